@@ -1,41 +1,53 @@
 package config
 
 import (
-	"flag"
 	"fmt"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/certs"
 	"github.com/JECSand/identity-service/pkg/constants"
 	"github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/probes"
+	"github.com/JECSand/identity-service/pkg/ratelimit"
+	"github.com/JECSand/identity-service/pkg/redis"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"os"
+	"time"
 )
 
-var configPath string
-
-func init() {
-	flag.StringVar(&configPath, "config", "", "API Gateway service config path")
-}
-
 // Config structures the configuration for the api gateway service
 type Config struct {
-	ServiceName     string          `mapstructure:"serviceName"`
-	Logger          *logging.Config `mapstructure:"logger"`
-	KafkaTopics     KafkaTopics     `mapstructure:"kafkaTopics"`
-	Http            Http            `mapstructure:"http"`
-	Grpc            Grpc            `mapstructure:"grpc"`
-	Kafka           *kafka.Config   `mapstructure:"kafka"`
-	Probes          probes.Config   `mapstructure:"probes"`
-	ServiceSettings ServiceSettings `mapstructure:"serviceSettings"`
-	Jaeger          *tracing.Config `mapstructure:"jaeger"`
+	ServiceName     string                           `mapstructure:"serviceName"`
+	Logger          *logging.Config                  `mapstructure:"logger"`
+	KafkaTopics     KafkaTopics                      `mapstructure:"kafkaTopics"`
+	Http            Http                             `mapstructure:"http"`
+	Grpc            Grpc                             `mapstructure:"grpc"`
+	Kafka           *kafka.Config                    `mapstructure:"kafka"`
+	Probes          probes.Config                    `mapstructure:"probes"`
+	ServiceSettings ServiceSettings                  `mapstructure:"serviceSettings"`
+	Revocation      Revocation                       `mapstructure:"revocation"`
+	Tracing         *tracing.Config                  `mapstructure:"tracing"`
+	ServiceAuth     authentication.ServiceAuthConfig `mapstructure:"serviceAuth"`
+	Redis           *redis.Config                    `mapstructure:"redis"`
+	RateLimit       ratelimit.Config                 `mapstructure:"rateLimit"`
 }
 
 type ServiceSettings struct {
 	JWTSalt string `mapstructure:"jwtSalt"`
 }
 
+// Revocation configures local token verification against the in-memory revocation list
+type Revocation struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	MaxStaleness      time.Duration `mapstructure:"maxStaleness"`
+	ExpectedTokens    int           `mapstructure:"expectedTokens"`
+	FalsePositiveRate float64       `mapstructure:"falsePositiveRate"`
+	SyncInterval      time.Duration `mapstructure:"syncInterval"`
+	PruneInterval     time.Duration `mapstructure:"pruneInterval"`
+}
+
 type Http struct {
 	Port                string   `mapstructure:"port"`
 	Development         bool     `mapstructure:"development"`
@@ -43,7 +55,11 @@ type Http struct {
 	UsersPath           string   `mapstructure:"usersPath"`
 	GroupsPath          string   `mapstructure:"groupsPath"`
 	MembershipsPath     string   `mapstructure:"membershipsPath"`
+	OrganizationsPath   string   `mapstructure:"organizationsPath"`
+	RolesPath           string   `mapstructure:"rolesPath"`
+	AuthzPath           string   `mapstructure:"authzPath"`
 	AuthPath            string   `mapstructure:"authPath"`
+	OAuthPath           string   `mapstructure:"oauthPath"`
 	DebugHeaders        bool     `mapstructure:"debugHeaders"`
 	HttpClientDebug     bool     `mapstructure:"httpClientDebug"`
 	DebugErrorsResponse bool     `mapstructure:"debugErrorsResponse"`
@@ -51,24 +67,41 @@ type Http struct {
 }
 
 type Grpc struct {
-	QueryServicePort string `mapstructure:"queryServicePort"`
+	QueryServicePort   string       `mapstructure:"queryServicePort"`
+	CommandServicePort string       `mapstructure:"commandServicePort"`
+	CommandServerName  string       `mapstructure:"commandServerName"`
+	TLS                certs.Config `mapstructure:"tls"`
 }
 
 type KafkaTopics struct {
-	UserCreate       kafka.TopicConfig `mapstructure:"userCreate"`
-	UserUpdate       kafka.TopicConfig `mapstructure:"userUpdate"`
-	UserDelete       kafka.TopicConfig `mapstructure:"userDelete"`
-	GroupCreate      kafka.TopicConfig `mapstructure:"groupCreate"`
-	GroupUpdate      kafka.TopicConfig `mapstructure:"groupUpdate"`
-	GroupDelete      kafka.TopicConfig `mapstructure:"groupDelete"`
-	MembershipCreate kafka.TopicConfig `mapstructure:"membershipCreate"`
-	MembershipUpdate kafka.TopicConfig `mapstructure:"membershipUpdate"`
-	MembershipDelete kafka.TopicConfig `mapstructure:"membershipDelete"`
-	TokenBlacklist   kafka.TopicConfig `mapstructure:"tokenBlacklist"`
-	PasswordUpdate   kafka.TopicConfig `mapstructure:"passwordUpdate"`
+	UserCreate                kafka.TopicConfig `mapstructure:"userCreate"`
+	UserUpdate                kafka.TopicConfig `mapstructure:"userUpdate"`
+	UserDelete                kafka.TopicConfig `mapstructure:"userDelete"`
+	GroupCreate               kafka.TopicConfig `mapstructure:"groupCreate"`
+	GroupUpdate               kafka.TopicConfig `mapstructure:"groupUpdate"`
+	GroupDelete               kafka.TopicConfig `mapstructure:"groupDelete"`
+	MembershipCreate          kafka.TopicConfig `mapstructure:"membershipCreate"`
+	MembershipUpdate          kafka.TopicConfig `mapstructure:"membershipUpdate"`
+	MembershipDelete          kafka.TopicConfig `mapstructure:"membershipDelete"`
+	MembershipExtend          kafka.TopicConfig `mapstructure:"membershipExtend"`
+	TokenBlacklist            kafka.TopicConfig `mapstructure:"tokenBlacklist"`
+	PasswordUpdate            kafka.TopicConfig `mapstructure:"passwordUpdate"`
+	TokenBlacklisted          kafka.TopicConfig `mapstructure:"tokenBlacklisted"`
+	UserStatusChanged         kafka.TopicConfig `mapstructure:"userStatusChanged"`
+	UserDeleted               kafka.TopicConfig `mapstructure:"userDeleted"`
+	OrganizationCreate        kafka.TopicConfig `mapstructure:"organizationCreate"`
+	OrganizationUpdate        kafka.TopicConfig `mapstructure:"organizationUpdate"`
+	OrganizationStatusChange  kafka.TopicConfig `mapstructure:"organizationStatusChange"`
+	OrganizationDelete        kafka.TopicConfig `mapstructure:"organizationDelete"`
+	OrganizationStatusChanged kafka.TopicConfig `mapstructure:"organizationStatusChanged"`
+	OrganizationDeleted       kafka.TopicConfig `mapstructure:"organizationDeleted"`
+	RoleCreate                kafka.TopicConfig `mapstructure:"roleCreate"`
+	RoleUpdate                kafka.TopicConfig `mapstructure:"roleUpdate"`
+	RoleDelete                kafka.TopicConfig `mapstructure:"roleDelete"`
 }
 
-func InitConfig() (*Config, error) {
+// InitConfig loads the config file at configPath, falling back to the CONFIG_PATH env and the working directory default
+func InitConfig(configPath string) (*Config, error) {
 	if configPath == "" {
 		configPathFromEnv := os.Getenv(constants.ConfigPath)
 		if configPathFromEnv != "" {
@@ -82,12 +115,13 @@ func InitConfig() (*Config, error) {
 		}
 	}
 	cfg := &Config{}
-	viper.SetConfigType(constants.Yaml)
-	viper.SetConfigFile(configPath)
-	if err := viper.ReadInConfig(); err != nil {
+	v := viper.New()
+	v.SetConfigType(constants.Yaml)
+	v.SetConfigFile(configPath)
+	if err := v.ReadInConfig(); err != nil {
 		return nil, errors.Wrap(err, "viper.ReadInConfig")
 	}
-	if err := viper.Unmarshal(cfg); err != nil {
+	if err := v.Unmarshal(cfg); err != nil {
 		return nil, errors.Wrap(err, "viper.Unmarshal")
 	}
 	httpPort := os.Getenv(constants.HttpPort)
@@ -96,15 +130,23 @@ func InitConfig() (*Config, error) {
 	}
 	kafkaBrokers := os.Getenv(constants.KafkaBrokers)
 	if kafkaBrokers != "" {
-		cfg.Kafka.Brokers = []string{kafkaBrokers}
+		cfg.Kafka.Brokers = kafka.ParseBrokers(kafkaBrokers)
+	}
+	otlpEndpoint := os.Getenv(constants.OtlpEndpoint)
+	if otlpEndpoint != "" && cfg.Tracing != nil {
+		cfg.Tracing.Endpoint = otlpEndpoint
 	}
-	jaegerAddr := os.Getenv(constants.JaegerHostPort)
-	if jaegerAddr != "" {
-		cfg.Jaeger.HostPort = jaegerAddr
+	redisAddr := os.Getenv(constants.RedisAddr)
+	if redisAddr != "" && cfg.Redis != nil {
+		cfg.Redis.Addr = redisAddr
 	}
 	queryServicePort := os.Getenv(constants.QueryServicePort)
 	if queryServicePort != "" {
 		cfg.Grpc.QueryServicePort = queryServicePort
 	}
+	commandServicePort := os.Getenv(constants.CommandServicePort)
+	if commandServicePort != "" {
+		cfg.Grpc.CommandServicePort = commandServicePort
+	}
 	return cfg, nil
 }
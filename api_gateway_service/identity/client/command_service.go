@@ -0,0 +1,19 @@
+package client
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/pkg/interceptors"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"google.golang.org/grpc"
+)
+
+// NewCommandServiceClient constructs and return a new gRPC client connection to the command service, extra dial
+// options are applied last; with TLS the command service is verified as CommandServerName
+func NewCommandServiceClient(ctx context.Context, log logging.Logger, cfg *config.Config, im interceptors.InterceptorManager, extra ...grpc.DialOption) (*grpc.ClientConn, error) {
+	tls := cfg.Grpc.TLS
+	if cfg.Grpc.CommandServerName != "" {
+		tls.ServerName = cfg.Grpc.CommandServerName
+	}
+	return dial(ctx, log, cfg.Grpc.CommandServicePort, &tls, im, extra...)
+}
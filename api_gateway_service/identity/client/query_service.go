@@ -3,9 +3,12 @@ package client
 import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/pkg/certs"
 	"github.com/JECSand/identity-service/pkg/interceptors"
+	"github.com/JECSand/identity-service/pkg/logging"
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"time"
@@ -16,22 +19,33 @@ const (
 	backoffRetries = 3
 )
 
-// NewQueryServiceClient constructs and return a new gRPC client connection to the query service
-func NewQueryServiceClient(ctx context.Context, cfg *config.Config, im interceptors.InterceptorManager) (*grpc.ClientConn, error) {
+// NewQueryServiceClient constructs and return a new gRPC client connection to the query service, extra dial
+// options are applied last
+func NewQueryServiceClient(ctx context.Context, log logging.Logger, cfg *config.Config, im interceptors.InterceptorManager, extra ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return dial(ctx, log, cfg.Grpc.QueryServicePort, &cfg.Grpc.TLS, im, extra...)
+}
+
+// dial connects to a backend service at target with retries, tracing and service authentication
+func dial(ctx context.Context, log logging.Logger, target string, tls *certs.Config, im interceptors.InterceptorManager, extra ...grpc.DialOption) (*grpc.ClientConn, error) {
 	opts := []grpc_retry.CallOption{
 		grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffLinear)),
 		grpc_retry.WithCodes(codes.NotFound, codes.Aborted),
 		grpc_retry.WithMax(backoffRetries),
 	}
-	queryClient, err := grpc.DialContext(
-		ctx,
-		cfg.Grpc.QueryServicePort,
+	creds, err := certs.DialOption(ctx, log, tls)
+	if err != nil {
+		return nil, errors.Wrap(err, "certs.DialOption")
+	}
+	dialOpts := append([]grpc.DialOption{
 		grpc.WithUnaryInterceptor(im.ClientRequestLoggerInterceptor()),
-		grpc.WithInsecure(),
+		creds,
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(opts...)),
-	)
+		grpc.WithChainUnaryInterceptor(im.ClientServiceAuthInterceptor()),
+	}, extra...)
+	conn, err := grpc.DialContext(ctx, target, dialOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "grpc.DialContext")
 	}
-	return queryClient, nil
+	return conn, nil
 }
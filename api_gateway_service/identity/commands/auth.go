@@ -7,12 +7,14 @@ import (
 type AuthCommands struct {
 	BlacklistToken BlacklistTokenCmdHandler
 	UpdatePassword UpdatePasswordCmdHandler
+	RevokeToken    RevokeTokenCmdHandler
 }
 
-func NewAuthCommands(blacklistToken BlacklistTokenCmdHandler, updatePass UpdatePasswordCmdHandler) *AuthCommands {
+func NewAuthCommands(blacklistToken BlacklistTokenCmdHandler, updatePass UpdatePasswordCmdHandler, revokeToken RevokeTokenCmdHandler) *AuthCommands {
 	return &AuthCommands{
 		BlacklistToken: blacklistToken,
 		UpdatePassword: updatePass,
+		RevokeToken:    revokeToken,
 	}
 }
 
@@ -25,6 +27,15 @@ func NewBlacklistTokenCommand(blacklistDto *dto.BlacklistTokenDTO) *BlacklistTok
 	return &BlacklistTokenCommand{BlacklistDto: blacklistDto}
 }
 
+// RevokeTokenCommand ...
+type RevokeTokenCommand struct {
+	BlacklistDto *dto.BlacklistTokenDTO
+}
+
+func NewRevokeTokenCommand(blacklistDto *dto.BlacklistTokenDTO) *RevokeTokenCommand {
+	return &RevokeTokenCommand{BlacklistDto: blacklistDto}
+}
+
 // UpdatePasswordCommand ...
 type UpdatePasswordCommand struct {
 	UpdateDto *dto.UpdatePasswordDTO
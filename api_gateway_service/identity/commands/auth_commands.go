@@ -5,12 +5,11 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
-	"time"
+	authQueryService "github.com/JECSand/identity-service/query_service/protos/auth_query"
+	"github.com/pkg/errors"
 )
 
 type BlacklistTokenCmdHandler interface {
@@ -18,36 +17,31 @@ type BlacklistTokenCmdHandler interface {
 }
 
 type blacklistTokenHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewBlacklistTokenHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *blacklistTokenHandler {
+func NewBlacklistTokenHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *blacklistTokenHandler {
 	return &blacklistTokenHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
 	}
 }
 
 func (c *blacklistTokenHandler) Handle(ctx context.Context, command *BlacklistTokenCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistTokenHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "blacklistTokenHandler.Handle")
+	defer span.End()
 	blacklistDTO := &kafkaMessages.TokenBlacklist{
 		ID:          command.BlacklistDto.ID.String(),
 		AccessToken: command.BlacklistDto.AccessToken,
 	}
-	dtoBytes, err := proto.Marshal(blacklistDTO)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.TokenBlacklist.TopicName, kafkaClient.TokenBlacklistEvent, blacklistDTO.GetID(), blacklistDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.TokenBlacklist.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
 }
 
 // UpdatePasswordCmdHandler ...
@@ -56,35 +50,77 @@ type UpdatePasswordCmdHandler interface {
 }
 
 type updatePasswordCmdHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewUpdatePasswordHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *updatePasswordCmdHandler {
+func NewUpdatePasswordHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *updatePasswordCmdHandler {
 	return &updatePasswordCmdHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
 	}
 }
 
 func (c *updatePasswordCmdHandler) Handle(ctx context.Context, command *UpdatePasswordCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updatePasswordCmdHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updatePasswordCmdHandler.Handle")
+	defer span.End()
 	updateDTO := &kafkaMessages.PasswordUpdate{
 		ID:              command.UpdateDto.ID.String(),
 		CurrentPassword: command.UpdateDto.CurrentPassword,
 		NewPassword:     command.UpdateDto.NewPassword,
 	}
-	dtoBytes, err := proto.Marshal(updateDTO)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.PasswordUpdate.TopicName, kafkaClient.PasswordUpdateEvent, updateDTO.GetID(), updateDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.PasswordUpdate.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
+	return c.publisher.Publish(ctx, message)
+}
+
+// RevokeTokenCmdHandler ...
+type RevokeTokenCmdHandler interface {
+	Handle(ctx context.Context, command *RevokeTokenCommand) error
+}
+
+type revokeTokenCmdHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+	rsClient  authQueryService.AuthQueryServiceClient
+}
+
+func NewRevokeTokenHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, rsClient authQueryService.AuthQueryServiceClient) *revokeTokenCmdHandler {
+	return &revokeTokenCmdHandler{
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
+		rsClient:  rsClient,
+	}
+}
+
+// Handle blacklists the token in the read model synchronously, then publishes the blacklist command
+// so the write model and every gateway revocation list record it as well
+func (c *revokeTokenCmdHandler) Handle(ctx context.Context, command *RevokeTokenCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "revokeTokenCmdHandler.Handle")
+	defer span.End()
+	res, err := c.rsClient.BlacklistToken(ctx, &authQueryService.BlacklistTokenReq{
+		ID:          command.BlacklistDto.ID.String(),
+		AccessToken: command.BlacklistDto.AccessToken,
 	})
+	if err != nil {
+		return err
+	}
+	if res.GetStatus() != 200 {
+		return errors.Errorf("BlacklistToken returned status %d", res.GetStatus())
+	}
+	blacklistDTO := &kafkaMessages.TokenBlacklist{
+		ID:          command.BlacklistDto.ID.String(),
+		AccessToken: command.BlacklistDto.AccessToken,
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.TokenBlacklist.TopicName, kafkaClient.TokenBlacklistEvent, blacklistDTO.GetID(), blacklistDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
 }
@@ -6,16 +6,18 @@ import (
 )
 
 type GroupCommands struct {
-	CreateGroup CreateGroupCmdHandler
-	UpdateGroup UpdateGroupCmdHandler
-	DeleteGroup DeleteGroupCmdHandler
+	CreateGroup    CreateGroupCmdHandler
+	UpdateGroup    UpdateGroupCmdHandler
+	DeleteGroup    DeleteGroupCmdHandler
+	SetGroupParent SetGroupParentCmdHandler
 }
 
-func NewGroupCommands(create CreateGroupCmdHandler, update UpdateGroupCmdHandler, delete DeleteGroupCmdHandler) *GroupCommands {
+func NewGroupCommands(create CreateGroupCmdHandler, update UpdateGroupCmdHandler, delete DeleteGroupCmdHandler, setParent SetGroupParentCmdHandler) *GroupCommands {
 	return &GroupCommands{
-		CreateGroup: create,
-		UpdateGroup: update,
-		DeleteGroup: delete,
+		CreateGroup:    create,
+		UpdateGroup:    update,
+		DeleteGroup:    delete,
+		SetGroupParent: setParent,
 	}
 }
 
@@ -45,3 +47,12 @@ type DeleteGroupCommand struct {
 func NewDeleteGroupCommand(groupID uuid.UUID) *DeleteGroupCommand {
 	return &DeleteGroupCommand{ID: groupID}
 }
+
+// SetGroupParentCommand ...
+type SetGroupParentCommand struct {
+	SetParentDto *dto.SetGroupParentDTO
+}
+
+func NewSetGroupParentCommand(setParentDto *dto.SetGroupParentDTO) *SetGroupParentCommand {
+	return &SetGroupParentCommand{SetParentDto: setParentDto}
+}
@@ -3,14 +3,15 @@ package commands
 import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	groupCommandService "github.com/JECSand/identity-service/command_service/protos/group_command"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
-	"time"
+	"github.com/gofrs/uuid"
 )
 
 type CreateGroupCmdHandler interface {
@@ -18,39 +19,44 @@ type CreateGroupCmdHandler interface {
 }
 
 type createGroupHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewCreateGroupHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *createGroupHandler {
+func NewCreateGroupHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *createGroupHandler {
 	return &createGroupHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
 	}
 }
 
 func (c *createGroupHandler) Handle(ctx context.Context, command *CreateGroupCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createGroupHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createGroupHandler.Handle")
+	defer span.End()
+	tenantID := authentication.TenantFromContext(ctx)
+	organizationID := authentication.OrganizationOrDefault(tenantID)
+	if tenantID == "" {
+		organizationID = command.CreateDto.OrganizationID.String()
+	}
 	createDTO := &kafkaMessages.GroupCreate{
-		ID:          command.CreateDto.ID.String(),
-		Name:        command.CreateDto.Name,
-		Description: command.CreateDto.Description,
-		CreatorID:   command.CreateDto.CreatorID.String(),
-		Active:      true,
+		ID:             command.CreateDto.ID.String(),
+		Name:           command.CreateDto.Name,
+		Description:    command.CreateDto.Description,
+		CreatorID:      command.CreateDto.CreatorID.String(),
+		Active:         true,
+		OrganizationID: organizationID,
+		TenantID:       tenantID,
+	}
+	if command.CreateDto.ParentID != uuid.Nil {
+		createDTO.ParentID = command.CreateDto.ParentID.String()
 	}
-	dtoBytes, err := proto.Marshal(createDTO)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.GroupCreate.TopicName, kafkaClient.GroupCreateEvent, createDTO.GetID(), createDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.GroupCreate.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
 }
 
 // UpdateGroupCmdHandler ...
@@ -59,37 +65,33 @@ type UpdateGroupCmdHandler interface {
 }
 
 type updateGroupCmdHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewUpdateGroupHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *updateGroupCmdHandler {
+func NewUpdateGroupHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *updateGroupCmdHandler {
 	return &updateGroupCmdHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
 	}
 }
 
 func (c *updateGroupCmdHandler) Handle(ctx context.Context, command *UpdateGroupCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateGroupCmdHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updateGroupCmdHandler.Handle")
+	defer span.End()
 	updateDTO := &kafkaMessages.GroupUpdate{
 		ID:          command.UpdateDto.ID.String(),
 		Name:        command.UpdateDto.Name,
 		Description: command.UpdateDto.Description,
+		TenantID:    authentication.TenantFromContext(ctx),
 	}
-	dtoBytes, err := proto.Marshal(updateDTO)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.GroupUpdate.TopicName, kafkaClient.GroupUpdateEvent, updateDTO.GetID(), updateDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.GroupUpdate.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
 }
 
 // DeleteGroupCmdHandler ...
@@ -98,27 +100,55 @@ type DeleteGroupCmdHandler interface {
 }
 
 type deleteGroupHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewDeleteGroupHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *deleteGroupHandler {
-	return &deleteGroupHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer}
+func NewDeleteGroupHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *deleteGroupHandler {
+	return &deleteGroupHandler{log: log, cfg: cfg, publisher: publisher}
 }
 
 func (c *deleteGroupHandler) Handle(ctx context.Context, command *DeleteGroupCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteGroupHandler.Handle")
-	defer span.Finish()
-	deleteDTO := &kafkaMessages.GroupDelete{ID: command.ID.String()}
-	dtoBytes, err := proto.Marshal(deleteDTO)
+	ctx, span := tracing.StartSpan(ctx, "deleteGroupHandler.Handle")
+	defer span.End()
+	deleteDTO := &kafkaMessages.GroupDelete{ID: command.ID.String(), TenantID: authentication.TenantFromContext(ctx)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.GroupDelete.TopicName, kafkaClient.GroupDeleteEvent, deleteDTO.GetID(), deleteDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.GroupDelete.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
+}
+
+// SetGroupParentCmdHandler ...
+type SetGroupParentCmdHandler interface {
+	Handle(ctx context.Context, command *SetGroupParentCommand) (*dto.GroupResponse, error)
+}
+
+type setGroupParentHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	csClient groupCommandService.GroupCommandServiceClient
+}
+
+func NewSetGroupParentHandler(log logging.Logger, cfg *config.Config, csClient groupCommandService.GroupCommandServiceClient) *setGroupParentHandler {
+	return &setGroupParentHandler{log: log, cfg: cfg, csClient: csClient}
+}
+
+// Handle moves the group synchronously in the command service, so that a hierarchy cycle is reported to the caller
+func (c *setGroupParentHandler) Handle(ctx context.Context, command *SetGroupParentCommand) (*dto.GroupResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "setGroupParentHandler.Handle")
+	defer span.End()
+	req := &groupCommandService.SetGroupParentReq{
+		ID:       command.SetParentDto.ID.String(),
+		TenantID: authentication.TenantFromContext(ctx),
+	}
+	if command.SetParentDto.ParentID != uuid.Nil {
+		req.ParentID = command.SetParentDto.ParentID.String()
+	}
+	res, err := c.csClient.SetGroupParent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return dto.GroupResponseFromCommandGrpc(res.GetGroup()), nil
 }
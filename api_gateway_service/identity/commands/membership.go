@@ -9,13 +9,15 @@ type MembershipCommands struct {
 	CreateMembership CreateMembershipCmdHandler
 	UpdateMembership UpdateMembershipCmdHandler
 	DeleteMembership DeleteMembershipCmdHandler
+	ExtendMembership ExtendMembershipCmdHandler
 }
 
-func NewMembershipCommands(create CreateMembershipCmdHandler, update UpdateMembershipCmdHandler, delete DeleteMembershipCmdHandler) *MembershipCommands {
+func NewMembershipCommands(create CreateMembershipCmdHandler, update UpdateMembershipCmdHandler, delete DeleteMembershipCmdHandler, extend ExtendMembershipCmdHandler) *MembershipCommands {
 	return &MembershipCommands{
 		CreateMembership: create,
 		UpdateMembership: update,
 		DeleteMembership: delete,
+		ExtendMembership: extend,
 	}
 }
 
@@ -45,3 +47,12 @@ type DeleteMembershipCommand struct {
 func NewDeleteMembershipCommand(membershipID uuid.UUID) *DeleteMembershipCommand {
 	return &DeleteMembershipCommand{ID: membershipID}
 }
+
+// ExtendMembershipCommand ...
+type ExtendMembershipCommand struct {
+	ExtendDto *dto.ExtendMembershipDTO
+}
+
+func NewExtendMembershipCommand(extendDto *dto.ExtendMembershipDTO) *ExtendMembershipCommand {
+	return &ExtendMembershipCommand{ExtendDto: extendDto}
+}
@@ -3,13 +3,14 @@ package commands
 import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"time"
 )
 
@@ -18,39 +19,58 @@ type CreateMembershipCmdHandler interface {
 }
 
 type createMembershipHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewCreateMembershipHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *createMembershipHandler {
+func NewCreateMembershipHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *createMembershipHandler {
 	return &createMembershipHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
 	}
 }
 
 func (c *createMembershipHandler) Handle(ctx context.Context, command *CreateMembershipCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createMembershipHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createMembershipHandler.Handle")
+	defer span.End()
+	var validFrom, validUntil time.Time
+	if command.CreateDto.ValidFrom != nil {
+		validFrom = *command.CreateDto.ValidFrom
+	}
+	if command.CreateDto.ValidUntil != nil {
+		validUntil = *command.CreateDto.ValidUntil
+	}
+	now := time.Now()
+	if err := authentication.CheckMembershipWindow(validFrom, validUntil, now); err != nil {
+		return err
+	}
+	if err := authentication.CheckMembershipStart(command.CreateDto.Status, validFrom, now); err != nil {
+		return err
+	}
 	createDTO := &kafkaMessages.MembershipCreate{
-		ID:      command.CreateDto.ID.String(),
-		UserID:  command.CreateDto.UserID.String(),
-		GroupID: command.CreateDto.GroupID.String(),
-		Status:  int64(command.CreateDto.Status),
-		Role:    int64(command.CreateDto.Role),
+		ID:       command.CreateDto.ID.String(),
+		UserID:   command.CreateDto.UserID.String(),
+		GroupID:  command.CreateDto.GroupID.String(),
+		Status:   int64(command.CreateDto.Status),
+		Role:     int64(command.CreateDto.Role),
+		TenantID: authentication.TenantFromContext(ctx),
 	}
-	dtoBytes, err := proto.Marshal(createDTO)
+	if command.CreateDto.RoleID != uuid.Nil {
+		createDTO.RoleID = command.CreateDto.RoleID.String()
+	}
+	if !validFrom.IsZero() {
+		createDTO.ValidFrom = timestamppb.New(validFrom)
+	}
+	if !validUntil.IsZero() {
+		createDTO.ValidUntil = timestamppb.New(validUntil)
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipCreate.TopicName, kafkaClient.MembershipCreateEvent, createDTO.GetID(), createDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.MembershipCreate.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
 }
 
 // UpdateMembershipCmdHandler ...
@@ -59,37 +79,36 @@ type UpdateMembershipCmdHandler interface {
 }
 
 type updateMembershipCmdHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewUpdateMembershipHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *updateMembershipCmdHandler {
+func NewUpdateMembershipHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *updateMembershipCmdHandler {
 	return &updateMembershipCmdHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
 	}
 }
 
 func (c *updateMembershipCmdHandler) Handle(ctx context.Context, command *UpdateMembershipCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateMembershipCmdHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updateMembershipCmdHandler.Handle")
+	defer span.End()
 	updateDTO := &kafkaMessages.MembershipUpdate{
-		ID:     command.UpdateDto.ID.String(),
-		Status: int64(command.UpdateDto.Status),
-		Role:   int64(command.UpdateDto.Role),
+		ID:       command.UpdateDto.ID.String(),
+		Status:   int64(command.UpdateDto.Status),
+		Role:     int64(command.UpdateDto.Role),
+		TenantID: authentication.TenantFromContext(ctx),
 	}
-	dtoBytes, err := proto.Marshal(updateDTO)
+	if command.UpdateDto.RoleID != uuid.Nil {
+		updateDTO.RoleID = command.UpdateDto.RoleID.String()
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipUpdate.TopicName, kafkaClient.MembershipUpdateEvent, updateDTO.GetID(), updateDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.MembershipUpdate.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
 }
 
 // DeleteMembershipCmdHandler ...
@@ -98,27 +117,59 @@ type DeleteMembershipCmdHandler interface {
 }
 
 type deleteMembershipHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewDeleteMembershipHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *deleteMembershipHandler {
-	return &deleteMembershipHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer}
+func NewDeleteMembershipHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *deleteMembershipHandler {
+	return &deleteMembershipHandler{log: log, cfg: cfg, publisher: publisher}
 }
 
 func (c *deleteMembershipHandler) Handle(ctx context.Context, command *DeleteMembershipCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteMembershipHandler.Handle")
-	defer span.Finish()
-	deleteDTO := &kafkaMessages.MembershipDelete{ID: command.ID.String()}
-	dtoBytes, err := proto.Marshal(deleteDTO)
+	ctx, span := tracing.StartSpan(ctx, "deleteMembershipHandler.Handle")
+	defer span.End()
+	deleteDTO := &kafkaMessages.MembershipDelete{ID: command.ID.String(), TenantID: authentication.TenantFromContext(ctx)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipDelete.TopicName, kafkaClient.MembershipDeleteEvent, deleteDTO.GetID(), deleteDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// ExtendMembershipCmdHandler ...
+type ExtendMembershipCmdHandler interface {
+	Handle(ctx context.Context, command *ExtendMembershipCommand) error
+}
+
+type extendMembershipHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewExtendMembershipHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *extendMembershipHandler {
+	return &extendMembershipHandler{log: log, cfg: cfg, publisher: publisher}
+}
+
+// Handle rejects a new end of the validity window that has already passed before publishing the extension;
+// the command service checks it against the start of the window and audits the change
+func (c *extendMembershipHandler) Handle(ctx context.Context, command *ExtendMembershipCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "extendMembershipHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckMembershipWindow(time.Time{}, command.ExtendDto.ValidUntil, time.Now()); err != nil {
+		return err
+	}
+	extendDTO := &kafkaMessages.MembershipExtend{
+		ID:         command.ExtendDto.ID.String(),
+		ValidUntil: timestamppb.New(command.ExtendDto.ValidUntil),
+		Reason:     command.ExtendDto.Reason,
+		ActorID:    kafkaClient.EventMetadataFromContext(ctx).Actor,
+		TenantID:   authentication.TenantFromContext(ctx),
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipExtend.TopicName, kafkaClient.MembershipExtendEvent, extendDTO.GetID(), extendDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.MembershipDelete.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
 }
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/gofrs/uuid"
+)
+
+type OrganizationCommands struct {
+	CreateOrganization       CreateOrganizationCmdHandler
+	UpdateOrganization       UpdateOrganizationCmdHandler
+	ChangeOrganizationStatus ChangeOrganizationStatusCmdHandler
+	DeleteOrganization       DeleteOrganizationCmdHandler
+}
+
+func NewOrganizationCommands(
+	create CreateOrganizationCmdHandler,
+	update UpdateOrganizationCmdHandler,
+	changeStatus ChangeOrganizationStatusCmdHandler,
+	delete DeleteOrganizationCmdHandler,
+) *OrganizationCommands {
+	return &OrganizationCommands{
+		CreateOrganization:       create,
+		UpdateOrganization:       update,
+		ChangeOrganizationStatus: changeStatus,
+		DeleteOrganization:       delete,
+	}
+}
+
+// CreateOrganizationCommand ...
+type CreateOrganizationCommand struct {
+	CreateDto *dto.CreateOrganizationDTO
+}
+
+func NewCreateOrganizationCommand(createDto *dto.CreateOrganizationDTO) *CreateOrganizationCommand {
+	return &CreateOrganizationCommand{CreateDto: createDto}
+}
+
+// UpdateOrganizationCommand ...
+type UpdateOrganizationCommand struct {
+	UpdateDto *dto.UpdateOrganizationDTO
+}
+
+func NewUpdateOrganizationCommand(updateDto *dto.UpdateOrganizationDTO) *UpdateOrganizationCommand {
+	return &UpdateOrganizationCommand{UpdateDto: updateDto}
+}
+
+// ChangeOrganizationStatusCommand suspends or reactivates an organization
+type ChangeOrganizationStatusCommand struct {
+	ID     uuid.UUID `json:"id" validate:"required"`
+	Active bool      `json:"active"`
+}
+
+func NewChangeOrganizationStatusCommand(organizationID uuid.UUID, active bool) *ChangeOrganizationStatusCommand {
+	return &ChangeOrganizationStatusCommand{ID: organizationID, Active: active}
+}
+
+// DeleteOrganizationCommand ...
+type DeleteOrganizationCommand struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+func NewDeleteOrganizationCommand(organizationID uuid.UUID) *DeleteOrganizationCommand {
+	return &DeleteOrganizationCommand{ID: organizationID}
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+)
+
+type CreateOrganizationCmdHandler interface {
+	Handle(ctx context.Context, command *CreateOrganizationCommand) error
+}
+
+type createOrganizationHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewCreateOrganizationHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *createOrganizationHandler {
+	return &createOrganizationHandler{
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
+	}
+}
+
+func (c *createOrganizationHandler) Handle(ctx context.Context, command *CreateOrganizationCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "createOrganizationHandler.Handle")
+	defer span.End()
+	createDTO := &kafkaMessages.OrganizationCreate{
+		ID:          command.CreateDto.ID.String(),
+		Name:        command.CreateDto.Name,
+		Description: command.CreateDto.Description,
+		TenantID:    authentication.TenantFromContext(ctx),
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationCreate.TopicName, kafkaClient.OrganizationCreateEvent, createDTO.GetID(), createDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// UpdateOrganizationCmdHandler ...
+type UpdateOrganizationCmdHandler interface {
+	Handle(ctx context.Context, command *UpdateOrganizationCommand) error
+}
+
+type updateOrganizationCmdHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewUpdateOrganizationHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *updateOrganizationCmdHandler {
+	return &updateOrganizationCmdHandler{
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
+	}
+}
+
+func (c *updateOrganizationCmdHandler) Handle(ctx context.Context, command *UpdateOrganizationCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "updateOrganizationCmdHandler.Handle")
+	defer span.End()
+	updateDTO := &kafkaMessages.OrganizationUpdate{
+		ID:          command.UpdateDto.ID.String(),
+		Name:        command.UpdateDto.Name,
+		Description: command.UpdateDto.Description,
+		TenantID:    authentication.TenantFromContext(ctx),
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationUpdate.TopicName, kafkaClient.OrganizationUpdateEvent, updateDTO.GetID(), updateDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// ChangeOrganizationStatusCmdHandler ...
+type ChangeOrganizationStatusCmdHandler interface {
+	Handle(ctx context.Context, command *ChangeOrganizationStatusCommand) error
+}
+
+type changeOrganizationStatusHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewChangeOrganizationStatusHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *changeOrganizationStatusHandler {
+	return &changeOrganizationStatusHandler{log: log, cfg: cfg, publisher: publisher}
+}
+
+func (c *changeOrganizationStatusHandler) Handle(ctx context.Context, command *ChangeOrganizationStatusCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "changeOrganizationStatusHandler.Handle")
+	defer span.End()
+	statusDTO := &kafkaMessages.OrganizationStatusChange{
+		ID:       command.ID.String(),
+		Active:   command.Active,
+		TenantID: authentication.TenantFromContext(ctx),
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationStatusChange.TopicName, kafkaClient.OrganizationStatusChangeEvent, statusDTO.GetID(), statusDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// DeleteOrganizationCmdHandler ...
+type DeleteOrganizationCmdHandler interface {
+	Handle(ctx context.Context, command *DeleteOrganizationCommand) error
+}
+
+type deleteOrganizationHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewDeleteOrganizationHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *deleteOrganizationHandler {
+	return &deleteOrganizationHandler{log: log, cfg: cfg, publisher: publisher}
+}
+
+func (c *deleteOrganizationHandler) Handle(ctx context.Context, command *DeleteOrganizationCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "deleteOrganizationHandler.Handle")
+	defer span.End()
+	deleteDTO := &kafkaMessages.OrganizationDelete{ID: command.ID.String(), TenantID: authentication.TenantFromContext(ctx)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationDelete.TopicName, kafkaClient.OrganizationDeleteEvent, deleteDTO.GetID(), deleteDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/gofrs/uuid"
+)
+
+type RoleCommands struct {
+	CreateRole CreateRoleCmdHandler
+	UpdateRole UpdateRoleCmdHandler
+	DeleteRole DeleteRoleCmdHandler
+}
+
+func NewRoleCommands(
+	create CreateRoleCmdHandler,
+	update UpdateRoleCmdHandler,
+	delete DeleteRoleCmdHandler,
+) *RoleCommands {
+	return &RoleCommands{
+		CreateRole: create,
+		UpdateRole: update,
+		DeleteRole: delete,
+	}
+}
+
+// CreateRoleCommand ...
+type CreateRoleCommand struct {
+	CreateDto *dto.CreateRoleDTO
+}
+
+func NewCreateRoleCommand(createDto *dto.CreateRoleDTO) *CreateRoleCommand {
+	return &CreateRoleCommand{CreateDto: createDto}
+}
+
+// UpdateRoleCommand ...
+type UpdateRoleCommand struct {
+	UpdateDto *dto.UpdateRoleDTO
+}
+
+func NewUpdateRoleCommand(updateDto *dto.UpdateRoleDTO) *UpdateRoleCommand {
+	return &UpdateRoleCommand{UpdateDto: updateDto}
+}
+
+// DeleteRoleCommand ...
+type DeleteRoleCommand struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+func NewDeleteRoleCommand(roleID uuid.UUID) *DeleteRoleCommand {
+	return &DeleteRoleCommand{ID: roleID}
+}
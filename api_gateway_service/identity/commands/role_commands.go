@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"github.com/gofrs/uuid"
+)
+
+type CreateRoleCmdHandler interface {
+	Handle(ctx context.Context, command *CreateRoleCommand) error
+}
+
+type createRoleHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewCreateRoleHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *createRoleHandler {
+	return &createRoleHandler{
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
+	}
+}
+
+func (c *createRoleHandler) Handle(ctx context.Context, command *CreateRoleCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "createRoleHandler.Handle")
+	defer span.End()
+	createDTO := &kafkaMessages.RoleCreate{
+		ID:          command.CreateDto.ID.String(),
+		Name:        command.CreateDto.Name,
+		Description: command.CreateDto.Description,
+		Permissions: command.CreateDto.Permissions,
+		TenantID:    authentication.TenantFromContext(ctx),
+	}
+	if command.CreateDto.GroupID != uuid.Nil {
+		createDTO.GroupID = command.CreateDto.GroupID.String()
+	}
+	if command.CreateDto.OrganizationID != uuid.Nil {
+		createDTO.OrganizationID = command.CreateDto.OrganizationID.String()
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.RoleCreate.TopicName, kafkaClient.RoleCreateEvent, createDTO.GetID(), createDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// UpdateRoleCmdHandler ...
+type UpdateRoleCmdHandler interface {
+	Handle(ctx context.Context, command *UpdateRoleCommand) error
+}
+
+type updateRoleCmdHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewUpdateRoleHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *updateRoleCmdHandler {
+	return &updateRoleCmdHandler{
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
+	}
+}
+
+func (c *updateRoleCmdHandler) Handle(ctx context.Context, command *UpdateRoleCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "updateRoleCmdHandler.Handle")
+	defer span.End()
+	updateDTO := &kafkaMessages.RoleUpdate{
+		ID:          command.UpdateDto.ID.String(),
+		Name:        command.UpdateDto.Name,
+		Description: command.UpdateDto.Description,
+		Permissions: command.UpdateDto.Permissions,
+		TenantID:    authentication.TenantFromContext(ctx),
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.RoleUpdate.TopicName, kafkaClient.RoleUpdateEvent, updateDTO.GetID(), updateDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// DeleteRoleCmdHandler ...
+type DeleteRoleCmdHandler interface {
+	Handle(ctx context.Context, command *DeleteRoleCommand) error
+}
+
+type deleteRoleHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+}
+
+func NewDeleteRoleHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *deleteRoleHandler {
+	return &deleteRoleHandler{log: log, cfg: cfg, publisher: publisher}
+}
+
+func (c *deleteRoleHandler) Handle(ctx context.Context, command *DeleteRoleCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "deleteRoleHandler.Handle")
+	defer span.End()
+	deleteDTO := &kafkaMessages.RoleDelete{ID: command.ID.String(), TenantID: authentication.TenantFromContext(ctx)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.RoleDelete.TopicName, kafkaClient.RoleDeleteEvent, deleteDTO.GetID(), deleteDTO, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
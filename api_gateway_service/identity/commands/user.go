@@ -2,20 +2,24 @@ package commands
 
 import (
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/gofrs/uuid"
+	"time"
 )
 
 type UserCommands struct {
-	CreateUser CreateUserCmdHandler
-	UpdateUser UpdateUserCmdHandler
-	DeleteUser DeleteUserCmdHandler
+	CreateUser       CreateUserCmdHandler
+	UpdateUser       UpdateUserCmdHandler
+	DeleteUser       DeleteUserCmdHandler
+	ChangeUserStatus ChangeUserStatusCmdHandler
 }
 
-func NewUserCommands(create CreateUserCmdHandler, update UpdateUserCmdHandler, delete DeleteUserCmdHandler) *UserCommands {
+func NewUserCommands(create CreateUserCmdHandler, update UpdateUserCmdHandler, delete DeleteUserCmdHandler, changeStatus ChangeUserStatusCmdHandler) *UserCommands {
 	return &UserCommands{
-		CreateUser: create,
-		UpdateUser: update,
-		DeleteUser: delete,
+		CreateUser:       create,
+		UpdateUser:       update,
+		DeleteUser:       delete,
+		ChangeUserStatus: changeStatus,
 	}
 }
 
@@ -45,3 +49,15 @@ type DeleteUserCommand struct {
 func NewDeleteUserCommand(userID uuid.UUID) *DeleteUserCommand {
 	return &DeleteUserCommand{ID: userID}
 }
+
+// ChangeUserStatusCommand ...
+type ChangeUserStatusCommand struct {
+	ID             uuid.UUID        `json:"id" validate:"required"`
+	Status         enums.UserStatus `json:"status" validate:"required"`
+	Reason         string           `json:"reason" validate:"lte=250"`
+	SuspendedUntil *time.Time       `json:"suspendedUntil"`
+}
+
+func NewChangeUserStatusCommand(userID uuid.UUID, status enums.UserStatus, reason string, suspendedUntil *time.Time) *ChangeUserStatusCommand {
+	return &ChangeUserStatusCommand{ID: userID, Status: status, Reason: reason, SuspendedUntil: suspendedUntil}
+}
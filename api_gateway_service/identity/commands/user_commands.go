@@ -3,14 +3,17 @@ package commands
 import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	commandService "github.com/JECSand/identity-service/command_service/protos/user_command"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
-	"time"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type CreateUserCmdHandler interface {
@@ -18,40 +21,59 @@ type CreateUserCmdHandler interface {
 }
 
 type createUserHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+	csClient  commandService.CommandServiceClient
 }
 
-func NewCreateUserHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *createUserHandler {
+func NewCreateUserHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, csClient commandService.CommandServiceClient) *createUserHandler {
 	return &createUserHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
+		csClient:  csClient,
 	}
 }
 
+// Handle reserves the email and username of the new user before publishing its creation, returning an
+// *authentication.IdentifierConflictError when either is taken
 func (c *createUserHandler) Handle(ctx context.Context, command *CreateUserCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createUserHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createUserHandler.Handle")
+	defer span.End()
+	if command.CreateDto.OrgAdmin && !authentication.AdminFromContext(ctx) {
+		return authentication.ErrOrgAdminRequired
+	}
+	tenantID := authentication.TenantFromContext(ctx)
+	organizationID := authentication.OrganizationOrDefault(tenantID)
+	if tenantID == "" {
+		organizationID = command.CreateDto.OrganizationID.String()
+	}
+	reserved, err := reserveIdentifiers(ctx, c.csClient, command.CreateDto.ID.String(), command.CreateDto.Email, command.CreateDto.Username)
+	if err != nil {
+		return err
+	}
+	status := enums.ACTIVATED
+	if command.CreateDto.Invite {
+		status = enums.INVITED
+	}
 	createDTO := &kafkaMessages.UserCreate{
-		ID:       command.CreateDto.ID.String(),
-		Email:    command.CreateDto.Email,
-		Username: command.CreateDto.Username,
-		Password: command.CreateDto.Password,
-		Root:     false,
-		Active:   true,
-	}
-	dtoBytes, err := proto.Marshal(createDTO)
+		ID:             command.CreateDto.ID.String(),
+		Email:          reserved.GetEmail(),
+		Username:       reserved.GetUsername(),
+		Password:       command.CreateDto.Password,
+		Root:           false,
+		Active:         status == enums.ACTIVATED,
+		Status:         status.Stringify(),
+		OrganizationID: organizationID,
+		OrgAdmin:       command.CreateDto.OrgAdmin,
+		TenantID:       tenantID,
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.UserCreate.TopicName, kafkaClient.UserCreateEvent, createDTO.GetID(), createDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.UserCreate.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
 }
 
 // UpdateUserCmdHandler ...
@@ -60,37 +82,65 @@ type UpdateUserCmdHandler interface {
 }
 
 type updateUserCmdHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
+	csClient  commandService.CommandServiceClient
 }
 
-func NewUpdateUserHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *updateUserCmdHandler {
+func NewUpdateUserHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, csClient commandService.CommandServiceClient) *updateUserCmdHandler {
 	return &updateUserCmdHandler{
-		log:           log,
-		cfg:           cfg,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		publisher: publisher,
+		csClient:  csClient,
 	}
 }
 
+// Handle reserves a changed email or username before publishing the update, returning an
+// *authentication.IdentifierConflictError when either is taken; users update themselves, admins the users of
+// their organization
 func (c *updateUserCmdHandler) Handle(ctx context.Context, command *UpdateUserCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateUserCmdHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updateUserCmdHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckSelfOrAdmin(ctx, command.UpdateDto.ID.String()); err != nil {
+		return err
+	}
 	updateDTO := &kafkaMessages.UserUpdate{
 		ID:       command.UpdateDto.ID.String(),
 		Username: command.UpdateDto.Username,
 		Email:    command.UpdateDto.Email,
+		TenantID: authentication.TenantFromContext(ctx),
 	}
-	dtoBytes, err := proto.Marshal(updateDTO)
+	if updateDTO.Email != "" || updateDTO.Username != "" {
+		reserved, err := reserveIdentifiers(ctx, c.csClient, updateDTO.ID, updateDTO.Email, updateDTO.Username)
+		if err != nil {
+			return err
+		}
+		updateDTO.Email, updateDTO.Username = reserved.GetEmail(), reserved.GetUsername()
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.UserUpdate.TopicName, kafkaClient.UserUpdateEvent, updateDTO.GetID(), updateDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.UserUpdate.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
+	return c.publisher.Publish(ctx, message)
+}
+
+// reserveIdentifiers reserves the email and username of user id in the command service, which serializes the
+// reservations against every other user; the response holds their normalized forms
+func reserveIdentifiers(ctx context.Context, csClient commandService.CommandServiceClient, id string, email string, username string) (*commandService.ReserveIdentifiersRes, error) {
+	res, err := csClient.ReserveIdentifiers(ctx, &commandService.ReserveIdentifiersReq{
+		ID:       id,
+		Email:    email,
+		Username: username,
 	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.GetConflicts()) > 0 {
+		return nil, &authentication.IdentifierConflictError{Identifiers: res.GetConflicts()}
+	}
+	return res, nil
 }
 
 // DeleteUserCmdHandler ...
@@ -99,27 +149,65 @@ type DeleteUserCmdHandler interface {
 }
 
 type deleteUserHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	publisher messaging.Publisher
 }
 
-func NewDeleteUserHandler(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *deleteUserHandler {
-	return &deleteUserHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer}
+func NewDeleteUserHandler(log logging.Logger, cfg *config.Config, publisher messaging.Publisher) *deleteUserHandler {
+	return &deleteUserHandler{log: log, cfg: cfg, publisher: publisher}
 }
 
+// Handle publishes the deletion of a user; users delete themselves, admins the users of their organization
 func (c *deleteUserHandler) Handle(ctx context.Context, command *DeleteUserCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteUserHandler.Handle")
-	defer span.Finish()
-	deleteDTO := &kafkaMessages.UserDelete{ID: command.ID.String()}
-	dtoBytes, err := proto.Marshal(deleteDTO)
+	ctx, span := tracing.StartSpan(ctx, "deleteUserHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckSelfOrAdmin(ctx, command.ID.String()); err != nil {
+		return err
+	}
+	deleteDTO := &kafkaMessages.UserDelete{ID: command.ID.String(), TenantID: authentication.TenantFromContext(ctx)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.UserDelete.TopicName, kafkaClient.UserDeleteEvent, deleteDTO.GetID(), deleteDTO, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
-		Topic:   c.cfg.KafkaTopics.UserDelete.TopicName,
-		Value:   dtoBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	})
+	return c.publisher.Publish(ctx, message)
+}
+
+// ChangeUserStatusCmdHandler ...
+type ChangeUserStatusCmdHandler interface {
+	Handle(ctx context.Context, command *ChangeUserStatusCommand) (*dto.UserResponse, error)
+}
+
+type changeUserStatusHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	csClient commandService.CommandServiceClient
+}
+
+func NewChangeUserStatusHandler(log logging.Logger, cfg *config.Config, csClient commandService.CommandServiceClient) *changeUserStatusHandler {
+	return &changeUserStatusHandler{log: log, cfg: cfg, csClient: csClient}
+}
+
+// Handle applies the lifecycle transition synchronously in the command service, so that an invalid transition
+// is reported to the caller; users cannot change their own status
+func (c *changeUserStatusHandler) Handle(ctx context.Context, command *ChangeUserStatusCommand) (*dto.UserResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "changeUserStatusHandler.Handle")
+	defer span.End()
+	if kafkaClient.EventMetadataFromContext(ctx).Actor == command.ID.String() {
+		return nil, errors.New("users cannot change their own user status")
+	}
+	req := &commandService.ChangeUserStatusReq{
+		ID:       command.ID.String(),
+		Status:   command.Status.Stringify(),
+		Reason:   command.Reason,
+		TenantID: authentication.TenantFromContext(ctx),
+	}
+	if command.SuspendedUntil != nil {
+		req.SuspendedUntil = timestamppb.New(*command.SuspendedUntil)
+	}
+	res, err := c.csClient.ChangeUserStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return dto.UserResponseFromCommandGrpc(res.GetUser()), nil
 }
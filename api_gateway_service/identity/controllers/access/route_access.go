@@ -2,16 +2,54 @@ package access
 
 import "github.com/JECSand/identity-service/pkg/enums"
 
+// DefaultAccessRules maps the method and echo route pattern of each protected endpoint to its required role
 func DefaultAccessRules() map[string]enums.Role {
 	accessMap := make(map[string]enums.Role)
 	accessMap["POST /api/v1/users"] = enums.MEMBER
+	accessMap["GET /api/v1/users/:id"] = enums.MEMBER
+	accessMap["GET /api/v1/users/search"] = enums.MEMBER
+	accessMap["GET /api/v1/users/:id/groups"] = enums.MEMBER
+	accessMap["GET /api/v1/users/:id/effective-groups"] = enums.MEMBER
+	accessMap["PUT /api/v1/users/:id"] = enums.MEMBER
+	accessMap["DELETE /api/v1/users/:id"] = enums.MEMBER
+	accessMap["POST /api/v1/users/:id/activate"] = enums.ADMIN
+	accessMap["POST /api/v1/users/:id/suspend"] = enums.ADMIN
+	accessMap["POST /api/v1/users/:id/deactivate"] = enums.ADMIN
 	accessMap["GET /api/v1/auth"] = enums.MEMBER
 	accessMap["DELETE /api/v1/auth"] = enums.MEMBER
 	accessMap["POST /api/v1/auth/password"] = enums.MEMBER
 	accessMap["POST /api/v1/groups"] = enums.MEMBER
 	accessMap["GET /api/v1/groups"] = enums.MEMBER
 	accessMap["DELETE /api/v1/groups"] = enums.MEMBER
+	accessMap["GET /api/v1/groups/:id"] = enums.MEMBER
+	accessMap["GET /api/v1/groups/search"] = enums.MEMBER
+	accessMap["GET /api/v1/groups/:id/users"] = enums.MEMBER
+	accessMap["GET /api/v1/groups/:id/effective-members"] = enums.MEMBER
+	accessMap["PUT /api/v1/groups/:id"] = enums.MEMBER
+	accessMap["PUT /api/v1/groups/:id/parent"] = enums.MEMBER
+	accessMap["DELETE /api/v1/groups/:id"] = enums.MEMBER
 	accessMap["POST /api/v1/memberships"] = enums.MEMBER
+	accessMap["POST /oauth/introspect"] = enums.MEMBER
+	accessMap["POST /oauth/revoke"] = enums.MEMBER
 	accessMap["DELETE /api/v1/memberships"] = enums.MEMBER
+	accessMap["GET /api/v1/memberships/:id"] = enums.MEMBER
+	accessMap["PUT /api/v1/memberships/:id"] = enums.MEMBER
+	accessMap["DELETE /api/v1/memberships/:id"] = enums.MEMBER
+	accessMap["POST /api/v1/memberships/:id/extend"] = enums.ADMIN
+	accessMap["POST /api/v1/organizations"] = enums.ROOT
+	accessMap["GET /api/v1/organizations/:id"] = enums.MEMBER
+	accessMap["GET /api/v1/organizations/search"] = enums.ROOT
+	accessMap["PUT /api/v1/organizations/:id"] = enums.ADMIN
+	accessMap["POST /api/v1/organizations/:id/suspend"] = enums.ROOT
+	accessMap["POST /api/v1/organizations/:id/activate"] = enums.ROOT
+	accessMap["DELETE /api/v1/organizations/:id"] = enums.ROOT
+	accessMap["POST /api/v1/roles"] = enums.ADMIN
+	accessMap["GET /api/v1/roles/:id"] = enums.MEMBER
+	accessMap["GET /api/v1/roles/search"] = enums.MEMBER
+	accessMap["PUT /api/v1/roles/:id"] = enums.ADMIN
+	accessMap["DELETE /api/v1/roles/:id"] = enums.ADMIN
+	accessMap["POST /api/v1/authz/check"] = enums.MEMBER
+	accessMap["POST /api/v1/authz/check/batch"] = enums.MEMBER
+	accessMap["POST /api/v1/authz/resources"] = enums.MEMBER
 	return accessMap
 }
@@ -17,7 +17,7 @@ import (
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 )
 
@@ -35,9 +35,9 @@ type authHandlers struct {
 
 func (h *authHandlers) MapRoutes() {
 	h.group.POST("", h.Authenticate())
-	h.group.GET("", h.mw.RequestVerifyMiddleware(h.Validate()))
-	h.group.DELETE("", h.mw.RequestVerifyMiddleware(h.Invalidate()))
-	h.group.POST("/password", h.mw.RequestVerifyMiddleware(h.UpdatePassword()))
+	h.group.GET("", h.mw.RequestVerifyRemoteMiddleware(h.Validate()))
+	h.group.DELETE("", h.mw.RequestVerifyRemoteMiddleware(h.Invalidate()))
+	h.group.POST("/password", h.mw.RequestVerifyRemoteMiddleware(h.UpdatePassword()))
 	h.group.POST("/register", h.Register())
 	h.group.Any("/health", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, "OK")
@@ -51,6 +51,7 @@ func NewAuthHandlers(
 	mw middlewares.MiddlewareManager,
 	cfg *config.Config,
 	as *services2.AuthService,
+	us *services2.UserService,
 	v *validator.Validate,
 	metrics *metrics.ApiGatewayMetrics,
 ) *authHandlers {
@@ -61,6 +62,7 @@ func NewAuthHandlers(
 		mw:      mw,
 		cfg:     cfg,
 		as:      as,
+		us:      us,
 		v:       v,
 		metrics: metrics,
 	}
@@ -79,29 +81,31 @@ func (h *authHandlers) Authenticate() echo.HandlerFunc {
 		var err error
 		h.metrics.AuthenticateHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "authHandlers.Authenticate")
-		defer span.Finish()
+		defer span.End()
 		authDto := &dto.AuthenticateDTO{}
 		if err = c.Bind(authDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.v.StructCtx(ctx, authDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
-		query := queries.NewAuthenticateQuery(authDto.Email, authDto.Password)
+		query := queries.NewAuthenticateQuery("", authDto.Email, authDto.Password)
 		response, err := h.as.Queries.Authenticate.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("Authenticate", err)
+			h.log.WithContext(ctx).WarnMsg("Authenticate", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		session := h.auth.NewSession(response.User.ID, response.User.Root, enums.USER)
+		session.OrganizationId = response.User.OrganizationID
+		session.OrgAdmin = response.User.OrgAdmin
 		token, err := session.NewToken()
 		if err != nil {
-			h.log.WarnMsg("session.NewToken", err)
+			h.log.WithContext(ctx).WarnMsg("session.NewToken", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -123,30 +127,36 @@ func (h *authHandlers) Register() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.RegisterHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "authHandlers.Register")
-		defer span.Finish()
+		defer span.End()
 		var err error
 		createDto := &dto.CreateUserDTO{}
 		if err = c.Bind(createDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		createDto.ID, err = utilities.NewID()
 		if err = h.v.StructCtx(ctx, createDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		check := queries.NewCheckPasswordQuery("", createDto.Email, createDto.Username, createDto.Password)
+		if err = h.as.Queries.CheckPassword.Handle(ctx, check); err != nil {
+			h.log.WithContext(ctx).WarnMsg("CheckPassword", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		command := commands2.NewCreateUserCommand(createDto)
 		if err = h.us.Commands.CreateUser.Handle(ctx, command); err != nil {
-			h.log.WarnMsg("Invalidate", err)
+			h.log.WithContext(ctx).WarnMsg("Invalidate", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		session := h.auth.NewSession(createDto.ID.String(), false, enums.USER)
 		token, err := session.NewToken()
 		if err != nil {
-			h.log.WarnMsg("session.NewToken", err)
+			h.log.WithContext(ctx).WarnMsg("session.NewToken", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -168,41 +178,47 @@ func (h *authHandlers) UpdatePassword() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.UpdatePasswordHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "authHandlers.UpdatePassword")
-		defer span.Finish()
+		defer span.End()
 		req := c.Request()
 		session, err := h.auth.GetTokenSession(req.Header.Get("Authorization"))
 		if err != nil {
-			h.log.WarnMsg("GetTokenSession", err)
+			h.log.WithContext(ctx).WarnMsg("GetTokenSession", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		updateDto := &dto.UpdatePasswordDTO{}
 		if err = c.Bind(updateDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
-		query := queries.NewValidateQuery(session.UserId, updateDto.CurrentPassword, enums.PASSWORD)
-		response, err := h.as.Queries.Validate.Handle(ctx, query)
+		query := queries.NewAuthenticateQuery(session.UserId, "", updateDto.CurrentPassword)
+		response, err := h.as.Queries.Authenticate.Handle(ctx, query)
 		if err != nil || response.Status != 200 {
-			h.log.WarnMsg("Validate", err)
+			h.log.WithContext(ctx).WarnMsg("Authenticate", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		updateDto.ID, err = uuid.FromString(session.UserId)
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.v.StructCtx(ctx, updateDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		check := queries.NewCheckPasswordQuery(session.UserId, "", "", updateDto.NewPassword)
+		if err = h.as.Queries.CheckPassword.Handle(ctx, check); err != nil {
+			h.log.WithContext(ctx).WarnMsg("CheckPassword", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		command := commands2.NewUpdatePasswordCommand(updateDto)
 		if err = h.as.Commands.UpdatePassword.Handle(ctx, command); err != nil {
-			h.log.WarnMsg("Invalidate", err)
+			h.log.WithContext(ctx).WarnMsg("Invalidate", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -224,18 +240,18 @@ func (h *authHandlers) Invalidate() echo.HandlerFunc {
 		var err error
 		h.metrics.InvalidateHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "authHandlers.Invalidate")
-		defer span.Finish()
+		defer span.End()
 		req := c.Request()
 		invalidateDto := &dto.BlacklistTokenDTO{AccessToken: req.Header.Get("Authorization")}
 		invalidateDto.ID, err = utilities.NewID()
 		if err = h.v.StructCtx(ctx, invalidateDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		command := commands2.NewBlacklistTokenCommand(invalidateDto)
 		if err = h.as.Commands.BlacklistToken.Handle(ctx, command); err != nil {
-			h.log.WarnMsg("Invalidate", err)
+			h.log.WithContext(ctx).WarnMsg("Invalidate", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -256,18 +272,18 @@ func (h *authHandlers) Validate() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.ValidateHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "authHandlers.Validate")
-		defer span.Finish()
+		defer span.End()
 		req := c.Request()
 		session, err := h.auth.GetTokenSession(req.Header.Get("Authorization"))
 		if err != nil {
-			h.log.WarnMsg("GetTokenSession", err)
+			h.log.WithContext(ctx).WarnMsg("GetTokenSession", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		query := queries.NewValidateQuery(session.UserId, req.Header.Get("Authorization"), enums.TOKEN)
 		response, err := h.as.Queries.Validate.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("Validate", err)
+			h.log.WithContext(ctx).WarnMsg("Validate", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -276,8 +292,7 @@ func (h *authHandlers) Validate() echo.HandlerFunc {
 	}
 }
 
-func (h *authHandlers) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+func (h *authHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 	h.metrics.ErrorHttpRequests.Inc()
 }
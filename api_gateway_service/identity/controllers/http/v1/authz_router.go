@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/routing"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/go-playground/validator"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+type authzHandlers struct {
+	group   *echo.Group
+	log     logging.Logger
+	mw      middlewares.MiddlewareManager
+	cfg     *config.Config
+	zs      *services.AuthzService
+	v       *validator.Validate
+	metrics *metrics.ApiGatewayMetrics
+}
+
+func (h *authzHandlers) MapRoutes() {
+	h.group.POST("/check", h.mw.RequestVerifyMiddleware(h.Check()))
+	h.group.POST("/check/batch", h.mw.RequestVerifyMiddleware(h.BatchCheck()))
+	h.group.POST("/resources", h.mw.RequestVerifyMiddleware(h.ListResources()))
+	h.group.Any("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "OK")
+	})
+}
+
+func NewAuthzHandlers(
+	group *echo.Group,
+	log logging.Logger,
+	mw middlewares.MiddlewareManager,
+	cfg *config.Config,
+	zs *services.AuthzService,
+	v *validator.Validate,
+	metrics *metrics.ApiGatewayMetrics,
+) *authzHandlers {
+	return &authzHandlers{
+		group:   group,
+		log:     log,
+		mw:      mw,
+		cfg:     cfg,
+		zs:      zs,
+		v:       v,
+		metrics: metrics,
+	}
+}
+
+// Check
+// @Tags Authz
+// @Summary Check authorization
+// @Description Decide whether a user may take an action on a group, from its memberships, their roles and root status
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.DecisionResponse
+// @Router /authz/check [post]
+func (h *authzHandlers) Check() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.CheckHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "authzHandlers.Check")
+		defer span.End()
+		checkDto := &dto.CheckDTO{}
+		if err := c.Bind(checkDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err := h.v.StructCtx(ctx, checkDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		response, err := h.zs.Queries.Check.Handle(ctx, queries.NewCheckQuery(checkDto))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("Check", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// BatchCheck
+// @Tags Authz
+// @Summary Check authorization in batch
+// @Description Decide up to 100 checks at once, answering them in order
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.BatchCheckResponse
+// @Router /authz/check/batch [post]
+func (h *authzHandlers) BatchCheck() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.BatchCheckHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "authzHandlers.BatchCheck")
+		defer span.End()
+		batchDto := &dto.BatchCheckDTO{}
+		if err := c.Bind(batchDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err := h.v.StructCtx(ctx, batchDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		response, err := h.zs.Queries.BatchCheck.Handle(ctx, queries.NewBatchCheckQuery(batchDto))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("BatchCheck", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// ListResources
+// @Tags Authz
+// @Summary List authorized resources
+// @Description List the groups a user may take an action on
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.ResourcesResponse
+// @Router /authz/resources [post]
+func (h *authzHandlers) ListResources() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.ListResourcesHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "authzHandlers.ListResources")
+		defer span.End()
+		listDto := &dto.ListResourcesDTO{}
+		if err := c.Bind(listDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err := h.v.StructCtx(ctx, listDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		response, err := h.zs.Queries.ListResources.Handle(ctx, queries.NewListResourcesQuery(listDto))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("ListResources", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+func (h *authzHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
+	h.metrics.ErrorHttpRequests.Inc()
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
@@ -8,6 +9,7 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/constants"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/routing"
@@ -16,7 +18,7 @@ import (
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 )
 
@@ -36,7 +38,9 @@ func (h *groupsHandlers) MapRoutes() {
 	h.group.GET("/:id", h.mw.RequestVerifyMiddleware(h.GetGroupByID()))
 	h.group.GET("/search", h.mw.RequestVerifyMiddleware(h.SearchGroup()))
 	h.group.GET("/:id/users", h.mw.RequestVerifyMiddleware(h.GetGroupUserMemberships()))
+	h.group.GET("/:id/effective-members", h.mw.RequestVerifyMiddleware(h.GetGroupEffectiveMembers()))
 	h.group.PUT("/:id", h.mw.RequestVerifyMiddleware(h.UpdateGroup()))
+	h.group.PUT("/:id/parent", h.mw.RequestVerifyMiddleware(h.SetGroupParent()))
 	h.group.DELETE("/:id", h.mw.RequestVerifyMiddleware(h.DeleteGroup()))
 	h.group.Any("/health", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, "OK")
@@ -78,21 +82,21 @@ func (h *groupsHandlers) CreateGroup() echo.HandlerFunc {
 		var err error
 		h.metrics.CreateGroupHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.CreateGroup")
-		defer span.Finish()
+		defer span.End()
 		createDto := &dto.CreateGroupDTO{}
 		if err = c.Bind(createDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		createDto.ID, err = utilities.NewID()
 		if err = h.v.StructCtx(ctx, createDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.CreateGroup.Handle(ctx, commands.NewCreateGroupCommand(createDto)); err != nil {
-			h.log.WarnMsg("CreateGroup", err)
+			h.log.WithContext(ctx).WarnMsg("CreateGroup", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -114,17 +118,17 @@ func (h *groupsHandlers) GetGroupByID() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.GetGroupByIdHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.GetGroupByID")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		query := queries.NewGetGroupByIdQuery(id)
 		response, err := h.ps.Queries.GetGroupById.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("GetGroupById", err)
+			h.log.WithContext(ctx).WarnMsg("GetGroupById", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -148,12 +152,12 @@ func (h *groupsHandlers) SearchGroup() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.SearchGroupHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.SearchGroup")
-		defer span.Finish()
+		defer span.End()
 		pq := utilities.NewPaginationFromQueryParams(c.QueryParam(constants.Size), c.QueryParam(constants.Page))
 		query := queries.NewSearchGroupQuery(c.QueryParam(constants.Search), pq)
 		response, err := h.ps.Queries.SearchGroup.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("SearchGroup", err)
+			h.log.WithContext(ctx).WarnMsg("SearchGroup", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -177,10 +181,10 @@ func (h *groupsHandlers) GetGroupUserMemberships() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.GetUserMembershipByGroupIdHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.GetGroupUserMemberships")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -188,7 +192,42 @@ func (h *groupsHandlers) GetGroupUserMemberships() echo.HandlerFunc {
 		query := queries.NewGetUserMembershipByGroupIdQuery(id, pq)
 		response, err := h.ms.Queries.GetUserMembershipByGroupId.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("GetGroupUserMemberships", err)
+			h.log.WithContext(ctx).WarnMsg("GetGroupUserMemberships", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetGroupEffectiveMembers
+// @Tags Groups
+// @Summary Get group effective members
+// @Description Get the users that belong to a group directly or through its subgroups, each with the path granting it
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID"
+// @Param page query string false "page number"
+// @Param size query string false "number of elements"
+// @Success 200 {object} dto.EffectiveMembershipsListResponse
+// @Router /groups/{id}/effective-members [get]
+func (h *groupsHandlers) GetGroupEffectiveMembers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.GetEffectiveMembersHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.GetGroupEffectiveMembers")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		pq := utilities.NewPaginationFromQueryParams(c.QueryParam(constants.Size), c.QueryParam(constants.Page))
+		query := queries.NewGetEffectiveMembersQuery(id, pq)
+		response, err := h.ms.Queries.GetEffectiveMembers.Handle(ctx, query)
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("GetGroupEffectiveMembers", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -210,26 +249,31 @@ func (h *groupsHandlers) UpdateGroup() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.UpdateGroupHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.UpdateGroup")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		updateDto := &dto.UpdateGroupDTO{ID: id}
 		if err = c.Bind(updateDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.v.StructCtx(ctx, updateDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.checkTenant(ctx, id); err != nil {
+			h.log.WithContext(ctx).WarnMsg("checkTenant", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.UpdateGroup.Handle(ctx, commands.NewUpdateGroupCommand(updateDto)); err != nil {
-			h.log.WarnMsg("UpdateGroup", err)
+			h.log.WithContext(ctx).WarnMsg("UpdateGroup", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -238,6 +282,45 @@ func (h *groupsHandlers) UpdateGroup() echo.HandlerFunc {
 	}
 }
 
+// SetGroupParent
+// @Tags Groups
+// @Summary Set group parent
+// @Description Nest a group below another group of its organization, or move it to the top level with an empty parentID
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID"
+// @Param parent body dto.SetGroupParentDTO true "Parent group"
+// @Success 200 {object} dto.GroupResponse
+// @Router /groups/{id}/parent [put]
+func (h *groupsHandlers) SetGroupParent() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.SetGroupParentHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.SetGroupParent")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		setParentDto := &dto.SetGroupParentDTO{}
+		if err = c.Bind(setParentDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		setParentDto.ID = id
+		response, err := h.ps.Commands.SetGroupParent.Handle(ctx, commands.NewSetGroupParentCommand(setParentDto))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("SetGroupParent", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
 // DeleteGroup
 // @Tags Groups
 // @Summary Delete group
@@ -251,15 +334,20 @@ func (h *groupsHandlers) DeleteGroup() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.DeleteGroupHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "groupsHandlers.DeleteGroup")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.checkTenant(ctx, id); err != nil {
+			h.log.WithContext(ctx).WarnMsg("checkTenant", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.DeleteGroup.Handle(ctx, commands.NewDeleteGroupCommand(id)); err != nil {
-			h.log.WarnMsg("DeleteGroup", err)
+			h.log.WithContext(ctx).WarnMsg("DeleteGroup", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -268,8 +356,17 @@ func (h *groupsHandlers) DeleteGroup() echo.HandlerFunc {
 	}
 }
 
-func (h *groupsHandlers) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+// checkTenant looks the group up through the tenant scoped read model before a command is published for it,
+// since the command side processes it asynchronously
+func (h *groupsHandlers) checkTenant(ctx context.Context, id uuid.UUID) error {
+	if authentication.TenantFromContext(ctx) == "" {
+		return nil
+	}
+	_, err := h.ps.Queries.GetGroupById.Handle(ctx, queries.NewGetGroupByIdQuery(id))
+	return err
+}
+
+func (h *groupsHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 	h.metrics.ErrorHttpRequests.Inc()
 }
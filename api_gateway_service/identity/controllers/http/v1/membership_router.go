@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
@@ -8,6 +9,7 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/constants"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/routing"
@@ -16,7 +18,7 @@ import (
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 )
 
@@ -35,6 +37,7 @@ func (h *membershipsHandlers) MapRoutes() {
 	h.group.GET("/:id", h.mw.RequestVerifyMiddleware(h.GetMembershipByID()))
 	h.group.PUT("/:id", h.mw.RequestVerifyMiddleware(h.UpdateMembership()))
 	h.group.DELETE("/:id", h.mw.RequestVerifyMiddleware(h.DeleteMembership()))
+	h.group.POST("/:id/extend", h.mw.RequestVerifyMiddleware(h.ExtendMembership()))
 	h.group.Any("/health", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, "OK")
 	})
@@ -73,21 +76,21 @@ func (h *membershipsHandlers) CreateMembership() echo.HandlerFunc {
 		var err error
 		h.metrics.CreateMembershipHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "membershipsHandlers.CreateMembership")
-		defer span.Finish()
+		defer span.End()
 		createDto := &dto.CreateMembershipDTO{}
 		if err = c.Bind(createDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		createDto.ID, err = utilities.NewID()
 		if err = h.v.StructCtx(ctx, createDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.CreateMembership.Handle(ctx, commands.NewCreateMembershipCommand(createDto)); err != nil {
-			h.log.WarnMsg("CreateMembership", err)
+			h.log.WithContext(ctx).WarnMsg("CreateMembership", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -109,17 +112,17 @@ func (h *membershipsHandlers) GetMembershipByID() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.GetMembershipByIdHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "membershipsHandlers.GetMembershipByID")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		query := queries.NewGetMembershipByIdQuery(id)
 		response, err := h.ps.Queries.GetMembershipById.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("GetMembershipById", err)
+			h.log.WithContext(ctx).WarnMsg("GetMembershipById", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -141,26 +144,31 @@ func (h *membershipsHandlers) UpdateMembership() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.UpdateMembershipHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "membershipsHandlers.UpdateMembership")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		updateDto := &dto.UpdateMembershipDTO{ID: id}
 		if err = c.Bind(updateDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.v.StructCtx(ctx, updateDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.checkTenant(ctx, id); err != nil {
+			h.log.WithContext(ctx).WarnMsg("checkTenant", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.UpdateMembership.Handle(ctx, commands.NewUpdateMembershipCommand(updateDto)); err != nil {
-			h.log.WarnMsg("UpdateMembership", err)
+			h.log.WithContext(ctx).WarnMsg("UpdateMembership", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -169,6 +177,53 @@ func (h *membershipsHandlers) UpdateMembership() echo.HandlerFunc {
 	}
 }
 
+// ExtendMembership
+// @Tags Memberships
+// @Summary Extend membership
+// @Description Move the end of a membership's validity window, reactivating it if it has expired
+// @Accept json
+// @Produce json
+// @Param id path string true "Membership ID"
+// @Success 200 {object} dto.ExtendMembershipDTO
+// @Router /memberships/{id}/extend [post]
+func (h *membershipsHandlers) ExtendMembership() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.ExtendMembershipHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "membershipsHandlers.ExtendMembership")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		extendDto := &dto.ExtendMembershipDTO{ID: id}
+		if err = c.Bind(extendDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		extendDto.ID = id
+		if err = h.v.StructCtx(ctx, extendDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.checkTenant(ctx, id); err != nil {
+			h.log.WithContext(ctx).WarnMsg("checkTenant", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.ps.Commands.ExtendMembership.Handle(ctx, commands.NewExtendMembershipCommand(extendDto)); err != nil {
+			h.log.WithContext(ctx).WarnMsg("ExtendMembership", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, extendDto)
+	}
+}
+
 // DeleteMembership
 // @Tags Memberships
 // @Summary Delete membership
@@ -182,15 +237,20 @@ func (h *membershipsHandlers) DeleteMembership() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.DeleteMembershipHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "membershipsHandlers.DeleteMembership")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.checkTenant(ctx, id); err != nil {
+			h.log.WithContext(ctx).WarnMsg("checkTenant", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.DeleteMembership.Handle(ctx, commands.NewDeleteMembershipCommand(id)); err != nil {
-			h.log.WarnMsg("DeleteMembership", err)
+			h.log.WithContext(ctx).WarnMsg("DeleteMembership", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -199,8 +259,17 @@ func (h *membershipsHandlers) DeleteMembership() echo.HandlerFunc {
 	}
 }
 
-func (h *membershipsHandlers) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+// checkTenant looks the membership up through the tenant scoped read model before a command is published for it,
+// since the command side processes it asynchronously
+func (h *membershipsHandlers) checkTenant(ctx context.Context, id uuid.UUID) error {
+	if authentication.TenantFromContext(ctx) == "" {
+		return nil
+	}
+	_, err := h.ps.Queries.GetMembershipById.Handle(ctx, queries.NewGetMembershipByIdQuery(id))
+	return err
+}
+
+func (h *membershipsHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 	h.metrics.ErrorHttpRequests.Inc()
 }
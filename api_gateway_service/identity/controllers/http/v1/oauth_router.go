@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	commands2 "github.com/JECSand/identity-service/api_gateway_service/identity/commands"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
+	services2 "github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/routing"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/go-playground/validator"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+type oauthHandlers struct {
+	group   *echo.Group
+	log     logging.Logger
+	auth    authentication.Authenticator
+	mw      middlewares.MiddlewareManager
+	cfg     *config.Config
+	as      *services2.AuthService
+	v       *validator.Validate
+	metrics *metrics.ApiGatewayMetrics
+}
+
+func (h *oauthHandlers) MapRoutes() {
+	h.group.POST("/introspect", h.mw.RequestVerifyIntegrationMiddleware(h.Introspect()))
+	h.group.POST("/revoke", h.mw.RequestVerifyIntegrationMiddleware(h.Revoke()))
+}
+
+func NewOAuthHandlers(
+	group *echo.Group,
+	log logging.Logger,
+	auth authentication.Authenticator,
+	mw middlewares.MiddlewareManager,
+	cfg *config.Config,
+	as *services2.AuthService,
+	v *validator.Validate,
+	metrics *metrics.ApiGatewayMetrics,
+) *oauthHandlers {
+	return &oauthHandlers{
+		group:   group,
+		log:     log,
+		auth:    auth,
+		mw:      mw,
+		cfg:     cfg,
+		as:      as,
+		v:       v,
+		metrics: metrics,
+	}
+}
+
+// Introspect
+// @Tags OAuth
+// @Summary Introspect
+// @Description Returns the active state and claims of a token (RFC 7662)
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 {object} dto.IntrospectResponse
+// @Router /oauth/introspect [post]
+func (h *oauthHandlers) Introspect() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.IntrospectHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "oauthHandlers.Introspect")
+		defer span.End()
+		introspectDto := &dto.IntrospectDTO{}
+		if err := c.Bind(introspectDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return c.JSON(http.StatusBadRequest, dto.OAuthErrorDTO{Error: "invalid_request"})
+		}
+		if err := h.v.StructCtx(ctx, introspectDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return c.JSON(http.StatusBadRequest, dto.OAuthErrorDTO{Error: "invalid_request"})
+		}
+		session, err := h.auth.GetTokenSession(introspectDto.Token)
+		if err != nil {
+			h.metrics.SuccessHttpRequests.Inc()
+			return c.JSON(http.StatusOK, dto.IntrospectResponse{Active: false})
+		}
+		query := queries.NewValidateQuery(session.UserId, introspectDto.Token, enums.TOKEN)
+		response, err := h.as.Queries.Validate.Handle(ctx, query)
+		if err != nil || response.Status != 200 {
+			h.metrics.SuccessHttpRequests.Inc()
+			return c.JSON(http.StatusOK, dto.IntrospectResponse{Active: false})
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, dto.IntrospectResponse{
+			Active:    true,
+			Sub:       session.UserId,
+			Username:  response.User.Username,
+			Exp:       session.Expiration,
+			TokenType: session.Type.Stringify(),
+			Root:      session.RootAdmin,
+		})
+	}
+}
+
+// Revoke
+// @Tags OAuth
+// @Summary Revoke
+// @Description Revokes a token of the calling client, or any token for root clients (RFC 7009)
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200
+// @Router /oauth/revoke [post]
+func (h *oauthHandlers) Revoke() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.RevokeHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "oauthHandlers.Revoke")
+		defer span.End()
+		revokeDto := &dto.RevokeDTO{}
+		if err := c.Bind(revokeDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return c.JSON(http.StatusBadRequest, dto.OAuthErrorDTO{Error: "invalid_request"})
+		}
+		if err := h.v.StructCtx(ctx, revokeDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return c.JSON(http.StatusBadRequest, dto.OAuthErrorDTO{Error: "invalid_request"})
+		}
+		// invalid and expired tokens are already unusable, RFC 7009 answers them with 200 as well
+		session, err := h.auth.GetTokenSession(revokeDto.Token)
+		if err != nil {
+			h.metrics.SuccessHttpRequests.Inc()
+			return c.NoContent(http.StatusOK)
+		}
+		// clients may only revoke their own tokens unless root; RFC 7009 lets the server ignore the others
+		if caller, ok := authentication.SessionFromContext(ctx); !ok || (!caller.RootAdmin && caller.UserId != session.UserId) {
+			h.log.WithContext(ctx).Warnf("client may not revoke a token of %s", session.UserId)
+			h.metrics.SuccessHttpRequests.Inc()
+			return c.NoContent(http.StatusOK)
+		}
+		id, err := utilities.NewID()
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("NewID", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		command := commands2.NewRevokeTokenCommand(&dto.BlacklistTokenDTO{ID: id, AccessToken: revokeDto.Token})
+		if err = h.as.Commands.RevokeToken.Handle(ctx, command); err != nil {
+			h.log.WithContext(ctx).WarnMsg("RevokeToken", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return c.JSON(http.StatusServiceUnavailable, dto.OAuthErrorDTO{Error: "temporarily_unavailable"})
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *oauthHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
+	h.metrics.ErrorHttpRequests.Inc()
+}
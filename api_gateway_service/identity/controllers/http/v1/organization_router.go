@@ -0,0 +1,291 @@
+package v1
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/constants"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/routing"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+type organizationsHandlers struct {
+	group   *echo.Group
+	log     logging.Logger
+	mw      middlewares.MiddlewareManager
+	cfg     *config.Config
+	os      *services.OrganizationService
+	v       *validator.Validate
+	metrics *metrics.ApiGatewayMetrics
+}
+
+func (h *organizationsHandlers) MapRoutes() {
+	h.group.POST("", h.mw.RequestVerifyMiddleware(h.CreateOrganization()))
+	h.group.GET("/:id", h.mw.RequestVerifyMiddleware(h.GetOrganizationByID()))
+	h.group.GET("/search", h.mw.RequestVerifyMiddleware(h.SearchOrganization()))
+	h.group.PUT("/:id", h.mw.RequestVerifyMiddleware(h.UpdateOrganization()))
+	h.group.POST("/:id/suspend", h.mw.RequestVerifyRemoteMiddleware(h.SuspendOrganization()))
+	h.group.POST("/:id/activate", h.mw.RequestVerifyRemoteMiddleware(h.ActivateOrganization()))
+	h.group.DELETE("/:id", h.mw.RequestVerifyRemoteMiddleware(h.DeleteOrganization()))
+	h.group.Any("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "OK")
+	})
+}
+
+func NewOrganizationsHandlers(
+	group *echo.Group,
+	log logging.Logger,
+	mw middlewares.MiddlewareManager,
+	cfg *config.Config,
+	os *services.OrganizationService,
+	v *validator.Validate,
+	metrics *metrics.ApiGatewayMetrics,
+) *organizationsHandlers {
+	return &organizationsHandlers{
+		group:   group,
+		log:     log,
+		mw:      mw,
+		cfg:     cfg,
+		os:      os,
+		v:       v,
+		metrics: metrics,
+	}
+}
+
+// CreateOrganization
+// @Tags Organizations
+// @Summary Create organization
+// @Description Create a new organization (tenant)
+// @Accept json
+// @Produce json
+// @Success 201 {object} dto.CreateOrganizationResponseDTO
+// @Router /organizations [post]
+func (h *organizationsHandlers) CreateOrganization() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var err error
+		h.metrics.CreateOrganizationHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "organizationsHandlers.CreateOrganization")
+		defer span.End()
+		createDto := &dto.CreateOrganizationDTO{}
+		if err = c.Bind(createDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		createDto.ID, err = utilities.NewID()
+		if err = h.v.StructCtx(ctx, createDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.os.Commands.CreateOrganization.Handle(ctx, commands.NewCreateOrganizationCommand(createDto)); err != nil {
+			h.log.WithContext(ctx).WarnMsg("CreateOrganization", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusCreated, dto.CreateOrganizationResponseDTO{ID: createDto.ID})
+	}
+}
+
+// GetOrganizationByID
+// @Tags Organizations
+// @Summary Get organization
+// @Description Get organization by id; users other than root only see their own organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {object} dto.OrganizationResponse
+// @Router /organizations/{id} [get]
+func (h *organizationsHandlers) GetOrganizationByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.GetOrganizationByIdHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "organizationsHandlers.GetOrganizationByID")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		response, err := h.os.Queries.GetOrganizationById.Handle(ctx, queries.NewGetOrganizationByIdQuery(id))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("GetOrganizationById", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// SearchOrganization
+// @Tags Organizations
+// @Summary Search organization
+// @Description Get organizations by name with pagination
+// @Accept json
+// @Produce json
+// @Param search query string false "search text"
+// @Param page query string false "page number"
+// @Param size query string false "number of elements"
+// @Success 200 {object} dto.OrganizationsListResponse
+// @Router /organizations/search [get]
+func (h *organizationsHandlers) SearchOrganization() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.SearchOrganizationHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "organizationsHandlers.SearchOrganization")
+		defer span.End()
+		pq := utilities.NewPaginationFromQueryParams(c.QueryParam(constants.Size), c.QueryParam(constants.Page))
+		query := queries.NewSearchOrganizationQuery(c.QueryParam(constants.Search), pq)
+		response, err := h.os.Queries.SearchOrganization.Handle(ctx, query)
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("SearchOrganization", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// UpdateOrganization
+// @Tags Organizations
+// @Summary Update organization
+// @Description Update an existing organization; organization admins can only update their own organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {object} dto.UpdateOrganizationDTO
+// @Router /organizations/{id} [put]
+func (h *organizationsHandlers) UpdateOrganization() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.UpdateOrganizationHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "organizationsHandlers.UpdateOrganization")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		updateDto := &dto.UpdateOrganizationDTO{ID: id}
+		if err = c.Bind(updateDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.v.StructCtx(ctx, updateDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = authentication.CheckTenant(authentication.TenantFromContext(ctx), id.String()); err != nil {
+			h.log.WithContext(ctx).WarnMsg("CheckTenant", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.os.Commands.UpdateOrganization.Handle(ctx, commands.NewUpdateOrganizationCommand(updateDto)); err != nil {
+			h.log.WithContext(ctx).WarnMsg("UpdateOrganization", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, updateDto)
+	}
+}
+
+// SuspendOrganization
+// @Tags Organizations
+// @Summary Suspend organization
+// @Description Suspend an organization, rejecting the logins of its users and revoking all of their sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 ""
+// @Router /organizations/{id}/suspend [post]
+func (h *organizationsHandlers) SuspendOrganization() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return h.changeOrganizationStatus(c, "organizationsHandlers.SuspendOrganization", false)
+	}
+}
+
+// ActivateOrganization
+// @Tags Organizations
+// @Summary Activate organization
+// @Description Reactivate a suspended organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 ""
+// @Router /organizations/{id}/activate [post]
+func (h *organizationsHandlers) ActivateOrganization() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return h.changeOrganizationStatus(c, "organizationsHandlers.ActivateOrganization", true)
+	}
+}
+
+func (h *organizationsHandlers) changeOrganizationStatus(c echo.Context, spanName string, active bool) error {
+	h.metrics.ChangeOrganizationStatusHttpRequests.Inc()
+	ctx, span := tracing.StartHttpServerTracerSpan(c, spanName)
+	defer span.End()
+	id, err := uuid.FromString(c.Param(constants.ID))
+	if err != nil {
+		h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		h.traceErr(span, err)
+		return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+	}
+	if err = h.os.Commands.ChangeOrganizationStatus.Handle(ctx, commands.NewChangeOrganizationStatusCommand(id, active)); err != nil {
+		h.log.WithContext(ctx).WarnMsg("ChangeOrganizationStatus", err)
+		h.metrics.ErrorHttpRequests.Inc()
+		return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+	}
+	h.metrics.SuccessHttpRequests.Inc()
+	return c.NoContent(http.StatusOK)
+}
+
+// DeleteOrganization
+// @Tags Organizations
+// @Summary Delete organization
+// @Description Delete an organization along with its users, groups and memberships
+// @Accept json
+// @Produce json
+// @Success 200 ""
+// @Param id path string true "Organization ID"
+// @Router /organizations/{id} [delete]
+func (h *organizationsHandlers) DeleteOrganization() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.DeleteOrganizationHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "organizationsHandlers.DeleteOrganization")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.os.Commands.DeleteOrganization.Handle(ctx, commands.NewDeleteOrganizationCommand(id)); err != nil {
+			h.log.WithContext(ctx).WarnMsg("DeleteOrganization", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *organizationsHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
+	h.metrics.ErrorHttpRequests.Inc()
+}
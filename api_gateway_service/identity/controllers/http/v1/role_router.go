@@ -0,0 +1,282 @@
+package v1
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/constants"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/routing"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+type rolesHandlers struct {
+	group   *echo.Group
+	log     logging.Logger
+	mw      middlewares.MiddlewareManager
+	cfg     *config.Config
+	rs      *services.RoleService
+	v       *validator.Validate
+	metrics *metrics.ApiGatewayMetrics
+}
+
+func (h *rolesHandlers) MapRoutes() {
+	h.group.POST("", h.mw.RequestVerifyMiddleware(h.CreateRole()))
+	h.group.GET("/:id", h.mw.RequestVerifyMiddleware(h.GetRoleByID()))
+	h.group.GET("/search", h.mw.RequestVerifyMiddleware(h.SearchRoles()))
+	h.group.PUT("/:id", h.mw.RequestVerifyMiddleware(h.UpdateRole()))
+	h.group.DELETE("/:id", h.mw.RequestVerifyMiddleware(h.DeleteRole()))
+	h.group.Any("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "OK")
+	})
+}
+
+func NewRolesHandlers(
+	group *echo.Group,
+	log logging.Logger,
+	mw middlewares.MiddlewareManager,
+	cfg *config.Config,
+	rs *services.RoleService,
+	v *validator.Validate,
+	metrics *metrics.ApiGatewayMetrics,
+) *rolesHandlers {
+	return &rolesHandlers{
+		group:   group,
+		log:     log,
+		mw:      mw,
+		cfg:     cfg,
+		rs:      rs,
+		v:       v,
+		metrics: metrics,
+	}
+}
+
+// CreateRole
+// @Tags Roles
+// @Summary Create role
+// @Description Create a custom role with a permission set, scoped to a group or to an organization
+// @Accept json
+// @Produce json
+// @Success 201 {object} dto.CreateRoleResponseDTO
+// @Router /roles [post]
+func (h *rolesHandlers) CreateRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var err error
+		h.metrics.CreateRoleHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "rolesHandlers.CreateRole")
+		defer span.End()
+		createDto := &dto.CreateRoleDTO{}
+		if err = c.Bind(createDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		createDto.ID, err = utilities.NewID()
+		if err = h.v.StructCtx(ctx, createDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = authentication.CheckRoleName(createDto.Name); err != nil {
+			h.log.WithContext(ctx).WarnMsg("CheckRoleName", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if createDto.Permissions, err = authentication.NormalizePermissions(createDto.Permissions); err != nil {
+			h.log.WithContext(ctx).WarnMsg("NormalizePermissions", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.rs.Commands.CreateRole.Handle(ctx, commands.NewCreateRoleCommand(createDto)); err != nil {
+			h.log.WithContext(ctx).WarnMsg("CreateRole", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusCreated, dto.CreateRoleResponseDTO{ID: createDto.ID})
+	}
+}
+
+// GetRoleByID
+// @Tags Roles
+// @Summary Get role
+// @Description Get role by id; built-in roles are visible to every organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} dto.RoleResponse
+// @Router /roles/{id} [get]
+func (h *rolesHandlers) GetRoleByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.GetRoleByIdHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "rolesHandlers.GetRoleByID")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		response, err := h.rs.Queries.GetRoleById.Handle(ctx, queries.NewGetRoleByIdQuery(id))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("GetRoleById", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// SearchRoles
+// @Tags Roles
+// @Summary Search roles
+// @Description Get roles by name or description with pagination, optionally only those assignable in a group
+// @Accept json
+// @Produce json
+// @Param search query string false "search text"
+// @Param groupID query string false "group the roles are assignable in"
+// @Param page query string false "page number"
+// @Param size query string false "number of elements"
+// @Success 200 {object} dto.RolesListResponse
+// @Router /roles/search [get]
+func (h *rolesHandlers) SearchRoles() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.SearchRolesHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "rolesHandlers.SearchRoles")
+		defer span.End()
+		groupID := c.QueryParam(constants.GroupID)
+		if groupID != "" {
+			if _, err := uuid.FromString(groupID); err != nil {
+				h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+				h.traceErr(span, err)
+				return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+			}
+		}
+		pq := utilities.NewPaginationFromQueryParams(c.QueryParam(constants.Size), c.QueryParam(constants.Page))
+		query := queries.NewSearchRolesQuery(c.QueryParam(constants.Search), groupID, pq)
+		response, err := h.rs.Queries.SearchRoles.Handle(ctx, query)
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("SearchRoles", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// UpdateRole
+// @Tags Roles
+// @Summary Update role
+// @Description Update the name, description or permissions of a custom role; built-in roles cannot be changed
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} dto.UpdateRoleDTO
+// @Router /roles/{id} [put]
+func (h *rolesHandlers) UpdateRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.UpdateRoleHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "rolesHandlers.UpdateRole")
+		defer span.End()
+		id, err := customRoleID(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("customRoleID", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		updateDto := &dto.UpdateRoleDTO{ID: id}
+		if err = c.Bind(updateDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		updateDto.ID = id
+		if err = h.v.StructCtx(ctx, updateDto); err != nil {
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if updateDto.Name != "" {
+			if err = authentication.CheckRoleName(updateDto.Name); err != nil {
+				h.log.WithContext(ctx).WarnMsg("CheckRoleName", err)
+				h.traceErr(span, err)
+				return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+			}
+		}
+		if updateDto.Permissions != nil {
+			if updateDto.Permissions, err = authentication.NormalizePermissions(updateDto.Permissions); err != nil {
+				h.log.WithContext(ctx).WarnMsg("NormalizePermissions", err)
+				h.traceErr(span, err)
+				return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+			}
+		}
+		if err = h.rs.Commands.UpdateRole.Handle(ctx, commands.NewUpdateRoleCommand(updateDto)); err != nil {
+			h.log.WithContext(ctx).WarnMsg("UpdateRole", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, updateDto)
+	}
+}
+
+// DeleteRole
+// @Tags Roles
+// @Summary Delete role
+// @Description Delete a custom role; its memberships fall back to the built-in MEMBER role
+// @Accept json
+// @Produce json
+// @Success 200 ""
+// @Param id path string true "Role ID"
+// @Router /roles/{id} [delete]
+func (h *rolesHandlers) DeleteRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.DeleteRoleHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "rolesHandlers.DeleteRole")
+		defer span.End()
+		id, err := customRoleID(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("customRoleID", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.rs.Commands.DeleteRole.Handle(ctx, commands.NewDeleteRoleCommand(id)); err != nil {
+			h.log.WithContext(ctx).WarnMsg("DeleteRole", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// customRoleID parses the id of a role that may be changed, rejecting the ids of the built-in roles
+func customRoleID(param string) (uuid.UUID, error) {
+	id, err := uuid.FromString(param)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if enums.RoleFromID(id.String()) != 0 {
+		return uuid.Nil, authentication.ErrBuiltInRole
+	}
+	return id, nil
+}
+
+func (h *rolesHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
+	h.metrics.ErrorHttpRequests.Inc()
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
@@ -8,7 +9,9 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/constants"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/routing"
 	"github.com/JECSand/identity-service/pkg/tracing"
@@ -16,7 +19,7 @@ import (
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 )
 
@@ -27,6 +30,7 @@ type usersHandlers struct {
 	cfg     *config.Config
 	ps      *services.UserService
 	ms      *services.MembershipService
+	as      *services.AuthService
 	v       *validator.Validate
 	metrics *metrics.ApiGatewayMetrics
 }
@@ -36,8 +40,12 @@ func (h *usersHandlers) MapRoutes() {
 	h.group.GET("/:id", h.mw.RequestVerifyMiddleware(h.GetUserByID()))
 	h.group.GET("/search", h.mw.RequestVerifyMiddleware(h.SearchUser()))
 	h.group.GET("/:id/groups", h.mw.RequestVerifyMiddleware(h.GetUserGroupMemberships()))
+	h.group.GET("/:id/effective-groups", h.mw.RequestVerifyMiddleware(h.GetUserEffectiveGroups()))
 	h.group.PUT("/:id", h.mw.RequestVerifyMiddleware(h.UpdateUser()))
-	h.group.DELETE("/:id", h.mw.RequestVerifyMiddleware(h.DeleteUser()))
+	h.group.DELETE("/:id", h.mw.RequestVerifyRemoteMiddleware(h.DeleteUser()))
+	h.group.POST("/:id/activate", h.mw.RequestVerifyRemoteMiddleware(h.ActivateUser()))
+	h.group.POST("/:id/suspend", h.mw.RequestVerifyRemoteMiddleware(h.SuspendUser()))
+	h.group.POST("/:id/deactivate", h.mw.RequestVerifyRemoteMiddleware(h.DeactivateUser()))
 	h.group.Any("/health", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, "OK")
 	})
@@ -50,6 +58,7 @@ func NewUsersHandlers(
 	cfg *config.Config,
 	ps *services.UserService,
 	ms *services.MembershipService,
+	as *services.AuthService,
 	v *validator.Validate,
 	metrics *metrics.ApiGatewayMetrics,
 ) *usersHandlers {
@@ -60,6 +69,7 @@ func NewUsersHandlers(
 		cfg:     cfg,
 		ps:      ps,
 		ms:      ms,
+		as:      as,
 		v:       v,
 		metrics: metrics,
 	}
@@ -78,21 +88,27 @@ func (h *usersHandlers) CreateUser() echo.HandlerFunc {
 		var err error
 		h.metrics.CreateUserHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "usersHandlers.CreateUser")
-		defer span.Finish()
+		defer span.End()
 		createDto := &dto.CreateUserDTO{}
 		if err = c.Bind(createDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		createDto.ID, err = utilities.NewID()
 		if err = h.v.StructCtx(ctx, createDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		check := queries.NewCheckPasswordQuery("", createDto.Email, createDto.Username, createDto.Password)
+		if err = h.as.Queries.CheckPassword.Handle(ctx, check); err != nil {
+			h.log.WithContext(ctx).WarnMsg("CheckPassword", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.CreateUser.Handle(ctx, commands.NewCreateUserCommand(createDto)); err != nil {
-			h.log.WarnMsg("CreateUser", err)
+			h.log.WithContext(ctx).WarnMsg("CreateUser", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -114,17 +130,17 @@ func (h *usersHandlers) GetUserByID() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.GetUserByIdHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "usersHandlers.GetUserByID")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		query := queries.NewGetUserByIdQuery(id)
 		response, err := h.ps.Queries.GetUserById.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("GetUserById", err)
+			h.log.WithContext(ctx).WarnMsg("GetUserById", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -148,12 +164,12 @@ func (h *usersHandlers) SearchUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.SearchUserHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "usersHandlers.SearchUser")
-		defer span.Finish()
+		defer span.End()
 		pq := utilities.NewPaginationFromQueryParams(c.QueryParam(constants.Size), c.QueryParam(constants.Page))
 		query := queries.NewSearchUserQuery(c.QueryParam(constants.Search), pq)
 		response, err := h.ps.Queries.SearchUser.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("SearchUser", err)
+			h.log.WithContext(ctx).WarnMsg("SearchUser", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -177,10 +193,10 @@ func (h *usersHandlers) GetUserGroupMemberships() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.GetGroupMembershipByUserIdHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "usersHandlers.GetUserGroupMemberships")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -188,7 +204,42 @@ func (h *usersHandlers) GetUserGroupMemberships() echo.HandlerFunc {
 		query := queries.NewGetGroupMembershipByUserIdQuery(id, pq)
 		response, err := h.ms.Queries.GetGroupMembershipByUserId.Handle(ctx, query)
 		if err != nil {
-			h.log.WarnMsg("GetUserGroupMemberships", err)
+			h.log.WithContext(ctx).WarnMsg("GetUserGroupMemberships", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetUserEffectiveGroups
+// @Tags Users
+// @Summary Get user effective groups
+// @Description Get the groups a user belongs to directly or through subgroups, each with the path granting it
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param page query string false "page number"
+// @Param size query string false "number of elements"
+// @Success 200 {object} dto.EffectiveMembershipsListResponse
+// @Router /users/{id}/effective-groups [get]
+func (h *usersHandlers) GetUserEffectiveGroups() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.GetEffectiveGroupsHttpRequests.Inc()
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "usersHandlers.GetUserEffectiveGroups")
+		defer span.End()
+		id, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		pq := utilities.NewPaginationFromQueryParams(c.QueryParam(constants.Size), c.QueryParam(constants.Page))
+		query := queries.NewGetEffectiveGroupsQuery(id, pq)
+		response, err := h.ms.Queries.GetEffectiveGroups.Handle(ctx, query)
+		if err != nil {
+			h.log.WithContext(ctx).WarnMsg("GetUserEffectiveGroups", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -210,26 +261,31 @@ func (h *usersHandlers) UpdateUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.UpdateUserHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "usersHandlers.UpdateUser")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		updateDto := &dto.UpdateUserDTO{ID: id}
 		if err = c.Bind(updateDto); err != nil {
-			h.log.WarnMsg("Bind", err)
+			h.log.WithContext(ctx).WarnMsg("Bind", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.v.StructCtx(ctx, updateDto); err != nil {
-			h.log.WarnMsg("validate", err)
+			h.log.WithContext(ctx).WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.checkTenant(ctx, id); err != nil {
+			h.log.WithContext(ctx).WarnMsg("checkTenant", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.UpdateUser.Handle(ctx, commands.NewUpdateUserCommand(updateDto)); err != nil {
-			h.log.WarnMsg("UpdateUser", err)
+			h.log.WithContext(ctx).WarnMsg("UpdateUser", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -251,15 +307,20 @@ func (h *usersHandlers) DeleteUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.metrics.DeleteUserHttpRequests.Inc()
 		ctx, span := tracing.StartHttpServerTracerSpan(c, "usersHandlers.DeleteUser")
-		defer span.Finish()
+		defer span.End()
 		id, err := uuid.FromString(c.Param(constants.ID))
 		if err != nil {
-			h.log.WarnMsg("uuid.FromString", err)
+			h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			h.traceErr(span, err)
+			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+		if err = h.checkTenant(ctx, id); err != nil {
+			h.log.WithContext(ctx).WarnMsg("checkTenant", err)
 			h.traceErr(span, err)
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 		if err = h.ps.Commands.DeleteUser.Handle(ctx, commands.NewDeleteUserCommand(id)); err != nil {
-			h.log.WarnMsg("DeleteUser", err)
+			h.log.WithContext(ctx).WarnMsg("DeleteUser", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
@@ -268,8 +329,97 @@ func (h *usersHandlers) DeleteUser() echo.HandlerFunc {
 	}
 }
 
-func (h *usersHandlers) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+// ActivateUser
+// @Tags Users
+// @Summary Activate user
+// @Description Activate an invited, suspended or deactivated user
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.UserResponse
+// @Router /users/{id}/activate [post]
+func (h *usersHandlers) ActivateUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return h.changeUserStatus(c, "usersHandlers.ActivateUser", enums.ACTIVATED)
+	}
+}
+
+// SuspendUser
+// @Tags Users
+// @Summary Suspend user
+// @Description Suspend an active user, optionally until a date, and revoke all of its sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param suspension body dto.UserStatusChangeDTO false "Suspension reason and end date"
+// @Success 200 {object} dto.UserResponse
+// @Router /users/{id}/suspend [post]
+func (h *usersHandlers) SuspendUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return h.changeUserStatus(c, "usersHandlers.SuspendUser", enums.SUSPENDED)
+	}
+}
+
+// DeactivateUser
+// @Tags Users
+// @Summary Deactivate user
+// @Description Deactivate a user and revoke all of its sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param deactivation body dto.UserStatusChangeDTO false "Deactivation reason"
+// @Success 200 {object} dto.UserResponse
+// @Router /users/{id}/deactivate [post]
+func (h *usersHandlers) DeactivateUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return h.changeUserStatus(c, "usersHandlers.DeactivateUser", enums.DEACTIVATED)
+	}
+}
+
+// changeUserStatus binds and validates the optional UserStatusChangeDTO body before applying the transition
+func (h *usersHandlers) changeUserStatus(c echo.Context, spanName string, userStatus enums.UserStatus) error {
+	h.metrics.ChangeUserStatusHttpRequests.Inc()
+	ctx, span := tracing.StartHttpServerTracerSpan(c, spanName)
+	defer span.End()
+	id, err := uuid.FromString(c.Param(constants.ID))
+	if err != nil {
+		h.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		h.traceErr(span, err)
+		return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+	}
+	changeDto := &dto.UserStatusChangeDTO{}
+	if err = c.Bind(changeDto); err != nil {
+		h.log.WithContext(ctx).WarnMsg("Bind", err)
+		h.traceErr(span, err)
+		return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+	}
+	if err = h.v.StructCtx(ctx, changeDto); err != nil {
+		h.log.WithContext(ctx).WarnMsg("validate", err)
+		h.traceErr(span, err)
+		return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+	}
+	command := commands.NewChangeUserStatusCommand(id, userStatus, changeDto.Reason, changeDto.Until)
+	response, err := h.ps.Commands.ChangeUserStatus.Handle(ctx, command)
+	if err != nil {
+		h.log.WithContext(ctx).WarnMsg("ChangeUserStatus", err)
+		h.metrics.ErrorHttpRequests.Inc()
+		return routing.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+	}
+	h.metrics.SuccessHttpRequests.Inc()
+	return c.JSON(http.StatusOK, response)
+}
+
+// checkTenant looks the user up through the tenant scoped read model before a command is published for it,
+// since the command side processes it asynchronously
+func (h *usersHandlers) checkTenant(ctx context.Context, id uuid.UUID) error {
+	if authentication.TenantFromContext(ctx) == "" {
+		return nil
+	}
+	_, err := h.ps.Queries.GetUserById.Handle(ctx, queries.NewGetUserByIdQuery(id))
+	return err
+}
+
+func (h *usersHandlers) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 	h.metrics.ErrorHttpRequests.Inc()
 }
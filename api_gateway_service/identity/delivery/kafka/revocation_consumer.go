@@ -0,0 +1,241 @@
+package kafka
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	defaultSyncInterval  = time.Second
+	defaultPruneInterval = 10 * time.Minute
+)
+
+// revocationConsumer replays the token_blacklisted, user_status_changed, user_deleted and organization status topics
+// into the gateway's in-memory revocation list. Every replica replays the whole topics so no replica misses a revocation.
+type revocationConsumer struct {
+	log         logging.Logger
+	cfg         *config.Config
+	auth        authentication.Authenticator
+	revocations *authentication.RevocationList
+	sub         messaging.Subscriber
+	registry    *kafkaClient.EventRegistry
+	metrics     *metrics.ApiGatewayMetrics
+}
+
+func NewRevocationConsumer(
+	log logging.Logger,
+	cfg *config.Config,
+	auth authentication.Authenticator,
+	revocations *authentication.RevocationList,
+	sub messaging.Subscriber,
+	metrics *metrics.ApiGatewayMetrics,
+) *revocationConsumer {
+	return &revocationConsumer{
+		log:         log,
+		cfg:         cfg,
+		auth:        auth,
+		revocations: revocations,
+		sub:         sub,
+		registry:    kafkaClient.DefaultEventRegistry(),
+		metrics:     metrics,
+	}
+}
+
+// Run replays the revocation topics from the beginning and starts the sync and prune loops
+func (c *revocationConsumer) Run(ctx context.Context) error {
+	topics := []string{
+		c.cfg.KafkaTopics.TokenBlacklisted.TopicName,
+		c.cfg.KafkaTopics.UserStatusChanged.TopicName,
+		c.cfg.KafkaTopics.UserDeleted.TopicName,
+		c.cfg.KafkaTopics.OrganizationStatusChanged.TopicName,
+		c.cfg.KafkaTopics.OrganizationDeleted.TopicName,
+	}
+	readers := make([]messaging.ReplayReader, 0, len(topics))
+	for _, topic := range topics {
+		r, err := c.sub.Replay(ctx, topic)
+		if err != nil {
+			for _, opened := range readers {
+				opened.Close() // nolint: errCheck
+			}
+			return errors.Wrap(err, "subscriber.Replay")
+		}
+		readers = append(readers, r)
+	}
+	c.log.Infof("Starting revocation consumer topics: %v", topics)
+	for _, r := range readers {
+		go c.consume(ctx, r)
+	}
+	go c.sync(ctx, readers)
+	go c.prune(ctx)
+	return nil
+}
+
+func (c *revocationConsumer) consume(ctx context.Context, r messaging.ReplayReader) {
+	defer func() {
+		if err := r.Close(); err != nil {
+			c.log.Warnf("revocationConsumer.r.Close: %v", err)
+		}
+	}()
+	for {
+		m, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, messaging.ErrClosed) {
+				return
+			}
+			c.log.Warnf("revocationConsumer.FetchMessage: %v", err)
+			continue
+		}
+		switch m.Topic {
+		case c.cfg.KafkaTopics.TokenBlacklisted.TopicName:
+			c.processTokenBlacklisted(m)
+		case c.cfg.KafkaTopics.UserStatusChanged.TopicName:
+			c.processUserStatusChanged(m)
+		case c.cfg.KafkaTopics.UserDeleted.TopicName:
+			c.processUserDeleted(m)
+		case c.cfg.KafkaTopics.OrganizationStatusChanged.TopicName:
+			c.processOrganizationStatusChanged(m)
+		case c.cfg.KafkaTopics.OrganizationDeleted.TopicName:
+			c.processOrganizationDeleted(m)
+		}
+	}
+}
+
+func (c *revocationConsumer) processTokenBlacklisted(m messaging.Message) {
+	msg := &kafkaMessages.TokenBlacklisted{}
+	if _, err := c.registry.Unmarshal(m, kafkaClient.TokenBlacklistedEvent, msg); err != nil {
+		c.log.WarnMsg("registry.Unmarshal", err)
+		return
+	}
+	accessToken := msg.GetBlacklist().GetAccessToken()
+	if accessToken == "" {
+		return
+	}
+	session, err := c.auth.GetTokenSession(accessToken)
+	if err != nil {
+		// tokens that no longer verify are rejected before the revocation list is consulted
+		return
+	}
+	c.revocations.Revoke(accessToken, time.Unix(session.Expiration, 0))
+	c.metrics.RevokedTokens.Inc()
+}
+
+// processUserStatusChanged revokes every session a suspended or deactivated user was issued before the change
+func (c *revocationConsumer) processUserStatusChanged(m messaging.Message) {
+	msg := &kafkaMessages.UserStatusChanged{}
+	if _, err := c.registry.Unmarshal(m, kafkaClient.UserStatusChangedEvent, msg); err != nil {
+		c.log.WarnMsg("registry.Unmarshal", err)
+		return
+	}
+	user := msg.GetUser()
+	if user.GetSessionsRevokedAt() == nil {
+		return
+	}
+	c.revocations.RevokeUserSessions(user.GetID(), user.GetSessionsRevokedAt().AsTime())
+	c.metrics.RevokedUserSessions.Inc()
+}
+
+// processUserDeleted revokes every session of a deleted user
+func (c *revocationConsumer) processUserDeleted(m messaging.Message) {
+	msg := &kafkaMessages.UserDeleted{}
+	if _, err := c.registry.Unmarshal(m, kafkaClient.UserDeletedEvent, msg); err != nil {
+		c.log.WarnMsg("registry.Unmarshal", err)
+		return
+	}
+	revokedAt := m.Time
+	if revokedAt.IsZero() {
+		revokedAt = time.Now()
+	}
+	c.revocations.RevokeUserSessions(msg.GetID(), revokedAt)
+	c.metrics.RevokedUserSessions.Inc()
+}
+
+// processOrganizationStatusChanged revokes every session of the users of a suspended organization issued before the
+// suspension
+func (c *revocationConsumer) processOrganizationStatusChanged(m messaging.Message) {
+	msg := &kafkaMessages.OrganizationStatusChanged{}
+	if _, err := c.registry.Unmarshal(m, kafkaClient.OrganizationStatusChangedEvent, msg); err != nil {
+		c.log.WarnMsg("registry.Unmarshal", err)
+		return
+	}
+	organization := msg.GetOrganization()
+	if organization.GetSessionsRevokedAt() == nil {
+		return
+	}
+	c.revocations.RevokeOrganizationSessions(organization.GetID(), organization.GetSessionsRevokedAt().AsTime())
+	c.metrics.RevokedUserSessions.Inc()
+}
+
+// processOrganizationDeleted revokes every session of the users of a deleted organization
+func (c *revocationConsumer) processOrganizationDeleted(m messaging.Message) {
+	msg := &kafkaMessages.OrganizationDeleted{}
+	if _, err := c.registry.Unmarshal(m, kafkaClient.OrganizationDeletedEvent, msg); err != nil {
+		c.log.WarnMsg("registry.Unmarshal", err)
+		return
+	}
+	revokedAt := m.Time
+	if revokedAt.IsZero() {
+		revokedAt = time.Now()
+	}
+	c.revocations.RevokeOrganizationSessions(msg.GetID(), revokedAt)
+	c.metrics.RevokedUserSessions.Inc()
+}
+
+// sync marks the revocation list fresh whenever every replay has caught up with the end of its topic
+func (c *revocationConsumer) sync(ctx context.Context, readers []messaging.ReplayReader) {
+	interval := c.cfg.Revocation.SyncInterval
+	if interval <= 0 {
+		interval = defaultSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.caughtUp(ctx, readers, interval) {
+			c.revocations.MarkSynced(time.Now())
+		}
+	}
+}
+
+// caughtUp reports whether no reader lags behind the end of its topic
+func (c *revocationConsumer) caughtUp(ctx context.Context, readers []messaging.ReplayReader, timeout time.Duration) bool {
+	for _, r := range readers {
+		lagCtx, cancel := context.WithTimeout(ctx, timeout)
+		lag, err := r.Lag(lagCtx)
+		cancel()
+		if err != nil || lag != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *revocationConsumer) prune(ctx context.Context) {
+	interval := c.cfg.Revocation.PruneInterval
+	if interval <= 0 {
+		interval = defaultPruneInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if pruned := c.revocations.Prune(now); pruned > 0 {
+				c.log.Debugf("pruned %d expired revocations, %d remaining", pruned, c.revocations.Len())
+			}
+		}
+	}
+}
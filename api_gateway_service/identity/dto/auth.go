@@ -1,6 +1,7 @@
 package dto
 
 import (
+	authCommandService "github.com/JECSand/identity-service/command_service/protos/auth_command"
 	authQueryService "github.com/JECSand/identity-service/query_service/protos/auth_query"
 	"github.com/gofrs/uuid"
 	"time"
@@ -16,13 +17,15 @@ type RegisterDTO struct {
 
 // AuthUserResponse ...
 type AuthUserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email,omitempty"`
-	Username  string    `json:"username,omitempty"`
-	Root      bool      `json:"root,omitempty"`
-	Active    bool      `json:"active,omitempty"`
-	CreatedAt time.Time `json:"createdAt,omitempty"`
-	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	ID             string    `json:"id"`
+	Email          string    `json:"email,omitempty"`
+	Username       string    `json:"username,omitempty"`
+	Root           bool      `json:"root,omitempty"`
+	Active         bool      `json:"active,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty"`
+	OrgAdmin       bool      `json:"orgAdmin,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty"`
 }
 
 type AuthenticateDTO struct {
@@ -37,19 +40,36 @@ type AuthenticateResponse struct {
 
 func AuthUserResponseFromGrpc(aUser *authQueryService.User) *AuthUserResponse {
 	return &AuthUserResponse{
-		ID:        aUser.GetID(),
-		Email:     aUser.GetEmail(),
-		Username:  aUser.GetUsername(),
-		Root:      aUser.GetRoot(),
-		Active:    aUser.GetActive(),
-		CreatedAt: aUser.GetCreatedAt().AsTime(),
-		UpdatedAt: aUser.GetUpdatedAt().AsTime(),
+		ID:             aUser.GetID(),
+		Email:          aUser.GetEmail(),
+		Username:       aUser.GetUsername(),
+		Root:           aUser.GetRoot(),
+		Active:         aUser.GetActive(),
+		OrganizationID: aUser.GetOrganizationID(),
+		OrgAdmin:       aUser.GetOrgAdmin(),
+		CreatedAt:      aUser.GetCreatedAt().AsTime(),
+		UpdatedAt:      aUser.GetUpdatedAt().AsTime(),
 	}
 }
 
-func AuthenticateResponseResponseFromGrpc(auth *authQueryService.AuthenticateRes) *AuthenticateResponse {
+// AuthUserResponseFromCommandGrpc ...
+func AuthUserResponseFromCommandGrpc(aUser *authCommandService.User) *AuthUserResponse {
+	return &AuthUserResponse{
+		ID:             aUser.GetID(),
+		Email:          aUser.GetEmail(),
+		Username:       aUser.GetUsername(),
+		Root:           aUser.GetRoot(),
+		Active:         aUser.GetActive(),
+		OrganizationID: aUser.GetOrganizationID(),
+		OrgAdmin:       aUser.GetOrgAdmin(),
+		CreatedAt:      aUser.GetCreatedAt().AsTime(),
+		UpdatedAt:      aUser.GetUpdatedAt().AsTime(),
+	}
+}
+
+func AuthenticateResponseFromCommandGrpc(auth *authCommandService.AuthenticateRes) *AuthenticateResponse {
 	return &AuthenticateResponse{
-		User:   AuthUserResponseFromGrpc(auth.GetUser()),
+		User:   AuthUserResponseFromCommandGrpc(auth.GetUser()),
 		Status: auth.GetStatus(),
 	}
 }
@@ -77,6 +97,33 @@ type UpdatePasswordDTO struct {
 	NewPassword     string    `json:"newPassword" validate:"required,gte=0,lte=255"`
 }
 
+// IntrospectDTO is an RFC 7662 token introspection request
+type IntrospectDTO struct {
+	Token         string `json:"token" form:"token" validate:"required,gte=0,lte=5000"`
+	TokenTypeHint string `json:"token_type_hint,omitempty" form:"token_type_hint"`
+}
+
+// IntrospectResponse is an RFC 7662 token introspection response
+type IntrospectResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Root      bool   `json:"root,omitempty"`
+}
+
+// RevokeDTO is an RFC 7009 token revocation request
+type RevokeDTO struct {
+	Token         string `json:"token" form:"token" validate:"required,gte=0,lte=5000"`
+	TokenTypeHint string `json:"token_type_hint,omitempty" form:"token_type_hint"`
+}
+
+// OAuthErrorDTO is an RFC 6749 error response
+type OAuthErrorDTO struct {
+	Error string `json:"error"`
+}
+
 type ErrorDTO struct {
 	Message string `json:"message" validate:"required,gte=0,lte=255"`
 }
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	authzQueryService "github.com/JECSand/identity-service/query_service/protos/authz_query"
+	"github.com/gofrs/uuid"
+)
+
+// CheckDTO asks whether SubjectID may take Action on the group ResourceID; SubjectID is only honored for integration
+// and admin sessions, an empty SubjectID and every member session check the caller
+type CheckDTO struct {
+	SubjectID  uuid.UUID `json:"subjectID"`
+	Action     string    `json:"action" validate:"required,lte=250"`
+	ResourceID uuid.UUID `json:"resourceID" validate:"required"`
+}
+
+type BatchCheckDTO struct {
+	Checks []*CheckDTO `json:"checks" validate:"required,min=1,max=100,dive"`
+}
+
+// ListResourcesDTO asks for the groups SubjectID may take Action on; SubjectID is only honored for integration and
+// admin sessions, an empty SubjectID and every member session list those of the caller
+type ListResourcesDTO struct {
+	SubjectID uuid.UUID `json:"subjectID"`
+	Action    string    `json:"action" validate:"required,lte=250"`
+}
+
+// DecisionResponse ...
+type DecisionResponse struct {
+	SubjectID  string `json:"subjectID"`
+	Action     string `json:"action"`
+	ResourceID string `json:"resourceID"`
+	Allowed    bool   `json:"allowed"`
+	Reason     string `json:"reason,omitempty"`
+	GrantedBy  string `json:"grantedBy,omitempty"`
+}
+
+func DecisionResponseFromGrpc(decision *authzQueryService.Decision) *DecisionResponse {
+	return &DecisionResponse{
+		SubjectID:  decision.GetSubjectID(),
+		Action:     decision.GetAction(),
+		ResourceID: decision.GetResourceID(),
+		Allowed:    decision.GetAllowed(),
+		Reason:     decision.GetReason(),
+		GrantedBy:  decision.GetGrantedBy(),
+	}
+}
+
+// BatchCheckResponse holds the decisions of a batch in the order of its checks
+type BatchCheckResponse struct {
+	Decisions []*DecisionResponse `json:"decisions"`
+}
+
+func BatchCheckResponseFromGrpc(res *authzQueryService.BatchCheckRes) *BatchCheckResponse {
+	list := make([]*DecisionResponse, 0, len(res.GetDecisions()))
+	for _, decision := range res.GetDecisions() {
+		list = append(list, DecisionResponseFromGrpc(decision))
+	}
+	return &BatchCheckResponse{Decisions: list}
+}
+
+// ResourcesResponse lists the groups a subject may take an action on; All is set for root subjects, which may act on
+// every group
+type ResourcesResponse struct {
+	SubjectID   string   `json:"subjectID"`
+	Action      string   `json:"action"`
+	All         bool     `json:"all"`
+	ResourceIDs []string `json:"resourceIDs"`
+}
+
+func ResourcesResponseFromGrpc(res *authzQueryService.ListResourcesRes) *ResourcesResponse {
+	resourceIDs := res.GetResourceIDs()
+	if resourceIDs == nil {
+		resourceIDs = make([]string, 0)
+	}
+	return &ResourcesResponse{
+		SubjectID:   res.GetSubjectID(),
+		Action:      res.GetAction(),
+		All:         res.GetAll(),
+		ResourceIDs: resourceIDs,
+	}
+}
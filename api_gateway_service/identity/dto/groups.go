@@ -1,17 +1,22 @@
 package dto
 
 import (
+	groupCommandService "github.com/JECSand/identity-service/command_service/protos/group_command"
 	groupQueryService "github.com/JECSand/identity-service/query_service/protos/group_query"
 	"github.com/gofrs/uuid"
 	"time"
 )
 
+// CreateGroupDTO creates a group; OrganizationID is only honored for root callers, other groups are created in the
+// organization of the caller. A ParentID nests the group below a group of the same organization
 type CreateGroupDTO struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name" validate:"required,gte=0,lte=255"`
-	Description string    `json:"description" validate:"required,gte=0,lte=255"`
-	CreatorID   uuid.UUID `json:"creatorID" validate:"required,gte=0,lte=5000"`
-	Active      bool      `json:"active"`
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name" validate:"required,gte=0,lte=255"`
+	Description    string    `json:"description" validate:"required,gte=0,lte=255"`
+	CreatorID      uuid.UUID `json:"creatorID" validate:"required,gte=0,lte=5000"`
+	Active         bool      `json:"active"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	ParentID       uuid.UUID `json:"parentID"`
 }
 
 type CreateGroupResponseDTO struct {
@@ -24,26 +29,51 @@ type UpdateGroupDTO struct {
 	Description string    `json:"description" validate:"required,gte=0,lte=255"`
 }
 
+// SetGroupParentDTO moves a group below ParentID, or to the top level when ParentID is empty
+type SetGroupParentDTO struct {
+	ID       uuid.UUID `json:"id"`
+	ParentID uuid.UUID `json:"parentID"`
+}
+
 // GroupResponse ...
 type GroupResponse struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name,omitempty"`
-	Description string    `json:"description,omitempty"`
-	CreatorID   string    `json:"creatorID,omitempty"`
-	Active      bool      `json:"active,omitempty"`
-	CreatedAt   time.Time `json:"createdAt,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	CreatorID      string    `json:"creatorID,omitempty"`
+	Active         bool      `json:"active,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty"`
+	ParentID       string    `json:"parentID,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty"`
 }
 
 func GroupResponseFromGrpc(group *groupQueryService.Group) *GroupResponse {
 	return &GroupResponse{
-		ID:          group.GetID(),
-		Name:        group.GetName(),
-		Description: group.GetDescription(),
-		CreatorID:   group.GetCreatorID(),
-		Active:      group.GetActive(),
-		CreatedAt:   group.GetCreatedAt().AsTime(),
-		UpdatedAt:   group.GetUpdatedAt().AsTime(),
+		ID:             group.GetID(),
+		Name:           group.GetName(),
+		Description:    group.GetDescription(),
+		CreatorID:      group.GetCreatorID(),
+		Active:         group.GetActive(),
+		OrganizationID: group.GetOrganizationID(),
+		ParentID:       group.GetParentID(),
+		CreatedAt:      group.GetCreatedAt().AsTime(),
+		UpdatedAt:      group.GetUpdatedAt().AsTime(),
+	}
+}
+
+// GroupResponseFromCommandGrpc converts a group returned by the command service
+func GroupResponseFromCommandGrpc(group *groupCommandService.Group) *GroupResponse {
+	return &GroupResponse{
+		ID:             group.GetID(),
+		Name:           group.GetName(),
+		Description:    group.GetDescription(),
+		CreatorID:      group.GetCreatorID(),
+		Active:         group.GetActive(),
+		OrganizationID: group.GetOrganizationID(),
+		ParentID:       group.GetParentID(),
+		CreatedAt:      group.GetCreatedAt().AsTime(),
+		UpdatedAt:      group.GetUpdatedAt().AsTime(),
 	}
 }
 
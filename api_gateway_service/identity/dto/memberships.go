@@ -8,11 +8,14 @@ import (
 )
 
 type CreateMembershipDTO struct {
-	ID      uuid.UUID              `json:"id"`
-	UserID  uuid.UUID              `json:"userID" validate:"required"`
-	GroupID uuid.UUID              `json:"groupID" validate:"required"`
-	Status  enums.MembershipStatus `json:"status" validate:"required"`
-	Role    enums.Role             `json:"role" validate:"required"`
+	ID         uuid.UUID              `json:"id"`
+	UserID     uuid.UUID              `json:"userID" validate:"required"`
+	GroupID    uuid.UUID              `json:"groupID" validate:"required"`
+	Status     enums.MembershipStatus `json:"status" validate:"required"`
+	Role       enums.Role             `json:"role" validate:"required_without=RoleID"`
+	RoleID     uuid.UUID              `json:"roleID"`
+	ValidFrom  *time.Time             `json:"validFrom"`
+	ValidUntil *time.Time             `json:"validUntil" validate:"omitempty,gt"`
 }
 
 type CreateMembershipResponseDTO struct {
@@ -22,29 +25,49 @@ type CreateMembershipResponseDTO struct {
 type UpdateMembershipDTO struct {
 	ID     uuid.UUID              `json:"id" validate:"required"`
 	Status enums.MembershipStatus `json:"status" validate:"required"`
-	Role   enums.Role             `json:"role" validate:"required"`
+	Role   enums.Role             `json:"role" validate:"required_without=RoleID"`
+	RoleID uuid.UUID              `json:"roleID"`
+}
+
+// ExtendMembershipDTO moves the end of a membership's validity window; Reason is kept in the membership audit log
+type ExtendMembershipDTO struct {
+	ID         uuid.UUID `json:"id" validate:"required"`
+	ValidUntil time.Time `json:"validUntil" validate:"required"`
+	Reason     string    `json:"reason" validate:"lte=250"`
 }
 
 // MembershipResponse ...
 type MembershipResponse struct {
-	ID        string                 `json:"id"`
-	UserID    string                 `json:"userID,omitempty"`
-	GroupID   string                 `json:"groupID,omitempty"`
-	Status    enums.MembershipStatus `json:"status,omitempty"`
-	Role      enums.Role             `json:"role,omitempty"`
-	CreatedAt time.Time              `json:"createdAt,omitempty"`
-	UpdatedAt time.Time              `json:"updatedAt,omitempty"`
+	ID             string                 `json:"id"`
+	UserID         string                 `json:"userID,omitempty"`
+	GroupID        string                 `json:"groupID,omitempty"`
+	Status         enums.MembershipStatus `json:"status,omitempty"`
+	Role           enums.Role             `json:"role,omitempty"`
+	RoleID         string                 `json:"roleID,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty"`
+	OrganizationID string                 `json:"organizationID,omitempty"`
+	ValidFrom      *time.Time             `json:"validFrom,omitempty"`
+	ValidUntil     *time.Time             `json:"validUntil,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty"`
 }
 
 func MembershipResponseFromGrpc(membership *membershipQueryService.Membership) *MembershipResponse {
 	return &MembershipResponse{
-		ID:        membership.GetID(),
-		UserID:    membership.GetUserID(),
-		GroupID:   membership.GetGroupID(),
-		Status:    enums.MembershipStatus(membership.GetStatus()),
-		Role:      enums.Role(membership.GetRole()),
-		CreatedAt: membership.GetCreatedAt().AsTime(),
-		UpdatedAt: membership.GetUpdatedAt().AsTime(),
+		ID:             membership.GetID(),
+		UserID:         membership.GetUserID(),
+		GroupID:        membership.GetGroupID(),
+		Status:         enums.MembershipStatus(membership.GetStatus()),
+		Role:           enums.Role(membership.GetRole()),
+		RoleID:         membership.GetRoleID(),
+		RoleName:       membership.GetRoleName(),
+		Permissions:    membership.GetPermissions(),
+		OrganizationID: membership.GetOrganizationID(),
+		ValidFrom:      optionalTime(membership.GetValidFrom()),
+		ValidUntil:     optionalTime(membership.GetValidUntil()),
+		CreatedAt:      membership.GetCreatedAt().AsTime(),
+		UpdatedAt:      membership.GetUpdatedAt().AsTime(),
 	}
 }
 
@@ -58,6 +81,11 @@ type UserMembershipResponse struct {
 	Username     string                 `json:"username,omitempty"`
 	Status       enums.MembershipStatus `json:"status,omitempty"`
 	Role         enums.Role             `json:"role,omitempty"`
+	RoleID       string                 `json:"roleID,omitempty"`
+	RoleName     string                 `json:"roleName,omitempty"`
+	Permissions  []string               `json:"permissions,omitempty"`
+	ValidFrom    *time.Time             `json:"validFrom,omitempty"`
+	ValidUntil   *time.Time             `json:"validUntil,omitempty"`
 	CreatedAt    time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt    time.Time              `json:"updatedAt,omitempty"`
 }
@@ -72,6 +100,11 @@ func UserMembershipResponseFromGrpc(userMembership *membershipQueryService.UserM
 		Username:     userMembership.GetUsername(),
 		Status:       enums.MembershipStatus(userMembership.GetStatus()),
 		Role:         enums.Role(userMembership.GetRole()),
+		RoleID:       userMembership.GetRoleID(),
+		RoleName:     userMembership.GetRoleName(),
+		Permissions:  userMembership.GetPermissions(),
+		ValidFrom:    optionalTime(userMembership.GetValidFrom()),
+		ValidUntil:   optionalTime(userMembership.GetValidUntil()),
 		CreatedAt:    userMembership.GetCreatedAt().AsTime(),
 		UpdatedAt:    userMembership.GetUpdatedAt().AsTime(),
 	}
@@ -112,7 +145,12 @@ type GroupMembershipResponse struct {
 	Description  string                 `json:"description,omitempty"`
 	Status       enums.MembershipStatus `json:"status,omitempty"`
 	Role         enums.Role             `json:"role,omitempty"`
+	RoleID       string                 `json:"roleID,omitempty"`
+	RoleName     string                 `json:"roleName,omitempty"`
+	Permissions  []string               `json:"permissions,omitempty"`
 	Creator      bool                   `json:"creator,omitempty"`
+	ValidFrom    *time.Time             `json:"validFrom,omitempty"`
+	ValidUntil   *time.Time             `json:"validUntil,omitempty"`
 	CreatedAt    time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt    time.Time              `json:"updatedAt,omitempty"`
 }
@@ -127,7 +165,12 @@ func GroupMembershipResponseFromGrpc(groupMembership *membershipQueryService.Gro
 		Description:  groupMembership.GetDescription(),
 		Status:       enums.MembershipStatus(groupMembership.GetStatus()),
 		Role:         enums.Role(groupMembership.GetRole()),
+		RoleID:       groupMembership.GetRoleID(),
+		RoleName:     groupMembership.GetRoleName(),
+		Permissions:  groupMembership.GetPermissions(),
 		Creator:      groupMembership.GetCreator(),
+		ValidFrom:    optionalTime(groupMembership.GetValidFrom()),
+		ValidUntil:   optionalTime(groupMembership.GetValidUntil()),
 		CreatedAt:    groupMembership.GetCreatedAt().AsTime(),
 		UpdatedAt:    groupMembership.GetUpdatedAt().AsTime(),
 	}
@@ -157,3 +200,48 @@ func GroupMembershipListResponseFromGrpc(listResponse *membershipQueryService.Ge
 		GroupMemberships: list,
 	}
 }
+
+// EffectiveMembershipResponse is a group a user belongs to directly or through subgroups; Path lists the groups from
+// the one the user is a direct member of up to GroupID
+type EffectiveMembershipResponse struct {
+	UserID         string    `json:"userID"`
+	GroupID        string    `json:"groupID"`
+	Path           []string  `json:"path"`
+	OrganizationID string    `json:"organizationID,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty"`
+}
+
+func EffectiveMembershipResponseFromGrpc(em *membershipQueryService.EffectiveMembership) *EffectiveMembershipResponse {
+	return &EffectiveMembershipResponse{
+		UserID:         em.GetUserID(),
+		GroupID:        em.GetGroupID(),
+		Path:           em.GetPath(),
+		OrganizationID: em.GetOrganizationID(),
+		UpdatedAt:      em.GetUpdatedAt().AsTime(),
+	}
+}
+
+// EffectiveMembershipsListResponse ...
+type EffectiveMembershipsListResponse struct {
+	TotalCount           int64                          `json:"totalCount" bson:"total_count"`
+	TotalPages           int64                          `json:"totalPages" bson:"total_pages"`
+	Page                 int64                          `json:"page" bson:"page"`
+	Size                 int64                          `json:"size" bson:"size"`
+	HasMore              bool                           `json:"hasMore" bson:"has_more"`
+	EffectiveMemberships []*EffectiveMembershipResponse `json:"effectiveMemberships" bson:"effective_memberships"`
+}
+
+func EffectiveMembershipListResponseFromGrpc(listResponse *membershipQueryService.GetEffectiveMembershipsRes) *EffectiveMembershipsListResponse {
+	list := make([]*EffectiveMembershipResponse, 0, len(listResponse.GetEffectiveMemberships()))
+	for _, em := range listResponse.GetEffectiveMemberships() {
+		list = append(list, EffectiveMembershipResponseFromGrpc(em))
+	}
+	return &EffectiveMembershipsListResponse{
+		TotalCount:           listResponse.GetTotalCount(),
+		TotalPages:           listResponse.GetTotalPages(),
+		Page:                 listResponse.GetPage(),
+		Size:                 listResponse.GetSize(),
+		HasMore:              listResponse.GetHasMore(),
+		EffectiveMemberships: list,
+	}
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	organizationQueryService "github.com/JECSand/identity-service/query_service/protos/organization_query"
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+type CreateOrganizationDTO struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name" validate:"required,gte=3,lte=250"`
+	Description string    `json:"description" validate:"lte=500"`
+}
+
+type CreateOrganizationResponseDTO struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+type UpdateOrganizationDTO struct {
+	ID          uuid.UUID `json:"id" validate:"required"`
+	Name        string    `json:"name" validate:"omitempty,gte=3,lte=250"`
+	Description string    `json:"description" validate:"lte=500"`
+}
+
+// OrganizationResponse ...
+type OrganizationResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+}
+
+func OrganizationResponseFromGrpc(organization *organizationQueryService.Organization) *OrganizationResponse {
+	return &OrganizationResponse{
+		ID:          organization.GetID(),
+		Name:        organization.GetName(),
+		Description: organization.GetDescription(),
+		Active:      organization.GetActive(),
+		CreatedAt:   organization.GetCreatedAt().AsTime(),
+		UpdatedAt:   organization.GetUpdatedAt().AsTime(),
+	}
+}
+
+// OrganizationsListResponse ...
+type OrganizationsListResponse struct {
+	TotalCount    int64                   `json:"totalCount" bson:"total_count"`
+	TotalPages    int64                   `json:"totalPages" bson:"total_pages"`
+	Page          int64                   `json:"page" bson:"page"`
+	Size          int64                   `json:"size" bson:"size"`
+	HasMore       bool                    `json:"hasMore" bson:"has_more"`
+	Organizations []*OrganizationResponse `json:"organizations" bson:"organizations"`
+}
+
+func OrganizationsListResponseFromGrpc(listResponse *organizationQueryService.SearchOrganizationRes) *OrganizationsListResponse {
+	list := make([]*OrganizationResponse, 0, len(listResponse.GetOrganizations()))
+	for _, organization := range listResponse.GetOrganizations() {
+		list = append(list, OrganizationResponseFromGrpc(organization))
+	}
+	return &OrganizationsListResponse{
+		TotalCount:    listResponse.GetTotalCount(),
+		TotalPages:    listResponse.GetTotalPages(),
+		Page:          listResponse.GetPage(),
+		Size:          listResponse.GetSize(),
+		HasMore:       listResponse.GetHasMore(),
+		Organizations: list,
+	}
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	roleQueryService "github.com/JECSand/identity-service/query_service/protos/role_query"
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+type CreateRoleDTO struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name" validate:"required,gte=3,lte=250"`
+	Description    string    `json:"description" validate:"lte=500"`
+	Permissions    []string  `json:"permissions"`
+	GroupID        uuid.UUID `json:"groupID"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+}
+
+type CreateRoleResponseDTO struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// UpdateRoleDTO changes a custom role; a nil Permissions keeps the current permission set
+type UpdateRoleDTO struct {
+	ID          uuid.UUID `json:"id" validate:"required"`
+	Name        string    `json:"name" validate:"omitempty,gte=3,lte=250"`
+	Description string    `json:"description" validate:"lte=500"`
+	Permissions []string  `json:"permissions"`
+}
+
+// RoleResponse ...
+type RoleResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	Permissions    []string  `json:"permissions"`
+	GroupID        string    `json:"groupID,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty"`
+	BuiltIn        bool      `json:"builtIn"`
+	CreatedAt      time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty"`
+}
+
+func RoleResponseFromGrpc(role *roleQueryService.Role) *RoleResponse {
+	permissions := role.GetPermissions()
+	if permissions == nil {
+		permissions = make([]string, 0)
+	}
+	return &RoleResponse{
+		ID:             role.GetID(),
+		Name:           role.GetName(),
+		Description:    role.GetDescription(),
+		Permissions:    permissions,
+		GroupID:        role.GetGroupID(),
+		OrganizationID: role.GetOrganizationID(),
+		BuiltIn:        role.GetBuiltIn(),
+		CreatedAt:      role.GetCreatedAt().AsTime(),
+		UpdatedAt:      role.GetUpdatedAt().AsTime(),
+	}
+}
+
+// RolesListResponse ...
+type RolesListResponse struct {
+	TotalCount int64           `json:"totalCount" bson:"total_count"`
+	TotalPages int64           `json:"totalPages" bson:"total_pages"`
+	Page       int64           `json:"page" bson:"page"`
+	Size       int64           `json:"size" bson:"size"`
+	HasMore    bool            `json:"hasMore" bson:"has_more"`
+	Roles      []*RoleResponse `json:"roles" bson:"roles"`
+}
+
+func RolesListResponseFromGrpc(listResponse *roleQueryService.SearchRolesRes) *RolesListResponse {
+	list := make([]*RoleResponse, 0, len(listResponse.GetRoles()))
+	for _, role := range listResponse.GetRoles() {
+		list = append(list, RoleResponseFromGrpc(role))
+	}
+	return &RolesListResponse{
+		TotalCount: listResponse.GetTotalCount(),
+		TotalPages: listResponse.GetTotalPages(),
+		Page:       listResponse.GetPage(),
+		Size:       listResponse.GetSize(),
+		HasMore:    listResponse.GetHasMore(),
+		Roles:      list,
+	}
+}
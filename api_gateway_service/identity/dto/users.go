@@ -1,17 +1,24 @@
 package dto
 
 import (
+	commandService "github.com/JECSand/identity-service/command_service/protos/user_command"
 	queryService "github.com/JECSand/identity-service/query_service/protos/user_query"
 	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"time"
 )
 
+// CreateUserDTO creates a user; OrganizationID is only honored for root callers, other users are created in the
+// organization of the caller
 type CreateUserDTO struct {
-	ID       uuid.UUID `json:"id"`
-	Email    string    `json:"email" validate:"required,gte=0,lte=255"`
-	Username string    `json:"username" validate:"required,gte=0,lte=255"`
-	Password string    `json:"password" validate:"required,gte=0,lte=5000"`
-	Active   bool      `json:"active"`
+	ID             uuid.UUID `json:"id"`
+	Email          string    `json:"email" validate:"required,gte=0,lte=255"`
+	Username       string    `json:"username" validate:"required,gte=0,lte=255"`
+	Password       string    `json:"password" validate:"required,gte=0,lte=5000"`
+	Active         bool      `json:"active"`
+	Invite         bool      `json:"invite"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	OrgAdmin       bool      `json:"orgAdmin"`
 }
 
 type CreateUserResponseDTO struct {
@@ -26,27 +33,69 @@ type UpdateUserDTO struct {
 	Active   bool      `json:"active"`
 }
 
+// UserStatusChangeDTO carries the reason for a suspension or deactivation; Until only applies to suspensions
+type UserStatusChangeDTO struct {
+	Reason string     `json:"reason" validate:"lte=250"`
+	Until  *time.Time `json:"until" validate:"omitempty,gt"`
+}
+
 // UserResponse ...
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email,omitempty"`
-	Username  string    `json:"username,omitempty"`
-	Root      bool      `json:"root,omitempty"`
-	Active    bool      `json:"active,omitempty"`
-	CreatedAt time.Time `json:"createdAt,omitempty"`
-	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	ID             string     `json:"id"`
+	Email          string     `json:"email,omitempty"`
+	Username       string     `json:"username,omitempty"`
+	Root           bool       `json:"root,omitempty"`
+	Active         bool       `json:"active,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	StatusReason   string     `json:"statusReason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	OrganizationID string     `json:"organizationID,omitempty"`
+	OrgAdmin       bool       `json:"orgAdmin,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time  `json:"updatedAt,omitempty"`
 }
 
 func UserResponseFromGrpc(user *queryService.User) *UserResponse {
 	return &UserResponse{
-		ID:        user.GetID(),
-		Email:     user.GetEmail(),
-		Username:  user.GetUsername(),
-		Root:      user.GetRoot(),
-		Active:    user.GetActive(),
-		CreatedAt: user.GetCreatedAt().AsTime(),
-		UpdatedAt: user.GetUpdatedAt().AsTime(),
+		ID:             user.GetID(),
+		Email:          user.GetEmail(),
+		Username:       user.GetUsername(),
+		Root:           user.GetRoot(),
+		Active:         user.GetActive(),
+		Status:         user.GetStatus(),
+		StatusReason:   user.GetStatusReason(),
+		SuspendedUntil: optionalTime(user.GetSuspendedUntil()),
+		OrganizationID: user.GetOrganizationID(),
+		OrgAdmin:       user.GetOrgAdmin(),
+		CreatedAt:      user.GetCreatedAt().AsTime(),
+		UpdatedAt:      user.GetUpdatedAt().AsTime(),
+	}
+}
+
+// UserResponseFromCommandGrpc converts a user returned by the command service
+func UserResponseFromCommandGrpc(user *commandService.User) *UserResponse {
+	return &UserResponse{
+		ID:             user.GetID(),
+		Email:          user.GetEmail(),
+		Username:       user.GetUsername(),
+		Root:           user.GetRoot(),
+		Active:         user.GetActive(),
+		Status:         user.GetStatus(),
+		StatusReason:   user.GetStatusReason(),
+		SuspendedUntil: optionalTime(user.GetSuspendedUntil()),
+		OrganizationID: user.GetOrganizationID(),
+		OrgAdmin:       user.GetOrgAdmin(),
+		CreatedAt:      user.GetCreatedAt().AsTime(),
+		UpdatedAt:      user.GetUpdatedAt().AsTime(),
+	}
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
 	}
+	t := ts.AsTime()
+	return &t
 }
 
 // UsersListResponse ...
@@ -13,6 +13,7 @@ type ApiGatewayMetrics struct {
 	CreateUserHttpRequests                 prometheus.Counter
 	UpdateUserHttpRequests                 prometheus.Counter
 	DeleteUserHttpRequests                 prometheus.Counter
+	ChangeUserStatusHttpRequests           prometheus.Counter
 	GetUserByIdHttpRequests                prometheus.Counter
 	SearchUserHttpRequests                 prometheus.Counter
 	CreateGroupHttpRequests                prometheus.Counter
@@ -20,17 +21,42 @@ type ApiGatewayMetrics struct {
 	DeleteGroupHttpRequests                prometheus.Counter
 	GetGroupByIdHttpRequests               prometheus.Counter
 	SearchGroupHttpRequests                prometheus.Counter
+	SetGroupParentHttpRequests             prometheus.Counter
 	CreateMembershipHttpRequests           prometheus.Counter
 	UpdateMembershipHttpRequests           prometheus.Counter
 	DeleteMembershipHttpRequests           prometheus.Counter
+	ExtendMembershipHttpRequests           prometheus.Counter
 	GetMembershipByIdHttpRequests          prometheus.Counter
 	GetUserMembershipByGroupIdHttpRequests prometheus.Counter
 	GetGroupMembershipByUserIdHttpRequests prometheus.Counter
+	GetEffectiveGroupsHttpRequests         prometheus.Counter
+	GetEffectiveMembersHttpRequests        prometheus.Counter
+	CreateOrganizationHttpRequests         prometheus.Counter
+	UpdateOrganizationHttpRequests         prometheus.Counter
+	DeleteOrganizationHttpRequests         prometheus.Counter
+	ChangeOrganizationStatusHttpRequests   prometheus.Counter
+	GetOrganizationByIdHttpRequests        prometheus.Counter
+	SearchOrganizationHttpRequests         prometheus.Counter
+	CreateRoleHttpRequests                 prometheus.Counter
+	UpdateRoleHttpRequests                 prometheus.Counter
+	DeleteRoleHttpRequests                 prometheus.Counter
+	GetRoleByIdHttpRequests                prometheus.Counter
+	SearchRolesHttpRequests                prometheus.Counter
+	CheckHttpRequests                      prometheus.Counter
+	BatchCheckHttpRequests                 prometheus.Counter
+	ListResourcesHttpRequests              prometheus.Counter
 	AuthenticateHttpRequests               prometheus.Counter
 	ValidateHttpRequests                   prometheus.Counter
 	InvalidateHttpRequests                 prometheus.Counter
 	UpdatePasswordHttpRequests             prometheus.Counter
 	RegisterHttpRequests                   prometheus.Counter
+	IntrospectHttpRequests                 prometheus.Counter
+	RevokeHttpRequests                     prometheus.Counter
+	LocalTokenVerifications                prometheus.Counter
+	RemoteTokenValidations                 prometheus.Counter
+	RevokedTokens                          prometheus.Counter
+	RevokedUserSessions                    prometheus.Counter
+	RateLimitedHttpRequests                *prometheus.CounterVec
 }
 
 func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
@@ -55,6 +81,10 @@ func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
 			Name: fmt.Sprintf("%s_delete_user_http_requests_total", cfg.ServiceName),
 			Help: "The total number of delete user http requests",
 		}),
+		ChangeUserStatusHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_change_user_status_http_requests_total", cfg.ServiceName),
+			Help: "The total number of change user status http requests",
+		}),
 		GetUserByIdHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_get_user_by_id_http_requests_total", cfg.ServiceName),
 			Help: "The total number of get user by id http requests",
@@ -83,6 +113,10 @@ func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
 			Name: fmt.Sprintf("%s_search_group_http_requests_total", cfg.ServiceName),
 			Help: "The total number of search group http requests",
 		}),
+		SetGroupParentHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_set_group_parent_http_requests_total", cfg.ServiceName),
+			Help: "The total number of set group parent http requests",
+		}),
 		CreateMembershipHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_create_membership_http_requests_total", cfg.ServiceName),
 			Help: "The total number of create membership http requests",
@@ -95,6 +129,10 @@ func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
 			Name: fmt.Sprintf("%s_delete_membership_http_requests_total", cfg.ServiceName),
 			Help: "The total number of delete membership http requests",
 		}),
+		ExtendMembershipHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_extend_membership_http_requests_total", cfg.ServiceName),
+			Help: "The total number of extend membership http requests",
+		}),
 		GetMembershipByIdHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_get_membership_by_id_http_requests_total", cfg.ServiceName),
 			Help: "The total number of get membership by id http requests",
@@ -107,6 +145,70 @@ func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
 			Name: fmt.Sprintf("%s_get_group_membership_by_user_id_http_requests_total", cfg.ServiceName),
 			Help: "The total number of get group membership by user id http requests",
 		}),
+		GetEffectiveGroupsHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_effective_groups_http_requests_total", cfg.ServiceName),
+			Help: "The total number of get effective groups http requests",
+		}),
+		GetEffectiveMembersHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_effective_members_http_requests_total", cfg.ServiceName),
+			Help: "The total number of get effective members http requests",
+		}),
+		CreateOrganizationHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_create_organization_http_requests_total", cfg.ServiceName),
+			Help: "The total number of create organization http requests",
+		}),
+		UpdateOrganizationHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_update_organization_http_requests_total", cfg.ServiceName),
+			Help: "The total number of update organization http requests",
+		}),
+		DeleteOrganizationHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_delete_organization_http_requests_total", cfg.ServiceName),
+			Help: "The total number of delete organization http requests",
+		}),
+		ChangeOrganizationStatusHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_change_organization_status_http_requests_total", cfg.ServiceName),
+			Help: "The total number of change organization status http requests",
+		}),
+		GetOrganizationByIdHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_organization_by_id_http_requests_total", cfg.ServiceName),
+			Help: "The total number of get organization by id http requests",
+		}),
+		SearchOrganizationHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_search_organization_http_requests_total", cfg.ServiceName),
+			Help: "The total number of search organization http requests",
+		}),
+		CreateRoleHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_create_role_http_requests_total", cfg.ServiceName),
+			Help: "The total number of create role http requests",
+		}),
+		UpdateRoleHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_update_role_http_requests_total", cfg.ServiceName),
+			Help: "The total number of update role http requests",
+		}),
+		DeleteRoleHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_delete_role_http_requests_total", cfg.ServiceName),
+			Help: "The total number of delete role http requests",
+		}),
+		GetRoleByIdHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_role_by_id_http_requests_total", cfg.ServiceName),
+			Help: "The total number of get role by id http requests",
+		}),
+		SearchRolesHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_search_roles_http_requests_total", cfg.ServiceName),
+			Help: "The total number of search roles http requests",
+		}),
+		CheckHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_check_http_requests_total", cfg.ServiceName),
+			Help: "The total number of authorization check http requests",
+		}),
+		BatchCheckHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_batch_check_http_requests_total", cfg.ServiceName),
+			Help: "The total number of batch authorization check http requests",
+		}),
+		ListResourcesHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_list_resources_http_requests_total", cfg.ServiceName),
+			Help: "The total number of list resources http requests",
+		}),
 		AuthenticateHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_authenticate_http_requests_total", cfg.ServiceName),
 			Help: "The total number of authenticate http requests",
@@ -127,5 +229,33 @@ func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
 			Name: fmt.Sprintf("%s_register_http_requests_total", cfg.ServiceName),
 			Help: "The total number of registerhttp requests",
 		}),
+		IntrospectHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_introspect_http_requests_total", cfg.ServiceName),
+			Help: "The total number of token introspection http requests",
+		}),
+		RevokeHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_revoke_http_requests_total", cfg.ServiceName),
+			Help: "The total number of token revocation http requests",
+		}),
+		LocalTokenVerifications: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_local_token_verifications_total", cfg.ServiceName),
+			Help: "The total number of tokens verified locally against the revocation list",
+		}),
+		RemoteTokenValidations: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_remote_token_validations_total", cfg.ServiceName),
+			Help: "The total number of tokens validated remotely by the query service",
+		}),
+		RevokedTokens: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_revoked_tokens_total", cfg.ServiceName),
+			Help: "The total number of revoked tokens consumed into the revocation list",
+		}),
+		RevokedUserSessions: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_revoked_user_sessions_total", cfg.ServiceName),
+			Help: "The total number of user session revocations consumed into the revocation list",
+		}),
+		RateLimitedHttpRequests: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_rate_limited_http_requests_total", cfg.ServiceName),
+			Help: "The total number of http requests rejected by the rate limiter by route group and limit scope",
+		}, []string{"group", "scope"}),
 	}
 }
@@ -1,15 +1,20 @@
 package middlewares
 
 import (
+	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
 	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/enums"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/ratelimit"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,46 +22,131 @@ import (
 type MiddlewareManager interface {
 	RequestLoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc
 	RequestVerifyMiddleware(next echo.HandlerFunc) echo.HandlerFunc
+	RequestVerifyRemoteMiddleware(next echo.HandlerFunc) echo.HandlerFunc
+	RequestVerifyIntegrationMiddleware(next echo.HandlerFunc) echo.HandlerFunc
+	RateLimitMiddleware(next echo.HandlerFunc) echo.HandlerFunc
 }
 
+// RateLimit header fields
+const (
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+)
+
 type middlewareManager struct {
-	log  logging.Logger
-	auth authentication.Authenticator
-	cfg  *config.Config
-	as   *services.AuthService
+	log         logging.Logger
+	auth        authentication.Authenticator
+	cfg         *config.Config
+	as          *services.AuthService
+	revocations *authentication.RevocationList
+	limiter     ratelimit.Limiter
+	metrics     *metrics.ApiGatewayMetrics
 }
 
-func NewMiddlewareManager(log logging.Logger, auth authentication.Authenticator, cfg *config.Config, as *services.AuthService) *middlewareManager {
+func NewMiddlewareManager(
+	log logging.Logger,
+	auth authentication.Authenticator,
+	cfg *config.Config,
+	as *services.AuthService,
+	revocations *authentication.RevocationList,
+	limiter ratelimit.Limiter,
+	metrics *metrics.ApiGatewayMetrics,
+) *middlewareManager {
 	return &middlewareManager{
-		log:  log,
-		auth: auth,
-		cfg:  cfg,
-		as:   as,
+		log:         log,
+		auth:        auth,
+		cfg:         cfg,
+		as:          as,
+		revocations: revocations,
+		limiter:     limiter,
+		metrics:     metrics,
 	}
 }
 
-// RequestVerifyMiddleware ...
+// RequestVerifyMiddleware verifies the request token locally and checks it against the revocation list,
+// falling back to remote validation when the list is disabled or staler than the configured bound
 func (mw *middlewareManager) RequestVerifyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		req := ctx.Request()
-		session, err := mw.auth.AuthorizeREST(req, req.Method+" "+req.URL.String())
+		session, err := mw.auth.AuthorizeREST(req, req.Method+" "+ctx.Path())
 		if err != nil {
 			mw.log.WarnMsg("auth.AuthorizeREST", err)
 			return ctx.JSON(http.StatusUnauthorized, dto.ErrorDTO{Message: err.Error()})
 		}
-		query := queries.NewValidateQuery(session.UserId, req.Header.Get("Authorization"), enums.TOKEN)
-		val, err := mw.as.Queries.Validate.Handle(req.Context(), query)
-		if err != nil {
-			mw.log.WarnMsg("as.Queries.Validate.Handle", err)
-			return ctx.JSON(http.StatusUnauthorized, dto.ErrorDTO{Message: err.Error()})
+		if session == nil {
+			return mw.missingAccessRule(ctx)
 		}
-		if val.Status != 200 {
+		if !mw.cfg.Revocation.Enabled || mw.revocations == nil || !mw.revocations.Fresh(mw.cfg.Revocation.MaxStaleness) {
+			return mw.validateRemote(ctx, session, next)
+		}
+		mw.metrics.LocalTokenVerifications.Inc()
+		issuedAt := time.Unix(session.IssuedAt, 0)
+		if mw.revocations.IsRevoked(req.Header.Get("Authorization")) || mw.revocations.IsSessionRevoked(session.UserId, issuedAt) ||
+			mw.revocations.IsOrganizationSessionRevoked(session.OrganizationId, issuedAt) {
 			return ctx.JSON(http.StatusUnauthorized, dto.ErrorDTO{Message: "unauthorized"})
 		}
+		ctx.SetRequest(req.WithContext(withSession(req.Context(), session)))
 		return next(ctx)
 	}
 }
 
+// RequestVerifyRemoteMiddleware always validates the request token with the query service
+func (mw *middlewareManager) RequestVerifyRemoteMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		session, err := mw.auth.AuthorizeREST(req, req.Method+" "+ctx.Path())
+		if err != nil {
+			mw.log.WarnMsg("auth.AuthorizeREST", err)
+			return ctx.JSON(http.StatusUnauthorized, dto.ErrorDTO{Message: err.Error()})
+		}
+		if session == nil {
+			return mw.missingAccessRule(ctx)
+		}
+		return mw.validateRemote(ctx, session, next)
+	}
+}
+
+// RequestVerifyIntegrationMiddleware requires a remotely validated INTEGRATION token
+func (mw *middlewareManager) RequestVerifyIntegrationMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		session, err := mw.auth.AuthorizeREST(req, req.Method+" "+ctx.Path())
+		if err != nil {
+			mw.log.WarnMsg("auth.AuthorizeREST", err)
+			return ctx.JSON(http.StatusUnauthorized, dto.OAuthErrorDTO{Error: "invalid_client"})
+		}
+		if session == nil || session.Type != enums.INTEGRATION {
+			return ctx.JSON(http.StatusUnauthorized, dto.OAuthErrorDTO{Error: "invalid_client"})
+		}
+		return mw.validateRemote(ctx, session, next)
+	}
+}
+
+// missingAccessRule rejects a request to a verified route without an access rule, which is a misconfiguration that
+// must not leave the route unprotected
+func (mw *middlewareManager) missingAccessRule(ctx echo.Context) error {
+	req := ctx.Request()
+	mw.log.WithContext(req.Context()).Errorf("no access rule for verified route: %s %s", req.Method, ctx.Path())
+	return ctx.JSON(http.StatusInternalServerError, dto.ErrorDTO{Message: "internal server error"})
+}
+
+func (mw *middlewareManager) validateRemote(ctx echo.Context, session *authentication.Session, next echo.HandlerFunc) error {
+	mw.metrics.RemoteTokenValidations.Inc()
+	req := ctx.Request()
+	query := queries.NewValidateQuery(session.UserId, req.Header.Get("Authorization"), enums.TOKEN)
+	val, err := mw.as.Queries.Validate.Handle(req.Context(), query)
+	if err != nil {
+		mw.log.WarnMsg("as.Queries.Validate.Handle", err)
+		return ctx.JSON(http.StatusUnauthorized, dto.ErrorDTO{Message: err.Error()})
+	}
+	if val.Status != 200 {
+		return ctx.JSON(http.StatusUnauthorized, dto.ErrorDTO{Message: "unauthorized"})
+	}
+	ctx.SetRequest(req.WithContext(withSession(req.Context(), session)))
+	return next(ctx)
+}
+
 func (mw *middlewareManager) RequestLoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		start := time.Now()
@@ -67,12 +157,117 @@ func (mw *middlewareManager) RequestLoggerMiddleware(next echo.HandlerFunc) echo
 		size := res.Size
 		s := time.Since(start)
 		if !mw.checkIgnoredURI(ctx.Request().RequestURI, mw.cfg.Http.IgnoreLogUrls) {
-			mw.log.HttpMiddlewareAccessLogger(req.Method, req.URL.String(), status, size, s)
+			mw.log.HttpMiddlewareAccessLogger(req.Context(), req.Method, req.URL.String(), status, size, s)
 		}
 		return err
 	}
 }
 
+// RateLimitMiddleware limits the requests of the route group matching the route per client IP, per authenticated
+// user and per API client. Limited requests carry the RateLimit header fields of their tightest limit and rejected
+// ones are answered with 429 Too Many Requests, giving back what they took from their other limits so that a user or
+// client over its limit does not drain the limit of its IP; requests are let through while the limiter fails.
+func (mw *middlewareManager) RateLimitMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if mw.limiter == nil {
+			return next(ctx)
+		}
+		req := ctx.Request()
+		group := mw.cfg.RateLimit.Match(req.Method, ctx.Path())
+		if group == nil {
+			return next(ctx)
+		}
+		var tightest *ratelimit.Result
+		limits := mw.scopedLimits(ctx, group)
+		for i, limit := range limits {
+			res, err := mw.limiter.Allow(req.Context(), limit.key(group), limit.limit)
+			if err != nil {
+				mw.log.WithContext(req.Context()).WarnMsg("limiter.Allow", err)
+				limits[i].failed = true
+				continue
+			}
+			if !res.Allowed {
+				mw.refund(req.Context(), group, limits[:i])
+				mw.metrics.RateLimitedHttpRequests.WithLabelValues(group.Name, limit.scope).Inc()
+				setRateLimitHeaders(ctx.Response().Header(), res)
+				ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				return ctx.JSON(http.StatusTooManyRequests, dto.ErrorDTO{Message: "too many requests"})
+			}
+			if tightest == nil || res.Remaining < tightest.Remaining {
+				tightest = res
+			}
+		}
+		if tightest != nil {
+			setRateLimitHeaders(ctx.Response().Header(), tightest)
+		}
+		return next(ctx)
+	}
+}
+
+// refund gives the request back to the limits that allowed it
+func (mw *middlewareManager) refund(ctx context.Context, group *ratelimit.Group, limits []scopedLimit) {
+	for _, limit := range limits {
+		if limit.failed {
+			continue
+		}
+		if err := mw.limiter.Refund(ctx, limit.key(group), limit.limit); err != nil {
+			mw.log.WithContext(ctx).WarnMsg("limiter.Refund", err)
+		}
+	}
+}
+
+// scopedLimit is a limit of a route group applied to one client IP, user or API client
+type scopedLimit struct {
+	scope  string
+	id     string
+	limit  ratelimit.Limit
+	failed bool
+}
+
+// key returns the limiter key of the limit in group
+func (l scopedLimit) key(group *ratelimit.Group) string {
+	return group.Name + ":" + l.scope + ":" + l.id
+}
+
+// scopedLimits returns the enabled limits of group applying to the request. The user and client limits key on the
+// session of the request token; an invalid token leaves only the IP limit, the route rejects the request anyway.
+func (mw *middlewareManager) scopedLimits(ctx echo.Context, group *ratelimit.Group) []scopedLimit {
+	limits := make([]scopedLimit, 0, 2)
+	if group.IP.Enabled() {
+		limits = append(limits, scopedLimit{scope: ratelimit.ScopeIP, id: ctx.RealIP(), limit: group.IP})
+	}
+	if !group.User.Enabled() && !group.Client.Enabled() {
+		return limits
+	}
+	accessToken := ctx.Request().Header.Get("Authorization")
+	if accessToken == "" {
+		return limits
+	}
+	session, err := mw.auth.GetTokenSession(accessToken)
+	if err != nil {
+		return limits
+	}
+	if session.Type == enums.INTEGRATION {
+		if group.Client.Enabled() {
+			limits = append(limits, scopedLimit{scope: ratelimit.ScopeClient, id: session.UserId, limit: group.Client})
+		}
+	} else if group.User.Enabled() {
+		limits = append(limits, scopedLimit{scope: ratelimit.ScopeUser, id: session.UserId, limit: group.User})
+	}
+	return limits
+}
+
+func setRateLimitHeaders(header http.Header, res *ratelimit.Result) {
+	header.Set(headerRateLimitLimit, strconv.Itoa(res.Limit))
+	header.Set(headerRateLimitRemaining, strconv.Itoa(res.Remaining))
+	header.Set(headerRateLimitReset, strconv.Itoa(ceilSeconds(res.ResetAfter)))
+}
+
+// ceilSeconds rounds d up to whole seconds, as the RateLimit-Reset and Retry-After fields carry
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
 func (mw *middlewareManager) checkIgnoredURI(requestURI string, uriList []string) bool {
 	for _, s := range uriList {
 		if strings.Contains(requestURI, s) {
@@ -81,3 +276,10 @@ func (mw *middlewareManager) checkIgnoredURI(requestURI string, uriList []string
 	}
 	return false
 }
+
+// withSession scopes ctx to the authenticated user, as the actor of its commands and the user of its log entries, and
+// to the tenant of its session
+func withSession(ctx context.Context, session *authentication.Session) context.Context {
+	ctx = authentication.ContextWithSession(ctx, session)
+	return logging.ContextWithUserID(kafkaClient.ContextWithActor(ctx, session.UserId), session.UserId)
+}
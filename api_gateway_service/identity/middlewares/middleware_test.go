@@ -0,0 +1,191 @@
+package middlewares
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	kafkaConsumer "github.com/JECSand/identity-service/api_gateway_service/identity/delivery/kafka"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/JECSand/identity-service/pkg/ratelimit"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMiddlewareRejectsWithHeaders(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "middleware_test",
+		RateLimit: ratelimit.Config{
+			Enabled: true,
+			Groups: []ratelimit.Group{{
+				Name: "default",
+				IP:   ratelimit.Limit{Rate: 1, Period: time.Minute},
+			}},
+		},
+	}
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	mw := NewMiddlewareManager(log, nil, cfg, nil, nil, ratelimit.NewMemoryLimiter(), metrics.NewApiGatewayMetrics(cfg))
+	e := echo.New()
+	e.GET("/limited", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, mw.RateLimitMiddleware)
+
+	tests := []struct {
+		status     int
+		remaining  string
+		retryAfter string
+	}{
+		{status: http.StatusOK, remaining: "0"},
+		{status: http.StatusTooManyRequests, remaining: "0", retryAfter: "60"},
+	}
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/limited", nil))
+		if rec.Code != tt.status {
+			t.Fatalf("request %d: got status %d, want %d", i, rec.Code, tt.status)
+		}
+		header := rec.Header()
+		if got := header.Get(headerRateLimitLimit); got != "1" {
+			t.Errorf("request %d: got %s %q, want %q", i, headerRateLimitLimit, got, "1")
+		}
+		if got := header.Get(headerRateLimitRemaining); got != tt.remaining {
+			t.Errorf("request %d: got %s %q, want %q", i, headerRateLimitRemaining, got, tt.remaining)
+		}
+		if got := header.Get(headerRateLimitReset); got != "60" {
+			t.Errorf("request %d: got %s %q, want %q", i, headerRateLimitReset, got, "60")
+		}
+		if got := header.Get(echo.HeaderRetryAfter); got != tt.retryAfter {
+			t.Errorf("request %d: got %s %q, want %q", i, echo.HeaderRetryAfter, got, tt.retryAfter)
+		}
+	}
+}
+
+func TestRateLimitMiddlewareRefundsRejectedRequests(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "middleware_refund_test",
+		RateLimit: ratelimit.Config{
+			Enabled: true,
+			Groups: []ratelimit.Group{{
+				Name: "default",
+				IP:   ratelimit.Limit{Rate: 2, Period: time.Minute},
+				User: ratelimit.Limit{Rate: 1, Period: time.Minute},
+			}},
+		},
+	}
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	auth := authentication.NewAuthenticator(log, nil, authentication.NewAuthConfig(1, 1, "secret"))
+	mw := NewMiddlewareManager(log, auth, cfg, nil, nil, ratelimit.NewMemoryLimiter(), metrics.NewApiGatewayMetrics(cfg))
+	e := echo.New()
+	e.GET("/limited", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, mw.RateLimitMiddleware)
+	tokens := make(map[string]string)
+	for _, user := range []string{"alice", "bob", "carol"} {
+		token, err := auth.NewSession(user, false, enums.USER).NewToken()
+		if err != nil {
+			t.Fatalf("NewToken: %v", err)
+		}
+		tokens[user] = token
+	}
+
+	// every request comes from one address; alice going over her own limit leaves the address budget to bob
+	tests := []struct {
+		user   string
+		status int
+	}{
+		{user: "alice", status: http.StatusOK},
+		{user: "alice", status: http.StatusTooManyRequests},
+		{user: "alice", status: http.StatusTooManyRequests},
+		{user: "bob", status: http.StatusOK},
+		{user: "carol", status: http.StatusTooManyRequests},
+	}
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.Header.Set("Authorization", tokens[tt.user])
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("request %d of %s: got status %d, want %d", i, tt.user, rec.Code, tt.status)
+		}
+	}
+}
+
+func TestRequestVerifyMiddlewareRejectsDeletedUsers(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "middleware_revocation_test",
+		Revocation: config.Revocation{
+			Enabled:           true,
+			MaxStaleness:      time.Minute,
+			ExpectedTokens:    100,
+			FalsePositiveRate: 0.001,
+			SyncInterval:      10 * time.Millisecond,
+		},
+	}
+	cfg.KafkaTopics.TokenBlacklisted.TopicName = "token_blacklisted"
+	cfg.KafkaTopics.UserStatusChanged.TopicName = "user_status_changed"
+	cfg.KafkaTopics.UserDeleted.TopicName = "user_deleted"
+	cfg.KafkaTopics.OrganizationStatusChanged.TopicName = "organization_status_changed"
+	cfg.KafkaTopics.OrganizationDeleted.TopicName = "organization_deleted"
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auth := authentication.NewAuthenticator(log, map[string]enums.Role{"GET /protected": enums.MEMBER}, authentication.NewAuthConfig(1, 1, "secret"))
+	revocations := authentication.NewRevocationList(cfg.Revocation.ExpectedTokens, cfg.Revocation.FalsePositiveRate)
+	m := metrics.NewApiGatewayMetrics(cfg)
+	bus := messaging.NewMemoryBus(1)
+	if err := kafkaConsumer.NewRevocationConsumer(log, cfg, auth, revocations, bus, m).Run(ctx); err != nil {
+		t.Fatalf("revocationConsumer.Run: %v", err)
+	}
+	// the query service is left out, so any request falling back to remote validation fails the test with a panic
+	mw := NewMiddlewareManager(log, auth, cfg, nil, revocations, nil, m)
+	e := echo.New()
+	e.GET("/protected", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, mw.RequestVerifyMiddleware)
+	userID := uuid.Must(uuid.NewV4()).String()
+	token, err := auth.NewSession(userID, false, enums.USER).NewToken()
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	request := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	waitFor(t, func() bool { return revocations.Fresh(cfg.Revocation.MaxStaleness) })
+	if code := request(); code != http.StatusOK {
+		t.Fatalf("before deletion: got status %d, want %d", code, http.StatusOK)
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, cfg.KafkaTopics.UserDeleted.TopicName, kafkaClient.UserDeletedEvent, userID, &kafkaMessages.UserDeleted{ID: userID}, nil)
+	if err != nil {
+		t.Fatalf("NewEventMessage: %v", err)
+	}
+	if err = bus.Publish(ctx, message); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	waitFor(t, func() bool { return request() == http.StatusUnauthorized })
+}
+
+func waitFor(t *testing.T, check func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !check() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 5s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -3,24 +3,29 @@ package queries
 import "github.com/JECSand/identity-service/pkg/enums"
 
 type AuthQueries struct {
-	Authenticate AuthenticateHandler
-	Validate     ValidateHandler
+	Authenticate  AuthenticateHandler
+	Validate      ValidateHandler
+	CheckPassword CheckPasswordHandler
 }
 
-func NewAuthQueries(authenticate AuthenticateHandler, validate ValidateHandler) *AuthQueries {
+func NewAuthQueries(authenticate AuthenticateHandler, validate ValidateHandler, checkPassword CheckPasswordHandler) *AuthQueries {
 	return &AuthQueries{
-		Authenticate: authenticate,
-		Validate:     validate,
+		Authenticate:  authenticate,
+		Validate:      validate,
+		CheckPassword: checkPassword,
 	}
 }
 
+// AuthenticateQuery verifies a password for the user identified by ID, or else by Email
 type AuthenticateQuery struct {
-	Email    string `json:"email" validate:"required,gte=0,lte=255"`
+	ID       string `json:"id"`
+	Email    string `json:"email" validate:"required_without=ID,lte=255"`
 	Password string `json:"password" validate:"required,gte=0,lte=255"`
 }
 
-func NewAuthenticateQuery(email string, password string) *AuthenticateQuery {
+func NewAuthenticateQuery(id string, email string, password string) *AuthenticateQuery {
 	return &AuthenticateQuery{
+		ID:       id,
 		Email:    email,
 		Password: password,
 	}
@@ -39,3 +44,21 @@ func NewValidateQuery(userID string, accessToken string, valType enums.Validatio
 		ValidationType: valType,
 	}
 }
+
+// CheckPasswordQuery checks a new password against the password policy; ID is set for existing users, whose
+// password history is then checked too
+type CheckPasswordQuery struct {
+	ID       string
+	Email    string
+	Username string
+	Password string
+}
+
+func NewCheckPasswordQuery(id string, email string, username string, password string) *CheckPasswordQuery {
+	return &CheckPasswordQuery{
+		ID:       id,
+		Email:    email,
+		Username: username,
+		Password: password,
+	}
+}
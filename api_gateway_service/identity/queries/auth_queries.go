@@ -4,10 +4,11 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	authCommandService "github.com/JECSand/identity-service/command_service/protos/auth_command"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	authQueryService "github.com/JECSand/identity-service/query_service/protos/auth_query"
-	"github.com/opentracing/opentracing-go"
 )
 
 type AuthenticateHandler interface {
@@ -17,29 +18,29 @@ type AuthenticateHandler interface {
 type authenticateHandler struct {
 	log      logging.Logger
 	cfg      *config.Config
-	rsClient authQueryService.AuthQueryServiceClient
+	csClient authCommandService.AuthCommandServiceClient
 }
 
-func NewAuthenticateHandler(log logging.Logger, cfg *config.Config, rsClient authQueryService.AuthQueryServiceClient) *authenticateHandler {
+func NewAuthenticateHandler(log logging.Logger, cfg *config.Config, csClient authCommandService.AuthCommandServiceClient) *authenticateHandler {
 	return &authenticateHandler{
 		log:      log,
 		cfg:      cfg,
-		rsClient: rsClient,
+		csClient: csClient,
 	}
 }
 
 func (q *authenticateHandler) Handle(ctx context.Context, query *AuthenticateQuery) (*dto.AuthenticateResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "authenticateHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
-	res, err := q.rsClient.Authenticate(ctx, &authQueryService.AuthenticateReq{
+	ctx, span := tracing.StartSpan(ctx, "authenticateHandler.Handle")
+	defer span.End()
+	res, err := q.csClient.Authenticate(ctx, &authCommandService.AuthenticateReq{
+		ID:       query.ID,
 		Email:    query.Email,
 		Password: query.Password,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return dto.AuthenticateResponseResponseFromGrpc(res), nil
+	return dto.AuthenticateResponseFromCommandGrpc(res), nil
 }
 
 // ValidateHandler ...
@@ -62,9 +63,8 @@ func NewValidateHandler(log logging.Logger, cfg *config.Config, rsClient authQue
 }
 
 func (s *validateHandler) Handle(ctx context.Context, query *ValidateQuery) (*dto.ValidateResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "validateHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	ctx, span := tracing.StartSpan(ctx, "validateHandler.Handle")
+	defer span.End()
 	res, err := s.rsClient.Validate(ctx, &authQueryService.ValidateReq{
 		UserID:         query.UserID,
 		AccessToken:    query.AccessToken,
@@ -75,3 +75,45 @@ func (s *validateHandler) Handle(ctx context.Context, query *ValidateQuery) (*dt
 	}
 	return dto.ValidateResponseResponseFromGrpc(res), nil
 }
+
+// CheckPasswordHandler ...
+type CheckPasswordHandler interface {
+	Handle(ctx context.Context, query *CheckPasswordQuery) error
+}
+
+type checkPasswordHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	csClient authCommandService.AuthCommandServiceClient
+}
+
+func NewCheckPasswordHandler(log logging.Logger, cfg *config.Config, csClient authCommandService.AuthCommandServiceClient) *checkPasswordHandler {
+	return &checkPasswordHandler{
+		log:      log,
+		cfg:      cfg,
+		csClient: csClient,
+	}
+}
+
+// Handle returns an *authentication.PasswordPolicyError listing the violated rules when the password fails the policy
+func (q *checkPasswordHandler) Handle(ctx context.Context, query *CheckPasswordQuery) error {
+	ctx, span := tracing.StartSpan(ctx, "checkPasswordHandler.Handle")
+	defer span.End()
+	res, err := q.csClient.CheckPassword(ctx, &authCommandService.CheckPasswordReq{
+		ID:       query.ID,
+		Email:    query.Email,
+		Username: query.Username,
+		Password: query.Password,
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.GetViolations()) == 0 {
+		return nil
+	}
+	policyErr := &authentication.PasswordPolicyError{Violations: make([]authentication.PasswordViolation, len(res.GetViolations()))}
+	for i, violation := range res.GetViolations() {
+		policyErr.Violations[i] = authentication.PasswordViolation{Rule: violation.GetRule(), Message: violation.GetMessage()}
+	}
+	return policyErr
+}
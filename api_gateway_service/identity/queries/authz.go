@@ -0,0 +1,41 @@
+package queries
+
+import "github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+
+type AuthzQueries struct {
+	Check         CheckHandler
+	BatchCheck    BatchCheckHandler
+	ListResources ListResourcesHandler
+}
+
+func NewAuthzQueries(check CheckHandler, batchCheck BatchCheckHandler, listResources ListResourcesHandler) *AuthzQueries {
+	return &AuthzQueries{
+		Check:         check,
+		BatchCheck:    batchCheck,
+		ListResources: listResources,
+	}
+}
+
+type CheckQuery struct {
+	CheckDto *dto.CheckDTO
+}
+
+func NewCheckQuery(checkDto *dto.CheckDTO) *CheckQuery {
+	return &CheckQuery{CheckDto: checkDto}
+}
+
+type BatchCheckQuery struct {
+	BatchDto *dto.BatchCheckDTO
+}
+
+func NewBatchCheckQuery(batchDto *dto.BatchCheckDTO) *BatchCheckQuery {
+	return &BatchCheckQuery{BatchDto: batchDto}
+}
+
+type ListResourcesQuery struct {
+	ListDto *dto.ListResourcesDTO
+}
+
+func NewListResourcesQuery(listDto *dto.ListResourcesDTO) *ListResourcesQuery {
+	return &ListResourcesQuery{ListDto: listDto}
+}
@@ -0,0 +1,150 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	authzQueryService "github.com/JECSand/identity-service/query_service/protos/authz_query"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// subjectOf returns the subject a request checks, defaulting to the caller. Integration and admin sessions may check
+// any subject of their tenant; member sessions naming another subject get authentication.ErrOrgAdminRequired.
+func subjectOf(ctx context.Context, subjectID uuid.UUID) (string, error) {
+	session, ok := authentication.SessionFromContext(ctx)
+	if !ok {
+		return "", errors.New("authorization checks require a session")
+	}
+	if subjectID == uuid.Nil {
+		return session.UserId, nil
+	}
+	if session.Type != enums.INTEGRATION && !authentication.AdminFromContext(ctx) && subjectID != uuid.FromStringOrNil(session.UserId) {
+		return "", authentication.ErrOrgAdminRequired
+	}
+	return subjectID.String(), nil
+}
+
+func newCheckReq(ctx context.Context, checkDto *dto.CheckDTO) (*authzQueryService.CheckReq, error) {
+	subjectID, err := subjectOf(ctx, checkDto.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	return &authzQueryService.CheckReq{
+		SubjectID:  subjectID,
+		Action:     checkDto.Action,
+		ResourceID: checkDto.ResourceID.String(),
+		TenantID:   authentication.TenantFromContext(ctx),
+	}, nil
+}
+
+// CheckHandler ...
+type CheckHandler interface {
+	Handle(ctx context.Context, query *CheckQuery) (*dto.DecisionResponse, error)
+}
+
+type checkHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	azClient authzQueryService.AuthzQueryServiceClient
+}
+
+func NewCheckHandler(log logging.Logger, cfg *config.Config, azClient authzQueryService.AuthzQueryServiceClient) *checkHandler {
+	return &checkHandler{
+		log:      log,
+		cfg:      cfg,
+		azClient: azClient,
+	}
+}
+
+func (q *checkHandler) Handle(ctx context.Context, query *CheckQuery) (*dto.DecisionResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "checkHandler.Handle")
+	defer span.End()
+	req, err := newCheckReq(ctx, query.CheckDto)
+	if err != nil {
+		return nil, err
+	}
+	res, err := q.azClient.Check(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return dto.DecisionResponseFromGrpc(res.GetDecision()), nil
+}
+
+// BatchCheckHandler ...
+type BatchCheckHandler interface {
+	Handle(ctx context.Context, query *BatchCheckQuery) (*dto.BatchCheckResponse, error)
+}
+
+type batchCheckHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	azClient authzQueryService.AuthzQueryServiceClient
+}
+
+func NewBatchCheckHandler(log logging.Logger, cfg *config.Config, azClient authzQueryService.AuthzQueryServiceClient) *batchCheckHandler {
+	return &batchCheckHandler{
+		log:      log,
+		cfg:      cfg,
+		azClient: azClient,
+	}
+}
+
+func (q *batchCheckHandler) Handle(ctx context.Context, query *BatchCheckQuery) (*dto.BatchCheckResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "batchCheckHandler.Handle")
+	defer span.End()
+	req := &authzQueryService.BatchCheckReq{TenantID: authentication.TenantFromContext(ctx)}
+	for _, checkDto := range query.BatchDto.Checks {
+		check, err := newCheckReq(ctx, checkDto)
+		if err != nil {
+			return nil, err
+		}
+		req.Checks = append(req.Checks, check)
+	}
+	res, err := q.azClient.BatchCheck(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return dto.BatchCheckResponseFromGrpc(res), nil
+}
+
+// ListResourcesHandler ...
+type ListResourcesHandler interface {
+	Handle(ctx context.Context, query *ListResourcesQuery) (*dto.ResourcesResponse, error)
+}
+
+type listResourcesHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	azClient authzQueryService.AuthzQueryServiceClient
+}
+
+func NewListResourcesHandler(log logging.Logger, cfg *config.Config, azClient authzQueryService.AuthzQueryServiceClient) *listResourcesHandler {
+	return &listResourcesHandler{
+		log:      log,
+		cfg:      cfg,
+		azClient: azClient,
+	}
+}
+
+func (q *listResourcesHandler) Handle(ctx context.Context, query *ListResourcesQuery) (*dto.ResourcesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "listResourcesHandler.Handle")
+	defer span.End()
+	subjectID, err := subjectOf(ctx, query.ListDto.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := q.azClient.ListResources(ctx, &authzQueryService.ListResourcesReq{
+		SubjectID: subjectID,
+		Action:    query.ListDto.Action,
+		TenantID:  authentication.TenantFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.ResourcesResponseFromGrpc(res), nil
+}
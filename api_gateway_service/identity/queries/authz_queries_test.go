@@ -0,0 +1,42 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestSubjectOf(t *testing.T) {
+	caller, other := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	tests := []struct {
+		name      string
+		session   *authentication.Session
+		subjectID uuid.UUID
+		want      string
+		wantErr   error
+	}{
+		{name: "member defaults to itself", session: &authentication.Session{UserId: caller.String(), Type: enums.USER}, want: caller.String()},
+		{name: "member names itself", session: &authentication.Session{UserId: caller.String(), Type: enums.USER}, subjectID: caller, want: caller.String()},
+		{name: "member names another subject", session: &authentication.Session{UserId: caller.String(), Type: enums.USER}, subjectID: other, wantErr: authentication.ErrOrgAdminRequired},
+		{name: "organization admin names another subject", session: &authentication.Session{UserId: caller.String(), Type: enums.USER, OrgAdmin: true}, subjectID: other, want: other.String()},
+		{name: "root names another subject", session: &authentication.Session{UserId: caller.String(), Type: enums.USER, RootAdmin: true}, subjectID: other, want: other.String()},
+		{name: "integration names another subject", session: &authentication.Session{UserId: caller.String(), Type: enums.INTEGRATION}, subjectID: other, want: other.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := subjectOf(authentication.ContextWithSession(context.Background(), tt.session), tt.subjectID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("subjectOf: got error %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("subjectOf = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := subjectOf(context.Background(), other); err == nil {
+		t.Error("subjectOf accepted a request without a session")
+	}
+}
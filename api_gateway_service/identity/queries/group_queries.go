@@ -4,10 +4,10 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	groupQueryService "github.com/JECSand/identity-service/query_service/protos/group_query"
-	"github.com/opentracing/opentracing-go"
 )
 
 type GetGroupByIdHandler interface {
@@ -29,10 +29,12 @@ func NewGetGroupByIdHandler(log logging.Logger, cfg *config.Config, rsClient gro
 }
 
 func (q *getGroupByIdHandler) Handle(ctx context.Context, query *GetGroupByIdQuery) (*dto.GroupResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getGroupByIdHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
-	res, err := q.rsClient.GetGroupById(ctx, &groupQueryService.GetGroupByIdReq{ID: query.ID.String()})
+	ctx, span := tracing.StartSpan(ctx, "getGroupByIdHandler.Handle")
+	defer span.End()
+	res, err := q.rsClient.GetGroupById(ctx, &groupQueryService.GetGroupByIdReq{
+		ID:       query.ID.String(),
+		TenantID: authentication.TenantFromContext(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -59,13 +61,13 @@ func NewSearchGroupHandler(log logging.Logger, cfg *config.Config, rsClient grou
 }
 
 func (s *searchGroupHandler) Handle(ctx context.Context, query *SearchGroupQuery) (*dto.GroupsListResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "searchGroupHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	ctx, span := tracing.StartSpan(ctx, "searchGroupHandler.Handle")
+	defer span.End()
 	res, err := s.rsClient.SearchGroup(ctx, &groupQueryService.SearchGroupReq{
-		Search: query.Text,
-		Page:   int64(query.Pagination.GetPage()),
-		Size:   int64(query.Pagination.GetSize()),
+		Search:   query.Text,
+		Page:     int64(query.Pagination.GetPage()),
+		Size:     int64(query.Pagination.GetSize()),
+		TenantID: authentication.TenantFromContext(ctx),
 	})
 	if err != nil {
 		return nil, err
@@ -9,13 +9,23 @@ type MembershipQueries struct {
 	GetMembershipById          GetMembershipByIdHandler
 	GetUserMembershipByGroupId GetUserMembershipByGroupIdHandler
 	GetGroupMembershipByUserId GetGroupMembershipByUserIdHandler
+	GetEffectiveGroups         GetEffectiveGroupsHandler
+	GetEffectiveMembers        GetEffectiveMembersHandler
 }
 
-func NewMembershipQueries(getById GetMembershipByIdHandler, getUserMembership GetUserMembershipByGroupIdHandler, getGroupMembership GetGroupMembershipByUserIdHandler) *MembershipQueries {
+func NewMembershipQueries(
+	getById GetMembershipByIdHandler,
+	getUserMembership GetUserMembershipByGroupIdHandler,
+	getGroupMembership GetGroupMembershipByUserIdHandler,
+	getEffectiveGroups GetEffectiveGroupsHandler,
+	getEffectiveMembers GetEffectiveMembersHandler,
+) *MembershipQueries {
 	return &MembershipQueries{
 		GetMembershipById:          getById,
 		GetUserMembershipByGroupId: getUserMembership,
 		GetGroupMembershipByUserId: getGroupMembership,
+		GetEffectiveGroups:         getEffectiveGroups,
+		GetEffectiveMembers:        getEffectiveMembers,
 	}
 }
 
@@ -50,3 +60,27 @@ func NewGetGroupMembershipByUserIdQuery(userId uuid.UUID, pagination *utilities.
 		Pagination: pagination,
 	}
 }
+
+type GetEffectiveGroupsQuery struct {
+	UserID     uuid.UUID             `json:"userID" validate:"required,gte=0,lte=255"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewGetEffectiveGroupsQuery(userId uuid.UUID, pagination *utilities.Pagination) *GetEffectiveGroupsQuery {
+	return &GetEffectiveGroupsQuery{
+		UserID:     userId,
+		Pagination: pagination,
+	}
+}
+
+type GetEffectiveMembersQuery struct {
+	GroupID    uuid.UUID             `json:"groupID" validate:"required,gte=0,lte=255"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewGetEffectiveMembersQuery(groupId uuid.UUID, pagination *utilities.Pagination) *GetEffectiveMembersQuery {
+	return &GetEffectiveMembersQuery{
+		GroupID:    groupId,
+		Pagination: pagination,
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	membershipQueryService "github.com/JECSand/identity-service/query_service/protos/membership_query"
-	"github.com/opentracing/opentracing-go"
 )
 
 type GetMembershipByIdHandler interface {
@@ -29,10 +29,12 @@ func NewGetMembershipByIdHandler(log logging.Logger, cfg *config.Config, rsClien
 }
 
 func (q *getMembershipByIdHandler) Handle(ctx context.Context, query *GetMembershipByIdQuery) (*dto.MembershipResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getMembershipByIdHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
-	res, err := q.rsClient.GetMembershipById(ctx, &membershipQueryService.GetMembershipByIdReq{ID: query.ID.String()})
+	ctx, span := tracing.StartSpan(ctx, "getMembershipByIdHandler.Handle")
+	defer span.End()
+	res, err := q.rsClient.GetMembershipById(ctx, &membershipQueryService.GetMembershipByIdReq{
+		ID:       query.ID.String(),
+		TenantID: authentication.TenantFromContext(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -59,13 +61,13 @@ func NewGetUserMembershipByGroupIHandler(log logging.Logger, cfg *config.Config,
 }
 
 func (s *getUserMembershipByGroupIdHandler) Handle(ctx context.Context, query *GetUserMembershipByGroupIdQuery) (*dto.UserMembershipsListResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getUserMembershipByGroupIdHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	ctx, span := tracing.StartSpan(ctx, "getUserMembershipByGroupIdHandler.Handle")
+	defer span.End()
 	res, err := s.rsClient.GetUserMembership(ctx, &membershipQueryService.GetUserMembershipReq{
-		GroupID: query.GroupID.String(),
-		Page:    int64(query.Pagination.GetPage()),
-		Size:    int64(query.Pagination.GetSize()),
+		GroupID:  query.GroupID.String(),
+		Page:     int64(query.Pagination.GetPage()),
+		Size:     int64(query.Pagination.GetSize()),
+		TenantID: authentication.TenantFromContext(ctx),
 	})
 	if err != nil {
 		return nil, err
@@ -93,16 +95,84 @@ func NewGetGroupMembershipByUserIdHandler(log logging.Logger, cfg *config.Config
 }
 
 func (s *getGroupMembershipByUserIdHandler) Handle(ctx context.Context, query *GetGroupMembershipByUserIdQuery) (*dto.GroupMembershipsListResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getGroupMembershipByUserIdHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	ctx, span := tracing.StartSpan(ctx, "getGroupMembershipByUserIdHandler.Handle")
+	defer span.End()
 	res, err := s.rsClient.GetGroupMembership(ctx, &membershipQueryService.GetGroupMembershipReq{
-		UserID: query.UserID.String(),
-		Page:   int64(query.Pagination.GetPage()),
-		Size:   int64(query.Pagination.GetSize()),
+		UserID:   query.UserID.String(),
+		Page:     int64(query.Pagination.GetPage()),
+		Size:     int64(query.Pagination.GetSize()),
+		TenantID: authentication.TenantFromContext(ctx),
 	})
 	if err != nil {
 		return nil, err
 	}
 	return dto.GroupMembershipListResponseFromGrpc(res), nil
 }
+
+// GetEffectiveGroupsHandler ...
+type GetEffectiveGroupsHandler interface {
+	Handle(ctx context.Context, query *GetEffectiveGroupsQuery) (*dto.EffectiveMembershipsListResponse, error)
+}
+
+type getEffectiveGroupsHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	rsClient membershipQueryService.MembershipQueryServiceClient
+}
+
+func NewGetEffectiveGroupsHandler(log logging.Logger, cfg *config.Config, rsClient membershipQueryService.MembershipQueryServiceClient) *getEffectiveGroupsHandler {
+	return &getEffectiveGroupsHandler{
+		log:      log,
+		cfg:      cfg,
+		rsClient: rsClient,
+	}
+}
+
+func (s *getEffectiveGroupsHandler) Handle(ctx context.Context, query *GetEffectiveGroupsQuery) (*dto.EffectiveMembershipsListResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "getEffectiveGroupsHandler.Handle")
+	defer span.End()
+	res, err := s.rsClient.GetEffectiveGroups(ctx, &membershipQueryService.GetEffectiveGroupsReq{
+		UserID:   query.UserID.String(),
+		Page:     int64(query.Pagination.GetPage()),
+		Size:     int64(query.Pagination.GetSize()),
+		TenantID: authentication.TenantFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.EffectiveMembershipListResponseFromGrpc(res), nil
+}
+
+// GetEffectiveMembersHandler ...
+type GetEffectiveMembersHandler interface {
+	Handle(ctx context.Context, query *GetEffectiveMembersQuery) (*dto.EffectiveMembershipsListResponse, error)
+}
+
+type getEffectiveMembersHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	rsClient membershipQueryService.MembershipQueryServiceClient
+}
+
+func NewGetEffectiveMembersHandler(log logging.Logger, cfg *config.Config, rsClient membershipQueryService.MembershipQueryServiceClient) *getEffectiveMembersHandler {
+	return &getEffectiveMembersHandler{
+		log:      log,
+		cfg:      cfg,
+		rsClient: rsClient,
+	}
+}
+
+func (s *getEffectiveMembersHandler) Handle(ctx context.Context, query *GetEffectiveMembersQuery) (*dto.EffectiveMembershipsListResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "getEffectiveMembersHandler.Handle")
+	defer span.End()
+	res, err := s.rsClient.GetEffectiveMembers(ctx, &membershipQueryService.GetEffectiveMembersReq{
+		GroupID:  query.GroupID.String(),
+		Page:     int64(query.Pagination.GetPage()),
+		Size:     int64(query.Pagination.GetSize()),
+		TenantID: authentication.TenantFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.EffectiveMembershipListResponseFromGrpc(res), nil
+}
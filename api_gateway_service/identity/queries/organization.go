@@ -0,0 +1,38 @@
+package queries
+
+import (
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/gofrs/uuid"
+)
+
+type OrganizationQueries struct {
+	GetOrganizationById GetOrganizationByIdHandler
+	SearchOrganization  SearchOrganizationHandler
+}
+
+func NewOrganizationQueries(getById GetOrganizationByIdHandler, search SearchOrganizationHandler) *OrganizationQueries {
+	return &OrganizationQueries{
+		GetOrganizationById: getById,
+		SearchOrganization:  search,
+	}
+}
+
+type GetOrganizationByIdQuery struct {
+	ID uuid.UUID `json:"id" validate:"required,gte=0,lte=255"`
+}
+
+func NewGetOrganizationByIdQuery(id uuid.UUID) *GetOrganizationByIdQuery {
+	return &GetOrganizationByIdQuery{ID: id}
+}
+
+type SearchOrganizationQuery struct {
+	Text       string                `json:"text"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewSearchOrganizationQuery(text string, pagination *utilities.Pagination) *SearchOrganizationQuery {
+	return &SearchOrganizationQuery{
+		Text:       text,
+		Pagination: pagination,
+	}
+}
@@ -0,0 +1,75 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	organizationQueryService "github.com/JECSand/identity-service/query_service/protos/organization_query"
+)
+
+type GetOrganizationByIdHandler interface {
+	Handle(ctx context.Context, query *GetOrganizationByIdQuery) (*dto.OrganizationResponse, error)
+}
+
+type getOrganizationByIdHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	rsClient organizationQueryService.OrganizationQueryServiceClient
+}
+
+func NewGetOrganizationByIdHandler(log logging.Logger, cfg *config.Config, rsClient organizationQueryService.OrganizationQueryServiceClient) *getOrganizationByIdHandler {
+	return &getOrganizationByIdHandler{
+		log:      log,
+		cfg:      cfg,
+		rsClient: rsClient,
+	}
+}
+
+func (q *getOrganizationByIdHandler) Handle(ctx context.Context, query *GetOrganizationByIdQuery) (*dto.OrganizationResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "getOrganizationByIdHandler.Handle")
+	defer span.End()
+	res, err := q.rsClient.GetOrganizationById(ctx, &organizationQueryService.GetOrganizationByIdReq{
+		ID:       query.ID.String(),
+		TenantID: authentication.TenantFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.OrganizationResponseFromGrpc(res.GetOrganization()), nil
+}
+
+// SearchOrganizationHandler ...
+type SearchOrganizationHandler interface {
+	Handle(ctx context.Context, query *SearchOrganizationQuery) (*dto.OrganizationsListResponse, error)
+}
+
+type searchOrganizationHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	rsClient organizationQueryService.OrganizationQueryServiceClient
+}
+
+func NewSearchOrganizationHandler(log logging.Logger, cfg *config.Config, rsClient organizationQueryService.OrganizationQueryServiceClient) *searchOrganizationHandler {
+	return &searchOrganizationHandler{
+		log:      log,
+		cfg:      cfg,
+		rsClient: rsClient,
+	}
+}
+
+func (s *searchOrganizationHandler) Handle(ctx context.Context, query *SearchOrganizationQuery) (*dto.OrganizationsListResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "searchOrganizationHandler.Handle")
+	defer span.End()
+	res, err := s.rsClient.SearchOrganization(ctx, &organizationQueryService.SearchOrganizationReq{
+		Search: query.Text,
+		Page:   int64(query.Pagination.GetPage()),
+		Size:   int64(query.Pagination.GetSize()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.OrganizationsListResponseFromGrpc(res), nil
+}
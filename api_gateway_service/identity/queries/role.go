@@ -0,0 +1,41 @@
+package queries
+
+import (
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/gofrs/uuid"
+)
+
+type RoleQueries struct {
+	GetRoleById GetRoleByIdHandler
+	SearchRoles SearchRolesHandler
+}
+
+func NewRoleQueries(getById GetRoleByIdHandler, search SearchRolesHandler) *RoleQueries {
+	return &RoleQueries{
+		GetRoleById: getById,
+		SearchRoles: search,
+	}
+}
+
+type GetRoleByIdQuery struct {
+	ID uuid.UUID `json:"id" validate:"required,gte=0,lte=255"`
+}
+
+func NewGetRoleByIdQuery(id uuid.UUID) *GetRoleByIdQuery {
+	return &GetRoleByIdQuery{ID: id}
+}
+
+// SearchRolesQuery searches the roles assignable in the group with GroupID, or in any group when GroupID is empty
+type SearchRolesQuery struct {
+	Text       string                `json:"text"`
+	GroupID    string                `json:"groupID"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewSearchRolesQuery(text string, groupID string, pagination *utilities.Pagination) *SearchRolesQuery {
+	return &SearchRolesQuery{
+		Text:       text,
+		GroupID:    groupID,
+		Pagination: pagination,
+	}
+}
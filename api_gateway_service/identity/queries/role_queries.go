@@ -0,0 +1,77 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	roleQueryService "github.com/JECSand/identity-service/query_service/protos/role_query"
+)
+
+type GetRoleByIdHandler interface {
+	Handle(ctx context.Context, query *GetRoleByIdQuery) (*dto.RoleResponse, error)
+}
+
+type getRoleByIdHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	rsClient roleQueryService.RoleQueryServiceClient
+}
+
+func NewGetRoleByIdHandler(log logging.Logger, cfg *config.Config, rsClient roleQueryService.RoleQueryServiceClient) *getRoleByIdHandler {
+	return &getRoleByIdHandler{
+		log:      log,
+		cfg:      cfg,
+		rsClient: rsClient,
+	}
+}
+
+func (q *getRoleByIdHandler) Handle(ctx context.Context, query *GetRoleByIdQuery) (*dto.RoleResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "getRoleByIdHandler.Handle")
+	defer span.End()
+	res, err := q.rsClient.GetRoleById(ctx, &roleQueryService.GetRoleByIdReq{
+		ID:       query.ID.String(),
+		TenantID: authentication.TenantFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.RoleResponseFromGrpc(res.GetRole()), nil
+}
+
+// SearchRolesHandler ...
+type SearchRolesHandler interface {
+	Handle(ctx context.Context, query *SearchRolesQuery) (*dto.RolesListResponse, error)
+}
+
+type searchRolesHandler struct {
+	log      logging.Logger
+	cfg      *config.Config
+	rsClient roleQueryService.RoleQueryServiceClient
+}
+
+func NewSearchRolesHandler(log logging.Logger, cfg *config.Config, rsClient roleQueryService.RoleQueryServiceClient) *searchRolesHandler {
+	return &searchRolesHandler{
+		log:      log,
+		cfg:      cfg,
+		rsClient: rsClient,
+	}
+}
+
+func (s *searchRolesHandler) Handle(ctx context.Context, query *SearchRolesQuery) (*dto.RolesListResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "searchRolesHandler.Handle")
+	defer span.End()
+	res, err := s.rsClient.SearchRoles(ctx, &roleQueryService.SearchRolesReq{
+		Search:   query.Text,
+		GroupID:  query.GroupID,
+		TenantID: authentication.TenantFromContext(ctx),
+		Page:     int64(query.Pagination.GetPage()),
+		Size:     int64(query.Pagination.GetSize()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.RolesListResponseFromGrpc(res), nil
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	queryService "github.com/JECSand/identity-service/query_service/protos/user_query"
-	"github.com/opentracing/opentracing-go"
 )
 
 type GetUserByIdHandler interface {
@@ -29,10 +29,12 @@ func NewGetUserByIdHandler(log logging.Logger, cfg *config.Config, rsClient quer
 }
 
 func (q *getUserByIdHandler) Handle(ctx context.Context, query *GetUserByIdQuery) (*dto.UserResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getUserByIdHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
-	res, err := q.rsClient.GetUserById(ctx, &queryService.GetUserByIdReq{ID: query.ID.String()})
+	ctx, span := tracing.StartSpan(ctx, "getUserByIdHandler.Handle")
+	defer span.End()
+	res, err := q.rsClient.GetUserById(ctx, &queryService.GetUserByIdReq{
+		ID:       query.ID.String(),
+		TenantID: authentication.TenantFromContext(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -59,13 +61,13 @@ func NewSearchUserHandler(log logging.Logger, cfg *config.Config, rsClient query
 }
 
 func (s *searchUserHandler) Handle(ctx context.Context, query *SearchUserQuery) (*dto.UsersListResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "searchUserHandler.Handle")
-	defer span.Finish()
-	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	ctx, span := tracing.StartSpan(ctx, "searchUserHandler.Handle")
+	defer span.End()
 	res, err := s.rsClient.SearchUser(ctx, &queryService.SearchReq{
-		Search: query.Text,
-		Page:   int64(query.Pagination.GetPage()),
-		Size:   int64(query.Pagination.GetSize()),
+		Search:   query.Text,
+		Page:     int64(query.Pagination.GetPage()),
+		Size:     int64(query.Pagination.GetSize()),
+		TenantID: authentication.TenantFromContext(ctx),
 	})
 	if err != nil {
 		return nil, err
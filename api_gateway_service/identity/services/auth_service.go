@@ -4,8 +4,9 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	authCommandService "github.com/JECSand/identity-service/command_service/protos/auth_command"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	authQueryService "github.com/JECSand/identity-service/query_service/protos/auth_query"
 )
 
@@ -14,13 +15,15 @@ type AuthService struct {
 	Queries  *queries.AuthQueries
 }
 
-func NewAuthService(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, rsClient authQueryService.AuthQueryServiceClient) *AuthService {
-	blacklistTokenHandler := commands.NewBlacklistTokenHandler(log, cfg, kafkaProducer)
-	passwordUpdateHandler := commands.NewUpdatePasswordHandler(log, cfg, kafkaProducer)
-	authenticateHandler := queries.NewAuthenticateHandler(log, cfg, rsClient)
+func NewAuthService(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, rsClient authQueryService.AuthQueryServiceClient, csClient authCommandService.AuthCommandServiceClient) *AuthService {
+	blacklistTokenHandler := commands.NewBlacklistTokenHandler(log, cfg, publisher)
+	passwordUpdateHandler := commands.NewUpdatePasswordHandler(log, cfg, publisher)
+	revokeTokenHandler := commands.NewRevokeTokenHandler(log, cfg, publisher, rsClient)
+	authenticateHandler := queries.NewAuthenticateHandler(log, cfg, csClient)
 	validateHandler := queries.NewValidateHandler(log, cfg, rsClient)
-	AuthCommands := commands.NewAuthCommands(blacklistTokenHandler, passwordUpdateHandler)
-	AuthQueries := queries.NewAuthQueries(authenticateHandler, validateHandler)
+	checkPasswordHandler := queries.NewCheckPasswordHandler(log, cfg, csClient)
+	AuthCommands := commands.NewAuthCommands(blacklistTokenHandler, passwordUpdateHandler, revokeTokenHandler)
+	AuthQueries := queries.NewAuthQueries(authenticateHandler, validateHandler, checkPasswordHandler)
 	return &AuthService{
 		Commands: AuthCommands,
 		Queries:  AuthQueries,
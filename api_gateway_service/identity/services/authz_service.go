@@ -0,0 +1,20 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
+	"github.com/JECSand/identity-service/pkg/logging"
+	authzQueryService "github.com/JECSand/identity-service/query_service/protos/authz_query"
+)
+
+type AuthzService struct {
+	Queries *queries.AuthzQueries
+}
+
+func NewAuthzService(log logging.Logger, cfg *config.Config, azClient authzQueryService.AuthzQueryServiceClient) *AuthzService {
+	checkHandler := queries.NewCheckHandler(log, cfg, azClient)
+	batchCheckHandler := queries.NewBatchCheckHandler(log, cfg, azClient)
+	listResourcesHandler := queries.NewListResourcesHandler(log, cfg, azClient)
+	authzQueries := queries.NewAuthzQueries(checkHandler, batchCheckHandler, listResourcesHandler)
+	return &AuthzService{Queries: authzQueries}
+}
@@ -4,8 +4,9 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	groupCommandService "github.com/JECSand/identity-service/command_service/protos/group_command"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	groupQueryService "github.com/JECSand/identity-service/query_service/protos/group_query"
 )
 
@@ -14,13 +15,14 @@ type GroupService struct {
 	Queries  *queries.GroupQueries
 }
 
-func NewGroupService(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, rsClient groupQueryService.GroupQueryServiceClient) *GroupService {
-	createGroupHandler := commands.NewCreateGroupHandler(log, cfg, kafkaProducer)
-	updateGroupHandler := commands.NewUpdateGroupHandler(log, cfg, kafkaProducer)
-	deleteGroupHandler := commands.NewDeleteGroupHandler(log, cfg, kafkaProducer)
+func NewGroupService(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, rsClient groupQueryService.GroupQueryServiceClient, csClient groupCommandService.GroupCommandServiceClient) *GroupService {
+	createGroupHandler := commands.NewCreateGroupHandler(log, cfg, publisher)
+	updateGroupHandler := commands.NewUpdateGroupHandler(log, cfg, publisher)
+	deleteGroupHandler := commands.NewDeleteGroupHandler(log, cfg, publisher)
+	setGroupParentHandler := commands.NewSetGroupParentHandler(log, cfg, csClient)
 	getGroupByIdHandler := queries.NewGetGroupByIdHandler(log, cfg, rsClient)
 	searchGroupHandler := queries.NewSearchGroupHandler(log, cfg, rsClient)
-	GroupCommands := commands.NewGroupCommands(createGroupHandler, updateGroupHandler, deleteGroupHandler)
+	GroupCommands := commands.NewGroupCommands(createGroupHandler, updateGroupHandler, deleteGroupHandler, setGroupParentHandler)
 	GroupQueries := queries.NewGroupQueries(getGroupByIdHandler, searchGroupHandler)
 	return &GroupService{
 		Commands: GroupCommands,
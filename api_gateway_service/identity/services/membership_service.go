@@ -4,8 +4,8 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	membershipQueryService "github.com/JECSand/identity-service/query_service/protos/membership_query"
 )
 
@@ -14,15 +14,24 @@ type MembershipService struct {
 	Queries  *queries.MembershipQueries
 }
 
-func NewMembershipService(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, rsClient membershipQueryService.MembershipQueryServiceClient) *MembershipService {
-	createMembershipHandler := commands.NewCreateMembershipHandler(log, cfg, kafkaProducer)
-	updateMembershipHandler := commands.NewUpdateMembershipHandler(log, cfg, kafkaProducer)
-	deleteMembershipHandler := commands.NewDeleteMembershipHandler(log, cfg, kafkaProducer)
+func NewMembershipService(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, rsClient membershipQueryService.MembershipQueryServiceClient) *MembershipService {
+	createMembershipHandler := commands.NewCreateMembershipHandler(log, cfg, publisher)
+	updateMembershipHandler := commands.NewUpdateMembershipHandler(log, cfg, publisher)
+	deleteMembershipHandler := commands.NewDeleteMembershipHandler(log, cfg, publisher)
+	extendMembershipHandler := commands.NewExtendMembershipHandler(log, cfg, publisher)
 	getMembershipByIdHandler := queries.NewGetMembershipByIdHandler(log, cfg, rsClient)
 	getUserMembershipByGroupIdHandler := queries.NewGetUserMembershipByGroupIHandler(log, cfg, rsClient)
 	getGroupMembershipByUserIdHandler := queries.NewGetGroupMembershipByUserIdHandler(log, cfg, rsClient)
-	MembershipCommands := commands.NewMembershipCommands(createMembershipHandler, updateMembershipHandler, deleteMembershipHandler)
-	MembershipQueries := queries.NewMembershipQueries(getMembershipByIdHandler, getUserMembershipByGroupIdHandler, getGroupMembershipByUserIdHandler)
+	getEffectiveGroupsHandler := queries.NewGetEffectiveGroupsHandler(log, cfg, rsClient)
+	getEffectiveMembersHandler := queries.NewGetEffectiveMembersHandler(log, cfg, rsClient)
+	MembershipCommands := commands.NewMembershipCommands(createMembershipHandler, updateMembershipHandler, deleteMembershipHandler, extendMembershipHandler)
+	MembershipQueries := queries.NewMembershipQueries(
+		getMembershipByIdHandler,
+		getUserMembershipByGroupIdHandler,
+		getGroupMembershipByUserIdHandler,
+		getEffectiveGroupsHandler,
+		getEffectiveMembersHandler,
+	)
 	return &MembershipService{
 		Commands: MembershipCommands,
 		Queries:  MembershipQueries,
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	organizationQueryService "github.com/JECSand/identity-service/query_service/protos/organization_query"
+)
+
+type OrganizationService struct {
+	Commands *commands.OrganizationCommands
+	Queries  *queries.OrganizationQueries
+}
+
+func NewOrganizationService(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, rsClient organizationQueryService.OrganizationQueryServiceClient) *OrganizationService {
+	createOrganizationHandler := commands.NewCreateOrganizationHandler(log, cfg, publisher)
+	updateOrganizationHandler := commands.NewUpdateOrganizationHandler(log, cfg, publisher)
+	changeOrganizationStatusHandler := commands.NewChangeOrganizationStatusHandler(log, cfg, publisher)
+	deleteOrganizationHandler := commands.NewDeleteOrganizationHandler(log, cfg, publisher)
+	getOrganizationByIdHandler := queries.NewGetOrganizationByIdHandler(log, cfg, rsClient)
+	searchOrganizationHandler := queries.NewSearchOrganizationHandler(log, cfg, rsClient)
+	organizationCommands := commands.NewOrganizationCommands(createOrganizationHandler, updateOrganizationHandler, changeOrganizationStatusHandler, deleteOrganizationHandler)
+	organizationQueries := queries.NewOrganizationQueries(getOrganizationByIdHandler, searchOrganizationHandler)
+	return &OrganizationService{
+		Commands: organizationCommands,
+		Queries:  organizationQueries,
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	roleQueryService "github.com/JECSand/identity-service/query_service/protos/role_query"
+)
+
+type RoleService struct {
+	Commands *commands.RoleCommands
+	Queries  *queries.RoleQueries
+}
+
+func NewRoleService(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, rsClient roleQueryService.RoleQueryServiceClient) *RoleService {
+	createRoleHandler := commands.NewCreateRoleHandler(log, cfg, publisher)
+	updateRoleHandler := commands.NewUpdateRoleHandler(log, cfg, publisher)
+	deleteRoleHandler := commands.NewDeleteRoleHandler(log, cfg, publisher)
+	getRoleByIdHandler := queries.NewGetRoleByIdHandler(log, cfg, rsClient)
+	searchRolesHandler := queries.NewSearchRolesHandler(log, cfg, rsClient)
+	roleCommands := commands.NewRoleCommands(createRoleHandler, updateRoleHandler, deleteRoleHandler)
+	roleQueries := queries.NewRoleQueries(getRoleByIdHandler, searchRolesHandler)
+	return &RoleService{
+		Commands: roleCommands,
+		Queries:  roleQueries,
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/commands"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/queries"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	commandService "github.com/JECSand/identity-service/command_service/protos/user_command"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	queryService "github.com/JECSand/identity-service/query_service/protos/user_query"
 )
 
@@ -14,13 +15,14 @@ type UserService struct {
 	Queries  *queries.UserQueries
 }
 
-func NewUserService(log logging.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, rsClient queryService.QueryServiceClient) *UserService {
-	createUserHandler := commands.NewCreateUserHandler(log, cfg, kafkaProducer)
-	updateUserHandler := commands.NewUpdateUserHandler(log, cfg, kafkaProducer)
-	deleteUserHandler := commands.NewDeleteUserHandler(log, cfg, kafkaProducer)
+func NewUserService(log logging.Logger, cfg *config.Config, publisher messaging.Publisher, rsClient queryService.QueryServiceClient, csClient commandService.CommandServiceClient) *UserService {
+	createUserHandler := commands.NewCreateUserHandler(log, cfg, publisher, csClient)
+	updateUserHandler := commands.NewUpdateUserHandler(log, cfg, publisher, csClient)
+	deleteUserHandler := commands.NewDeleteUserHandler(log, cfg, publisher)
+	changeUserStatusHandler := commands.NewChangeUserStatusHandler(log, cfg, csClient)
 	getUserByIdHandler := queries.NewGetUserByIdHandler(log, cfg, rsClient)
 	searchUserHandler := queries.NewSearchUserHandler(log, cfg, rsClient)
-	UserCommands := commands.NewUserCommands(createUserHandler, updateUserHandler, deleteUserHandler)
+	UserCommands := commands.NewUserCommands(createUserHandler, updateUserHandler, deleteUserHandler, changeUserStatusHandler)
 	UserQueries := queries.NewUserQueries(getUserByIdHandler, searchUserHandler)
 	return &UserService{
 		Commands: UserCommands,
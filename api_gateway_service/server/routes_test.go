@@ -0,0 +1,40 @@
+package server
+
+import (
+	"github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/controllers/access"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"strings"
+	"testing"
+)
+
+// TestVerifiedRoutesHaveAccessRules walks the mapped routes and requires an access rule for every route behind a
+// verify middleware, as such a route is refused without one
+func TestVerifiedRoutesHaveAccessRules(t *testing.T) {
+	cfg, err := config.InitConfig("../config/config.yaml")
+	if err != nil {
+		t.Fatalf("InitConfig: %v", err)
+	}
+	log := logging.NewAppLogger(cfg.Logger)
+	log.InitLogger()
+	rules := access.DefaultAccessRules()
+	auth := authentication.NewAuthenticator(log, rules, authentication.NewAuthConfig(1, 4380, cfg.ServiceSettings.JWTSalt))
+	s := NewServer(log, auth, cfg)
+	s.mw = middlewares.NewMiddlewareManager(s.log, s.auth, s.cfg, nil, nil, nil, s.m)
+	s.mapHandlers()
+	verified := 0
+	for _, route := range s.echo.Routes() {
+		if !strings.Contains(route.Name, ".RequestVerify") {
+			continue
+		}
+		verified++
+		if _, ok := rules[route.Method+" "+route.Path]; !ok {
+			t.Errorf("verified route %s %s has no access rule", route.Method, route.Path)
+		}
+	}
+	if verified == 0 {
+		t.Fatal("no verified routes found")
+	}
+}
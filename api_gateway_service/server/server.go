@@ -2,30 +2,44 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"github.com/JECSand/identity-service/api_gateway_service/config"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/client"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/controllers/http/v1"
+	kafkaConsumer "github.com/JECSand/identity-service/api_gateway_service/identity/delivery/kafka"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/metrics"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/middlewares"
 	"github.com/JECSand/identity-service/api_gateway_service/identity/services"
+	authCommandService "github.com/JECSand/identity-service/command_service/protos/auth_command"
+	groupCommandService "github.com/JECSand/identity-service/command_service/protos/group_command"
+	commandService "github.com/JECSand/identity-service/command_service/protos/user_command"
 	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/constants"
 	"github.com/JECSand/identity-service/pkg/interceptors"
 	"github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/JECSand/identity-service/pkg/probes"
+	"github.com/JECSand/identity-service/pkg/ratelimit"
+	"github.com/JECSand/identity-service/pkg/redis"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	authQueryService "github.com/JECSand/identity-service/query_service/protos/auth_query"
+	authzQueryService "github.com/JECSand/identity-service/query_service/protos/authz_query"
 	groupQueryService "github.com/JECSand/identity-service/query_service/protos/group_query"
 	membershipQueryService "github.com/JECSand/identity-service/query_service/protos/membership_query"
+	organizationQueryService "github.com/JECSand/identity-service/query_service/protos/organization_query"
+	roleQueryService "github.com/JECSand/identity-service/query_service/protos/role_query"
 	queryService "github.com/JECSand/identity-service/query_service/protos/user_query"
 	"github.com/go-playground/validator"
-	"github.com/heptiolabs/healthcheck"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"github.com/swaggo/swag/example/basic/docs"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"google.golang.org/grpc"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -55,7 +69,18 @@ type server struct {
 	gs   *services.GroupService
 	ms   *services.MembershipService
 	as   *services.AuthService
+	os   *services.OrganizationService
+	rs   *services.RoleService
+	zs   *services.AuthzService
 	m    *metrics.ApiGatewayMetrics
+	// queryDialOpts are extra options for the query service connections, e.g. an in-process dialer
+	queryDialOpts []grpc.DialOption
+	queryConn     *grpc.ClientConn
+	// commandDialOpts are extra options for the command service connection
+	commandDialOpts []grpc.DialOption
+	commandConn     *grpc.ClientConn
+	limiter         ratelimit.Limiter
+	health          *probes.Health
 }
 
 func NewServer(log logging.Logger, auth authentication.Authenticator, cfg *config.Config) *server {
@@ -64,91 +89,206 @@ func NewServer(log logging.Logger, auth authentication.Authenticator, cfg *confi
 		auth: auth,
 		cfg:  cfg,
 		echo: echo.New(), v: validator.New(),
+		m:      metrics.NewApiGatewayMetrics(cfg),
+		health: probes.NewHealth(log, &cfg.Probes),
 	}
 }
 
-func (s *server) Run() error {
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-	defer cancel()
-	s.im = interceptors.NewInterceptorManager(s.log, s.auth)
-	s.m = metrics.NewApiGatewayMetrics(s.cfg)
-	queryServiceClient, err := client.NewQueryServiceClient(ctx, s.cfg, s.im)
+// WithQueryDialer makes the gateway reach the query service through dialer instead of the network
+func (s *server) WithQueryDialer(dialer func(context.Context, string) (net.Conn, error)) *server {
+	s.queryDialOpts = append(s.queryDialOpts, grpc.WithContextDialer(dialer))
+	return s
+}
+
+// WithCommandDialer makes the gateway reach the command service through dialer instead of the network
+func (s *server) WithCommandDialer(dialer func(context.Context, string) (net.Conn, error)) *server {
+	s.commandDialOpts = append(s.commandDialOpts, grpc.WithContextDialer(dialer))
+	return s
+}
+
+// WithLimiter makes the gateway rate limit requests with limiter instead of an in-process one
+func (s *server) WithLimiter(limiter ratelimit.Limiter) *server {
+	s.limiter = limiter
+	return s
+}
+
+// Start wires the services over pub, dials the query and command services, follows revocations from sub when enabled and
+// serves the HTTP API on l until the returned stop func is called
+func (s *server) Start(ctx context.Context, pub messaging.Publisher, sub messaging.Subscriber, l net.Listener) (func() error, error) {
+	serviceAuth, err := authentication.NewServiceAuthenticator(s.log, &s.cfg.ServiceAuth, nil)
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "authentication.NewServiceAuthenticator")
 	}
-	defer queryServiceClient.Close() // nolint: errCheck
+	s.im = interceptors.NewInterceptorManager(s.log, s.auth, serviceAuth)
+	var conns []*grpc.ClientConn
+	closeConns := func() {
+		for _, conn := range conns {
+			conn.Close() // nolint: errCheck
+		}
+	}
+	dial := func() (*grpc.ClientConn, error) {
+		conn, err := client.NewQueryServiceClient(ctx, s.log, s.cfg, s.im, s.queryDialOpts...)
+		if err != nil {
+			return nil, err
+		}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	queryServiceClient, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	s.queryConn = queryServiceClient
 	rsClient := queryService.NewQueryServiceClient(queryServiceClient)
-	authQueryServiceClient, err := client.NewQueryServiceClient(ctx, s.cfg, s.im)
+	authQueryServiceClient, err := dial()
 	if err != nil {
-		return err
+		closeConns()
+		return nil, err
 	}
-	defer authQueryServiceClient.Close() // nolint: errCheck
 	rsAuthClient := authQueryService.NewAuthQueryServiceClient(authQueryServiceClient)
-	groupQueryServiceClient, err := client.NewQueryServiceClient(ctx, s.cfg, s.im)
+	groupQueryServiceClient, err := dial()
 	if err != nil {
-		return err
+		closeConns()
+		return nil, err
 	}
-	defer groupQueryServiceClient.Close() // nolint: errCheck
 	rsGroupClient := groupQueryService.NewGroupQueryServiceClient(groupQueryServiceClient)
-	membershipQueryServiceClient, err := client.NewQueryServiceClient(ctx, s.cfg, s.im)
+	membershipQueryServiceClient, err := dial()
 	if err != nil {
-		return err
+		closeConns()
+		return nil, err
 	}
-	defer membershipQueryServiceClient.Close() // nolint: errCheck
 	rsMembershipClient := membershipQueryService.NewMembershipQueryServiceClient(membershipQueryServiceClient)
-	kafkaProducer := kafka.NewProducer(s.log, s.cfg.Kafka.Brokers)
-	defer kafkaProducer.Close() // nolint: errCheck
-	s.ps = services.NewUserService(s.log, s.cfg, kafkaProducer, rsClient)
-	s.gs = services.NewGroupService(s.log, s.cfg, kafkaProducer, rsGroupClient)
-	s.ms = services.NewMembershipService(s.log, s.cfg, kafkaProducer, rsMembershipClient)
-	s.as = services.NewAuthService(s.log, s.cfg, kafkaProducer, rsAuthClient)
-	s.mw = middlewares.NewMiddlewareManager(s.log, s.auth, s.cfg, s.as)
-	userHandlers := v1.NewUsersHandlers(s.echo.Group(s.cfg.Http.UsersPath), s.log, s.mw, s.cfg, s.ps, s.ms, s.v, s.m)
-	userHandlers.MapRoutes()
-	groupHandlers := v1.NewGroupsHandlers(s.echo.Group(s.cfg.Http.GroupsPath), s.log, s.mw, s.cfg, s.gs, s.ms, s.v, s.m)
-	groupHandlers.MapRoutes()
-	membershipHandlers := v1.NewMembershipsHandlers(s.echo.Group(s.cfg.Http.GroupsPath), s.log, s.mw, s.cfg, s.ms, s.v, s.m)
-	membershipHandlers.MapRoutes()
-	authHandlers := v1.NewAuthHandlers(s.echo.Group(s.cfg.Http.AuthPath), s.log, s.auth, s.mw, s.cfg, s.as, s.v, s.m)
-	authHandlers.MapRoutes()
+	organizationQueryServiceClient, err := dial()
+	if err != nil {
+		closeConns()
+		return nil, err
+	}
+	rsOrganizationClient := organizationQueryService.NewOrganizationQueryServiceClient(organizationQueryServiceClient)
+	roleQueryServiceClient, err := dial()
+	if err != nil {
+		closeConns()
+		return nil, err
+	}
+	rsRoleClient := roleQueryService.NewRoleQueryServiceClient(roleQueryServiceClient)
+	authzQueryServiceClient, err := dial()
+	if err != nil {
+		closeConns()
+		return nil, err
+	}
+	rsAuthzClient := authzQueryService.NewAuthzQueryServiceClient(authzQueryServiceClient)
+	commandServiceClient, err := client.NewCommandServiceClient(ctx, s.log, s.cfg, s.im, s.commandDialOpts...)
+	if err != nil {
+		closeConns()
+		return nil, err
+	}
+	conns = append(conns, commandServiceClient)
+	s.commandConn = commandServiceClient
+	csAuthClient := authCommandService.NewAuthCommandServiceClient(commandServiceClient)
+	csClient := commandService.NewCommandServiceClient(commandServiceClient)
+	csGroupClient := groupCommandService.NewGroupCommandServiceClient(commandServiceClient)
+	s.ps = services.NewUserService(s.log, s.cfg, pub, rsClient, csClient)
+	s.gs = services.NewGroupService(s.log, s.cfg, pub, rsGroupClient, csGroupClient)
+	s.ms = services.NewMembershipService(s.log, s.cfg, pub, rsMembershipClient)
+	s.as = services.NewAuthService(s.log, s.cfg, pub, rsAuthClient, csAuthClient)
+	s.os = services.NewOrganizationService(s.log, s.cfg, pub, rsOrganizationClient)
+	s.rs = services.NewRoleService(s.log, s.cfg, pub, rsRoleClient)
+	s.zs = services.NewAuthzService(s.log, s.cfg, rsAuthzClient)
+	revocations := authentication.NewRevocationList(s.cfg.Revocation.ExpectedTokens, s.cfg.Revocation.FalsePositiveRate)
+	if s.cfg.Revocation.Enabled {
+		revocationConsumer := kafkaConsumer.NewRevocationConsumer(s.log, s.cfg, s.auth, revocations, sub, s.m)
+		if err = revocationConsumer.Run(ctx); err != nil {
+			closeConns()
+			return nil, err
+		}
+	}
+	var limiter ratelimit.Limiter
+	if s.cfg.RateLimit.Enabled {
+		limiter = s.limiter
+		if limiter == nil {
+			limiter = ratelimit.NewMemoryLimiter()
+		}
+	}
+	s.echo.IPExtractor = echo.ExtractIPDirect()
+	if s.cfg.RateLimit.TrustProxyHeaders {
+		s.echo.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
+	s.mw = middlewares.NewMiddlewareManager(s.log, s.auth, s.cfg, s.as, revocations, limiter, s.m)
+	s.mapHandlers()
+	s.echo.Listener = l
 	go func() {
-		if err = s.runHttpServer(); err != nil {
+		if err := s.runHttpServer(); err != nil && err != http.ErrServerClosed {
 			s.log.Errorf(" s.runHttpServer: %v", err)
-			cancel()
 		}
 	}()
-	s.log.Infof("API Gateway Service is listening on PORT: %s", s.cfg.Http.Port)
-	s.runMetrics(cancel)
-	s.runHealthCheck(ctx)
-	if s.cfg.Jaeger.Enable {
-		tracer, closer, err := tracing.NewJaegerTracer(s.cfg.Jaeger)
-		if err != nil {
-			return err
+	s.log.Infof("API Gateway Service is listening on: %s", l.Addr())
+	return func() error {
+		defer closeConns()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		return s.echo.Shutdown(shutdownCtx)
+	}, nil
+}
+
+func (s *server) Run() error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	publisher, err := kafka.NewPublisher(s.log, s.cfg.Kafka)
+	if err != nil {
+		return errors.Wrap(err, "kafka.NewPublisher")
+	}
+	defer publisher.Close() // nolint: errCheck
+	var subscriber messaging.Subscriber
+	if s.cfg.Revocation.Enabled {
+		if subscriber, err = kafka.NewSubscriber(s.log, s.cfg.Kafka); err != nil {
+			return errors.Wrap(err, "kafka.NewSubscriber")
+		}
+	}
+	if s.cfg.RateLimit.Enabled {
+		switch s.cfg.RateLimit.Store {
+		case ratelimit.StoreRedis:
+			redisClient := redis.NewRedisClient(s.cfg.Redis)
+			defer redisClient.Close() // nolint: errCheck
+			s.limiter = ratelimit.NewFallbackLimiter(s.log, ratelimit.NewRedisLimiter(redisClient, s.cfg.RateLimit.KeyPrefix), ratelimit.NewMemoryLimiter())
+		case ratelimit.StoreMemory, "":
+		default:
+			return fmt.Errorf("unknown rate limit store %q, want %s or %s", s.cfg.RateLimit.Store, ratelimit.StoreRedis, ratelimit.StoreMemory)
 		}
-		defer closer.Close() // nolint: errCheck
-		opentracing.SetGlobalTracer(tracer)
 	}
+	s.runMetrics(cancel)
+	stopPprof := probes.ServePprof(s.log, &s.cfg.Probes)
+	defer stopPprof() // nolint: errCheck
+	stopTracing, err := tracing.NewTracerProvider(ctx, s.cfg.Tracing)
+	if err != nil {
+		return errors.Wrap(err, "tracing.NewTracerProvider")
+	}
+	defer stopTracing() // nolint: errCheck
+	l, err := net.Listen("tcp", s.cfg.Http.Port)
+	if err != nil {
+		return errors.Wrap(err, "net.Listen")
+	}
+	stop, err := s.Start(ctx, publisher, subscriber, l)
+	if err != nil {
+		l.Close() // nolint: errCheck
+		return errors.Wrap(err, "Start")
+	}
+	stopProbes := s.runHealthCheck(ctx, publisher)
+	defer stopProbes() // nolint: errCheck
 	<-ctx.Done()
-	if err = s.echo.Server.Shutdown(ctx); err != nil {
-		s.log.WarnMsg("echo.Server.Shutdown", err)
+	s.health.Drain(context.Background())
+	if err = stop(); err != nil {
+		s.log.WarnMsg("echo.Shutdown", err)
 	}
 	return nil
 }
 
-func (s *server) runHealthCheck(ctx context.Context) {
-	health := healthcheck.NewHandler()
-	health.AddReadinessCheck(s.cfg.ServiceName, healthcheck.AsyncWithContext(ctx, func() error {
-		if s.cfg != nil {
-			return nil
-		}
-		return errors.New("Config not loaded")
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	go func() {
-		s.log.Infof("API_Gateway Kubernetes probes listening on port: %s", s.cfg.Probes.Port)
-		if err := http.ListenAndServe(s.cfg.Probes.Port, health); err != nil {
-			s.log.WarnMsg("ListenAndServe", err)
-		}
-	}()
+func (s *server) runHealthCheck(ctx context.Context, pub messaging.Publisher) func() error {
+	s.health.AddLivenessCheck(ctx, s.cfg.ServiceName, func(context.Context) error {
+		return nil
+	})
+	s.health.AddReadinessCheck(ctx, constants.QueryService, probes.GrpcCheck(s.queryConn, ""))
+	s.health.AddReadinessCheck(ctx, constants.CommandService, probes.GrpcCheck(s.commandConn, ""))
+	s.health.AddReadinessCheck(ctx, constants.Kafka, probes.PingCheck(pub))
+	return s.health.Serve()
 }
 
 func (s *server) runMetrics(cancel context.CancelFunc) {
@@ -173,7 +313,7 @@ func (s *server) runHttpServer() error {
 	s.echo.Server.ReadTimeout = readTimeout
 	s.echo.Server.WriteTimeout = writeTimeout
 	s.echo.Server.MaxHeaderBytes = maxHeaderBytes
-	return s.echo.Start(s.cfg.Http.Port)
+	return s.echo.Start("")
 }
 
 func (s *server) mapRoutes() {
@@ -183,13 +323,19 @@ func (s *server) mapRoutes() {
 	docs.SwaggerInfo.Version = "1.0"
 	docs.SwaggerInfo.BasePath = "/api/v1"
 	s.echo.GET("/swagger/*", echoSwagger.WrapHandler)
+	s.echo.Use(otelecho.Middleware(s.cfg.ServiceName))
+	s.echo.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			c.SetRequest(c.Request().WithContext(logging.ContextWithRequestID(c.Request().Context(), requestID)))
+		},
+	}))
 	s.echo.Use(s.mw.RequestLoggerMiddleware)
+	s.echo.Use(s.mw.RateLimitMiddleware)
 	s.echo.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		StackSize:         stackSize,
 		DisablePrintStack: true,
 		DisableStackAll:   true,
 	}))
-	s.echo.Use(middleware.RequestID())
 	s.echo.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: gzipLevel,
 		Skipper: func(c echo.Context) bool {
@@ -198,3 +344,23 @@ func (s *server) mapRoutes() {
 	}))
 	s.echo.Use(middleware.BodyLimit(bodyLimit))
 }
+
+// mapHandlers maps the routes of every handler group on the echo server
+func (s *server) mapHandlers() {
+	userHandlers := v1.NewUsersHandlers(s.echo.Group(s.cfg.Http.UsersPath), s.log, s.mw, s.cfg, s.ps, s.ms, s.as, s.v, s.m)
+	userHandlers.MapRoutes()
+	groupHandlers := v1.NewGroupsHandlers(s.echo.Group(s.cfg.Http.GroupsPath), s.log, s.mw, s.cfg, s.gs, s.ms, s.v, s.m)
+	groupHandlers.MapRoutes()
+	membershipHandlers := v1.NewMembershipsHandlers(s.echo.Group(s.cfg.Http.MembershipsPath), s.log, s.mw, s.cfg, s.ms, s.v, s.m)
+	membershipHandlers.MapRoutes()
+	organizationHandlers := v1.NewOrganizationsHandlers(s.echo.Group(s.cfg.Http.OrganizationsPath), s.log, s.mw, s.cfg, s.os, s.v, s.m)
+	organizationHandlers.MapRoutes()
+	roleHandlers := v1.NewRolesHandlers(s.echo.Group(s.cfg.Http.RolesPath), s.log, s.mw, s.cfg, s.rs, s.v, s.m)
+	roleHandlers.MapRoutes()
+	authzHandlers := v1.NewAuthzHandlers(s.echo.Group(s.cfg.Http.AuthzPath), s.log, s.mw, s.cfg, s.zs, s.v, s.m)
+	authzHandlers.MapRoutes()
+	authHandlers := v1.NewAuthHandlers(s.echo.Group(s.cfg.Http.AuthPath), s.log, s.auth, s.mw, s.cfg, s.as, s.ps, s.v, s.m)
+	authHandlers.MapRoutes()
+	oauthHandlers := v1.NewOAuthHandlers(s.echo.Group(s.cfg.Http.OAuthPath), s.log, s.auth, s.mw, s.cfg, s.as, s.v, s.m)
+	oauthHandlers.MapRoutes()
+}
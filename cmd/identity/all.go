@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	gatewayConfig "github.com/JECSand/identity-service/api_gateway_service/config"
+	gatewayServer "github.com/JECSand/identity-service/api_gateway_service/server"
+	commandConfig "github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	commandServer "github.com/JECSand/identity-service/command_service/server"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/JECSand/identity-service/pkg/sqlite"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	queryConfig "github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/cache"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	queryServer "github.com/JECSand/identity-service/query_service/server"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	storageMemory = "memory"
+	storageSqlite = "sqlite"
+
+	busPartitions  = 10
+	bufconnSize    = 1 << 20
+	metricsTimeout = 5 * time.Second
+)
+
+// allOptions are the flags of the all-in-one mode
+type allOptions struct {
+	gatewayConfig    string
+	commandConfig    string
+	queryConfig      string
+	storage          string
+	sqlitePath       string
+	documentsPath    string
+	snapshotInterval time.Duration
+}
+
+// runAll runs the query, command and gateway services in one process. Messages travel over an in-process bus,
+// the gateway reaches the query service through an in-memory gRPC connection and the read model lives in an
+// in-process document store. With sqlite storage the write model is kept in an embedded database file and the
+// read model is snapshotted next to it, so both survive restarts; with memory storage nothing is persisted.
+func runAll(args []string) error {
+	var opts allOptions
+	flags := flag.NewFlagSet("all", flag.ExitOnError)
+	flags.StringVar(&opts.gatewayConfig, "gateway-config", "api_gateway_service/config/config.yaml", "API Gateway service config path")
+	flags.StringVar(&opts.commandConfig, "command-config", "command_service/config/config.yaml", "Command service config path")
+	flags.StringVar(&opts.queryConfig, "query-config", "query_service/config/config.yaml", "Query service config path")
+	flags.StringVar(&opts.storage, "storage", storageSqlite, "write model storage: sqlite or memory")
+	flags.StringVar(&opts.sqlitePath, "sqlite-path", "", "sqlite database file, defaults to the command service sqlite.path")
+	flags.StringVar(&opts.documentsPath, "documents-path", "", "read model snapshot file, defaults to the sqlite file with a .documents.json suffix")
+	flags.DurationVar(&opts.snapshotInterval, "snapshot-interval", 30*time.Second, "how often the read model is snapshotted with sqlite storage")
+	flags.Parse(args) // nolint: errCheck
+	if opts.storage != storageSqlite && opts.storage != storageMemory {
+		return fmt.Errorf("unknown storage %q, want %s or %s", opts.storage, storageSqlite, storageMemory)
+	}
+	gwCfg, err := gatewayConfig.InitConfig(opts.gatewayConfig)
+	if err != nil {
+		return errors.Wrap(err, "gateway config")
+	}
+	cmdCfg, err := commandConfig.InitConfig(opts.commandConfig)
+	if err != nil {
+		return errors.Wrap(err, "command config")
+	}
+	qCfg, err := queryConfig.InitConfig(opts.queryConfig)
+	if err != nil {
+		return errors.Wrap(err, "query config")
+	}
+	if err = authentication.GenerateServiceKeys(&gwCfg.ServiceAuth, &cmdCfg.ServiceAuth, &qCfg.ServiceAuth); err != nil {
+		return errors.Wrap(err, "authentication.GenerateServiceKeys")
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	gwLog := newLogger(gwCfg.Logger, "GatewayService")
+	cmdLog := newLogger(cmdCfg.Logger, "CommandService")
+	qLog := newLogger(qCfg.Logger, "QueryService")
+	stopTracing, err := tracing.NewTracerProvider(ctx, gwCfg.Tracing)
+	if err != nil {
+		return errors.Wrap(err, "tracing.NewTracerProvider")
+	}
+	defer stopTracing() // nolint: errCheck
+	bus := messaging.NewMemoryBus(busPartitions)
+	defer bus.Close() // nolint: errCheck
+
+	repo, documents, closeStores, err := openStores(cmdLog, cmdCfg, qLog, qCfg, &opts)
+	if err != nil {
+		return err
+	}
+	stops := []func() error{closeStores}
+	defer func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			if err := stops[i](); err != nil {
+				gwLog.WarnMsg("stop", err)
+			}
+		}
+	}()
+
+	queryListener := bufconn.Listen(bufconnSize)
+	q := queryServer.NewServer(qLog, qCfg)
+	stop, err := q.Start(ctx, documents, cache.NewMemoryCache(qLog, qCfg), bus, queryListener)
+	if err != nil {
+		return errors.Wrap(err, "query Start")
+	}
+	stops = append(stops, stop)
+	if opts.storage == storageSqlite {
+		stops = append(stops, snapshotDocuments(ctx, qLog, documents, opts.documentsPath, opts.snapshotInterval))
+	}
+
+	commandListener, err := net.Listen("tcp", cmdCfg.GRPC.Port)
+	if err != nil {
+		return errors.Wrap(err, "net.Listen")
+	}
+	c := commandServer.NewServer(cmdLog, cmdCfg)
+	if stop, err = c.Start(ctx, repo, bus, bus, commandListener); err != nil {
+		commandListener.Close() // nolint: errCheck
+		return errors.Wrap(err, "command Start")
+	}
+	stops = append(stops, stop)
+	gwCfg.Grpc.CommandServicePort = commandListener.Addr().String()
+
+	gatewayListener, err := net.Listen("tcp", gwCfg.Http.Port)
+	if err != nil {
+		return errors.Wrap(err, "net.Listen")
+	}
+	g := gatewayServer.NewServer(gwLog, newAuthenticator(gwLog, gwCfg), gwCfg).WithQueryDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return queryListener.DialContext(ctx)
+	})
+	if stop, err = g.Start(ctx, bus, bus, gatewayListener); err != nil {
+		gatewayListener.Close() // nolint: errCheck
+		return errors.Wrap(err, "gateway Start")
+	}
+	stops = append(stops, stop)
+
+	stops = append(stops, runMetrics(gwLog, gwCfg.Probes.PrometheusPort, gwCfg.Probes.PrometheusPath))
+	gwLog.Infof("identity service running all components with %s storage", opts.storage)
+	<-ctx.Done()
+	return nil
+}
+
+// documentStore is the in-process read model with snapshot support
+type documentStore interface {
+	data.Database
+	Save(path string) error
+	Load(path string) error
+}
+
+// openStores opens the write model for the selected storage and the in-process read model, restoring the read
+// model snapshot when the write model is persistent; the returned func closes the write model
+func openStores(cmdLog logging.Logger, cmdCfg *commandConfig.Config, qLog logging.Logger, qCfg *queryConfig.Config, opts *allOptions) (repositories.Repository, documentStore, func() error, error) {
+	documents := data.NewMemoryDatabase(qLog, qCfg)
+	if opts.storage == storageMemory {
+		return repositories.NewMemoryRepository(cmdLog, cmdCfg), documents, func() error { return nil }, nil
+	}
+	sqliteCfg := sqlite.Config{}
+	if cmdCfg.Sqlite != nil {
+		sqliteCfg = *cmdCfg.Sqlite
+	}
+	if opts.sqlitePath != "" {
+		sqliteCfg.Path = opts.sqlitePath
+	}
+	if sqliteCfg.Path == "" {
+		return nil, nil, nil, errors.New("sqlite storage needs -sqlite-path or the command service sqlite.path")
+	}
+	if opts.documentsPath == "" {
+		opts.documentsPath = sqliteCfg.Path + ".documents.json"
+	}
+	db, err := sqlite.NewSqliteConn(&sqliteCfg)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "sqlite.NewSqliteConn")
+	}
+	repo := repositories.NewSqliteRepository(cmdLog, cmdCfg, db)
+	if err = repo.Migrate(context.Background()); err != nil {
+		db.Close() // nolint: errCheck
+		return nil, nil, nil, errors.Wrap(err, "Migrate")
+	}
+	if err = documents.Load(opts.documentsPath); err != nil {
+		db.Close() // nolint: errCheck
+		return nil, nil, nil, errors.Wrap(err, "documents.Load")
+	}
+	cmdLog.Infof("sqlite storage at %s, read model snapshot at %s", sqliteCfg.Path, opts.documentsPath)
+	return repo, documents, db.Close, nil
+}
+
+// snapshotDocuments saves the read model every interval until ctx is done; the returned func takes a final snapshot
+func snapshotDocuments(ctx context.Context, log logging.Logger, documents documentStore, path string, interval time.Duration) func() error {
+	if interval > 0 {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := documents.Save(path); err != nil {
+						log.WarnMsg("documents.Save", err)
+					}
+				}
+			}
+		}()
+	}
+	return func() error {
+		return documents.Save(path)
+	}
+}
+
+// runMetrics serves the prometheus registry shared by every component
+func runMetrics(log logging.Logger, port string, path string) func() error {
+	mux := http.NewServeMux()
+	mux.Handle(path, promhttp.Handler())
+	srv := &http.Server{Addr: port, Handler: mux, ReadHeaderTimeout: metricsTimeout}
+	go func() {
+		log.Infof("Metrics server is running on port: %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorf("metricsServer.ListenAndServe: %v", err)
+		}
+	}()
+	return func() error {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), metricsTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	gatewayConfig "github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/controllers/access"
+	gatewayServer "github.com/JECSand/identity-service/api_gateway_service/server"
+	commandConfig "github.com/JECSand/identity-service/command_service/config"
+	commandServer "github.com/JECSand/identity-service/command_service/server"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	queryConfig "github.com/JECSand/identity-service/query_service/config"
+	queryServer "github.com/JECSand/identity-service/query_service/server"
+	"log"
+	"os"
+)
+
+const usage = `Usage: identity <command> [flags]
+
+Commands:
+  gateway   run the API gateway service
+  command   run the command service
+  query     run the query service
+  all       run every service in one process over in-process transports
+
+Run 'identity <command> -h' for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	args := os.Args[2:]
+	var err error
+	switch os.Args[1] {
+	case "gateway":
+		err = runGateway(args)
+	case "command":
+		err = runCommand(args)
+	case "query":
+		err = runQuery(args)
+	case "all":
+		err = runAll(args)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "identity: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newLogger(cfg *logging.Config, name string) logging.Logger {
+	logger := logging.NewAppLogger(cfg)
+	logger.InitLogger()
+	logger.WithName(name)
+	return logger
+}
+
+func newAuthenticator(log logging.Logger, cfg *gatewayConfig.Config) authentication.Authenticator {
+	authCfg := authentication.NewAuthConfig(1, 4380, cfg.ServiceSettings.JWTSalt)
+	return authentication.NewAuthenticator(log, access.DefaultAccessRules(), authCfg)
+}
+
+func runGateway(args []string) error {
+	flags := flag.NewFlagSet("gateway", flag.ExitOnError)
+	configPath := flags.String("config", "", "API Gateway service config path")
+	flags.Parse(args) // nolint: errCheck
+	cfg, err := gatewayConfig.InitConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	logger := newLogger(cfg.Logger, "GatewayService")
+	return gatewayServer.NewServer(logger, newAuthenticator(logger, cfg), cfg).Run()
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("command", flag.ExitOnError)
+	configPath := flags.String("config", "", "Command service config path")
+	flags.Parse(args) // nolint: errCheck
+	cfg, err := commandConfig.InitConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	return commandServer.NewServer(newLogger(cfg.Logger, "CommandService"), cfg).Run()
+}
+
+func runQuery(args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	configPath := flags.String("config", "", "Query service config path")
+	flags.Parse(args) // nolint: errCheck
+	cfg, err := queryConfig.InitConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	return queryServer.NewServer(newLogger(cfg.Logger, "QueryService"), cfg).Run()
+}
@@ -1,65 +1,95 @@
 package config
 
 import (
-	"flag"
 	"fmt"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/certs"
 	"github.com/JECSand/identity-service/pkg/constants"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/postgres"
 	"github.com/JECSand/identity-service/pkg/probes"
+	"github.com/JECSand/identity-service/pkg/sqlite"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"os"
+	"time"
 )
 
-var configPath string
-
-func init() {
-	flag.StringVar(&configPath, "config", "", "Command service config path")
+type Config struct {
+	ServiceName      string                              `mapstructure:"serviceName"`
+	Logger           *logging.Config                     `mapstructure:"logger"`
+	KafkaTopics      KafkaTopics                         `mapstructure:"kafkaTopics"`
+	GRPC             GRPC                                `mapstructure:"grpc"`
+	Postgresql       *postgres.Config                    `mapstructure:"postgres"`
+	Sqlite           *sqlite.Config                      `mapstructure:"sqlite"`
+	Kafka            *kafkaClient.Config                 `mapstructure:"kafka"`
+	Probes           probes.Config                       `mapstructure:"probes"`
+	Tracing          *tracing.Config                     `mapstructure:"tracing"`
+	Initialization   Initialization                      `mapstructure:"initialization"`
+	ServiceAuth      authentication.ServiceAuthConfig    `mapstructure:"serviceAuth"`
+	Password         authentication.PasswordConfig       `mapstructure:"password"`
+	PasswordPolicy   authentication.PasswordPolicyConfig `mapstructure:"passwordPolicy"`
+	MembershipExpiry MembershipExpiry                    `mapstructure:"membershipExpiry"`
 }
 
-type Config struct {
-	ServiceName    string              `mapstructure:"serviceName"`
-	Logger         *logging.Config     `mapstructure:"logger"`
-	KafkaTopics    KafkaTopics         `mapstructure:"kafkaTopics"`
-	GRPC           GRPC                `mapstructure:"grpc"`
-	Postgresql     *postgres.Config    `mapstructure:"postgres"`
-	Kafka          *kafkaClient.Config `mapstructure:"kafka"`
-	Probes         probes.Config       `mapstructure:"probes"`
-	Jaeger         *tracing.Config     `mapstructure:"jaeger"`
-	Initialization Initialization      `mapstructure:"initialization"`
+// MembershipExpiry configures the scheduler that ends and starts time-bound memberships and warns before they expire
+type MembershipExpiry struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Interval      time.Duration `mapstructure:"interval"`
+	WarningWindow time.Duration `mapstructure:"warningWindow"`
+	BatchSize     int           `mapstructure:"batchSize"`
 }
 
 type GRPC struct {
-	Port        string `mapstructure:"port"`
-	Development bool   `mapstructure:"development"`
+	Port        string       `mapstructure:"port"`
+	Development bool         `mapstructure:"development"`
+	TLS         certs.Config `mapstructure:"tls"`
 }
 
 type KafkaTopics struct {
-	UserCreate        kafkaClient.TopicConfig `mapstructure:"userCreate"`
-	UserCreated       kafkaClient.TopicConfig `mapstructure:"userCreated"`
-	UserUpdate        kafkaClient.TopicConfig `mapstructure:"userUpdate"`
-	UserUpdated       kafkaClient.TopicConfig `mapstructure:"userUpdated"`
-	UserDelete        kafkaClient.TopicConfig `mapstructure:"userDelete"`
-	UserDeleted       kafkaClient.TopicConfig `mapstructure:"userDeleted"`
-	GroupCreate       kafkaClient.TopicConfig `mapstructure:"groupCreate"`
-	GroupCreated      kafkaClient.TopicConfig `mapstructure:"groupCreated"`
-	GroupUpdate       kafkaClient.TopicConfig `mapstructure:"groupUpdate"`
-	GroupUpdated      kafkaClient.TopicConfig `mapstructure:"groupUpdated"`
-	GroupDelete       kafkaClient.TopicConfig `mapstructure:"groupDelete"`
-	GroupDeleted      kafkaClient.TopicConfig `mapstructure:"groupDeleted"`
-	MembershipCreate  kafkaClient.TopicConfig `mapstructure:"membershipCreate"`
-	MembershipCreated kafkaClient.TopicConfig `mapstructure:"membershipCreated"`
-	MembershipUpdate  kafkaClient.TopicConfig `mapstructure:"membershipUpdate"`
-	MembershipUpdated kafkaClient.TopicConfig `mapstructure:"membershipUpdated"`
-	MembershipDelete  kafkaClient.TopicConfig `mapstructure:"membershipDelete"`
-	MembershipDeleted kafkaClient.TopicConfig `mapstructure:"membershipDeleted"`
-	TokenBlacklist    kafkaClient.TopicConfig `mapstructure:"tokenBlacklist"`
-	TokenBlacklisted  kafkaClient.TopicConfig `mapstructure:"tokenBlacklisted"`
-	PasswordUpdate    kafkaClient.TopicConfig `mapstructure:"passwordUpdate"`
-	PasswordUpdated   kafkaClient.TopicConfig `mapstructure:"passwordUpdated"`
+	UserCreate                kafkaClient.TopicConfig `mapstructure:"userCreate"`
+	UserCreated               kafkaClient.TopicConfig `mapstructure:"userCreated"`
+	UserUpdate                kafkaClient.TopicConfig `mapstructure:"userUpdate"`
+	UserUpdated               kafkaClient.TopicConfig `mapstructure:"userUpdated"`
+	UserDelete                kafkaClient.TopicConfig `mapstructure:"userDelete"`
+	UserDeleted               kafkaClient.TopicConfig `mapstructure:"userDeleted"`
+	UserStatusChanged         kafkaClient.TopicConfig `mapstructure:"userStatusChanged"`
+	GroupCreate               kafkaClient.TopicConfig `mapstructure:"groupCreate"`
+	GroupCreated              kafkaClient.TopicConfig `mapstructure:"groupCreated"`
+	GroupUpdate               kafkaClient.TopicConfig `mapstructure:"groupUpdate"`
+	GroupUpdated              kafkaClient.TopicConfig `mapstructure:"groupUpdated"`
+	GroupDelete               kafkaClient.TopicConfig `mapstructure:"groupDelete"`
+	GroupDeleted              kafkaClient.TopicConfig `mapstructure:"groupDeleted"`
+	GroupParentChanged        kafkaClient.TopicConfig `mapstructure:"groupParentChanged"`
+	MembershipCreate          kafkaClient.TopicConfig `mapstructure:"membershipCreate"`
+	MembershipCreated         kafkaClient.TopicConfig `mapstructure:"membershipCreated"`
+	MembershipUpdate          kafkaClient.TopicConfig `mapstructure:"membershipUpdate"`
+	MembershipUpdated         kafkaClient.TopicConfig `mapstructure:"membershipUpdated"`
+	MembershipDelete          kafkaClient.TopicConfig `mapstructure:"membershipDelete"`
+	MembershipDeleted         kafkaClient.TopicConfig `mapstructure:"membershipDeleted"`
+	MembershipExtend          kafkaClient.TopicConfig `mapstructure:"membershipExtend"`
+	MembershipExtended        kafkaClient.TopicConfig `mapstructure:"membershipExtended"`
+	MembershipExpiring        kafkaClient.TopicConfig `mapstructure:"membershipExpiring"`
+	TokenBlacklist            kafkaClient.TopicConfig `mapstructure:"tokenBlacklist"`
+	TokenBlacklisted          kafkaClient.TopicConfig `mapstructure:"tokenBlacklisted"`
+	PasswordUpdate            kafkaClient.TopicConfig `mapstructure:"passwordUpdate"`
+	PasswordUpdated           kafkaClient.TopicConfig `mapstructure:"passwordUpdated"`
+	OrganizationCreate        kafkaClient.TopicConfig `mapstructure:"organizationCreate"`
+	OrganizationCreated       kafkaClient.TopicConfig `mapstructure:"organizationCreated"`
+	OrganizationUpdate        kafkaClient.TopicConfig `mapstructure:"organizationUpdate"`
+	OrganizationUpdated       kafkaClient.TopicConfig `mapstructure:"organizationUpdated"`
+	OrganizationStatusChange  kafkaClient.TopicConfig `mapstructure:"organizationStatusChange"`
+	OrganizationStatusChanged kafkaClient.TopicConfig `mapstructure:"organizationStatusChanged"`
+	OrganizationDelete        kafkaClient.TopicConfig `mapstructure:"organizationDelete"`
+	OrganizationDeleted       kafkaClient.TopicConfig `mapstructure:"organizationDeleted"`
+	RoleCreate                kafkaClient.TopicConfig `mapstructure:"roleCreate"`
+	RoleCreated               kafkaClient.TopicConfig `mapstructure:"roleCreated"`
+	RoleUpdate                kafkaClient.TopicConfig `mapstructure:"roleUpdate"`
+	RoleUpdated               kafkaClient.TopicConfig `mapstructure:"roleUpdated"`
+	RoleDelete                kafkaClient.TopicConfig `mapstructure:"roleDelete"`
+	RoleDeleted               kafkaClient.TopicConfig `mapstructure:"roleDeleted"`
 }
 
 type InitUser struct {
@@ -76,7 +106,8 @@ type Initialization struct {
 	Users UsersInitialization `mapstructure:"users"`
 }
 
-func InitConfig() (*Config, error) {
+// InitConfig loads the config file at configPath, falling back to the CONFIG_PATH env and the working directory default
+func InitConfig(configPath string) (*Config, error) {
 	if configPath == "" {
 		configPathFromEnv := os.Getenv(constants.ConfigPath)
 		if configPathFromEnv != "" {
@@ -90,12 +121,13 @@ func InitConfig() (*Config, error) {
 		}
 	}
 	cfg := &Config{}
-	viper.SetConfigType(constants.Yaml)
-	viper.SetConfigFile(configPath)
-	if err := viper.ReadInConfig(); err != nil {
+	v := viper.New()
+	v.SetConfigType(constants.Yaml)
+	v.SetConfigFile(configPath)
+	if err := v.ReadInConfig(); err != nil {
 		return nil, errors.Wrap(err, "viper.ReadInConfig")
 	}
-	if err := viper.Unmarshal(cfg); err != nil {
+	if err := v.Unmarshal(cfg); err != nil {
 		return nil, errors.Wrap(err, "viper.Unmarshal")
 	}
 	grpcPort := os.Getenv(constants.GrpcPort)
@@ -110,13 +142,13 @@ func InitConfig() (*Config, error) {
 	if postgresPort != "" {
 		cfg.Postgresql.Port = postgresPort
 	}
-	jaegerAddr := os.Getenv(constants.JaegerHostPort)
-	if jaegerAddr != "" {
-		cfg.Jaeger.HostPort = jaegerAddr
+	otlpEndpoint := os.Getenv(constants.OtlpEndpoint)
+	if otlpEndpoint != "" {
+		cfg.Tracing.Endpoint = otlpEndpoint
 	}
 	kafkaBrokers := os.Getenv(constants.KafkaBrokers)
 	if kafkaBrokers != "" {
-		cfg.Kafka.Brokers = []string{kafkaBrokers}
+		cfg.Kafka.Brokers = kafkaClient.ParseBrokers(kafkaBrokers)
 	}
 	return cfg, nil
 }
@@ -4,17 +4,16 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/command_service/identity/queries"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
 	"github.com/JECSand/identity-service/command_service/mappings"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
-	"time"
 )
 
 // BlacklistTokenCmdHandler ...
@@ -23,26 +22,26 @@ type BlacklistTokenCmdHandler interface {
 }
 
 type blacklistTokenHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewBlacklistTokenHandler ...
-func NewBlacklistTokenHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *blacklistTokenHandler {
+func NewBlacklistTokenHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *blacklistTokenHandler {
 	return &blacklistTokenHandler{
-		log:           log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *blacklistTokenHandler) Handle(ctx context.Context, command *BlacklistTokenCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistTokenHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "blacklistTokenHandler.Handle")
+	defer span.End()
 	blDTO := &models.Blacklist{
 		ID:          command.ID,
 		AccessToken: command.AccessToken,
@@ -52,17 +51,11 @@ func (c *blacklistTokenHandler) Handle(ctx context.Context, command *BlacklistTo
 		return err
 	}
 	msg := &kafkaMessages.TokenBlacklisted{Blacklist: mappings.BlacklistToGrpcMessage(bl)}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.TokenBlacklisted.TopicName, kafkaClient.TokenBlacklistedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.TokenBlacklisted.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.publisher.Publish(ctx, message)
 }
 
 // PasswordUpdateCmdHandler ...
@@ -74,29 +67,36 @@ type passwordUpdateHandler struct {
 	log           logging.Logger
 	cfg           *config.Config
 	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	publisher     messaging.Publisher
+	hasher        authentication.PasswordHasher
+	checkPassword queries.CheckPasswordHandler
 }
 
 // NewUpdatePasswordHandler ...
-func NewUpdatePasswordHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *passwordUpdateHandler {
+func NewUpdatePasswordHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher, hasher authentication.PasswordHasher, checkPassword queries.CheckPasswordHandler) *passwordUpdateHandler {
 	return &passwordUpdateHandler{
 		log:           log,
 		cfg:           cfg,
 		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		publisher:     publisher,
+		hasher:        hasher,
+		checkPassword: checkPassword,
 	}
 }
 
-// Handle ...
+// Handle sets the new password once it passes the password policy, including the password history of the user
 func (c *passwordUpdateHandler) Handle(ctx context.Context, command *PasswordUpdateCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "passwordUpdateHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "passwordUpdateHandler.Handle")
+	defer span.End()
+	if err := c.checkPassword.Handle(ctx, queries.NewCheckPasswordQuery(command.ID, "", "", command.NewPassword)); err != nil {
+		return err
+	}
 	authDTO := &models.User{
 		ID:       command.ID,
 		Password: command.NewPassword,
 	}
 	// TODO: QUERY FOR USER RECORD BY ID, HASH COMMAND PASSWORD, AND COMPARE CURRENT COMMAND PASSWORD WITH USER PASSWORD
-	if err := authDTO.HashPassword(); err != nil {
+	if err := authDTO.HashPassword(c.hasher); err != nil {
 		return err
 	}
 	user, err := c.pgRepo.UpdateUserPassword(ctx, authDTO)
@@ -104,20 +104,13 @@ func (c *passwordUpdateHandler) Handle(ctx context.Context, command *PasswordUpd
 		return err
 	}
 	msg := &kafkaMessages.PasswordUpdated{
-		ID:          user.ID.String(),
-		NewPassword: authDTO.Password,
-		Status:      200,
-		UpdatedAt:   timestamppb.New(user.UpdatedAt),
+		ID:        user.ID.String(),
+		Status:    200,
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
 	}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.PasswordUpdated.TopicName, kafkaClient.PasswordUpdatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.PasswordUpdated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.publisher.Publish(ctx, message)
 }
@@ -6,37 +6,45 @@ import (
 
 // GroupCommands ...
 type GroupCommands struct {
-	CreateGroup CreateGroupCmdHandler
-	UpdateGroup UpdateGroupCmdHandler
-	DeleteGroup DeleteGroupCmdHandler
+	CreateGroup    CreateGroupCmdHandler
+	UpdateGroup    UpdateGroupCmdHandler
+	DeleteGroup    DeleteGroupCmdHandler
+	SetGroupParent SetGroupParentCmdHandler
 }
 
 // NewGroupCommands ...
-func NewGroupCommands(createUser CreateGroupCmdHandler, updateUser UpdateGroupCmdHandler, deleteUser DeleteGroupCmdHandler) *GroupCommands {
+func NewGroupCommands(createUser CreateGroupCmdHandler, updateUser UpdateGroupCmdHandler, deleteUser DeleteGroupCmdHandler, setGroupParent SetGroupParentCmdHandler) *GroupCommands {
 	return &GroupCommands{
-		CreateGroup: createUser,
-		UpdateGroup: updateUser,
-		DeleteGroup: deleteUser,
+		CreateGroup:    createUser,
+		UpdateGroup:    updateUser,
+		DeleteGroup:    deleteUser,
+		SetGroupParent: setGroupParent,
 	}
 }
 
 // CreateGroupCommand ...
 type CreateGroupCommand struct {
-	ID          uuid.UUID `json:"id" validate:"required"`
-	Name        string    `json:"name" validate:"required,gte=0,lte=255"`
-	Description string    `json:"description" validate:"required,gte=0,lte=5000"`
-	CreatorID   uuid.UUID `json:"creatorID" validate:"required"`
-	Active      bool      `json:"active"`
+	ID             uuid.UUID `json:"id" validate:"required"`
+	Name           string    `json:"name" validate:"required,gte=0,lte=255"`
+	Description    string    `json:"description" validate:"required,gte=0,lte=5000"`
+	CreatorID      uuid.UUID `json:"creatorID" validate:"required"`
+	Active         bool      `json:"active"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	ParentID       uuid.UUID `json:"parentID"`
+	TenantID       string    `json:"tenantID"`
 }
 
 // NewCreateGroupCommand ...
-func NewCreateGroupCommand(id uuid.UUID, name string, description string, creatorId uuid.UUID, active bool) *CreateGroupCommand {
+func NewCreateGroupCommand(id uuid.UUID, name string, description string, creatorId uuid.UUID, active bool, organizationId uuid.UUID, parentId uuid.UUID, tenantId string) *CreateGroupCommand {
 	return &CreateGroupCommand{
-		ID:          id,
-		Name:        name,
-		Description: description,
-		CreatorID:   creatorId,
-		Active:      active,
+		ID:             id,
+		Name:           name,
+		Description:    description,
+		CreatorID:      creatorId,
+		Active:         active,
+		OrganizationID: organizationId,
+		ParentID:       parentId,
+		TenantID:       tenantId,
 	}
 }
 
@@ -45,23 +53,38 @@ type UpdateGroupCommand struct {
 	ID          uuid.UUID `json:"id" validate:"required,gte=0,lte=255"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	TenantID    string    `json:"tenantID"`
 }
 
 // NewUpdateGroupCommand ...
-func NewUpdateGroupCommand(id uuid.UUID, name string, description string) *UpdateGroupCommand {
+func NewUpdateGroupCommand(id uuid.UUID, name string, description string, tenantId string) *UpdateGroupCommand {
 	return &UpdateGroupCommand{
 		ID:          id,
 		Name:        name,
 		Description: description,
+		TenantID:    tenantId,
 	}
 }
 
 // DeleteGroupCommand ...
 type DeleteGroupCommand struct {
-	ID uuid.UUID `json:"id" validate:"required"`
+	ID       uuid.UUID `json:"id" validate:"required"`
+	TenantID string    `json:"tenantID"`
 }
 
 // NewDeleteGroupCommand ...
-func NewDeleteGroupCommand(id uuid.UUID) *DeleteGroupCommand {
-	return &DeleteGroupCommand{ID: id}
+func NewDeleteGroupCommand(id uuid.UUID, tenantId string) *DeleteGroupCommand {
+	return &DeleteGroupCommand{ID: id, TenantID: tenantId}
+}
+
+// SetGroupParentCommand moves a group below ParentID, or to the top level when ParentID is uuid.Nil
+type SetGroupParentCommand struct {
+	ID       uuid.UUID `json:"id" validate:"required"`
+	ParentID uuid.UUID `json:"parentID"`
+	TenantID string    `json:"tenantID"`
+}
+
+// NewSetGroupParentCommand ...
+func NewSetGroupParentCommand(id uuid.UUID, parentId uuid.UUID, tenantId string) *SetGroupParentCommand {
+	return &SetGroupParentCommand{ID: id, ParentID: parentId, TenantID: tenantId}
 }
@@ -6,14 +6,21 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
 	"github.com/JECSand/identity-service/command_service/mappings"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
-	"time"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrGroupHierarchyCycle is returned when a group would become its own ancestor
+	ErrGroupHierarchyCycle = repositories.ErrGroupHierarchyCycle
+	// ErrParentGroupOrganization is returned when a group is nested below a group of another organization
+	ErrParentGroupOrganization = errors.New("parent group belongs to another organization")
 )
 
 // CreateGroupCmdHandler ...
@@ -22,49 +29,53 @@ type CreateGroupCmdHandler interface {
 }
 
 type createGroupHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewCreateGroupHandler ...
-func NewCreateGroupHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *createGroupHandler {
+func NewCreateGroupHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *createGroupHandler {
 	return &createGroupHandler{
-		log:           log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *createGroupHandler) Handle(ctx context.Context, command *CreateGroupCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createGroupHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createGroupHandler.Handle")
+	defer span.End()
+	if err := checkOrganization(ctx, c.pgRepo, command.TenantID, command.OrganizationID); err != nil {
+		return err
+	}
+	if command.ParentID != uuid.Nil {
+		if err := checkParentGroup(ctx, c.pgRepo, command.OrganizationID, command.ID, command.ParentID); err != nil {
+			return err
+		}
+	}
 	groupDTO := &models.Group{
-		ID:          command.ID,
-		Name:        command.Name,
-		Description: command.Description,
-		CreatorID:   command.CreatorID,
-		Active:      command.Active,
+		ID:             command.ID,
+		Name:           command.Name,
+		Description:    command.Description,
+		CreatorID:      command.CreatorID,
+		Active:         command.Active,
+		OrganizationID: command.OrganizationID,
+		ParentID:       command.ParentID,
 	}
 	group, err := c.pgRepo.CreateGroup(ctx, groupDTO)
 	if err != nil {
 		return err
 	}
 	msg := &kafkaMessages.GroupCreated{Group: mappings.GroupToGrpcMessage(group)}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.GroupCreated.TopicName, kafkaClient.GroupCreatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.GroupCreated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.publisher.Publish(ctx, message)
 }
 
 // UpdateGroupCmdHandler ...
@@ -73,25 +84,28 @@ type UpdateGroupCmdHandler interface {
 }
 
 type updateGroupHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewUpdateGroupHandler ...
-func NewUpdateGroupHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *updateGroupHandler {
+func NewUpdateGroupHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *updateGroupHandler {
 	return &updateGroupHandler{log: log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *updateGroupHandler) Handle(ctx context.Context, command *UpdateGroupCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateGroupHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updateGroupHandler.Handle")
+	defer span.End()
+	if err := checkGroupTenant(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
 	groupDTO := &models.Group{
 		ID:          command.ID,
 		Name:        command.Name,
@@ -102,17 +116,11 @@ func (c *updateGroupHandler) Handle(ctx context.Context, command *UpdateGroupCom
 		return err
 	}
 	msg := &kafkaMessages.GroupUpdated{Group: mappings.GroupToGrpcMessage(user)}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.GroupUpdated.TopicName, kafkaClient.GroupUpdatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.GroupUpdated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.publisher.Publish(ctx, message)
 }
 
 // DeleteGroupCmdHandler ...
@@ -121,39 +129,116 @@ type DeleteGroupCmdHandler interface {
 }
 
 type deleteGroupHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewDeleteGroupHandler ...
-func NewDeleteGroupHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *deleteGroupHandler {
+func NewDeleteGroupHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *deleteGroupHandler {
 	return &deleteGroupHandler{
-		log:           log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *deleteGroupHandler) Handle(ctx context.Context, command *DeleteGroupCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteGroupHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "deleteGroupHandler.Handle")
+	defer span.End()
+	if err := checkGroupTenant(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
 	if err := c.pgRepo.DeleteGroupById(ctx, command.ID); err != nil {
 		return err
 	}
 	msg := &kafkaMessages.GroupDeleted{ID: command.ID.String()}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.GroupDeleted.TopicName, kafkaClient.GroupDeletedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// SetGroupParentCmdHandler ...
+type SetGroupParentCmdHandler interface {
+	Handle(ctx context.Context, command *SetGroupParentCommand) (*models.Group, error)
+}
+
+type setGroupParentHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewSetGroupParentHandler ...
+func NewSetGroupParentHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *setGroupParentHandler {
+	return &setGroupParentHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle moves a group within the hierarchy of its organization synchronously, so that a move creating a cycle
+// is reported to the caller
+func (c *setGroupParentHandler) Handle(ctx context.Context, command *SetGroupParentCommand) (*models.Group, error) {
+	ctx, span := tracing.StartSpan(ctx, "setGroupParentHandler.Handle")
+	defer span.End()
+	group, err := c.pgRepo.GetGroupById(ctx, command.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = authentication.CheckTenant(command.TenantID, group.OrganizationID.String()); err != nil {
+		return nil, err
+	}
+	if command.ParentID != uuid.Nil {
+		if err = checkParentGroup(ctx, c.pgRepo, group.OrganizationID, command.ID, command.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := c.pgRepo.SetGroupParent(ctx, command.ID, command.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	msg := &kafkaMessages.GroupParentChanged{Group: mappings.GroupToGrpcMessage(updated)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.GroupParentChanged.TopicName, kafkaClient.GroupParentChangedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err = c.publisher.Publish(ctx, message); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// checkParentGroup walks up from parentID and returns ErrGroupHierarchyCycle when it reaches the group with id,
+// or ErrParentGroupOrganization when the parent group is not part of organizationID. The walk rejects most moves
+// early; SetGroupParent checks again atomically with the update, as a concurrent move may change the ancestors
+func checkParentGroup(ctx context.Context, pgRepo repositories.Repository, organizationID uuid.UUID, id uuid.UUID, parentID uuid.UUID) error {
+	parent, err := pgRepo.GetGroupById(ctx, parentID)
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.GroupDeleted.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
+	if parent.OrganizationID != organizationID {
+		return ErrParentGroupOrganization
+	}
+	visited := map[uuid.UUID]bool{}
+	for ancestor := parent; ; {
+		if ancestor.ID == id || visited[ancestor.ID] {
+			return ErrGroupHierarchyCycle
+		}
+		visited[ancestor.ID] = true
+		if ancestor.ParentID == uuid.Nil {
+			return nil
+		}
+		if ancestor, err = pgRepo.GetGroupById(ctx, ancestor.ParentID); err != nil {
+			return err
+		}
 	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
 }
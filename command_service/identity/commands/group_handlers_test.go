@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestCheckParentGroup(t *testing.T) {
+	ctx := context.Background()
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	repo := repositories.NewMemoryRepository(log, &config.Config{})
+	org, otherOrg := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	creator, err := repo.CreateUser(ctx, &models.User{ID: uuid.Must(uuid.NewV4()), Email: "creator@example.com", Username: "creator", Active: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	root, child, grandchild, sibling, foreign := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	for _, group := range []*models.Group{
+		{ID: root, Name: "root", CreatorID: creator.ID, OrganizationID: org, Active: true},
+		{ID: child, Name: "child", CreatorID: creator.ID, OrganizationID: org, Active: true, ParentID: root},
+		{ID: grandchild, Name: "grandchild", CreatorID: creator.ID, OrganizationID: org, Active: true, ParentID: child},
+		{ID: sibling, Name: "sibling", CreatorID: creator.ID, OrganizationID: org, Active: true},
+		{ID: foreign, Name: "foreign", CreatorID: creator.ID, OrganizationID: otherOrg, Active: true},
+	} {
+		if _, err := repo.CreateGroup(ctx, group); err != nil {
+			t.Fatalf("CreateGroup: %v", err)
+		}
+	}
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		parent  uuid.UUID
+		wantErr error
+	}{
+		{name: "self parent", id: root, parent: root, wantErr: ErrGroupHierarchyCycle},
+		{name: "direct cycle", id: root, parent: child, wantErr: ErrGroupHierarchyCycle},
+		{name: "indirect cycle", id: root, parent: grandchild, wantErr: ErrGroupHierarchyCycle},
+		{name: "cross organization", id: sibling, parent: foreign, wantErr: ErrParentGroupOrganization},
+		{name: "below a descendant of another group", id: sibling, parent: grandchild},
+		{name: "new group below an existing one", id: uuid.Must(uuid.NewV4()), parent: grandchild},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkParentGroup(ctx, repo, org, tt.id, tt.parent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkParentGroup: got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if err := checkParentGroup(ctx, repo, org, root, uuid.Must(uuid.NewV4())); err == nil {
+		t.Error("checkParentGroup accepted an unknown parent")
+	}
+	if _, err := repo.SetGroupParent(ctx, root, grandchild); !errors.Is(err, ErrGroupHierarchyCycle) {
+		t.Errorf("SetGroupParent: got %v, want %v", err, ErrGroupHierarchyCycle)
+	}
+}
@@ -3,66 +3,115 @@ package commands
 import (
 	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/gofrs/uuid"
+	"time"
 )
 
 // MembershipCommands ...
 type MembershipCommands struct {
-	CreateMembership CreateMembershipCmdHandler
-	UpdateMembership UpdateMembershipCmdHandler
-	DeleteMembership DeleteMembershipCmdHandler
+	CreateMembership  CreateMembershipCmdHandler
+	UpdateMembership  UpdateMembershipCmdHandler
+	DeleteMembership  DeleteMembershipCmdHandler
+	ExtendMembership  ExtendMembershipCmdHandler
+	ExpireMemberships ExpireMembershipsCmdHandler
 }
 
 // NewMembershipCommands ...
-func NewMembershipCommands(createMembership CreateMembershipCmdHandler, updateMembership UpdateMembershipCmdHandler, deleteMembership DeleteMembershipCmdHandler) *MembershipCommands {
+func NewMembershipCommands(createMembership CreateMembershipCmdHandler, updateMembership UpdateMembershipCmdHandler, deleteMembership DeleteMembershipCmdHandler, extendMembership ExtendMembershipCmdHandler, expireMemberships ExpireMembershipsCmdHandler) *MembershipCommands {
 	return &MembershipCommands{
-		CreateMembership: createMembership,
-		UpdateMembership: updateMembership,
-		DeleteMembership: deleteMembership,
+		CreateMembership:  createMembership,
+		UpdateMembership:  updateMembership,
+		DeleteMembership:  deleteMembership,
+		ExtendMembership:  extendMembership,
+		ExpireMemberships: expireMemberships,
 	}
 }
 
 // CreateMembershipCommand ...
 type CreateMembershipCommand struct {
-	ID      uuid.UUID              `json:"id"`
-	UserID  uuid.UUID              `json:"userID,omitempty"`
-	GroupID uuid.UUID              `json:"groupID,omitempty"`
-	Status  enums.MembershipStatus `json:"status,omitempty"`
-	Role    enums.Role             `json:"role,omitempty"`
+	ID         uuid.UUID              `json:"id"`
+	UserID     uuid.UUID              `json:"userID,omitempty"`
+	GroupID    uuid.UUID              `json:"groupID,omitempty"`
+	Status     enums.MembershipStatus `json:"status,omitempty"`
+	Role       enums.Role             `json:"role,omitempty"`
+	RoleID     uuid.UUID              `json:"roleID,omitempty"`
+	ValidFrom  time.Time              `json:"validFrom,omitempty"`
+	ValidUntil time.Time              `json:"validUntil,omitempty"`
+	TenantID   string                 `json:"tenantID,omitempty"`
 }
 
 // NewCreateMembershipCommand ...
-func NewCreateMembershipCommand(id uuid.UUID, userId uuid.UUID, groupId uuid.UUID, status enums.MembershipStatus, role enums.Role) *CreateMembershipCommand {
+func NewCreateMembershipCommand(id uuid.UUID, userId uuid.UUID, groupId uuid.UUID, status enums.MembershipStatus, role enums.Role, roleId uuid.UUID, validFrom time.Time, validUntil time.Time, tenantId string) *CreateMembershipCommand {
 	return &CreateMembershipCommand{
-		ID:      id,
-		UserID:  userId,
-		GroupID: groupId,
-		Status:  status,
-		Role:    role,
+		ID:         id,
+		UserID:     userId,
+		GroupID:    groupId,
+		Status:     status,
+		Role:       role,
+		RoleID:     roleId,
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+		TenantID:   tenantId,
 	}
 }
 
 // UpdateMembershipCommand ...
 type UpdateMembershipCommand struct {
-	ID     uuid.UUID              `json:"id" validate:"required,gte=0,lte=255"`
-	Status enums.MembershipStatus `json:"status,omitempty"`
-	Role   enums.Role             `json:"role,omitempty"`
+	ID       uuid.UUID              `json:"id" validate:"required,gte=0,lte=255"`
+	Status   enums.MembershipStatus `json:"status,omitempty"`
+	Role     enums.Role             `json:"role,omitempty"`
+	RoleID   uuid.UUID              `json:"roleID,omitempty"`
+	TenantID string                 `json:"tenantID,omitempty"`
 }
 
 // NewUpdateMembershipCommand ...
-func NewUpdateMembershipCommand(id uuid.UUID, status enums.MembershipStatus, role enums.Role) *UpdateMembershipCommand {
+func NewUpdateMembershipCommand(id uuid.UUID, status enums.MembershipStatus, role enums.Role, roleId uuid.UUID, tenantId string) *UpdateMembershipCommand {
 	return &UpdateMembershipCommand{
-		ID:     id,
-		Status: status,
-		Role:   role,
+		ID:       id,
+		Status:   status,
+		Role:     role,
+		RoleID:   roleId,
+		TenantID: tenantId,
 	}
 }
 
 // DeleteMembershipCommand ...
 type DeleteMembershipCommand struct {
-	ID uuid.UUID `json:"id" validate:"required"`
+	ID       uuid.UUID `json:"id" validate:"required"`
+	TenantID string    `json:"tenantID"`
 }
 
 // NewDeleteMembershipCommand ...
-func NewDeleteMembershipCommand(id uuid.UUID) *DeleteMembershipCommand {
-	return &DeleteMembershipCommand{ID: id}
+func NewDeleteMembershipCommand(id uuid.UUID, tenantId string) *DeleteMembershipCommand {
+	return &DeleteMembershipCommand{ID: id, TenantID: tenantId}
+}
+
+// ExtendMembershipCommand moves the end of the validity window of a membership to ValidUntil
+type ExtendMembershipCommand struct {
+	ID         uuid.UUID `json:"id" validate:"required"`
+	ValidUntil time.Time `json:"validUntil" validate:"required"`
+	Reason     string    `json:"reason,omitempty"`
+	ActorID    uuid.UUID `json:"actorID,omitempty"`
+	TenantID   string    `json:"tenantID,omitempty"`
+}
+
+// NewExtendMembershipCommand ...
+func NewExtendMembershipCommand(id uuid.UUID, validUntil time.Time, reason string, actorId uuid.UUID, tenantId string) *ExtendMembershipCommand {
+	return &ExtendMembershipCommand{
+		ID:         id,
+		ValidUntil: validUntil,
+		Reason:     reason,
+		ActorID:    actorId,
+		TenantID:   tenantId,
+	}
+}
+
+// ExpireMembershipsCommand disables the memberships that ended by Now, activates the ones that started and warns
+// about the ones ending within the warning window
+type ExpireMembershipsCommand struct {
+	Now time.Time `json:"now"`
+}
+
+// NewExpireMembershipsCommand ...
+func NewExpireMembershipsCommand(now time.Time) *ExpireMembershipsCommand {
+	return &ExpireMembershipsCommand{Now: now}
 }
@@ -6,48 +6,85 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
 	"github.com/JECSand/identity-service/command_service/mappings"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"time"
 )
 
+// defaultExpiryBatchSize bounds the memberships handled per expiry run when membershipExpiry.batchSize is unset
+const defaultExpiryBatchSize = 100
+
 // CreateMembershipCmdHandler ...
 type CreateMembershipCmdHandler interface {
 	Handle(ctx context.Context, command *CreateMembershipCommand) error
 }
 
 type createMembershipHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewCreateMembershipHandler ...
-func NewCreateMembershipHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *createMembershipHandler {
+func NewCreateMembershipHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *createMembershipHandler {
 	return &createMembershipHandler{
-		log:           log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *createMembershipHandler) Handle(ctx context.Context, command *CreateMembershipCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createMembershipHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createMembershipHandler.Handle")
+	defer span.End()
+	user, err := c.pgRepo.GetUserById(ctx, command.UserID)
+	if err != nil {
+		return err
+	}
+	group, err := c.pgRepo.GetGroupById(ctx, command.GroupID)
+	if err != nil {
+		return err
+	}
+	if err = authentication.CheckTenant(command.TenantID, group.OrganizationID.String()); err != nil {
+		return err
+	}
+	if user.OrganizationID != group.OrganizationID {
+		return errors.New("user and group belong to different organizations")
+	}
+	role, err := membershipRole(ctx, c.pgRepo, command.RoleID, command.Role, group)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err = authentication.CheckMembershipWindow(command.ValidFrom, command.ValidUntil, now); err != nil {
+		return err
+	}
+	if err = authentication.CheckMembershipStart(command.Status, command.ValidFrom, now); err != nil {
+		return err
+	}
+	status := authentication.EffectiveMembershipStatus(command.Status, command.ValidFrom, command.ValidUntil, now)
 	membershipDTO := &models.Membership{
-		ID:      command.ID,
-		UserID:  command.UserID,
-		GroupID: command.GroupID,
-		Status:  command.Status,
-		Role:    command.Role,
+		ID:             command.ID,
+		UserID:         command.UserID,
+		GroupID:        command.GroupID,
+		Status:         status,
+		ScheduledStart: command.Status == enums.ACTIVE && status == enums.PENDING,
+		Role:           role.EnumRole(),
+		RoleID:         role.ID,
+		OrganizationID: group.OrganizationID,
+		ValidFrom:      command.ValidFrom,
+		ValidUntil:     command.ValidUntil,
 	}
 	membership, err := c.pgRepo.CreateMembership(ctx, membershipDTO)
 	if err != nil {
@@ -97,22 +134,19 @@ func (c *createMembershipHandler) Handle(ctx context.Context, command *CreateMem
 			}
 		}
 	}
+	membership.RoleName, membership.Permissions = role.Name, role.Permissions
+	userMembership.RoleName, userMembership.Permissions = role.Name, role.Permissions
+	groupMembership.RoleName, groupMembership.Permissions = role.Name, role.Permissions
 	msg := &kafkaMessages.MembershipCreated{
 		Membership:      mappings.MembershipToGrpcMessage(membership),
 		UserMembership:  mappings.UserMembershipToGrpcMessage(userMembership),
 		GroupMembership: mappings.GroupMembershipToGrpcMessage(groupMembership),
 	}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipCreated.TopicName, kafkaClient.MembershipCreatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.MembershipCreated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.publisher.Publish(ctx, message)
 }
 
 // UpdateMembershipCmdHandler ...
@@ -121,46 +155,70 @@ type UpdateMembershipCmdHandler interface {
 }
 
 type updateMembershipHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewUpdateMembershipHandler ...
-func NewUpdateMembershipHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *updateMembershipHandler {
+func NewUpdateMembershipHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *updateMembershipHandler {
 	return &updateMembershipHandler{log: log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *updateMembershipHandler) Handle(ctx context.Context, command *UpdateMembershipCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateMembershipHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updateMembershipHandler.Handle")
+	defer span.End()
+	if err := checkMembershipTenant(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
 	membershipDTO := &models.Membership{
 		ID:     command.ID,
 		Status: command.Status,
 		Role:   command.Role,
+		RoleID: command.RoleID,
+	}
+	if command.RoleID != uuid.Nil || command.Role != 0 {
+		current, err := c.pgRepo.GetMembershipById(ctx, command.ID)
+		if err != nil {
+			return err
+		}
+		group, err := c.pgRepo.GetGroupById(ctx, current.GroupID)
+		if err != nil {
+			return err
+		}
+		role, err := membershipRole(ctx, c.pgRepo, command.RoleID, command.Role, group)
+		if err != nil {
+			return err
+		}
+		membershipDTO.Role, membershipDTO.RoleID = role.EnumRole(), role.ID
 	}
-	user, err := c.pgRepo.UpdateMembership(ctx, membershipDTO)
+	membership, err := c.pgRepo.UpdateMembership(ctx, membershipDTO)
 	if err != nil {
 		return err
 	}
-	msg := &kafkaMessages.MembershipUpdated{Membership: mappings.MembershipToGrpcMessage(user)}
-	msgBytes, err := proto.Marshal(msg)
+	return publishMembershipUpdated(ctx, c.cfg, c.pgRepo, c.publisher, membership)
+}
+
+// publishMembershipUpdated publishes a MembershipUpdated event for membership, carrying the name and permissions of
+// its role
+func publishMembershipUpdated(ctx context.Context, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher, membership *models.Membership) error {
+	role, err := pgRepo.GetRoleById(ctx, membership.RoleID)
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.MembershipUpdated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
+	membership.RoleName, membership.Permissions = role.Name, role.Permissions
+	msg := &kafkaMessages.MembershipUpdated{Membership: mappings.MembershipToGrpcMessage(membership)}
+	message, err := kafkaClient.NewEventMessage(ctx, cfg.KafkaTopics.MembershipUpdated.TopicName, kafkaClient.MembershipUpdatedEvent, membership.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return publisher.Publish(ctx, message)
 }
 
 // DeleteMembershipCmdHandler ...
@@ -169,39 +227,201 @@ type DeleteMembershipCmdHandler interface {
 }
 
 type deleteMembershipHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewDeleteMembershipHandler ...
-func NewDeleteMembershipHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *deleteMembershipHandler {
+func NewDeleteMembershipHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *deleteMembershipHandler {
 	return &deleteMembershipHandler{
-		log:           log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *deleteMembershipHandler) Handle(ctx context.Context, command *DeleteMembershipCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteMembershipHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "deleteMembershipHandler.Handle")
+	defer span.End()
+	if err := checkMembershipTenant(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
 	if err := c.pgRepo.DeleteMembershipById(ctx, command.ID); err != nil {
 		return err
 	}
 	msg := &kafkaMessages.MembershipDeleted{ID: command.ID.String()}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipDeleted.TopicName, kafkaClient.MembershipDeletedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// ExtendMembershipCmdHandler ...
+type ExtendMembershipCmdHandler interface {
+	Handle(ctx context.Context, command *ExtendMembershipCommand) error
+}
+
+type extendMembershipHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewExtendMembershipHandler ...
+func NewExtendMembershipHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *extendMembershipHandler {
+	return &extendMembershipHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle moves the end of the validity window of a membership, reactivating it when it was disabled by expiry, and
+// audits the change
+func (c *extendMembershipHandler) Handle(ctx context.Context, command *ExtendMembershipCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "extendMembershipHandler.Handle")
+	defer span.End()
+	if err := checkMembershipTenant(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
+	current, err := c.pgRepo.GetMembershipById(ctx, command.ID)
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.MembershipDeleted.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
+	now := time.Now()
+	if err = authentication.CheckMembershipWindow(current.ValidFrom, command.ValidUntil, now); err != nil {
+		return err
+	}
+	membershipDTO := &models.Membership{
+		ID:         command.ID,
+		ValidUntil: command.ValidUntil,
+	}
+	if current.Status == enums.DISABLED && !current.ValidUntil.IsZero() && !current.ValidUntil.After(now) {
+		membershipDTO.Status = authentication.EffectiveMembershipStatus(enums.ACTIVE, current.ValidFrom, command.ValidUntil, now)
+	}
+	membership, err := c.pgRepo.UpdateMembership(ctx, membershipDTO)
+	if err != nil {
+		return err
+	}
+	auditID, err := uuid.NewV4()
+	if err != nil {
+		return errors.Wrap(err, "uuid.NewV4")
+	}
+	audit := &models.MembershipAudit{
+		ID:                 auditID,
+		MembershipID:       command.ID,
+		Action:             models.MembershipAuditExtend,
+		ActorID:            command.ActorID,
+		Reason:             command.Reason,
+		PreviousValidUntil: current.ValidUntil,
+		ValidUntil:         command.ValidUntil,
+	}
+	if err = c.pgRepo.CreateMembershipAudit(ctx, audit); err != nil {
+		return err
+	}
+	if err = publishMembershipUpdated(ctx, c.cfg, c.pgRepo, c.publisher, membership); err != nil {
+		return err
+	}
+	msg := &kafkaMessages.MembershipExtended{
+		ID:         command.ID.String(),
+		ValidUntil: timestamppb.New(command.ValidUntil),
+		Reason:     command.Reason,
+		ActorID:    command.ActorID.String(),
+		ExtendedAt: timestamppb.New(membership.UpdatedAt),
+	}
+	if !current.ValidUntil.IsZero() {
+		msg.PreviousValidUntil = timestamppb.New(current.ValidUntil)
+	}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipExtended.TopicName, kafkaClient.MembershipExtendedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// ExpireMembershipsCmdHandler ...
+type ExpireMembershipsCmdHandler interface {
+	Handle(ctx context.Context, command *ExpireMembershipsCommand) error
+}
+
+type expireMembershipsHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewExpireMembershipsHandler ...
+func NewExpireMembershipsHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *expireMembershipsHandler {
+	return &expireMembershipsHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle moves a batch of memberships whose validity window started or ended to their effective status and warns
+// about the ones ending within the warning window; a membership that fails is logged and retried on the next run
+func (c *expireMembershipsHandler) Handle(ctx context.Context, command *ExpireMembershipsCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "expireMembershipsHandler.Handle")
+	defer span.End()
+	batchSize := c.cfg.MembershipExpiry.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExpiryBatchSize
+	}
+	warnBefore := command.Now
+	if c.cfg.MembershipExpiry.WarningWindow > 0 {
+		warnBefore = command.Now.Add(c.cfg.MembershipExpiry.WarningWindow)
+	}
+	due, err := c.pgRepo.GetMembershipsDue(ctx, command.Now, warnBefore, batchSize)
+	if err != nil {
+		return err
+	}
+	for _, membership := range due {
+		status := authentication.EffectiveMembershipStatus(enums.ACTIVE, membership.ValidFrom, membership.ValidUntil, command.Now)
+		if status == membership.Status {
+			err = c.warn(ctx, membership, command.Now)
+		} else {
+			err = c.transition(ctx, membership, status)
+		}
+		if err != nil {
+			c.log.WarnMsg("expireMembershipsHandler.Handle", errors.Wrapf(err, "membership %s", membership.ID))
+		}
+	}
+	return nil
+}
+
+// transition moves membership to status and publishes the change
+func (c *expireMembershipsHandler) transition(ctx context.Context, membership *models.Membership, status enums.MembershipStatus) error {
+	updated, err := c.pgRepo.UpdateMembership(ctx, &models.Membership{ID: membership.ID, Status: status})
+	if err != nil {
+		return err
+	}
+	return publishMembershipUpdated(ctx, c.cfg, c.pgRepo, c.publisher, updated)
+}
+
+// warn publishes a MembershipExpiring event for membership and marks it as warned
+func (c *expireMembershipsHandler) warn(ctx context.Context, membership *models.Membership, now time.Time) error {
+	role, err := c.pgRepo.GetRoleById(ctx, membership.RoleID)
+	if err != nil {
+		return err
+	}
+	membership.RoleName, membership.Permissions = role.Name, role.Permissions
+	msg := &kafkaMessages.MembershipExpiring{Membership: mappings.MembershipToGrpcMessage(membership)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.MembershipExpiring.TopicName, kafkaClient.MembershipExpiringEvent, membership.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	if err = c.publisher.Publish(ctx, message); err != nil {
+		return err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.pgRepo.MarkMembershipExpiryWarned(ctx, membership.ID, now)
 }
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// OrganizationCommands ...
+type OrganizationCommands struct {
+	CreateOrganization       CreateOrganizationCmdHandler
+	UpdateOrganization       UpdateOrganizationCmdHandler
+	ChangeOrganizationStatus ChangeOrganizationStatusCmdHandler
+	DeleteOrganization       DeleteOrganizationCmdHandler
+}
+
+// NewOrganizationCommands ...
+func NewOrganizationCommands(createOrganization CreateOrganizationCmdHandler, updateOrganization UpdateOrganizationCmdHandler, changeOrganizationStatus ChangeOrganizationStatusCmdHandler, deleteOrganization DeleteOrganizationCmdHandler) *OrganizationCommands {
+	return &OrganizationCommands{
+		CreateOrganization:       createOrganization,
+		UpdateOrganization:       updateOrganization,
+		ChangeOrganizationStatus: changeOrganizationStatus,
+		DeleteOrganization:       deleteOrganization,
+	}
+}
+
+// CreateOrganizationCommand ...
+type CreateOrganizationCommand struct {
+	ID          uuid.UUID `json:"id" validate:"required"`
+	Name        string    `json:"name" validate:"required,gte=0,lte=250"`
+	Description string    `json:"description" validate:"lte=250"`
+	TenantID    string    `json:"tenantID"`
+}
+
+// NewCreateOrganizationCommand ...
+func NewCreateOrganizationCommand(id uuid.UUID, name string, description string, tenantId string) *CreateOrganizationCommand {
+	return &CreateOrganizationCommand{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		TenantID:    tenantId,
+	}
+}
+
+// UpdateOrganizationCommand ...
+type UpdateOrganizationCommand struct {
+	ID          uuid.UUID `json:"id" validate:"required"`
+	Name        string    `json:"name" validate:"lte=250"`
+	Description string    `json:"description" validate:"lte=250"`
+	TenantID    string    `json:"tenantID"`
+}
+
+// NewUpdateOrganizationCommand ...
+func NewUpdateOrganizationCommand(id uuid.UUID, name string, description string, tenantId string) *UpdateOrganizationCommand {
+	return &UpdateOrganizationCommand{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		TenantID:    tenantId,
+	}
+}
+
+// ChangeOrganizationStatusCommand ...
+type ChangeOrganizationStatusCommand struct {
+	ID       uuid.UUID `json:"id" validate:"required"`
+	Active   bool      `json:"active"`
+	TenantID string    `json:"tenantID"`
+}
+
+// NewChangeOrganizationStatusCommand ...
+func NewChangeOrganizationStatusCommand(id uuid.UUID, active bool, tenantId string) *ChangeOrganizationStatusCommand {
+	return &ChangeOrganizationStatusCommand{
+		ID:       id,
+		Active:   active,
+		TenantID: tenantId,
+	}
+}
+
+// DeleteOrganizationCommand ...
+type DeleteOrganizationCommand struct {
+	ID       uuid.UUID `json:"id" validate:"required"`
+	TenantID string    `json:"tenantID"`
+}
+
+// NewDeleteOrganizationCommand ...
+func NewDeleteOrganizationCommand(id uuid.UUID, tenantId string) *DeleteOrganizationCommand {
+	return &DeleteOrganizationCommand{ID: id, TenantID: tenantId}
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/command_service/mappings"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"time"
+)
+
+// CreateOrganizationCmdHandler ...
+type CreateOrganizationCmdHandler interface {
+	Handle(ctx context.Context, command *CreateOrganizationCommand) error
+}
+
+type createOrganizationHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewCreateOrganizationHandler ...
+func NewCreateOrganizationHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *createOrganizationHandler {
+	return &createOrganizationHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle creates an active organization, which only unrestricted tenants can do
+func (c *createOrganizationHandler) Handle(ctx context.Context, command *CreateOrganizationCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "createOrganizationHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckTenant(command.TenantID, command.ID.String()); err != nil {
+		return err
+	}
+	organizationDTO := &models.Organization{
+		ID:          command.ID,
+		Name:        command.Name,
+		Description: command.Description,
+		Active:      true,
+	}
+	organization, err := c.pgRepo.CreateOrganization(ctx, organizationDTO)
+	if err != nil {
+		return err
+	}
+	msg := &kafkaMessages.OrganizationCreated{Organization: mappings.OrganizationToGrpcMessage(organization)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationCreated.TopicName, kafkaClient.OrganizationCreatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// UpdateOrganizationCmdHandler ...
+type UpdateOrganizationCmdHandler interface {
+	Handle(ctx context.Context, command *UpdateOrganizationCommand) error
+}
+
+type updateOrganizationHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewUpdateOrganizationHandler ...
+func NewUpdateOrganizationHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *updateOrganizationHandler {
+	return &updateOrganizationHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle ...
+func (c *updateOrganizationHandler) Handle(ctx context.Context, command *UpdateOrganizationCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "updateOrganizationHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckTenant(command.TenantID, command.ID.String()); err != nil {
+		return err
+	}
+	organizationDTO := &models.Organization{
+		ID:          command.ID,
+		Name:        command.Name,
+		Description: command.Description,
+	}
+	organization, err := c.pgRepo.UpdateOrganization(ctx, organizationDTO)
+	if err != nil {
+		return err
+	}
+	msg := &kafkaMessages.OrganizationUpdated{Organization: mappings.OrganizationToGrpcMessage(organization)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationUpdated.TopicName, kafkaClient.OrganizationUpdatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// ChangeOrganizationStatusCmdHandler ...
+type ChangeOrganizationStatusCmdHandler interface {
+	Handle(ctx context.Context, command *ChangeOrganizationStatusCommand) error
+}
+
+type changeOrganizationStatusHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewChangeOrganizationStatusHandler ...
+func NewChangeOrganizationStatusHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *changeOrganizationStatusHandler {
+	return &changeOrganizationStatusHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle suspends or reactivates an organization. Suspending it revokes every session issued to its users so far
+func (c *changeOrganizationStatusHandler) Handle(ctx context.Context, command *ChangeOrganizationStatusCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "changeOrganizationStatusHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckTenant(command.TenantID, command.ID.String()); err != nil {
+		return err
+	}
+	organizationDTO := &models.Organization{
+		ID:     command.ID,
+		Active: command.Active,
+	}
+	if !command.Active {
+		organizationDTO.SessionsRevokedAt = time.Now()
+	}
+	organization, err := c.pgRepo.UpdateOrganizationStatus(ctx, organizationDTO)
+	if err != nil {
+		return err
+	}
+	msg := &kafkaMessages.OrganizationStatusChanged{Organization: mappings.OrganizationToGrpcMessage(organization)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationStatusChanged.TopicName, kafkaClient.OrganizationStatusChangedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// DeleteOrganizationCmdHandler ...
+type DeleteOrganizationCmdHandler interface {
+	Handle(ctx context.Context, command *DeleteOrganizationCommand) error
+}
+
+type deleteOrganizationHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewDeleteOrganizationHandler ...
+func NewDeleteOrganizationHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *deleteOrganizationHandler {
+	return &deleteOrganizationHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle deletes an organization along with its users, groups and memberships
+func (c *deleteOrganizationHandler) Handle(ctx context.Context, command *DeleteOrganizationCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "deleteOrganizationHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckTenant(command.TenantID, command.ID.String()); err != nil {
+		return err
+	}
+	if _, err := c.pgRepo.GetOrganizationById(ctx, command.ID); err != nil {
+		return err
+	}
+	if err := c.pgRepo.DeleteOrganizationById(ctx, command.ID); err != nil {
+		return err
+	}
+	msg := &kafkaMessages.OrganizationDeleted{ID: command.ID.String()}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.OrganizationDeleted.TopicName, kafkaClient.OrganizationDeletedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
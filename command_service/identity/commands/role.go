@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// RoleCommands ...
+type RoleCommands struct {
+	CreateRole CreateRoleCmdHandler
+	UpdateRole UpdateRoleCmdHandler
+	DeleteRole DeleteRoleCmdHandler
+}
+
+// NewRoleCommands ...
+func NewRoleCommands(createRole CreateRoleCmdHandler, updateRole UpdateRoleCmdHandler, deleteRole DeleteRoleCmdHandler) *RoleCommands {
+	return &RoleCommands{
+		CreateRole: createRole,
+		UpdateRole: updateRole,
+		DeleteRole: deleteRole,
+	}
+}
+
+// CreateRoleCommand ...
+type CreateRoleCommand struct {
+	ID             uuid.UUID `json:"id" validate:"required"`
+	Name           string    `json:"name" validate:"required,gte=0,lte=250"`
+	Description    string    `json:"description" validate:"lte=250"`
+	Permissions    []string  `json:"permissions"`
+	GroupID        uuid.UUID `json:"groupID"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	TenantID       string    `json:"tenantID"`
+}
+
+// NewCreateRoleCommand ...
+func NewCreateRoleCommand(id uuid.UUID, name string, description string, permissions []string, groupId uuid.UUID, organizationId uuid.UUID, tenantId string) *CreateRoleCommand {
+	return &CreateRoleCommand{
+		ID:             id,
+		Name:           name,
+		Description:    description,
+		Permissions:    permissions,
+		GroupID:        groupId,
+		OrganizationID: organizationId,
+		TenantID:       tenantId,
+	}
+}
+
+// UpdateRoleCommand ...
+type UpdateRoleCommand struct {
+	ID          uuid.UUID `json:"id" validate:"required"`
+	Name        string    `json:"name" validate:"lte=250"`
+	Description string    `json:"description" validate:"lte=250"`
+	Permissions []string  `json:"permissions"`
+	TenantID    string    `json:"tenantID"`
+}
+
+// NewUpdateRoleCommand ...
+func NewUpdateRoleCommand(id uuid.UUID, name string, description string, permissions []string, tenantId string) *UpdateRoleCommand {
+	return &UpdateRoleCommand{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		TenantID:    tenantId,
+	}
+}
+
+// DeleteRoleCommand ...
+type DeleteRoleCommand struct {
+	ID       uuid.UUID `json:"id" validate:"required"`
+	TenantID string    `json:"tenantID"`
+}
+
+// NewDeleteRoleCommand ...
+func NewDeleteRoleCommand(id uuid.UUID, tenantId string) *DeleteRoleCommand {
+	return &DeleteRoleCommand{ID: id, TenantID: tenantId}
+}
@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/command_service/mappings"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// membershipRole returns the role a membership of group is granted: the role with roleID, or the built-in role of the
+// Role enum value when no roleID is given. Custom roles must be scoped to the group or to its organization
+func membershipRole(ctx context.Context, pgRepo repositories.Repository, roleID uuid.UUID, role enums.Role, group *models.Group) (*models.Role, error) {
+	if roleID == uuid.Nil {
+		if role == 0 {
+			role = enums.MEMBER
+		}
+		roleID = uuid.FromStringOrNil(role.RoleID())
+	}
+	found, err := pgRepo.GetRoleById(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if found.BuiltIn {
+		return found, nil
+	}
+	if found.OrganizationID != group.OrganizationID || (found.GroupID != uuid.Nil && found.GroupID != group.ID) {
+		return nil, authentication.ErrRoleOutOfScope
+	}
+	return found, nil
+}
+
+// checkCustomRole returns the custom role with id when it is visible to tenantID
+func checkCustomRole(ctx context.Context, pgRepo repositories.Repository, tenantID string, id uuid.UUID) (*models.Role, error) {
+	role, err := pgRepo.GetRoleById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = authentication.CheckTenant(tenantID, role.OrganizationID.String()); err != nil {
+		return nil, err
+	}
+	if role.BuiltIn {
+		return nil, authentication.ErrBuiltInRole
+	}
+	return role, nil
+}
+
+// CreateRoleCmdHandler ...
+type CreateRoleCmdHandler interface {
+	Handle(ctx context.Context, command *CreateRoleCommand) error
+}
+
+type createRoleHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewCreateRoleHandler ...
+func NewCreateRoleHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *createRoleHandler {
+	return &createRoleHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle creates a custom role scoped to a group, or to an organization when no group is given
+func (c *createRoleHandler) Handle(ctx context.Context, command *CreateRoleCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "createRoleHandler.Handle")
+	defer span.End()
+	if err := authentication.CheckRoleName(command.Name); err != nil {
+		return err
+	}
+	permissions, err := authentication.NormalizePermissions(command.Permissions)
+	if err != nil {
+		return err
+	}
+	organizationID := command.OrganizationID
+	if command.GroupID != uuid.Nil {
+		group, err := c.pgRepo.GetGroupById(ctx, command.GroupID)
+		if err != nil {
+			return err
+		}
+		if organizationID != uuid.Nil && organizationID != group.OrganizationID {
+			return errors.New("role group belongs to another organization")
+		}
+		organizationID = group.OrganizationID
+	}
+	if err = checkOrganization(ctx, c.pgRepo, command.TenantID, organizationID); err != nil {
+		return err
+	}
+	roleDTO := &models.Role{
+		ID:             command.ID,
+		Name:           command.Name,
+		Description:    command.Description,
+		Permissions:    permissions,
+		GroupID:        command.GroupID,
+		OrganizationID: organizationID,
+	}
+	role, err := c.pgRepo.CreateRole(ctx, roleDTO)
+	if err != nil {
+		return err
+	}
+	msg := &kafkaMessages.RoleCreated{Role: mappings.RoleToGrpcMessage(role)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.RoleCreated.TopicName, kafkaClient.RoleCreatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// UpdateRoleCmdHandler ...
+type UpdateRoleCmdHandler interface {
+	Handle(ctx context.Context, command *UpdateRoleCommand) error
+}
+
+type updateRoleHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewUpdateRoleHandler ...
+func NewUpdateRoleHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *updateRoleHandler {
+	return &updateRoleHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle updates a custom role; empty fields and a nil permission set keep their current values
+func (c *updateRoleHandler) Handle(ctx context.Context, command *UpdateRoleCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "updateRoleHandler.Handle")
+	defer span.End()
+	roleDTO, err := checkCustomRole(ctx, c.pgRepo, command.TenantID, command.ID)
+	if err != nil {
+		return err
+	}
+	if command.Name != "" {
+		if err = authentication.CheckRoleName(command.Name); err != nil {
+			return err
+		}
+		roleDTO.Name = command.Name
+	}
+	if command.Description != "" {
+		roleDTO.Description = command.Description
+	}
+	if command.Permissions != nil {
+		if roleDTO.Permissions, err = authentication.NormalizePermissions(command.Permissions); err != nil {
+			return err
+		}
+	}
+	role, err := c.pgRepo.UpdateRole(ctx, roleDTO)
+	if err != nil {
+		return err
+	}
+	msg := &kafkaMessages.RoleUpdated{Role: mappings.RoleToGrpcMessage(role)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.RoleUpdated.TopicName, kafkaClient.RoleUpdatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
+
+// DeleteRoleCmdHandler ...
+type DeleteRoleCmdHandler interface {
+	Handle(ctx context.Context, command *DeleteRoleCommand) error
+}
+
+type deleteRoleHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewDeleteRoleHandler ...
+func NewDeleteRoleHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *deleteRoleHandler {
+	return &deleteRoleHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle deletes a custom role; the memberships granted it fall back to the built-in MEMBER role
+func (c *deleteRoleHandler) Handle(ctx context.Context, command *DeleteRoleCommand) error {
+	ctx, span := tracing.StartSpan(ctx, "deleteRoleHandler.Handle")
+	defer span.End()
+	if _, err := checkCustomRole(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
+	if err := c.pgRepo.DeleteRoleById(ctx, command.ID); err != nil {
+		return err
+	}
+	fallback, err := c.pgRepo.GetRoleById(ctx, uuid.FromStringOrNil(enums.MEMBER.RoleID()))
+	if err != nil {
+		return err
+	}
+	msg := &kafkaMessages.RoleDeleted{ID: command.ID.String(), FallbackRole: mappings.RoleToGrpcMessage(fallback)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.RoleDeleted.TopicName, kafkaClient.RoleDeletedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, message)
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/gofrs/uuid"
+)
+
+// checkOrganization returns an error unless organizationID is visible to tenantID and is either the default tenant
+// or an active organization that new users and groups can be added to
+func checkOrganization(ctx context.Context, pgRepo repositories.Repository, tenantID string, organizationID uuid.UUID) error {
+	if err := authentication.CheckTenant(tenantID, organizationID.String()); err != nil {
+		return err
+	}
+	if organizationID == uuid.Nil {
+		return nil
+	}
+	organization, err := pgRepo.GetOrganizationById(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if !organization.Active {
+		return authentication.ErrOrganizationSuspended
+	}
+	return nil
+}
+
+// checkUserTenant returns authentication.ErrOutsideTenant unless the user with id is visible to tenantID, and
+// authentication.ErrRootAdminRequired when it is a root user and tenantID is not the empty tenant of root sessions
+func checkUserTenant(ctx context.Context, pgRepo repositories.Repository, tenantID string, id uuid.UUID) error {
+	if tenantID == "" {
+		return nil
+	}
+	user, err := pgRepo.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = authentication.CheckTenant(tenantID, user.OrganizationID.String()); err != nil {
+		return err
+	}
+	return authentication.CheckRootTarget(tenantID, user.Root)
+}
+
+// checkGroupTenant returns authentication.ErrOutsideTenant unless the group with id is visible to tenantID
+func checkGroupTenant(ctx context.Context, pgRepo repositories.Repository, tenantID string, id uuid.UUID) error {
+	if tenantID == "" {
+		return nil
+	}
+	group, err := pgRepo.GetGroupById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return authentication.CheckTenant(tenantID, group.OrganizationID.String())
+}
+
+// checkMembershipTenant returns authentication.ErrOutsideTenant unless the membership with id is visible to tenantID
+func checkMembershipTenant(ctx context.Context, pgRepo repositories.Repository, tenantID string, id uuid.UUID) error {
+	if tenantID == "" {
+		return nil
+	}
+	membership, err := pgRepo.GetMembershipById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return authentication.CheckTenant(tenantID, membership.OrganizationID.String())
+}
@@ -1,44 +1,56 @@
 package commands
 
 import (
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/gofrs/uuid"
+	"time"
 )
 
 // UserCommands ...
 type UserCommands struct {
-	CreateUser CreateUserCmdHandler
-	UpdateUser UpdateUserCmdHandler
-	DeleteUser DeleteUserCmdHandler
+	CreateUser         CreateUserCmdHandler
+	UpdateUser         UpdateUserCmdHandler
+	DeleteUser         DeleteUserCmdHandler
+	ReserveIdentifiers ReserveIdentifiersCmdHandler
+	ChangeUserStatus   ChangeUserStatusCmdHandler
 }
 
 // NewUserCommands ...
-func NewUserCommands(createUser CreateUserCmdHandler, updateUser UpdateUserCmdHandler, deleteUser DeleteUserCmdHandler) *UserCommands {
+func NewUserCommands(createUser CreateUserCmdHandler, updateUser UpdateUserCmdHandler, deleteUser DeleteUserCmdHandler, reserveIdentifiers ReserveIdentifiersCmdHandler, changeUserStatus ChangeUserStatusCmdHandler) *UserCommands {
 	return &UserCommands{
-		CreateUser: createUser,
-		UpdateUser: updateUser,
-		DeleteUser: deleteUser,
+		CreateUser:         createUser,
+		UpdateUser:         updateUser,
+		DeleteUser:         deleteUser,
+		ReserveIdentifiers: reserveIdentifiers,
+		ChangeUserStatus:   changeUserStatus,
 	}
 }
 
 // CreateUserCommand ...
 type CreateUserCommand struct {
-	ID       uuid.UUID `json:"id" validate:"required"`
-	Email    string    `json:"email" validate:"required,gte=0,lte=255"`
-	Username string    `json:"username" validate:"required,gte=0,lte=5000"`
-	Password string    `json:"password" validate:"required"`
-	Root     bool      `json:"root"`
-	Active   bool      `json:"active"`
+	ID             uuid.UUID        `json:"id" validate:"required"`
+	Email          string           `json:"email" validate:"required,gte=0,lte=255"`
+	Username       string           `json:"username" validate:"required,gte=0,lte=5000"`
+	Password       string           `json:"password" validate:"required"`
+	Root           bool             `json:"root"`
+	Status         enums.UserStatus `json:"status" validate:"required,gte=1,lte=4"`
+	OrganizationID uuid.UUID        `json:"organizationID"`
+	OrgAdmin       bool             `json:"orgAdmin"`
+	TenantID       string           `json:"tenantID"`
 }
 
 // NewCreateUserCommand ...
-func NewCreateUserCommand(id uuid.UUID, email string, username string, password string, root bool, active bool) *CreateUserCommand {
+func NewCreateUserCommand(id uuid.UUID, email string, username string, password string, root bool, status enums.UserStatus, organizationId uuid.UUID, orgAdmin bool, tenantId string) *CreateUserCommand {
 	return &CreateUserCommand{
-		ID:       id,
-		Email:    email,
-		Username: username,
-		Password: password,
-		Root:     root,
-		Active:   active,
+		ID:             id,
+		Email:          email,
+		Username:       username,
+		Password:       password,
+		Root:           root,
+		Status:         status,
+		OrganizationID: organizationId,
+		OrgAdmin:       orgAdmin,
+		TenantID:       tenantId,
 	}
 }
 
@@ -47,23 +59,62 @@ type UpdateUserCommand struct {
 	ID       uuid.UUID `json:"id" validate:"required,gte=0,lte=255"`
 	Email    string    `json:"email"`
 	Username string    `json:"username"`
+	TenantID string    `json:"tenantID"`
 }
 
 // NewUpdateUserCommand ...
-func NewUpdateUserCommand(id uuid.UUID, email string, username string) *UpdateUserCommand {
+func NewUpdateUserCommand(id uuid.UUID, email string, username string, tenantId string) *UpdateUserCommand {
 	return &UpdateUserCommand{
 		ID:       id,
 		Email:    email,
 		Username: username,
+		TenantID: tenantId,
 	}
 }
 
 // DeleteUserCommand ...
 type DeleteUserCommand struct {
-	ID uuid.UUID `json:"id" validate:"required"`
+	ID       uuid.UUID `json:"id" validate:"required"`
+	TenantID string    `json:"tenantID"`
 }
 
 // NewDeleteUserCommand ...
-func NewDeleteUserCommand(id uuid.UUID) *DeleteUserCommand {
-	return &DeleteUserCommand{ID: id}
+func NewDeleteUserCommand(id uuid.UUID, tenantId string) *DeleteUserCommand {
+	return &DeleteUserCommand{ID: id, TenantID: tenantId}
+}
+
+// ReserveIdentifiersCommand ...
+type ReserveIdentifiersCommand struct {
+	ID       uuid.UUID `json:"id" validate:"required"`
+	Email    string    `json:"email" validate:"required_without=Username,lte=255"`
+	Username string    `json:"username" validate:"lte=5000"`
+}
+
+// NewReserveIdentifiersCommand ...
+func NewReserveIdentifiersCommand(id uuid.UUID, email string, username string) *ReserveIdentifiersCommand {
+	return &ReserveIdentifiersCommand{
+		ID:       id,
+		Email:    email,
+		Username: username,
+	}
+}
+
+// ChangeUserStatusCommand ...
+type ChangeUserStatusCommand struct {
+	ID             uuid.UUID        `json:"id" validate:"required"`
+	Status         enums.UserStatus `json:"status" validate:"required,gte=1,lte=4"`
+	Reason         string           `json:"reason" validate:"lte=250"`
+	SuspendedUntil time.Time        `json:"suspendedUntil"`
+	TenantID       string           `json:"tenantID"`
+}
+
+// NewChangeUserStatusCommand ...
+func NewChangeUserStatusCommand(id uuid.UUID, status enums.UserStatus, reason string, suspendedUntil time.Time, tenantId string) *ChangeUserStatusCommand {
+	return &ChangeUserStatusCommand{
+		ID:             id,
+		Status:         status,
+		Reason:         reason,
+		SuspendedUntil: suspendedUntil,
+		TenantID:       tenantId,
+	}
 }
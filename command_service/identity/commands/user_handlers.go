@@ -4,15 +4,18 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/command_service/identity/queries"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
 	"github.com/JECSand/identity-service/command_service/mappings"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
-	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"time"
 )
 
@@ -25,32 +28,48 @@ type createUserHandler struct {
 	log           logging.Logger
 	cfg           *config.Config
 	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	publisher     messaging.Publisher
+	hasher        authentication.PasswordHasher
+	checkPassword queries.CheckPasswordHandler
 }
 
 // NewCreateUserHandler ...
-func NewCreateUserHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *createUserHandler {
+func NewCreateUserHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher, hasher authentication.PasswordHasher, checkPassword queries.CheckPasswordHandler) *createUserHandler {
 	return &createUserHandler{
 		log:           log,
 		cfg:           cfg,
 		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		publisher:     publisher,
+		hasher:        hasher,
+		checkPassword: checkPassword,
 	}
 }
 
-// Handle ...
+// Handle creates the user once its password passes the password policy; the root user seeded from the config is
+// exempt, as its password is set by the operator
 func (c *createUserHandler) Handle(ctx context.Context, command *CreateUserCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createUserHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createUserHandler.Handle")
+	defer span.End()
+	if err := checkOrganization(ctx, c.pgRepo, command.TenantID, command.OrganizationID); err != nil {
+		return err
+	}
+	if !command.Root {
+		if err := c.checkPassword.Handle(ctx, queries.NewCheckPasswordQuery(uuid.Nil, command.Email, command.Username, command.Password)); err != nil {
+			return err
+		}
+	}
 	userDTO := &models.User{
-		ID:       command.ID,
-		Email:    command.Email,
-		Username: command.Username,
-		Password: command.Password,
-		Root:     command.Root,
-		Active:   command.Active,
+		ID:             command.ID,
+		Email:          command.Email,
+		Username:       command.Username,
+		Password:       command.Password,
+		Root:           command.Root,
+		OrganizationID: command.OrganizationID,
+		OrgAdmin:       command.OrgAdmin,
 	}
-	if err := userDTO.HashPassword(); err != nil {
+	userDTO.SetStatus(command.Status)
+	userDTO.NormalizeIdentifiers()
+	if err := userDTO.HashPassword(c.hasher); err != nil {
 		return err
 	}
 	user, err := c.pgRepo.CreateUser(ctx, userDTO)
@@ -58,17 +77,11 @@ func (c *createUserHandler) Handle(ctx context.Context, command *CreateUserComma
 		return err
 	}
 	msg := &kafkaMessages.UserCreated{User: mappings.UserToGrpcMessage(user)}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.UserCreated.TopicName, kafkaClient.UserCreatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.UserCreated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.publisher.Publish(ctx, message)
 }
 
 // UpdateUserCmdHandler ...
@@ -77,46 +90,44 @@ type UpdateUserCmdHandler interface {
 }
 
 type updateUserHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewUpdateUserHandler ...
-func NewUpdateUserHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *updateUserHandler {
+func NewUpdateUserHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *updateUserHandler {
 	return &updateUserHandler{log: log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *updateUserHandler) Handle(ctx context.Context, command *UpdateUserCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateUserHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updateUserHandler.Handle")
+	defer span.End()
+	if err := checkUserTenant(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
 	userDTO := &models.User{
 		ID:       command.ID,
 		Email:    command.Email,
 		Username: command.Username,
 	}
+	userDTO.NormalizeIdentifiers()
 	user, err := c.pgRepo.UpdateUser(ctx, userDTO)
 	if err != nil {
 		return err
 	}
 	msg := &kafkaMessages.UserUpdated{User: mappings.UserToGrpcMessage(user)}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.UserUpdated.TopicName, kafkaClient.UserUpdatedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.UserUpdated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.publisher.Publish(ctx, message)
 }
 
 // DeleteUserCmdHandler ...
@@ -125,39 +136,145 @@ type DeleteUserCmdHandler interface {
 }
 
 type deleteUserHandler struct {
-	log           logging.Logger
-	cfg           *config.Config
-	pgRepo        repositories.Repository
-	kafkaProducer kafkaClient.Producer
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
 }
 
 // NewDeleteUserHandler ...
-func NewDeleteUserHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *deleteUserHandler {
+func NewDeleteUserHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *deleteUserHandler {
 	return &deleteUserHandler{
-		log:           log,
-		cfg:           cfg,
-		pgRepo:        pgRepo,
-		kafkaProducer: kafkaProducer,
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
 	}
 }
 
 // Handle ...
 func (c *deleteUserHandler) Handle(ctx context.Context, command *DeleteUserCommand) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteUserHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "deleteUserHandler.Handle")
+	defer span.End()
+	if err := checkUserTenant(ctx, c.pgRepo, command.TenantID, command.ID); err != nil {
+		return err
+	}
 	if err := c.pgRepo.DeleteUserById(ctx, command.ID); err != nil {
 		return err
 	}
 	msg := &kafkaMessages.UserDeleted{ID: command.ID.String()}
-	msgBytes, err := proto.Marshal(msg)
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.UserDeleted.TopicName, kafkaClient.UserDeletedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
 	if err != nil {
 		return err
 	}
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.UserDeleted.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
+	return c.publisher.Publish(ctx, message)
+}
+
+// ReserveIdentifiersCmdHandler ...
+type ReserveIdentifiersCmdHandler interface {
+	Handle(ctx context.Context, command *ReserveIdentifiersCommand) (*models.User, error)
+}
+
+type reserveIdentifiersHandler struct {
+	log    logging.Logger
+	cfg    *config.Config
+	pgRepo repositories.Repository
+}
+
+// NewReserveIdentifiersHandler ...
+func NewReserveIdentifiersHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository) *reserveIdentifiersHandler {
+	return &reserveIdentifiersHandler{
+		log:    log,
+		cfg:    cfg,
+		pgRepo: pgRepo,
+	}
+}
+
+// Handle reserves the normalized email and username of a user about to be created or updated, returning them or an
+// *authentication.IdentifierConflictError when another user holds any of them
+func (c *reserveIdentifiersHandler) Handle(ctx context.Context, command *ReserveIdentifiersCommand) (*models.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "reserveIdentifiersHandler.Handle")
+	defer span.End()
+	userDTO := &models.User{
+		ID:       command.ID,
+		Email:    command.Email,
+		Username: command.Username,
+	}
+	userDTO.NormalizeIdentifiers()
+	if err := c.pgRepo.ReserveUserIdentifiers(ctx, userDTO.ID, userDTO.Email, userDTO.Username); err != nil {
+		return nil, err
+	}
+	return userDTO, nil
+}
+
+// ChangeUserStatusCmdHandler ...
+type ChangeUserStatusCmdHandler interface {
+	Handle(ctx context.Context, command *ChangeUserStatusCommand) (*models.User, error)
+}
+
+type changeUserStatusHandler struct {
+	log       logging.Logger
+	cfg       *config.Config
+	pgRepo    repositories.Repository
+	publisher messaging.Publisher
+}
+
+// NewChangeUserStatusHandler ...
+func NewChangeUserStatusHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *changeUserStatusHandler {
+	return &changeUserStatusHandler{
+		log:       log,
+		cfg:       cfg,
+		pgRepo:    pgRepo,
+		publisher: publisher,
+	}
+}
+
+// Handle moves a user into a new lifecycle status, returning an *authentication.UserStatusTransitionError when its
+// current status does not allow it. Only root sessions change the status of root users. Suspending or deactivating a
+// user revokes every session issued to it so far
+func (c *changeUserStatusHandler) Handle(ctx context.Context, command *ChangeUserStatusCommand) (*models.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "changeUserStatusHandler.Handle")
+	defer span.End()
+	user, err := c.pgRepo.GetUserById(ctx, command.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = authentication.CheckTenant(command.TenantID, user.OrganizationID.String()); err != nil {
+		return nil, err
+	}
+	if err = authentication.CheckRootTarget(command.TenantID, user.Root); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	current := authentication.EffectiveUserStatus(user.LifecycleStatus(), user.SuspendedUntil, now)
+	if !current.CanTransitionTo(command.Status) {
+		return nil, &authentication.UserStatusTransitionError{From: current, To: command.Status}
+	}
+	if command.Status == enums.SUSPENDED && !command.SuspendedUntil.IsZero() && !command.SuspendedUntil.After(now) {
+		return nil, errors.New("suspension end date must be in the future")
+	}
+	userDTO := &models.User{ID: command.ID}
+	userDTO.SetStatus(command.Status)
+	if command.Status != enums.ACTIVATED {
+		userDTO.StatusReason = command.Reason
+	}
+	if command.Status == enums.SUSPENDED {
+		userDTO.SuspendedUntil = command.SuspendedUntil
+	}
+	if command.Status.RevokesSessions() {
+		userDTO.SessionsRevokedAt = now
+	}
+	updated, err := c.pgRepo.UpdateUserStatus(ctx, userDTO)
+	if err != nil {
+		return nil, err
+	}
+	msg := &kafkaMessages.UserStatusChanged{User: mappings.UserToGrpcMessage(updated)}
+	message, err := kafkaClient.NewEventMessage(ctx, c.cfg.KafkaTopics.UserStatusChanged.TopicName, kafkaClient.UserStatusChangedEvent, command.ID.String(), msg, tracing.GetKafkaTracingHeaders(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err = c.publisher.Publish(ctx, message); err != nil {
+		return nil, err
 	}
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return updated, nil
 }
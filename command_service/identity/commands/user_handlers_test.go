@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestRootUserTargets(t *testing.T) {
+	ctx := context.Background()
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	cfg := &config.Config{}
+	repo := repositories.NewMemoryRepository(log, cfg)
+	bus := messaging.NewMemoryBus(1)
+	defaultTenant := authentication.NoOrganization
+	newUser := func(name string, root bool) uuid.UUID {
+		user := &models.User{ID: uuid.Must(uuid.NewV4()), Email: name + "@example.com", Username: name, Root: root}
+		user.SetStatus(enums.ACTIVATED)
+		if _, err := repo.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		return user.ID
+	}
+	rootUser, member := newUser("root", true), newUser("member", false)
+	changeStatus := NewChangeUserStatusHandler(log, cfg, repo, bus)
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		tenantID string
+		status   enums.UserStatus
+		wantErr  error
+	}{
+		{name: "admin suspends root", id: rootUser, tenantID: defaultTenant, status: enums.SUSPENDED, wantErr: authentication.ErrRootAdminRequired},
+		{name: "admin deactivates root", id: rootUser, tenantID: defaultTenant, status: enums.DEACTIVATED, wantErr: authentication.ErrRootAdminRequired},
+		{name: "admin suspends member", id: member, tenantID: defaultTenant, status: enums.SUSPENDED},
+		{name: "root suspends root", id: rootUser, tenantID: "", status: enums.SUSPENDED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := changeStatus.Handle(ctx, NewChangeUserStatusCommand(tt.id, tt.status, "", time.Time{}, tt.tenantID))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChangeUserStatus: got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if err := NewUpdateUserHandler(log, cfg, repo, bus).Handle(ctx, NewUpdateUserCommand(rootUser, "", "renamed", defaultTenant)); !errors.Is(err, authentication.ErrRootAdminRequired) {
+		t.Errorf("UpdateUser: got %v, want %v", err, authentication.ErrRootAdminRequired)
+	}
+	if err := NewDeleteUserHandler(log, cfg, repo, bus).Handle(ctx, NewDeleteUserCommand(rootUser, defaultTenant)); !errors.Is(err, authentication.ErrRootAdminRequired) {
+		t.Errorf("DeleteUser: got %v, want %v", err, authentication.ErrRootAdminRequired)
+	}
+	if _, err := repo.GetUserById(ctx, rootUser); err != nil {
+		t.Errorf("GetUserById: %v", err)
+	}
+}
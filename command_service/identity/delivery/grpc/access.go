@@ -0,0 +1,29 @@
+package grpc
+
+import "github.com/JECSand/identity-service/pkg/authentication"
+
+// ServiceAccessRules declares which services may call each command service RPC
+func ServiceAccessRules() authentication.ServiceAccessRules {
+	gateway := []string{authentication.ApiGatewayService}
+	return authentication.ServiceAccessRules{
+		"/authCommandService.authCommandService/BlacklistToken":                gateway,
+		"/authCommandService.authCommandService/UpdatePassword":                gateway,
+		"/authCommandService.authCommandService/CheckTokenBlacklist":           gateway,
+		"/authCommandService.authCommandService/CheckPassword":                 gateway,
+		"/authCommandService.authCommandService/Authenticate":                  gateway,
+		"/commandService.commandService/CreateUser":                            gateway,
+		"/commandService.commandService/UpdateUser":                            gateway,
+		"/commandService.commandService/GetUserById":                           gateway,
+		"/commandService.commandService/ReserveIdentifiers":                    gateway,
+		"/commandService.commandService/ChangeUserStatus":                      gateway,
+		"/groupCommandService.groupCommandService/CreateGroup":                 gateway,
+		"/groupCommandService.groupCommandService/UpdateGroup":                 gateway,
+		"/groupCommandService.groupCommandService/GetGroupById":                gateway,
+		"/groupCommandService.groupCommandService/SetGroupParent":              gateway,
+		"/membershipCommandService.membershipCommandService/CreateMembership":  gateway,
+		"/membershipCommandService.membershipCommandService/UpdateMembership":  gateway,
+		"/membershipCommandService.membershipCommandService/GetMembershipById": gateway,
+		"/grpc.health.v1.Health/Check":                                         {authentication.AnyCaller},
+		"/grpc.health.v1.Health/Watch":                                         {authentication.AnyCaller},
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/metrics"
 	"github.com/JECSand/identity-service/command_service/identity/queries"
 	"github.com/JECSand/identity-service/command_service/identity/services"
+	"github.com/JECSand/identity-service/command_service/mappings"
 	"github.com/JECSand/identity-service/command_service/protos/auth_command"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/go-playground/validator"
@@ -40,20 +42,20 @@ func NewAuthCommandGrpcService(log logging.Logger, cfg *config.Config, v *valida
 func (s *authGrpcService) BlacklistToken(ctx context.Context, req *authCommandService.BlacklistTokenReq) (*authCommandService.BlacklistTokenRes, error) {
 	s.metrics.BlacklistTokenGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authGrpcService.BlacklistToken")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	command := commands.NewBlacklistTokenCommand(id, req.GetAccessToken())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.authService.Commands.BlacklistToken.Handle(ctx, command)
 	if err != nil {
-		s.log.WarnMsg("BlacklistToken.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("BlacklistToken.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -63,20 +65,25 @@ func (s *authGrpcService) BlacklistToken(ctx context.Context, req *authCommandSe
 func (s *authGrpcService) UpdatePassword(ctx context.Context, req *authCommandService.UpdatePasswordReq) (*authCommandService.UpdatePasswordRes, error) {
 	s.metrics.PasswordUpdateGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authGrpcService.UpdatePassword")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	command := commands.NewUpdatePasswordCommand(id, req.GetCurrentPassword(), req.GetNewPassword())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.authService.Commands.UpdatePassword.Handle(ctx, command)
+	var policyErr *authentication.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		s.log.WithContext(ctx).WarnMsg("UpdatePassword.Handle", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
 	if err != nil {
-		s.log.WarnMsg("UpdatePassword.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdatePassword.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -86,22 +93,88 @@ func (s *authGrpcService) UpdatePassword(ctx context.Context, req *authCommandSe
 func (s *authGrpcService) CheckTokenBlacklist(ctx context.Context, req *authCommandService.CheckBlacklistReq) (*authCommandService.CheckBlacklistRes, error) {
 	s.metrics.CheckTokenBlacklistGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authGrpcService.CheckTokenBlacklist")
-	defer span.Finish()
+	defer span.End()
 	query := queries.NewCheckTokenBlacklistQuery(req.GetAccessToken())
 	if err := s.v.StructCtx(ctx, query); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	_, err := s.authService.Queries.CheckTokenBlacklist.Handle(ctx, query)
 	if err == nil {
 		err = errors.New("token is blacklisted")
-		s.log.WarnMsg("CheckTokenBlacklist.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CheckTokenBlacklist.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
 	return &authCommandService.CheckBlacklistRes{Status: 200}, nil
 }
 
+func (s *authGrpcService) CheckPassword(ctx context.Context, req *authCommandService.CheckPasswordReq) (*authCommandService.CheckPasswordRes, error) {
+	s.metrics.CheckPasswordGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authGrpcService.CheckPassword")
+	defer span.End()
+	var id uuid.UUID
+	if req.GetID() != "" {
+		var err error
+		if id, err = uuid.FromString(req.GetID()); err != nil {
+			s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			return nil, s.errResponse(codes.InvalidArgument, err)
+		}
+	}
+	query := queries.NewCheckPasswordQuery(id, req.GetEmail(), req.GetUsername(), req.GetPassword())
+	if err := s.v.StructCtx(ctx, query); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	err := s.authService.Queries.CheckPassword.Handle(ctx, query)
+	var policyErr *authentication.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		s.metrics.SuccessGrpcRequests.Inc()
+		return &authCommandService.CheckPasswordRes{Violations: mappings.PasswordViolationsToGrpc(policyErr.Violations), Status: 400}, nil
+	}
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("CheckPassword.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &authCommandService.CheckPasswordRes{Status: 200}, nil
+}
+
+func (s *authGrpcService) Authenticate(ctx context.Context, req *authCommandService.AuthenticateReq) (*authCommandService.AuthenticateRes, error) {
+	s.metrics.AuthenticateGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authGrpcService.Authenticate")
+	defer span.End()
+	var id uuid.UUID
+	if req.GetID() != "" {
+		var err error
+		if id, err = uuid.FromString(req.GetID()); err != nil {
+			s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			return nil, s.errResponse(codes.InvalidArgument, err)
+		}
+	}
+	query := queries.NewAuthenticateQuery(id, req.GetEmail(), req.GetPassword())
+	if err := s.v.StructCtx(ctx, query); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	user, err := s.authService.Queries.Authenticate.Handle(ctx, query)
+	if errors.Is(err, authentication.ErrPasswordMismatch) {
+		s.log.WithContext(ctx).WarnMsg("Authenticate.Handle", err)
+		return nil, s.errResponse(codes.Unauthenticated, err)
+	}
+	var statusErr *authentication.UserStatusError
+	if errors.As(err, &statusErr) || errors.Is(err, authentication.ErrOrganizationSuspended) {
+		s.log.WithContext(ctx).WarnMsg("Authenticate.Handle", err)
+		return nil, s.errResponse(codes.PermissionDenied, err)
+	}
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("Authenticate.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &authCommandService.AuthenticateRes{User: mappings.CommandAuthUserToGrpc(user), Status: 200}, nil
+}
+
 func (s *authGrpcService) errResponse(c codes.Code, err error) error {
 	s.metrics.ErrorGrpcRequests.Inc()
 	return status.Error(c, err.Error())
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"database/sql"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	"github.com/JECSand/identity-service/command_service/identity/metrics"
@@ -13,6 +14,8 @@ import (
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -38,25 +41,25 @@ func NewGroupCommandGrpcService(log logging.Logger, cfg *config.Config, v *valid
 func (s *groupGrpcService) CreateGroup(ctx context.Context, req *groupCommandService.CreateGroupReq) (*groupCommandService.CreateGroupRes, error) {
 	s.metrics.CreateGroupGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.CreateGroup")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	creatorId, err := uuid.FromString(req.GetCreatorID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	command := commands.NewCreateGroupCommand(id, req.GetName(), req.GetDescription(), creatorId, false)
+	command := commands.NewCreateGroupCommand(id, req.GetName(), req.GetDescription(), creatorId, false, uuid.Nil, uuid.Nil, "")
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.groupService.Commands.CreateGroup.Handle(ctx, command)
 	if err != nil {
-		s.log.WarnMsg("CreateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateGroup.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -66,20 +69,20 @@ func (s *groupGrpcService) CreateGroup(ctx context.Context, req *groupCommandSer
 func (s *groupGrpcService) UpdateGroup(ctx context.Context, req *groupCommandService.UpdateGroupReq) (*groupCommandService.UpdateGroupRes, error) {
 	s.metrics.UpdateGroupGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.UpdateGroup")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	command := commands.NewUpdateGroupCommand(id, req.GetName(), req.GetDescription())
+	command := commands.NewUpdateGroupCommand(id, req.GetName(), req.GetDescription(), "")
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.groupService.Commands.UpdateGroup.Handle(ctx, command)
 	if err != nil {
-		s.log.WarnMsg("UpdateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateGroup.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -89,26 +92,65 @@ func (s *groupGrpcService) UpdateGroup(ctx context.Context, req *groupCommandSer
 func (s *groupGrpcService) GetGroupById(ctx context.Context, req *groupCommandService.GetGroupByIdReq) (*groupCommandService.GetGroupByIdRes, error) {
 	s.metrics.GetGroupByIdGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.GetGroupById")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	query := queries.NewGetGroupByIdQuery(id)
 	if err = s.v.StructCtx(ctx, query); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	found, err := s.groupService.Queries.GetGroupById.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetGroupById.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetGroupById.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
 	return &groupCommandService.GetGroupByIdRes{Group: mappings.CommandGroupToGrpc(found)}, nil
 }
 
+// SetGroupParent moves a group below another group of its organization, or to the top level, and returns it
+func (s *groupGrpcService) SetGroupParent(ctx context.Context, req *groupCommandService.SetGroupParentReq) (*groupCommandService.SetGroupParentRes, error) {
+	s.metrics.SetGroupParentGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.SetGroupParent")
+	defer span.End()
+	id, err := uuid.FromString(req.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	parentId := uuid.Nil
+	if req.GetParentID() != "" {
+		if parentId, err = uuid.FromString(req.GetParentID()); err != nil {
+			s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			return nil, s.errResponse(codes.InvalidArgument, err)
+		}
+	}
+	command := commands.NewSetGroupParentCommand(id, parentId, req.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	group, err := s.groupService.Commands.SetGroupParent.Handle(ctx, command)
+	if errors.Is(err, commands.ErrGroupHierarchyCycle) || errors.Is(err, commands.ErrParentGroupOrganization) {
+		s.log.WithContext(ctx).WarnMsg("SetGroupParent.Handle", err)
+		return nil, s.errResponse(codes.FailedPrecondition, err)
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		s.log.WithContext(ctx).WarnMsg("SetGroupParent.Handle", err)
+		return nil, s.errResponse(codes.NotFound, err)
+	}
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("SetGroupParent.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &groupCommandService.SetGroupParentRes{Group: mappings.CommandGroupToGrpc(group)}, nil
+}
+
 func (s *groupGrpcService) errResponse(c codes.Code, err error) error {
 	s.metrics.ErrorGrpcRequests.Inc()
 	return status.Error(c, err.Error())
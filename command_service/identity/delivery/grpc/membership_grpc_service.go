@@ -16,6 +16,7 @@ import (
 	"github.com/gofrs/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 type membershipGrpcService struct {
@@ -39,30 +40,30 @@ func NewMembershipCommandGrpcService(log logging.Logger, cfg *config.Config, v *
 func (s *membershipGrpcService) CreateMembership(ctx context.Context, req *membershipCommandService.CreateMembershipReq) (*membershipCommandService.CreateMembershipRes, error) {
 	s.metrics.CreateMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.CreateMembership")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	userId, err := uuid.FromString(req.GetUserID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	groupId, err := uuid.FromString(req.GetGroupID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	command := commands.NewCreateMembershipCommand(id, userId, groupId, enums.MembershipStatus(req.GetStatus()), enums.Role(req.GetRole()))
+	command := commands.NewCreateMembershipCommand(id, userId, groupId, enums.MembershipStatus(req.GetStatus()), enums.Role(req.GetRole()), uuid.Nil, time.Time{}, time.Time{}, "")
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.membershipService.Commands.CreateMembership.Handle(ctx, command)
 	if err != nil {
-		s.log.WarnMsg("CreateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateMembership.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -72,20 +73,20 @@ func (s *membershipGrpcService) CreateMembership(ctx context.Context, req *membe
 func (s *membershipGrpcService) UpdateMembership(ctx context.Context, req *membershipCommandService.UpdateMembershipReq) (*membershipCommandService.UpdateMembershipRes, error) {
 	s.metrics.UpdateMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.UpdateMembership")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	command := commands.NewUpdateMembershipCommand(id, enums.MembershipStatus(req.GetStatus()), enums.Role(req.GetRole()))
+	command := commands.NewUpdateMembershipCommand(id, enums.MembershipStatus(req.GetStatus()), enums.Role(req.GetRole()), uuid.Nil, "")
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.membershipService.Commands.UpdateMembership.Handle(ctx, command)
 	if err != nil {
-		s.log.WarnMsg("UpdateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateMembership.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -95,20 +96,20 @@ func (s *membershipGrpcService) UpdateMembership(ctx context.Context, req *membe
 func (s *membershipGrpcService) GetMembershipById(ctx context.Context, req *membershipCommandService.GetMembershipByIdReq) (*membershipCommandService.GetMembershipByIdRes, error) {
 	s.metrics.GetMembershipByIdGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.GetMembershipById")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	query := queries.NewGetMembershipByIdQuery(id)
 	if err = s.v.StructCtx(ctx, query); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	found, err := s.membershipService.Queries.GetMembershipById.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetMembershipById.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetMembershipById.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"database/sql"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	"github.com/JECSand/identity-service/command_service/identity/metrics"
@@ -9,12 +10,17 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/services"
 	"github.com/JECSand/identity-service/command_service/mappings"
 	"github.com/JECSand/identity-service/command_service/protos/user_command"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 type grpcService struct {
@@ -40,21 +46,26 @@ func NewCommandGrpcService(log logging.Logger, cfg *config.Config, v *validator.
 func (s *grpcService) CreateUser(ctx context.Context, req *commandService.CreateUserReq) (*commandService.CreateUserRes, error) {
 	s.metrics.CreateUserGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.CreateUser")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	// TODO: Add logic to manage new User Active and Root fields
-	command := commands.NewCreateUserCommand(id, req.GetEmail(), req.GetUsername(), req.GetPassword(), false, false)
+	// TODO: Add logic to manage new User Root field
+	command := commands.NewCreateUserCommand(id, req.GetEmail(), req.GetUsername(), req.GetPassword(), false, enums.UserStatusFromActive(req.GetActive()), uuid.Nil, false, "")
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.userService.Commands.CreateUser.Handle(ctx, command)
+	var policyErr *authentication.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		s.log.WithContext(ctx).WarnMsg("CreateUser.Handle", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
 	if err != nil {
-		s.log.WarnMsg("CreateUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateUser.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -64,20 +75,20 @@ func (s *grpcService) CreateUser(ctx context.Context, req *commandService.Create
 func (s *grpcService) UpdateUser(ctx context.Context, req *commandService.UpdateUserReq) (*commandService.UpdateUserRes, error) {
 	s.metrics.UpdateUserGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.UpdateUser")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	command := commands.NewUpdateUserCommand(id, req.GetEmail(), req.GetUsername())
+	command := commands.NewUpdateUserCommand(id, req.GetEmail(), req.GetUsername(), "")
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	err = s.userService.Commands.UpdateUser.Handle(ctx, command)
 	if err != nil {
-		s.log.WarnMsg("UpdateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateGroup.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -87,26 +98,97 @@ func (s *grpcService) UpdateUser(ctx context.Context, req *commandService.Update
 func (s *grpcService) GetUserById(ctx context.Context, req *commandService.GetUserByIdReq) (*commandService.GetUserByIdRes, error) {
 	s.metrics.GetUserByIdGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.GetUserById")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	query := queries.NewGetUserByIdQuery(id)
 	if err = s.v.StructCtx(ctx, query); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	found, err := s.userService.Queries.GetUserById.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetUserById.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetUserById.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
 	return &commandService.GetUserByIdRes{User: mappings.CommandUserToGrpc(found)}, nil
 }
 
+// ReserveIdentifiers reserves the email and username of a user to be created or updated, listing the identifiers
+// taken by other users as conflicts
+func (s *grpcService) ReserveIdentifiers(ctx context.Context, req *commandService.ReserveIdentifiersReq) (*commandService.ReserveIdentifiersRes, error) {
+	s.metrics.ReserveIdentifiersGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.ReserveIdentifiers")
+	defer span.End()
+	id, err := uuid.FromString(req.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	command := commands.NewReserveIdentifiersCommand(id, req.GetEmail(), req.GetUsername())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	reserved, err := s.userService.Commands.ReserveIdentifiers.Handle(ctx, command)
+	var conflictErr *authentication.IdentifierConflictError
+	if errors.As(err, &conflictErr) {
+		s.metrics.SuccessGrpcRequests.Inc()
+		return &commandService.ReserveIdentifiersRes{Conflicts: conflictErr.Identifiers}, nil
+	}
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("ReserveIdentifiers.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &commandService.ReserveIdentifiersRes{Email: reserved.Email, Username: reserved.Username}, nil
+}
+
+// ChangeUserStatus moves a user into the requested lifecycle status and returns it
+func (s *grpcService) ChangeUserStatus(ctx context.Context, req *commandService.ChangeUserStatusReq) (*commandService.ChangeUserStatusRes, error) {
+	s.metrics.ChangeUserStatusGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.ChangeUserStatus")
+	defer span.End()
+	id, err := uuid.FromString(req.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	var suspendedUntil time.Time
+	if req.GetSuspendedUntil() != nil {
+		suspendedUntil = req.GetSuspendedUntil().AsTime()
+	}
+	command := commands.NewChangeUserStatusCommand(id, enums.UserStatusFromString(req.GetStatus()), req.GetReason(), suspendedUntil, req.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	user, err := s.userService.Commands.ChangeUserStatus.Handle(ctx, command)
+	var transitionErr *authentication.UserStatusTransitionError
+	if errors.As(err, &transitionErr) {
+		s.log.WithContext(ctx).WarnMsg("ChangeUserStatus.Handle", err)
+		return nil, s.errResponse(codes.FailedPrecondition, err)
+	}
+	if errors.Is(err, authentication.ErrRootAdminRequired) {
+		s.log.WithContext(ctx).WarnMsg("ChangeUserStatus.Handle", err)
+		return nil, s.errResponse(codes.PermissionDenied, err)
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		s.log.WithContext(ctx).WarnMsg("ChangeUserStatus.Handle", err)
+		return nil, s.errResponse(codes.NotFound, err)
+	}
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("ChangeUserStatus.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &commandService.ChangeUserStatusRes{User: mappings.CommandUserToGrpc(user)}, nil
+}
+
 func (s *grpcService) errResponse(c codes.Code, err error) error {
 	s.metrics.ErrorGrpcRequests.Inc()
 	return status.Error(c, err.Error())
@@ -2,19 +2,22 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	"github.com/JECSand/identity-service/command_service/identity/metrics"
 	"github.com/JECSand/identity-service/command_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/enums"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
 	"github.com/avast/retry-go"
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"sync"
 	"time"
 )
@@ -30,14 +33,34 @@ var (
 )
 
 type identityMessageProcessor struct {
-	log     logging.Logger
-	cfg     *config.Config
-	v       *validator.Validate
-	us      *services.UserService
-	gs      *services.GroupService
-	ms      *services.MembershipService
-	as      *services.AuthService
-	metrics *metrics.CommandServiceMetrics
+	log      logging.Logger
+	cfg      *config.Config
+	v        *validator.Validate
+	us       *services.UserService
+	gs       *services.GroupService
+	ms       *services.MembershipService
+	as       *services.AuthService
+	orgs     *services.OrganizationService
+	roles    *services.RoleService
+	registry *kafkaClient.EventRegistry
+	metrics  *metrics.CommandServiceMetrics
+}
+
+// parseOptionalId parses an optional id of a command message, such as the OrganizationID that is empty for the
+// default tenant or the ParentID that is empty for top level groups
+func parseOptionalId(id string) (uuid.UUID, error) {
+	if id == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.FromString(id)
+}
+
+// optionalTime parses an optional timestamp of a command message, such as the ValidUntil of an unbounded membership
+func optionalTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
 }
 
 func NewIdentityMessageProcessor(
@@ -48,411 +71,777 @@ func NewIdentityMessageProcessor(
 	gs *services.GroupService,
 	ms *services.MembershipService,
 	as *services.AuthService,
+	orgs *services.OrganizationService,
+	roles *services.RoleService,
 	metrics *metrics.CommandServiceMetrics,
 ) *identityMessageProcessor {
 	return &identityMessageProcessor{
-		log:     log,
-		cfg:     cfg,
-		v:       v,
-		us:      us,
-		gs:      gs,
-		ms:      ms,
-		as:      as,
-		metrics: metrics,
+		log:      log,
+		cfg:      cfg,
+		v:        v,
+		us:       us,
+		gs:       gs,
+		ms:       ms,
+		as:       as,
+		orgs:     orgs,
+		roles:    roles,
+		registry: kafkaClient.DefaultEventRegistry(),
+		metrics:  metrics,
 	}
 }
 
-func (s *identityMessageProcessor) commitMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) commitMessage(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.SuccessKafkaMessages.Inc()
-	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
+	s.log.KafkaLogCommittedMessage(ctx, m.Topic, m.Partition, m.Offset)
 	if err := r.CommitMessages(ctx, m); err != nil {
 		s.log.WarnMsg("commitMessage", err)
 	}
 }
 
-func (s *identityMessageProcessor) commitErrMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) commitErrMessage(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.ErrorKafkaMessages.Inc()
-	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
+	s.log.KafkaLogCommittedMessage(ctx, m.Topic, m.Partition, m.Offset)
 	if err := r.CommitMessages(ctx, m); err != nil {
 		s.log.WarnMsg("commitMessage", err)
 	}
 }
 
-func (s *identityMessageProcessor) logProcessMessage(m kafka.Message, workerID int) {
-	s.log.KafkaProcessMessage(m.Topic, m.Partition, string(m.Value), workerID, m.Offset, m.Time)
+// isRetryable reports whether a command that failed with err may succeed when handled again; a password failing the
+// password policy never does
+func isRetryable(err error) bool {
+	var policyErr *authentication.PasswordPolicyError
+	return !errors.As(err, &policyErr)
+}
+
+func (s *identityMessageProcessor) logProcessMessage(ctx context.Context, m messaging.Message, workerID int) {
+	s.log.KafkaProcessMessage(tracing.ContextFromKafkaHeaders(ctx, m.Headers), m.Topic, m.Partition, m.Key, len(m.Value), workerID, m.Offset, m.Time)
 }
 
-func (s *identityMessageProcessor) processBlacklistToken(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processBlacklistToken(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.BlacklistTokenKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processBlacklistToken")
-	defer span.Finish()
-	var msg kafkaMessages.TokenBlacklist
-	if err := proto.Unmarshal(m.Value, &msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processBlacklistToken")
+	defer span.End()
+	msg := &kafkaMessages.TokenBlacklist{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.TokenBlacklistEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	command := commands.NewBlacklistTokenCommand(id, msg.GetAccessToken())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.as.Commands.BlacklistToken.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("BlacklistToken.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("BlacklistToken.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processUpdatePassword(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processUpdatePassword(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.PasswordUpdateKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processUpdatePassword")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processUpdatePassword")
+	defer span.End()
 	msg := &kafkaMessages.PasswordUpdate{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.PasswordUpdateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	command := commands.NewUpdatePasswordCommand(id, msg.GetCurrentPassword(), msg.GetNewPassword())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.as.Commands.UpdatePassword.Handle(ctx, command)
-	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdatePassword.Handle", err)
+	}, append(retryOptions, retry.Context(ctx), retry.RetryIf(isRetryable), retry.LastErrorOnly(true))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("UpdatePassword.Handle", err)
+		if !isRetryable(err) {
+			s.commitErrMessage(ctx, r, m)
+			return
+		}
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processCreateGroup(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processCreateGroup(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.CreateGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processCreateGroup")
-	defer span.Finish()
-	var msg kafkaMessages.GroupCreate
-	if err := proto.Unmarshal(m.Value, &msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processCreateGroup")
+	defer span.End()
+	msg := &kafkaMessages.GroupCreate{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.GroupCreateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	creatorId, err := uuid.FromString(msg.GetCreatorID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewCreateGroupCommand(id, msg.GetName(), msg.GetDescription(), creatorId, msg.GetActive())
+	organizationId, err := parseOptionalId(msg.GetOrganizationID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	parentId, err := parseOptionalId(msg.GetParentID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewCreateGroupCommand(id, msg.GetName(), msg.GetDescription(), creatorId, msg.GetActive(), organizationId, parentId, msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.gs.Commands.CreateGroup.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("CreateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateGroup.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processUpdateGroup(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processUpdateGroup(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.UpdateGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processUpdateGroup")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processUpdateGroup")
+	defer span.End()
 	msg := &kafkaMessages.GroupUpdate{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.GroupUpdateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewUpdateGroupCommand(id, msg.GetName(), msg.GetDescription())
+	command := commands.NewUpdateGroupCommand(id, msg.GetName(), msg.GetDescription(), msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.gs.Commands.UpdateGroup.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateGroup.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processDeleteGroup(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processDeleteGroup(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.DeleteGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processDeleteGroup")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processDeleteGroup")
+	defer span.End()
 	msg := &kafkaMessages.GroupDelete{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.GroupDeleteEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewDeleteGroupCommand(id)
+	command := commands.NewDeleteGroupCommand(id, msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.gs.Commands.DeleteGroup.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("DeleteGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteGroup.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processCreateMembership(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processCreateMembership(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.CreateMembershipKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processCreateMembership")
-	defer span.Finish()
-	var msg kafkaMessages.MembershipCreate
-	if err := proto.Unmarshal(m.Value, &msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processCreateMembership")
+	defer span.End()
+	msg := &kafkaMessages.MembershipCreate{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.MembershipCreateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	userId, err := uuid.FromString(msg.GetUserID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	groupId, err := uuid.FromString(msg.GetGroupID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	roleId, err := parseOptionalId(msg.GetRoleID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewCreateMembershipCommand(id, userId, groupId, enums.MembershipStatus(msg.GetStatus()), enums.Role(msg.GetRole()))
+	command := commands.NewCreateMembershipCommand(id, userId, groupId, enums.MembershipStatus(msg.GetStatus()), enums.Role(msg.GetRole()), roleId, optionalTime(msg.GetValidFrom()), optionalTime(msg.GetValidUntil()), msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.ms.Commands.CreateMembership.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("CreateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateMembership.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processUpdateMembership(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processUpdateMembership(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.UpdateMembershipKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processUpdateMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processUpdateMembership")
+	defer span.End()
 	msg := &kafkaMessages.MembershipUpdate{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.MembershipUpdateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewUpdateMembershipCommand(id, enums.MembershipStatus(msg.GetStatus()), enums.Role(msg.GetRole()))
+	roleId, err := parseOptionalId(msg.GetRoleID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewUpdateMembershipCommand(id, enums.MembershipStatus(msg.GetStatus()), enums.Role(msg.GetRole()), roleId, msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.ms.Commands.UpdateMembership.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateMembership.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processDeleteMembership(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processDeleteMembership(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.DeleteGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processDeleteMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processDeleteMembership")
+	defer span.End()
 	msg := &kafkaMessages.MembershipDelete{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.MembershipDeleteEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewDeleteMembershipCommand(id)
+	command := commands.NewDeleteMembershipCommand(id, msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.ms.Commands.DeleteMembership.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("DeleteMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteMembership.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) processExtendMembership(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.ExtendMembershipKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processExtendMembership")
+	defer span.End()
+	msg := &kafkaMessages.MembershipExtend{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.MembershipExtendEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	actorId, err := parseOptionalId(msg.GetActorID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewExtendMembershipCommand(id, optionalTime(msg.GetValidUntil()), msg.GetReason(), actorId, msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.ms.Commands.ExtendMembership.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("ExtendMembership.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processCreateUser(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processCreateUser(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.CreateUserKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processCreateUser")
-	defer span.Finish()
-	var msg kafkaMessages.UserCreate
-	if err := proto.Unmarshal(m.Value, &msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processCreateUser")
+	defer span.End()
+	msg := &kafkaMessages.UserCreate{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.UserCreateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewCreateUserCommand(id, msg.GetEmail(), msg.GetUsername(), msg.GetPassword(), false, msg.GetActive())
+	status := enums.UserStatusFromString(msg.GetStatus())
+	if status == 0 {
+		status = enums.UserStatusFromActive(msg.GetActive())
+	}
+	organizationId, err := parseOptionalId(msg.GetOrganizationID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewCreateUserCommand(id, msg.GetEmail(), msg.GetUsername(), msg.GetPassword(), false, status, organizationId, msg.GetOrgAdmin(), msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.us.Commands.CreateUser.Handle(ctx, command)
-	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("CreateUser.Handle", err)
+	}, append(retryOptions, retry.Context(ctx), retry.RetryIf(isRetryable), retry.LastErrorOnly(true))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("CreateUser.Handle", err)
+		if !isRetryable(err) {
+			s.commitErrMessage(ctx, r, m)
+			return
+		}
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processUpdateUser(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processUpdateUser(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.UpdateUserKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processUpdateUser")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processUpdateUser")
+	defer span.End()
 	msg := &kafkaMessages.UserUpdate{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.UserUpdateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewUpdateUserCommand(id, msg.GetEmail(), msg.GetUsername())
+	command := commands.NewUpdateUserCommand(id, msg.GetEmail(), msg.GetUsername(), msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.us.Commands.UpdateUser.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdateUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateUser.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) processDeleteUser(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *identityMessageProcessor) processDeleteUser(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.DeleteUserKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "identityMessageProcessor.processDeleteUser")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processDeleteUser")
+	defer span.End()
 	msg := &kafkaMessages.UserDelete{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.UserDeleteEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	command := commands.NewDeleteUserCommand(id)
+	command := commands.NewDeleteUserCommand(id, msg.GetTenantID())
 	if err = s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.us.Commands.DeleteUser.Handle(ctx, command)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("DeleteUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteUser.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) processCreateOrganization(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.CreateOrganizationKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processCreateOrganization")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationCreate{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationCreateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewCreateOrganizationCommand(id, msg.GetName(), msg.GetDescription(), msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.orgs.Commands.CreateOrganization.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("CreateOrganization.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) processUpdateOrganization(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.UpdateOrganizationKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processUpdateOrganization")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationUpdate{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationUpdateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewUpdateOrganizationCommand(id, msg.GetName(), msg.GetDescription(), msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.orgs.Commands.UpdateOrganization.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("UpdateOrganization.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) processChangeOrganizationStatus(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.ChangeOrganizationStatusKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processChangeOrganizationStatus")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationStatusChange{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationStatusChangeEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewChangeOrganizationStatusCommand(id, msg.GetActive(), msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.orgs.Commands.ChangeOrganizationStatus.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("ChangeOrganizationStatus.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) processDeleteOrganization(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.DeleteOrganizationKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processDeleteOrganization")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationDelete{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationDeleteEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewDeleteOrganizationCommand(id, msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.orgs.Commands.DeleteOrganization.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("DeleteOrganization.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) processCreateRole(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.CreateRoleKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processCreateRole")
+	defer span.End()
+	msg := &kafkaMessages.RoleCreate{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.RoleCreateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	groupId, err := parseOptionalId(msg.GetGroupID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	organizationId, err := parseOptionalId(msg.GetOrganizationID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewCreateRoleCommand(id, msg.GetName(), msg.GetDescription(), msg.GetPermissions(), groupId, organizationId, msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.roles.Commands.CreateRole.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("CreateRole.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *identityMessageProcessor) ProcessMessages(ctx context.Context, r *kafka.Reader, wg *sync.WaitGroup, workerID int) {
+func (s *identityMessageProcessor) processUpdateRole(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.UpdateRoleKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processUpdateRole")
+	defer span.End()
+	msg := &kafkaMessages.RoleUpdate{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.RoleUpdateEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewUpdateRoleCommand(id, msg.GetName(), msg.GetDescription(), msg.GetPermissions(), msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.roles.Commands.UpdateRole.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("UpdateRole.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) processDeleteRole(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.DeleteRoleKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "identityMessageProcessor.processDeleteRole")
+	defer span.End()
+	msg := &kafkaMessages.RoleDelete{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.RoleDeleteEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("proto.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	command := commands.NewDeleteRoleCommand(id, msg.GetTenantID())
+	if err = s.v.StructCtx(ctx, command); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.roles.Commands.DeleteRole.Handle(ctx, command)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("DeleteRole.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *identityMessageProcessor) ProcessMessages(ctx context.Context, r messaging.Reader, wg *sync.WaitGroup, workerID int) {
 	defer wg.Done()
 	for {
 		select {
@@ -461,11 +850,14 @@ func (s *identityMessageProcessor) ProcessMessages(ctx context.Context, r *kafka
 		default:
 		}
 		m, err := r.FetchMessage(ctx)
+		if errors.Is(err, messaging.ErrClosed) {
+			return
+		}
 		if err != nil {
 			s.log.Warnf("workerID: %v, err: %v", workerID, err)
 			continue
 		}
-		s.logProcessMessage(m, workerID)
+		s.logProcessMessage(ctx, m, workerID)
 		switch m.Topic {
 		case s.cfg.KafkaTopics.UserCreate.TopicName:
 			s.processCreateUser(ctx, r, m)
@@ -477,6 +869,34 @@ func (s *identityMessageProcessor) ProcessMessages(ctx context.Context, r *kafka
 			s.processBlacklistToken(ctx, r, m)
 		case s.cfg.KafkaTopics.PasswordUpdate.TopicName:
 			s.processUpdatePassword(ctx, r, m)
+		case s.cfg.KafkaTopics.GroupCreate.TopicName:
+			s.processCreateGroup(ctx, r, m)
+		case s.cfg.KafkaTopics.GroupUpdate.TopicName:
+			s.processUpdateGroup(ctx, r, m)
+		case s.cfg.KafkaTopics.GroupDelete.TopicName:
+			s.processDeleteGroup(ctx, r, m)
+		case s.cfg.KafkaTopics.MembershipCreate.TopicName:
+			s.processCreateMembership(ctx, r, m)
+		case s.cfg.KafkaTopics.MembershipUpdate.TopicName:
+			s.processUpdateMembership(ctx, r, m)
+		case s.cfg.KafkaTopics.MembershipDelete.TopicName:
+			s.processDeleteMembership(ctx, r, m)
+		case s.cfg.KafkaTopics.MembershipExtend.TopicName:
+			s.processExtendMembership(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationCreate.TopicName:
+			s.processCreateOrganization(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationUpdate.TopicName:
+			s.processUpdateOrganization(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationStatusChange.TopicName:
+			s.processChangeOrganizationStatus(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationDelete.TopicName:
+			s.processDeleteOrganization(ctx, r, m)
+		case s.cfg.KafkaTopics.RoleCreate.TopicName:
+			s.processCreateRole(ctx, r, m)
+		case s.cfg.KafkaTopics.RoleUpdate.TopicName:
+			s.processUpdateRole(ctx, r, m)
+		case s.cfg.KafkaTopics.RoleDelete.TopicName:
+			s.processDeleteRole(ctx, r, m)
 		}
 	}
 }
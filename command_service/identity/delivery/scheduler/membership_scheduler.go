@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/commands"
+	"github.com/JECSand/identity-service/command_service/identity/metrics"
+	"github.com/JECSand/identity-service/command_service/identity/services"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"time"
+)
+
+const defaultExpiryInterval = time.Minute
+
+// membershipScheduler periodically ends expired memberships, starts scheduled ones and warns about the ones that are
+// about to expire
+type membershipScheduler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	ms      *services.MembershipService
+	metrics *metrics.CommandServiceMetrics
+}
+
+// NewMembershipScheduler ...
+func NewMembershipScheduler(log logging.Logger, cfg *config.Config, ms *services.MembershipService, metrics *metrics.CommandServiceMetrics) *membershipScheduler {
+	return &membershipScheduler{
+		log:     log,
+		cfg:     cfg,
+		ms:      ms,
+		metrics: metrics,
+	}
+}
+
+// Run expires memberships once and then every membershipExpiry.interval until ctx is done
+func (s *membershipScheduler) Run(ctx context.Context) {
+	interval := s.cfg.MembershipExpiry.Interval
+	if interval <= 0 {
+		interval = defaultExpiryInterval
+	}
+	s.log.Infof("Starting membership expiry scheduler every %s", interval)
+	s.expire(ctx, time.Now())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.expire(ctx, now)
+		}
+	}
+}
+
+func (s *membershipScheduler) expire(ctx context.Context, now time.Time) {
+	s.metrics.MembershipExpiryRuns.Inc()
+	if err := s.ms.Commands.ExpireMemberships.Handle(ctx, commands.NewExpireMembershipsCommand(now)); err != nil {
+		s.log.WarnMsg("ExpireMemberships.Handle", err)
+		s.metrics.ErrorMembershipExpiryRuns.Inc()
+	}
+}
@@ -8,40 +8,55 @@ import (
 )
 
 type CommandServiceMetrics struct {
-	SuccessGrpcRequests             prometheus.Counter
-	ErrorGrpcRequests               prometheus.Counter
-	CreateUserGrpcRequests          prometheus.Counter
-	UpdateUserGrpcRequests          prometheus.Counter
-	DeleteUserGrpcRequests          prometheus.Counter
-	GetUserByIdGrpcRequests         prometheus.Counter
-	SearchUserGrpcRequests          prometheus.Counter
-	CreateGroupGrpcRequests         prometheus.Counter
-	UpdateGroupGrpcRequests         prometheus.Counter
-	DeleteGroupGrpcRequests         prometheus.Counter
-	GetGroupByIdGrpcRequests        prometheus.Counter
-	SearchGroupGrpcRequests         prometheus.Counter
-	CreateMembershipGrpcRequests    prometheus.Counter
-	UpdateMembershipGrpcRequests    prometheus.Counter
-	DeleteMembershipGrpcRequests    prometheus.Counter
-	GetMembershipByIdGrpcRequests   prometheus.Counter
-	GetUserMembershipGrpcRequests   prometheus.Counter
-	GetGroupMembershipGrpcRequests  prometheus.Counter
-	BlacklistTokenGrpcRequests      prometheus.Counter
-	PasswordUpdateGrpcRequests      prometheus.Counter
-	CheckTokenBlacklistGrpcRequests prometheus.Counter
-	SuccessKafkaMessages            prometheus.Counter
-	ErrorKafkaMessages              prometheus.Counter
-	CreateUserKafkaMessages         prometheus.Counter
-	UpdateUserKafkaMessages         prometheus.Counter
-	DeleteUserKafkaMessages         prometheus.Counter
-	CreateGroupKafkaMessages        prometheus.Counter
-	UpdateGroupKafkaMessages        prometheus.Counter
-	DeleteGroupKafkaMessages        prometheus.Counter
-	CreateMembershipKafkaMessages   prometheus.Counter
-	UpdateMembershipKafkaMessages   prometheus.Counter
-	DeleteMembershipKafkaMessages   prometheus.Counter
-	BlacklistTokenKafkaMessages     prometheus.Counter
-	PasswordUpdateKafkaMessages     prometheus.Counter
+	SuccessGrpcRequests                   prometheus.Counter
+	ErrorGrpcRequests                     prometheus.Counter
+	CreateUserGrpcRequests                prometheus.Counter
+	UpdateUserGrpcRequests                prometheus.Counter
+	DeleteUserGrpcRequests                prometheus.Counter
+	GetUserByIdGrpcRequests               prometheus.Counter
+	ReserveIdentifiersGrpcRequests        prometheus.Counter
+	ChangeUserStatusGrpcRequests          prometheus.Counter
+	SearchUserGrpcRequests                prometheus.Counter
+	CreateGroupGrpcRequests               prometheus.Counter
+	UpdateGroupGrpcRequests               prometheus.Counter
+	DeleteGroupGrpcRequests               prometheus.Counter
+	GetGroupByIdGrpcRequests              prometheus.Counter
+	SetGroupParentGrpcRequests            prometheus.Counter
+	SearchGroupGrpcRequests               prometheus.Counter
+	CreateMembershipGrpcRequests          prometheus.Counter
+	UpdateMembershipGrpcRequests          prometheus.Counter
+	DeleteMembershipGrpcRequests          prometheus.Counter
+	GetMembershipByIdGrpcRequests         prometheus.Counter
+	GetUserMembershipGrpcRequests         prometheus.Counter
+	GetGroupMembershipGrpcRequests        prometheus.Counter
+	BlacklistTokenGrpcRequests            prometheus.Counter
+	PasswordUpdateGrpcRequests            prometheus.Counter
+	CheckTokenBlacklistGrpcRequests       prometheus.Counter
+	CheckPasswordGrpcRequests             prometheus.Counter
+	AuthenticateGrpcRequests              prometheus.Counter
+	SuccessKafkaMessages                  prometheus.Counter
+	ErrorKafkaMessages                    prometheus.Counter
+	CreateUserKafkaMessages               prometheus.Counter
+	UpdateUserKafkaMessages               prometheus.Counter
+	DeleteUserKafkaMessages               prometheus.Counter
+	CreateGroupKafkaMessages              prometheus.Counter
+	UpdateGroupKafkaMessages              prometheus.Counter
+	DeleteGroupKafkaMessages              prometheus.Counter
+	CreateMembershipKafkaMessages         prometheus.Counter
+	UpdateMembershipKafkaMessages         prometheus.Counter
+	DeleteMembershipKafkaMessages         prometheus.Counter
+	ExtendMembershipKafkaMessages         prometheus.Counter
+	MembershipExpiryRuns                  prometheus.Counter
+	ErrorMembershipExpiryRuns             prometheus.Counter
+	BlacklistTokenKafkaMessages           prometheus.Counter
+	PasswordUpdateKafkaMessages           prometheus.Counter
+	CreateOrganizationKafkaMessages       prometheus.Counter
+	UpdateOrganizationKafkaMessages       prometheus.Counter
+	ChangeOrganizationStatusKafkaMessages prometheus.Counter
+	DeleteOrganizationKafkaMessages       prometheus.Counter
+	CreateRoleKafkaMessages               prometheus.Counter
+	UpdateRoleKafkaMessages               prometheus.Counter
+	DeleteRoleKafkaMessages               prometheus.Counter
 }
 
 func NewCommandServiceMetrics(cfg *config.Config) *CommandServiceMetrics {
@@ -70,6 +85,14 @@ func NewCommandServiceMetrics(cfg *config.Config) *CommandServiceMetrics {
 			Name: fmt.Sprintf("%s_get_user_by_id_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of get user by id grpc requests",
 		}),
+		ReserveIdentifiersGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_reserve_identifiers_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of reserve identifiers grpc requests",
+		}),
+		ChangeUserStatusGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_change_user_status_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of change user status grpc requests",
+		}),
 		SearchUserGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_search_user_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of search user grpc requests",
@@ -90,6 +113,10 @@ func NewCommandServiceMetrics(cfg *config.Config) *CommandServiceMetrics {
 			Name: fmt.Sprintf("%s_get_group_by_id_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of get group by id grpc requests",
 		}),
+		SetGroupParentGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_set_group_parent_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of set group parent grpc requests",
+		}),
 		SearchGroupGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_search_group_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of search group grpc requests",
@@ -130,6 +157,14 @@ func NewCommandServiceMetrics(cfg *config.Config) *CommandServiceMetrics {
 			Name: fmt.Sprintf("%s_check_token_blacklist_grpc_messages_total", cfg.ServiceName),
 			Help: "The total number of check token blacklist grpc messages",
 		}),
+		CheckPasswordGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_check_password_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of check password grpc requests",
+		}),
+		AuthenticateGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_authenticate_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of authenticate grpc requests",
+		}),
 		CreateUserKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_create_user_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of create user kafka messages",
@@ -166,6 +201,18 @@ func NewCommandServiceMetrics(cfg *config.Config) *CommandServiceMetrics {
 			Name: fmt.Sprintf("%s_delete_membership_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of delete membership kafka messages",
 		}),
+		ExtendMembershipKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_extend_membership_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of extend membership kafka messages",
+		}),
+		MembershipExpiryRuns: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_membership_expiry_runs_total", cfg.ServiceName),
+			Help: "The total number of membership expiry runs",
+		}),
+		ErrorMembershipExpiryRuns: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_error_membership_expiry_runs_total", cfg.ServiceName),
+			Help: "The total number of failed membership expiry runs",
+		}),
 		BlacklistTokenKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_blacklist_token_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of blacklist token kafka messages",
@@ -182,5 +229,33 @@ func NewCommandServiceMetrics(cfg *config.Config) *CommandServiceMetrics {
 			Name: fmt.Sprintf("%s_error_kafka_processed_messages_total", cfg.ServiceName),
 			Help: "The total number of error kafka processed messages",
 		}),
+		CreateOrganizationKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_create_organization_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of create organization kafka messages",
+		}),
+		UpdateOrganizationKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_update_organization_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of update organization kafka messages",
+		}),
+		ChangeOrganizationStatusKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_change_organization_status_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of change organization status kafka messages",
+		}),
+		DeleteOrganizationKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_delete_organization_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of delete organization kafka messages",
+		}),
+		CreateRoleKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_create_role_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of create role kafka messages",
+		}),
+		UpdateRoleKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_update_role_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of update role kafka messages",
+		}),
+		DeleteRoleKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_delete_role_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of delete role kafka messages",
+		}),
 	}
 }
@@ -6,11 +6,13 @@ import (
 )
 
 type Group struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name,omitempty"`
-	Description string    `json:"description,omitempty"`
-	CreatorID   uuid.UUID `json:"creatorID,omitempty"`
-	Active      bool      `json:"active,omitempty"`
-	CreatedAt   time.Time `json:"createdAt,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	CreatorID      uuid.UUID `json:"creatorID,omitempty"`
+	Active         bool      `json:"active,omitempty"`
+	OrganizationID uuid.UUID `json:"organizationID,omitempty"`
+	ParentID       uuid.UUID `json:"parentID,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty"`
 }
@@ -7,38 +7,73 @@ import (
 )
 
 type Membership struct {
-	ID        uuid.UUID              `json:"id"`
-	UserID    uuid.UUID              `json:"userID,omitempty"`
-	GroupID   uuid.UUID              `json:"groupID,omitempty"`
-	Status    enums.MembershipStatus `json:"status,omitempty"`
-	Role      enums.Role             `json:"role,omitempty"`
-	CreatedAt time.Time              `json:"createdAt,omitempty"`
-	UpdatedAt time.Time              `json:"updatedAt,omitempty"`
+	ID             uuid.UUID              `json:"id"`
+	UserID         uuid.UUID              `json:"userID,omitempty"`
+	GroupID        uuid.UUID              `json:"groupID,omitempty"`
+	Status         enums.MembershipStatus `json:"status,omitempty"`
+	Role           enums.Role             `json:"role,omitempty"`
+	RoleID         uuid.UUID              `json:"roleID,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty"`
+	OrganizationID uuid.UUID              `json:"organizationID,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty"`
+	ExpiryWarnedAt time.Time              `json:"expiryWarnedAt,omitempty"`
+	ScheduledStart bool                   `json:"scheduledStart,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty"`
 }
 
 type UserMembership struct {
-	ID           uuid.UUID              `json:"id"`
-	GroupID      uuid.UUID              `json:"groupID,omitempty"`
-	UserID       uuid.UUID              `json:"userID,omitempty"`
-	MembershipID uuid.UUID              `json:"membershipID,omitempty"`
-	Email        string                 `json:"email,omitempty"`
-	Username     string                 `json:"username,omitempty"`
-	Status       enums.MembershipStatus `json:"status,omitempty"`
-	Role         enums.Role             `json:"role,omitempty"`
-	CreatedAt    time.Time              `json:"createdAt,omitempty"`
-	UpdatedAt    time.Time              `json:"updatedAt,omitempty"`
+	ID             uuid.UUID              `json:"id"`
+	GroupID        uuid.UUID              `json:"groupID,omitempty"`
+	UserID         uuid.UUID              `json:"userID,omitempty"`
+	MembershipID   uuid.UUID              `json:"membershipID,omitempty"`
+	Email          string                 `json:"email,omitempty"`
+	Username       string                 `json:"username,omitempty"`
+	Status         enums.MembershipStatus `json:"status,omitempty"`
+	Role           enums.Role             `json:"role,omitempty"`
+	RoleID         uuid.UUID              `json:"roleID,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty"`
+	OrganizationID uuid.UUID              `json:"organizationID,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty"`
 }
 
 type GroupMembership struct {
-	ID           uuid.UUID              `json:"id"`
-	UserID       uuid.UUID              `json:"userID,omitempty"`
-	GroupID      uuid.UUID              `json:"groupID,omitempty"`
-	MembershipID uuid.UUID              `json:"membershipID,omitempty"`
-	Name         string                 `json:"name,omitempty"`
-	Description  string                 `json:"description,omitempty"`
-	Status       enums.MembershipStatus `json:"status,omitempty"`
-	Role         enums.Role             `json:"role,omitempty"`
-	Creator      bool                   `json:"creator,omitempty"`
-	CreatedAt    time.Time              `json:"createdAt,omitempty"`
-	UpdatedAt    time.Time              `json:"updatedAt,omitempty"`
+	ID             uuid.UUID              `json:"id"`
+	UserID         uuid.UUID              `json:"userID,omitempty"`
+	GroupID        uuid.UUID              `json:"groupID,omitempty"`
+	MembershipID   uuid.UUID              `json:"membershipID,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	Description    string                 `json:"description,omitempty"`
+	Status         enums.MembershipStatus `json:"status,omitempty"`
+	Role           enums.Role             `json:"role,omitempty"`
+	RoleID         uuid.UUID              `json:"roleID,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty"`
+	OrganizationID uuid.UUID              `json:"organizationID,omitempty"`
+	Creator        bool                   `json:"creator,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty"`
+}
+
+// MembershipAuditExtend is the action of audit records written when a membership is extended
+const MembershipAuditExtend = "extend"
+
+// MembershipAudit records a change to the validity window of a membership and who made it
+type MembershipAudit struct {
+	ID                 uuid.UUID `json:"id"`
+	MembershipID       uuid.UUID `json:"membershipID"`
+	Action             string    `json:"action"`
+	ActorID            uuid.UUID `json:"actorID,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	PreviousValidUntil time.Time `json:"previousValidUntil,omitempty"`
+	ValidUntil         time.Time `json:"validUntil,omitempty"`
+	CreatedAt          time.Time `json:"createdAt,omitempty"`
 }
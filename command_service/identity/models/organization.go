@@ -0,0 +1,16 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+type Organization struct {
+	ID                uuid.UUID `json:"id"`
+	Name              string    `json:"name,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	Active            bool      `json:"active,omitempty"`
+	SessionsRevokedAt time.Time `json:"sessionsRevokedAt,omitempty"`
+	CreatedAt         time.Time `json:"createdAt,omitempty"`
+	UpdatedAt         time.Time `json:"updatedAt,omitempty"`
+}
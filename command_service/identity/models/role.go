@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+type Role struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	Permissions    []string  `json:"permissions,omitempty"`
+	GroupID        uuid.UUID `json:"groupID,omitempty"`
+	OrganizationID uuid.UUID `json:"organizationID,omitempty"`
+	BuiltIn        bool      `json:"builtIn,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty"`
+}
+
+// BuiltInRole returns the built-in Role seeded for the Role enum value r
+func BuiltInRole(r enums.Role) *Role {
+	return &Role{
+		ID:          uuid.FromStringOrNil(r.RoleID()),
+		Name:        r.Stringify(),
+		Description: "Built-in " + r.Stringify() + " role",
+		Permissions: r.Permissions(),
+		BuiltIn:     true,
+	}
+}
+
+// EnumRole returns the Role enum value memberships with the Role carry; custom roles grant MEMBER access to the checks
+// that still read the enum
+func (r *Role) EnumRole() enums.Role {
+	if role := enums.RoleFromID(r.ID.String()); role != 0 {
+		return role
+	}
+	return enums.MEMBER
+}
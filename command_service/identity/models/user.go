@@ -2,32 +2,58 @@ package models
 
 import (
 	"errors"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/gofrs/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"time"
 )
 
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email,omitempty"`
-	Username  string    `json:"username,omitempty"`
-	Password  string    `json:"password,omitempty"`
-	Root      bool      `json:"root,omitempty"`
-	Active    bool      `json:"active,omitempty"`
-	CreatedAt time.Time `json:"createdAt,omitempty"`
-	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	ID                uuid.UUID        `json:"id"`
+	Email             string           `json:"email,omitempty"`
+	Username          string           `json:"username,omitempty"`
+	Password          string           `json:"password,omitempty"`
+	Root              bool             `json:"root,omitempty"`
+	Active            bool             `json:"active,omitempty"`
+	Status            enums.UserStatus `json:"status,omitempty"`
+	StatusReason      string           `json:"statusReason,omitempty"`
+	SuspendedUntil    time.Time        `json:"suspendedUntil,omitempty"`
+	SessionsRevokedAt time.Time        `json:"sessionsRevokedAt,omitempty"`
+	OrganizationID    uuid.UUID        `json:"organizationID,omitempty"`
+	OrgAdmin          bool             `json:"orgAdmin,omitempty"`
+	CreatedAt         time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt         time.Time        `json:"updatedAt,omitempty"`
 }
 
-// HashPassword hashes a User Password
-func (u *User) HashPassword() error {
+// HashPassword hashes a User Password with hasher
+func (u *User) HashPassword(hasher authentication.PasswordHasher) error {
 	if len(u.Password) != 0 {
-		password := []byte(u.Password)
-		hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+		hashedPassword, err := hasher.Hash(u.Password)
 		if err != nil {
 			return err
 		}
-		u.Password = string(hashedPassword)
+		u.Password = hashedPassword
 		return nil
 	}
 	return errors.New("user password is missing")
 }
+
+// NormalizeIdentifiers puts the User Email and Username in the canonical form they are unique in
+func (u *User) NormalizeIdentifiers() {
+	u.Email = authentication.NormalizeEmail(u.Email)
+	u.Username = authentication.NormalizeUsername(u.Username)
+}
+
+// LifecycleStatus returns the User Status, derived from Active for users stored before lifecycle states
+func (u *User) LifecycleStatus() enums.UserStatus {
+	if u.Status == 0 {
+		return enums.UserStatusFromActive(u.Active)
+	}
+	return u.Status
+}
+
+// SetStatus moves the User into status, keeping Active in step with it
+func (u *User) SetStatus(status enums.UserStatus) {
+	u.Status = status
+	u.Active = status == enums.ACTIVATED
+}
@@ -1,14 +1,22 @@
 package queries
 
+import (
+	"github.com/gofrs/uuid"
+)
+
 // AuthQueries ...
 type AuthQueries struct {
 	CheckTokenBlacklist CheckTokenBlacklistHandler
+	CheckPassword       CheckPasswordHandler
+	Authenticate        AuthenticateHandler
 }
 
 // NewAuthQueries ...
-func NewAuthQueries(checkBlacklist CheckTokenBlacklistHandler) *AuthQueries {
+func NewAuthQueries(checkBlacklist CheckTokenBlacklistHandler, checkPassword CheckPasswordHandler, authenticate AuthenticateHandler) *AuthQueries {
 	return &AuthQueries{
 		CheckTokenBlacklist: checkBlacklist,
+		CheckPassword:       checkPassword,
+		Authenticate:        authenticate,
 	}
 }
 
@@ -23,3 +31,37 @@ func NewCheckTokenBlacklistQuery(accessToken string) *CheckTokenBlacklistQuery {
 		AccessToken: accessToken,
 	}
 }
+
+// CheckPasswordQuery checks a new password of the user ID, or of a user yet to be created when ID is nil
+type CheckPasswordQuery struct {
+	ID       uuid.UUID `json:"id"`
+	Email    string    `json:"email" validate:"lte=255"`
+	Username string    `json:"username" validate:"lte=255"`
+	Password string    `json:"password" validate:"required"`
+}
+
+// NewCheckPasswordQuery ...
+func NewCheckPasswordQuery(id uuid.UUID, email string, username string, password string) *CheckPasswordQuery {
+	return &CheckPasswordQuery{
+		ID:       id,
+		Email:    email,
+		Username: username,
+		Password: password,
+	}
+}
+
+// AuthenticateQuery verifies the password of the user ID, or of the user with Email when ID is nil
+type AuthenticateQuery struct {
+	ID       uuid.UUID `json:"id"`
+	Email    string    `json:"email" validate:"required_without=ID,lte=255"`
+	Password string    `json:"password" validate:"required,lte=255"`
+}
+
+// NewAuthenticateQuery ...
+func NewAuthenticateQuery(id uuid.UUID, email string, password string) *AuthenticateQuery {
+	return &AuthenticateQuery{
+		ID:       id,
+		Email:    email,
+		Password: password,
+	}
+}
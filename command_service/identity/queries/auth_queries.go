@@ -2,10 +2,17 @@ package queries
 
 import (
 	"context"
+	"database/sql"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"time"
 )
 
 /*
@@ -36,3 +43,141 @@ func NewCheckTokenBlacklistHandler(log logging.Logger, cfg *config.Config, pgRep
 func (q *checkTokenBlacklistHandler) Handle(ctx context.Context, query *CheckTokenBlacklistQuery) (*models.Blacklist, error) {
 	return q.pgRepo.CheckBlacklist(ctx, query.AccessToken)
 }
+
+/*
+CHECK PASSWORD
+*/
+
+// CheckPasswordHandler ...
+type CheckPasswordHandler interface {
+	Handle(ctx context.Context, query *CheckPasswordQuery) error
+}
+
+type checkPasswordHandler struct {
+	log    logging.Logger
+	cfg    *config.Config
+	pgRepo repositories.Repository
+	policy authentication.PasswordPolicy
+}
+
+// NewCheckPasswordHandler ...
+func NewCheckPasswordHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, policy authentication.PasswordPolicy) *checkPasswordHandler {
+	return &checkPasswordHandler{
+		log:    log,
+		cfg:    cfg,
+		pgRepo: pgRepo,
+		policy: policy,
+	}
+}
+
+// Handle returns an *authentication.PasswordPolicyError listing the rules the password fails; an existing user is
+// checked against its current and previous passwords and, when left out of the query, its email and username
+func (q *checkPasswordHandler) Handle(ctx context.Context, query *CheckPasswordQuery) error {
+	ctx, span := tracing.StartSpan(ctx, "checkPasswordHandler.Handle")
+	defer span.End()
+	subject := &authentication.PasswordSubject{Email: query.Email, Username: query.Username}
+	if query.ID != uuid.Nil {
+		user, err := q.pgRepo.GetUserById(ctx, query.ID)
+		if err != nil {
+			return err
+		}
+		if subject.Email == "" {
+			subject.Email = user.Email
+		}
+		if subject.Username == "" {
+			subject.Username = user.Username
+		}
+		if size := q.cfg.PasswordPolicy.HistorySize; size > 0 {
+			history, err := q.pgRepo.GetUserPasswordHistory(ctx, query.ID, size-1)
+			if err != nil {
+				return err
+			}
+			subject.Hashes = append([]string{user.Password}, history...)
+		}
+	}
+	return q.policy.Check(query.Password, subject)
+}
+
+/*
+AUTHENTICATE
+*/
+
+// AuthenticateHandler ...
+type AuthenticateHandler interface {
+	Handle(ctx context.Context, query *AuthenticateQuery) (*models.User, error)
+}
+
+type authenticateHandler struct {
+	log    logging.Logger
+	cfg    *config.Config
+	pgRepo repositories.Repository
+	hasher authentication.PasswordHasher
+}
+
+// NewAuthenticateHandler ...
+func NewAuthenticateHandler(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, hasher authentication.PasswordHasher) *authenticateHandler {
+	return &authenticateHandler{
+		log:    log,
+		cfg:    cfg,
+		pgRepo: pgRepo,
+		hasher: hasher,
+	}
+}
+
+// Handle verifies the password against the stored hash and returns the user without it; an unknown user fails like
+// a wrong password and takes as long, a user that is not active fails with an *authentication.UserStatusError and a user of a suspended
+// organization with authentication.ErrOrganizationSuspended. A hash made with outdated parameters is upgraded in place
+func (q *authenticateHandler) Handle(ctx context.Context, query *AuthenticateQuery) (*models.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "authenticateHandler.Handle")
+	defer span.End()
+	var user *models.User
+	var err error
+	if query.ID != uuid.Nil {
+		user, err = q.pgRepo.GetUserById(ctx, query.ID)
+	} else {
+		user, err = q.pgRepo.GetUserByEmail(ctx, authentication.NormalizeEmail(query.Email))
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		return nil, q.hasher.VerifyUnknown(query.Password)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = q.hasher.Verify(user.Password, query.Password); err != nil {
+		return nil, err
+	}
+	if err = authentication.CheckUserStatus(user.LifecycleStatus(), user.StatusReason, user.SuspendedUntil, time.Now()); err != nil {
+		return nil, err
+	}
+	if user.OrganizationID != uuid.Nil {
+		organization, err := q.pgRepo.GetOrganizationById(ctx, user.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if !organization.Active {
+			return nil, authentication.ErrOrganizationSuspended
+		}
+	}
+	if q.hasher.NeedsRehash(user.Password) {
+		if err = q.rehashPassword(ctx, user, query.Password); err != nil {
+			q.log.WithContext(ctx).WarnMsg("authenticateHandler.rehashPassword", err)
+		}
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// rehashPassword swaps in a hash of the verified password made with the configured algorithm, only while the
+// stored hash is still the outdated one; losing to a concurrent password change or rehash is not an error
+func (q *authenticateHandler) rehashPassword(ctx context.Context, user *models.User, password string) error {
+	newHash, err := q.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	_, err = q.pgRepo.RehashUserPassword(ctx, &models.User{ID: user.ID, Password: newHash}, user.Password)
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		q.log.WithContext(ctx).Infof("skipping stale password rehash of user %s", user.ID)
+		return nil
+	}
+	return err
+}
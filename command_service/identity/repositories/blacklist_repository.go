@@ -5,9 +5,10 @@ import (
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
 const (
@@ -37,8 +38,8 @@ func NewBlacklistRepository(log logging.Logger, cfg *config.Config, db *pgxpool.
 
 // Count ...
 func (p *blacklistRepository) Count(ctx context.Context) (int, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistRepository.Count")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "blacklists")
+	defer span.End()
 	type countRes struct {
 		count int
 	}
@@ -53,8 +54,8 @@ func (p *blacklistRepository) Count(ctx context.Context) (int, error) {
 
 // Create ...
 func (p *blacklistRepository) Create(ctx context.Context, bl *models.Blacklist) (*models.Blacklist, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistRepository.Create")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "blacklists")
+	defer span.End()
 	var created models.Blacklist
 	if err := p.db.QueryRow(ctx, blacklistQuery, &bl.ID, &bl.AccessToken).Scan(
 		&created.ID,
@@ -68,8 +69,8 @@ func (p *blacklistRepository) Create(ctx context.Context, bl *models.Blacklist)
 
 // GetByAccessToken ...
 func (p *blacklistRepository) GetByAccessToken(ctx context.Context, accessToken string) (*models.Blacklist, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistRepository.GetByAccessToken")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "blacklists")
+	defer span.End()
 	var found models.Blacklist
 	if err := p.db.QueryRow(ctx, checkBlacklistQuery, accessToken).Scan(
 		&found.ID,
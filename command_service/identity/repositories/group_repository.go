@@ -5,31 +5,50 @@ import (
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/gofrs/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
 const (
-	createGroupQuery = `INSERT INTO user_groups (id, group_name, description, creator_id, active, created_at, updated_at) 
-	VALUES ($1, $2, $3, $4, $5, now(), now()) RETURNING id, group_name, description, creator_id, active, created_at, updated_at`
+	createGroupQuery = `INSERT INTO user_groups (id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now()) RETURNING id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at`
 
 	updateGroupQuery = `UPDATE user_groups p SET 
                       group_name=COALESCE(NULLIF($2, ''), group_name), 
                       description=COALESCE(NULLIF($3, ''), description), 
                       updated_at = now()
                       WHERE id=$1
-                      RETURNING id, group_name, description, creator_id, active, created_at, updated_at`
+                      RETURNING id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at`
 
-	getGroupByIdQuery = `SELECT p.id, p.group_name AS name, p.description, p.creator_id, p.active, p.created_at, p.updated_at 
+	setGroupParentQuery = `UPDATE user_groups SET parent_id = $2, updated_at = now() WHERE id = $1
+	RETURNING id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at`
+
+	lockGroupQuery = `SELECT organization_id FROM user_groups WHERE id = $1 FOR UPDATE`
+
+	lockGroupHierarchyQuery = `SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))`
+
+	groupHierarchyCycleQuery = `WITH RECURSIVE ancestors (id, parent_id) AS (
+	SELECT id, parent_id FROM user_groups WHERE id = $2
+	UNION
+	SELECT g.id, g.parent_id FROM user_groups g INNER JOIN ancestors a ON g.id = a.parent_id
+	) SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $1)`
+
+	getGroupByIdQuery = `SELECT p.id, p.group_name AS name, p.description, p.creator_id, p.active, p.organization_id, p.parent_id, p.created_at, p.updated_at 
 	FROM user_groups p WHERE p.id = $1`
 
+	detachChildGroupsQuery = `UPDATE user_groups SET parent_id = '00000000-0000-0000-0000-000000000000', updated_at = now() WHERE parent_id = $1`
+
 	deleteGroupByIdQuery = `DELETE FROM user_groups WHERE id = $1`
 
 	countGroupsQuery = `SELECT COUNT(*) from user_groups`
 )
 
+// ErrGroupHierarchyCycle is returned when a group would become its own ancestor
+var ErrGroupHierarchyCycle = errors.New("group hierarchy cycle")
+
 type groupRepository struct {
 	log logging.Logger
 	cfg *config.Config
@@ -47,8 +66,8 @@ func NewGroupRepository(log logging.Logger, cfg *config.Config, db *pgxpool.Pool
 
 // Count ...
 func (p *groupRepository) Count(ctx context.Context) (int, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.CountGroups")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "user_groups")
+	defer span.End()
 	type countRes struct {
 		count int
 	}
@@ -63,15 +82,17 @@ func (p *groupRepository) Count(ctx context.Context) (int, error) {
 
 // Create ...
 func (p *groupRepository) Create(ctx context.Context, group *models.Group) (*models.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.CreateGroup")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "user_groups")
+	defer span.End()
 	var created models.Group
-	if err := p.db.QueryRow(ctx, createGroupQuery, &group.ID, &group.Name, &group.Description, &group.CreatorID, group.Active).Scan(
+	if err := p.db.QueryRow(ctx, createGroupQuery, &group.ID, &group.Name, &group.Description, &group.CreatorID, group.Active, &group.OrganizationID, &group.ParentID).Scan(
 		&created.ID,
 		&created.Name,
 		&created.Description,
 		&created.CreatorID,
 		&created.Active,
+		&created.OrganizationID,
+		&created.ParentID,
 		&created.CreatedAt,
 		&created.UpdatedAt,
 	); err != nil {
@@ -82,8 +103,8 @@ func (p *groupRepository) Create(ctx context.Context, group *models.Group) (*mod
 
 // Update ...
 func (p *groupRepository) Update(ctx context.Context, group *models.Group) (*models.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.UpdateGroup")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "user_groups")
+	defer span.End()
 	var updated models.Group
 	if err := p.db.QueryRow(
 		ctx,
@@ -91,16 +112,63 @@ func (p *groupRepository) Update(ctx context.Context, group *models.Group) (*mod
 		&group.ID,
 		&group.Name,
 		&group.Description,
-	).Scan(&updated.ID, &updated.Name, &updated.Description, &updated.CreatorID, &updated.Active, &updated.CreatedAt, &updated.UpdatedAt); err != nil {
+	).Scan(&updated.ID, &updated.Name, &updated.Description, &updated.CreatorID, &updated.Active, &updated.OrganizationID, &updated.ParentID, &updated.CreatedAt, &updated.UpdatedAt); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return &updated, nil
+}
+
+// SetParent moves a group below another group, or to the top level when parentID is uuid.Nil. Hierarchy changes of
+// an organization are serialized and the move fails with ErrGroupHierarchyCycle when the group is an ancestor of
+// parentID, checked in the same transaction as the update so that concurrent moves cannot create a cycle
+func (p *groupRepository) SetParent(ctx context.Context, id uuid.UUID, parentID uuid.UUID) (*models.Group, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "user_groups")
+	defer span.End()
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Begin")
+	}
+	defer tx.Rollback(ctx) // nolint: errCheck
+	var organizationID uuid.UUID
+	if err = tx.QueryRow(ctx, lockGroupQuery, id).Scan(&organizationID); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	if _, err = tx.Exec(ctx, lockGroupHierarchyQuery, organizationID); err != nil {
+		return nil, errors.Wrap(err, "Exec")
+	}
+	if parentID != uuid.Nil {
+		var cycle bool
+		if err = tx.QueryRow(ctx, groupHierarchyCycleQuery, id, parentID).Scan(&cycle); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		if cycle {
+			return nil, ErrGroupHierarchyCycle
+		}
+	}
+	var updated models.Group
+	if err = tx.QueryRow(ctx, setGroupParentQuery, id, parentID).Scan(
+		&updated.ID,
+		&updated.Name,
+		&updated.Description,
+		&updated.CreatorID,
+		&updated.Active,
+		&updated.OrganizationID,
+		&updated.ParentID,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+	); err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "Commit")
+	}
 	return &updated, nil
 }
 
 // GetById ...
 func (p *groupRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.GetGroupById")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "user_groups")
+	defer span.End()
 	var found models.Group
 	if err := p.db.QueryRow(ctx, getGroupByIdQuery, uuid).Scan(
 		&found.ID,
@@ -108,6 +176,8 @@ func (p *groupRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.
 		&found.Description,
 		&found.CreatorID,
 		&found.Active,
+		&found.OrganizationID,
+		&found.ParentID,
 		&found.CreatedAt,
 		&found.UpdatedAt,
 	); err != nil {
@@ -116,13 +186,19 @@ func (p *groupRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.
 	return &found, nil
 }
 
-// DeleteByID ...
+// DeleteByID deletes a group together with its roles, moving its child groups to the top level
 func (p *groupRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.DeleteGroupByID")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "DELETE", "user_groups")
+	defer span.End()
+	if _, err := p.db.Exec(ctx, detachChildGroupsQuery, id); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
 	_, err := p.db.Exec(ctx, deleteGroupByIdQuery, id)
 	if err != nil {
 		return errors.Wrap(err, "Exec")
 	}
+	if _, err = p.db.Exec(ctx, deleteGroupRolesQuery, id); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
 	return nil
 }
@@ -2,29 +2,51 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"time"
 )
 
 const (
-	createMembershipQuery = `INSERT INTO memberships (id, user_id, group_id, status, member_role, created_at, updated_at) 
-	VALUES ($1, $2, $3, $4, $5, now(), now()) RETURNING id, user_id, group_id, status, member_role, created_at, updated_at`
+	createMembershipQuery = `INSERT INTO memberships (id, user_id, group_id, status, member_role, role_id, organization_id, valid_from, valid_until, scheduled_start, created_at, updated_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, now(), now()) RETURNING id, user_id, group_id, status, member_role, role_id, organization_id, created_at, updated_at, valid_from, valid_until, expiry_warned_at`
 
 	updateMembershipQuery = `UPDATE memberships p SET 
                       status=COALESCE(NULLIF($2, 0), status), 
+                      scheduled_start=CASE WHEN $2 = 0 THEN scheduled_start ELSE FALSE END, 
                       member_role=COALESCE(NULLIF($3, 0), member_role), 
+                      role_id=COALESCE(NULLIF($4, '00000000-0000-0000-0000-000000000000'::uuid), role_id), 
+                      expiry_warned_at=CASE WHEN $5::timestamptz IS NULL THEN expiry_warned_at END, 
+                      valid_until=COALESCE($5, valid_until), 
                       updated_at = now()
                       WHERE id=$1
-                      RETURNING id, user_id, group_id, status, member_role, created_at, updated_at`
+                      RETURNING id, user_id, group_id, status, member_role, role_id, organization_id, created_at, updated_at, valid_from, valid_until, expiry_warned_at`
 
-	getMembershipByIdQuery = `SELECT p.id, p.user_id, p.group_id, p.status, p.member_role, p.created_at, p.updated_at 
+	getMembershipByIdQuery = `SELECT p.id, p.user_id, p.group_id, p.status, p.member_role, p.role_id, p.organization_id, p.created_at, p.updated_at, 
+	p.valid_from, p.valid_until, p.expiry_warned_at 
 	FROM memberships p WHERE p.id = $1`
 
+	getMembershipsDueQuery = `SELECT p.id, p.user_id, p.group_id, p.status, p.member_role, p.role_id, p.organization_id, p.created_at, p.updated_at, 
+	p.valid_from, p.valid_until, p.expiry_warned_at 
+	FROM memberships p 
+	WHERE (p.status = 1 AND p.valid_until <= $1) 
+	OR (p.status = 4 AND p.scheduled_start AND p.valid_from <= $1) 
+	OR (p.status = 1 AND p.valid_until <= $2 AND p.expiry_warned_at IS NULL) 
+	ORDER BY p.valid_until LIMIT $3`
+
+	markMembershipExpiryWarnedQuery = `UPDATE memberships SET expiry_warned_at = $2 WHERE id = $1`
+
+	createMembershipAuditQuery = `INSERT INTO membership_audit (id, membership_id, action, actor_id, reason, previous_valid_until, valid_until, created_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, now())`
+
 	deleteMembershipByIdQuery = `DELETE FROM memberships WHERE id = $1`
 
 	countMembershipsQuery = `SELECT COUNT(*) from memberships`
@@ -38,6 +60,10 @@ const (
     	u.username,
     	p.status, 
     	p.member_role AS role, 
+    	p.role_id, 
+    	p.organization_id, 
+    	p.valid_from, 
+    	p.valid_until, 
     	p.created_at, 
     	p.updated_at 
 	FROM memberships p 
@@ -53,7 +79,11 @@ const (
     	g.description,
     	p.status, 
     	p.member_role AS role,
+    	p.role_id, 
+    	p.organization_id, 
     	(p.user_id = g.creator_id) AS creator,
+    	p.valid_from, 
+    	p.valid_until, 
     	p.created_at, 
     	p.updated_at 
 	FROM memberships p 
@@ -61,6 +91,44 @@ const (
 	WHERE p.id = $1`
 )
 
+// membershipValidity scans the validity columns of a memberships row, which stay NULL when unbounded
+type membershipValidity struct {
+	validFrom      sql.NullTime
+	validUntil     sql.NullTime
+	expiryWarnedAt sql.NullTime
+}
+
+// apply copies the scanned validity columns onto membership
+func (v *membershipValidity) apply(membership *models.Membership) {
+	membership.ValidFrom = v.validFrom.Time
+	membership.ValidUntil = v.validUntil.Time
+	membership.ExpiryWarnedAt = v.expiryWarnedAt.Time
+}
+
+// scanMembership scans a memberships row selected with its validity columns
+func scanMembership(row pgx.Row) (*models.Membership, error) {
+	var found models.Membership
+	var validity membershipValidity
+	if err := row.Scan(
+		&found.ID,
+		&found.UserID,
+		&found.GroupID,
+		&found.Status,
+		&found.Role,
+		&found.RoleID,
+		&found.OrganizationID,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+		&validity.validFrom,
+		&validity.validUntil,
+		&validity.expiryWarnedAt,
+	); err != nil {
+		return nil, err
+	}
+	validity.apply(&found)
+	return &found, nil
+}
+
 type membershipRepository struct {
 	log logging.Logger
 	cfg *config.Config
@@ -78,8 +146,8 @@ func NewMembershipRepository(log logging.Logger, cfg *config.Config, db *pgxpool
 
 // Count ...
 func (p *membershipRepository) Count(ctx context.Context) (int, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.CountMemberships")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "memberships")
+	defer span.End()
 	type countRes struct {
 		count int
 	}
@@ -94,64 +162,92 @@ func (p *membershipRepository) Count(ctx context.Context) (int, error) {
 
 // Create ...
 func (p *membershipRepository) Create(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.CreateMembership")
-	defer span.Finish()
-	var created models.Membership
-	if err := p.db.QueryRow(ctx, createMembershipQuery, &membership.ID, &membership.UserID, &membership.GroupID, &membership.Status, membership.Role).Scan(
-		&created.ID,
-		&created.UserID,
-		&created.GroupID,
-		&created.Status,
-		&created.Role,
-		&created.CreatedAt,
-		&created.UpdatedAt,
-	); err != nil {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "memberships")
+	defer span.End()
+	created, err := scanMembership(p.db.QueryRow(ctx, createMembershipQuery, &membership.ID, &membership.UserID, &membership.GroupID, &membership.Status, membership.Role, &membership.RoleID, &membership.OrganizationID, nullTime(membership.ValidFrom), nullTime(membership.ValidUntil), membership.ScheduledStart))
+	if err != nil {
 		return nil, errors.Wrap(err, "db.QueryRow")
 	}
-	return &created, nil
+	return created, nil
 }
 
 // Update ...
 func (p *membershipRepository) Update(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.UpdateMembership")
-	defer span.Finish()
-	var updated models.Membership
-	if err := p.db.QueryRow(
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "memberships")
+	defer span.End()
+	updated, err := scanMembership(p.db.QueryRow(
 		ctx,
 		updateMembershipQuery,
 		&membership.ID,
 		&membership.Status,
 		&membership.Role,
-	).Scan(&updated.ID, &updated.UserID, &updated.GroupID, &updated.Status, &updated.Role, &updated.CreatedAt, &updated.UpdatedAt); err != nil {
+		&membership.RoleID,
+		nullTime(membership.ValidUntil),
+	))
+	if err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
-	return &updated, nil
+	return updated, nil
 }
 
 // GetById ...
 func (p *membershipRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.GetMembershipById")
-	defer span.Finish()
-	var found models.Membership
-	if err := p.db.QueryRow(ctx, getMembershipByIdQuery, uuid).Scan(
-		&found.ID,
-		&found.UserID,
-		&found.GroupID,
-		&found.Status,
-		&found.Role,
-		&found.CreatedAt,
-		&found.UpdatedAt,
-	); err != nil {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "memberships")
+	defer span.End()
+	found, err := scanMembership(p.db.QueryRow(ctx, getMembershipByIdQuery, uuid))
+	if err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
-	return &found, nil
+	return found, nil
+}
+
+// GetDue returns up to limit memberships whose validity window has started or ended by now, or that end by
+// warnBefore and have not been warned about yet
+func (p *membershipRepository) GetDue(ctx context.Context, now time.Time, warnBefore time.Time, limit int) ([]*models.Membership, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "memberships")
+	defer span.End()
+	rows, err := p.db.Query(ctx, getMembershipsDueQuery, now, warnBefore, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Query")
+	}
+	defer rows.Close()
+	var due []*models.Membership
+	for rows.Next() {
+		membership, err := scanMembership(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		due = append(due, membership)
+	}
+	return due, errors.Wrap(rows.Err(), "rows.Err")
+}
+
+// MarkExpiryWarned records that the expiry warning of a membership was sent at
+func (p *membershipRepository) MarkExpiryWarned(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "memberships")
+	defer span.End()
+	if _, err := p.db.Exec(ctx, markMembershipExpiryWarnedQuery, id, at); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return nil
+}
+
+// CreateAudit ...
+func (p *membershipRepository) CreateAudit(ctx context.Context, audit *models.MembershipAudit) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "membership_audit")
+	defer span.End()
+	if _, err := p.db.Exec(ctx, createMembershipAuditQuery, audit.ID, audit.MembershipID, audit.Action, audit.ActorID, audit.Reason, nullTime(audit.PreviousValidUntil), nullTime(audit.ValidUntil)); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return nil
 }
 
 // GetUserMembershipById ...
 func (p *membershipRepository) GetUserMembershipById(ctx context.Context, uuid uuid.UUID) (*models.UserMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.GetUserMembershipById")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "memberships")
+	defer span.End()
 	var found models.UserMembership
+	var validFrom, validUntil sql.NullTime
 	if err := p.db.QueryRow(ctx, getUserMembershipByIdQuery, uuid).Scan(
 		&found.ID,
 		&found.GroupID,
@@ -161,19 +257,25 @@ func (p *membershipRepository) GetUserMembershipById(ctx context.Context, uuid u
 		&found.Username,
 		&found.Status,
 		&found.Role,
+		&found.RoleID,
+		&found.OrganizationID,
+		&validFrom,
+		&validUntil,
 		&found.CreatedAt,
 		&found.UpdatedAt,
 	); err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
+	found.ValidFrom, found.ValidUntil = validFrom.Time, validUntil.Time
 	return &found, nil
 }
 
 // GetGroupMembershipById ...
 func (p *membershipRepository) GetGroupMembershipById(ctx context.Context, uuid uuid.UUID) (*models.GroupMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.GetGroupMembershipById")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "memberships")
+	defer span.End()
 	var found models.GroupMembership
+	var validFrom, validUntil sql.NullTime
 	if err := p.db.QueryRow(ctx, getGroupMembershipByIdQuery, uuid).Scan(
 		&found.ID,
 		&found.UserID,
@@ -183,19 +285,24 @@ func (p *membershipRepository) GetGroupMembershipById(ctx context.Context, uuid
 		&found.Description,
 		&found.Status,
 		&found.Role,
+		&found.RoleID,
+		&found.OrganizationID,
 		&found.Creator,
+		&validFrom,
+		&validUntil,
 		&found.CreatedAt,
 		&found.UpdatedAt,
 	); err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
+	found.ValidFrom, found.ValidUntil = validFrom.Time, validUntil.Time
 	return &found, nil
 }
 
 // DeleteByID ...
 func (p *membershipRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.DeleteMembershipByID")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "DELETE", "memberships")
+	defer span.End()
 	_, err := p.db.Exec(ctx, deleteMembershipByIdQuery, id)
 	if err != nil {
 		return errors.Wrap(err, "Exec")
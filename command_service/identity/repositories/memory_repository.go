@@ -0,0 +1,762 @@
+package repositories
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// memoryRepository is an in-process Repository mirroring the postgres schema: partial updates skip empty
+// values, foreign keys are enforced and missing rows surface as pgx.ErrNoRows
+type memoryRepository struct {
+	log           logging.Logger
+	cfg           *config.Config
+	mu            sync.RWMutex
+	users         map[uuid.UUID]models.User
+	groups        map[uuid.UUID]models.Group
+	memberships   map[uuid.UUID]models.Membership
+	audits        []models.MembershipAudit
+	blacklist     map[uuid.UUID]models.Blacklist
+	history       map[uuid.UUID][]string
+	reservations  map[string]identifierReservation
+	organizations map[uuid.UUID]models.Organization
+	roles         map[uuid.UUID]models.Role
+}
+
+// identifierReservation holds a login identifier for a user until it expires
+type identifierReservation struct {
+	userID    uuid.UUID
+	expiresAt time.Time
+}
+
+// NewMemoryRepository ...
+func NewMemoryRepository(log logging.Logger, cfg *config.Config) *memoryRepository {
+	roles := make(map[uuid.UUID]models.Role)
+	now := time.Now()
+	for _, r := range enums.BuiltInRoles() {
+		role := models.BuiltInRole(r)
+		role.CreatedAt, role.UpdatedAt = now, now
+		roles[role.ID] = *role
+	}
+	return &memoryRepository{
+		log:           log,
+		cfg:           cfg,
+		users:         make(map[uuid.UUID]models.User),
+		groups:        make(map[uuid.UUID]models.Group),
+		memberships:   make(map[uuid.UUID]models.Membership),
+		blacklist:     make(map[uuid.UUID]models.Blacklist),
+		history:       make(map[uuid.UUID][]string),
+		reservations:  make(map[string]identifierReservation),
+		organizations: make(map[uuid.UUID]models.Organization),
+		roles:         roles,
+	}
+}
+
+func pgError(code string, constraint string, message string) error {
+	return &pgconn.PgError{Severity: "ERROR", Code: code, ConstraintName: constraint, Message: message}
+}
+
+func duplicateKey(constraint string) error {
+	return errors.Wrap(pgError(uniqueViolation, constraint, "duplicate key value violates unique constraint \""+constraint+"\""), "db.QueryRow")
+}
+
+func missingReference(table string, constraint string) error {
+	return pgError(foreignKeyViolation, constraint, "insert or update on table \""+table+"\" violates foreign key constraint \""+constraint+"\"")
+}
+
+func stillReferenced(table string, constraint string) error {
+	return errors.Wrap(pgError(foreignKeyViolation, constraint, "update or delete on table \""+table+"\" violates foreign key constraint \""+constraint+"\""), "Exec")
+}
+
+func noRows() error {
+	return errors.Wrap(pgx.ErrNoRows, "Scan")
+}
+
+func (d *memoryRepository) CreateUser(_ context.Context, user *models.User) (*models.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.users[user.ID]; ok {
+		return nil, duplicateKey("users_pkey")
+	}
+	if err := d.checkIdentifiers(user); err != nil {
+		return nil, err
+	}
+	d.releaseReservations(user.ID)
+	now := time.Now()
+	created := *user
+	created.Status = user.LifecycleStatus()
+	created.CreatedAt, created.UpdatedAt = now, now
+	d.users[created.ID] = created
+	return &created, nil
+}
+
+func (d *memoryRepository) UpdateUser(_ context.Context, user *models.User) (*models.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.users[user.ID]
+	if !ok {
+		return nil, noRows()
+	}
+	if err := d.checkIdentifiers(user); err != nil {
+		return nil, err
+	}
+	d.releaseReservations(user.ID)
+	if user.Email != "" {
+		updated.Email = user.Email
+	}
+	if user.Username != "" {
+		updated.Username = user.Username
+	}
+	updated.UpdatedAt = time.Now()
+	d.users[updated.ID] = updated
+	updated.Password = ""
+	return &updated, nil
+}
+
+func (d *memoryRepository) UpdateUserPassword(_ context.Context, user *models.User) (*models.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.users[user.ID]
+	if !ok {
+		return nil, noRows()
+	}
+	if user.Password != "" {
+		d.history[updated.ID] = append([]string{updated.Password}, d.history[updated.ID]...)
+		updated.Password = user.Password
+	}
+	updated.UpdatedAt = time.Now()
+	d.users[updated.ID] = updated
+	updated.Password = ""
+	return &updated, nil
+}
+
+func (d *memoryRepository) GetUserPasswordHistory(_ context.Context, id uuid.UUID, limit int) ([]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	history := d.history[id]
+	if len(history) > limit {
+		history = history[:limit]
+	}
+	return append([]string(nil), history...), nil
+}
+
+func (d *memoryRepository) UpdateUserStatus(_ context.Context, user *models.User) (*models.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.users[user.ID]
+	if !ok {
+		return nil, noRows()
+	}
+	updated.Status = user.Status
+	updated.Active = user.Active
+	updated.StatusReason = user.StatusReason
+	updated.SuspendedUntil = user.SuspendedUntil
+	if !user.SessionsRevokedAt.IsZero() {
+		updated.SessionsRevokedAt = user.SessionsRevokedAt
+	}
+	updated.UpdatedAt = time.Now()
+	d.users[updated.ID] = updated
+	updated.Password = ""
+	return &updated, nil
+}
+
+func (d *memoryRepository) RehashUserPassword(_ context.Context, user *models.User, currentHash string) (*models.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.users[user.ID]
+	if !ok || updated.Password != currentHash {
+		return nil, noRows()
+	}
+	updated.Password = user.Password
+	updated.UpdatedAt = time.Now()
+	d.users[updated.ID] = updated
+	updated.Password = ""
+	return &updated, nil
+}
+
+func (d *memoryRepository) DeleteUserById(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, g := range d.groups {
+		if g.CreatorID == id {
+			return stillReferenced("users", "user_groups_creator_id_fkey")
+		}
+	}
+	for _, m := range d.memberships {
+		if m.UserID == id {
+			return stillReferenced("users", "memberships_user_id_fkey")
+		}
+	}
+	delete(d.users, id)
+	delete(d.history, id)
+	return nil
+}
+
+func (d *memoryRepository) GetUserById(_ context.Context, id uuid.UUID) (*models.User, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.users[id]
+	if !ok {
+		return nil, noRows()
+	}
+	return &found, nil
+}
+
+func (d *memoryRepository) GetUserByEmail(_ context.Context, email string) (*models.User, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, found := range d.users {
+		if found.Email == email {
+			return &found, nil
+		}
+	}
+	return nil, noRows()
+}
+
+// identifierTaken reports whether a user other than id has the identifier of kind
+func (d *memoryRepository) identifierTaken(kind string, identifier string, id uuid.UUID) bool {
+	for _, found := range d.users {
+		if found.ID == id {
+			continue
+		}
+		if kind == authentication.IdentifierEmail && found.Email == identifier ||
+			kind == authentication.IdentifierUsername && found.Username == identifier {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIdentifiers enforces the unique email and username constraints for user
+func (d *memoryRepository) checkIdentifiers(user *models.User) error {
+	if user.Email != "" && d.identifierTaken(authentication.IdentifierEmail, user.Email, user.ID) {
+		return duplicateKey("users_email_key")
+	}
+	if user.Username != "" && d.identifierTaken(authentication.IdentifierUsername, user.Username, user.ID) {
+		return duplicateKey("users_username_key")
+	}
+	return nil
+}
+
+func (d *memoryRepository) releaseReservations(id uuid.UUID) {
+	for key, reservation := range d.reservations {
+		if reservation.userID == id {
+			delete(d.reservations, key)
+		}
+	}
+}
+
+func (d *memoryRepository) ReserveUserIdentifiers(_ context.Context, id uuid.UUID, email string, username string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	for key, reservation := range d.reservations {
+		if reservation.expiresAt.Before(now) {
+			delete(d.reservations, key)
+		}
+	}
+	identifiers := [][2]string{{authentication.IdentifierEmail, email}, {authentication.IdentifierUsername, username}}
+	var conflicts []string
+	var keys []string
+	for _, identifier := range identifiers {
+		kind, value := identifier[0], identifier[1]
+		if value == "" {
+			continue
+		}
+		key := kind + ":" + value
+		if reservation, ok := d.reservations[key]; ok && reservation.userID != id || d.identifierTaken(kind, value, id) {
+			conflicts = append(conflicts, kind)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(conflicts) > 0 {
+		return &authentication.IdentifierConflictError{Identifiers: conflicts}
+	}
+	for _, key := range keys {
+		d.reservations[key] = identifierReservation{userID: id, expiresAt: now.Add(identifierReservationTTL)}
+	}
+	return nil
+}
+
+func (d *memoryRepository) CountUsers(context.Context) (int, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.users), nil
+}
+
+func (d *memoryRepository) CreateGroup(_ context.Context, group *models.Group) (*models.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.groups[group.ID]; ok {
+		return nil, duplicateKey("user_groups_pkey")
+	}
+	if _, ok := d.users[group.CreatorID]; !ok {
+		return nil, errors.Wrap(missingReference("user_groups", "user_groups_creator_id_fkey"), "db.QueryRow")
+	}
+	now := time.Now()
+	created := *group
+	created.CreatedAt, created.UpdatedAt = now, now
+	d.groups[created.ID] = created
+	return &created, nil
+}
+
+func (d *memoryRepository) UpdateGroup(_ context.Context, group *models.Group) (*models.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.groups[group.ID]
+	if !ok {
+		return nil, noRows()
+	}
+	if group.Name != "" {
+		updated.Name = group.Name
+	}
+	if group.Description != "" {
+		updated.Description = group.Description
+	}
+	updated.UpdatedAt = time.Now()
+	d.groups[updated.ID] = updated
+	return &updated, nil
+}
+
+func (d *memoryRepository) DeleteGroupById(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, m := range d.memberships {
+		if m.GroupID == id {
+			return stillReferenced("user_groups", "memberships_group_id_fkey")
+		}
+	}
+	now := time.Now()
+	for childID, child := range d.groups {
+		if child.ParentID == id {
+			child.ParentID, child.UpdatedAt = uuid.Nil, now
+			d.groups[childID] = child
+		}
+	}
+	delete(d.groups, id)
+	for key, r := range d.roles {
+		if r.GroupID == id && !r.BuiltIn {
+			delete(d.roles, key)
+		}
+	}
+	return nil
+}
+
+func (d *memoryRepository) SetGroupParent(_ context.Context, id uuid.UUID, parentID uuid.UUID) (*models.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.groups[id]
+	if !ok {
+		return nil, noRows()
+	}
+	for ancestorID := parentID; ancestorID != uuid.Nil; ancestorID = d.groups[ancestorID].ParentID {
+		if ancestorID == id {
+			return nil, ErrGroupHierarchyCycle
+		}
+	}
+	updated.ParentID = parentID
+	updated.UpdatedAt = time.Now()
+	d.groups[updated.ID] = updated
+	return &updated, nil
+}
+
+func (d *memoryRepository) GetGroupById(_ context.Context, id uuid.UUID) (*models.Group, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.groups[id]
+	if !ok {
+		return nil, noRows()
+	}
+	return &found, nil
+}
+
+func (d *memoryRepository) CountGroups(context.Context) (int, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.groups), nil
+}
+
+func (d *memoryRepository) CreateMembership(_ context.Context, membership *models.Membership) (*models.Membership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.memberships[membership.ID]; ok {
+		return nil, duplicateKey("memberships_pkey")
+	}
+	if _, ok := d.users[membership.UserID]; !ok {
+		return nil, errors.Wrap(missingReference("memberships", "memberships_user_id_fkey"), "db.QueryRow")
+	}
+	if _, ok := d.groups[membership.GroupID]; !ok {
+		return nil, errors.Wrap(missingReference("memberships", "memberships_group_id_fkey"), "db.QueryRow")
+	}
+	if _, ok := d.roles[membership.RoleID]; !ok {
+		return nil, errors.Wrap(missingReference("memberships", "memberships_role_id_fkey"), "db.QueryRow")
+	}
+	now := time.Now()
+	created := *membership
+	created.ExpiryWarnedAt = time.Time{}
+	created.CreatedAt, created.UpdatedAt = now, now
+	d.memberships[created.ID] = created
+	return &created, nil
+}
+
+func (d *memoryRepository) UpdateMembership(_ context.Context, membership *models.Membership) (*models.Membership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.memberships[membership.ID]
+	if !ok {
+		return nil, noRows()
+	}
+	if membership.Status != 0 {
+		updated.Status, updated.ScheduledStart = membership.Status, false
+	}
+	if membership.Role != 0 {
+		updated.Role = membership.Role
+	}
+	if membership.RoleID != uuid.Nil {
+		if _, ok = d.roles[membership.RoleID]; !ok {
+			return nil, errors.Wrap(missingReference("memberships", "memberships_role_id_fkey"), "Scan")
+		}
+		updated.RoleID = membership.RoleID
+	}
+	if !membership.ValidUntil.IsZero() {
+		updated.ValidUntil, updated.ExpiryWarnedAt = membership.ValidUntil, time.Time{}
+	}
+	updated.UpdatedAt = time.Now()
+	d.memberships[updated.ID] = updated
+	return &updated, nil
+}
+
+func (d *memoryRepository) DeleteMembershipById(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.memberships, id)
+	return nil
+}
+
+func (d *memoryRepository) GetMembershipById(_ context.Context, id uuid.UUID) (*models.Membership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.memberships[id]
+	if !ok {
+		return nil, noRows()
+	}
+	return &found, nil
+}
+
+func (d *memoryRepository) GetMembershipsDue(_ context.Context, now time.Time, warnBefore time.Time, limit int) ([]*models.Membership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var due []*models.Membership
+	for _, m := range d.memberships {
+		m := m
+		bounded := !m.ValidUntil.IsZero()
+		expired := m.Status == enums.ACTIVE && bounded && !m.ValidUntil.After(now)
+		started := m.Status == enums.PENDING && m.ScheduledStart && !m.ValidFrom.IsZero() && !m.ValidFrom.After(now)
+		expiring := m.Status == enums.ACTIVE && bounded && !m.ValidUntil.After(warnBefore) && m.ExpiryWarnedAt.IsZero()
+		if expired || started || expiring {
+			due = append(due, &m)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].ValidUntil.Before(due[j].ValidUntil)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (d *memoryRepository) MarkMembershipExpiryWarned(_ context.Context, id uuid.UUID, at time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if m, ok := d.memberships[id]; ok {
+		m.ExpiryWarnedAt = at
+		d.memberships[id] = m
+	}
+	return nil
+}
+
+func (d *memoryRepository) CreateMembershipAudit(_ context.Context, audit *models.MembershipAudit) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	created := *audit
+	created.CreatedAt = time.Now()
+	d.audits = append(d.audits, created)
+	return nil
+}
+
+func (d *memoryRepository) CountMemberships(context.Context) (int, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.memberships), nil
+}
+
+func (d *memoryRepository) GetUserMembershipById(_ context.Context, id uuid.UUID) (*models.UserMembership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	m, ok := d.memberships[id]
+	if !ok {
+		return nil, noRows()
+	}
+	u, ok := d.users[m.UserID]
+	if !ok {
+		return nil, noRows()
+	}
+	rowID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.Wrap(err, "uuid.NewV4")
+	}
+	return &models.UserMembership{
+		ID:             rowID,
+		GroupID:        m.GroupID,
+		UserID:         m.UserID,
+		MembershipID:   m.ID,
+		Email:          u.Email,
+		Username:       u.Username,
+		Status:         m.Status,
+		Role:           m.Role,
+		RoleID:         m.RoleID,
+		OrganizationID: m.OrganizationID,
+		ValidFrom:      m.ValidFrom,
+		ValidUntil:     m.ValidUntil,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}, nil
+}
+
+func (d *memoryRepository) GetGroupMembershipById(_ context.Context, id uuid.UUID) (*models.GroupMembership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	m, ok := d.memberships[id]
+	if !ok {
+		return nil, noRows()
+	}
+	g, ok := d.groups[m.GroupID]
+	if !ok {
+		return nil, noRows()
+	}
+	rowID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.Wrap(err, "uuid.NewV4")
+	}
+	return &models.GroupMembership{
+		ID:             rowID,
+		UserID:         m.UserID,
+		GroupID:        m.GroupID,
+		MembershipID:   m.ID,
+		Name:           g.Name,
+		Description:    g.Description,
+		Status:         m.Status,
+		Role:           m.Role,
+		RoleID:         m.RoleID,
+		OrganizationID: m.OrganizationID,
+		Creator:        m.UserID == g.CreatorID,
+		ValidFrom:      m.ValidFrom,
+		ValidUntil:     m.ValidUntil,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}, nil
+}
+
+func (d *memoryRepository) CreateOrganization(_ context.Context, organization *models.Organization) (*models.Organization, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.organizations[organization.ID]; ok {
+		return nil, duplicateKey("organizations_pkey")
+	}
+	now := time.Now()
+	created := *organization
+	created.CreatedAt, created.UpdatedAt = now, now
+	d.organizations[created.ID] = created
+	return &created, nil
+}
+
+func (d *memoryRepository) UpdateOrganization(_ context.Context, organization *models.Organization) (*models.Organization, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.organizations[organization.ID]
+	if !ok {
+		return nil, noRows()
+	}
+	if organization.Name != "" {
+		updated.Name = organization.Name
+	}
+	if organization.Description != "" {
+		updated.Description = organization.Description
+	}
+	updated.UpdatedAt = time.Now()
+	d.organizations[updated.ID] = updated
+	return &updated, nil
+}
+
+func (d *memoryRepository) UpdateOrganizationStatus(_ context.Context, organization *models.Organization) (*models.Organization, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.organizations[organization.ID]
+	if !ok {
+		return nil, noRows()
+	}
+	updated.Active = organization.Active
+	if !organization.SessionsRevokedAt.IsZero() {
+		updated.SessionsRevokedAt = organization.SessionsRevokedAt
+	}
+	updated.UpdatedAt = time.Now()
+	d.organizations[updated.ID] = updated
+	return &updated, nil
+}
+
+// DeleteOrganizationById deletes an organization together with its memberships, roles, groups and users
+func (d *memoryRepository) DeleteOrganizationById(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, m := range d.memberships {
+		if m.OrganizationID == id || d.users[m.UserID].OrganizationID == id || d.groups[m.GroupID].OrganizationID == id {
+			delete(d.memberships, key)
+		}
+	}
+	for key, r := range d.roles {
+		if !r.BuiltIn && (r.OrganizationID == id || d.groups[r.GroupID].OrganizationID == id) {
+			delete(d.roles, key)
+		}
+	}
+	for key, g := range d.groups {
+		if g.OrganizationID == id || d.users[g.CreatorID].OrganizationID == id {
+			delete(d.groups, key)
+		}
+	}
+	for key, u := range d.users {
+		if u.OrganizationID == id {
+			d.releaseReservations(key)
+			delete(d.users, key)
+			delete(d.history, key)
+		}
+	}
+	delete(d.organizations, id)
+	return nil
+}
+
+func (d *memoryRepository) GetOrganizationById(_ context.Context, id uuid.UUID) (*models.Organization, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.organizations[id]
+	if !ok {
+		return nil, noRows()
+	}
+	return &found, nil
+}
+
+// roleNameTaken reports whether another role of the same organization and group is named name, mirroring the
+// roles_scope_name_key index
+func (d *memoryRepository) roleNameTaken(role *models.Role, name string) bool {
+	for _, r := range d.roles {
+		if r.ID != role.ID && r.OrganizationID == role.OrganizationID && r.GroupID == role.GroupID && strings.EqualFold(r.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *memoryRepository) CreateRole(_ context.Context, role *models.Role) (*models.Role, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.roles[role.ID]; ok {
+		return nil, duplicateKey("roles_pkey")
+	}
+	if d.roleNameTaken(role, role.Name) {
+		return nil, duplicateKey("roles_scope_name_key")
+	}
+	now := time.Now()
+	created := *role
+	created.BuiltIn = false
+	created.CreatedAt, created.UpdatedAt = now, now
+	d.roles[created.ID] = created
+	return &created, nil
+}
+
+func (d *memoryRepository) UpdateRole(_ context.Context, role *models.Role) (*models.Role, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.roles[role.ID]
+	if !ok || updated.BuiltIn {
+		return nil, noRows()
+	}
+	if role.Name != "" {
+		if d.roleNameTaken(&updated, role.Name) {
+			return nil, duplicateKey("roles_scope_name_key")
+		}
+		updated.Name = role.Name
+	}
+	updated.Description = role.Description
+	updated.Permissions = role.Permissions
+	updated.UpdatedAt = time.Now()
+	d.roles[updated.ID] = updated
+	return &updated, nil
+}
+
+// DeleteRoleById deletes a custom role, moving the memberships that reference it to the built-in MEMBER role
+func (d *memoryRepository) DeleteRoleById(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if r, ok := d.roles[id]; !ok || r.BuiltIn {
+		return nil
+	}
+	now := time.Now()
+	for key, m := range d.memberships {
+		if m.RoleID == id {
+			m.RoleID, m.Role, m.UpdatedAt = uuid.FromStringOrNil(enums.MEMBER.RoleID()), enums.MEMBER, now
+			d.memberships[key] = m
+		}
+	}
+	delete(d.roles, id)
+	return nil
+}
+
+func (d *memoryRepository) GetRoleById(_ context.Context, id uuid.UUID) (*models.Role, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.roles[id]
+	if !ok {
+		return nil, noRows()
+	}
+	return &found, nil
+}
+
+func (d *memoryRepository) BlacklistToken(_ context.Context, blacklist *models.Blacklist) (*models.Blacklist, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.blacklist[blacklist.ID]; ok {
+		return nil, duplicateKey("blacklists_pkey")
+	}
+	created := *blacklist
+	created.CreatedAt = time.Now()
+	d.blacklist[created.ID] = created
+	return &created, nil
+}
+
+func (d *memoryRepository) CheckBlacklist(_ context.Context, accessToken string) (*models.Blacklist, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, b := range d.blacklist {
+		if b.AccessToken == accessToken {
+			return &b, nil
+		}
+	}
+	return nil, noRows()
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+const (
+	createOrganizationQuery = `INSERT INTO organizations (id, org_name, description, active, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, now(), now()) RETURNING id, org_name, description, active, sessions_revoked_at, created_at, updated_at`
+
+	updateOrganizationQuery = `UPDATE organizations p SET
+                      org_name=COALESCE(NULLIF($2, ''), org_name),
+                      description=COALESCE(NULLIF($3, ''), description),
+                      updated_at = now()
+                      WHERE id=$1
+                      RETURNING id, org_name, description, active, sessions_revoked_at, created_at, updated_at`
+
+	updateOrganizationStatusQuery = `UPDATE organizations p SET
+                      active=$2,
+                      sessions_revoked_at=COALESCE($3, sessions_revoked_at),
+                      updated_at = now()
+                      WHERE id=$1
+                      RETURNING id, org_name, description, active, sessions_revoked_at, created_at, updated_at`
+
+	getOrganizationByIdQuery = `SELECT p.id, p.org_name AS name, p.description, p.active, p.sessions_revoked_at, p.created_at, p.updated_at
+	FROM organizations p WHERE p.id = $1`
+
+	deleteOrganizationMembershipsQuery = `DELETE FROM memberships p WHERE p.organization_id = $1
+	OR p.user_id IN (SELECT u.id FROM users u WHERE u.organization_id = $1)
+	OR p.group_id IN (SELECT g.id FROM user_groups g WHERE g.organization_id = $1)`
+
+	deleteOrganizationGroupsQuery = `DELETE FROM user_groups p WHERE p.organization_id = $1
+	OR p.creator_id IN (SELECT u.id FROM users u WHERE u.organization_id = $1)`
+
+	deleteOrganizationReservationsQuery = `DELETE FROM identifier_reservations p
+	WHERE p.user_id IN (SELECT u.id FROM users u WHERE u.organization_id = $1)`
+
+	deleteOrganizationUsersQuery = `DELETE FROM users WHERE organization_id = $1`
+
+	deleteOrganizationByIdQuery = `DELETE FROM organizations WHERE id = $1`
+)
+
+type organizationRepository struct {
+	log logging.Logger
+	cfg *config.Config
+	db  *pgxpool.Pool
+}
+
+// NewOrganizationRepository ...
+func NewOrganizationRepository(log logging.Logger, cfg *config.Config, db *pgxpool.Pool) *organizationRepository {
+	return &organizationRepository{
+		log: log,
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+// scanOrganization scans an organizations row, whose sessions_revoked_at stays NULL until the organization is suspended
+func scanOrganization(row pgx.Row) (*models.Organization, error) {
+	var found models.Organization
+	var sessionsRevokedAt sql.NullTime
+	if err := row.Scan(
+		&found.ID,
+		&found.Name,
+		&found.Description,
+		&found.Active,
+		&sessionsRevokedAt,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	found.SessionsRevokedAt = sessionsRevokedAt.Time
+	return &found, nil
+}
+
+// Create ...
+func (p *organizationRepository) Create(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "organizations")
+	defer span.End()
+	created, err := scanOrganization(p.db.QueryRow(ctx, createOrganizationQuery, &organization.ID, &organization.Name, &organization.Description, organization.Active))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	return created, nil
+}
+
+// Update ...
+func (p *organizationRepository) Update(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "organizations")
+	defer span.End()
+	updated, err := scanOrganization(p.db.QueryRow(ctx, updateOrganizationQuery, &organization.ID, &organization.Name, &organization.Description))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return updated, nil
+}
+
+// UpdateStatus sets whether an organization is Active, recording SessionsRevokedAt when set
+func (p *organizationRepository) UpdateStatus(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "organizations")
+	defer span.End()
+	updated, err := scanOrganization(p.db.QueryRow(ctx, updateOrganizationStatusQuery, &organization.ID, organization.Active, nullTime(organization.SessionsRevokedAt)))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return updated, nil
+}
+
+// GetById ...
+func (p *organizationRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "organizations")
+	defer span.End()
+	found, err := scanOrganization(p.db.QueryRow(ctx, getOrganizationByIdQuery, uuid))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return found, nil
+}
+
+// DeleteByID deletes an organization together with its memberships, roles, groups and users in one transaction
+func (p *organizationRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "DELETE", "organizations")
+	defer span.End()
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Begin")
+	}
+	defer tx.Rollback(ctx) // nolint: errCheck
+	for _, query := range []string{
+		deleteOrganizationMembershipsQuery,
+		deleteOrganizationRolesQuery,
+		deleteOrganizationGroupsQuery,
+		deleteOrganizationReservationsQuery,
+		deleteOrganizationUsersQuery,
+		deleteOrganizationByIdQuery,
+	} {
+		if _, err = tx.Exec(ctx, query, id); err != nil {
+			return errors.Wrap(err, "Exec")
+		}
+	}
+	return errors.Wrap(tx.Commit(ctx), "Commit")
+}
@@ -7,13 +7,17 @@ import (
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/gofrs/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
 )
 
 type repository struct {
-	blacklist   *blacklistRepository
-	users       *userRepository
-	groups      *groupRepository
-	memberships *membershipRepository
+	blacklist     *blacklistRepository
+	users         *userRepository
+	groups        *groupRepository
+	memberships   *membershipRepository
+	reservations  *reservationRepository
+	organizations *organizationRepository
+	roles         *roleRepository
 }
 
 // NewRepository ...
@@ -22,11 +26,17 @@ func NewRepository(log logging.Logger, cfg *config.Config, db *pgxpool.Pool) *re
 	g := NewGroupRepository(log, cfg, db)
 	m := NewMembershipRepository(log, cfg, db)
 	b := NewBlacklistRepository(log, cfg, db)
+	r := NewReservationRepository(log, cfg, db)
+	o := NewOrganizationRepository(log, cfg, db)
+	ro := NewRoleRepository(log, cfg, db)
 	return &repository{
-		blacklist:   b,
-		users:       u,
-		groups:      g,
-		memberships: m,
+		blacklist:     b,
+		users:         u,
+		groups:        g,
+		memberships:   m,
+		reservations:  r,
+		organizations: o,
+		roles:         ro,
 	}
 }
 
@@ -42,6 +52,18 @@ func (d *repository) UpdateUserPassword(ctx context.Context, user *models.User)
 	return d.users.UpdatePassword(ctx, user)
 }
 
+func (d *repository) UpdateUserStatus(ctx context.Context, user *models.User) (*models.User, error) {
+	return d.users.UpdateStatus(ctx, user)
+}
+
+func (d *repository) RehashUserPassword(ctx context.Context, user *models.User, currentHash string) (*models.User, error) {
+	return d.users.RehashPassword(ctx, user, currentHash)
+}
+
+func (d *repository) GetUserPasswordHistory(ctx context.Context, id uuid.UUID, limit int) ([]string, error) {
+	return d.users.GetPasswordHistory(ctx, id, limit)
+}
+
 func (d *repository) DeleteUserById(ctx context.Context, id uuid.UUID) error {
 	return d.users.DeleteByID(ctx, id)
 }
@@ -50,6 +72,14 @@ func (d *repository) GetUserById(ctx context.Context, id uuid.UUID) (*models.Use
 	return d.users.GetById(ctx, id)
 }
 
+func (d *repository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return d.users.GetByEmail(ctx, email)
+}
+
+func (d *repository) ReserveUserIdentifiers(ctx context.Context, id uuid.UUID, email string, username string) error {
+	return d.reservations.Reserve(ctx, id, email, username)
+}
+
 func (d *repository) CountUsers(ctx context.Context) (int, error) {
 	return d.users.Count(ctx)
 }
@@ -66,6 +96,10 @@ func (d *repository) DeleteGroupById(ctx context.Context, id uuid.UUID) error {
 	return d.groups.DeleteByID(ctx, id)
 }
 
+func (d *repository) SetGroupParent(ctx context.Context, id uuid.UUID, parentID uuid.UUID) (*models.Group, error) {
+	return d.groups.SetParent(ctx, id, parentID)
+}
+
 func (d *repository) GetGroupById(ctx context.Context, id uuid.UUID) (*models.Group, error) {
 	return d.groups.GetById(ctx, id)
 }
@@ -90,6 +124,18 @@ func (d *repository) GetMembershipById(ctx context.Context, id uuid.UUID) (*mode
 	return d.memberships.GetById(ctx, id)
 }
 
+func (d *repository) GetMembershipsDue(ctx context.Context, now time.Time, warnBefore time.Time, limit int) ([]*models.Membership, error) {
+	return d.memberships.GetDue(ctx, now, warnBefore, limit)
+}
+
+func (d *repository) MarkMembershipExpiryWarned(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return d.memberships.MarkExpiryWarned(ctx, id, at)
+}
+
+func (d *repository) CreateMembershipAudit(ctx context.Context, audit *models.MembershipAudit) error {
+	return d.memberships.CreateAudit(ctx, audit)
+}
+
 func (d *repository) CountMemberships(ctx context.Context) (int, error) {
 	return d.memberships.Count(ctx)
 }
@@ -102,6 +148,42 @@ func (d *repository) GetGroupMembershipById(ctx context.Context, id uuid.UUID) (
 	return d.memberships.GetGroupMembershipById(ctx, id)
 }
 
+func (d *repository) CreateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	return d.organizations.Create(ctx, organization)
+}
+
+func (d *repository) UpdateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	return d.organizations.Update(ctx, organization)
+}
+
+func (d *repository) UpdateOrganizationStatus(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	return d.organizations.UpdateStatus(ctx, organization)
+}
+
+func (d *repository) DeleteOrganizationById(ctx context.Context, id uuid.UUID) error {
+	return d.organizations.DeleteByID(ctx, id)
+}
+
+func (d *repository) GetOrganizationById(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	return d.organizations.GetById(ctx, id)
+}
+
+func (d *repository) CreateRole(ctx context.Context, role *models.Role) (*models.Role, error) {
+	return d.roles.Create(ctx, role)
+}
+
+func (d *repository) UpdateRole(ctx context.Context, role *models.Role) (*models.Role, error) {
+	return d.roles.Update(ctx, role)
+}
+
+func (d *repository) DeleteRoleById(ctx context.Context, id uuid.UUID) error {
+	return d.roles.DeleteByID(ctx, id)
+}
+
+func (d *repository) GetRoleById(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+	return d.roles.GetById(ctx, id)
+}
+
 func (d *repository) BlacklistToken(ctx context.Context, blacklist *models.Blacklist) (*models.Blacklist, error) {
 	return d.blacklist.Create(ctx, blacklist)
 }
@@ -115,10 +197,12 @@ type Repository interface {
 	UpdateUser(ctx context.Context, user *models.User) (*models.User, error)
 	DeleteUserById(ctx context.Context, id uuid.UUID) error
 	GetUserById(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	CountUsers(ctx context.Context) (int, error)
 	CreateGroup(ctx context.Context, group *models.Group) (*models.Group, error)
 	UpdateGroup(ctx context.Context, group *models.Group) (*models.Group, error)
 	DeleteGroupById(ctx context.Context, id uuid.UUID) error
+	SetGroupParent(ctx context.Context, id uuid.UUID, parentID uuid.UUID) (*models.Group, error)
 	GetGroupById(ctx context.Context, id uuid.UUID) (*models.Group, error)
 	CountGroups(ctx context.Context) (int, error)
 	CreateMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error)
@@ -128,7 +212,23 @@ type Repository interface {
 	CountMemberships(ctx context.Context) (int, error)
 	GetUserMembershipById(ctx context.Context, id uuid.UUID) (*models.UserMembership, error)
 	GetGroupMembershipById(ctx context.Context, id uuid.UUID) (*models.GroupMembership, error)
+	GetMembershipsDue(ctx context.Context, now time.Time, warnBefore time.Time, limit int) ([]*models.Membership, error)
+	MarkMembershipExpiryWarned(ctx context.Context, id uuid.UUID, at time.Time) error
+	CreateMembershipAudit(ctx context.Context, audit *models.MembershipAudit) error
 	BlacklistToken(ctx context.Context, blacklist *models.Blacklist) (*models.Blacklist, error)
 	CheckBlacklist(ctx context.Context, accessToken string) (*models.Blacklist, error)
 	UpdateUserPassword(ctx context.Context, user *models.User) (*models.User, error)
+	RehashUserPassword(ctx context.Context, user *models.User, currentHash string) (*models.User, error)
+	UpdateUserStatus(ctx context.Context, user *models.User) (*models.User, error)
+	GetUserPasswordHistory(ctx context.Context, id uuid.UUID, limit int) ([]string, error)
+	ReserveUserIdentifiers(ctx context.Context, id uuid.UUID, email string, username string) error
+	CreateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error)
+	UpdateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error)
+	UpdateOrganizationStatus(ctx context.Context, organization *models.Organization) (*models.Organization, error)
+	DeleteOrganizationById(ctx context.Context, id uuid.UUID) error
+	GetOrganizationById(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	CreateRole(ctx context.Context, role *models.Role) (*models.Role, error)
+	UpdateRole(ctx context.Context, role *models.Role) (*models.Role, error)
+	DeleteRoleById(ctx context.Context, id uuid.UUID) error
+	GetRoleById(ctx context.Context, id uuid.UUID) (*models.Role, error)
 }
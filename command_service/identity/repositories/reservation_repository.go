@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"time"
+)
+
+// identifierReservationTTL bounds how long a reservation holds an identifier for a user create or update command
+// that is never applied
+const identifierReservationTTL = 15 * time.Minute
+
+const (
+	deleteExpiredReservationsQuery = `DELETE FROM identifier_reservations WHERE expires_at < now()`
+
+	reserveIdentifierQuery = `INSERT INTO identifier_reservations (kind, identifier, user_id, expires_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (kind, identifier) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	WHERE identifier_reservations.user_id = EXCLUDED.user_id
+	RETURNING user_id`
+
+	emailTakenQuery = `SELECT EXISTS (SELECT 1 FROM users p WHERE p.email = $2 AND p.id <> $1)`
+
+	usernameTakenQuery = `SELECT EXISTS (SELECT 1 FROM users p WHERE p.username = $2 AND p.id <> $1)`
+)
+
+type reservationRepository struct {
+	log logging.Logger
+	cfg *config.Config
+	db  *pgxpool.Pool
+}
+
+// NewReservationRepository ...
+func NewReservationRepository(log logging.Logger, cfg *config.Config, db *pgxpool.Pool) *reservationRepository {
+	return &reservationRepository{
+		log: log,
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+// Reserve holds the given identifiers for the user with id until it is created or updated with them, returning an
+// *authentication.IdentifierConflictError when another user holds or reserved any of them.
+// The reservations are inserted before the users are checked: a concurrent create releasing a conflicting reservation
+// blocks the insert until it commits, so its user is always visible to the check.
+func (p *reservationRepository) Reserve(ctx context.Context, id uuid.UUID, email string, username string) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "identifier_reservations")
+	defer span.End()
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Begin")
+	}
+	defer tx.Rollback(ctx) // nolint: errCheck
+	if _, err = tx.Exec(ctx, deleteExpiredReservationsQuery); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	identifiers := []struct {
+		kind       string
+		identifier string
+		takenQuery string
+	}{
+		{authentication.IdentifierEmail, email, emailTakenQuery},
+		{authentication.IdentifierUsername, username, usernameTakenQuery},
+	}
+	expiresAt := time.Now().Add(identifierReservationTTL)
+	var conflicts []string
+	for _, identifier := range identifiers {
+		if identifier.identifier == "" {
+			continue
+		}
+		var holder uuid.UUID
+		err = tx.QueryRow(ctx, reserveIdentifierQuery, identifier.kind, identifier.identifier, id, expiresAt).Scan(&holder)
+		if errors.Is(err, pgx.ErrNoRows) {
+			conflicts = append(conflicts, identifier.kind)
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "Scan")
+		}
+		var taken bool
+		if err = tx.QueryRow(ctx, identifier.takenQuery, id, identifier.identifier).Scan(&taken); err != nil {
+			return errors.Wrap(err, "Scan")
+		}
+		if taken {
+			conflicts = append(conflicts, identifier.kind)
+		}
+	}
+	if len(conflicts) > 0 {
+		return &authentication.IdentifierConflictError{Identifiers: conflicts}
+	}
+	return errors.Wrap(tx.Commit(ctx), "Commit")
+}
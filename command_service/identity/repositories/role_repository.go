@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+const (
+	createRoleQuery = `INSERT INTO roles (id, role_name, description, permissions, group_id, organization_id, built_in, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, FALSE, now(), now())
+	RETURNING id, role_name, description, permissions, group_id, organization_id, built_in, created_at, updated_at`
+
+	updateRoleQuery = `UPDATE roles p SET
+                      role_name=COALESCE(NULLIF($2, ''), role_name),
+                      description=$3,
+                      permissions=$4,
+                      updated_at = now()
+                      WHERE id=$1 AND NOT built_in
+                      RETURNING id, role_name, description, permissions, group_id, organization_id, built_in, created_at, updated_at`
+
+	getRoleByIdQuery = `SELECT p.id, p.role_name AS name, p.description, p.permissions, p.group_id, p.organization_id, p.built_in, p.created_at, p.updated_at
+	FROM roles p WHERE p.id = $1`
+
+	reassignRoleMembershipsQuery = `UPDATE memberships SET role_id = $2, member_role = $3, updated_at = now() WHERE role_id = $1`
+
+	deleteRoleByIdQuery = `DELETE FROM roles WHERE id = $1 AND NOT built_in`
+
+	deleteGroupRolesQuery = `DELETE FROM roles WHERE group_id = $1 AND NOT built_in`
+
+	deleteOrganizationRolesQuery = `DELETE FROM roles p WHERE NOT p.built_in AND (p.organization_id = $1
+	OR p.group_id IN (SELECT g.id FROM user_groups g WHERE g.organization_id = $1))`
+)
+
+type roleRepository struct {
+	log logging.Logger
+	cfg *config.Config
+	db  *pgxpool.Pool
+}
+
+// NewRoleRepository ...
+func NewRoleRepository(log logging.Logger, cfg *config.Config, db *pgxpool.Pool) *roleRepository {
+	return &roleRepository{
+		log: log,
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func scanRole(row pgx.Row) (*models.Role, error) {
+	var found models.Role
+	if err := row.Scan(
+		&found.ID,
+		&found.Name,
+		&found.Description,
+		&found.Permissions,
+		&found.GroupID,
+		&found.OrganizationID,
+		&found.BuiltIn,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+// Create ...
+func (p *roleRepository) Create(ctx context.Context, role *models.Role) (*models.Role, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "roles")
+	defer span.End()
+	created, err := scanRole(p.db.QueryRow(ctx, createRoleQuery, &role.ID, &role.Name, &role.Description, role.Permissions, &role.GroupID, &role.OrganizationID))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	return created, nil
+}
+
+// Update replaces the name, description and permissions of a custom role
+func (p *roleRepository) Update(ctx context.Context, role *models.Role) (*models.Role, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "roles")
+	defer span.End()
+	updated, err := scanRole(p.db.QueryRow(ctx, updateRoleQuery, &role.ID, &role.Name, &role.Description, role.Permissions))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return updated, nil
+}
+
+// GetById ...
+func (p *roleRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.Role, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "roles")
+	defer span.End()
+	found, err := scanRole(p.db.QueryRow(ctx, getRoleByIdQuery, uuid))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return found, nil
+}
+
+// DeleteByID deletes a custom role, moving the memberships that reference it to the built-in MEMBER role in one
+// transaction
+func (p *roleRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "DELETE", "roles")
+	defer span.End()
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Begin")
+	}
+	defer tx.Rollback(ctx) // nolint: errCheck
+	if _, err = tx.Exec(ctx, reassignRoleMembershipsQuery, id, enums.MEMBER.RoleID(), enums.MEMBER); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	if _, err = tx.Exec(ctx, deleteRoleByIdQuery, id); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return errors.Wrap(tx.Commit(ctx), "Commit")
+}
@@ -0,0 +1,1232 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"strings"
+	"time"
+)
+
+const (
+	sqliteSchema = `
+CREATE TABLE IF NOT EXISTS users
+(
+    id          TEXT PRIMARY KEY,
+    username    TEXT      NOT NULL CHECK ( username <> '' ),
+    email       TEXT      NOT NULL CHECK ( email <> '' ),
+    password    TEXT      NOT NULL CHECK ( password <> '' ),
+    root        BOOLEAN   NOT NULL,
+    active      BOOLEAN   NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL,
+    status              INTEGER   NOT NULL DEFAULT 2,
+    status_reason       TEXT      NOT NULL DEFAULT '',
+    suspended_until     TIMESTAMP,
+    sessions_revoked_at TIMESTAMP,
+    organization_id     TEXT      NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    org_admin           BOOLEAN   NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS user_groups
+(
+    id          TEXT PRIMARY KEY,
+    group_name  TEXT      NOT NULL CHECK ( group_name <> '' ),
+    description TEXT      NOT NULL CHECK ( description <> '' ),
+    creator_id  TEXT      NOT NULL REFERENCES users (id),
+    active      BOOLEAN   NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL,
+    organization_id TEXT  NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    parent_id   TEXT      NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'
+);
+
+CREATE TABLE IF NOT EXISTS memberships
+(
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT      NOT NULL REFERENCES users (id),
+    group_id    TEXT      NOT NULL REFERENCES user_groups (id),
+    status      INTEGER   NOT NULL,
+    member_role INTEGER   NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL,
+    organization_id TEXT  NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    role_id     TEXT      NOT NULL DEFAULT '00000000-0000-4000-8000-000000000001',
+    valid_from       TIMESTAMP,
+    valid_until      TIMESTAMP,
+    expiry_warned_at TIMESTAMP,
+    scheduled_start  BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS membership_audit
+(
+    id                   TEXT PRIMARY KEY,
+    membership_id        TEXT      NOT NULL,
+    action               TEXT      NOT NULL CHECK ( action <> '' ),
+    actor_id             TEXT      NOT NULL,
+    reason               TEXT      NOT NULL DEFAULT '',
+    previous_valid_until TIMESTAMP,
+    valid_until          TIMESTAMP,
+    created_at           TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS membership_audit_membership_id_idx ON membership_audit (membership_id, created_at);
+
+CREATE TABLE IF NOT EXISTS blacklists
+(
+    id           TEXT PRIMARY KEY,
+    access_token TEXT      NOT NULL CHECK ( access_token <> '' ),
+    created_at   TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS blacklists_access_token_idx ON blacklists (access_token);
+
+CREATE TABLE IF NOT EXISTS password_history
+(
+    user_id     TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    password    TEXT      NOT NULL CHECK ( password <> '' ),
+    created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS password_history_user_id_idx ON password_history (user_id, created_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email COLLATE NOCASE);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users (username COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS identifier_reservations
+(
+    kind        TEXT      NOT NULL,
+    identifier  TEXT      NOT NULL COLLATE NOCASE CHECK ( identifier <> '' ),
+    user_id     TEXT      NOT NULL,
+    expires_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (kind, identifier)
+);
+
+CREATE INDEX IF NOT EXISTS identifier_reservations_user_id_idx ON identifier_reservations (user_id);
+
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id                  TEXT PRIMARY KEY,
+    org_name            TEXT      NOT NULL CHECK ( org_name <> '' ),
+    description         TEXT      NOT NULL DEFAULT '',
+    active              BOOLEAN   NOT NULL,
+    sessions_revoked_at TIMESTAMP,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS roles
+(
+    id              TEXT PRIMARY KEY,
+    role_name       TEXT      NOT NULL CHECK ( role_name <> '' ),
+    description     TEXT      NOT NULL DEFAULT '',
+    permissions     TEXT      NOT NULL DEFAULT '',
+    group_id        TEXT      NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    organization_id TEXT      NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    built_in        BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS roles_scope_name_key ON roles (organization_id, group_id, role_name COLLATE NOCASE);
+
+CREATE INDEX IF NOT EXISTS roles_group_id_idx ON roles (group_id);`
+
+	// sqliteOrganizationIndexes are created once Migrate added the organization_id columns to older tables
+	sqliteOrganizationIndexes = `
+CREATE INDEX IF NOT EXISTS users_organization_id_idx ON users (organization_id);
+
+CREATE INDEX IF NOT EXISTS user_groups_organization_id_idx ON user_groups (organization_id);
+
+CREATE INDEX IF NOT EXISTS user_groups_parent_id_idx ON user_groups (parent_id);
+
+CREATE INDEX IF NOT EXISTS memberships_organization_id_idx ON memberships (organization_id);
+
+CREATE INDEX IF NOT EXISTS memberships_valid_until_idx ON memberships (valid_until);
+
+CREATE INDEX IF NOT EXISTS memberships_role_id_idx ON memberships (role_id);`
+
+	sqliteReleaseReservationsQuery = `DELETE FROM identifier_reservations WHERE user_id = $1`
+
+	sqliteDeleteExpiredReservationsQuery = `DELETE FROM identifier_reservations WHERE expires_at < $1`
+
+	sqliteReserveIdentifierQuery = `INSERT INTO identifier_reservations (kind, identifier, user_id, expires_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (kind, identifier) DO UPDATE SET expires_at = excluded.expires_at
+	WHERE identifier_reservations.user_id = excluded.user_id
+	RETURNING user_id`
+
+	sqliteEmailTakenQuery = `SELECT EXISTS (SELECT 1 FROM users WHERE email = $2 COLLATE NOCASE AND id <> $1)`
+
+	sqliteUsernameTakenQuery = `SELECT EXISTS (SELECT 1 FROM users WHERE username = $2 COLLATE NOCASE AND id <> $1)`
+
+	sqliteCreateUserQuery = `INSERT INTO users (id, email, username, password, root, active, status, organization_id, org_admin, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+	RETURNING id, email, username, password, root, active, created_at, updated_at, status, status_reason, suspended_until, sessions_revoked_at,
+	organization_id, org_admin`
+
+	sqliteUpdateUserStatusQuery = `UPDATE users SET
+                      status=$2,
+                      active=$3,
+                      status_reason=$4,
+                      suspended_until=$5,
+                      sessions_revoked_at=COALESCE($6, sessions_revoked_at),
+                      updated_at = $7
+                      WHERE id=$1
+                      RETURNING id, email, username, root, active, created_at, updated_at, status, status_reason, suspended_until, sessions_revoked_at,
+                      organization_id, org_admin`
+
+	sqliteGetUserQuery = `SELECT id, email, username, password, root, active, created_at, updated_at,
+	status, status_reason, suspended_until, sessions_revoked_at, organization_id, org_admin FROM users`
+
+	sqliteUpdateUserQuery = `UPDATE users SET
+                      email=COALESCE(NULLIF($2, ''), email),
+                      username=COALESCE(NULLIF($3, ''), username),
+                      updated_at = $4
+                      WHERE id=$1
+                      RETURNING id, email, username, root, active, created_at, updated_at`
+
+	sqliteUpdateUserPasswordQuery = `UPDATE users SET
+                      password=COALESCE(NULLIF($2, ''), password),
+                      updated_at = $3
+                      WHERE id=$1
+                      RETURNING id, email, username, root, active, created_at, updated_at`
+
+	sqliteRecordPasswordHistoryQuery = `INSERT INTO password_history (user_id, password, created_at)
+	SELECT id, password, $2 FROM users WHERE id=$1`
+
+	sqliteGetPasswordHistoryQuery = `SELECT p.password FROM password_history p WHERE p.user_id = $1 ORDER BY p.created_at DESC LIMIT $2`
+
+	sqliteRehashUserPasswordQuery = `UPDATE users SET
+                      password=$2,
+                      updated_at = $4
+                      WHERE id=$1 AND password=$3
+                      RETURNING id, email, username, root, active, created_at, updated_at`
+
+	sqliteCreateGroupQuery = `INSERT INTO user_groups (id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8) RETURNING id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at`
+
+	sqliteUpdateGroupQuery = `UPDATE user_groups SET
+                      group_name=COALESCE(NULLIF($2, ''), group_name),
+                      description=COALESCE(NULLIF($3, ''), description),
+                      updated_at = $4
+                      WHERE id=$1
+                      RETURNING id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at`
+
+	sqliteSetGroupParentQuery = `UPDATE user_groups SET parent_id = $2, updated_at = $3 WHERE id = $1
+	RETURNING id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at`
+
+	sqliteGroupHierarchyCycleQuery = `WITH RECURSIVE ancestors (id, parent_id) AS (
+	SELECT id, parent_id FROM user_groups WHERE id = $2
+	UNION
+	SELECT g.id, g.parent_id FROM user_groups g INNER JOIN ancestors a ON g.id = a.parent_id
+	) SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $1)`
+
+	sqliteDetachChildGroupsQuery = `UPDATE user_groups SET parent_id = '00000000-0000-0000-0000-000000000000', updated_at = $2 WHERE parent_id = $1`
+
+	sqliteCreateMembershipQuery = `INSERT INTO memberships (id, user_id, group_id, status, member_role, role_id, organization_id, valid_from, valid_until, scheduled_start, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $11, $10, $10) RETURNING id, user_id, group_id, status, member_role, role_id, organization_id, created_at, updated_at, valid_from, valid_until, expiry_warned_at`
+
+	sqliteUpdateMembershipQuery = `UPDATE memberships SET
+                      status=COALESCE(NULLIF($2, 0), status),
+                      scheduled_start=CASE WHEN $2 = 0 THEN scheduled_start ELSE FALSE END,
+                      member_role=COALESCE(NULLIF($3, 0), member_role),
+                      role_id=COALESCE(NULLIF($4, '00000000-0000-0000-0000-000000000000'), role_id),
+                      expiry_warned_at=CASE WHEN $6 IS NULL THEN expiry_warned_at END,
+                      valid_until=COALESCE($6, valid_until),
+                      updated_at = $5
+                      WHERE id=$1
+                      RETURNING id, user_id, group_id, status, member_role, role_id, organization_id, created_at, updated_at, valid_from, valid_until, expiry_warned_at`
+
+	sqliteGetMembershipByIdQuery = `SELECT id, user_id, group_id, status, member_role, role_id, organization_id, created_at, updated_at,
+	valid_from, valid_until, expiry_warned_at
+	FROM memberships WHERE id = $1`
+
+	sqliteGetMembershipsDueQuery = `SELECT id, user_id, group_id, status, member_role, role_id, organization_id, created_at, updated_at,
+	valid_from, valid_until, expiry_warned_at
+	FROM memberships
+	WHERE (status = 1 AND valid_until <= $1)
+	OR (status = 4 AND scheduled_start AND valid_from <= $1)
+	OR (status = 1 AND valid_until <= $2 AND expiry_warned_at IS NULL)
+	ORDER BY valid_until LIMIT $3`
+
+	sqliteMarkMembershipExpiryWarnedQuery = `UPDATE memberships SET expiry_warned_at = $2 WHERE id = $1`
+
+	sqliteCreateMembershipAuditQuery = `INSERT INTO membership_audit (id, membership_id, action, actor_id, reason, previous_valid_until, valid_until, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	sqliteGetUserMembershipByIdQuery = `SELECT
+    	p.group_id,
+    	p.user_id,
+    	p.id AS membership_id,
+    	u.email,
+    	u.username,
+    	p.status,
+    	p.member_role AS role,
+    	p.role_id,
+    	p.organization_id,
+    	p.valid_from,
+    	p.valid_until,
+    	p.created_at,
+    	p.updated_at
+	FROM memberships p
+	INNER JOIN users u ON p.user_id = u.id
+	WHERE p.id = $1`
+
+	sqliteGetGroupMembershipByIdQuery = `SELECT
+    	p.user_id,
+    	p.group_id,
+    	p.id AS membership_id,
+    	g.group_name AS name,
+    	g.description,
+    	p.status,
+    	p.member_role AS role,
+    	p.role_id,
+    	p.organization_id,
+    	(p.user_id = g.creator_id) AS creator,
+    	p.valid_from,
+    	p.valid_until,
+    	p.created_at,
+    	p.updated_at
+	FROM memberships p
+	INNER JOIN user_groups g ON p.group_id = g.id
+	WHERE p.id = $1`
+
+	sqliteCreateOrganizationQuery = `INSERT INTO organizations (id, org_name, description, active, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $5) RETURNING id, org_name, description, active, sessions_revoked_at, created_at, updated_at`
+
+	sqliteUpdateOrganizationQuery = `UPDATE organizations SET
+                      org_name=COALESCE(NULLIF($2, ''), org_name),
+                      description=COALESCE(NULLIF($3, ''), description),
+                      updated_at = $4
+                      WHERE id=$1
+                      RETURNING id, org_name, description, active, sessions_revoked_at, created_at, updated_at`
+
+	sqliteUpdateOrganizationStatusQuery = `UPDATE organizations SET
+                      active=$2,
+                      sessions_revoked_at=COALESCE($3, sessions_revoked_at),
+                      updated_at = $4
+                      WHERE id=$1
+                      RETURNING id, org_name, description, active, sessions_revoked_at, created_at, updated_at`
+
+	sqliteGetOrganizationByIdQuery = `SELECT id, org_name, description, active, sessions_revoked_at, created_at, updated_at
+	FROM organizations WHERE id = $1`
+
+	sqliteMembershipRoleIdsQuery = `UPDATE memberships SET role_id = CASE member_role
+	WHEN 2 THEN '00000000-0000-4000-8000-000000000002'
+	WHEN 3 THEN '00000000-0000-4000-8000-000000000003'
+	ELSE '00000000-0000-4000-8000-000000000001' END`
+
+	sqliteSeedRoleQuery = `INSERT INTO roles (id, role_name, description, permissions, built_in, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, TRUE, $5, $5) ON CONFLICT (id) DO NOTHING`
+
+	sqliteCreateRoleQuery = `INSERT INTO roles (id, role_name, description, permissions, group_id, organization_id, built_in, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, FALSE, $7, $7)
+	RETURNING id, role_name, description, permissions, group_id, organization_id, built_in, created_at, updated_at`
+
+	sqliteUpdateRoleQuery = `UPDATE roles SET
+                      role_name=COALESCE(NULLIF($2, ''), role_name),
+                      description=$3,
+                      permissions=$4,
+                      updated_at = $5
+                      WHERE id=$1 AND NOT built_in
+                      RETURNING id, role_name, description, permissions, group_id, organization_id, built_in, created_at, updated_at`
+
+	sqliteGetRoleByIdQuery = `SELECT id, role_name, description, permissions, group_id, organization_id, built_in, created_at, updated_at
+	FROM roles WHERE id = $1`
+
+	sqliteReassignRoleMembershipsQuery = `UPDATE memberships SET role_id = $2, member_role = $3, updated_at = $4 WHERE role_id = $1`
+
+	sqliteBlacklistQuery = `INSERT INTO blacklists (id, access_token, created_at)
+	VALUES ($1, $2, $3) RETURNING id, access_token, created_at`
+
+	sqliteCheckBlacklistQuery = `SELECT p.id, p.access_token, p.created_at
+	FROM blacklists p WHERE p.access_token = $1`
+)
+
+// sqliteRepository is a Repository over an embedded sqlite database with the same schema as postgres; ids are
+// stored as text and timestamps are set by the repository since sqlite has no now() with time zones
+type sqliteRepository struct {
+	log logging.Logger
+	cfg *config.Config
+	db  *sql.DB
+}
+
+// NewSqliteRepository ...
+func NewSqliteRepository(log logging.Logger, cfg *config.Config, db *sql.DB) *sqliteRepository {
+	return &sqliteRepository{
+		log: log,
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+// sqliteAddedColumns are the columns added to tables after they were first created
+var sqliteAddedColumns = []struct {
+	table      string
+	name       string
+	definition string
+}{
+	{"users", "status", "INTEGER NOT NULL DEFAULT 2"},
+	{"users", "status_reason", "TEXT NOT NULL DEFAULT ''"},
+	{"users", "suspended_until", "TIMESTAMP"},
+	{"users", "sessions_revoked_at", "TIMESTAMP"},
+	{"users", "organization_id", "TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'"},
+	{"users", "org_admin", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{"user_groups", "organization_id", "TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'"},
+	{"user_groups", "parent_id", "TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'"},
+	{"memberships", "organization_id", "TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'"},
+	{"memberships", "role_id", "TEXT NOT NULL DEFAULT '00000000-0000-4000-8000-000000000001'"},
+	{"memberships", "valid_from", "TIMESTAMP"},
+	{"memberships", "valid_until", "TIMESTAMP"},
+	{"memberships", "expiry_warned_at", "TIMESTAMP"},
+	{"memberships", "scheduled_start", "BOOLEAN NOT NULL DEFAULT FALSE"},
+}
+
+// columns returns the names of the columns table has
+func (d *sqliteRepository) columns(ctx context.Context, table string) (map[string]bool, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT name FROM pragma_table_info($1)`, table)
+	if err != nil {
+		return nil, errors.Wrap(err, "QueryContext")
+	}
+	defer rows.Close() // nolint: errCheck
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		existing[name] = true
+	}
+	return existing, errors.Wrap(rows.Err(), "rows.Err")
+}
+
+// Migrate creates the tables that do not exist yet and adds the columns missing from older tables
+func (d *sqliteRepository) Migrate(ctx context.Context) error {
+	if _, err := d.db.ExecContext(ctx, sqliteSchema); err != nil {
+		return errors.Wrap(err, "ExecContext")
+	}
+	existing := make(map[string]map[string]bool)
+	for _, column := range sqliteAddedColumns {
+		if existing[column.table] == nil {
+			columns, err := d.columns(ctx, column.table)
+			if err != nil {
+				return err
+			}
+			existing[column.table] = columns
+		}
+		if existing[column.table][column.name] {
+			continue
+		}
+		if _, err := d.db.ExecContext(ctx, "ALTER TABLE "+column.table+" ADD COLUMN "+column.name+" "+column.definition); err != nil {
+			return errors.Wrap(err, "ExecContext")
+		}
+		if column.table == "users" && column.name == "status" {
+			if _, err := d.db.ExecContext(ctx, `UPDATE users SET status = CASE WHEN active THEN 2 ELSE 4 END`); err != nil {
+				return errors.Wrap(err, "ExecContext")
+			}
+		}
+		if column.table == "memberships" && column.name == "role_id" {
+			if _, err := d.db.ExecContext(ctx, sqliteMembershipRoleIdsQuery); err != nil {
+				return errors.Wrap(err, "ExecContext")
+			}
+		}
+	}
+	if _, err := d.db.ExecContext(ctx, sqliteOrganizationIndexes); err != nil {
+		return errors.Wrap(err, "ExecContext")
+	}
+	now := time.Now().UTC()
+	for _, r := range enums.BuiltInRoles() {
+		role := models.BuiltInRole(r)
+		if _, err := d.db.ExecContext(ctx, sqliteSeedRoleQuery, role.ID, role.Name, role.Description, strings.Join(role.Permissions, ","), now); err != nil {
+			return errors.Wrap(err, "ExecContext")
+		}
+	}
+	return nil
+}
+
+func (d *sqliteRepository) count(ctx context.Context, table string) (int, error) {
+	var count int
+	if err := d.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, "Scan")
+	}
+	return count, nil
+}
+
+func (d *sqliteRepository) deleteById(ctx context.Context, table string, id uuid.UUID) error {
+	if _, err := d.db.ExecContext(ctx, "DELETE FROM "+table+" WHERE id = $1", id); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return nil
+}
+
+func (d *sqliteRepository) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "users")
+	defer span.End()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "BeginTx")
+	}
+	defer tx.Rollback() // nolint: errCheck
+	if _, err = tx.ExecContext(ctx, sqliteReleaseReservationsQuery, user.ID); err != nil {
+		return nil, errors.Wrap(err, "Exec")
+	}
+	var created models.User
+	var lifecycle userLifecycle
+	if err = tx.QueryRowContext(ctx, sqliteCreateUserQuery, user.ID, user.Email, user.Username, user.Password, user.Root, user.Active, user.LifecycleStatus().EnumIndex(), user.OrganizationID, user.OrgAdmin, time.Now().UTC()).Scan(
+		&created.ID,
+		&created.Email,
+		&created.Username,
+		&created.Password,
+		&created.Root,
+		&created.Active,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&created.OrganizationID,
+		&created.OrgAdmin,
+	); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	lifecycle.apply(&created)
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "Commit")
+	}
+	return &created, nil
+}
+
+func (d *sqliteRepository) UpdateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "users")
+	defer span.End()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "BeginTx")
+	}
+	defer tx.Rollback() // nolint: errCheck
+	if _, err = tx.ExecContext(ctx, sqliteReleaseReservationsQuery, user.ID); err != nil {
+		return nil, errors.Wrap(err, "Exec")
+	}
+	var updated models.User
+	if err = tx.QueryRowContext(ctx, sqliteUpdateUserQuery, user.ID, user.Email, user.Username, time.Now().UTC()).Scan(
+		&updated.ID,
+		&updated.Email,
+		&updated.Username,
+		&updated.Root,
+		&updated.Active,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "Commit")
+	}
+	return &updated, nil
+}
+
+func (d *sqliteRepository) UpdateUserPassword(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "users")
+	defer span.End()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "BeginTx")
+	}
+	defer tx.Rollback() // nolint: errCheck
+	now := time.Now().UTC()
+	if user.Password != "" {
+		if _, err = tx.ExecContext(ctx, sqliteRecordPasswordHistoryQuery, user.ID, now); err != nil {
+			return nil, errors.Wrap(err, "Exec")
+		}
+	}
+	var updated models.User
+	if err = tx.QueryRowContext(ctx, sqliteUpdateUserPasswordQuery, user.ID, user.Password, now).Scan(
+		&updated.ID,
+		&updated.Email,
+		&updated.Username,
+		&updated.Root,
+		&updated.Active,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "Commit")
+	}
+	return &updated, nil
+}
+
+func (d *sqliteRepository) GetUserPasswordHistory(ctx context.Context, id uuid.UUID, limit int) ([]string, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "password_history")
+	defer span.End()
+	rows, err := d.db.QueryContext(ctx, sqliteGetPasswordHistoryQuery, id, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "QueryContext")
+	}
+	defer rows.Close() // nolint: errCheck
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err = rows.Scan(&hash); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, errors.Wrap(rows.Err(), "rows.Err")
+}
+
+func (d *sqliteRepository) UpdateUserStatus(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "users")
+	defer span.End()
+	var updated models.User
+	var lifecycle userLifecycle
+	if err := d.db.QueryRowContext(
+		ctx,
+		sqliteUpdateUserStatusQuery,
+		user.ID,
+		user.Status.EnumIndex(),
+		user.Active,
+		user.StatusReason,
+		nullTime(user.SuspendedUntil),
+		nullTime(user.SessionsRevokedAt),
+		time.Now().UTC(),
+	).Scan(
+		&updated.ID,
+		&updated.Email,
+		&updated.Username,
+		&updated.Root,
+		&updated.Active,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&updated.OrganizationID,
+		&updated.OrgAdmin,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	lifecycle.apply(&updated)
+	return &updated, nil
+}
+
+func (d *sqliteRepository) RehashUserPassword(ctx context.Context, user *models.User, currentHash string) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "users")
+	defer span.End()
+	var updated models.User
+	if err := d.db.QueryRowContext(ctx, sqliteRehashUserPasswordQuery, user.ID, user.Password, currentHash, time.Now().UTC()).Scan(
+		&updated.ID,
+		&updated.Email,
+		&updated.Username,
+		&updated.Root,
+		&updated.Active,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return &updated, nil
+}
+
+func (d *sqliteRepository) DeleteUserById(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "DELETE", "users")
+	defer span.End()
+	return d.deleteById(ctx, "users", id)
+}
+
+func (d *sqliteRepository) GetUserById(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "users")
+	defer span.End()
+	var found models.User
+	var lifecycle userLifecycle
+	if err := d.db.QueryRowContext(ctx, sqliteGetUserQuery+` WHERE id = $1`, id).Scan(
+		&found.ID,
+		&found.Email,
+		&found.Username,
+		&found.Password,
+		&found.Root,
+		&found.Active,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&found.OrganizationID,
+		&found.OrgAdmin,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	lifecycle.apply(&found)
+	return &found, nil
+}
+
+func (d *sqliteRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "users")
+	defer span.End()
+	var found models.User
+	var lifecycle userLifecycle
+	if err := d.db.QueryRowContext(ctx, sqliteGetUserQuery+` WHERE email = $1 COLLATE NOCASE`, email).Scan(
+		&found.ID,
+		&found.Email,
+		&found.Username,
+		&found.Password,
+		&found.Root,
+		&found.Active,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&found.OrganizationID,
+		&found.OrgAdmin,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	lifecycle.apply(&found)
+	return &found, nil
+}
+
+// ReserveUserIdentifiers holds the given identifiers for the user with id, sqlite serializes the transaction with
+// every other writer
+func (d *sqliteRepository) ReserveUserIdentifiers(ctx context.Context, id uuid.UUID, email string, username string) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "identifier_reservations")
+	defer span.End()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "BeginTx")
+	}
+	defer tx.Rollback() // nolint: errCheck
+	now := time.Now().UTC()
+	if _, err = tx.ExecContext(ctx, sqliteDeleteExpiredReservationsQuery, now); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	identifiers := []struct {
+		kind       string
+		identifier string
+		takenQuery string
+	}{
+		{authentication.IdentifierEmail, email, sqliteEmailTakenQuery},
+		{authentication.IdentifierUsername, username, sqliteUsernameTakenQuery},
+	}
+	var conflicts []string
+	for _, identifier := range identifiers {
+		if identifier.identifier == "" {
+			continue
+		}
+		var holder uuid.UUID
+		err = tx.QueryRowContext(ctx, sqliteReserveIdentifierQuery, identifier.kind, identifier.identifier, id, now.Add(identifierReservationTTL)).Scan(&holder)
+		if errors.Is(err, sql.ErrNoRows) {
+			conflicts = append(conflicts, identifier.kind)
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "Scan")
+		}
+		var taken bool
+		if err = tx.QueryRowContext(ctx, identifier.takenQuery, id, identifier.identifier).Scan(&taken); err != nil {
+			return errors.Wrap(err, "Scan")
+		}
+		if taken {
+			conflicts = append(conflicts, identifier.kind)
+		}
+	}
+	if len(conflicts) > 0 {
+		return &authentication.IdentifierConflictError{Identifiers: conflicts}
+	}
+	return errors.Wrap(tx.Commit(), "Commit")
+}
+
+func (d *sqliteRepository) CountUsers(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "users")
+	defer span.End()
+	return d.count(ctx, "users")
+}
+
+func (d *sqliteRepository) CreateGroup(ctx context.Context, group *models.Group) (*models.Group, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "user_groups")
+	defer span.End()
+	var created models.Group
+	if err := d.db.QueryRowContext(ctx, sqliteCreateGroupQuery, group.ID, group.Name, group.Description, group.CreatorID, group.Active, group.OrganizationID, group.ParentID, time.Now().UTC()).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Description,
+		&created.CreatorID,
+		&created.Active,
+		&created.OrganizationID,
+		&created.ParentID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	return &created, nil
+}
+
+func (d *sqliteRepository) UpdateGroup(ctx context.Context, group *models.Group) (*models.Group, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "user_groups")
+	defer span.End()
+	var updated models.Group
+	if err := d.db.QueryRowContext(ctx, sqliteUpdateGroupQuery, group.ID, group.Name, group.Description, time.Now().UTC()).Scan(
+		&updated.ID,
+		&updated.Name,
+		&updated.Description,
+		&updated.CreatorID,
+		&updated.Active,
+		&updated.OrganizationID,
+		&updated.ParentID,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return &updated, nil
+}
+
+// SetGroupParent checks for a cycle and moves the group in one transaction, see groupRepository.SetParent
+func (d *sqliteRepository) SetGroupParent(ctx context.Context, id uuid.UUID, parentID uuid.UUID) (*models.Group, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "user_groups")
+	defer span.End()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "BeginTx")
+	}
+	defer tx.Rollback() // nolint: errCheck
+	if parentID != uuid.Nil {
+		var cycle bool
+		if err = tx.QueryRowContext(ctx, sqliteGroupHierarchyCycleQuery, id, parentID).Scan(&cycle); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		if cycle {
+			return nil, ErrGroupHierarchyCycle
+		}
+	}
+	var updated models.Group
+	if err = tx.QueryRowContext(ctx, sqliteSetGroupParentQuery, id, parentID, time.Now().UTC()).Scan(
+		&updated.ID,
+		&updated.Name,
+		&updated.Description,
+		&updated.CreatorID,
+		&updated.Active,
+		&updated.OrganizationID,
+		&updated.ParentID,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "Commit")
+	}
+	return &updated, nil
+}
+
+func (d *sqliteRepository) DeleteGroupById(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "DELETE", "user_groups")
+	defer span.End()
+	if _, err := d.db.ExecContext(ctx, sqliteDetachChildGroupsQuery, id, time.Now().UTC()); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	if err := d.deleteById(ctx, "user_groups", id); err != nil {
+		return err
+	}
+	if _, err := d.db.ExecContext(ctx, deleteGroupRolesQuery, id); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return nil
+}
+
+func (d *sqliteRepository) GetGroupById(ctx context.Context, id uuid.UUID) (*models.Group, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "user_groups")
+	defer span.End()
+	var found models.Group
+	if err := d.db.QueryRowContext(ctx, `SELECT id, group_name, description, creator_id, active, organization_id, parent_id, created_at, updated_at
+	FROM user_groups WHERE id = $1`, id).Scan(
+		&found.ID,
+		&found.Name,
+		&found.Description,
+		&found.CreatorID,
+		&found.Active,
+		&found.OrganizationID,
+		&found.ParentID,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return &found, nil
+}
+
+func (d *sqliteRepository) CountGroups(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "user_groups")
+	defer span.End()
+	return d.count(ctx, "user_groups")
+}
+
+// scanSqliteMembership scans a memberships row selected with its validity columns
+func scanSqliteMembership(row interface{ Scan(...interface{}) error }) (*models.Membership, error) {
+	var found models.Membership
+	var validity membershipValidity
+	if err := row.Scan(
+		&found.ID,
+		&found.UserID,
+		&found.GroupID,
+		&found.Status,
+		&found.Role,
+		&found.RoleID,
+		&found.OrganizationID,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+		&validity.validFrom,
+		&validity.validUntil,
+		&validity.expiryWarnedAt,
+	); err != nil {
+		return nil, err
+	}
+	validity.apply(&found)
+	return &found, nil
+}
+
+// sqliteTime maps the zero time to NULL and stores other times in UTC, so they compare as text
+func sqliteTime(t time.Time) sql.NullTime {
+	return nullTime(t.UTC())
+}
+
+func (d *sqliteRepository) CreateMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "memberships")
+	defer span.End()
+	created, err := scanSqliteMembership(d.db.QueryRowContext(ctx, sqliteCreateMembershipQuery, membership.ID, membership.UserID, membership.GroupID, membership.Status, membership.Role, membership.RoleID, membership.OrganizationID, sqliteTime(membership.ValidFrom), sqliteTime(membership.ValidUntil), time.Now().UTC(), membership.ScheduledStart))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	return created, nil
+}
+
+func (d *sqliteRepository) UpdateMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "memberships")
+	defer span.End()
+	updated, err := scanSqliteMembership(d.db.QueryRowContext(ctx, sqliteUpdateMembershipQuery, membership.ID, membership.Status, membership.Role, membership.RoleID, time.Now().UTC(), sqliteTime(membership.ValidUntil)))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return updated, nil
+}
+
+func (d *sqliteRepository) DeleteMembershipById(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "DELETE", "memberships")
+	defer span.End()
+	return d.deleteById(ctx, "memberships", id)
+}
+
+func (d *sqliteRepository) GetMembershipById(ctx context.Context, id uuid.UUID) (*models.Membership, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "memberships")
+	defer span.End()
+	found, err := scanSqliteMembership(d.db.QueryRowContext(ctx, sqliteGetMembershipByIdQuery, id))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return found, nil
+}
+
+func (d *sqliteRepository) GetMembershipsDue(ctx context.Context, now time.Time, warnBefore time.Time, limit int) ([]*models.Membership, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "memberships")
+	defer span.End()
+	rows, err := d.db.QueryContext(ctx, sqliteGetMembershipsDueQuery, now.UTC(), warnBefore.UTC(), limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "QueryContext")
+	}
+	defer rows.Close() // nolint: errCheck
+	var due []*models.Membership
+	for rows.Next() {
+		membership, err := scanSqliteMembership(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		due = append(due, membership)
+	}
+	return due, errors.Wrap(rows.Err(), "rows.Err")
+}
+
+func (d *sqliteRepository) MarkMembershipExpiryWarned(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "memberships")
+	defer span.End()
+	if _, err := d.db.ExecContext(ctx, sqliteMarkMembershipExpiryWarnedQuery, id, at.UTC()); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return nil
+}
+
+func (d *sqliteRepository) CreateMembershipAudit(ctx context.Context, audit *models.MembershipAudit) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "membership_audit")
+	defer span.End()
+	if _, err := d.db.ExecContext(ctx, sqliteCreateMembershipAuditQuery, audit.ID, audit.MembershipID, audit.Action, audit.ActorID, audit.Reason, sqliteTime(audit.PreviousValidUntil), sqliteTime(audit.ValidUntil), time.Now().UTC()); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return nil
+}
+
+func (d *sqliteRepository) CountMemberships(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "memberships")
+	defer span.End()
+	return d.count(ctx, "memberships")
+}
+
+func (d *sqliteRepository) GetUserMembershipById(ctx context.Context, id uuid.UUID) (*models.UserMembership, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "memberships")
+	defer span.End()
+	rowID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.Wrap(err, "uuid.NewV4")
+	}
+	found := models.UserMembership{ID: rowID}
+	var validFrom, validUntil sql.NullTime
+	if err = d.db.QueryRowContext(ctx, sqliteGetUserMembershipByIdQuery, id).Scan(
+		&found.GroupID,
+		&found.UserID,
+		&found.MembershipID,
+		&found.Email,
+		&found.Username,
+		&found.Status,
+		&found.Role,
+		&found.RoleID,
+		&found.OrganizationID,
+		&validFrom,
+		&validUntil,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	found.ValidFrom, found.ValidUntil = validFrom.Time, validUntil.Time
+	return &found, nil
+}
+
+func (d *sqliteRepository) GetGroupMembershipById(ctx context.Context, id uuid.UUID) (*models.GroupMembership, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "memberships")
+	defer span.End()
+	rowID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.Wrap(err, "uuid.NewV4")
+	}
+	found := models.GroupMembership{ID: rowID}
+	var validFrom, validUntil sql.NullTime
+	if err = d.db.QueryRowContext(ctx, sqliteGetGroupMembershipByIdQuery, id).Scan(
+		&found.UserID,
+		&found.GroupID,
+		&found.MembershipID,
+		&found.Name,
+		&found.Description,
+		&found.Status,
+		&found.Role,
+		&found.RoleID,
+		&found.OrganizationID,
+		&found.Creator,
+		&validFrom,
+		&validUntil,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	found.ValidFrom, found.ValidUntil = validFrom.Time, validUntil.Time
+	return &found, nil
+}
+
+// scanSqliteOrganization scans an organizations row, whose sessions_revoked_at stays NULL until the organization is
+// suspended
+func scanSqliteOrganization(row *sql.Row) (*models.Organization, error) {
+	var found models.Organization
+	var sessionsRevokedAt sql.NullTime
+	if err := row.Scan(
+		&found.ID,
+		&found.Name,
+		&found.Description,
+		&found.Active,
+		&sessionsRevokedAt,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	found.SessionsRevokedAt = sessionsRevokedAt.Time
+	return &found, nil
+}
+
+func (d *sqliteRepository) CreateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "organizations")
+	defer span.End()
+	created, err := scanSqliteOrganization(d.db.QueryRowContext(ctx, sqliteCreateOrganizationQuery, organization.ID, organization.Name, organization.Description, organization.Active, time.Now().UTC()))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	return created, nil
+}
+
+func (d *sqliteRepository) UpdateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "organizations")
+	defer span.End()
+	updated, err := scanSqliteOrganization(d.db.QueryRowContext(ctx, sqliteUpdateOrganizationQuery, organization.ID, organization.Name, organization.Description, time.Now().UTC()))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return updated, nil
+}
+
+func (d *sqliteRepository) UpdateOrganizationStatus(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "organizations")
+	defer span.End()
+	updated, err := scanSqliteOrganization(d.db.QueryRowContext(ctx, sqliteUpdateOrganizationStatusQuery, organization.ID, organization.Active, nullTime(organization.SessionsRevokedAt), time.Now().UTC()))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return updated, nil
+}
+
+// DeleteOrganizationById deletes an organization together with its memberships, roles, groups and users in one transaction
+func (d *sqliteRepository) DeleteOrganizationById(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "DELETE", "organizations")
+	defer span.End()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "BeginTx")
+	}
+	defer tx.Rollback() // nolint: errCheck
+	for _, query := range []string{
+		deleteOrganizationMembershipsQuery,
+		deleteOrganizationRolesQuery,
+		deleteOrganizationGroupsQuery,
+		deleteOrganizationReservationsQuery,
+		deleteOrganizationUsersQuery,
+		deleteOrganizationByIdQuery,
+	} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return errors.Wrap(err, "Exec")
+		}
+	}
+	return errors.Wrap(tx.Commit(), "Commit")
+}
+
+func (d *sqliteRepository) GetOrganizationById(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "organizations")
+	defer span.End()
+	found, err := scanSqliteOrganization(d.db.QueryRowContext(ctx, sqliteGetOrganizationByIdQuery, id))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return found, nil
+}
+
+// scanSqliteRole scans a roles row, whose permissions are stored comma separated
+func scanSqliteRole(row *sql.Row) (*models.Role, error) {
+	var found models.Role
+	var permissions string
+	if err := row.Scan(
+		&found.ID,
+		&found.Name,
+		&found.Description,
+		&permissions,
+		&found.GroupID,
+		&found.OrganizationID,
+		&found.BuiltIn,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	found.Permissions = make([]string, 0)
+	if permissions != "" {
+		found.Permissions = strings.Split(permissions, ",")
+	}
+	return &found, nil
+}
+
+func (d *sqliteRepository) CreateRole(ctx context.Context, role *models.Role) (*models.Role, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "roles")
+	defer span.End()
+	created, err := scanSqliteRole(d.db.QueryRowContext(ctx, sqliteCreateRoleQuery, role.ID, role.Name, role.Description, strings.Join(role.Permissions, ","), role.GroupID, role.OrganizationID, time.Now().UTC()))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	return created, nil
+}
+
+func (d *sqliteRepository) UpdateRole(ctx context.Context, role *models.Role) (*models.Role, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "UPDATE", "roles")
+	defer span.End()
+	updated, err := scanSqliteRole(d.db.QueryRowContext(ctx, sqliteUpdateRoleQuery, role.ID, role.Name, role.Description, strings.Join(role.Permissions, ","), time.Now().UTC()))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return updated, nil
+}
+
+// DeleteRoleById deletes a custom role, moving the memberships that reference it to the built-in MEMBER role in one
+// transaction
+func (d *sqliteRepository) DeleteRoleById(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "DELETE", "roles")
+	defer span.End()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "BeginTx")
+	}
+	defer tx.Rollback() // nolint: errCheck
+	if _, err = tx.ExecContext(ctx, sqliteReassignRoleMembershipsQuery, id, enums.MEMBER.RoleID(), enums.MEMBER, time.Now().UTC()); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	if _, err = tx.ExecContext(ctx, deleteRoleByIdQuery, id); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+	return errors.Wrap(tx.Commit(), "Commit")
+}
+
+func (d *sqliteRepository) GetRoleById(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "roles")
+	defer span.End()
+	found, err := scanSqliteRole(d.db.QueryRowContext(ctx, sqliteGetRoleByIdQuery, id))
+	if err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return found, nil
+}
+
+func (d *sqliteRepository) BlacklistToken(ctx context.Context, blacklist *models.Blacklist) (*models.Blacklist, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "INSERT", "blacklists")
+	defer span.End()
+	var created models.Blacklist
+	if err := d.db.QueryRowContext(ctx, sqliteBlacklistQuery, blacklist.ID, blacklist.AccessToken, time.Now().UTC()).Scan(
+		&created.ID,
+		&created.AccessToken,
+		&created.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+	return &created, nil
+}
+
+func (d *sqliteRepository) CheckBlacklist(ctx context.Context, accessToken string) (*models.Blacklist, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemSqlite, "SELECT", "blacklists")
+	defer span.End()
+	var found models.Blacklist
+	if err := d.db.QueryRowContext(ctx, sqliteCheckBlacklistQuery, accessToken).Scan(
+		&found.ID,
+		&found.AccessToken,
+		&found.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return &found, nil
+}
@@ -2,40 +2,97 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"github.com/JECSand/identity-service/command_service/config"
 	"github.com/JECSand/identity-service/command_service/identity/models"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/gofrs/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"time"
 )
 
 const (
-	createUserQuery = `INSERT INTO users (id, email, username, password, root, active, created_at, updated_at) 
-	VALUES ($1, $2, $3, $4, $5, $6, now(), now()) RETURNING id, email, username, password, root, active, created_at, updated_at`
+	createUserQuery = `WITH released AS (DELETE FROM identifier_reservations WHERE user_id = $1)
+	INSERT INTO users (id, email, username, password, root, active, status, organization_id, org_admin, created_at, updated_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now()) 
+	RETURNING id, email, username, password, root, active, created_at, updated_at, status, status_reason, suspended_until, sessions_revoked_at, 
+	organization_id, org_admin`
 
-	updateUserQuery = `UPDATE users p SET 
+	updateUserQuery = `WITH released AS (DELETE FROM identifier_reservations WHERE user_id = $1)
+                      UPDATE users p SET 
                       email=COALESCE(NULLIF($2, ''), email), 
                       username=COALESCE(NULLIF($3, ''), username), 
                       updated_at = now()
                       WHERE id=$1
                       RETURNING id, email, username, root, active, created_at, updated_at`
 
-	updateUserPasswordQuery = `UPDATE users p SET 
+	updateUserPasswordQuery = `WITH previous AS (
+                      INSERT INTO password_history (user_id, password, created_at)
+                      SELECT id, password, now() FROM users WHERE id=$1 AND $2 <> ''
+                      )
+                      UPDATE users p SET 
                       password=COALESCE(NULLIF($2, ''), password), 
                       updated_at = now()
                       WHERE id=$1
                       RETURNING id, email, username, root, active, created_at, updated_at`
 
-	getUserByIdQuery = `SELECT p.id, p.email, p.username, p.password, p.root, p.active, p.created_at, p.updated_at 
+	updateUserStatusQuery = `UPDATE users p SET 
+                      status=$2, 
+                      active=$3, 
+                      status_reason=$4, 
+                      suspended_until=$5, 
+                      sessions_revoked_at=COALESCE($6, sessions_revoked_at), 
+                      updated_at = now()
+                      WHERE id=$1
+                      RETURNING id, email, username, root, active, created_at, updated_at, status, status_reason, suspended_until, sessions_revoked_at, 
+                      organization_id, org_admin`
+
+	rehashUserPasswordQuery = `UPDATE users p SET 
+                      password=$2, 
+                      updated_at = now()
+                      WHERE id=$1 AND password=$3
+                      RETURNING id, email, username, root, active, created_at, updated_at`
+
+	getUserByIdQuery = `SELECT p.id, p.email, p.username, p.password, p.root, p.active, p.created_at, p.updated_at, 
+	p.status, p.status_reason, p.suspended_until, p.sessions_revoked_at, p.organization_id, p.org_admin 
 	FROM users p WHERE p.id = $1`
 
+	getUserByEmailQuery = `SELECT p.id, p.email, p.username, p.password, p.root, p.active, p.created_at, p.updated_at, 
+	p.status, p.status_reason, p.suspended_until, p.sessions_revoked_at, p.organization_id, p.org_admin 
+	FROM users p WHERE p.email = $1`
+
+	getPasswordHistoryQuery = `SELECT p.password FROM password_history p WHERE p.user_id = $1 ORDER BY p.created_at DESC LIMIT $2`
+
 	deleteUserByIdQuery = `DELETE FROM users WHERE id = $1`
 
 	countUsersQuery = `SELECT COUNT(*) from users`
 )
 
+// userLifecycle scans the lifecycle columns of a users row, whose timestamps stay NULL until first set
+type userLifecycle struct {
+	status            int
+	statusReason      string
+	suspendedUntil    sql.NullTime
+	sessionsRevokedAt sql.NullTime
+}
+
+// apply copies the scanned lifecycle columns onto user
+func (l *userLifecycle) apply(user *models.User) {
+	user.Status = enums.UserStatus(l.status)
+	user.StatusReason = l.statusReason
+	user.SuspendedUntil = l.suspendedUntil.Time
+	user.SessionsRevokedAt = l.sessionsRevokedAt.Time
+}
+
+// nullTime maps the zero time to NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
 type userRepository struct {
 	log logging.Logger
 	cfg *config.Config
@@ -53,8 +110,8 @@ func NewUserRepository(log logging.Logger, cfg *config.Config, db *pgxpool.Pool)
 
 // Count ...
 func (p *userRepository) Count(ctx context.Context) (int, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.CountUsers")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "users")
+	defer span.End()
 	type countRes struct {
 		count int
 	}
@@ -69,10 +126,11 @@ func (p *userRepository) Count(ctx context.Context) (int, error) {
 
 // Create ...
 func (p *userRepository) Create(ctx context.Context, user *models.User) (*models.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.CreateUser")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "INSERT", "users")
+	defer span.End()
 	var created models.User
-	if err := p.db.QueryRow(ctx, createUserQuery, &user.ID, &user.Email, &user.Username, &user.Password, user.Root, user.Active).Scan(
+	var lifecycle userLifecycle
+	if err := p.db.QueryRow(ctx, createUserQuery, &user.ID, &user.Email, &user.Username, &user.Password, user.Root, user.Active, user.LifecycleStatus().EnumIndex(), &user.OrganizationID, user.OrgAdmin).Scan(
 		&created.ID,
 		&created.Email,
 		&created.Username,
@@ -81,16 +139,23 @@ func (p *userRepository) Create(ctx context.Context, user *models.User) (*models
 		&created.Active,
 		&created.CreatedAt,
 		&created.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&created.OrganizationID,
+		&created.OrgAdmin,
 	); err != nil {
 		return nil, errors.Wrap(err, "db.QueryRow")
 	}
+	lifecycle.apply(&created)
 	return &created, nil
 }
 
 // Update ...
 func (p *userRepository) Update(ctx context.Context, user *models.User) (*models.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.UpdateUser")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "users")
+	defer span.End()
 	var updated models.User
 	if err := p.db.QueryRow(
 		ctx,
@@ -106,8 +171,8 @@ func (p *userRepository) Update(ctx context.Context, user *models.User) (*models
 
 // UpdatePassword ...
 func (p *userRepository) UpdatePassword(ctx context.Context, user *models.User) (*models.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.UpdatePassword")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "users")
+	defer span.End()
 	var updated models.User
 	if err := p.db.QueryRow(
 		ctx,
@@ -120,11 +185,85 @@ func (p *userRepository) UpdatePassword(ctx context.Context, user *models.User)
 	return &updated, nil
 }
 
+// UpdateStatus moves a user into its Status with StatusReason and SuspendedUntil, recording SessionsRevokedAt when set
+func (p *userRepository) UpdateStatus(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "users")
+	defer span.End()
+	var updated models.User
+	var lifecycle userLifecycle
+	if err := p.db.QueryRow(
+		ctx,
+		updateUserStatusQuery,
+		&user.ID,
+		user.Status.EnumIndex(),
+		user.Active,
+		&user.StatusReason,
+		nullTime(user.SuspendedUntil),
+		nullTime(user.SessionsRevokedAt),
+	).Scan(
+		&updated.ID,
+		&updated.Email,
+		&updated.Username,
+		&updated.Root,
+		&updated.Active,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&updated.OrganizationID,
+		&updated.OrgAdmin,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	lifecycle.apply(&updated)
+	return &updated, nil
+}
+
+// RehashPassword replaces the password hash of a user only while it still equals currentHash
+func (p *userRepository) RehashPassword(ctx context.Context, user *models.User, currentHash string) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "UPDATE", "users")
+	defer span.End()
+	var updated models.User
+	if err := p.db.QueryRow(
+		ctx,
+		rehashUserPasswordQuery,
+		&user.ID,
+		&user.Password,
+		&currentHash,
+	).Scan(&updated.ID, &updated.Email, &updated.Username, &updated.Root, &updated.Active, &updated.CreatedAt, &updated.UpdatedAt); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	return &updated, nil
+}
+
+// GetPasswordHistory returns up to limit previous password hashes of a user, the most recent first
+func (p *userRepository) GetPasswordHistory(ctx context.Context, id uuid.UUID, limit int) ([]string, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "password_history")
+	defer span.End()
+	rows, err := p.db.Query(ctx, getPasswordHistoryQuery, id, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "Query")
+	}
+	defer rows.Close()
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err = rows.Scan(&hash); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, errors.Wrap(rows.Err(), "rows.Err")
+}
+
 // GetById ...
 func (p *userRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.GetUserById")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "users")
+	defer span.End()
 	var found models.User
+	var lifecycle userLifecycle
 	if err := p.db.QueryRow(ctx, getUserByIdQuery, uuid).Scan(
 		&found.ID,
 		&found.Email,
@@ -134,16 +273,51 @@ func (p *userRepository) GetById(ctx context.Context, uuid uuid.UUID) (*models.U
 		&found.Active,
 		&found.CreatedAt,
 		&found.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&found.OrganizationID,
+		&found.OrgAdmin,
+	); err != nil {
+		return nil, errors.Wrap(err, "Scan")
+	}
+	lifecycle.apply(&found)
+	return &found, nil
+}
+
+// GetByEmail ...
+func (p *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "SELECT", "users")
+	defer span.End()
+	var found models.User
+	var lifecycle userLifecycle
+	if err := p.db.QueryRow(ctx, getUserByEmailQuery, email).Scan(
+		&found.ID,
+		&found.Email,
+		&found.Username,
+		&found.Password,
+		&found.Root,
+		&found.Active,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+		&lifecycle.status,
+		&lifecycle.statusReason,
+		&lifecycle.suspendedUntil,
+		&lifecycle.sessionsRevokedAt,
+		&found.OrganizationID,
+		&found.OrgAdmin,
 	); err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
+	lifecycle.apply(&found)
 	return &found, nil
 }
 
 // DeleteByID ...
 func (p *userRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.DeleteUserByID")
-	defer span.Finish()
+	ctx, span := tracing.StartDbSpan(ctx, semconv.DBSystemPostgreSQL, "DELETE", "users")
+	defer span.End()
 	_, err := p.db.Exec(ctx, deleteUserByIdQuery, id)
 	if err != nil {
 		return errors.Wrap(err, "Exec")
@@ -5,8 +5,9 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	"github.com/JECSand/identity-service/command_service/identity/queries"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 )
 
 // AuthService ...
@@ -16,12 +17,14 @@ type AuthService struct {
 }
 
 // NewAuthService ...
-func NewAuthService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *AuthService {
-	blacklistTokenHandler := commands.NewBlacklistTokenHandler(log, cfg, pgRepo, kafkaProducer)
-	passwordUpdateHandler := commands.NewUpdatePasswordHandler(log, cfg, pgRepo, kafkaProducer)
+func NewAuthService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher, hasher authentication.PasswordHasher, policy authentication.PasswordPolicy) *AuthService {
+	blacklistTokenHandler := commands.NewBlacklistTokenHandler(log, cfg, pgRepo, publisher)
+	checkPasswordHandler := queries.NewCheckPasswordHandler(log, cfg, pgRepo, policy)
+	passwordUpdateHandler := commands.NewUpdatePasswordHandler(log, cfg, pgRepo, publisher, hasher, checkPasswordHandler)
 	checkBlacklistHandler := queries.NewCheckTokenBlacklistHandler(log, cfg, pgRepo)
+	authenticateHandler := queries.NewAuthenticateHandler(log, cfg, pgRepo, hasher)
 	userCommands := commands.NewAuthCommands(blacklistTokenHandler, passwordUpdateHandler)
-	userQueries := queries.NewAuthQueries(checkBlacklistHandler)
+	userQueries := queries.NewAuthQueries(checkBlacklistHandler, checkPasswordHandler, authenticateHandler)
 	return &AuthService{
 		Commands: userCommands,
 		Queries:  userQueries,
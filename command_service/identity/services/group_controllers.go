@@ -5,8 +5,8 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	"github.com/JECSand/identity-service/command_service/identity/queries"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 )
 
 // GroupService ...
@@ -16,13 +16,14 @@ type GroupService struct {
 }
 
 // NewGroupService ...
-func NewGroupService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *GroupService {
-	updateGroupHandler := commands.NewUpdateGroupHandler(log, cfg, pgRepo, kafkaProducer)
-	createGroupHandler := commands.NewCreateGroupHandler(log, cfg, pgRepo, kafkaProducer)
-	deleteGroupHandler := commands.NewDeleteGroupHandler(log, cfg, pgRepo, kafkaProducer)
+func NewGroupService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *GroupService {
+	updateGroupHandler := commands.NewUpdateGroupHandler(log, cfg, pgRepo, publisher)
+	createGroupHandler := commands.NewCreateGroupHandler(log, cfg, pgRepo, publisher)
+	deleteGroupHandler := commands.NewDeleteGroupHandler(log, cfg, pgRepo, publisher)
+	setGroupParentHandler := commands.NewSetGroupParentHandler(log, cfg, pgRepo, publisher)
 	getGroupByIdHandler := queries.NewGetGroupByIdHandler(log, cfg, pgRepo)
 	countGroupsHandler := queries.NewCountGroupsHandler(log, cfg, pgRepo)
-	GroupCommands := commands.NewGroupCommands(createGroupHandler, updateGroupHandler, deleteGroupHandler)
+	GroupCommands := commands.NewGroupCommands(createGroupHandler, updateGroupHandler, deleteGroupHandler, setGroupParentHandler)
 	GroupQueries := queries.NewGroupQueries(getGroupByIdHandler, countGroupsHandler)
 	return &GroupService{
 		Commands: GroupCommands,
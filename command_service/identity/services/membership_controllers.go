@@ -5,8 +5,8 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	"github.com/JECSand/identity-service/command_service/identity/queries"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 )
 
 // MembershipService ...
@@ -16,15 +16,17 @@ type MembershipService struct {
 }
 
 // NewMembershipService ...
-func NewMembershipService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *MembershipService {
-	updateMembershipHandler := commands.NewUpdateMembershipHandler(log, cfg, pgRepo, kafkaProducer)
-	createMembershipHandler := commands.NewCreateMembershipHandler(log, cfg, pgRepo, kafkaProducer)
-	deleteMembershipHandler := commands.NewDeleteMembershipHandler(log, cfg, pgRepo, kafkaProducer)
+func NewMembershipService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *MembershipService {
+	updateMembershipHandler := commands.NewUpdateMembershipHandler(log, cfg, pgRepo, publisher)
+	createMembershipHandler := commands.NewCreateMembershipHandler(log, cfg, pgRepo, publisher)
+	deleteMembershipHandler := commands.NewDeleteMembershipHandler(log, cfg, pgRepo, publisher)
+	extendMembershipHandler := commands.NewExtendMembershipHandler(log, cfg, pgRepo, publisher)
+	expireMembershipsHandler := commands.NewExpireMembershipsHandler(log, cfg, pgRepo, publisher)
 	getMembershipByIdHandler := queries.NewGetMembershipByIdHandler(log, cfg, pgRepo)
 	getUserMembershipByIdHandler := queries.NewGetUserMembershipByIdHandler(log, cfg, pgRepo)
 	getGroupMembershipByIdHandler := queries.NewGetGroupMembershipByIdHandler(log, cfg, pgRepo)
 	countMembershipsHandler := queries.NewCountMembershipsHandler(log, cfg, pgRepo)
-	membershipCommands := commands.NewMembershipCommands(createMembershipHandler, updateMembershipHandler, deleteMembershipHandler)
+	membershipCommands := commands.NewMembershipCommands(createMembershipHandler, updateMembershipHandler, deleteMembershipHandler, extendMembershipHandler, expireMembershipsHandler)
 	membershipQueries := queries.NewMembershipQueries(getMembershipByIdHandler, getUserMembershipByIdHandler, getGroupMembershipByIdHandler, countMembershipsHandler)
 	return &MembershipService{
 		Commands: membershipCommands,
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/commands"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+)
+
+// OrganizationService ...
+type OrganizationService struct {
+	Commands *commands.OrganizationCommands
+}
+
+// NewOrganizationService ...
+func NewOrganizationService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *OrganizationService {
+	createOrganizationHandler := commands.NewCreateOrganizationHandler(log, cfg, pgRepo, publisher)
+	updateOrganizationHandler := commands.NewUpdateOrganizationHandler(log, cfg, pgRepo, publisher)
+	changeOrganizationStatusHandler := commands.NewChangeOrganizationStatusHandler(log, cfg, pgRepo, publisher)
+	deleteOrganizationHandler := commands.NewDeleteOrganizationHandler(log, cfg, pgRepo, publisher)
+	organizationCommands := commands.NewOrganizationCommands(createOrganizationHandler, updateOrganizationHandler, changeOrganizationStatusHandler, deleteOrganizationHandler)
+	return &OrganizationService{
+		Commands: organizationCommands,
+	}
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/commands"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+)
+
+// RoleService ...
+type RoleService struct {
+	Commands *commands.RoleCommands
+}
+
+// NewRoleService ...
+func NewRoleService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher) *RoleService {
+	createRoleHandler := commands.NewCreateRoleHandler(log, cfg, pgRepo, publisher)
+	updateRoleHandler := commands.NewUpdateRoleHandler(log, cfg, pgRepo, publisher)
+	deleteRoleHandler := commands.NewDeleteRoleHandler(log, cfg, pgRepo, publisher)
+	roleCommands := commands.NewRoleCommands(createRoleHandler, updateRoleHandler, deleteRoleHandler)
+	return &RoleService{
+		Commands: roleCommands,
+	}
+}
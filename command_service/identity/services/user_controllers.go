@@ -5,8 +5,9 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	"github.com/JECSand/identity-service/command_service/identity/queries"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
-	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 )
 
 // UserService ...
@@ -16,13 +17,15 @@ type UserService struct {
 }
 
 // NewUserService ...
-func NewUserService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, kafkaProducer kafkaClient.Producer) *UserService {
-	updateUserHandler := commands.NewUpdateUserHandler(log, cfg, pgRepo, kafkaProducer)
-	createUserHandler := commands.NewCreateUserHandler(log, cfg, pgRepo, kafkaProducer)
-	deleteUserHandler := commands.NewDeleteUserHandler(log, cfg, pgRepo, kafkaProducer)
+func NewUserService(log logging.Logger, cfg *config.Config, pgRepo repositories.Repository, publisher messaging.Publisher, hasher authentication.PasswordHasher, policy authentication.PasswordPolicy) *UserService {
+	updateUserHandler := commands.NewUpdateUserHandler(log, cfg, pgRepo, publisher)
+	createUserHandler := commands.NewCreateUserHandler(log, cfg, pgRepo, publisher, hasher, queries.NewCheckPasswordHandler(log, cfg, pgRepo, policy))
+	deleteUserHandler := commands.NewDeleteUserHandler(log, cfg, pgRepo, publisher)
+	reserveIdentifiersHandler := commands.NewReserveIdentifiersHandler(log, cfg, pgRepo)
+	changeUserStatusHandler := commands.NewChangeUserStatusHandler(log, cfg, pgRepo, publisher)
 	getUserByIdHandler := queries.NewGetUserByIdHandler(log, cfg, pgRepo)
 	countUsersHandler := queries.NewCountUsersHandler(log, cfg, pgRepo)
-	userCommands := commands.NewUserCommands(createUserHandler, updateUserHandler, deleteUserHandler)
+	userCommands := commands.NewUserCommands(createUserHandler, updateUserHandler, deleteUserHandler, reserveIdentifiersHandler, changeUserStatusHandler)
 	userQueries := queries.NewUserQueries(getUserByIdHandler, countUsersHandler)
 	return &UserService{
 		Commands: userCommands,
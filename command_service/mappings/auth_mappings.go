@@ -3,6 +3,7 @@ package mappings
 import (
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	commandService "github.com/JECSand/identity-service/command_service/protos/auth_command"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
 	"github.com/gofrs/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -38,3 +39,25 @@ func CommandBlacklistToGrpc(bl *models.Blacklist) *commandService.Blacklist {
 		UpdatedAt:   timestamppb.New(bl.UpdatedAt),
 	}
 }
+
+func CommandAuthUserToGrpc(user *models.User) *commandService.User {
+	return &commandService.User{
+		ID:             user.ID.String(),
+		Email:          user.Email,
+		Username:       user.Username,
+		Root:           user.Root,
+		Active:         user.Active,
+		CreatedAt:      timestamppb.New(user.CreatedAt),
+		UpdatedAt:      timestamppb.New(user.UpdatedAt),
+		OrganizationID: user.OrganizationID.String(),
+		OrgAdmin:       user.OrgAdmin,
+	}
+}
+
+func PasswordViolationsToGrpc(violations []authentication.PasswordViolation) []*commandService.PasswordViolation {
+	grpcViolations := make([]*commandService.PasswordViolation, len(violations))
+	for i, violation := range violations {
+		grpcViolations[i] = &commandService.PasswordViolation{Rule: violation.Rule, Message: violation.Message}
+	}
+	return grpcViolations
+}
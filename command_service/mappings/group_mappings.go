@@ -10,13 +10,15 @@ import (
 
 func GroupToGrpcMessage(group *models.Group) *kafkaMessages.Group {
 	return &kafkaMessages.Group{
-		ID:          group.ID.String(),
-		Name:        group.Name,
-		Description: group.Description,
-		CreatorID:   group.CreatorID.String(),
-		Active:      group.Active,
-		CreatedAt:   timestamppb.New(group.CreatedAt),
-		UpdatedAt:   timestamppb.New(group.UpdatedAt),
+		ID:             group.ID.String(),
+		Name:           group.Name,
+		Description:    group.Description,
+		CreatorID:      group.CreatorID.String(),
+		Active:         group.Active,
+		CreatedAt:      timestamppb.New(group.CreatedAt),
+		UpdatedAt:      timestamppb.New(group.UpdatedAt),
+		OrganizationID: group.OrganizationID.String(),
+		ParentID:       parentIDToString(group.ParentID),
 	}
 }
 
@@ -30,24 +32,36 @@ func GroupFromGrpcMessage(group *kafkaMessages.Group) (*models.Group, error) {
 		return nil, err
 	}
 	return &models.Group{
-		ID:          id,
-		Name:        group.GetName(),
-		Description: group.GetDescription(),
-		CreatorID:   creatorId,
-		Active:      group.GetActive(),
-		CreatedAt:   group.GetCreatedAt().AsTime(),
-		UpdatedAt:   group.GetUpdatedAt().AsTime(),
+		ID:             id,
+		Name:           group.GetName(),
+		Description:    group.GetDescription(),
+		CreatorID:      creatorId,
+		Active:         group.GetActive(),
+		CreatedAt:      group.GetCreatedAt().AsTime(),
+		UpdatedAt:      group.GetUpdatedAt().AsTime(),
+		OrganizationID: uuid.FromStringOrNil(group.GetOrganizationID()),
+		ParentID:       uuid.FromStringOrNil(group.GetParentID()),
 	}, nil
 }
 
 func CommandGroupToGrpc(group *models.Group) *commandService.Group {
 	return &commandService.Group{
-		ID:          group.ID.String(),
-		Name:        group.Name,
-		Description: group.Description,
-		CreatorID:   group.CreatorID.String(),
-		Active:      group.Active,
-		CreatedAt:   timestamppb.New(group.CreatedAt),
-		UpdatedAt:   timestamppb.New(group.UpdatedAt),
+		ID:             group.ID.String(),
+		Name:           group.Name,
+		Description:    group.Description,
+		CreatorID:      group.CreatorID.String(),
+		Active:         group.Active,
+		CreatedAt:      timestamppb.New(group.CreatedAt),
+		UpdatedAt:      timestamppb.New(group.UpdatedAt),
+		ParentID:       parentIDToString(group.ParentID),
+		OrganizationID: group.OrganizationID.String(),
 	}
 }
+
+// parentIDToString leaves the parent of top level groups empty
+func parentIDToString(parentID uuid.UUID) string {
+	if parentID == uuid.Nil {
+		return ""
+	}
+	return parentID.String()
+}
@@ -11,44 +11,62 @@ import (
 
 func MembershipToGrpcMessage(membership *models.Membership) *kafkaMessages.Membership {
 	return &kafkaMessages.Membership{
-		ID:        membership.ID.String(),
-		UserID:    membership.UserID.String(),
-		GroupID:   membership.GroupID.String(),
-		Status:    int64(membership.Status),
-		Role:      int64(membership.Role),
-		CreatedAt: timestamppb.New(membership.CreatedAt),
-		UpdatedAt: timestamppb.New(membership.UpdatedAt),
+		ID:             membership.ID.String(),
+		UserID:         membership.UserID.String(),
+		GroupID:        membership.GroupID.String(),
+		Status:         int64(membership.Status),
+		Role:           int64(membership.Role),
+		RoleID:         membership.RoleID.String(),
+		RoleName:       membership.RoleName,
+		Permissions:    membership.Permissions,
+		CreatedAt:      timestamppb.New(membership.CreatedAt),
+		UpdatedAt:      timestamppb.New(membership.UpdatedAt),
+		OrganizationID: membership.OrganizationID.String(),
+		ValidFrom:      optionalTimestamp(membership.ValidFrom),
+		ValidUntil:     optionalTimestamp(membership.ValidUntil),
 	}
 }
 
 func UserMembershipToGrpcMessage(membership *models.UserMembership) *kafkaMessages.UserMembership {
 	return &kafkaMessages.UserMembership{
-		ID:           membership.ID.String(),
-		GroupID:      membership.GroupID.String(),
-		UserID:       membership.UserID.String(),
-		MembershipID: membership.MembershipID.String(),
-		Email:        membership.Email,
-		Username:     membership.Username,
-		Status:       int64(membership.Status),
-		Role:         int64(membership.Role),
-		CreatedAt:    timestamppb.New(membership.CreatedAt),
-		UpdatedAt:    timestamppb.New(membership.UpdatedAt),
+		ID:             membership.ID.String(),
+		GroupID:        membership.GroupID.String(),
+		UserID:         membership.UserID.String(),
+		MembershipID:   membership.MembershipID.String(),
+		Email:          membership.Email,
+		Username:       membership.Username,
+		Status:         int64(membership.Status),
+		Role:           int64(membership.Role),
+		RoleID:         membership.RoleID.String(),
+		RoleName:       membership.RoleName,
+		Permissions:    membership.Permissions,
+		CreatedAt:      timestamppb.New(membership.CreatedAt),
+		UpdatedAt:      timestamppb.New(membership.UpdatedAt),
+		OrganizationID: membership.OrganizationID.String(),
+		ValidFrom:      optionalTimestamp(membership.ValidFrom),
+		ValidUntil:     optionalTimestamp(membership.ValidUntil),
 	}
 }
 
 func GroupMembershipToGrpcMessage(membership *models.GroupMembership) *kafkaMessages.GroupMembership {
 	return &kafkaMessages.GroupMembership{
-		ID:           membership.ID.String(),
-		UserID:       membership.UserID.String(),
-		GroupID:      membership.GroupID.String(),
-		MembershipID: membership.MembershipID.String(),
-		Name:         membership.Name,
-		Description:  membership.Description,
-		Status:       int64(membership.Status),
-		Role:         int64(membership.Role),
-		Creator:      membership.Creator,
-		CreatedAt:    timestamppb.New(membership.CreatedAt),
-		UpdatedAt:    timestamppb.New(membership.UpdatedAt),
+		ID:             membership.ID.String(),
+		UserID:         membership.UserID.String(),
+		GroupID:        membership.GroupID.String(),
+		MembershipID:   membership.MembershipID.String(),
+		Name:           membership.Name,
+		Description:    membership.Description,
+		Status:         int64(membership.Status),
+		Role:           int64(membership.Role),
+		RoleID:         membership.RoleID.String(),
+		RoleName:       membership.RoleName,
+		Permissions:    membership.Permissions,
+		Creator:        membership.Creator,
+		CreatedAt:      timestamppb.New(membership.CreatedAt),
+		UpdatedAt:      timestamppb.New(membership.UpdatedAt),
+		OrganizationID: membership.OrganizationID.String(),
+		ValidFrom:      optionalTimestamp(membership.ValidFrom),
+		ValidUntil:     optionalTimestamp(membership.ValidUntil),
 	}
 }
 
@@ -66,13 +84,19 @@ func MembershipFromGrpcMessage(membership *kafkaMessages.Membership) (*models.Me
 		return nil, err
 	}
 	return &models.Membership{
-		ID:        id,
-		UserID:    userId,
-		GroupID:   groupId,
-		Status:    enums.MembershipStatus(membership.GetStatus()),
-		Role:      enums.Role(membership.GetRole()),
-		CreatedAt: membership.GetCreatedAt().AsTime(),
-		UpdatedAt: membership.GetUpdatedAt().AsTime(),
+		ID:             id,
+		UserID:         userId,
+		GroupID:        groupId,
+		Status:         enums.MembershipStatus(membership.GetStatus()),
+		Role:           enums.Role(membership.GetRole()),
+		RoleID:         uuid.FromStringOrNil(membership.GetRoleID()),
+		RoleName:       membership.GetRoleName(),
+		Permissions:    membership.GetPermissions(),
+		CreatedAt:      membership.GetCreatedAt().AsTime(),
+		UpdatedAt:      membership.GetUpdatedAt().AsTime(),
+		OrganizationID: uuid.FromStringOrNil(membership.GetOrganizationID()),
+		ValidFrom:      optionalTime(membership.GetValidFrom()),
+		ValidUntil:     optionalTime(membership.GetValidUntil()),
 	}, nil
 }
 
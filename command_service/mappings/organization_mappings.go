@@ -0,0 +1,19 @@
+package mappings
+
+import (
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func OrganizationToGrpcMessage(organization *models.Organization) *kafkaMessages.Organization {
+	return &kafkaMessages.Organization{
+		ID:                organization.ID.String(),
+		Name:              organization.Name,
+		Description:       organization.Description,
+		Active:            organization.Active,
+		CreatedAt:         timestamppb.New(organization.CreatedAt),
+		UpdatedAt:         timestamppb.New(organization.UpdatedAt),
+		SessionsRevokedAt: optionalTimestamp(organization.SessionsRevokedAt),
+	}
+}
@@ -0,0 +1,21 @@
+package mappings
+
+import (
+	"github.com/JECSand/identity-service/command_service/identity/models"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func RoleToGrpcMessage(role *models.Role) *kafkaMessages.Role {
+	return &kafkaMessages.Role{
+		ID:             role.ID.String(),
+		Name:           role.Name,
+		Description:    role.Description,
+		Permissions:    role.Permissions,
+		GroupID:        role.GroupID.String(),
+		OrganizationID: role.OrganizationID.String(),
+		BuiltIn:        role.BuiltIn,
+		CreatedAt:      timestamppb.New(role.CreatedAt),
+		UpdatedAt:      timestamppb.New(role.UpdatedAt),
+	}
+}
@@ -3,21 +3,28 @@ package mappings
 import (
 	"github.com/JECSand/identity-service/command_service/identity/models"
 	commandService "github.com/JECSand/identity-service/command_service/protos/user_command"
+	"github.com/JECSand/identity-service/pkg/enums"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
 	"github.com/gofrs/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
 )
 
 func UserToGrpcMessage(user *models.User) *kafkaMessages.User {
 	return &kafkaMessages.User{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		Username:  user.Username,
-		Password:  user.Password,
-		Root:      user.Root,
-		Active:    user.Active,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		ID:                user.ID.String(),
+		Email:             user.Email,
+		Username:          user.Username,
+		Root:              user.Root,
+		Active:            user.Active,
+		CreatedAt:         timestamppb.New(user.CreatedAt),
+		UpdatedAt:         timestamppb.New(user.UpdatedAt),
+		Status:            user.LifecycleStatus().Stringify(),
+		StatusReason:      user.StatusReason,
+		SuspendedUntil:    optionalTimestamp(user.SuspendedUntil),
+		SessionsRevokedAt: optionalTimestamp(user.SessionsRevokedAt),
+		OrganizationID:    user.OrganizationID.String(),
+		OrgAdmin:          user.OrgAdmin,
 	}
 }
 
@@ -27,26 +34,51 @@ func UserFromGrpcMessage(user *kafkaMessages.User) (*models.User, error) {
 		return nil, err
 	}
 	return &models.User{
-		ID:        id,
-		Email:     user.GetEmail(),
-		Username:  user.GetUsername(),
-		Password:  user.GetPassword(),
-		Root:      user.GetRoot(),
-		Active:    user.GetActive(),
-		CreatedAt: user.GetCreatedAt().AsTime(),
-		UpdatedAt: user.GetUpdatedAt().AsTime(),
+		ID:                id,
+		Email:             user.GetEmail(),
+		Username:          user.GetUsername(),
+		Root:              user.GetRoot(),
+		Active:            user.GetActive(),
+		CreatedAt:         user.GetCreatedAt().AsTime(),
+		UpdatedAt:         user.GetUpdatedAt().AsTime(),
+		Status:            enums.UserStatusFromString(user.GetStatus()),
+		StatusReason:      user.GetStatusReason(),
+		SuspendedUntil:    optionalTime(user.GetSuspendedUntil()),
+		SessionsRevokedAt: optionalTime(user.GetSessionsRevokedAt()),
+		OrganizationID:    uuid.FromStringOrNil(user.GetOrganizationID()),
+		OrgAdmin:          user.GetOrgAdmin(),
 	}, nil
 }
 
 func CommandUserToGrpc(user *models.User) *commandService.User {
 	return &commandService.User{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		Username:  user.Username,
-		Password:  user.Password,
-		Root:      user.Root,
-		Active:    user.Active,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		ID:             user.ID.String(),
+		Email:          user.Email,
+		Username:       user.Username,
+		Root:           user.Root,
+		Active:         user.Active,
+		CreatedAt:      timestamppb.New(user.CreatedAt),
+		UpdatedAt:      timestamppb.New(user.UpdatedAt),
+		Status:         user.LifecycleStatus().Stringify(),
+		StatusReason:   user.StatusReason,
+		SuspendedUntil: optionalTimestamp(user.SuspendedUntil),
+		OrganizationID: user.OrganizationID.String(),
+		OrgAdmin:       user.OrgAdmin,
 	}
 }
+
+// optionalTimestamp maps the zero time to an unset timestamp
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// optionalTime maps an unset timestamp to the zero time
+func optionalTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -26,7 +26,7 @@ var file_auth_command_proto_rawDesc = []byte{
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
 	0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1b, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x63,
 	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xb9, 0x02, 0x0a, 0x12, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xf0, 0x03, 0x0a, 0x12, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f,
 	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5e, 0x0a, 0x0e,
 	0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x25,
 	0x2e, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76,
@@ -46,28 +46,47 @@ var file_auth_command_proto_rawDesc = []byte{
 	0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x25, 0x2e, 0x61, 0x75, 0x74,
 	0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
 	0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x65,
-	0x73, 0x42, 0x17, 0x5a, 0x15, 0x2e, 0x2f, 0x3b, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x73, 0x12, 0x5b, 0x0a, 0x0d, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x12, 0x24, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x24, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x12, 0x58,
+	0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x23,
+	0x2e, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x42, 0x17, 0x5a, 0x15, 0x2e, 0x2f, 0x3b, 0x61,
+	0x75, 0x74, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_auth_command_proto_goTypes = []interface{}{
 	(*BlacklistTokenReq)(nil), // 0: authCommandService.BlacklistTokenReq
 	(*UpdatePasswordReq)(nil), // 1: authCommandService.UpdatePasswordReq
 	(*CheckBlacklistReq)(nil), // 2: authCommandService.CheckBlacklistReq
-	(*BlacklistTokenRes)(nil), // 3: authCommandService.BlacklistTokenRes
-	(*UpdatePasswordRes)(nil), // 4: authCommandService.UpdatePasswordRes
-	(*CheckBlacklistRes)(nil), // 5: authCommandService.CheckBlacklistRes
+	(*CheckPasswordReq)(nil),  // 3: authCommandService.CheckPasswordReq
+	(*AuthenticateReq)(nil),   // 4: authCommandService.AuthenticateReq
+	(*BlacklistTokenRes)(nil), // 5: authCommandService.BlacklistTokenRes
+	(*UpdatePasswordRes)(nil), // 6: authCommandService.UpdatePasswordRes
+	(*CheckBlacklistRes)(nil), // 7: authCommandService.CheckBlacklistRes
+	(*CheckPasswordRes)(nil),  // 8: authCommandService.CheckPasswordRes
+	(*AuthenticateRes)(nil),   // 9: authCommandService.AuthenticateRes
 }
 var file_auth_command_proto_depIdxs = []int32{
 	0, // 0: authCommandService.authCommandService.BlacklistToken:input_type -> authCommandService.BlacklistTokenReq
 	1, // 1: authCommandService.authCommandService.UpdatePassword:input_type -> authCommandService.UpdatePasswordReq
 	2, // 2: authCommandService.authCommandService.CheckTokenBlacklist:input_type -> authCommandService.CheckBlacklistReq
-	3, // 3: authCommandService.authCommandService.BlacklistToken:output_type -> authCommandService.BlacklistTokenRes
-	4, // 4: authCommandService.authCommandService.UpdatePassword:output_type -> authCommandService.UpdatePasswordRes
-	5, // 5: authCommandService.authCommandService.CheckTokenBlacklist:output_type -> authCommandService.CheckBlacklistRes
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
+	3, // 3: authCommandService.authCommandService.CheckPassword:input_type -> authCommandService.CheckPasswordReq
+	4, // 4: authCommandService.authCommandService.Authenticate:input_type -> authCommandService.AuthenticateReq
+	5, // 5: authCommandService.authCommandService.BlacklistToken:output_type -> authCommandService.BlacklistTokenRes
+	6, // 6: authCommandService.authCommandService.UpdatePassword:output_type -> authCommandService.UpdatePasswordRes
+	7, // 7: authCommandService.authCommandService.CheckTokenBlacklist:output_type -> authCommandService.CheckBlacklistRes
+	8, // 8: authCommandService.authCommandService.CheckPassword:output_type -> authCommandService.CheckPasswordRes
+	9, // 9: authCommandService.authCommandService.Authenticate:output_type -> authCommandService.AuthenticateRes
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -25,6 +25,8 @@ type AuthCommandServiceClient interface {
 	BlacklistToken(ctx context.Context, in *BlacklistTokenReq, opts ...grpc.CallOption) (*BlacklistTokenRes, error)
 	UpdatePassword(ctx context.Context, in *UpdatePasswordReq, opts ...grpc.CallOption) (*UpdatePasswordRes, error)
 	CheckTokenBlacklist(ctx context.Context, in *CheckBlacklistReq, opts ...grpc.CallOption) (*CheckBlacklistRes, error)
+	CheckPassword(ctx context.Context, in *CheckPasswordReq, opts ...grpc.CallOption) (*CheckPasswordRes, error)
+	Authenticate(ctx context.Context, in *AuthenticateReq, opts ...grpc.CallOption) (*AuthenticateRes, error)
 }
 
 type authCommandServiceClient struct {
@@ -62,6 +64,24 @@ func (c *authCommandServiceClient) CheckTokenBlacklist(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *authCommandServiceClient) CheckPassword(ctx context.Context, in *CheckPasswordReq, opts ...grpc.CallOption) (*CheckPasswordRes, error) {
+	out := new(CheckPasswordRes)
+	err := c.cc.Invoke(ctx, "/authCommandService.authCommandService/CheckPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authCommandServiceClient) Authenticate(ctx context.Context, in *AuthenticateReq, opts ...grpc.CallOption) (*AuthenticateRes, error) {
+	out := new(AuthenticateRes)
+	err := c.cc.Invoke(ctx, "/authCommandService.authCommandService/Authenticate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthCommandServiceServer is the server API for AuthCommandService service.
 // All implementations should embed UnimplementedAuthCommandServiceServer
 // for forward compatibility
@@ -69,6 +89,8 @@ type AuthCommandServiceServer interface {
 	BlacklistToken(context.Context, *BlacklistTokenReq) (*BlacklistTokenRes, error)
 	UpdatePassword(context.Context, *UpdatePasswordReq) (*UpdatePasswordRes, error)
 	CheckTokenBlacklist(context.Context, *CheckBlacklistReq) (*CheckBlacklistRes, error)
+	CheckPassword(context.Context, *CheckPasswordReq) (*CheckPasswordRes, error)
+	Authenticate(context.Context, *AuthenticateReq) (*AuthenticateRes, error)
 }
 
 // UnimplementedAuthCommandServiceServer should be embedded to have forward compatible implementations.
@@ -84,6 +106,12 @@ func (UnimplementedAuthCommandServiceServer) UpdatePassword(context.Context, *Up
 func (UnimplementedAuthCommandServiceServer) CheckTokenBlacklist(context.Context, *CheckBlacklistReq) (*CheckBlacklistRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckTokenBlacklist not implemented")
 }
+func (UnimplementedAuthCommandServiceServer) CheckPassword(context.Context, *CheckPasswordReq) (*CheckPasswordRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPassword not implemented")
+}
+func (UnimplementedAuthCommandServiceServer) Authenticate(context.Context, *AuthenticateReq) (*AuthenticateRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
+}
 
 // UnsafeAuthCommandServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to AuthCommandServiceServer will
@@ -150,6 +178,42 @@ func _AuthCommandService_CheckTokenBlacklist_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthCommandService_CheckPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPasswordReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthCommandServiceServer).CheckPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/authCommandService.authCommandService/CheckPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthCommandServiceServer).CheckPassword(ctx, req.(*CheckPasswordReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthCommandService_Authenticate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthCommandServiceServer).Authenticate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/authCommandService.authCommandService/Authenticate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthCommandServiceServer).Authenticate(ctx, req.(*AuthenticateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthCommandService_ServiceDesc is the grpc.ServiceDesc for AuthCommandService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -169,6 +233,14 @@ var AuthCommandService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckTokenBlacklist",
 			Handler:    _AuthCommandService_CheckTokenBlacklist_Handler,
 		},
+		{
+			MethodName: "CheckPassword",
+			Handler:    _AuthCommandService_CheckPassword_Handler,
+		},
+		{
+			MethodName: "Authenticate",
+			Handler:    _AuthCommandService_Authenticate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth_command.proto",
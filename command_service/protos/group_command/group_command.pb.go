@@ -26,7 +26,7 @@ var file_group_command_proto_rawDesc = []byte{
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6d, 0x6d,
 	0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1c, 0x67, 0x72, 0x6f, 0x75,
 	0x70, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xa3, 0x02, 0x0a, 0x13, 0x67, 0x72, 0x6f,
+	0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x85, 0x03, 0x0a, 0x13, 0x67, 0x72, 0x6f,
 	0x75, 0x70, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
 	0x12, 0x57, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
 	0x23, 0x2e, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65,
@@ -44,28 +44,39 @@ var file_group_command_proto_rawDesc = []byte{
 	0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75,
 	0x70, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x24, 0x2e, 0x67, 0x72, 0x6f, 0x75, 0x70,
 	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47,
-	0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x73, 0x42, 0x18,
-	0x5a, 0x16, 0x2e, 0x2f, 0x3b, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x73, 0x12, 0x60,
+	0x0a, 0x0e, 0x53, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74,
+	0x12, 0x26, 0x2e, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x50,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x26, 0x2e, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73,
+	0x42, 0x18, 0x5a, 0x16, 0x2e, 0x2f, 0x3b, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var file_group_command_proto_goTypes = []interface{}{
-	(*CreateGroupReq)(nil),  // 0: groupCommandService.CreateGroupReq
-	(*UpdateGroupReq)(nil),  // 1: groupCommandService.UpdateGroupReq
-	(*GetGroupByIdReq)(nil), // 2: groupCommandService.GetGroupByIdReq
-	(*CreateGroupRes)(nil),  // 3: groupCommandService.CreateGroupRes
-	(*UpdateGroupRes)(nil),  // 4: groupCommandService.UpdateGroupRes
-	(*GetGroupByIdRes)(nil), // 5: groupCommandService.GetGroupByIdRes
+	(*CreateGroupReq)(nil),    // 0: groupCommandService.CreateGroupReq
+	(*UpdateGroupReq)(nil),    // 1: groupCommandService.UpdateGroupReq
+	(*GetGroupByIdReq)(nil),   // 2: groupCommandService.GetGroupByIdReq
+	(*SetGroupParentReq)(nil), // 3: groupCommandService.SetGroupParentReq
+	(*CreateGroupRes)(nil),    // 4: groupCommandService.CreateGroupRes
+	(*UpdateGroupRes)(nil),    // 5: groupCommandService.UpdateGroupRes
+	(*GetGroupByIdRes)(nil),   // 6: groupCommandService.GetGroupByIdRes
+	(*SetGroupParentRes)(nil), // 7: groupCommandService.SetGroupParentRes
 }
 var file_group_command_proto_depIdxs = []int32{
 	0, // 0: groupCommandService.groupCommandService.CreateGroup:input_type -> groupCommandService.CreateGroupReq
 	1, // 1: groupCommandService.groupCommandService.UpdateGroup:input_type -> groupCommandService.UpdateGroupReq
 	2, // 2: groupCommandService.groupCommandService.GetGroupById:input_type -> groupCommandService.GetGroupByIdReq
-	3, // 3: groupCommandService.groupCommandService.CreateGroup:output_type -> groupCommandService.CreateGroupRes
-	4, // 4: groupCommandService.groupCommandService.UpdateGroup:output_type -> groupCommandService.UpdateGroupRes
-	5, // 5: groupCommandService.groupCommandService.GetGroupById:output_type -> groupCommandService.GetGroupByIdRes
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
+	3, // 3: groupCommandService.groupCommandService.SetGroupParent:input_type -> groupCommandService.SetGroupParentReq
+	4, // 4: groupCommandService.groupCommandService.CreateGroup:output_type -> groupCommandService.CreateGroupRes
+	5, // 5: groupCommandService.groupCommandService.UpdateGroup:output_type -> groupCommandService.UpdateGroupRes
+	6, // 6: groupCommandService.groupCommandService.GetGroupById:output_type -> groupCommandService.GetGroupByIdRes
+	7, // 7: groupCommandService.groupCommandService.SetGroupParent:output_type -> groupCommandService.SetGroupParentRes
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
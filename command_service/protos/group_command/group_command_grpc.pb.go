@@ -25,6 +25,7 @@ type GroupCommandServiceClient interface {
 	CreateGroup(ctx context.Context, in *CreateGroupReq, opts ...grpc.CallOption) (*CreateGroupRes, error)
 	UpdateGroup(ctx context.Context, in *UpdateGroupReq, opts ...grpc.CallOption) (*UpdateGroupRes, error)
 	GetGroupById(ctx context.Context, in *GetGroupByIdReq, opts ...grpc.CallOption) (*GetGroupByIdRes, error)
+	SetGroupParent(ctx context.Context, in *SetGroupParentReq, opts ...grpc.CallOption) (*SetGroupParentRes, error)
 }
 
 type groupCommandServiceClient struct {
@@ -62,6 +63,15 @@ func (c *groupCommandServiceClient) GetGroupById(ctx context.Context, in *GetGro
 	return out, nil
 }
 
+func (c *groupCommandServiceClient) SetGroupParent(ctx context.Context, in *SetGroupParentReq, opts ...grpc.CallOption) (*SetGroupParentRes, error) {
+	out := new(SetGroupParentRes)
+	err := c.cc.Invoke(ctx, "/groupCommandService.groupCommandService/SetGroupParent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GroupCommandServiceServer is the server API for GroupCommandService service.
 // All implementations should embed UnimplementedGroupCommandServiceServer
 // for forward compatibility
@@ -69,6 +79,7 @@ type GroupCommandServiceServer interface {
 	CreateGroup(context.Context, *CreateGroupReq) (*CreateGroupRes, error)
 	UpdateGroup(context.Context, *UpdateGroupReq) (*UpdateGroupRes, error)
 	GetGroupById(context.Context, *GetGroupByIdReq) (*GetGroupByIdRes, error)
+	SetGroupParent(context.Context, *SetGroupParentReq) (*SetGroupParentRes, error)
 }
 
 // UnimplementedGroupCommandServiceServer should be embedded to have forward compatible implementations.
@@ -84,6 +95,9 @@ func (UnimplementedGroupCommandServiceServer) UpdateGroup(context.Context, *Upda
 func (UnimplementedGroupCommandServiceServer) GetGroupById(context.Context, *GetGroupByIdReq) (*GetGroupByIdRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetGroupById not implemented")
 }
+func (UnimplementedGroupCommandServiceServer) SetGroupParent(context.Context, *SetGroupParentReq) (*SetGroupParentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetGroupParent not implemented")
+}
 
 // UnsafeGroupCommandServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to GroupCommandServiceServer will
@@ -150,6 +164,24 @@ func _GroupCommandService_GetGroupById_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GroupCommandService_SetGroupParent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetGroupParentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupCommandServiceServer).SetGroupParent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/groupCommandService.groupCommandService/SetGroupParent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupCommandServiceServer).SetGroupParent(ctx, req.(*SetGroupParentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GroupCommandService_ServiceDesc is the grpc.ServiceDesc for GroupCommandService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -169,6 +201,10 @@ var GroupCommandService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetGroupById",
 			Handler:    _GroupCommandService_GetGroupById_Handler,
 		},
+		{
+			MethodName: "SetGroupParent",
+			Handler:    _GroupCommandService_SetGroupParent_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "group_command.proto",
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
 // 	protoc-gen-go v1.28.1
-// 	protoc        v3.20.2
+// 	protoc        v3.12.4
 // source: user_command.proto
 
 package commandService
@@ -26,7 +26,7 @@ var file_user_command_proto_rawDesc = []byte{
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72,
 	0x76, 0x69, 0x63, 0x65, 0x1a, 0x1b, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
 	0x6e, 0x64, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x32, 0xf7, 0x01, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72,
+	0x6f, 0x32, 0xb9, 0x03, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72,
 	0x76, 0x69, 0x63, 0x65, 0x12, 0x4a, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73,
 	0x65, 0x72, 0x12, 0x1d, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76,
 	0x69, 0x63, 0x65, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
@@ -41,28 +41,48 @@ var file_user_command_proto_rawDesc = []byte{
 	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74,
 	0x55, 0x73, 0x65, 0x72, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x63, 0x6f,
 	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74,
-	0x55, 0x73, 0x65, 0x72, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x73, 0x42, 0x13, 0x5a, 0x11, 0x2e,
-	0x2f, 0x3b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x55, 0x73, 0x65, 0x72, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x73, 0x12, 0x62, 0x0a, 0x12, 0x52,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x73, 0x12, 0x25, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x25, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x12,
+	0x5c, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x23, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x55, 0x73, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x42, 0x13, 0x5a,
+	0x11, 0x2e, 0x2f, 0x3b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_user_command_proto_goTypes = []interface{}{
-	(*CreateUserReq)(nil),  // 0: commandService.CreateUserReq
-	(*UpdateUserReq)(nil),  // 1: commandService.UpdateUserReq
-	(*GetUserByIdReq)(nil), // 2: commandService.GetUserByIdReq
-	(*CreateUserRes)(nil),  // 3: commandService.CreateUserRes
-	(*UpdateUserRes)(nil),  // 4: commandService.UpdateUserRes
-	(*GetUserByIdRes)(nil), // 5: commandService.GetUserByIdRes
+	(*CreateUserReq)(nil),         // 0: commandService.CreateUserReq
+	(*UpdateUserReq)(nil),         // 1: commandService.UpdateUserReq
+	(*GetUserByIdReq)(nil),        // 2: commandService.GetUserByIdReq
+	(*ReserveIdentifiersReq)(nil), // 3: commandService.ReserveIdentifiersReq
+	(*ChangeUserStatusReq)(nil),   // 4: commandService.ChangeUserStatusReq
+	(*CreateUserRes)(nil),         // 5: commandService.CreateUserRes
+	(*UpdateUserRes)(nil),         // 6: commandService.UpdateUserRes
+	(*GetUserByIdRes)(nil),        // 7: commandService.GetUserByIdRes
+	(*ReserveIdentifiersRes)(nil), // 8: commandService.ReserveIdentifiersRes
+	(*ChangeUserStatusRes)(nil),   // 9: commandService.ChangeUserStatusRes
 }
 var file_user_command_proto_depIdxs = []int32{
 	0, // 0: commandService.commandService.CreateUser:input_type -> commandService.CreateUserReq
 	1, // 1: commandService.commandService.UpdateUser:input_type -> commandService.UpdateUserReq
 	2, // 2: commandService.commandService.GetUserById:input_type -> commandService.GetUserByIdReq
-	3, // 3: commandService.commandService.CreateUser:output_type -> commandService.CreateUserRes
-	4, // 4: commandService.commandService.UpdateUser:output_type -> commandService.UpdateUserRes
-	5, // 5: commandService.commandService.GetUserById:output_type -> commandService.GetUserByIdRes
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
+	3, // 3: commandService.commandService.ReserveIdentifiers:input_type -> commandService.ReserveIdentifiersReq
+	4, // 4: commandService.commandService.ChangeUserStatus:input_type -> commandService.ChangeUserStatusReq
+	5, // 5: commandService.commandService.CreateUser:output_type -> commandService.CreateUserRes
+	6, // 6: commandService.commandService.UpdateUser:output_type -> commandService.UpdateUserRes
+	7, // 7: commandService.commandService.GetUserById:output_type -> commandService.GetUserByIdRes
+	8, // 8: commandService.commandService.ReserveIdentifiers:output_type -> commandService.ReserveIdentifiersRes
+	9, // 9: commandService.commandService.ChangeUserStatus:output_type -> commandService.ChangeUserStatusRes
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
 // - protoc-gen-go-grpc v1.2.0
-// - protoc             v3.20.2
+// - protoc             v3.12.4
 // source: user_command.proto
 
 package commandService
@@ -25,6 +25,8 @@ type CommandServiceClient interface {
 	CreateUser(ctx context.Context, in *CreateUserReq, opts ...grpc.CallOption) (*CreateUserRes, error)
 	UpdateUser(ctx context.Context, in *UpdateUserReq, opts ...grpc.CallOption) (*UpdateUserRes, error)
 	GetUserById(ctx context.Context, in *GetUserByIdReq, opts ...grpc.CallOption) (*GetUserByIdRes, error)
+	ReserveIdentifiers(ctx context.Context, in *ReserveIdentifiersReq, opts ...grpc.CallOption) (*ReserveIdentifiersRes, error)
+	ChangeUserStatus(ctx context.Context, in *ChangeUserStatusReq, opts ...grpc.CallOption) (*ChangeUserStatusRes, error)
 }
 
 type commandServiceClient struct {
@@ -62,6 +64,24 @@ func (c *commandServiceClient) GetUserById(ctx context.Context, in *GetUserByIdR
 	return out, nil
 }
 
+func (c *commandServiceClient) ReserveIdentifiers(ctx context.Context, in *ReserveIdentifiersReq, opts ...grpc.CallOption) (*ReserveIdentifiersRes, error) {
+	out := new(ReserveIdentifiersRes)
+	err := c.cc.Invoke(ctx, "/commandService.commandService/ReserveIdentifiers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandServiceClient) ChangeUserStatus(ctx context.Context, in *ChangeUserStatusReq, opts ...grpc.CallOption) (*ChangeUserStatusRes, error) {
+	out := new(ChangeUserStatusRes)
+	err := c.cc.Invoke(ctx, "/commandService.commandService/ChangeUserStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CommandServiceServer is the server API for CommandService service.
 // All implementations should embed UnimplementedCommandServiceServer
 // for forward compatibility
@@ -69,6 +89,8 @@ type CommandServiceServer interface {
 	CreateUser(context.Context, *CreateUserReq) (*CreateUserRes, error)
 	UpdateUser(context.Context, *UpdateUserReq) (*UpdateUserRes, error)
 	GetUserById(context.Context, *GetUserByIdReq) (*GetUserByIdRes, error)
+	ReserveIdentifiers(context.Context, *ReserveIdentifiersReq) (*ReserveIdentifiersRes, error)
+	ChangeUserStatus(context.Context, *ChangeUserStatusReq) (*ChangeUserStatusRes, error)
 }
 
 // UnimplementedCommandServiceServer should be embedded to have forward compatible implementations.
@@ -84,6 +106,12 @@ func (UnimplementedCommandServiceServer) UpdateUser(context.Context, *UpdateUser
 func (UnimplementedCommandServiceServer) GetUserById(context.Context, *GetUserByIdReq) (*GetUserByIdRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserById not implemented")
 }
+func (UnimplementedCommandServiceServer) ReserveIdentifiers(context.Context, *ReserveIdentifiersReq) (*ReserveIdentifiersRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveIdentifiers not implemented")
+}
+func (UnimplementedCommandServiceServer) ChangeUserStatus(context.Context, *ChangeUserStatusReq) (*ChangeUserStatusRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeUserStatus not implemented")
+}
 
 // UnsafeCommandServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to CommandServiceServer will
@@ -150,6 +178,42 @@ func _CommandService_GetUserById_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CommandService_ReserveIdentifiers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveIdentifiersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandServiceServer).ReserveIdentifiers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/commandService.commandService/ReserveIdentifiers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandServiceServer).ReserveIdentifiers(ctx, req.(*ReserveIdentifiersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandService_ChangeUserStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeUserStatusReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandServiceServer).ChangeUserStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/commandService.commandService/ChangeUserStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandServiceServer).ChangeUserStatus(ctx, req.(*ChangeUserStatusReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CommandService_ServiceDesc is the grpc.ServiceDesc for CommandService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -169,6 +233,14 @@ var CommandService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserById",
 			Handler:    _CommandService_GetUserById_Handler,
 		},
+		{
+			MethodName: "ReserveIdentifiers",
+			Handler:    _CommandService_ReserveIdentifiers_Handler,
+		},
+		{
+			MethodName: "ChangeUserStatus",
+			Handler:    _CommandService_ChangeUserStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user_command.proto",
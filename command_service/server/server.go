@@ -6,6 +6,7 @@ import (
 	"github.com/JECSand/identity-service/command_service/identity/commands"
 	grpc3 "github.com/JECSand/identity-service/command_service/identity/delivery/grpc"
 	kafkaConsumer "github.com/JECSand/identity-service/command_service/identity/delivery/kafka"
+	"github.com/JECSand/identity-service/command_service/identity/delivery/scheduler"
 	"github.com/JECSand/identity-service/command_service/identity/metrics"
 	"github.com/JECSand/identity-service/command_service/identity/repositories"
 	"github.com/JECSand/identity-service/command_service/identity/services"
@@ -14,35 +15,36 @@ import (
 	membershipCommandService "github.com/JECSand/identity-service/command_service/protos/membership_command"
 	commandService "github.com/JECSand/identity-service/command_service/protos/user_command"
 	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/certs"
 	"github.com/JECSand/identity-service/pkg/constants"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/interceptors"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/postgres"
+	"github.com/JECSand/identity-service/pkg/probes"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
-	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
-	"github.com/heptiolabs/healthcheck"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 	"net"
-	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
 	"syscall"
 	"time"
 )
@@ -65,16 +67,22 @@ type server struct {
 	groupService      *services.GroupService
 	membershipService *services.MembershipService
 	authService       *services.AuthService
+	orgService        *services.OrganizationService
+	roleService       *services.RoleService
 	im                interceptors.InterceptorManager
 	pgConn            *pgxpool.Pool
 	metrics           *metrics.CommandServiceMetrics
+	health            *probes.Health
+	lag               *messaging.LagMonitor
 }
 
 func NewServer(log logging.Logger, cfg *config.Config) *server {
 	return &server{
-		log: log,
-		cfg: cfg,
-		v:   validator.New(),
+		log:     log,
+		cfg:     cfg,
+		v:       validator.New(),
+		metrics: metrics.NewCommandServiceMetrics(cfg),
+		health:  probes.NewHealth(log, &cfg.Probes),
 	}
 }
 
@@ -93,20 +101,13 @@ func (s *server) connectKafkaBrokers(ctx context.Context) error {
 }
 
 func (s *server) initKafkaTopics(ctx context.Context) {
-	controller, err := s.kafkaConn.Controller()
+	conn, err := kafkaClient.NewControllerConn(ctx, s.cfg.Kafka, s.kafkaConn)
 	if err != nil {
-		s.log.WarnMsg("kafkaConn.Controller", err)
-		return
-	}
-	controllerURI := net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port))
-	s.log.Infof("kafka controller uri: %s", controllerURI)
-	conn, err := kafka.DialContext(ctx, "tcp", controllerURI)
-	if err != nil {
-		s.log.WarnMsg("initKafkaTopics.DialContext", err)
+		s.log.WarnMsg("initKafkaTopics.NewControllerConn", err)
 		return
 	}
 	defer conn.Close() // nolint: errCheck
-	s.log.Infof("established new kafka controller connection: %s", controllerURI)
+	s.log.Infof("established new kafka controller connection: %s", conn.RemoteAddr())
 	userCreateTopic := kafka.TopicConfig{
 		Topic:             s.cfg.KafkaTopics.UserCreate.TopicName,
 		NumPartitions:     s.cfg.KafkaTopics.UserCreate.Partitions,
@@ -137,6 +138,11 @@ func (s *server) initKafkaTopics(ctx context.Context) {
 		NumPartitions:     s.cfg.KafkaTopics.UserDeleted.Partitions,
 		ReplicationFactor: s.cfg.KafkaTopics.UserDeleted.ReplicationFactor,
 	}
+	userStatusChangedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.UserStatusChanged.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.UserStatusChanged.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.UserStatusChanged.ReplicationFactor,
+	}
 	groupCreateTopic := kafka.TopicConfig{
 		Topic:             s.cfg.KafkaTopics.GroupCreate.TopicName,
 		NumPartitions:     s.cfg.KafkaTopics.GroupCreate.Partitions,
@@ -167,6 +173,11 @@ func (s *server) initKafkaTopics(ctx context.Context) {
 		NumPartitions:     s.cfg.KafkaTopics.GroupDeleted.Partitions,
 		ReplicationFactor: s.cfg.KafkaTopics.GroupDeleted.ReplicationFactor,
 	}
+	groupParentChangedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.GroupParentChanged.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.GroupParentChanged.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.GroupParentChanged.ReplicationFactor,
+	}
 	membershipCreateTopic := kafka.TopicConfig{
 		Topic:             s.cfg.KafkaTopics.MembershipCreate.TopicName,
 		NumPartitions:     s.cfg.KafkaTopics.MembershipCreate.Partitions,
@@ -197,6 +208,21 @@ func (s *server) initKafkaTopics(ctx context.Context) {
 		NumPartitions:     s.cfg.KafkaTopics.MembershipDeleted.Partitions,
 		ReplicationFactor: s.cfg.KafkaTopics.MembershipDeleted.ReplicationFactor,
 	}
+	membershipExtendTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.MembershipExtend.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.MembershipExtend.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.MembershipExtend.ReplicationFactor,
+	}
+	membershipExtendedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.MembershipExtended.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.MembershipExtended.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.MembershipExtended.ReplicationFactor,
+	}
+	membershipExpiringTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.MembershipExpiring.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.MembershipExpiring.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.MembershipExpiring.ReplicationFactor,
+	}
 	tokenBlacklistTopic := kafka.TopicConfig{
 		Topic:             s.cfg.KafkaTopics.TokenBlacklist.TopicName,
 		NumPartitions:     s.cfg.KafkaTopics.TokenBlacklist.Partitions,
@@ -217,6 +243,76 @@ func (s *server) initKafkaTopics(ctx context.Context) {
 		NumPartitions:     s.cfg.KafkaTopics.PasswordUpdated.Partitions,
 		ReplicationFactor: s.cfg.KafkaTopics.PasswordUpdated.ReplicationFactor,
 	}
+	organizationCreateTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationCreate.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationCreate.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationCreate.ReplicationFactor,
+	}
+	organizationCreatedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationCreated.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationCreated.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationCreated.ReplicationFactor,
+	}
+	organizationUpdateTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationUpdate.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationUpdate.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationUpdate.ReplicationFactor,
+	}
+	organizationUpdatedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationUpdated.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationUpdated.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationUpdated.ReplicationFactor,
+	}
+	organizationStatusChangeTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationStatusChange.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationStatusChange.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationStatusChange.ReplicationFactor,
+	}
+	organizationStatusChangedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationStatusChanged.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationStatusChanged.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationStatusChanged.ReplicationFactor,
+	}
+	organizationDeleteTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationDelete.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationDelete.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationDelete.ReplicationFactor,
+	}
+	organizationDeletedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.OrganizationDeleted.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.OrganizationDeleted.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.OrganizationDeleted.ReplicationFactor,
+	}
+	roleCreateTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.RoleCreate.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.RoleCreate.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.RoleCreate.ReplicationFactor,
+	}
+	roleCreatedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.RoleCreated.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.RoleCreated.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.RoleCreated.ReplicationFactor,
+	}
+	roleUpdateTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.RoleUpdate.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.RoleUpdate.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.RoleUpdate.ReplicationFactor,
+	}
+	roleUpdatedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.RoleUpdated.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.RoleUpdated.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.RoleUpdated.ReplicationFactor,
+	}
+	roleDeleteTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.RoleDelete.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.RoleDelete.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.RoleDelete.ReplicationFactor,
+	}
+	roleDeletedTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.RoleDeleted.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.RoleDeleted.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.RoleDeleted.ReplicationFactor,
+	}
 	if err = conn.CreateTopics(
 		userCreateTopic,
 		userUpdateTopic,
@@ -224,22 +320,41 @@ func (s *server) initKafkaTopics(ctx context.Context) {
 		userUpdatedTopic,
 		userDeleteTopic,
 		userDeletedTopic,
+		userStatusChangedTopic,
 		groupCreateTopic,
 		groupUpdateTopic,
 		groupCreatedTopic,
 		groupUpdatedTopic,
 		groupDeleteTopic,
 		groupDeletedTopic,
+		groupParentChangedTopic,
 		membershipCreateTopic,
 		membershipUpdateTopic,
 		membershipCreatedTopic,
 		membershipUpdatedTopic,
 		membershipDeleteTopic,
 		membershipDeletedTopic,
+		membershipExtendTopic,
+		membershipExtendedTopic,
+		membershipExpiringTopic,
 		tokenBlacklistTopic,
 		tokenBlacklistedTopic,
 		passwordUpdateTopic,
 		passwordUpdatedTopic,
+		organizationCreateTopic,
+		organizationCreatedTopic,
+		organizationUpdateTopic,
+		organizationUpdatedTopic,
+		organizationStatusChangeTopic,
+		organizationStatusChangedTopic,
+		organizationDeleteTopic,
+		organizationDeletedTopic,
+		roleCreateTopic,
+		roleCreatedTopic,
+		roleUpdateTopic,
+		roleUpdatedTopic,
+		roleDeleteTopic,
+		roleDeletedTopic,
 	); err != nil {
 		s.log.WarnMsg("kafkaConn.CreateTopics", err)
 		return
@@ -251,22 +366,41 @@ func (s *server) initKafkaTopics(ctx context.Context) {
 		userUpdatedTopic,
 		userDeleteTopic,
 		userDeletedTopic,
+		userStatusChangedTopic,
 		groupCreateTopic,
 		groupUpdateTopic,
 		groupCreatedTopic,
 		groupUpdatedTopic,
 		groupDeleteTopic,
 		groupDeletedTopic,
+		groupParentChangedTopic,
 		membershipCreateTopic,
 		membershipUpdateTopic,
 		membershipCreatedTopic,
 		membershipUpdatedTopic,
 		membershipDeleteTopic,
 		membershipDeletedTopic,
+		membershipExtendTopic,
+		membershipExtendedTopic,
+		membershipExpiringTopic,
 		tokenBlacklistTopic,
 		tokenBlacklistedTopic,
 		passwordUpdateTopic,
 		passwordUpdatedTopic,
+		organizationCreateTopic,
+		organizationCreatedTopic,
+		organizationUpdateTopic,
+		organizationUpdatedTopic,
+		organizationStatusChangeTopic,
+		organizationStatusChangedTopic,
+		organizationDeleteTopic,
+		organizationDeletedTopic,
+		roleCreateTopic,
+		roleCreatedTopic,
+		roleUpdateTopic,
+		roleUpdatedTopic,
+		roleDeleteTopic,
+		roleDeletedTopic,
 	})
 }
 
@@ -281,32 +415,27 @@ func (s *server) getConsumerGroupTopics() []string {
 		s.cfg.KafkaTopics.MembershipCreate.TopicName,
 		s.cfg.KafkaTopics.MembershipUpdate.TopicName,
 		s.cfg.KafkaTopics.MembershipDelete.TopicName,
+		s.cfg.KafkaTopics.MembershipExtend.TopicName,
 		s.cfg.KafkaTopics.TokenBlacklist.TopicName,
 		s.cfg.KafkaTopics.PasswordUpdate.TopicName,
+		s.cfg.KafkaTopics.OrganizationCreate.TopicName,
+		s.cfg.KafkaTopics.OrganizationUpdate.TopicName,
+		s.cfg.KafkaTopics.OrganizationStatusChange.TopicName,
+		s.cfg.KafkaTopics.OrganizationDelete.TopicName,
+		s.cfg.KafkaTopics.RoleCreate.TopicName,
+		s.cfg.KafkaTopics.RoleUpdate.TopicName,
+		s.cfg.KafkaTopics.RoleDelete.TopicName,
 	}
 }
 
-func (s *server) runHealthCheck(ctx context.Context) {
-	health := healthcheck.NewHandler()
-	health.AddLivenessCheck(s.cfg.ServiceName, healthcheck.AsyncWithContext(ctx, func() error {
-		return nil
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	health.AddReadinessCheck(constants.Postgres, healthcheck.AsyncWithContext(ctx, func() error {
-		return s.pgConn.Ping(ctx)
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	health.AddReadinessCheck(constants.Kafka, healthcheck.AsyncWithContext(ctx, func() error {
-		_, err := s.kafkaConn.Brokers()
-		if err != nil {
-			return err
-		}
+func (s *server) runHealthCheck(ctx context.Context, pub messaging.Publisher) func() error {
+	s.health.AddLivenessCheck(ctx, s.cfg.ServiceName, func(context.Context) error {
 		return nil
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	go func() {
-		s.log.Infof("Command service Kubernetes probes listening on port: %s", s.cfg.Probes.Port)
-		if err := http.ListenAndServe(s.cfg.Probes.Port, health); err != nil {
-			s.log.WarnMsg("ListenAndServe", err)
-		}
-	}()
+	})
+	s.health.AddReadinessCheck(ctx, constants.Postgres, postgres.PoolCheck(s.pgConn))
+	s.health.AddReadinessCheck(ctx, constants.Kafka, probes.PingCheck(pub))
+	s.health.AddReadinessCheck(ctx, constants.ConsumerLag, probes.LagCheck(s.lag, s.cfg.Probes.MaxConsumerLag))
+	return s.health.Serve()
 }
 
 func (s *server) runMetrics(cancel context.CancelFunc) {
@@ -337,7 +466,7 @@ func (s *server) runInitializations(ctx context.Context) {
 		if err != nil {
 			s.log.WarnMsg("utilities.NewID", err)
 		}
-		command := commands.NewCreateUserCommand(id, r.Email, r.Username, r.Password, true, true)
+		command := commands.NewCreateUserCommand(id, r.Email, r.Username, r.Password, true, enums.ACTIVATED, uuid.Nil, false, "")
 		if err = s.v.StructCtx(ctx, command); err != nil {
 			s.log.WarnMsg("validate", err)
 		}
@@ -348,12 +477,14 @@ func (s *server) runInitializations(ctx context.Context) {
 	}
 }
 
-func (s *server) newCommandGrpcServer() (func() error, *grpc.Server, error) {
-	l, err := net.Listen("tcp", s.cfg.GRPC.Port)
+func (s *server) newCommandGrpcServer(ctx context.Context, l net.Listener) (*grpc.Server, error) {
+	creds, err := certs.ServerOption(ctx, s.log, &s.cfg.GRPC.TLS)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "net.Listen")
+		return nil, errors.Wrap(err, "certs.ServerOption")
 	}
 	grpcServer := grpc.NewServer(
+		creds,
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.KeepaliveParams(keepalive.ServerParameters{
 			MaxConnectionIdle: maxConnectionIdle * time.Minute,
 			Timeout:           gRPCTimeout * time.Second,
@@ -362,12 +493,14 @@ func (s *server) newCommandGrpcServer() (func() error, *grpc.Server, error) {
 		}),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			grpc_ctxtags.UnaryServerInterceptor(),
-			grpc_opentracing.UnaryServerInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
 			grpc_recovery.UnaryServerInterceptor(),
 			s.im.Logger,
+			s.im.ServiceAuthUnaryInterceptor,
 		)),
+		grpc.StreamInterceptor(s.im.ServiceAuthStreamInterceptor),
 	)
+	s.health.RegisterGrpc(ctx, grpcServer)
 	commandGrpcWriter := grpc3.NewCommandGrpcService(s.log, s.cfg, s.v, s.userService, s.authService, s.metrics)
 	commandService.RegisterCommandServiceServer(grpcServer, commandGrpcWriter)
 	grpc_prometheus.Register(grpcServer)
@@ -382,31 +515,36 @@ func (s *server) newCommandGrpcServer() (func() error, *grpc.Server, error) {
 		reflection.Register(grpcServer)
 	}
 	go func() {
-		s.log.Infof("Command gRPC server is listening on port: %s", s.cfg.GRPC.Port)
-		s.log.Fatal(grpcServer.Serve(l))
+		s.log.Infof("Command gRPC server is listening on: %s", l.Addr())
+		if err := grpcServer.Serve(l); err != nil {
+			s.log.Fatal(err)
+		}
 	}()
-	return l.Close, grpcServer, nil
+	return grpcServer, nil
 }
 
-func (s *server) Run() error {
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-	defer cancel()
-	s.im = interceptors.NewInterceptorManager(s.log, s.auth)
-	s.metrics = metrics.NewCommandServiceMetrics(s.cfg)
-	pgxConn, err := postgres.NewPostgresConn(s.cfg.Postgresql)
+// Start wires the services over repo and pub, consumes the command topics from sub and serves the command gRPC
+// API on l until the returned stop func is called or ctx is done
+func (s *server) Start(ctx context.Context, repo repositories.Repository, pub messaging.Publisher, sub messaging.Subscriber, l net.Listener) (func() error, error) {
+	hasher, err := authentication.NewPasswordHasher(&s.cfg.Password)
 	if err != nil {
-		return errors.Wrap(err, "postgresql.NewPostgresConn")
+		return nil, errors.Wrap(err, "authentication.NewPasswordHasher")
 	}
-	s.pgConn = pgxConn
-	s.log.Infof("postgres connected: %v", pgxConn.Stat().TotalConns())
-	defer pgxConn.Close()
-	kafkaProducer := kafkaClient.NewProducer(s.log, s.cfg.Kafka.Brokers)
-	defer kafkaProducer.Close() // nolint: errCheck
-	repo := repositories.NewRepository(s.log, s.cfg, pgxConn)
-	s.userService = services.NewUserService(s.log, s.cfg, repo, kafkaProducer)
-	s.groupService = services.NewGroupService(s.log, s.cfg, repo, kafkaProducer)
-	s.membershipService = services.NewMembershipService(s.log, s.cfg, repo, kafkaProducer)
-	s.authService = services.NewAuthService(s.log, s.cfg, repo, kafkaProducer)
+	policy, err := authentication.NewPasswordPolicy(&s.cfg.PasswordPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "authentication.NewPasswordPolicy")
+	}
+	serviceAuth, err := authentication.NewServiceAuthenticator(s.log, &s.cfg.ServiceAuth, grpc3.ServiceAccessRules())
+	if err != nil {
+		return nil, errors.Wrap(err, "authentication.NewServiceAuthenticator")
+	}
+	s.im = interceptors.NewInterceptorManager(s.log, s.auth, serviceAuth)
+	s.userService = services.NewUserService(s.log, s.cfg, repo, pub, hasher, policy)
+	s.groupService = services.NewGroupService(s.log, s.cfg, repo, pub)
+	s.membershipService = services.NewMembershipService(s.log, s.cfg, repo, pub)
+	s.authService = services.NewAuthService(s.log, s.cfg, repo, pub, hasher, policy)
+	s.orgService = services.NewOrganizationService(s.log, s.cfg, repo, pub)
+	s.roleService = services.NewRoleService(s.log, s.cfg, repo, pub)
 	identityMessageProcessor := kafkaConsumer.NewIdentityMessageProcessor(
 		s.log,
 		s.cfg,
@@ -415,16 +553,46 @@ func (s *server) Run() error {
 		s.groupService,
 		s.membershipService,
 		s.authService,
+		s.orgService,
+		s.roleService,
 		s.metrics,
 	)
-	s.log.Info("Starting Writer Kafka consumers")
-	cg := kafkaClient.NewConsumerGroup(s.cfg.Kafka.Brokers, s.cfg.Kafka.GroupID, s.log)
-	go cg.ConsumeTopic(ctx, s.getConsumerGroupTopics(), kafkaConsumer.PoolSize, identityMessageProcessor.ProcessMessages)
-	closeGrpcServer, grpcServer, err := s.newCommandGrpcServer()
+	s.log.Info("Starting Writer consumers")
+	s.lag = messaging.NewLagMonitor(sub)
+	go messaging.ConsumeTopics(ctx, s.log, s.lag, s.cfg.Kafka.GroupID, s.getConsumerGroupTopics(), kafkaConsumer.PoolSize, identityMessageProcessor.ProcessMessages)
+	if s.cfg.MembershipExpiry.Enabled {
+		go scheduler.NewMembershipScheduler(s.log, s.cfg, s.membershipService, s.metrics).Run(ctx)
+	}
+	grpcServer, err := s.newCommandGrpcServer(ctx, l)
 	if err != nil {
-		return errors.Wrap(err, "NewScmGrpcServer")
+		return nil, errors.Wrap(err, "newCommandGrpcServer")
+	}
+	s.runInitializations(ctx)
+	return func() error {
+		grpcServer.GracefulStop()
+		return nil
+	}, nil
+}
+
+func (s *server) Run() error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	pgxConn, err := postgres.NewPostgresConn(s.cfg.Postgresql)
+	if err != nil {
+		return errors.Wrap(err, "postgresql.NewPostgresConn")
+	}
+	s.pgConn = pgxConn
+	s.log.Infof("postgres connected: %v", pgxConn.Stat().TotalConns())
+	defer pgxConn.Close()
+	publisher, err := kafkaClient.NewPublisher(s.log, s.cfg.Kafka)
+	if err != nil {
+		return errors.Wrap(err, "kafka.NewPublisher")
+	}
+	defer publisher.Close() // nolint: errCheck
+	subscriber, err := kafkaClient.NewSubscriber(s.log, s.cfg.Kafka)
+	if err != nil {
+		return errors.Wrap(err, "kafka.NewSubscriber")
 	}
-	defer closeGrpcServer() // nolint: errCheck
 	if err = s.connectKafkaBrokers(ctx); err != nil {
 		return errors.Wrap(err, "s.connectKafkaBrokers")
 	}
@@ -432,18 +600,26 @@ func (s *server) Run() error {
 	if s.cfg.Kafka.InitTopics {
 		s.initKafkaTopics(ctx)
 	}
-	s.runHealthCheck(ctx)
 	s.runMetrics(cancel)
-	if s.cfg.Jaeger.Enable {
-		tracer, closer, err := tracing.NewJaegerTracer(s.cfg.Jaeger)
-		if err != nil {
-			return err
-		}
-		defer closer.Close() // nolint: errCheck
-		opentracing.SetGlobalTracer(tracer)
+	stopPprof := probes.ServePprof(s.log, &s.cfg.Probes)
+	defer stopPprof() // nolint: errCheck
+	stopTracing, err := tracing.NewTracerProvider(ctx, s.cfg.Tracing)
+	if err != nil {
+		return errors.Wrap(err, "tracing.NewTracerProvider")
 	}
-	s.runInitializations(ctx)
+	defer stopTracing() // nolint: errCheck
+	l, err := net.Listen("tcp", s.cfg.GRPC.Port)
+	if err != nil {
+		return errors.Wrap(err, "net.Listen")
+	}
+	stop, err := s.Start(ctx, repositories.NewRepository(s.log, s.cfg, pgxConn), publisher, subscriber, l)
+	if err != nil {
+		l.Close() // nolint: errCheck
+		return errors.Wrap(err, "Start")
+	}
+	stopProbes := s.runHealthCheck(ctx, publisher)
+	defer stopProbes() // nolint: errCheck
 	<-ctx.Done()
-	grpcServer.GracefulStop()
-	return nil
+	s.health.Drain(context.Background())
+	return stop()
 }
@@ -0,0 +1,209 @@
+package e2e
+
+import (
+	"fmt"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/dto"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/gofrs/uuid"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// the services register their prometheus collectors globally, so one harness is shared by the whole package
+var h *harness
+
+func TestMain(m *testing.M) {
+	var err error
+	if h, err = newHarness(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	h.Close()
+	os.Exit(code)
+}
+
+type account struct {
+	id    string
+	email string
+	token string
+}
+
+// register signs up a new user and waits until its projection accepts a login
+func register(t *testing.T, name string) *account {
+	t.Helper()
+	a := &account{email: fmt.Sprintf("%s-%s@example.com", name, newID(t)[:8])}
+	password := "Pa55word!" + name
+	var created dto.CreateUserResponseDTO
+	res := h.mustDo(t, http.MethodPost, "/api/v1/auth/register", "", &dto.CreateUserDTO{
+		Email:    a.email,
+		Username: name + "-" + a.email[len(name)+1:len(name)+9],
+		Password: password,
+	}, &created)
+	if res.Header.Get("Authorization") == "" {
+		t.Fatal("register: no token issued")
+	}
+	a.id = created.ID.String()
+	eventually(t, func() error {
+		var auth dto.AuthenticateResponse
+		res, err := h.do(http.MethodPost, "/api/v1/auth", "", &dto.AuthenticateDTO{Email: a.email, Password: password}, &auth)
+		if err != nil {
+			return err
+		}
+		if auth.User == nil || uuidOf(auth.User.ID) != a.id {
+			return fmt.Errorf("login returned user %+v, want %s", auth.User, a.id)
+		}
+		a.token = res.Header.Get("Authorization")
+		return nil
+	})
+	return a
+}
+
+func newID(t *testing.T) string {
+	t.Helper()
+	id, err := uuid.NewV4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id.String()
+}
+
+// uuidOf normalizes the object id backed ids returned by the read model to the canonical uuid form
+func uuidOf(id string) string {
+	parsed, err := uuid.FromString(id)
+	if err != nil {
+		return id
+	}
+	return parsed.String()
+}
+
+func createGroup(t *testing.T, owner *account, name string) string {
+	t.Helper()
+	var created dto.CreateGroupResponseDTO
+	h.mustDo(t, http.MethodPost, "/api/v1/groups", owner.token, &dto.CreateGroupDTO{
+		Name:        name,
+		Description: name + " description",
+		CreatorID:   uuid.FromStringOrNil(owner.id),
+	}, &created)
+	return created.ID.String()
+}
+
+func getGroup(groupID string, token string) (*dto.GroupResponse, error) {
+	var group dto.GroupResponse
+	if _, err := h.do(http.MethodGet, "/api/v1/groups/"+groupID, token, nil, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func groupUsers(groupID string, token string) (*dto.UserMembershipsListResponse, error) {
+	var list dto.UserMembershipsListResponse
+	if _, err := h.do(http.MethodGet, "/api/v1/groups/"+groupID+"/users", token, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func userGroups(userID string, token string) (*dto.GroupMembershipsListResponse, error) {
+	var list dto.GroupMembershipsListResponse
+	if _, err := h.do(http.MethodGet, "/api/v1/users/"+userID+"/groups", token, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func TestRegisterLoginAndValidate(t *testing.T) {
+	a := register(t, "alice")
+	var validated dto.ValidateResponse
+	h.mustDo(t, http.MethodGet, "/api/v1/auth", a.token, nil, &validated)
+	if validated.User == nil || uuidOf(validated.User.ID) != a.id {
+		t.Fatalf("validate returned %+v, want user %s", validated.User, a.id)
+	}
+	if validated.User.Email != a.email {
+		t.Fatalf("validate returned email %q, want %q", validated.User.Email, a.email)
+	}
+}
+
+func TestLoginRejectsWrongPassword(t *testing.T) {
+	a := register(t, "bob")
+	res, err := h.do(http.MethodPost, "/api/v1/auth", "", &dto.AuthenticateDTO{Email: a.email, Password: "wrong"}, nil)
+	if err == nil {
+		t.Fatalf("login with a wrong password succeeded: %d", res.StatusCode)
+	}
+}
+
+func TestGroupMembershipProjections(t *testing.T) {
+	owner := register(t, "carol")
+	member := register(t, "dave")
+	groupID := createGroup(t, owner, "engineering")
+	eventually(t, func() error {
+		group, err := getGroup(groupID, owner.token)
+		if err != nil {
+			return err
+		}
+		if group.Name != "engineering" || uuidOf(group.CreatorID) != owner.id {
+			return fmt.Errorf("group projection %+v", group)
+		}
+		return nil
+	})
+	var membership dto.CreateMembershipResponseDTO
+	h.mustDo(t, http.MethodPost, "/api/v1/memberships", owner.token, &dto.CreateMembershipDTO{
+		UserID:  uuid.FromStringOrNil(member.id),
+		GroupID: uuid.FromStringOrNil(groupID),
+		Status:  enums.ACTIVE,
+		Role:    enums.MEMBER,
+	}, &membership)
+	eventually(t, func() error {
+		list, err := groupUsers(groupID, owner.token)
+		if err != nil {
+			return err
+		}
+		for _, m := range list.UserMemberships {
+			if uuidOf(m.UserID) == member.id {
+				if m.Email != member.email {
+					return fmt.Errorf("group user email %q, want %q", m.Email, member.email)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("member %s not yet listed in group %s: %+v", member.id, groupID, list.UserMemberships)
+	})
+	eventually(t, func() error {
+		list, err := userGroups(member.id, member.token)
+		if err != nil {
+			return err
+		}
+		for _, m := range list.GroupMemberships {
+			if uuidOf(m.MembershipID) == membership.ID.String() {
+				if m.Name != "engineering" {
+					return fmt.Errorf("user group name %q, want engineering", m.Name)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("group %s not yet listed for user %s: %+v", groupID, member.id, list.GroupMemberships)
+	})
+}
+
+func TestProjectionsCatchUpAfterLag(t *testing.T) {
+	owner := register(t, "erin")
+	h.projections.Pause()
+	groupID := createGroup(t, owner, "operations")
+	// the command side accepted the write but the read model has not seen it yet
+	if group, err := getGroup(groupID, owner.token); err == nil {
+		h.projections.Resume()
+		t.Fatalf("group %+v visible while projections were paused", group)
+	}
+	h.projections.Resume()
+	eventually(t, func() error {
+		group, err := getGroup(groupID, owner.token)
+		if err != nil {
+			return err
+		}
+		if group.Name != "operations" {
+			return fmt.Errorf("group projection %+v", group)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,292 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	gatewayConfig "github.com/JECSand/identity-service/api_gateway_service/config"
+	"github.com/JECSand/identity-service/api_gateway_service/identity/controllers/access"
+	gatewayServer "github.com/JECSand/identity-service/api_gateway_service/server"
+	commandConfig "github.com/JECSand/identity-service/command_service/config"
+	"github.com/JECSand/identity-service/command_service/identity/repositories"
+	commandServer "github.com/JECSand/identity-service/command_service/server"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	queryConfig "github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/cache"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	queryServer "github.com/JECSand/identity-service/query_service/server"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	busPartitions   = 4
+	logLevel        = "error"
+	eventualTimeout = 10 * time.Second
+	pollInterval    = 25 * time.Millisecond
+)
+
+// harness runs the gateway, command and query services in-process over in-memory stores sharing one message bus
+type harness struct {
+	baseURL     string
+	client      *http.Client
+	projections *gatedSubscriber
+	cancel      context.CancelFunc
+	stops       []func() error
+}
+
+// newHarness starts the query, command and gateway services in that order, each on an ephemeral local port
+func newHarness() (*harness, error) {
+	qCfg, err := queryConfig.InitConfig("../query_service/config/config.yaml")
+	if err != nil {
+		return nil, err
+	}
+	cmdCfg, err := commandConfig.InitConfig("../command_service/config/config.yaml")
+	if err != nil {
+		return nil, err
+	}
+	gwCfg, err := gatewayConfig.InitConfig("../api_gateway_service/config/config.yaml")
+	if err != nil {
+		return nil, err
+	}
+	if err = authentication.GenerateServiceKeys(&qCfg.ServiceAuth, &cmdCfg.ServiceAuth, &gwCfg.ServiceAuth); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := messaging.NewMemoryBus(busPartitions)
+	h := &harness{
+		client:      &http.Client{Timeout: eventualTimeout},
+		projections: newGatedSubscriber(bus),
+		cancel:      cancel,
+	}
+	queryAddr, err := h.startQueryService(ctx, qCfg)
+	if err != nil {
+		h.Close()
+		return nil, errors.Wrap(err, "startQueryService")
+	}
+	commandAddr, err := h.startCommandService(ctx, cmdCfg, bus, bus)
+	if err != nil {
+		h.Close()
+		return nil, errors.Wrap(err, "startCommandService")
+	}
+	if err = h.startGateway(ctx, gwCfg, bus, bus, queryAddr, commandAddr); err != nil {
+		h.Close()
+		return nil, errors.Wrap(err, "startGateway")
+	}
+	return h, nil
+}
+
+func newLogger(cfg *logging.Config, name string) logging.Logger {
+	cfg.LogLevel = logLevel
+	logger := logging.NewAppLogger(cfg)
+	logger.InitLogger()
+	logger.WithName(name)
+	return logger
+}
+
+func (h *harness) startQueryService(ctx context.Context, cfg *queryConfig.Config) (string, error) {
+	log := newLogger(cfg.Logger, "QueryService")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errors.Wrap(err, "net.Listen")
+	}
+	s := queryServer.NewServer(log, cfg)
+	stop, err := s.Start(ctx, data.NewMemoryDatabase(log, cfg), cache.NewMemoryCache(log, cfg), h.projections, l)
+	if err != nil {
+		l.Close() // nolint: errCheck
+		return "", err
+	}
+	h.stops = append(h.stops, stop)
+	return l.Addr().String(), nil
+}
+
+func (h *harness) startCommandService(ctx context.Context, cfg *commandConfig.Config, pub messaging.Publisher, sub messaging.Subscriber) (string, error) {
+	cfg.PasswordPolicy.BreachedPasswordsPath = "../" + cfg.PasswordPolicy.BreachedPasswordsPath
+	log := newLogger(cfg.Logger, "CommandService")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errors.Wrap(err, "net.Listen")
+	}
+	s := commandServer.NewServer(log, cfg)
+	stop, err := s.Start(ctx, repositories.NewMemoryRepository(log, cfg), pub, sub, l)
+	if err != nil {
+		l.Close() // nolint: errCheck
+		return "", err
+	}
+	h.stops = append(h.stops, stop)
+	return l.Addr().String(), nil
+}
+
+func (h *harness) startGateway(ctx context.Context, cfg *gatewayConfig.Config, pub messaging.Publisher, sub messaging.Subscriber, queryAddr string, commandAddr string) error {
+	cfg.Grpc.QueryServicePort = queryAddr
+	cfg.Grpc.CommandServicePort = commandAddr
+	cfg.RateLimit.Enabled = false // the flows register and authenticate many users from one address
+	log := newLogger(cfg.Logger, "GatewayService")
+	authCfg := authentication.NewAuthConfig(1, 4380, cfg.ServiceSettings.JWTSalt)
+	auth := authentication.NewAuthenticator(log, access.DefaultAccessRules(), authCfg)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "net.Listen")
+	}
+	s := gatewayServer.NewServer(log, auth, cfg)
+	stop, err := s.Start(ctx, pub, sub, l)
+	if err != nil {
+		l.Close() // nolint: errCheck
+		return err
+	}
+	h.stops = append(h.stops, stop)
+	h.baseURL = "http://" + l.Addr().String()
+	return nil
+}
+
+// Close stops the services in reverse start order, then cancels their consumers
+func (h *harness) Close() {
+	h.projections.Resume()
+	for i := len(h.stops) - 1; i >= 0; i-- {
+		h.stops[i]() // nolint: errCheck
+	}
+	h.cancel()
+}
+
+// do sends a JSON request to the gateway and decodes a JSON response into out when it is non-nil
+func (h *harness) do(method string, path string, token string, body interface{}, out interface{}) (*http.Response, error) {
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "json.Marshal")
+		}
+		payload = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, h.baseURL+path, payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "http.NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "client.Do")
+	}
+	defer res.Body.Close() // nolint: errCheck
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res, errors.Wrap(err, "io.ReadAll")
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return res, fmt.Errorf("%s %s: %d %s", method, path, res.StatusCode, b)
+	}
+	if out != nil {
+		if err = json.Unmarshal(b, out); err != nil {
+			return res, errors.Wrapf(err, "json.Unmarshal %s", b)
+		}
+	}
+	return res, nil
+}
+
+// mustDo is do failing the test on any transport, status or decoding error
+func (h *harness) mustDo(t *testing.T, method string, path string, token string, body interface{}, out interface{}) *http.Response {
+	t.Helper()
+	res, err := h.do(method, path, token, body, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// eventually polls check until it returns nil, failing the test with the last error after eventualTimeout
+func eventually(t *testing.T, check func() error) {
+	t.Helper()
+	deadline := time.Now().Add(eventualTimeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met after %s: %v", eventualTimeout, err)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// gatedSubscriber lets tests hold back projection updates: while paused, fetched messages are not handed to the
+// consumer until Resume, which is how a slow or lagging read model looks to the gateway
+type gatedSubscriber struct {
+	messaging.Subscriber
+	mu     sync.Mutex
+	open   chan struct{}
+	paused bool
+}
+
+func newGatedSubscriber(sub messaging.Subscriber) *gatedSubscriber {
+	open := make(chan struct{})
+	close(open)
+	return &gatedSubscriber{Subscriber: sub, open: open}
+}
+
+// Pause holds back every message fetched from now on
+func (g *gatedSubscriber) Pause() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused {
+		g.paused = true
+		g.open = make(chan struct{})
+	}
+}
+
+// Resume releases the held back messages
+func (g *gatedSubscriber) Resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		g.paused = false
+		close(g.open)
+	}
+}
+
+func (g *gatedSubscriber) wait(ctx context.Context) error {
+	g.mu.Lock()
+	open := g.open
+	g.mu.Unlock()
+	select {
+	case <-open:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *gatedSubscriber) Subscribe(ctx context.Context, groupID string, topics []string) (messaging.Reader, error) {
+	r, err := g.Subscriber.Subscribe(ctx, groupID, topics)
+	if err != nil {
+		return nil, err
+	}
+	return &gatedReader{Reader: r, gate: g}, nil
+}
+
+type gatedReader struct {
+	messaging.Reader
+	gate *gatedSubscriber
+}
+
+func (r *gatedReader) FetchMessage(ctx context.Context) (messaging.Message, error) {
+	m, err := r.Reader.FetchMessage(ctx)
+	if err != nil {
+		return m, err
+	}
+	if err = r.gate.wait(ctx); err != nil {
+		return messaging.Message{}, err
+	}
+	return m, nil
+}
@@ -1,7 +1,8 @@
 use identity
 
 db.users.stats()
-db.users.createIndex({ email: 1 });
+db.users.createIndex({ email: 1 }, { unique: true });
+db.users.createIndex({ username: 1 }, { unique: true });
 db.users.createIndex({ '$**': 'text' });
 db.users.getIndexes();
 
@@ -12,12 +13,14 @@ db.blacklists.getIndexes();
 
 db.user_groups.stats()
 db.user_groups.createIndex({ creator_id: 1 });
+db.user_groups.createIndex({ parent_id: 1 });
 db.user_groups.createIndex({ '$**': 'text' });
 db.user_groups.getIndexes();
 
 db.memberships.stats()
 db.memberships.createIndex({ group_id: 1 });
 db.memberships.createIndex({ user_id: 1 });
+db.memberships.createIndex({ role_id: 1 });
 db.memberships.createIndex({ '$**': 'text' });
 db.memberships.getIndexes();
 
@@ -25,6 +28,7 @@ db.user_memberships.stats()
 db.user_memberships.createIndex({ membership_id: 1 });
 db.user_memberships.createIndex({ user_id: 1 });
 db.user_memberships.createIndex({ group_id: 1 });
+db.user_memberships.createIndex({ role_id: 1 });
 db.user_memberships.createIndex({ '$**': 'text' });
 db.user_memberships.getIndexes();
 
@@ -32,5 +36,17 @@ db.group_memberships.stats()
 db.group_memberships.createIndex({ membership_id: 1 });
 db.group_memberships.createIndex({ group_id: 1 });
 db.group_memberships.createIndex({ user_id: 1 });
+db.group_memberships.createIndex({ role_id: 1 });
 db.group_memberships.createIndex({ '$**': 'text' });
 db.group_memberships.getIndexes();
+
+db.effective_memberships.stats()
+db.effective_memberships.createIndex({ user_id: 1 });
+db.effective_memberships.createIndex({ group_id: 1 });
+db.effective_memberships.getIndexes();
+
+db.roles.stats()
+db.roles.createIndex({ organization_id: 1 });
+db.roles.createIndex({ group_id: 1 });
+db.roles.createIndex({ '$**': 'text' });
+db.roles.getIndexes();
@@ -1,5 +1,10 @@
 DROP TABLE IF EXISTS users CASCADE;
 DROP TABLE IF EXISTS user_groups CASCADE;
 DROP TABLE IF EXISTS memberships CASCADE;
+DROP TABLE IF EXISTS membership_audit CASCADE;
+DROP TABLE IF EXISTS roles CASCADE;
 DROP TABLE IF EXISTS blacklists CASCADE;
+DROP TABLE IF EXISTS password_history CASCADE;
+DROP TABLE IF EXISTS identifier_reservations CASCADE;
+DROP TABLE IF EXISTS organizations CASCADE;
 DROP EXTENSION IF EXISTS citext CASCADE;
\ No newline at end of file
@@ -1,23 +1,47 @@
 CREATE EXTENSION IF NOT EXISTS citext;
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+DROP TABLE IF EXISTS organizations CASCADE;
 DROP TABLE IF EXISTS users CASCADE;
 DROP TABLE IF EXISTS user_groups CASCADE;
 DROP TABLE IF EXISTS memberships CASCADE;
+DROP TABLE IF EXISTS membership_audit CASCADE;
+DROP TABLE IF EXISTS roles CASCADE;
 DROP TABLE IF EXISTS blacklists CASCADE;
+DROP TABLE IF EXISTS password_history CASCADE;
+DROP TABLE IF EXISTS identifier_reservations CASCADE;
 
 
+CREATE TABLE organizations
+(
+    id                  UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    org_name            VARCHAR(250)  NOT NULL CHECK ( org_name <> '' ),
+    description         VARCHAR(250)  NOT NULL DEFAULT '',
+    active              BOOLEAN       NOT NULL,
+    sessions_revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE users
 (
     id              UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
-    username        VARCHAR(250)  NOT NULL CHECK ( username <> '' ),
-    email           VARCHAR(250)  NOT NULL CHECK ( email <> '' ),
+    username        CITEXT        NOT NULL UNIQUE CHECK ( username <> '' AND length(username) <= 250 ),
+    email           CITEXT        NOT NULL UNIQUE CHECK ( email <> '' AND length(email) <= 250 ),
     password        VARCHAR(250) NOT NULL CHECK ( password <> '' ),
     root            BOOLEAN       NOT NULL,
     active          BOOLEAN       NOT NULL,
+    status          INTEGER       NOT NULL DEFAULT 2,
+    status_reason   VARCHAR(250)  NOT NULL DEFAULT '',
+    suspended_until TIMESTAMP WITH TIME ZONE,
+    sessions_revoked_at TIMESTAMP WITH TIME ZONE,
+    organization_id UUID          NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    org_admin       BOOLEAN       NOT NULL DEFAULT FALSE,
     created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE INDEX users_organization_id_idx ON users (organization_id);
+
 CREATE TABLE user_groups
 (
     id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -25,11 +49,40 @@ CREATE TABLE user_groups
     description VARCHAR(250) NOT NULL CHECK ( description <> '' ),
     creator_id  UUID NOT NULL,
     active      BOOLEAN       NOT NULL,
+    organization_id UUID      NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    parent_id   UUID          NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
     created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (creator_id) REFERENCES users(id)
 );
 
+CREATE INDEX user_groups_organization_id_idx ON user_groups (organization_id);
+
+CREATE INDEX user_groups_parent_id_idx ON user_groups (parent_id);
+
+CREATE TABLE roles
+(
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    role_name       VARCHAR(250)  NOT NULL CHECK ( role_name <> '' ),
+    description     VARCHAR(250)  NOT NULL DEFAULT '',
+    permissions     TEXT[]        NOT NULL DEFAULT '{}',
+    group_id        UUID          NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    organization_id UUID          NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    built_in        BOOLEAN       NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX roles_scope_name_key ON roles (organization_id, group_id, lower(role_name));
+
+CREATE INDEX roles_group_id_idx ON roles (group_id);
+
+INSERT INTO roles (id, role_name, description, permissions, built_in) VALUES
+    ('00000000-0000-4000-8000-000000000001', 'MEMBER', 'Built-in MEMBER role', '{group.read,membership.read,role.read}', TRUE),
+    ('00000000-0000-4000-8000-000000000002', 'ADMIN', 'Built-in ADMIN role',
+     '{group.read,group.update,membership.read,membership.create,membership.update,membership.delete,role.read}', TRUE),
+    ('00000000-0000-4000-8000-000000000003', 'ROOT', 'Built-in ROOT role', '{*}', TRUE);
+
 CREATE TABLE memberships
 (
     id          UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
@@ -37,15 +90,63 @@ CREATE TABLE memberships
     group_id    UUID NOT NULL,
     status      INTEGER       NOT NULL,
     member_role INTEGER       NOT NULL,
+    role_id     UUID          NOT NULL DEFAULT '00000000-0000-4000-8000-000000000001',
+    organization_id UUID      NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    valid_from       TIMESTAMP WITH TIME ZONE,
+    valid_until      TIMESTAMP WITH TIME ZONE,
+    expiry_warned_at TIMESTAMP WITH TIME ZONE,
+    scheduled_start  BOOLEAN NOT NULL DEFAULT FALSE,
     created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (user_id) REFERENCES users(id),
-    FOREIGN KEY (group_id) REFERENCES user_groups(id)
+    FOREIGN KEY (group_id) REFERENCES user_groups(id),
+    FOREIGN KEY (role_id) REFERENCES roles(id)
+);
+
+CREATE INDEX memberships_organization_id_idx ON memberships (organization_id);
+
+CREATE INDEX memberships_role_id_idx ON memberships (role_id);
+
+CREATE INDEX memberships_valid_until_idx ON memberships (valid_until) WHERE valid_until IS NOT NULL;
+
+CREATE TABLE membership_audit
+(
+    id                   UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    membership_id        UUID         NOT NULL,
+    action               VARCHAR(32)  NOT NULL CHECK ( action <> '' ),
+    actor_id             UUID         NOT NULL,
+    reason               VARCHAR(500) NOT NULL DEFAULT '',
+    previous_valid_until TIMESTAMP WITH TIME ZONE,
+    valid_until          TIMESTAMP WITH TIME ZONE,
+    created_at           TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE INDEX membership_audit_membership_id_idx ON membership_audit (membership_id, created_at);
+
 CREATE TABLE blacklists
 (
     id                 UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
     access_token       VARCHAR(2500)  NOT NULL CHECK ( access_token <> '' ),
     created_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
+
+CREATE TABLE password_history
+(
+    user_id     UUID NOT NULL,
+    password    VARCHAR(250) NOT NULL CHECK ( password <> '' ),
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX password_history_user_id_idx ON password_history (user_id, created_at);
+
+CREATE TABLE identifier_reservations
+(
+    kind        VARCHAR(16)  NOT NULL,
+    identifier  CITEXT       NOT NULL CHECK ( identifier <> '' ),
+    user_id     UUID         NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (kind, identifier)
+);
+
+CREATE INDEX identifier_reservations_user_id_idx ON identifier_reservations (user_id);
\ No newline at end of file
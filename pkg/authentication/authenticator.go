@@ -60,6 +60,10 @@ func (i *authenticator) authorize(accessToken string, accessRule enums.Role) (*S
 		if session.RootAdmin {
 			authorized = true
 		}
+	case enums.ADMIN:
+		if session.RootAdmin || session.OrgAdmin {
+			authorized = true
+		}
 	case enums.MEMBER:
 		authorized = true
 	}
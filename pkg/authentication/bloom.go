@@ -0,0 +1,60 @@
+package authentication
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// bloomFilter is a fixed size bloom filter indexed by double hashing a 16+ byte digest
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64
+	hashes uint64
+}
+
+// newBloomFilter sizes a bloom filter for n items at false positive rate p
+func newBloomFilter(n int, p float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits:   make([]uint64, (m+63)/64),
+		m:      m,
+		hashes: k,
+	}
+}
+
+// Add sets the bits for digest
+func (b *bloomFilter) Add(digest []byte) {
+	h1, h2 := splitDigest(digest)
+	for i := uint64(0); i < b.hashes; i++ {
+		idx := (h1 + i*h2) % b.m
+		b.bits[idx/64] |= 1 << (idx % 64)
+	}
+}
+
+// Test reports whether digest may have been added; false means it definitely was not
+func (b *bloomFilter) Test(digest []byte) bool {
+	h1, h2 := splitDigest(digest)
+	for i := uint64(0); i < b.hashes; i++ {
+		idx := (h1 + i*h2) % b.m
+		if b.bits[idx/64]&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func splitDigest(digest []byte) (uint64, uint64) {
+	h1 := binary.BigEndian.Uint64(digest[0:8])
+	h2 := binary.BigEndian.Uint64(digest[8:16]) | 1
+	return h1, h2
+}
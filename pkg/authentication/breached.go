@@ -0,0 +1,172 @@
+package authentication
+
+import (
+	"bufio"
+	"container/list"
+	"crypto/sha1"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// breachedPrefixLength is the SHA-1 hex prefix length breached hashes are bucketed by, as in the k-anonymity
+	// range API
+	breachedPrefixLength = 5
+	// defaultBreachedRangeCacheSize is the number of range files kept in memory when none is configured
+	defaultBreachedRangeCacheSize = 256
+)
+
+// breachedCorpus returns the sorted hash suffixes of the breached passwords whose SHA-1 starts with an upper case
+// hex prefix
+type breachedCorpus interface {
+	Range(prefix string) ([]string, error)
+}
+
+// breachedPasswords is a corpus held in memory, bucketed by hash prefix
+type breachedPasswords map[string][]string
+
+func (b breachedPasswords) Range(prefix string) ([]string, error) {
+	return b[prefix], nil
+}
+
+// loadBreachedPasswords reads the file of full hashes at path into memory
+func loadBreachedPasswords(path string) (breachedPasswords, error) {
+	breached := make(breachedPasswords)
+	if err := readBreachedPasswords(breached, path, ""); err != nil {
+		return nil, err
+	}
+	for _, suffixes := range breached {
+		sort.Strings(suffixes)
+	}
+	return breached, nil
+}
+
+// breachedRanges is a directory of k-anonymity range files named after their hash prefix, such as 5BAA6.txt. The file
+// of a prefix is read when a password of that prefix is checked, and the suffixes of the most recently read files are
+// kept in memory
+type breachedRanges struct {
+	dir   string
+	upper bool
+	ext   string
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type breachedRange struct {
+	prefix   string
+	suffixes []string
+}
+
+// openBreachedRanges opens the range files of dir, taking the case and extension of their names from the first one
+// found, and keeps up to size of them in memory
+func openBreachedRanges(dir string, size int) (*breachedRanges, error) {
+	if size <= 0 {
+		size = defaultBreachedRangeCacheSize
+	}
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "os.Open")
+	}
+	defer f.Close() // nolint: errCheck
+	for {
+		entries, err := f.ReadDir(64)
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			prefix := strings.TrimSuffix(entry.Name(), ext)
+			if entry.IsDir() || len(prefix) != breachedPrefixLength || !isHex(prefix) {
+				continue
+			}
+			return &breachedRanges{
+				dir:   dir,
+				upper: prefix != strings.ToLower(prefix),
+				ext:   ext,
+				size:  size,
+				ll:    list.New(),
+				items: make(map[string]*list.Element),
+			}, nil
+		}
+		if err == io.EOF {
+			return nil, errors.Errorf("%s: no range files", dir)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "f.ReadDir")
+		}
+	}
+}
+
+// Range returns the suffixes of the range file of prefix; a missing file is an empty range
+func (b *breachedRanges) Range(prefix string) ([]string, error) {
+	b.mu.Lock()
+	if el, ok := b.items[prefix]; ok {
+		b.ll.MoveToFront(el)
+		b.mu.Unlock()
+		return el.Value.(*breachedRange).suffixes, nil
+	}
+	b.mu.Unlock()
+	name := strings.ToLower(prefix)
+	if b.upper {
+		name = prefix
+	}
+	breached := make(breachedPasswords)
+	err := readBreachedPasswords(breached, filepath.Join(b.dir, name+b.ext), prefix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	suffixes := breached[prefix]
+	sort.Strings(suffixes)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.items[prefix]; !ok {
+		b.items[prefix] = b.ll.PushFront(&breachedRange{prefix: prefix, suffixes: suffixes})
+		if b.ll.Len() > b.size {
+			oldest := b.ll.Back()
+			b.ll.Remove(oldest)
+			delete(b.items, oldest.Value.(*breachedRange).prefix)
+		}
+	}
+	return suffixes, nil
+}
+
+// readBreachedPasswords reads the hashes of the file at path into breached, prefixing each line with prefix; lines
+// with a zero count are the padding of range responses and skipped
+func readBreachedPasswords(breached breachedPasswords, path string, prefix string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "os.Open")
+	}
+	defer f.Close() // nolint: errCheck
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		hash, count, _ := strings.Cut(text, ":")
+		if strings.TrimSpace(count) == "0" {
+			continue
+		}
+		hash = prefix + strings.ToUpper(strings.TrimSpace(hash))
+		if len(hash) != 2*sha1.Size || !isHex(hash) {
+			return errors.Errorf("%s:%d: not a SHA-1 hex hash", path, line)
+		}
+		breached[hash[:breachedPrefixLength]] = append(breached[hash[:breachedPrefixLength]], hash[breachedPrefixLength:])
+	}
+	return errors.Wrap(scanner.Err(), "scanner.Scan")
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !unicode.Is(unicode.ASCII_Hex_Digit, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package authentication
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func writeFile(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestBreachedPasswordCorpus(t *testing.T) {
+	breached, padded := sha1Hex("password"), sha1Hex("letmein")
+	file := filepath.Join(t.TempDir(), "breached.txt")
+	writeFile(t, file, "# comment", strings.ToLower(breached)+":3730471", padded+":0")
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, breached[:5]+".txt"), breached[5:]+":3730471", padded[5:]+":0")
+	writeFile(t, filepath.Join(dir, padded[:5]+".txt"), padded[5:]+":0")
+	for name, path := range map[string]string{"file": file, "range directory": dir} {
+		t.Run(name, func(t *testing.T) {
+			policy, err := NewPasswordPolicy(&PasswordPolicyConfig{Enabled: true, BreachedPasswordsPath: path})
+			if err != nil {
+				t.Fatalf("NewPasswordPolicy: %v", err)
+			}
+			tests := []struct {
+				password string
+				want     bool
+			}{
+				{password: "password", want: true},
+				{password: "letmein"},
+				{password: "Correct-Horse-Battery-9"},
+			}
+			for _, tt := range tests {
+				got, err := policy.isBreached(tt.password)
+				if err != nil {
+					t.Fatalf("isBreached(%q): %v", tt.password, err)
+				}
+				if got != tt.want {
+					t.Errorf("isBreached(%q) = %v, want %v", tt.password, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBreachedRangesReadOnDemand(t *testing.T) {
+	dir := t.TempDir()
+	hashes := []string{sha1Hex("password"), sha1Hex("123456"), sha1Hex("qwerty")}
+	writeFile(t, filepath.Join(dir, strings.ToLower(hashes[0][:5])), strings.ToLower(hashes[0][5:])+":1")
+	ranges, err := openBreachedRanges(dir, 2)
+	if err != nil {
+		t.Fatalf("openBreachedRanges: %v", err)
+	}
+	// range files written after opening are read when their prefix is first checked
+	for _, hash := range hashes[1:] {
+		writeFile(t, filepath.Join(dir, strings.ToLower(hash[:5])), hash[5:]+":1")
+	}
+	for _, hash := range hashes {
+		suffixes, err := ranges.Range(hash[:5])
+		if err != nil {
+			t.Fatalf("Range(%s): %v", hash[:5], err)
+		}
+		if len(suffixes) != 1 || suffixes[0] != hash[5:] {
+			t.Errorf("Range(%s) = %v, want [%s]", hash[:5], suffixes, hash[5:])
+		}
+	}
+	if ranges.ll.Len() != 2 {
+		t.Errorf("kept %d ranges in memory, want 2", ranges.ll.Len())
+	}
+	if _, ok := ranges.items[hashes[0][:5]]; ok {
+		t.Errorf("least recently used range %s was kept", hashes[0][:5])
+	}
+	if suffixes, err := ranges.Range("00000"); err != nil || len(suffixes) != 0 {
+		t.Errorf("missing range: got %v, %v, want an empty range", suffixes, err)
+	}
+	writeFile(t, filepath.Join(dir, "fffff"), "not a hash:1")
+	if _, err := ranges.Range("FFFFF"); err == nil {
+		t.Error("malformed range file accepted")
+	}
+}
+
+func TestOpenBreachedRangesRequiresRangeFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "README.md"), "no ranges here")
+	if _, err := openBreachedRanges(dir, 0); err == nil {
+		t.Error("directory without range files accepted")
+	}
+	if _, err := NewPasswordPolicy(&PasswordPolicyConfig{Enabled: true, BreachedPasswordsPath: filepath.Join(dir, "missing")}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing corpus: got %v, want %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,38 @@
+package authentication
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+)
+
+// Login identifiers that must be unique across users
+const (
+	IdentifierEmail    = "email"
+	IdentifierUsername = "username"
+)
+
+// NormalizeEmail returns the canonical form an email is stored, compared and reserved in
+func NormalizeEmail(email string) string {
+	return normalizeIdentifier(email)
+}
+
+// NormalizeUsername returns the canonical form a username is stored, compared and reserved in
+func NormalizeUsername(username string) string {
+	return normalizeIdentifier(username)
+}
+
+// normalizeIdentifier applies NFKC_Casefold: compatibility characters, full-width forms and case variants of the same
+// identifier all map to one string
+func normalizeIdentifier(identifier string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(strings.TrimSpace(identifier))))
+}
+
+// IdentifierConflictError lists the identifiers already taken by another user
+type IdentifierConflictError struct {
+	Identifiers []string `json:"identifiers"`
+}
+
+func (e *IdentifierConflictError) Error() string {
+	return "identifier conflict: " + strings.Join(e.Identifiers, ", ") + " already taken"
+}
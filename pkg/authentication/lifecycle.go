@@ -0,0 +1,63 @@
+package authentication
+
+import (
+	"fmt"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"time"
+)
+
+// UserStatusError reports that a user's lifecycle status does not allow it to sign in or use its sessions
+type UserStatusError struct {
+	Status enums.UserStatus
+	Reason string
+	Until  time.Time
+}
+
+func (e *UserStatusError) Error() string {
+	msg := "user status " + e.Status.Stringify()
+	if !e.Until.IsZero() {
+		msg += " until " + e.Until.UTC().Format(time.RFC3339)
+	}
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// UserStatusTransitionError reports a lifecycle change the user's current status does not allow
+type UserStatusTransitionError struct {
+	From enums.UserStatus
+	To   enums.UserStatus
+}
+
+func (e *UserStatusTransitionError) Error() string {
+	return fmt.Sprintf("invalid user status transition from %s to %s", e.From.Stringify(), e.To.Stringify())
+}
+
+// EffectiveUserStatus returns the status a user is in at now: a suspension whose end date passed counts as active
+func EffectiveUserStatus(status enums.UserStatus, suspendedUntil time.Time, now time.Time) enums.UserStatus {
+	if status == enums.SUSPENDED && !suspendedUntil.IsZero() && !now.Before(suspendedUntil) {
+		return enums.ACTIVATED
+	}
+	return status
+}
+
+// CheckUserStatus returns a *UserStatusError unless a user in status may sign in at now
+func CheckUserStatus(status enums.UserStatus, reason string, suspendedUntil time.Time, now time.Time) error {
+	effective := EffectiveUserStatus(status, suspendedUntil, now)
+	if effective == enums.ACTIVATED {
+		return nil
+	}
+	statusErr := &UserStatusError{Status: effective}
+	if effective == enums.SUSPENDED {
+		statusErr.Reason = reason
+		statusErr.Until = suspendedUntil
+	}
+	return statusErr
+}
+
+// SessionRevoked reports whether a session issued at issuedAt was ended by a revocation of all the user's sessions at
+// revokedAt; the token iat claim has second precision, so a session issued in the revocation's second is revoked too
+func SessionRevoked(issuedAt time.Time, revokedAt time.Time) bool {
+	return !revokedAt.IsZero() && !issuedAt.After(revokedAt.Truncate(time.Second))
+}
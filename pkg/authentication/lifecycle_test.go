@@ -0,0 +1,97 @@
+package authentication
+
+import (
+	"errors"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"testing"
+	"time"
+)
+
+func TestCheckUserStatus(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		status         enums.UserStatus
+		suspendedUntil time.Time
+		wantStatus     enums.UserStatus
+	}{
+		{name: "active", status: enums.ACTIVATED},
+		{name: "invited", status: enums.INVITED, wantStatus: enums.INVITED},
+		{name: "suspended", status: enums.SUSPENDED, wantStatus: enums.SUSPENDED},
+		{name: "suspended until later", status: enums.SUSPENDED, suspendedUntil: now.Add(time.Hour), wantStatus: enums.SUSPENDED},
+		{name: "suspension ended", status: enums.SUSPENDED, suspendedUntil: now},
+		{name: "deactivated", status: enums.DEACTIVATED, wantStatus: enums.DEACTIVATED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckUserStatus(tt.status, "reason", tt.suspendedUntil, now)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Errorf("CheckUserStatus: %v", err)
+				}
+				return
+			}
+			var statusErr *UserStatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("CheckUserStatus: got %v, want a *UserStatusError", err)
+			}
+			if statusErr.Status != tt.wantStatus {
+				t.Errorf("got status %s, want %s", statusErr.Status.Stringify(), tt.wantStatus.Stringify())
+			}
+		})
+	}
+}
+
+func TestSessionRevoked(t *testing.T) {
+	revokedAt := time.Date(2030, 1, 1, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+	tests := []struct {
+		name      string
+		issuedAt  time.Time
+		revokedAt time.Time
+		want      bool
+	}{
+		{name: "never revoked", issuedAt: revokedAt.Add(-time.Hour)},
+		{name: "issued before", issuedAt: revokedAt.Add(-time.Hour), revokedAt: revokedAt, want: true},
+		{name: "issued in the same second", issuedAt: revokedAt.Truncate(time.Second), revokedAt: revokedAt, want: true},
+		{name: "issued after", issuedAt: revokedAt.Truncate(time.Second).Add(time.Second), revokedAt: revokedAt},
+		{name: "without iat", revokedAt: revokedAt, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SessionRevoked(tt.issuedAt, tt.revokedAt); got != tt.want {
+				t.Errorf("SessionRevoked: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevocationListSessions(t *testing.T) {
+	revokedAt := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	before, after := revokedAt.Add(-time.Minute), revokedAt.Add(time.Minute)
+	r := NewRevocationList(100, 0.01)
+	r.RevokeUserSessions("user", revokedAt)
+	r.RevokeUserSessions("user", revokedAt.Add(-time.Hour))
+	r.RevokeUserSessions("other", time.Time{})
+	r.RevokeOrganizationSessions("org", revokedAt)
+	r.RevokeOrganizationSessions("org", time.Time{})
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{name: "user session issued before", got: r.IsSessionRevoked("user", before), want: true},
+		{name: "user session issued after", got: r.IsSessionRevoked("user", after)},
+		{name: "earlier revocation is ignored", got: r.IsSessionRevoked("user", revokedAt.Add(-30*time.Minute)), want: true},
+		{name: "zero revocation is ignored", got: r.IsSessionRevoked("other", before)},
+		{name: "unknown user", got: r.IsSessionRevoked("unknown", before)},
+		{name: "organization session issued before", got: r.IsOrganizationSessionRevoked("org", before), want: true},
+		{name: "organization session issued after", got: r.IsOrganizationSessionRevoked("org", after)},
+		{name: "unknown organization", got: r.IsOrganizationSessionRevoked("unknown", before)},
+		{name: "no organization", got: r.IsOrganizationSessionRevoked("", before)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
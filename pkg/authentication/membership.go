@@ -0,0 +1,50 @@
+package authentication
+
+import (
+	"errors"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"time"
+)
+
+var (
+	// ErrMembershipWindow is returned for membership validity windows that end before they start or have already ended
+	ErrMembershipWindow = errors.New("invalid membership validity window")
+	// ErrPendingMembershipStart is returned for memberships created PENDING with a validity window that already started
+	ErrPendingMembershipStart = errors.New("invalid membership validity window: a pending membership cannot start in the past")
+)
+
+// CheckMembershipStart returns ErrPendingMembershipStart for a membership explicitly created PENDING, for example to
+// await approval, whose validFrom is not in the future; only memberships created ACTIVE are started on schedule
+func CheckMembershipStart(status enums.MembershipStatus, validFrom time.Time, now time.Time) error {
+	if status == enums.PENDING && !validFrom.IsZero() && !validFrom.After(now) {
+		return ErrPendingMembershipStart
+	}
+	return nil
+}
+
+// CheckMembershipWindow returns ErrMembershipWindow unless a membership valid from validFrom until validUntil ends
+// after it starts and after now; a zero time leaves its side of the window open
+func CheckMembershipWindow(validFrom time.Time, validUntil time.Time, now time.Time) error {
+	if validUntil.IsZero() {
+		return nil
+	}
+	if !validUntil.After(now) || (!validFrom.IsZero() && !validUntil.After(validFrom)) {
+		return ErrMembershipWindow
+	}
+	return nil
+}
+
+// EffectiveMembershipStatus returns the status a membership is in at now: an ACTIVE membership counts as PENDING
+// before its validFrom and as DISABLED from its validUntil on, so access ends on time between expiry runs
+func EffectiveMembershipStatus(status enums.MembershipStatus, validFrom time.Time, validUntil time.Time, now time.Time) enums.MembershipStatus {
+	if status != enums.ACTIVE {
+		return status
+	}
+	if !validUntil.IsZero() && !now.Before(validUntil) {
+		return enums.DISABLED
+	}
+	if !validFrom.IsZero() && now.Before(validFrom) {
+		return enums.PENDING
+	}
+	return status
+}
@@ -0,0 +1,280 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+	"strings"
+)
+
+// Password hashing algorithms
+const (
+	Argon2id = "argon2id"
+	Scrypt   = "scrypt"
+	Bcrypt   = "bcrypt"
+)
+
+var (
+	// ErrPasswordMismatch is returned when a password does not match its hash
+	ErrPasswordMismatch = errors.New("password hash: password does not match")
+	// ErrUnknownPasswordHash is returned for hashes of an unsupported or malformed format
+	ErrUnknownPasswordHash = errors.New("password hash: unknown hash format")
+)
+
+// PasswordConfig selects the algorithm and parameters new password hashes are created with
+type PasswordConfig struct {
+	Algorithm string         `mapstructure:"algorithm"`
+	Argon2id  Argon2idParams `mapstructure:"argon2id"`
+	Scrypt    ScryptParams   `mapstructure:"scrypt"`
+	Bcrypt    BcryptParams   `mapstructure:"bcrypt"`
+}
+
+// Argon2idParams are the argon2id cost parameters, Memory is in KiB
+type Argon2idParams struct {
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  int    `mapstructure:"saltLength"`
+	KeyLength   int    `mapstructure:"keyLength"`
+}
+
+// ScryptParams are the scrypt cost parameters, the CPU/memory cost is 2^LogN
+type ScryptParams struct {
+	LogN       int `mapstructure:"logN"`
+	R          int `mapstructure:"r"`
+	P          int `mapstructure:"p"`
+	SaltLength int `mapstructure:"saltLength"`
+	KeyLength  int `mapstructure:"keyLength"`
+}
+
+// BcryptParams are the bcrypt cost parameters
+type BcryptParams struct {
+	Cost int `mapstructure:"cost"`
+}
+
+// DefaultPasswordConfig returns the OWASP recommended argon2id parameters
+func DefaultPasswordConfig() PasswordConfig {
+	return PasswordConfig{
+		Algorithm: Argon2id,
+		Argon2id:  Argon2idParams{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		Scrypt:    ScryptParams{LogN: 17, R: 8, P: 1, SaltLength: 16, KeyLength: 32},
+		Bcrypt:    BcryptParams{Cost: 12},
+	}
+}
+
+// PasswordHasher hashes passwords with the configured algorithm into PHC strings
+// ($argon2id$v=19$m=...,t=...,p=...$salt$hash, $scrypt$ln=...,r=...,p=...$salt$hash) or bcrypt modular crypt strings
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded string, password string) error
+	VerifyUnknown(password string) error
+	NeedsRehash(encoded string) bool
+}
+
+type passwordHasher struct {
+	cfg   PasswordConfig
+	dummy string
+}
+
+// NewPasswordHasher returns a PasswordHasher for cfg, unset parameters take their DefaultPasswordConfig values
+func NewPasswordHasher(cfg *PasswordConfig) (*passwordHasher, error) {
+	h := &passwordHasher{cfg: DefaultPasswordConfig()}
+	if cfg == nil {
+		return h, nil
+	}
+	if cfg.Algorithm != "" {
+		h.cfg.Algorithm = cfg.Algorithm
+	}
+	if cfg.Argon2id != (Argon2idParams{}) {
+		h.cfg.Argon2id = cfg.Argon2id
+	}
+	if cfg.Scrypt != (ScryptParams{}) {
+		h.cfg.Scrypt = cfg.Scrypt
+	}
+	if cfg.Bcrypt != (BcryptParams{}) {
+		h.cfg.Bcrypt = cfg.Bcrypt
+	}
+	switch h.cfg.Algorithm {
+	case Argon2id, Scrypt, Bcrypt:
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q, want %s, %s or %s", h.cfg.Algorithm, Argon2id, Scrypt, Bcrypt)
+	}
+	secret, err := newSalt(32)
+	if err != nil {
+		return nil, err
+	}
+	if h.dummy, err = h.Hash(encodeB64(secret)); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Hash returns the encoded hash of password
+func (h *passwordHasher) Hash(password string) (string, error) {
+	switch h.cfg.Algorithm {
+	case Scrypt:
+		return hashScrypt(password, h.cfg.Scrypt)
+	case Bcrypt:
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.Bcrypt.Cost)
+		return string(hashed), errors.Wrap(err, "bcrypt.GenerateFromPassword")
+	default:
+		return hashArgon2id(password, h.cfg.Argon2id)
+	}
+}
+
+// Verify checks password against a hash of any supported algorithm
+func (h *passwordHasher) Verify(encoded string, password string) error {
+	return VerifyPassword(encoded, password)
+}
+
+// VerifyUnknown checks password against a dummy hash made with the configured algorithm and always fails with
+// ErrPasswordMismatch, so that authenticating an unknown user costs as much as a wrong password
+func (h *passwordHasher) VerifyUnknown(password string) error {
+	_ = VerifyPassword(h.dummy, password)
+	return ErrPasswordMismatch
+}
+
+// NeedsRehash reports whether encoded was made with another algorithm or other parameters than the configured ones
+func (h *passwordHasher) NeedsRehash(encoded string) bool {
+	switch {
+	case strings.HasPrefix(encoded, "$"+Argon2id+"$"):
+		params, _, _, err := decodeArgon2id(encoded)
+		return err != nil || h.cfg.Algorithm != Argon2id || params.Memory != h.cfg.Argon2id.Memory ||
+			params.Iterations != h.cfg.Argon2id.Iterations || params.Parallelism != h.cfg.Argon2id.Parallelism ||
+			params.KeyLength != h.cfg.Argon2id.KeyLength
+	case strings.HasPrefix(encoded, "$"+Scrypt+"$"):
+		params, _, _, err := decodeScrypt(encoded)
+		return err != nil || h.cfg.Algorithm != Scrypt || params.LogN != h.cfg.Scrypt.LogN || params.R != h.cfg.Scrypt.R ||
+			params.P != h.cfg.Scrypt.P || params.KeyLength != h.cfg.Scrypt.KeyLength
+	default:
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || h.cfg.Algorithm != Bcrypt || cost != h.cfg.Bcrypt.Cost
+	}
+}
+
+// VerifyPassword checks password against a hash of any supported algorithm, the parameters are read from the hash
+func VerifyPassword(encoded string, password string) error {
+	var key, computed []byte
+	switch {
+	case strings.HasPrefix(encoded, "$"+Argon2id+"$"):
+		params, salt, hash, err := decodeArgon2id(encoded)
+		if err != nil {
+			return err
+		}
+		key, computed = hash, argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(hash)))
+	case strings.HasPrefix(encoded, "$"+Scrypt+"$"):
+		params, salt, hash, err := decodeScrypt(encoded)
+		if err != nil {
+			return err
+		}
+		computed, err = scrypt.Key([]byte(password), salt, 1<<params.LogN, params.R, params.P, len(hash))
+		if err != nil {
+			return errors.Wrap(err, "scrypt.Key")
+		}
+		key = hash
+	default:
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		if err != nil {
+			return errors.Wrap(ErrUnknownPasswordHash, err.Error())
+		}
+		return nil
+	}
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func hashArgon2id(password string, params Argon2idParams) (string, error) {
+	salt, err := newSalt(params.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	hash := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(params.KeyLength))
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", Argon2id, argon2.Version, params.Memory, params.Iterations,
+		params.Parallelism, encodeB64(salt), encodeB64(hash)), nil
+}
+
+func decodeArgon2id(encoded string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil ||
+		params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	salt, hash, err := decodeSaltAndHash(parts[4], parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	params.SaltLength, params.KeyLength = len(salt), len(hash)
+	return params, salt, hash, nil
+}
+
+func hashScrypt(password string, params ScryptParams) (string, error) {
+	salt, err := newSalt(params.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	hash, err := scrypt.Key([]byte(password), salt, 1<<params.LogN, params.R, params.P, params.KeyLength)
+	if err != nil {
+		return "", errors.Wrap(err, "scrypt.Key")
+	}
+	return fmt.Sprintf("$%s$ln=%d,r=%d,p=%d$%s$%s", Scrypt, params.LogN, params.R, params.P, encodeB64(salt), encodeB64(hash)), nil
+}
+
+func decodeScrypt(encoded string) (ScryptParams, []byte, []byte, error) {
+	var params ScryptParams
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &params.LogN, &params.R, &params.P); err != nil ||
+		params.LogN < 1 || params.LogN > 62 || params.R < 1 || params.P < 1 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	salt, hash, err := decodeSaltAndHash(parts[3], parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	params.SaltLength, params.KeyLength = len(salt), len(hash)
+	return params, salt, hash, nil
+}
+
+func decodeSaltAndHash(encodedSalt string, encodedHash string) ([]byte, []byte, error) {
+	salt, err := base64.RawStdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return nil, nil, ErrUnknownPasswordHash
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(encodedHash)
+	if err != nil || len(hash) == 0 {
+		return nil, nil, ErrUnknownPasswordHash
+	}
+	return salt, hash, nil
+}
+
+func newSalt(length int) ([]byte, error) {
+	salt := make([]byte, length)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "rand.Read")
+	}
+	return salt, nil
+}
+
+func encodeB64(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
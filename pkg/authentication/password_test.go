@@ -0,0 +1,174 @@
+package authentication
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+	"testing"
+)
+
+// testPasswordConfig returns cheap parameters of every algorithm, selecting algorithm
+func testPasswordConfig(algorithm string) *PasswordConfig {
+	return &PasswordConfig{
+		Algorithm: algorithm,
+		Argon2id:  Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		Scrypt:    ScryptParams{LogN: 4, R: 8, P: 1, SaltLength: 16, KeyLength: 32},
+		Bcrypt:    BcryptParams{Cost: 4},
+	}
+}
+
+func TestPasswordHasherRoundTrip(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		prefix    string
+	}{
+		{algorithm: Argon2id, prefix: "$argon2id$v=19$m=64,t=1,p=1$"},
+		{algorithm: Scrypt, prefix: "$scrypt$ln=4,r=8,p=1$"},
+		{algorithm: Bcrypt, prefix: "$2a$04$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			h, err := NewPasswordHasher(testPasswordConfig(tt.algorithm))
+			if err != nil {
+				t.Fatalf("NewPasswordHasher: %v", err)
+			}
+			encoded, err := h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Errorf("got hash %q, want prefix %q", encoded, tt.prefix)
+			}
+			if err = h.Verify(encoded, "correct horse battery staple"); err != nil {
+				t.Errorf("Verify of the password: %v", err)
+			}
+			if err = h.Verify(encoded, "correct horse battery stapler"); !errors.Is(err, ErrPasswordMismatch) {
+				t.Errorf("Verify of another password: got %v, want %v", err, ErrPasswordMismatch)
+			}
+			if other, _ := h.Hash("correct horse battery staple"); other == encoded {
+				t.Error("hashes of the same password share a salt")
+			}
+			if h.NeedsRehash(encoded) {
+				t.Error("NeedsRehash of a hash made with the configured parameters")
+			}
+			if err = h.VerifyUnknown("correct horse battery staple"); !errors.Is(err, ErrPasswordMismatch) {
+				t.Errorf("VerifyUnknown: got %v, want %v", err, ErrPasswordMismatch)
+			}
+		})
+	}
+}
+
+func TestVerifyPasswordAcrossAlgorithms(t *testing.T) {
+	h, err := NewPasswordHasher(testPasswordConfig(Argon2id))
+	if err != nil {
+		t.Fatalf("NewPasswordHasher: %v", err)
+	}
+	for _, algorithm := range []string{Scrypt, Bcrypt} {
+		other, err := NewPasswordHasher(testPasswordConfig(algorithm))
+		if err != nil {
+			t.Fatalf("NewPasswordHasher: %v", err)
+		}
+		encoded, err := other.Hash("secret")
+		if err != nil {
+			t.Fatalf("Hash: %v", err)
+		}
+		if err = h.Verify(encoded, "secret"); err != nil {
+			t.Errorf("Verify of a %s hash: %v", algorithm, err)
+		}
+	}
+}
+
+func TestVerifyPasswordMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "empty", encoded: ""},
+		{name: "plain text", encoded: "secret"},
+		{name: "unknown algorithm", encoded: "$md5$c2FsdA$aGFzaA"},
+		{name: "argon2id missing fields", encoded: "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0"},
+		{name: "argon2id other version", encoded: "$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNo"},
+		{name: "argon2id bad parameters", encoded: "$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNo"},
+		{name: "argon2id zero parallelism", encoded: "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNo"},
+		{name: "argon2id bad salt", encoded: "$argon2id$v=19$m=64,t=1,p=1$!!!$aGFzaGhhc2hoYXNo"},
+		{name: "argon2id empty hash", encoded: "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0$"},
+		{name: "scrypt missing fields", encoded: "$scrypt$ln=4,r=8,p=1$c2FsdHNhbHRzYWx0"},
+		{name: "scrypt bad parameters", encoded: "$scrypt$ln=4,r=8$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNo"},
+		{name: "scrypt zero cost", encoded: "$scrypt$ln=0,r=8,p=1$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNo"},
+		{name: "scrypt bad hash", encoded: "$scrypt$ln=4,r=8,p=1$c2FsdHNhbHRzYWx0$!!!"},
+		{name: "bcrypt truncated", encoded: "$2a$04$abc"},
+	}
+	h, err := NewPasswordHasher(testPasswordConfig(Argon2id))
+	if err != nil {
+		t.Fatalf("NewPasswordHasher: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyPassword(tt.encoded, "secret"); !errors.Is(err, ErrUnknownPasswordHash) {
+				t.Errorf("VerifyPassword: got %v, want %v", err, ErrUnknownPasswordHash)
+			}
+			if !h.NeedsRehash(tt.encoded) {
+				t.Error("NeedsRehash of a malformed hash is false")
+			}
+		})
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	base := testPasswordConfig(Argon2id)
+	h, err := NewPasswordHasher(base)
+	if err != nil {
+		t.Fatalf("NewPasswordHasher: %v", err)
+	}
+	encoded := map[string]string{}
+	for _, algorithm := range []string{Argon2id, Scrypt, Bcrypt} {
+		other, err := NewPasswordHasher(testPasswordConfig(algorithm))
+		if err != nil {
+			t.Fatalf("NewPasswordHasher: %v", err)
+		}
+		if encoded[algorithm], err = other.Hash("secret"); err != nil {
+			t.Fatalf("Hash: %v", err)
+		}
+	}
+	tests := []struct {
+		name      string
+		change    func(cfg *PasswordConfig)
+		algorithm string
+		want      bool
+	}{
+		{name: "same argon2id parameters", change: func(cfg *PasswordConfig) {}, algorithm: Argon2id, want: false},
+		{name: "argon2id memory", change: func(cfg *PasswordConfig) { cfg.Argon2id.Memory = 128 }, algorithm: Argon2id, want: true},
+		{name: "argon2id iterations", change: func(cfg *PasswordConfig) { cfg.Argon2id.Iterations = 2 }, algorithm: Argon2id, want: true},
+		{name: "argon2id parallelism", change: func(cfg *PasswordConfig) { cfg.Argon2id.Parallelism = 2 }, algorithm: Argon2id, want: true},
+		{name: "argon2id key length", change: func(cfg *PasswordConfig) { cfg.Argon2id.KeyLength = 64 }, algorithm: Argon2id, want: true},
+		{name: "argon2id salt length", change: func(cfg *PasswordConfig) { cfg.Argon2id.SaltLength = 32 }, algorithm: Argon2id, want: false},
+		{name: "argon2id to scrypt", change: func(cfg *PasswordConfig) { cfg.Algorithm = Scrypt }, algorithm: Argon2id, want: true},
+		{name: "same scrypt parameters", change: func(cfg *PasswordConfig) { cfg.Algorithm = Scrypt }, algorithm: Scrypt, want: false},
+		{name: "scrypt cost", change: func(cfg *PasswordConfig) { cfg.Algorithm, cfg.Scrypt.LogN = Scrypt, 5 }, algorithm: Scrypt, want: true},
+		{name: "scrypt block size", change: func(cfg *PasswordConfig) { cfg.Algorithm, cfg.Scrypt.R = Scrypt, 4 }, algorithm: Scrypt, want: true},
+		{name: "same bcrypt cost", change: func(cfg *PasswordConfig) { cfg.Algorithm = Bcrypt }, algorithm: Bcrypt, want: false},
+		{name: "bcrypt cost", change: func(cfg *PasswordConfig) { cfg.Algorithm, cfg.Bcrypt.Cost = Bcrypt, 5 }, algorithm: Bcrypt, want: true},
+		{name: "bcrypt to argon2id", change: func(cfg *PasswordConfig) {}, algorithm: Bcrypt, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *base
+			tt.change(&cfg)
+			h, err := NewPasswordHasher(&cfg)
+			if err != nil {
+				t.Fatalf("NewPasswordHasher: %v", err)
+			}
+			if got := h.NeedsRehash(encoded[tt.algorithm]); got != tt.want {
+				t.Errorf("NeedsRehash of the %s hash: got %v, want %v", tt.algorithm, got, tt.want)
+			}
+		})
+	}
+	if h.NeedsRehash(encoded[Argon2id]) {
+		t.Error("NeedsRehash of a hash made with the configured parameters")
+	}
+}
+
+func TestNewPasswordHasherUnknownAlgorithm(t *testing.T) {
+	if _, err := NewPasswordHasher(&PasswordConfig{Algorithm: "md5"}); err == nil {
+		t.Error("NewPasswordHasher accepted an unknown algorithm")
+	}
+}
@@ -0,0 +1,80 @@
+package authentication
+
+import (
+	"errors"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidPermission is returned for permissions that are not "*" or dot separated lowercase names, optionally
+	// ending in a "*" wildcard such as "billing.*"
+	ErrInvalidPermission = errors.New("invalid permission")
+	// ErrBuiltInRole is returned when a built-in role is updated or deleted
+	ErrBuiltInRole = errors.New("built-in roles cannot be changed")
+	// ErrReservedRoleName is returned when a custom role is named after a built-in role
+	ErrReservedRoleName = errors.New("role name is reserved")
+	// ErrRoleOutOfScope is returned when a membership is granted a role of another group or organization
+	ErrRoleOutOfScope = errors.New("role belongs to another group or organization")
+	// ErrInvalidAction is returned for authorization checks of actions that are not dot separated lowercase names
+	ErrInvalidAction = errors.New("invalid action")
+)
+
+var (
+	permissionPattern = regexp.MustCompile(`^(\*|[a-z][a-z0-9_-]*(\.[a-z][a-z0-9_-]*)*(\.\*)?)$`)
+	actionPattern     = regexp.MustCompile(`^[a-z][a-z0-9_-]*(\.[a-z][a-z0-9_-]*)*$`)
+)
+
+// NormalizePermissions validates a permission set, returning it lowercased, sorted and without duplicates
+func NormalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	normalized := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if !permissionPattern.MatchString(p) {
+			return nil, ErrInvalidPermission
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		normalized = append(normalized, p)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// CheckRoleName returns ErrReservedRoleName when name is the name of a built-in role
+func CheckRoleName(name string) error {
+	for _, r := range enums.BuiltInRoles() {
+		if strings.EqualFold(strings.TrimSpace(name), r.Stringify()) {
+			return ErrReservedRoleName
+		}
+	}
+	return nil
+}
+
+// CheckAction returns ErrInvalidAction unless action names a single permission, without wildcards
+func CheckAction(action string) error {
+	if !actionPattern.MatchString(strings.ToLower(strings.TrimSpace(action))) {
+		return ErrInvalidAction
+	}
+	return nil
+}
+
+// PermissionGranted reports whether a permission set grants action; "*" grants every action and a wildcard such as
+// "billing.*" grants "billing" and every action below it
+func PermissionGranted(permissions []string, action string) bool {
+	action = strings.ToLower(strings.TrimSpace(action))
+	for _, p := range permissions {
+		if p == "*" || p == action {
+			return true
+		}
+		if prefix := strings.TrimSuffix(p, "*"); prefix != p && (strings.HasPrefix(action, prefix) || action+"." == prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package authentication
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Password policy rules
+const (
+	RuleMinLength        = "min_length"
+	RuleMaxLength        = "max_length"
+	RuleUpper            = "upper"
+	RuleLower            = "lower"
+	RuleDigit            = "digit"
+	RuleSymbol           = "symbol"
+	RuleContainsEmail    = "contains_email"
+	RuleContainsUsername = "contains_username"
+	RuleReused           = "reused"
+	RuleBreached         = "breached"
+)
+
+// PasswordPolicyConfig sets the rules new passwords must pass. BreachedPasswordsPath names a file of SHA-1 hashes of
+// breached passwords, one upper or lower case hex hash per line optionally followed by :count, which is held in
+// memory, or a directory of range files named after their 5 character hash prefix holding SUFFIX:count lines, as in
+// the Have I Been Pwned downloads, which are read on demand; BreachedRangeCacheSize bounds the range files kept in
+// memory
+type PasswordPolicyConfig struct {
+	Enabled                bool   `mapstructure:"enabled"`
+	MinLength              int    `mapstructure:"minLength"`
+	MaxLength              int    `mapstructure:"maxLength"`
+	RequireUpper           bool   `mapstructure:"requireUpper"`
+	RequireLower           bool   `mapstructure:"requireLower"`
+	RequireDigit           bool   `mapstructure:"requireDigit"`
+	RequireSymbol          bool   `mapstructure:"requireSymbol"`
+	RejectIdentifiers      bool   `mapstructure:"rejectIdentifiers"`
+	HistorySize            int    `mapstructure:"historySize"`
+	BreachedPasswordsPath  string `mapstructure:"breachedPasswordsPath"`
+	BreachedRangeCacheSize int    `mapstructure:"breachedRangeCacheSize"`
+}
+
+// PasswordViolation is a rule a password fails
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError lists every rule a password fails
+type PasswordPolicyError struct {
+	Violations []PasswordViolation `json:"violations"`
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return "password policy: " + strings.Join(messages, "; ")
+}
+
+// PasswordSubject is the account a password is checked for; Hashes are its current and previous password hashes
+type PasswordSubject struct {
+	Email    string
+	Username string
+	Hashes   []string
+}
+
+// PasswordPolicy checks passwords against the configured rules
+type PasswordPolicy interface {
+	Check(password string, subject *PasswordSubject) error
+}
+
+type passwordPolicy struct {
+	cfg      PasswordPolicyConfig
+	breached breachedCorpus
+}
+
+// NewPasswordPolicy returns a PasswordPolicy for cfg, opening its breached password corpus
+func NewPasswordPolicy(cfg *PasswordPolicyConfig) (*passwordPolicy, error) {
+	p := &passwordPolicy{cfg: *cfg}
+	if !cfg.Enabled || cfg.BreachedPasswordsPath == "" {
+		return p, nil
+	}
+	info, err := os.Stat(cfg.BreachedPasswordsPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "os.Stat")
+	}
+	if info.IsDir() {
+		p.breached, err = openBreachedRanges(cfg.BreachedPasswordsPath, cfg.BreachedRangeCacheSize)
+	} else {
+		p.breached, err = loadBreachedPasswords(cfg.BreachedPasswordsPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Check returns a *PasswordPolicyError listing the rules password fails for subject, or nil; reading the breached
+// password corpus can fail with another error
+func (p *passwordPolicy) Check(password string, subject *PasswordSubject) error {
+	if !p.cfg.Enabled {
+		return nil
+	}
+	if subject == nil {
+		subject = &PasswordSubject{}
+	}
+	var violations []PasswordViolation
+	violate := func(rule string, format string, args ...interface{}) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+	length := len([]rune(password))
+	if length < p.cfg.MinLength {
+		violate(RuleMinLength, "must be at least %d characters long", p.cfg.MinLength)
+	}
+	if p.cfg.MaxLength > 0 && length > p.cfg.MaxLength {
+		violate(RuleMaxLength, "must be at most %d characters long", p.cfg.MaxLength)
+	}
+	if p.cfg.RequireUpper && strings.IndexFunc(password, unicode.IsUpper) < 0 {
+		violate(RuleUpper, "must contain an upper case letter")
+	}
+	if p.cfg.RequireLower && strings.IndexFunc(password, unicode.IsLower) < 0 {
+		violate(RuleLower, "must contain a lower case letter")
+	}
+	if p.cfg.RequireDigit && strings.IndexFunc(password, unicode.IsDigit) < 0 {
+		violate(RuleDigit, "must contain a digit")
+	}
+	if p.cfg.RequireSymbol && strings.IndexFunc(password, isSymbol) < 0 {
+		violate(RuleSymbol, "must contain a symbol")
+	}
+	if p.cfg.RejectIdentifiers {
+		lower := strings.ToLower(password)
+		if email := strings.ToLower(subject.Email); email != "" {
+			local, _, _ := strings.Cut(email, "@")
+			if strings.Contains(lower, email) || len(local) >= 3 && strings.Contains(lower, local) {
+				violate(RuleContainsEmail, "must not contain the email address")
+			}
+		}
+		if username := strings.ToLower(subject.Username); len(username) >= 3 && strings.Contains(lower, username) {
+			violate(RuleContainsUsername, "must not contain the username")
+		}
+	}
+	if p.reused(password, subject.Hashes) {
+		violate(RuleReused, "must not be one of the last %d passwords", p.cfg.HistorySize)
+	}
+	breached, err := p.isBreached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		violate(RuleBreached, "appears in a known data breach")
+	}
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func (p *passwordPolicy) reused(password string, hashes []string) bool {
+	if p.cfg.HistorySize <= 0 {
+		return false
+	}
+	for i, hash := range hashes {
+		if i == p.cfg.HistorySize {
+			break
+		}
+		if VerifyPassword(hash, password) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isBreached looks the SHA-1 of password up in the breached password corpus
+func (p *passwordPolicy) isBreached(password string) (bool, error) {
+	if p.breached == nil {
+		return false, nil
+	}
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := p.breached.Range(hash[:breachedPrefixLength])
+	if err != nil {
+		return false, err
+	}
+	i := sort.SearchStrings(suffixes, hash[breachedPrefixLength:])
+	return i < len(suffixes) && suffixes[i] == hash[breachedPrefixLength:], nil
+}
+
+func isSymbol(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+}
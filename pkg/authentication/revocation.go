@@ -0,0 +1,146 @@
+package authentication
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// RevocationList tracks revoked access tokens in memory.
+// A bloom filter answers the common "not revoked" case cheaply and an exact set of token digests
+// confirms positives, so lookups never report a false revocation.
+type RevocationList struct {
+	mu          sync.RWMutex
+	expected    int
+	fpRate      float64
+	filter      *bloomFilter
+	revoked     map[[sha256.Size]byte]time.Time
+	sessions    map[string]time.Time
+	orgs        map[string]time.Time
+	lastSynced  time.Time
+	initialized bool
+}
+
+// NewRevocationList constructs a RevocationList sized for expected revoked tokens at false positive rate fpRate
+func NewRevocationList(expected int, fpRate float64) *RevocationList {
+	return &RevocationList{
+		expected: expected,
+		fpRate:   fpRate,
+		filter:   newBloomFilter(expected, fpRate),
+		revoked:  make(map[[sha256.Size]byte]time.Time),
+		sessions: make(map[string]time.Time),
+		orgs:     make(map[string]time.Time),
+	}
+}
+
+// Revoke records accessToken as revoked until expiresAt
+func (r *RevocationList) Revoke(accessToken string, expiresAt time.Time) {
+	digest := sha256.Sum256([]byte(accessToken))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filter.Add(digest[:])
+	r.revoked[digest] = expiresAt
+}
+
+// IsRevoked reports whether accessToken has been revoked
+func (r *RevocationList) IsRevoked(accessToken string) bool {
+	digest := sha256.Sum256([]byte(accessToken))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.filter.Test(digest[:]) {
+		return false
+	}
+	_, ok := r.revoked[digest]
+	return ok
+}
+
+// RevokeUserSessions records that every session of userId issued up to revokedAt was revoked; a zero revokedAt keeps
+// the latest revocation
+func (r *RevocationList) RevokeUserSessions(userId string, revokedAt time.Time) {
+	if revokedAt.IsZero() {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if revokedAt.After(r.sessions[userId]) {
+		r.sessions[userId] = revokedAt
+	}
+}
+
+// IsSessionRevoked reports whether a session of userId issued at issuedAt was revoked with all the user's sessions
+func (r *RevocationList) IsSessionRevoked(userId string, issuedAt time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return SessionRevoked(issuedAt, r.sessions[userId])
+}
+
+// RevokeOrganizationSessions records that every session of the users of organizationId issued up to revokedAt was
+// revoked; a zero revokedAt keeps the latest revocation
+func (r *RevocationList) RevokeOrganizationSessions(organizationId string, revokedAt time.Time) {
+	if revokedAt.IsZero() {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if revokedAt.After(r.orgs[organizationId]) {
+		r.orgs[organizationId] = revokedAt
+	}
+}
+
+// IsOrganizationSessionRevoked reports whether a session of a user of organizationId issued at issuedAt was revoked
+// with all the sessions of the organization
+func (r *RevocationList) IsOrganizationSessionRevoked(organizationId string, issuedAt time.Time) bool {
+	if organizationId == "" {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return SessionRevoked(issuedAt, r.orgs[organizationId])
+}
+
+// MarkSynced records that the list had caught up with the revocation stream at t
+func (r *RevocationList) MarkSynced(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastSynced = t
+	r.initialized = true
+}
+
+// Fresh reports whether the list caught up with the revocation stream within maxStaleness
+func (r *RevocationList) Fresh(maxStaleness time.Duration) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.initialized && time.Since(r.lastSynced) <= maxStaleness
+}
+
+// Prune drops revocations for tokens that have expired and rebuilds the bloom filter from the survivors
+func (r *RevocationList) Prune(now time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pruned int
+	for digest, expiresAt := range r.revoked {
+		if !expiresAt.IsZero() && now.After(expiresAt) {
+			delete(r.revoked, digest)
+			pruned++
+		}
+	}
+	if pruned == 0 {
+		return 0
+	}
+	expected := r.expected
+	if len(r.revoked) > expected {
+		expected = len(r.revoked) * 2
+	}
+	r.filter = newBloomFilter(expected, r.fpRate)
+	for digest := range r.revoked {
+		r.filter.Add(digest[:])
+	}
+	return pruned
+}
+
+// Len returns the number of tracked revocations
+func (r *RevocationList) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.revoked)
+}
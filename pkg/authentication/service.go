@@ -0,0 +1,352 @@
+package authentication
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// ServiceTokenHeader is the gRPC metadata key carrying service tokens, separate from user tokens in "authorization"
+	ServiceTokenHeader = "service-authorization"
+
+	serviceTokenType       = "SERVICE"
+	defaultServiceTokenTTL = 5 * time.Minute
+)
+
+// Service names used as token subjects, certificate SANs and access rule entries
+const (
+	ApiGatewayService = "api-gateway-service"
+	CommandService    = "command-service"
+	QueryService      = "query-service"
+	// AnyCaller in an access rule lets unauthenticated callers, such as orchestrator probes, call the method
+	AnyCaller = "*"
+)
+
+// ServiceAuthConfig settings for service to service authentication. Each service signs its tokens with its own
+// private key and verifies callers with their public keys, so no service can issue tokens in another's name.
+type ServiceAuthConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Name    string `mapstructure:"name"`
+	// KeyFile is the PEM private key (RSA or ECDSA P-256) this service signs its tokens with
+	KeyFile string `mapstructure:"keyFile"`
+	// CallerKeys maps calling service names to PEM files holding their public key or certificate
+	CallerKeys map[string]string `mapstructure:"callerKeys"`
+	// Secret is the shared signing secret of earlier versions, which is refused
+	Secret   string        `mapstructure:"secret"`
+	TokenTTL time.Duration `mapstructure:"tokenTTL"`
+	// Key and PublicKeys are keys set up in process, used instead of KeyFile and CallerKeys when set
+	Key        crypto.Signer               `mapstructure:"-"`
+	PublicKeys map[string]crypto.PublicKey `mapstructure:"-"`
+}
+
+// ServiceAccessRules maps full gRPC method names to the services allowed to call them
+type ServiceAccessRules map[string][]string
+
+// ServiceAuthenticator issues service tokens for outgoing calls and authorizes incoming calls by caller identity
+type ServiceAuthenticator interface {
+	ServiceToken() (string, error)
+	AuthorizeService(ctx context.Context, method string) (string, error)
+}
+
+type serviceContextKey struct{}
+
+// ServiceFromContext returns the authenticated calling service stored by the service auth interceptor
+func ServiceFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(serviceContextKey{}).(string)
+	return name
+}
+
+// ContextWithService stores the authenticated calling service in ctx
+func ContextWithService(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, serviceContextKey{}, name)
+}
+
+// serviceAuthenticator
+type serviceAuthenticator struct {
+	log     logging.Logger
+	cfg     *ServiceAuthConfig
+	rules   ServiceAccessRules
+	key     crypto.Signer
+	method  jwt.SigningMethod
+	callers map[string]crypto.PublicKey
+	mu      sync.Mutex
+	token   string
+	renewAt time.Time
+}
+
+// NewServiceAuthenticator constructs a new serviceAuthenticator, loading the signing key and caller keys when
+// enabled; it refuses a shared secret
+func NewServiceAuthenticator(log logging.Logger, cfg *ServiceAuthConfig, rules ServiceAccessRules) (*serviceAuthenticator, error) {
+	a := &serviceAuthenticator{
+		log:     log,
+		cfg:     cfg,
+		rules:   rules,
+		callers: make(map[string]crypto.PublicKey),
+	}
+	if !cfg.Enabled {
+		return a, nil
+	}
+	if cfg.Secret != "" {
+		return nil, errors.New("serviceAuth.secret is not supported: configure serviceAuth.keyFile and serviceAuth.callerKeys")
+	}
+	if cfg.Name == "" {
+		return nil, errors.New("serviceAuth.name is required")
+	}
+	a.key = cfg.Key
+	if a.key == nil {
+		if cfg.KeyFile == "" {
+			return nil, errors.New("serviceAuth.keyFile is required")
+		}
+		key, err := readPrivateKey(cfg.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "serviceAuth.keyFile")
+		}
+		a.key = key
+	}
+	method, err := serviceSigningMethod(a.key.Public())
+	if err != nil {
+		return nil, errors.Wrap(err, "serviceAuth.keyFile")
+	}
+	a.method = method
+	if cfg.PublicKeys != nil {
+		for name, key := range cfg.PublicKeys {
+			a.callers[name] = key
+		}
+	} else {
+		for name, path := range cfg.CallerKeys {
+			key, err := readPublicKey(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "serviceAuth.callerKeys.%s", name)
+			}
+			a.callers[name] = key
+		}
+	}
+	for name, key := range a.callers {
+		if _, err = serviceSigningMethod(key); err != nil {
+			return nil, errors.Wrapf(err, "serviceAuth.callerKeys.%s", name)
+		}
+	}
+	return a, nil
+}
+
+// GenerateServiceKeys gives every enabled config a new ECDSA key and the public keys of all of them, for services
+// running in one process
+func GenerateServiceKeys(cfgs ...*ServiceAuthConfig) error {
+	publicKeys := make(map[string]crypto.PublicKey)
+	for _, cfg := range cfgs {
+		if !cfg.Enabled {
+			continue
+		}
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return errors.Wrap(err, "ecdsa.GenerateKey")
+		}
+		cfg.Key, cfg.PublicKeys = key, publicKeys
+		publicKeys[cfg.Name] = key.Public()
+	}
+	return nil
+}
+
+// serviceSigningMethod returns the token signing method for keys of the type of key
+func serviceSigningMethod(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, errors.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+		}
+		return jwt.SigningMethodES256, nil
+	}
+	return nil, errors.Errorf("unsupported key type %T", key)
+}
+
+// readPrivateKey reads a PKCS #8, PKCS #1 or SEC 1 PEM private key
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.Errorf("unsupported key type %T", key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.Errorf("%s holds no supported private key", path)
+}
+
+// readPublicKey reads a PEM public key or the public key of a PEM certificate
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "x509.ParseCertificate")
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.Errorf("%s holds no PEM data", path)
+	}
+	return block, nil
+}
+
+func (a *serviceAuthenticator) tokenTTL() time.Duration {
+	if a.cfg.TokenTTL > 0 {
+		return a.cfg.TokenTTL
+	}
+	return defaultServiceTokenTTL
+}
+
+// ServiceToken returns a short-lived token identifying this service, renewed at half its lifetime
+func (a *serviceAuthenticator) ServiceToken() (string, error) {
+	if !a.cfg.Enabled {
+		return "", nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if a.token != "" && now.Before(a.renewAt) {
+		return a.token, nil
+	}
+	ttl := a.tokenTTL()
+	token := jwt.New(a.method)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["sub"] = a.cfg.Name
+	claims["token_type"] = serviceTokenType
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		return "", err
+	}
+	a.token, a.renewAt = signed, now.Add(ttl/2)
+	return a.token, nil
+}
+
+// AuthorizeService identifies the caller from its verified client certificate or service token
+// and checks it against the rules for method; methods without rules are denied
+func (a *serviceAuthenticator) AuthorizeService(ctx context.Context, method string) (string, error) {
+	if !a.cfg.Enabled {
+		return "", nil
+	}
+	allowed, ok := a.rules[method]
+	if !ok {
+		return "", status.Errorf(codes.PermissionDenied, "no service access rule for %s", method)
+	}
+	for _, name := range allowed {
+		if name == AnyCaller {
+			return "", nil
+		}
+	}
+	callers := callerIdentities(ctx)
+	if name, err := a.verifyServiceToken(ctx); err == nil {
+		callers = append(callers, name)
+	} else if len(callers) == 0 {
+		return "", err
+	}
+	for _, caller := range callers {
+		for _, name := range allowed {
+			if caller == name {
+				return caller, nil
+			}
+		}
+	}
+	return "", status.Errorf(codes.PermissionDenied, "service %v may not call %s", callers, method)
+}
+
+func (a *serviceAuthenticator) verifyServiceToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[ServiceTokenHeader]) == 0 {
+		return "", status.Error(codes.Unauthenticated, "service credentials are not provided")
+	}
+	parsed, err := jwt.Parse(md[ServiceTokenHeader][0], func(token *jwt.Token) (interface{}, error) {
+		name, _ := token.Claims.(jwt.MapClaims)["sub"].(string)
+		key, ok := a.callers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown calling service %q", name)
+		}
+		method, err := serviceSigningMethod(key)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method != method {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return key, nil
+	})
+	if err != nil || !parsed.Valid {
+		return "", status.Errorf(codes.Unauthenticated, "service token is invalid: %v", err)
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+	if claims["token_type"] != serviceTokenType {
+		return "", status.Error(codes.Unauthenticated, "token is not a service token")
+	}
+	if _, ok = claims["exp"]; !ok {
+		return "", status.Error(codes.Unauthenticated, "service token has no expiry")
+	}
+	name, _ := claims["sub"].(string)
+	if name == "" {
+		return "", status.Error(codes.Unauthenticated, "service token has no subject")
+	}
+	return name, nil
+}
+
+// callerIdentities returns the DNS SANs of the verified mTLS client certificate, if any
+func callerIdentities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return append([]string(nil), tlsInfo.State.VerifiedChains[0][0].DNSNames...)
+}
+
+// AttachServiceTokenToContext returns ctx with a service token attached to the outgoing metadata
+func AttachServiceTokenToContext(ctx context.Context, serviceToken string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ServiceTokenHeader, serviceToken)
+}
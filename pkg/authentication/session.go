@@ -10,11 +10,14 @@ import (
 
 // Session stores the structured data from a session token for use
 type Session struct {
-	UserId     string
-	RootAdmin  bool
-	Type       enums.SessionType
-	Expiration int64
-	Cfg        *Config
+	UserId         string
+	RootAdmin      bool
+	OrganizationId string
+	OrgAdmin       bool
+	Type           enums.SessionType
+	Expiration     int64
+	IssuedAt       int64
+	Cfg            *Config
 }
 
 // newSession constructions a new Token
@@ -28,6 +31,15 @@ func newSession(userId string, root bool, tokenType enums.SessionType, cfg *Conf
 	}
 }
 
+// Tenant returns the organization the session is scoped to: root sessions are not scoped, sessions of users without
+// an organization are scoped to the default tenant
+func (t *Session) Tenant() string {
+	if t.RootAdmin {
+		return ""
+	}
+	return OrganizationOrDefault(t.OrganizationId)
+}
+
 // setExpiration returns the unix time for token expiration
 func (t *Session) setExpiration() {
 	var duration int
@@ -56,8 +68,12 @@ func (t *Session) NewToken() (string, error) {
 	claims := token.Claims.(jwt.MapClaims)
 	claims["id"] = t.UserId
 	claims["root"] = t.RootAdmin
+	claims["org"] = t.OrganizationId
+	claims["org_admin"] = t.OrgAdmin
 	claims["token_type"] = t.Type.Stringify()
 	claims["exp"] = t.Expiration
+	t.IssuedAt = time.Now().Unix()
+	claims["iat"] = t.IssuedAt
 	return token.SignedString(MySigningKey)
 }
 
@@ -82,7 +98,28 @@ func decryptToken(tokenStr string, secret string) (*Session, error) {
 		session.UserId = tokenClaims["id"].(string)
 		session.RootAdmin = tokenClaims["root"].(bool)
 		session.Type = enums.SessionTypeFromString(tokenClaims["token_type"].(string))
+		session.OrganizationId, _ = tokenClaims["org"].(string)
+		session.OrgAdmin, _ = tokenClaims["org_admin"].(bool)
+		if exp, ok := tokenClaims["exp"].(float64); ok {
+			session.Expiration = int64(exp)
+		}
+		if iat, ok := tokenClaims["iat"].(float64); ok {
+			session.IssuedAt = int64(iat)
+		}
 		return &session, nil
 	}
 	return &session, errors.New("invalid token")
 }
+
+// TokenIssuedAt returns the iat claim of tokenStr without verifying its signature, for services that verified it
+// already; tokens issued before the claim existed report the zero time
+func TokenIssuedAt(tokenStr string) (time.Time, error) {
+	parsedToken, _, err := new(jwt.Parser).ParseUnverified(tokenStr, jwt.MapClaims{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if iat, ok := parsedToken.Claims.(jwt.MapClaims)["iat"].(float64); ok {
+		return time.Unix(int64(iat), 0), nil
+	}
+	return time.Time{}, nil
+}
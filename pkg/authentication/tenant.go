@@ -0,0 +1,97 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+)
+
+// NoOrganization is the OrganizationID of the users and groups that belong to no organization, which together make
+// up the default tenant
+var NoOrganization = uuid.Nil.String()
+
+var (
+	// ErrOutsideTenant is returned for resources of another organization than the one a request is scoped to; it
+	// reads as a missing resource so that tenants cannot probe each other's ids
+	ErrOutsideTenant = errors.New("resource not found in organization")
+	// ErrOrganizationSuspended is returned when the organization of a user is suspended
+	ErrOrganizationSuspended = errors.New("organization is suspended")
+	// ErrOrgAdminRequired is returned when a user that does not administer its organization grants admin rights
+	ErrOrgAdminRequired = errors.New("organization admin rights required")
+	// ErrRootAdminRequired is returned when a session that is not root changes or removes a root user
+	ErrRootAdminRequired = errors.New("root admin rights required")
+)
+
+type sessionCtxKey struct{}
+
+// ContextWithSession stores the authenticated session of a request in ctx
+func ContextWithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionCtxKey{}, session)
+}
+
+// SessionFromContext returns the authenticated session of the request carried in ctx, if any
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionCtxKey{}).(*Session)
+	return session, ok && session != nil
+}
+
+// TenantFromContext returns the tenant the request carried in ctx is scoped to; requests without a session are
+// scoped to the default tenant
+func TenantFromContext(ctx context.Context) string {
+	if session, ok := SessionFromContext(ctx); ok {
+		return session.Tenant()
+	}
+	return NoOrganization
+}
+
+// AdminFromContext reports whether the session of the request carried in ctx is a root or organization admin
+func AdminFromContext(ctx context.Context) bool {
+	session, ok := SessionFromContext(ctx)
+	return ok && (session.RootAdmin || session.OrgAdmin)
+}
+
+// CheckSelfOrAdmin returns ErrOrgAdminRequired unless the session of the request carried in ctx belongs to the user
+// with userID or to a root or organization admin
+func CheckSelfOrAdmin(ctx context.Context, userID string) error {
+	if session, ok := SessionFromContext(ctx); ok && session.UserId == userID {
+		return nil
+	}
+	if !AdminFromContext(ctx) {
+		return ErrOrgAdminRequired
+	}
+	return nil
+}
+
+// OrganizationOrDefault returns organizationID, or NoOrganization when it is empty
+func OrganizationOrDefault(organizationID string) string {
+	if organizationID == "" {
+		return NoOrganization
+	}
+	return organizationID
+}
+
+// InTenant reports whether a resource of organizationID is visible to a request scoped to tenantID; the empty
+// tenant of root sessions sees every organization
+func InTenant(tenantID string, organizationID string) bool {
+	if tenantID == "" {
+		return true
+	}
+	return uuid.FromStringOrNil(tenantID) == uuid.FromStringOrNil(OrganizationOrDefault(organizationID))
+}
+
+// CheckTenant returns ErrOutsideTenant unless a resource of organizationID is visible to tenantID
+func CheckTenant(tenantID string, organizationID string) error {
+	if !InTenant(tenantID, organizationID) {
+		return ErrOutsideTenant
+	}
+	return nil
+}
+
+// CheckRootTarget returns ErrRootAdminRequired when a request scoped to tenantID targets a root user; only root
+// sessions are not scoped to a tenant
+func CheckRootTarget(tenantID string, root bool) error {
+	if root && tenantID != "" {
+		return ErrRootAdminRequired
+	}
+	return nil
+}
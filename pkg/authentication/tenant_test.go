@@ -0,0 +1,45 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckSelfOrAdmin(t *testing.T) {
+	tests := []struct {
+		name    string
+		session *Session
+		userID  string
+		wantErr error
+	}{
+		{name: "no session", userID: "user", wantErr: ErrOrgAdminRequired},
+		{name: "self", session: &Session{UserId: "user"}, userID: "user"},
+		{name: "other member", session: &Session{UserId: "member"}, userID: "user", wantErr: ErrOrgAdminRequired},
+		{name: "organization admin", session: &Session{UserId: "admin", OrgAdmin: true}, userID: "user"},
+		{name: "root", session: &Session{UserId: "root", RootAdmin: true}, userID: "user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.session != nil {
+				ctx = ContextWithSession(ctx, tt.session)
+			}
+			if err := CheckSelfOrAdmin(ctx, tt.userID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckSelfOrAdmin: got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRootTarget(t *testing.T) {
+	if err := CheckRootTarget(NoOrganization, true); !errors.Is(err, ErrRootAdminRequired) {
+		t.Errorf("scoped session targeting root: got %v, want %v", err, ErrRootAdminRequired)
+	}
+	if err := CheckRootTarget("", true); err != nil {
+		t.Errorf("root session targeting root: %v", err)
+	}
+	if err := CheckRootTarget(NoOrganization, false); err != nil {
+		t.Errorf("scoped session targeting member: %v", err)
+	}
+}
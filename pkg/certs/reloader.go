@@ -0,0 +1,158 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/pkg/errors"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultReloadInterval = 30 * time.Second
+
+// Config structures the TLS settings of a gRPC server or client
+type Config struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	CertFile       string        `mapstructure:"certFile"`
+	KeyFile        string        `mapstructure:"keyFile"`
+	CAFile         string        `mapstructure:"caFile"`
+	Mutual         bool          `mapstructure:"mutual"`
+	ServerName     string        `mapstructure:"serverName"`
+	AllowedSANs    []string      `mapstructure:"allowedSANs"`
+	ReloadInterval time.Duration `mapstructure:"reloadInterval"`
+}
+
+// reloader keeps a key pair and CA pool loaded from disk, reloading them when the files change
+type reloader struct {
+	log      logging.Logger
+	cfg      *Config
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	roots    *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// newReloader loads the configured files and starts polling them for changes until ctx is done
+func newReloader(ctx context.Context, log logging.Logger, cfg *Config) (*reloader, error) {
+	r := &reloader{
+		log:      log,
+		cfg:      cfg,
+		modTimes: make(map[string]time.Time),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	go r.watch(ctx)
+	return r, nil
+}
+
+func (r *reloader) files() []string {
+	var files []string
+	for _, f := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.CAFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (r *reloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return errors.Wrap(err, "os.Stat")
+		}
+		modTimes[f] = info.ModTime()
+	}
+	var cert *tls.Certificate
+	if r.cfg.CertFile != "" || r.cfg.KeyFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+		if err != nil {
+			return errors.Wrap(err, "tls.LoadX509KeyPair")
+		}
+		if pair.Leaf, err = x509.ParseCertificate(pair.Certificate[0]); err != nil {
+			return errors.Wrap(err, "x509.ParseCertificate")
+		}
+		cert = &pair
+	}
+	var roots *x509.CertPool
+	if r.cfg.CAFile != "" {
+		pem, err := os.ReadFile(r.cfg.CAFile)
+		if err != nil {
+			return errors.Wrap(err, "os.ReadFile")
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return errors.Errorf("no certificates found in %s", r.cfg.CAFile)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert, r.roots, r.modTimes = cert, roots, modTimes
+	return nil
+}
+
+func (r *reloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil || !info.ModTime().Equal(r.modTimes[f]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *reloader) watch(ctx context.Context) {
+	interval := r.cfg.ReloadInterval
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		// a failed reload keeps serving the previous material; handshakes still fail once it expires
+		if err := r.load(); err != nil {
+			r.log.WarnMsg("certs.reload", err)
+			continue
+		}
+		r.log.Infof("reloaded TLS material from %v", r.files())
+	}
+}
+
+// certificate returns the current key pair, failing when it is missing or outside its validity period
+func (r *reloader) certificate() (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil {
+		return nil, errors.New("no TLS certificate configured")
+	}
+	now := time.Now()
+	if now.After(r.cert.Leaf.NotAfter) || now.Before(r.cert.Leaf.NotBefore) {
+		return nil, errors.Errorf("TLS certificate %q is not valid at %s", r.cert.Leaf.Subject.CommonName, now.Format(time.RFC3339))
+	}
+	return r.cert, nil
+}
+
+// rootPool returns the current CA pool
+func (r *reloader) rootPool() (*x509.CertPool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.roots == nil {
+		return nil, errors.New("no TLS CA configured")
+	}
+	return r.roots, nil
+}
@@ -5,19 +5,23 @@ const (
 	HttpPort       = "HTTP_PORT"
 	ConfigPath     = "CONFIG_PATH"
 	KafkaBrokers   = "KAFKA_BROKERS"
-	JaegerHostPort = "JAEGER_HOST"
+	OtlpEndpoint   = "OTLP_ENDPOINT"
 	RedisAddr      = "REDIS_ADDR"
 	MongoDbURI     = "MONGO_URI"
 	PostgresqlHost = "POSTGRES_HOST"
 	PostgresqlPort = "POSTGRES_PORT"
 
-	QueryServicePort = "QUERY_SERVICE"
+	QueryServicePort   = "QUERY_SERVICE"
+	CommandServicePort = "COMMAND_SERVICE"
 
-	Yaml     = "yaml"
-	Redis    = "redis"
-	Kafka    = "kafka"
-	Postgres = "postgres"
-	MongoDB  = "mongo"
+	Yaml           = "yaml"
+	Redis          = "redis"
+	Kafka          = "kafka"
+	Postgres       = "postgres"
+	MongoDB        = "mongo"
+	QueryService   = "query_service"
+	CommandService = "command_service"
+	ConsumerLag    = "consumer_lag"
 
 	GRPC     = "GRPC"
 	SIZE     = "SIZE"
@@ -37,9 +41,16 @@ const (
 	WorkerID  = "workerID"
 	Offset    = "offset"
 	Time      = "time"
+	Key       = "key"
+	Bytes     = "bytes"
+	TraceID   = "trace_id"
+	SpanID    = "span_id"
+	RequestID = "request_id"
+	UserID    = "user_id"
 
-	Page   = "page"
-	Size   = "size"
-	Search = "search"
-	ID     = "id"
+	Page    = "page"
+	Size    = "size"
+	Search  = "search"
+	ID      = "id"
+	GroupID = "groupID"
 )
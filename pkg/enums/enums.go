@@ -106,3 +106,112 @@ func (r ReadTableIdType) KeyString() string {
 func (r ReadTableIdType) EnumIndex() int {
 	return int(r)
 }
+
+// UserStatus enumerates the lifecycle states of a User
+type UserStatus int
+
+const (
+	INVITED UserStatus = iota + 1
+	ACTIVATED
+	SUSPENDED
+	DEACTIVATED
+)
+
+// Stringify converts UserStatus enum into a string value
+func (s UserStatus) Stringify() string {
+	if s < INVITED || s > DEACTIVATED {
+		return "UNKNOWN"
+	}
+	return [...]string{"INVITED", "ACTIVE", "SUSPENDED", "DEACTIVATED"}[s-1]
+}
+
+// EnumIndex returns the current index of the UserStatus enum value
+func (s UserStatus) EnumIndex() int {
+	return int(s)
+}
+
+// CanTransitionTo reports whether a User may move from status s to next; a suspension may be renewed with a new
+// reason or end date
+func (s UserStatus) CanTransitionTo(next UserStatus) bool {
+	switch next {
+	case ACTIVATED:
+		return s == INVITED || s == SUSPENDED || s == DEACTIVATED
+	case SUSPENDED:
+		return s == ACTIVATED || s == SUSPENDED
+	case DEACTIVATED:
+		return s == INVITED || s == ACTIVATED || s == SUSPENDED
+	default:
+		return false
+	}
+}
+
+// RevokesSessions reports whether moving a User into status s ends its sessions
+func (s UserStatus) RevokesSessions() bool {
+	return s == SUSPENDED || s == DEACTIVATED
+}
+
+// UserStatusFromString returns the UserStatus of a Stringify value, or 0 when it names none
+func UserStatusFromString(inStr string) UserStatus {
+	switch inStr {
+	case "INVITED":
+		return INVITED
+	case "ACTIVE":
+		return ACTIVATED
+	case "SUSPENDED":
+		return SUSPENDED
+	case "DEACTIVATED":
+		return DEACTIVATED
+	default:
+		return 0
+	}
+}
+
+// UserStatusFromActive returns the UserStatus of a User stored before lifecycle states, from its Active flag
+func UserStatusFromActive(active bool) UserStatus {
+	if active {
+		return ACTIVATED
+	}
+	return DEACTIVATED
+}
+
+// builtInRoleIDs are the fixed identifiers of the built-in roles seeded for the Role enum values
+var builtInRoleIDs = [...]string{
+	"00000000-0000-4000-8000-000000000001",
+	"00000000-0000-4000-8000-000000000002",
+	"00000000-0000-4000-8000-000000000003",
+}
+
+// RoleID returns the identifier of the built-in role seeded for the Role enum value
+func (r Role) RoleID() string {
+	if r < MEMBER || r > ROOT {
+		return builtInRoleIDs[0]
+	}
+	return builtInRoleIDs[r-1]
+}
+
+// Permissions returns the permission set of the built-in role seeded for the Role enum value
+func (r Role) Permissions() []string {
+	switch r {
+	case ROOT:
+		return []string{"*"}
+	case ADMIN:
+		return []string{"group.read", "group.update", "membership.read", "membership.create", "membership.update", "membership.delete", "role.read"}
+	default:
+		return []string{"group.read", "membership.read", "role.read"}
+	}
+}
+
+// BuiltInRoles returns the Role enum values that have a built-in role
+func BuiltInRoles() []Role {
+	return []Role{MEMBER, ADMIN, ROOT}
+}
+
+// RoleFromID returns the Role enum value of the built-in role with id, or 0 when id names a custom role
+func RoleFromID(id string) Role {
+	for i, builtInID := range builtInRoleIDs {
+		if builtInID == id {
+			return Role(i + 1)
+		}
+	}
+	return 0
+}
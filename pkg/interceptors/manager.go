@@ -5,7 +5,9 @@ import (
 	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"time"
 )
 
@@ -38,19 +40,42 @@ type InterceptorManager interface {
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) (err error)
+	ServiceAuthUnaryInterceptor(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error)
+	ServiceAuthStreamInterceptor(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error)
+	ClientServiceAuthInterceptor() func(
+		ctx context.Context,
+		method string,
+		req interface{},
+		reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error
 }
 
 // InterceptorManager struct
 type interceptorManager struct {
-	logger logging.Logger
-	auth   authentication.Authenticator
+	logger      logging.Logger
+	auth        authentication.Authenticator
+	serviceAuth authentication.ServiceAuthenticator
 }
 
 // NewInterceptorManager InterceptorManager constructor
-func NewInterceptorManager(logger logging.Logger, auth authentication.Authenticator) *interceptorManager {
+func NewInterceptorManager(logger logging.Logger, auth authentication.Authenticator, serviceAuth authentication.ServiceAuthenticator) *interceptorManager {
 	return &interceptorManager{
-		logger: logger,
-		auth:   auth,
+		logger:      logger,
+		auth:        auth,
+		serviceAuth: serviceAuth,
 	}
 }
 
@@ -64,7 +89,7 @@ func (im *interceptorManager) Logger(
 	start := time.Now()
 	md, _ := metadata.FromIncomingContext(ctx)
 	reply, err := handler(ctx, req)
-	im.logger.GrpcMiddlewareAccessLogger(info.FullMethod, time.Since(start), md, err)
+	im.logger.GrpcMiddlewareAccessLogger(ctx, info.FullMethod, time.Since(start), md, err)
 	return reply, err
 }
 
@@ -90,7 +115,7 @@ func (im *interceptorManager) ClientRequestLoggerInterceptor() func(
 		start := time.Now()
 		err := invoker(ctx, method, req, reply, cc, opts...)
 		md, _ := metadata.FromIncomingContext(ctx)
-		im.logger.GrpcClientInterceptorLogger(method, req, reply, time.Since(start), md, err)
+		im.logger.GrpcClientInterceptorLogger(ctx, method, req, reply, time.Since(start), md, err)
 		return err
 	}
 }
@@ -124,3 +149,62 @@ func (im *interceptorManager) AuthStreamInterceptor(
 	}
 	return handler(srv, stream)
 }
+
+// ServiceAuthUnaryInterceptor intercepts unary gRPC requests and authorizes the calling service
+func (im *interceptorManager) ServiceAuthUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	caller, err := im.serviceAuth.AuthorizeService(ctx, info.FullMethod)
+	if err != nil {
+		im.logger.Warnf("service auth denied %s: %v", info.FullMethod, err)
+		return nil, err
+	}
+	return handler(authentication.ContextWithService(ctx, caller), req)
+}
+
+// ServiceAuthStreamInterceptor intercepts streaming gRPC requests and authorizes the calling service
+func (im *interceptorManager) ServiceAuthStreamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) (err error) {
+	if _, err = im.serviceAuth.AuthorizeService(stream.Context(), info.FullMethod); err != nil {
+		im.logger.Warnf("service auth denied %s: %v", info.FullMethod, err)
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// ClientServiceAuthInterceptor gRPC client interceptor attaching this service's token to outgoing requests
+func (im *interceptorManager) ClientServiceAuthInterceptor() func(
+	ctx context.Context,
+	method string,
+	req interface{},
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	return func(
+		ctx context.Context,
+		method string,
+		req interface{},
+		reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		token, err := im.serviceAuth.ServiceToken()
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "service token: %v", err)
+		}
+		if token != "" {
+			ctx = authentication.AttachServiceTokenToContext(ctx, token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
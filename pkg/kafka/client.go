@@ -2,10 +2,44 @@ package kafka
 
 import (
 	"context"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
+	"net"
+	"strconv"
 )
 
-// NewKafkaConn create new kafka connection
+// NewKafkaConn create new kafka connection to the first reachable broker
 func NewKafkaConn(ctx context.Context, kafkaCfg *Config) (*kafka.Conn, error) {
-	return kafka.DialContext(ctx, "tcp", kafkaCfg.Brokers[0])
+	if len(kafkaCfg.Brokers) == 0 {
+		return nil, errors.New("no kafka brokers configured")
+	}
+	dialer, err := NewDialer(kafkaCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.NewDialer")
+	}
+	var lastErr error
+	for _, broker := range kafkaCfg.Brokers {
+		conn, err := dialer.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = errors.Wrapf(err, "dial broker %s", broker)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// NewControllerConn create new kafka connection to the cluster controller, as required for topic creation
+func NewControllerConn(ctx context.Context, kafkaCfg *Config, conn *kafka.Conn) (*kafka.Conn, error) {
+	controller, err := conn.Controller()
+	if err != nil {
+		return nil, errors.Wrap(err, "kafkaConn.Controller")
+	}
+	dialer, err := NewDialer(kafkaCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.NewDialer")
+	}
+	return dialer.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
 }
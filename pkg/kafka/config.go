@@ -2,9 +2,27 @@ package kafka
 
 // Config kafka config
 type Config struct {
-	Brokers    []string `mapstructure:"brokers"`
-	GroupID    string   `mapstructure:"groupID"`
-	InitTopics bool     `mapstructure:"initTopics"`
+	Brokers    []string   `mapstructure:"brokers"`
+	GroupID    string     `mapstructure:"groupID"`
+	InitTopics bool       `mapstructure:"initTopics"`
+	TLS        TLSConfig  `mapstructure:"tls"`
+	SASL       SASLConfig `mapstructure:"sasl"`
+}
+
+// TLSConfig kafka TLS config; CertFile and KeyFile are only needed when brokers require client certificates
+type TLSConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	CAFile     string `mapstructure:"caFile"`
+	CertFile   string `mapstructure:"certFile"`
+	KeyFile    string `mapstructure:"keyFile"`
+	ServerName string `mapstructure:"serverName"`
+}
+
+// SASLConfig kafka SASL config; Mechanism is one of PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512, empty disables SASL
+type SASLConfig struct {
+	Mechanism string `mapstructure:"mechanism"`
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
 }
 
 // TopicConfig kafka topic config
@@ -0,0 +1,139 @@
+package kafka
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+const (
+	// EnvelopeHeader marks a kafka message whose value is an EventEnvelope
+	EnvelopeHeader = "event-envelope"
+	// EnvelopeVersion is the version of the envelope format itself
+	EnvelopeVersion = "1"
+	// LegacySchemaVersion is assumed for messages published before the envelope existed
+	LegacySchemaVersion int32 = 1
+)
+
+type envelopeCtxKey struct{}
+type actorCtxKey struct{}
+
+// EventMetadata carries the causal metadata stamped onto a new EventEnvelope
+type EventMetadata struct {
+	CausationID   string
+	CorrelationID string
+	Actor         string
+}
+
+// ContextWithEnvelope stores the envelope of the message currently being processed in ctx
+func ContextWithEnvelope(ctx context.Context, envelope *kafkaMessages.EventEnvelope) context.Context {
+	return context.WithValue(ctx, envelopeCtxKey{}, envelope)
+}
+
+// EnvelopeFromContext returns the envelope of the message currently being processed, if any
+func EnvelopeFromContext(ctx context.Context) (*kafkaMessages.EventEnvelope, bool) {
+	envelope, ok := ctx.Value(envelopeCtxKey{}).(*kafkaMessages.EventEnvelope)
+	return envelope, ok && envelope != nil
+}
+
+// ContextWithActor stores the id of the principal that initiated the request in ctx
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// EventMetadataFromContext derives the metadata for an event caused by the work carried in ctx
+func EventMetadataFromContext(ctx context.Context) EventMetadata {
+	var meta EventMetadata
+	if actor, ok := ctx.Value(actorCtxKey{}).(string); ok {
+		meta.Actor = actor
+	}
+	if envelope, ok := EnvelopeFromContext(ctx); ok {
+		meta.CausationID = envelope.GetEventID()
+		meta.CorrelationID = envelope.GetCorrelationID()
+		if meta.CorrelationID == "" {
+			meta.CorrelationID = envelope.GetEventID()
+		}
+		if meta.Actor == "" {
+			meta.Actor = envelope.GetActor()
+		}
+	}
+	return meta
+}
+
+// NewEventEnvelope wraps a payload message in a versioned EventEnvelope
+func NewEventEnvelope(eventType string, version int32, aggregateID string, payload proto.Message, meta EventMetadata) (*kafkaMessages.EventEnvelope, error) {
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "proto.Marshal")
+	}
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.Wrap(err, "uuid.NewV4")
+	}
+	correlationID := meta.CorrelationID
+	if correlationID == "" {
+		correlationID = eventID.String()
+	}
+	return &kafkaMessages.EventEnvelope{
+		EventID:       eventID.String(),
+		EventType:     eventType,
+		SchemaVersion: version,
+		AggregateID:   aggregateID,
+		OccurredAt:    timestamppb.Now(),
+		CausationID:   meta.CausationID,
+		CorrelationID: correlationID,
+		Actor:         meta.Actor,
+		Payload:       payloadBytes,
+	}, nil
+}
+
+// NewEventMessage builds a kafka message carrying payload wrapped in an EventEnvelope at the current schema version
+func NewEventMessage(ctx context.Context, topic string, eventType string, aggregateID string, payload proto.Message, headers []messaging.Header) (messaging.Message, error) {
+	envelope, err := NewEventEnvelope(eventType, CurrentSchemaVersion(eventType), aggregateID, payload, EventMetadataFromContext(ctx))
+	if err != nil {
+		return messaging.Message{}, err
+	}
+	envelopeBytes, err := proto.Marshal(envelope)
+	if err != nil {
+		return messaging.Message{}, errors.Wrap(err, "proto.Marshal")
+	}
+	return messaging.Message{
+		Topic:   topic,
+		Key:     []byte(aggregateID),
+		Value:   envelopeBytes,
+		Time:    time.Now().UTC(),
+		Headers: append(headers, messaging.Header{Key: EnvelopeHeader, Value: []byte(EnvelopeVersion)}),
+	}, nil
+}
+
+// ReadEnvelope extracts the EventEnvelope from a kafka message,
+// wrapping messages published before the envelope existed as legacyType at LegacySchemaVersion
+func ReadEnvelope(m messaging.Message, legacyType string) (*kafkaMessages.EventEnvelope, error) {
+	if !hasEnvelopeHeader(m.Headers) {
+		return &kafkaMessages.EventEnvelope{
+			EventType:     legacyType,
+			SchemaVersion: LegacySchemaVersion,
+			OccurredAt:    timestamppb.New(m.Time),
+			Payload:       m.Value,
+		}, nil
+	}
+	envelope := &kafkaMessages.EventEnvelope{}
+	if err := proto.Unmarshal(m.Value, envelope); err != nil {
+		return nil, errors.Wrap(err, "proto.Unmarshal")
+	}
+	return envelope, nil
+}
+
+func hasEnvelopeHeader(headers []messaging.Header) bool {
+	for _, h := range headers {
+		if h.Key == EnvelopeHeader {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package kafka
+
+import (
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Event types carried in EventEnvelope.EventType
+const (
+	UserCreateEvent        = "UserCreate"
+	UserCreatedEvent       = "UserCreated"
+	UserUpdateEvent        = "UserUpdate"
+	UserUpdatedEvent       = "UserUpdated"
+	UserDeleteEvent        = "UserDelete"
+	UserDeletedEvent       = "UserDeleted"
+	UserStatusChangedEvent = "UserStatusChanged"
+	TokenBlacklistEvent    = "TokenBlacklist"
+	TokenBlacklistedEvent  = "TokenBlacklisted"
+	AuthenticateEvent      = "Authenticate"
+	AuthenticatedEvent     = "Authenticated"
+	ValidateEvent          = "Validate"
+	ValidatedEvent         = "Validated"
+	InvalidateEvent        = "Invalidate"
+	InvalidatedEvent       = "Invalidated"
+	PasswordUpdateEvent    = "PasswordUpdate"
+	PasswordUpdatedEvent   = "PasswordUpdated"
+	GroupCreateEvent       = "GroupCreate"
+	GroupCreatedEvent      = "GroupCreated"
+	GroupUpdateEvent       = "GroupUpdate"
+	GroupUpdatedEvent      = "GroupUpdated"
+	GroupDeleteEvent       = "GroupDelete"
+	GroupDeletedEvent      = "GroupDeleted"
+	MembershipCreateEvent  = "MembershipCreate"
+	MembershipCreatedEvent = "MembershipCreated"
+	MembershipUpdateEvent  = "MembershipUpdate"
+	MembershipUpdatedEvent = "MembershipUpdated"
+	MembershipDeleteEvent  = "MembershipDelete"
+	MembershipDeletedEvent = "MembershipDeleted"
+
+	GroupParentChangedEvent = "GroupParentChanged"
+
+	MembershipExtendEvent   = "MembershipExtend"
+	MembershipExtendedEvent = "MembershipExtended"
+	MembershipExpiringEvent = "MembershipExpiring"
+
+	OrganizationCreateEvent        = "OrganizationCreate"
+	OrganizationCreatedEvent       = "OrganizationCreated"
+	OrganizationUpdateEvent        = "OrganizationUpdate"
+	OrganizationUpdatedEvent       = "OrganizationUpdated"
+	OrganizationStatusChangeEvent  = "OrganizationStatusChange"
+	OrganizationStatusChangedEvent = "OrganizationStatusChanged"
+	OrganizationDeleteEvent        = "OrganizationDelete"
+	OrganizationDeletedEvent       = "OrganizationDeleted"
+
+	RoleCreateEvent  = "RoleCreate"
+	RoleCreatedEvent = "RoleCreated"
+	RoleUpdateEvent  = "RoleUpdate"
+	RoleUpdatedEvent = "RoleUpdated"
+	RoleDeleteEvent  = "RoleDelete"
+	RoleDeletedEvent = "RoleDeleted"
+)
+
+var defaultRegistry = NewDefaultEventRegistry()
+
+// NewDefaultEventRegistry constructs an EventRegistry with every kafkaMessages event registered.
+// When a payload changes shape, register the new decoder at the next version along with an
+// upcaster from the previous one, e.g.:
+//
+//	r.Register(UserCreatedEvent, 2, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserCreated{} }))
+//	r.RegisterUpcaster(UserCreatedEvent, 1, upcastUserCreatedV1)
+func NewDefaultEventRegistry() *EventRegistry {
+	r := NewEventRegistry()
+	r.Register(UserCreateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserCreate{} }))
+	r.Register(UserCreatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserCreated{} }))
+	r.Register(UserCreatedEvent, 2, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserCreated{} }))
+	r.RegisterUpcaster(UserCreatedEvent, 1, upcastDropPasswordHash)
+	r.Register(UserUpdateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserUpdate{} }))
+	r.Register(UserUpdatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserUpdated{} }))
+	r.Register(UserUpdatedEvent, 2, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserUpdated{} }))
+	r.RegisterUpcaster(UserUpdatedEvent, 1, upcastDropPasswordHash)
+	r.Register(UserDeleteEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserDelete{} }))
+	r.Register(UserDeletedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserDeleted{} }))
+	r.Register(UserStatusChangedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.UserStatusChanged{} }))
+	r.Register(TokenBlacklistEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.TokenBlacklist{} }))
+	r.Register(TokenBlacklistedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.TokenBlacklisted{} }))
+	r.Register(AuthenticateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.Authenticate{} }))
+	r.Register(AuthenticatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.Authenticated{} }))
+	r.Register(AuthenticatedEvent, 2, ProtoDecoder(func() proto.Message { return &kafkaMessages.Authenticated{} }))
+	r.RegisterUpcaster(AuthenticatedEvent, 1, upcastDropPasswordHash)
+	r.Register(ValidateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.Validate{} }))
+	r.Register(ValidatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.Validated{} }))
+	r.Register(ValidatedEvent, 2, ProtoDecoder(func() proto.Message { return &kafkaMessages.Validated{} }))
+	r.RegisterUpcaster(ValidatedEvent, 1, upcastDropPasswordHash)
+	r.Register(InvalidateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.Invalidate{} }))
+	r.Register(InvalidatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.Invalidated{} }))
+	r.Register(PasswordUpdateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.PasswordUpdate{} }))
+	r.Register(PasswordUpdatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.PasswordUpdated{} }))
+	r.Register(PasswordUpdatedEvent, 2, ProtoDecoder(func() proto.Message { return &kafkaMessages.PasswordUpdated{} }))
+	r.RegisterUpcaster(PasswordUpdatedEvent, 1, upcastDropPasswordHash)
+	r.Register(GroupCreateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.GroupCreate{} }))
+	r.Register(GroupCreatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.GroupCreated{} }))
+	r.Register(GroupUpdateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.GroupUpdate{} }))
+	r.Register(GroupUpdatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.GroupUpdated{} }))
+	r.Register(GroupDeleteEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.GroupDelete{} }))
+	r.Register(GroupDeletedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.GroupDeleted{} }))
+	r.Register(GroupParentChangedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.GroupParentChanged{} }))
+	r.Register(MembershipCreateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipCreate{} }))
+	r.Register(MembershipCreatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipCreated{} }))
+	r.Register(MembershipUpdateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipUpdate{} }))
+	r.Register(MembershipUpdatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipUpdated{} }))
+	r.Register(MembershipDeleteEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipDelete{} }))
+	r.Register(MembershipDeletedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipDeleted{} }))
+	r.Register(MembershipExtendEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipExtend{} }))
+	r.Register(MembershipExtendedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipExtended{} }))
+	r.Register(MembershipExpiringEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.MembershipExpiring{} }))
+	r.Register(OrganizationCreateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationCreate{} }))
+	r.Register(OrganizationCreatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationCreated{} }))
+	r.Register(OrganizationUpdateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationUpdate{} }))
+	r.Register(OrganizationUpdatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationUpdated{} }))
+	r.Register(OrganizationStatusChangeEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationStatusChange{} }))
+	r.Register(OrganizationStatusChangedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationStatusChanged{} }))
+	r.Register(OrganizationDeleteEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationDelete{} }))
+	r.Register(OrganizationDeletedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.OrganizationDeleted{} }))
+	r.Register(RoleCreateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.RoleCreate{} }))
+	r.Register(RoleCreatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.RoleCreated{} }))
+	r.Register(RoleUpdateEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.RoleUpdate{} }))
+	r.Register(RoleUpdatedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.RoleUpdated{} }))
+	r.Register(RoleDeleteEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.RoleDelete{} }))
+	r.Register(RoleDeletedEvent, 1, ProtoDecoder(func() proto.Message { return &kafkaMessages.RoleDeleted{} }))
+	return r
+}
+
+// upcastDropPasswordHash upcasts the v1 user and password events, which carried password hashes; the hashes
+// decode into the unknown fields of the reserved Password and NewPassword numbers and are dropped with them
+func upcastDropPasswordHash(msg proto.Message) (proto.Message, error) {
+	dropUnknownFields(msg.ProtoReflect())
+	return msg, nil
+}
+
+func dropUnknownFields(m protoreflect.Message) {
+	m.SetUnknown(nil)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			dropUnknownFields(v.Message())
+		}
+		return true
+	})
+}
+
+// DefaultEventRegistry returns the process wide EventRegistry
+func DefaultEventRegistry() *EventRegistry {
+	return defaultRegistry
+}
+
+// CurrentSchemaVersion returns the schema version producers write for an event type
+func CurrentSchemaVersion(eventType string) int32 {
+	return defaultRegistry.CurrentVersion(eventType)
+}
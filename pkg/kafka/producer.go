@@ -1,31 +0,0 @@
-package kafka
-
-import (
-	"context"
-	"github.com/JECSand/identity-service/pkg/logging"
-	"github.com/segmentio/kafka-go"
-)
-
-type Producer interface {
-	PublishMessage(ctx context.Context, msgs ...kafka.Message) error
-	Close() error
-}
-
-type producer struct {
-	log     logging.Logger
-	brokers []string
-	w       *kafka.Writer
-}
-
-// NewProducer create new kafka producer
-func NewProducer(log logging.Logger, brokers []string) *producer {
-	return &producer{log: log, brokers: brokers, w: NewWriter(brokers, kafka.LoggerFunc(log.Errorf))}
-}
-
-func (p *producer) PublishMessage(ctx context.Context, msgs ...kafka.Message) error {
-	return p.w.WriteMessages(ctx, msgs...)
-}
-
-func (p *producer) Close() error {
-	return p.w.Close()
-}
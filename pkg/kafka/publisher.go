@@ -0,0 +1,82 @@
+package kafka
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+type publisher struct {
+	log     logging.Logger
+	cfg     *Config
+	brokers []string
+	w       *kafka.Writer
+}
+
+// NewPublisher create new kafka backed messaging.Publisher
+func NewPublisher(log logging.Logger, cfg *Config) (*publisher, error) {
+	transport, err := NewTransport(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.NewTransport")
+	}
+	return &publisher{log: log, cfg: cfg, brokers: cfg.Brokers, w: NewWriter(cfg.Brokers, transport, kafka.LoggerFunc(log.Errorf))}, nil
+}
+
+func (p *publisher) Publish(ctx context.Context, msgs ...messaging.Message) error {
+	kafkaMsgs := make([]kafka.Message, 0, len(msgs))
+	for _, m := range msgs {
+		kafkaMsgs = append(kafkaMsgs, toKafkaMessage(m))
+	}
+	return p.w.WriteMessages(ctx, kafkaMsgs...)
+}
+
+// Ping checks that a broker is reachable and knows the cluster
+func (p *publisher) Ping(ctx context.Context) error {
+	conn, err := NewKafkaConn(ctx, p.cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() // nolint: errCheck
+	if _, err = conn.Brokers(); err != nil {
+		return errors.Wrap(err, "kafkaConn.Brokers")
+	}
+	return nil
+}
+
+func (p *publisher) Close() error {
+	return p.w.Close()
+}
+
+func toKafkaMessage(m messaging.Message) kafka.Message {
+	headers := make([]kafka.Header, 0, len(m.Headers))
+	for _, h := range m.Headers {
+		headers = append(headers, kafka.Header{Key: h.Key, Value: h.Value})
+	}
+	return kafka.Message{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Key:       m.Key,
+		Value:     m.Value,
+		Headers:   headers,
+		Time:      m.Time,
+	}
+}
+
+func fromKafkaMessage(m kafka.Message) messaging.Message {
+	headers := make([]messaging.Header, 0, len(m.Headers))
+	for _, h := range m.Headers {
+		headers = append(headers, messaging.Header{Key: h.Key, Value: h.Value})
+	}
+	return messaging.Message{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Key:       m.Key,
+		Value:     m.Value,
+		Headers:   headers,
+		Time:      m.Time,
+	}
+}
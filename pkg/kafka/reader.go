@@ -2,15 +2,14 @@ package kafka
 
 import (
 	"github.com/segmentio/kafka-go"
-	"time"
 )
 
-// NewKafkaReader create new configured kafka reader
-func NewKafkaReader(kafkaURL []string, topic, groupID string, errLogger kafka.Logger) *kafka.Reader {
+// NewKafkaReader create new configured kafka consumer group reader
+func NewKafkaReader(kafkaURL []string, groupTopics []string, groupID string, dialer *kafka.Dialer, errLogger kafka.Logger) *kafka.Reader {
 	return kafka.NewReader(kafka.ReaderConfig{
 		Brokers:                kafkaURL,
 		GroupID:                groupID,
-		Topic:                  topic,
+		GroupTopics:            groupTopics,
 		MinBytes:               minBytes,
 		MaxBytes:               maxBytes,
 		QueueCapacity:          queueCapacity,
@@ -19,9 +18,22 @@ func NewKafkaReader(kafkaURL []string, topic, groupID string, errLogger kafka.Lo
 		PartitionWatchInterval: partitionWatchInterval,
 		ErrorLogger:            errLogger,
 		MaxAttempts:            maxAttempts,
-		MaxWait:                time.Second,
-		Dialer: &kafka.Dialer{
-			Timeout: dialTimeout,
-		},
+		MaxWait:                maxWait,
+		Dialer:                 dialer,
+	})
+}
+
+// NewPartitionReader create new kafka reader bound to a single partition outside any consumer group
+func NewPartitionReader(kafkaURL []string, topic string, partition int, dialer *kafka.Dialer, errLogger kafka.Logger) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     kafkaURL,
+		Topic:       topic,
+		Partition:   partition,
+		MinBytes:    minBytes,
+		MaxBytes:    maxBytes,
+		ErrorLogger: errLogger,
+		MaxAttempts: maxAttempts,
+		MaxWait:     maxWait,
+		Dialer:      dialer,
 	})
 }
@@ -0,0 +1,130 @@
+package kafka
+
+import (
+	"fmt"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"sync"
+)
+
+// EventDecoder decodes an envelope payload written at a specific schema version
+type EventDecoder func(payload []byte) (proto.Message, error)
+
+// EventUpcaster converts a decoded event at schema version N into its shape at version N+1
+type EventUpcaster func(msg proto.Message) (proto.Message, error)
+
+type eventKey struct {
+	eventType string
+	version   int32
+}
+
+// EventRegistry maps (event type, schema version) pairs to decoders and upcasters
+type EventRegistry struct {
+	mu        sync.RWMutex
+	decoders  map[eventKey]EventDecoder
+	upcasters map[eventKey]EventUpcaster
+	current   map[string]int32
+}
+
+// NewEventRegistry constructs an empty EventRegistry
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{
+		decoders:  make(map[eventKey]EventDecoder),
+		upcasters: make(map[eventKey]EventUpcaster),
+		current:   make(map[string]int32),
+	}
+}
+
+// ProtoDecoder returns an EventDecoder that unmarshals into the message built by newMsg
+func ProtoDecoder(newMsg func() proto.Message) EventDecoder {
+	return func(payload []byte) (proto.Message, error) {
+		msg := newMsg()
+		if err := proto.Unmarshal(payload, msg); err != nil {
+			return nil, errors.Wrap(err, "proto.Unmarshal")
+		}
+		return msg, nil
+	}
+}
+
+// Register adds a decoder for an event type at a schema version; the highest registered version is the current one
+func (r *EventRegistry) Register(eventType string, version int32, decoder EventDecoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.decoders[eventKey{eventType, version}] = decoder
+	if version > r.current[eventType] {
+		r.current[eventType] = version
+	}
+}
+
+// RegisterUpcaster adds an upcaster converting an event type from fromVersion to fromVersion+1
+func (r *EventRegistry) RegisterUpcaster(eventType string, fromVersion int32, upcaster EventUpcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.upcasters[eventKey{eventType, fromVersion}] = upcaster
+}
+
+// CurrentVersion returns the schema version producers should write for an event type
+func (r *EventRegistry) CurrentVersion(eventType string) int32 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if v, ok := r.current[eventType]; ok {
+		return v
+	}
+	return LegacySchemaVersion
+}
+
+// Decode decodes an envelope payload and upcasts it to the current schema version of its event type
+func (r *EventRegistry) Decode(envelope *kafkaMessages.EventEnvelope) (proto.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	eventType, version := envelope.GetEventType(), envelope.GetSchemaVersion()
+	current, ok := r.current[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unregistered event type %q", eventType)
+	}
+	if version > current {
+		return nil, fmt.Errorf("event %q schema version %d is newer than supported version %d", eventType, version, current)
+	}
+	decoder, ok := r.decoders[eventKey{eventType, version}]
+	if !ok {
+		return nil, fmt.Errorf("no decoder for event %q schema version %d", eventType, version)
+	}
+	msg, err := decoder(envelope.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	for ; version < current; version++ {
+		upcaster, ok := r.upcasters[eventKey{eventType, version}]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster for event %q from schema version %d", eventType, version)
+		}
+		if msg, err = upcaster(msg); err != nil {
+			return nil, errors.Wrapf(err, "upcast %s v%d", eventType, version)
+		}
+	}
+	return msg, nil
+}
+
+// Unmarshal reads the envelope of m, decodes and upcasts its payload, and stores the result in dst.
+// Messages without an envelope are treated as eventType at LegacySchemaVersion.
+func (r *EventRegistry) Unmarshal(m messaging.Message, eventType string, dst proto.Message) (*kafkaMessages.EventEnvelope, error) {
+	envelope, err := ReadEnvelope(m, eventType)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.GetEventType() != eventType {
+		return nil, fmt.Errorf("unexpected event type %q on topic %s, want %q", envelope.GetEventType(), m.Topic, eventType)
+	}
+	msg, err := r.Decode(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if msg.ProtoReflect().Descriptor().FullName() != dst.ProtoReflect().Descriptor().FullName() {
+		return nil, fmt.Errorf("event %q decoded to %s, want %s", eventType, msg.ProtoReflect().Descriptor().FullName(), dst.ProtoReflect().Descriptor().FullName())
+	}
+	proto.Reset(dst)
+	proto.Merge(dst, msg)
+	return envelope, nil
+}
@@ -0,0 +1,145 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+const testPasswordHash = "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNo"
+
+// userCreatedV1 returns a UserCreated payload as written at schema version 1, when users still carried their
+// password hash in field 4
+func userCreatedV1(t *testing.T) []byte {
+	t.Helper()
+	user, err := proto.Marshal(&kafkaMessages.User{ID: "user-1", Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	user = protowire.AppendTag(user, 4, protowire.BytesType)
+	user = protowire.AppendString(user, testPasswordHash)
+	payload := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(payload, user)
+}
+
+func TestDefaultRegistryUpcastsUserCreatedV1(t *testing.T) {
+	if got := CurrentSchemaVersion(UserCreatedEvent); got != 2 {
+		t.Fatalf("current UserCreated version: got %d, want 2", got)
+	}
+	envelope, err := NewEventEnvelope(UserCreatedEvent, 1, "user-1", &kafkaMessages.UserCreated{}, EventMetadata{})
+	if err != nil {
+		t.Fatalf("NewEventEnvelope: %v", err)
+	}
+	envelope.Payload = userCreatedV1(t)
+	enveloped, err := proto.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	tests := []struct {
+		name    string
+		message messaging.Message
+	}{
+		{name: "enveloped v1", message: messaging.Message{Topic: "user_created", Value: enveloped, Headers: []messaging.Header{{Key: EnvelopeHeader, Value: []byte(EnvelopeVersion)}}}},
+		{name: "legacy without envelope", message: messaging.Message{Topic: "user_created", Value: userCreatedV1(t)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &kafkaMessages.UserCreated{}
+			read, err := DefaultEventRegistry().Unmarshal(tt.message, UserCreatedEvent, msg)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if read.GetSchemaVersion() != 1 {
+				t.Errorf("got envelope version %d, want 1", read.GetSchemaVersion())
+			}
+			if msg.GetUser().GetID() != "user-1" || msg.GetUser().GetEmail() != "user@example.com" || msg.GetUser().GetUsername() != "user" {
+				t.Errorf("got user %v, want its fields kept", msg.GetUser())
+			}
+			if unknown := msg.GetUser().ProtoReflect().GetUnknown(); len(unknown) != 0 {
+				t.Errorf("upcast user keeps unknown fields %x", unknown)
+			}
+			b, err := proto.Marshal(msg)
+			if err != nil {
+				t.Fatalf("proto.Marshal: %v", err)
+			}
+			if bytes.Contains(b, []byte(testPasswordHash)) {
+				t.Error("upcast user still carries the password hash")
+			}
+		})
+	}
+}
+
+func TestEventRegistryDecode(t *testing.T) {
+	const eventType = "TestGroup"
+	appendName := func(suffix string) EventUpcaster {
+		return func(msg proto.Message) (proto.Message, error) {
+			group := msg.(*kafkaMessages.Group)
+			group.Name += suffix
+			return group, nil
+		}
+	}
+	newRegistry := func(upcasters ...int32) *EventRegistry {
+		r := NewEventRegistry()
+		for v := int32(1); v <= 3; v++ {
+			r.Register(eventType, v, ProtoDecoder(func() proto.Message { return &kafkaMessages.Group{} }))
+		}
+		for _, from := range upcasters {
+			r.RegisterUpcaster(eventType, from, appendName(fmt.Sprintf("+v%d", from+1)))
+		}
+		return r
+	}
+	tests := []struct {
+		name      string
+		registry  *EventRegistry
+		eventType string
+		version   int32
+		wantName  string
+		wantErr   bool
+	}{
+		{name: "current version", registry: newRegistry(1, 2), eventType: eventType, version: 3, wantName: "group"},
+		{name: "one version behind", registry: newRegistry(1, 2), eventType: eventType, version: 2, wantName: "group+v3"},
+		{name: "upcasts v1 through every version", registry: newRegistry(1, 2), eventType: eventType, version: 1, wantName: "group+v2+v3"},
+		{name: "missing upcaster", registry: newRegistry(1), eventType: eventType, version: 1, wantErr: true},
+		{name: "newer than supported", registry: newRegistry(1, 2), eventType: eventType, version: 4, wantErr: true},
+		{name: "unregistered event type", registry: newRegistry(1, 2), eventType: "Unknown", version: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := NewEventEnvelope(tt.eventType, tt.version, "group-1", &kafkaMessages.Group{ID: "group-1", Name: "group"}, EventMetadata{})
+			if err != nil {
+				t.Fatalf("NewEventEnvelope: %v", err)
+			}
+			msg, err := tt.registry.Decode(envelope)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Decode: got %v, want an error", msg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if got := msg.(*kafkaMessages.Group).GetName(); got != tt.wantName {
+				t.Errorf("Decode: got name %q, want %q", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestEventRegistryUnmarshalRejectsOtherMessages(t *testing.T) {
+	m, err := NewEventMessage(context.Background(), "group_created", GroupCreatedEvent, "group-1", &kafkaMessages.GroupCreated{}, nil)
+	if err != nil {
+		t.Fatalf("NewEventMessage: %v", err)
+	}
+	if _, err = DefaultEventRegistry().Unmarshal(m, GroupCreatedEvent, &kafkaMessages.UserCreated{}); err == nil {
+		t.Error("Unmarshal decoded a GroupCreated event into a UserCreated message")
+	}
+	if _, err = DefaultEventRegistry().Unmarshal(m, GroupDeletedEvent, &kafkaMessages.GroupDeleted{}); err == nil {
+		t.Error("Unmarshal accepted a GroupCreated event on a GroupDeleted topic")
+	}
+}
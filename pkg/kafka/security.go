@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+	"os"
+	"strings"
+)
+
+// SASL mechanisms supported by SASLConfig.Mechanism
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// NewDialer create new kafka dialer applying the configured TLS and SASL settings
+func NewDialer(cfg *Config) (*kafka.Dialer, error) {
+	tlsCfg, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	mechanism, err := newSASLMechanism(cfg.SASL)
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Dialer{
+		Timeout:       dialTimeout,
+		DualStack:     true,
+		TLS:           tlsCfg,
+		SASLMechanism: mechanism,
+	}, nil
+}
+
+// NewTransport create new kafka writer transport applying the configured TLS and SASL settings
+func NewTransport(cfg *Config) (*kafka.Transport, error) {
+	tlsCfg, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	mechanism, err := newSASLMechanism(cfg.SASL)
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Transport{
+		DialTimeout: dialTimeout,
+		TLS:         tlsCfg,
+		SASL:        mechanism,
+	}, nil
+}
+
+func newTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "os.ReadFile")
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "tls.LoadX509KeyPair")
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
+func newSASLMechanism(cfg SASLConfig) (sasl.Mechanism, error) {
+	switch strings.ToUpper(cfg.Mechanism) {
+	case "":
+		return nil, nil
+	case SASLPlain:
+		return plain.Mechanism{Username: cfg.Username, Password: cfg.Password}, nil
+	case SASLScramSHA256:
+		mechanism, err := scram.Mechanism(scram.SHA256, cfg.Username, cfg.Password)
+		return mechanism, errors.Wrap(err, "scram.Mechanism")
+	case SASLScramSHA512:
+		mechanism, err := scram.Mechanism(scram.SHA512, cfg.Username, cfg.Password)
+		return mechanism, errors.Wrap(err, "scram.Mechanism")
+	default:
+		return nil, errors.Errorf("unsupported kafka SASL mechanism %q", cfg.Mechanism)
+	}
+}
+
+// ParseBrokers splits a comma separated broker list
+func ParseBrokers(brokers string) []string {
+	var parsed []string
+	for _, b := range strings.Split(brokers, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			parsed = append(parsed, b)
+		}
+	}
+	return parsed
+}
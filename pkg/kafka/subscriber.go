@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"sync"
+)
+
+type subscriber struct {
+	log    logging.Logger
+	cfg    *Config
+	dialer *kafka.Dialer
+}
+
+// NewSubscriber create new kafka backed messaging.Subscriber
+func NewSubscriber(log logging.Logger, cfg *Config) (*subscriber, error) {
+	dialer, err := NewDialer(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.NewDialer")
+	}
+	return &subscriber{log: log, cfg: cfg, dialer: dialer}, nil
+}
+
+// Subscribe joins the kafka consumer group groupID on topics
+func (s *subscriber) Subscribe(_ context.Context, groupID string, topics []string) (messaging.Reader, error) {
+	return &groupReader{r: NewKafkaReader(s.cfg.Brokers, topics, groupID, s.dialer, kafka.LoggerFunc(s.log.Errorf))}, nil
+}
+
+// Replay reads every partition of topic from the first offset outside any consumer group
+func (s *subscriber) Replay(ctx context.Context, topic string) (messaging.ReplayReader, error) {
+	conn, err := NewKafkaConn(ctx, s.cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.NewKafkaConn")
+	}
+	partitions, err := conn.ReadPartitions(topic)
+	conn.Close() // nolint: errCheck
+	if err != nil {
+		return nil, errors.Wrap(err, "kafkaConn.ReadPartitions")
+	}
+	rr := &replayReader{
+		msgs: make(chan messaging.Message, queueCapacity),
+		errs: make(chan error, len(partitions)),
+		done: make(chan struct{}),
+	}
+	for _, p := range partitions {
+		r := NewPartitionReader(s.cfg.Brokers, p.Topic, p.ID, s.dialer, kafka.LoggerFunc(s.log.Errorf))
+		if err = r.SetOffset(kafka.FirstOffset); err != nil {
+			rr.Close() // nolint: errCheck
+			return nil, errors.Wrap(err, "reader.SetOffset")
+		}
+		rr.readers = append(rr.readers, r)
+		rr.wg.Add(1)
+		go rr.pump(r)
+	}
+	return rr, nil
+}
+
+type groupReader struct {
+	r *kafka.Reader
+}
+
+func (g *groupReader) FetchMessage(ctx context.Context) (messaging.Message, error) {
+	m, err := g.r.FetchMessage(ctx)
+	if err != nil {
+		return messaging.Message{}, err
+	}
+	return fromKafkaMessage(m), nil
+}
+
+func (g *groupReader) CommitMessages(ctx context.Context, msgs ...messaging.Message) error {
+	kafkaMsgs := make([]kafka.Message, 0, len(msgs))
+	for _, m := range msgs {
+		kafkaMsgs = append(kafkaMsgs, toKafkaMessage(m))
+	}
+	return g.r.CommitMessages(ctx, kafkaMsgs...)
+}
+
+// Lag returns the lag kafka reported with the last fetch, which covers the partition fetched from last
+func (g *groupReader) Lag(context.Context) (int64, error) {
+	return g.r.Stats().Lag, nil
+}
+
+func (g *groupReader) Close() error {
+	return g.r.Close()
+}
+
+// replayReader merges one partition reader per partition into a single stream
+type replayReader struct {
+	readers []*kafka.Reader
+	msgs    chan messaging.Message
+	errs    chan error
+	done    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+func (rr *replayReader) pump(r *kafka.Reader) {
+	defer rr.wg.Done()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-rr.done
+		cancel()
+	}()
+	for {
+		m, err := r.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				select {
+				case rr.errs <- err:
+				default:
+				}
+			}
+			return
+		}
+		select {
+		case rr.msgs <- fromKafkaMessage(m):
+		case <-rr.done:
+			return
+		}
+	}
+}
+
+func (rr *replayReader) FetchMessage(ctx context.Context) (messaging.Message, error) {
+	select {
+	case <-ctx.Done():
+		return messaging.Message{}, ctx.Err()
+	case <-rr.done:
+		return messaging.Message{}, messaging.ErrClosed
+	case err := <-rr.errs:
+		return messaging.Message{}, err
+	case m := <-rr.msgs:
+		return m, nil
+	}
+}
+
+// CommitMessages is a no-op, replays keep no offsets
+func (rr *replayReader) CommitMessages(context.Context, ...messaging.Message) error {
+	return nil
+}
+
+// Lag returns the messages not yet read from the partitions plus those buffered for FetchMessage
+func (rr *replayReader) Lag(ctx context.Context) (int64, error) {
+	lag := int64(len(rr.msgs))
+	for _, r := range rr.readers {
+		l, err := r.ReadLag(ctx)
+		if err != nil {
+			return 0, errors.Wrap(err, "reader.ReadLag")
+		}
+		lag += l
+	}
+	return lag, nil
+}
+
+func (rr *replayReader) Close() error {
+	var err error
+	rr.once.Do(func() {
+		close(rr.done)
+		rr.wg.Wait()
+		for _, r := range rr.readers {
+			if cerr := r.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	})
+	return err
+}
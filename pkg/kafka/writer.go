@@ -6,10 +6,10 @@ import (
 )
 
 // NewWriter create new configured kafka writer
-func NewWriter(brokers []string, errLogger kafka.Logger) *kafka.Writer {
+func NewWriter(brokers []string, transport *kafka.Transport, errLogger kafka.Logger) *kafka.Writer {
 	w := &kafka.Writer{
 		Addr:         kafka.TCP(brokers...),
-		Balancer:     &kafka.LeastBytes{},
+		Balancer:     &kafka.Hash{}, // keyed by aggregate id so each aggregate keeps its event order
 		RequiredAcks: writerRequiredAcks,
 		MaxAttempts:  writerMaxAttempts,
 		ErrorLogger:  errLogger,
@@ -17,6 +17,7 @@ func NewWriter(brokers []string, errLogger kafka.Logger) *kafka.Writer {
 		ReadTimeout:  writerReadTimeout,
 		WriteTimeout: writerWriteTimeout,
 		Async:        false,
+		Transport:    transport,
 	}
 	return w
 }
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/constants"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/url"
+)
+
+// ContextWithRequestID adds the request ID to the baggage of ctx, so it follows the request to every service
+// the work reaches over gRPC and Kafka
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return contextWithMember(ctx, constants.RequestID, requestID)
+}
+
+// ContextWithUserID adds the ID of the authenticated user to the baggage of ctx
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return contextWithMember(ctx, constants.UserID, userID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	return memberFromContext(ctx, constants.RequestID)
+}
+
+// UserIDFromContext returns the user ID carried by ctx, if any
+func UserIDFromContext(ctx context.Context) string {
+	return memberFromContext(ctx, constants.UserID)
+}
+
+func contextWithMember(ctx context.Context, key string, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	member, err := baggage.NewMember(key, url.PathEscape(value))
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+func memberFromContext(ctx context.Context, key string) string {
+	value := baggage.FromContext(ctx).Member(key).Value()
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// contextFields returns the request ID, user ID, trace ID and span ID carried by ctx
+func contextFields(ctx context.Context) []zap.Field {
+	fields := make([]zap.Field, 0, 4)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, zap.String(constants.RequestID, requestID))
+	}
+	if userID := UserIDFromContext(ctx); userID != "" {
+		fields = append(fields, zap.String(constants.UserID, userID))
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		fields = append(fields,
+			zap.String(constants.TraceID, spanCtx.TraceID().String()),
+			zap.String(constants.SpanID, spanCtx.SpanID().String()),
+		)
+	}
+	return fields
+}
@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"context"
 	"github.com/JECSand/identity-service/pkg/constants"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -9,9 +10,20 @@ import (
 )
 
 type Config struct {
-	LogLevel string `mapstructure:"level"`
-	DevMode  bool   `mapstructure:"devMode"`
-	Encoder  string `mapstructure:"encoder"`
+	LogLevel  string          `mapstructure:"level"`
+	DevMode   bool            `mapstructure:"devMode"`
+	Encoder   string          `mapstructure:"encoder"`
+	Redaction RedactionConfig `mapstructure:"redaction"`
+	Sampling  SamplingConfig  `mapstructure:"sampling"`
+}
+
+// SamplingConfig caps the access and Kafka message entries logged per tick: the first Initial entries with the same
+// level and message are logged, then every Thereafter-th one
+type SamplingConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	TickMillis int  `mapstructure:"tickMillis"`
+	Initial    int  `mapstructure:"initial"`
+	Thereafter int  `mapstructure:"thereafter"`
 }
 
 func NewLoggerConfig(logLevel string, devMode bool, encoder string) *Config {
@@ -38,11 +50,12 @@ type Logger interface {
 	Fatalf(template string, args ...interface{})
 	Printf(template string, args ...interface{})
 	WithName(name string)
-	HttpMiddlewareAccessLogger(method string, uri string, status int, size int64, time time.Duration)
-	GrpcMiddlewareAccessLogger(method string, time time.Duration, metaData map[string][]string, err error)
-	GrpcClientInterceptorLogger(method string, req interface{}, reply interface{}, time time.Duration, metaData map[string][]string, err error)
-	KafkaProcessMessage(topic string, partition int, message string, workerID int, offset int64, time time.Time)
-	KafkaLogCommittedMessage(topic string, partition int, offset int64)
+	WithContext(ctx context.Context) Logger
+	HttpMiddlewareAccessLogger(ctx context.Context, method string, uri string, status int, size int64, time time.Duration)
+	GrpcMiddlewareAccessLogger(ctx context.Context, method string, time time.Duration, metaData map[string][]string, err error)
+	GrpcClientInterceptorLogger(ctx context.Context, method string, req interface{}, reply interface{}, time time.Duration, metaData map[string][]string, err error)
+	KafkaProcessMessage(ctx context.Context, topic string, partition int, key []byte, size int, workerID int, offset int64, time time.Time)
+	KafkaLogCommittedMessage(ctx context.Context, topic string, partition int, offset int64)
 }
 
 // Application logger
@@ -50,13 +63,22 @@ type appLogger struct {
 	level       string
 	devMode     bool
 	encoding    string
+	redaction   RedactionConfig
+	sampling    SamplingConfig
 	sugarLogger *zap.SugaredLogger
 	logger      *zap.Logger
+	sampled     *zap.Logger
 }
 
 // NewAppLogger App Logger constructor
 func NewAppLogger(cfg *Config) *appLogger {
-	return &appLogger{level: cfg.LogLevel, devMode: cfg.DevMode, encoding: cfg.Encoder}
+	return &appLogger{
+		level:     cfg.LogLevel,
+		devMode:   cfg.DevMode,
+		encoding:  cfg.Encoder,
+		redaction: cfg.Redaction,
+		sampling:  cfg.Sampling,
+	}
 }
 
 // For mapping config logger to email_service logger levels
@@ -112,10 +134,19 @@ func (l *appLogger) InitLogger() {
 	}
 
 	core := zapcore.NewCore(encoder, logWriter, zap.NewAtomicLevelAt(logLevel))
+	if l.redaction.Enabled {
+		core = &redactCore{Core: core, r: newRedactor(l.redaction)}
+	}
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 
 	l.logger = logger
 	l.sugarLogger = logger.Sugar()
+	l.sampled = logger
+	if l.sampling.Enabled {
+		l.sampled = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Duration(l.sampling.TickMillis)*time.Millisecond, l.sampling.Initial, l.sampling.Thereafter)
+		}))
+	}
 }
 
 // Logger methods
@@ -124,6 +155,20 @@ func (l *appLogger) InitLogger() {
 func (l *appLogger) WithName(name string) {
 	l.logger = l.logger.Named(name)
 	l.sugarLogger = l.sugarLogger.Named(name)
+	l.sampled = l.sampled.Named(name)
+}
+
+// WithContext returns a logger adding the request ID, user ID, trace ID and span ID carried by ctx to every entry
+func (l *appLogger) WithContext(ctx context.Context) Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	scoped := *l
+	scoped.logger = l.logger.With(fields...)
+	scoped.sugarLogger = scoped.logger.Sugar()
+	scoped.sampled = l.sampled.With(fields...)
+	return &scoped
 }
 
 // Debug uses fmt.Sprint to construct and log a message.
@@ -217,56 +262,52 @@ func (l *appLogger) Sync() error {
 	return l.sugarLogger.Sync()
 }
 
-func (l *appLogger) HttpMiddlewareAccessLogger(method, uri string, status int, size int64, time time.Duration) {
-	l.logger.Info(
-		constants.HTTP,
+func (l *appLogger) HttpMiddlewareAccessLogger(ctx context.Context, method, uri string, status int, size int64, time time.Duration) {
+	l.sampled.Info(constants.HTTP, append(contextFields(ctx),
 		zap.String(constants.METHOD, method),
 		zap.String(constants.URI, uri),
 		zap.Int(constants.STATUS, status),
 		zap.Int64(constants.SIZE, size),
 		zap.Duration(constants.TIME, time),
-	)
+	)...)
 }
 
-func (l *appLogger) GrpcMiddlewareAccessLogger(method string, time time.Duration, metaData map[string][]string, err error) {
-	l.logger.Info(
-		constants.GRPC,
+func (l *appLogger) GrpcMiddlewareAccessLogger(ctx context.Context, method string, time time.Duration, metaData map[string][]string, err error) {
+	l.sampled.Info(constants.GRPC, append(contextFields(ctx),
 		zap.String(constants.METHOD, method),
 		zap.Duration(constants.TIME, time),
 		zap.Any(constants.METADATA, metaData),
 		zap.Error(err),
-	)
+	)...)
 }
 
-func (l *appLogger) GrpcClientInterceptorLogger(method string, req, reply interface{}, time time.Duration, metaData map[string][]string, err error) {
-	l.logger.Info(
-		constants.GRPC,
+func (l *appLogger) GrpcClientInterceptorLogger(ctx context.Context, method string, req, reply interface{}, time time.Duration, metaData map[string][]string, err error) {
+	l.sampled.Info(constants.GRPC, append(contextFields(ctx),
 		zap.String(constants.METHOD, method),
 		zap.Any(constants.REQUEST, req),
 		zap.Any(constants.REPLY, reply),
 		zap.Duration(constants.TIME, time),
 		zap.Any(constants.METADATA, metaData),
 		zap.Error(err),
-	)
+	)...)
 }
 
-func (l *appLogger) KafkaProcessMessage(topic string, partition int, message string, workerID int, offset int64, time time.Time) {
-	l.logger.Debug(
-		"Processing Kafka message",
+func (l *appLogger) KafkaProcessMessage(ctx context.Context, topic string, partition int, key []byte, size int, workerID int, offset int64, time time.Time) {
+	l.sampled.Debug("Processing Kafka message", append(contextFields(ctx),
 		zap.String(constants.Topic, topic),
 		zap.Int(constants.Partition, partition),
-		zap.String(constants.Message, message),
+		zap.ByteString(constants.Key, key),
+		zap.Int(constants.Bytes, size),
 		zap.Int(constants.WorkerID, workerID),
 		zap.Int64(constants.Offset, offset),
 		zap.Time(constants.Time, time),
-	)
+	)...)
 }
 
-func (l *appLogger) KafkaLogCommittedMessage(topic string, partition int, offset int64) {
-	l.logger.Info(
-		"Committed Kafka message",
+func (l *appLogger) KafkaLogCommittedMessage(ctx context.Context, topic string, partition int, offset int64) {
+	l.sampled.Info("Committed Kafka message", append(contextFields(ctx),
 		zap.String(constants.Topic, topic),
 		zap.Int(constants.Partition, partition),
 		zap.Int64(constants.Offset, offset),
-	)
+	)...)
 }
@@ -0,0 +1,191 @@
+package logging
+
+import (
+	"encoding/json"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"strings"
+)
+
+const defaultRedactionMask = "[REDACTED]"
+
+// defaultRedactedFields are masked when redaction is enabled without a field list; they cover the credentials,
+// tokens and emails of the identity messages in both their proto and JSON spellings
+var defaultRedactedFields = []string{
+	"password", "currentPassword", "current_password", "newPassword", "new_password", "currentHash", "newHash",
+	"accessToken", "access_token", "refreshToken", "refresh_token", "token", "clientSecret", "client_secret",
+	"authorization", "service-authorization", "email",
+}
+
+// RedactionConfig selects the field keys masked in log entries, at any depth
+type RedactionConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Fields  []string `mapstructure:"fields"`
+	Mask    string   `mapstructure:"mask"`
+}
+
+// redactor masks the values of configured field keys, matched case-insensitively
+type redactor struct {
+	keys map[string]struct{}
+	mask string
+}
+
+func newRedactor(cfg RedactionConfig) *redactor {
+	fields := cfg.Fields
+	if len(fields) == 0 {
+		fields = defaultRedactedFields
+	}
+	r := &redactor{keys: make(map[string]struct{}, len(fields)), mask: cfg.Mask}
+	if r.mask == "" {
+		r.mask = defaultRedactionMask
+	}
+	for _, field := range fields {
+		r.keys[strings.ToLower(field)] = struct{}{}
+	}
+	return r
+}
+
+func (r *redactor) redacts(key string) bool {
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+func (r *redactor) fields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = r.field(field)
+	}
+	return redacted
+}
+
+func (r *redactor) field(field zapcore.Field) zapcore.Field {
+	switch field.Type {
+	case zapcore.SkipType:
+		return field
+	case zapcore.InlineMarshalerType:
+		field.Interface = redactedObject{marshaler: field.Interface.(zapcore.ObjectMarshaler), r: r}
+		return field
+	}
+	if r.redacts(field.Key) {
+		return zap.String(field.Key, r.mask)
+	}
+	switch field.Type {
+	case zapcore.ReflectType:
+		field.Interface = r.value(field.Interface)
+	case zapcore.StringerType:
+		if msg, ok := field.Interface.(proto.Message); ok {
+			return zap.Reflect(field.Key, r.value(msg))
+		}
+	case zapcore.ObjectMarshalerType:
+		field.Interface = redactedObject{marshaler: field.Interface.(zapcore.ObjectMarshaler), r: r}
+	}
+	return field
+}
+
+// value returns a JSON-shaped copy of v with redacted keys masked; proto messages are rendered with protojson
+func (r *redactor) value(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	var raw []byte
+	var err error
+	if msg, ok := v.(proto.Message); ok {
+		raw, err = protojson.Marshal(msg)
+	} else {
+		raw, err = json.Marshal(v)
+	}
+	if err != nil {
+		return r.mask
+	}
+	var decoded interface{}
+	if err = json.Unmarshal(raw, &decoded); err != nil {
+		return r.mask
+	}
+	return r.walk(decoded)
+}
+
+func (r *redactor) walk(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for key, value := range typed {
+			if r.redacts(key) {
+				typed[key] = r.mask
+			} else {
+				typed[key] = r.walk(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range typed {
+			typed[i] = r.walk(value)
+		}
+	}
+	return v
+}
+
+// redactCore masks redacted fields of every entry before handing it to the wrapped core
+type redactCore struct {
+	zapcore.Core
+	r *redactor
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.fields(fields)), r: c.r}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.r.fields(fields))
+}
+
+// redactedObject marshals an object through an encoder masking redacted keys
+type redactedObject struct {
+	marshaler zapcore.ObjectMarshaler
+	r         *redactor
+}
+
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.marshaler.MarshalLogObject(redactEncoder{ObjectEncoder: enc, r: o.r})
+}
+
+type redactEncoder struct {
+	zapcore.ObjectEncoder
+	r *redactor
+}
+
+func (e redactEncoder) AddString(key string, value string) {
+	if e.r.redacts(key) {
+		value = e.r.mask
+	}
+	e.ObjectEncoder.AddString(key, value)
+}
+
+func (e redactEncoder) AddByteString(key string, value []byte) {
+	if e.r.redacts(key) {
+		value = []byte(e.r.mask)
+	}
+	e.ObjectEncoder.AddByteString(key, value)
+}
+
+func (e redactEncoder) AddReflected(key string, value interface{}) error {
+	if e.r.redacts(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return nil
+	}
+	return e.ObjectEncoder.AddReflected(key, e.r.value(value))
+}
+
+func (e redactEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	if e.r.redacts(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, redactedObject{marshaler: marshaler, r: e.r})
+}
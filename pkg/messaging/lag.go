@@ -0,0 +1,65 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+)
+
+// LagMonitor is a Subscriber tracking the consumer group readers it opens, so probes can check how far behind
+// the consumers are
+type LagMonitor struct {
+	Subscriber
+	mu      sync.Mutex
+	readers map[*monitoredReader]struct{}
+}
+
+// NewLagMonitor wraps sub
+func NewLagMonitor(sub Subscriber) *LagMonitor {
+	return &LagMonitor{Subscriber: sub, readers: make(map[*monitoredReader]struct{})}
+}
+
+// Subscribe joins groupID through the wrapped Subscriber and tracks the reader until it is closed
+func (m *LagMonitor) Subscribe(ctx context.Context, groupID string, topics []string) (Reader, error) {
+	r, err := m.Subscriber.Subscribe(ctx, groupID, topics)
+	if err != nil {
+		return nil, err
+	}
+	mr := &monitoredReader{Reader: r, monitor: m}
+	m.mu.Lock()
+	m.readers[mr] = struct{}{}
+	m.mu.Unlock()
+	return mr, nil
+}
+
+// Lag sums the lag of the open readers able to report it
+func (m *LagMonitor) Lag(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	laggers := make([]Lagger, 0, len(m.readers))
+	for r := range m.readers {
+		if l, ok := r.Reader.(Lagger); ok {
+			laggers = append(laggers, l)
+		}
+	}
+	m.mu.Unlock()
+	var total int64
+	for _, l := range laggers {
+		lag, err := l.Lag(ctx)
+		if err != nil {
+			return 0, err
+		}
+		total += lag
+	}
+	return total, nil
+}
+
+type monitoredReader struct {
+	Reader
+	monitor *LagMonitor
+}
+
+func (r *monitoredReader) Close() error {
+	r.monitor.mu.Lock()
+	delete(r.monitor.readers, r)
+	r.monitor.mu.Unlock()
+	return r.Reader.Close()
+}
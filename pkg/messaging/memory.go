@@ -0,0 +1,236 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const defaultMemoryPartitions = 4
+
+// ErrClosed is returned by readers of a closed bus or a closed reader
+var ErrClosed = errors.New("messaging: closed")
+
+// memoryBus is an in-process Publisher and Subscriber.
+// Topics are split into partitions by message key and each consumer group tracks a fetch cursor and a
+// committed offset per partition, mirroring kafka consumer group semantics.
+type memoryBus struct {
+	mu         sync.Mutex
+	partitions int
+	topics     map[string][][]Message
+	groups     map[string]*memoryGroup
+	notify     chan struct{}
+	closed     bool
+}
+
+type memoryGroup struct {
+	cursors   map[string][]int64
+	committed map[string][]int64
+	readers   int
+}
+
+// NewMemoryBus constructs an in-process bus with the given number of partitions per topic
+func NewMemoryBus(partitions int) *memoryBus {
+	if partitions < 1 {
+		partitions = defaultMemoryPartitions
+	}
+	return &memoryBus{
+		partitions: partitions,
+		topics:     make(map[string][][]Message),
+		groups:     make(map[string]*memoryGroup),
+		notify:     make(chan struct{}),
+	}
+}
+
+// topic returns the partitions of name, creating them on first use; callers hold b.mu
+func (b *memoryBus) topic(name string) [][]Message {
+	parts, ok := b.topics[name]
+	if !ok {
+		parts = make([][]Message, b.partitions)
+		b.topics[name] = parts
+	}
+	return parts
+}
+
+func (b *memoryBus) partitionFor(key []byte) int {
+	if len(key) == 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write(key) // nolint: errCheck
+	return int(h.Sum32() % uint32(b.partitions))
+}
+
+// Publish appends msgs to their topic partitions and wakes waiting readers
+func (b *memoryBus) Publish(ctx context.Context, msgs ...Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	now := time.Now()
+	for _, m := range msgs {
+		parts := b.topic(m.Topic)
+		p := b.partitionFor(m.Key)
+		m.Partition = p
+		m.Offset = int64(len(parts[p]))
+		if m.Time.IsZero() {
+			m.Time = now
+		}
+		m.Headers = append([]Header(nil), m.Headers...)
+		parts[p] = append(parts[p], m)
+	}
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return nil
+}
+
+// Close stops the bus; blocked readers return ErrClosed
+func (b *memoryBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.notify)
+	}
+	return nil
+}
+
+// Subscribe joins groupID; a group resumes from its committed offsets once it has no open readers
+func (b *memoryBus) Subscribe(_ context.Context, groupID string, topics []string) (Reader, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	g, ok := b.groups[groupID]
+	if !ok {
+		g = &memoryGroup{cursors: make(map[string][]int64), committed: make(map[string][]int64)}
+		b.groups[groupID] = g
+	}
+	if g.readers == 0 {
+		// uncommitted messages handed to a previous generation of readers are redelivered
+		for t, committed := range g.committed {
+			g.cursors[t] = append([]int64(nil), committed...)
+		}
+	}
+	for _, t := range topics {
+		b.topic(t)
+		if _, ok := g.cursors[t]; !ok {
+			g.cursors[t] = make([]int64, b.partitions)
+			g.committed[t] = make([]int64, b.partitions)
+		}
+	}
+	g.readers++
+	return &memoryReader{bus: b, group: g, topics: append([]string(nil), topics...)}, nil
+}
+
+// Replay reads topic from its first message with a private cursor
+func (b *memoryBus) Replay(_ context.Context, topic string) (ReplayReader, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	b.topic(topic)
+	g := &memoryGroup{
+		cursors:   map[string][]int64{topic: make([]int64, b.partitions)},
+		committed: map[string][]int64{topic: make([]int64, b.partitions)},
+		readers:   1,
+	}
+	return &memoryReader{bus: b, group: g, topics: []string{topic}}, nil
+}
+
+type memoryReader struct {
+	bus    *memoryBus
+	group  *memoryGroup
+	topics []string
+	next   int
+	closed bool
+}
+
+// FetchMessage returns the next unfetched message of the subscribed topics, blocking until one is published
+func (r *memoryReader) FetchMessage(ctx context.Context) (Message, error) {
+	for {
+		r.bus.mu.Lock()
+		if r.closed || r.bus.closed {
+			r.bus.mu.Unlock()
+			return Message{}, ErrClosed
+		}
+		if m, ok := r.take(); ok {
+			r.bus.mu.Unlock()
+			return m, nil
+		}
+		notify := r.bus.notify
+		r.bus.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// take hands out the next message round robin across subscribed partitions; callers hold bus.mu
+func (r *memoryReader) take() (Message, bool) {
+	slots := len(r.topics) * r.bus.partitions
+	for i := 0; i < slots; i++ {
+		slot := (r.next + i) % slots
+		t, p := r.topics[slot/r.bus.partitions], slot%r.bus.partitions
+		cursor := r.group.cursors[t][p]
+		if log := r.bus.topics[t][p]; cursor < int64(len(log)) {
+			r.group.cursors[t][p] = cursor + 1
+			r.next = slot + 1
+			return log[cursor], true
+		}
+	}
+	return Message{}, false
+}
+
+// CommitMessages advances the committed offset of each message partition past the message
+func (r *memoryReader) CommitMessages(_ context.Context, msgs ...Message) error {
+	r.bus.mu.Lock()
+	defer r.bus.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	for _, m := range msgs {
+		committed, ok := r.group.committed[m.Topic]
+		if !ok || m.Partition < 0 || m.Partition >= len(committed) {
+			continue
+		}
+		if m.Offset+1 > committed[m.Partition] {
+			committed[m.Partition] = m.Offset + 1
+		}
+	}
+	return nil
+}
+
+// Lag returns the number of published messages not yet fetched by this reader
+func (r *memoryReader) Lag(context.Context) (int64, error) {
+	r.bus.mu.Lock()
+	defer r.bus.mu.Unlock()
+	var lag int64
+	for _, t := range r.topics {
+		for p, log := range r.bus.topics[t] {
+			lag += int64(len(log)) - r.group.cursors[t][p]
+		}
+	}
+	return lag, nil
+}
+
+// Close leaves the group
+func (r *memoryReader) Close() error {
+	r.bus.mu.Lock()
+	defer r.bus.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		r.group.readers--
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package messaging
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"sync"
+	"time"
+)
+
+// Header is a message header
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is a transport neutral message; Partition and Offset are assigned by the bus on delivery
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+	Time      time.Time
+}
+
+// Publisher publishes messages; messages sharing a key are delivered in publish order
+type Publisher interface {
+	Publish(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Pinger is implemented by publishers able to check their connection to the transport
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Reader fetches messages for a subscription and commits them once processed.
+// Committing a message commits every earlier message of its partition; uncommitted messages are redelivered
+// to the next reader of the same group.
+type Reader interface {
+	FetchMessage(ctx context.Context) (Message, error)
+	CommitMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Lagger reports how many published messages a reader has not fetched yet
+type Lagger interface {
+	Lag(ctx context.Context) (int64, error)
+}
+
+// ReplayReader reads a topic from its earliest message without a consumer group
+type ReplayReader interface {
+	Reader
+	Lagger
+}
+
+// Subscriber creates readers for consumer groups and topic replays
+type Subscriber interface {
+	Subscribe(ctx context.Context, groupID string, topics []string) (Reader, error)
+	Replay(ctx context.Context, topic string) (ReplayReader, error)
+}
+
+// Worker fetches and processes messages from r until ctx is done
+type Worker func(ctx context.Context, r Reader, wg *sync.WaitGroup, workerID int)
+
+// ConsumeTopics subscribes groupID to topics and runs poolSize workers against the shared reader until they return
+func ConsumeTopics(ctx context.Context, log logging.Logger, sub Subscriber, groupID string, topics []string, poolSize int, worker Worker) {
+	r, err := sub.Subscribe(ctx, groupID, topics)
+	if err != nil {
+		log.Errorf("messaging.Subscribe: %v", err)
+		return
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.Warnf("messaging.Reader.Close: %v", err)
+		}
+	}()
+	log.Infof("Starting consumer groupID: %s, topic: %+v, pool size: %v", groupID, topics, poolSize)
+	wg := &sync.WaitGroup{}
+	for i := 0; i <= poolSize; i++ {
+		wg.Add(1)
+		go worker(ctx, r, wg, i)
+	}
+	wg.Wait()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"time"
 )
 
@@ -30,7 +31,8 @@ func NewMongoDBConn(ctx context.Context, cfg *Config) (*mongo.Client, error) {
 			SetConnectTimeout(connectTimeout).
 			SetMaxConnIdleTime(maxConnIdleTime).
 			SetMinPoolSize(minPoolSize).
-			SetMaxPoolSize(maxPoolSize))
+			SetMaxPoolSize(maxPoolSize).
+			SetMonitor(otelmongo.NewMonitor()))
 	if err != nil {
 		return nil, err
 	}
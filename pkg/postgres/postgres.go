@@ -53,3 +53,14 @@ func NewPostgresConn(cfg *Config) (*pgxpool.Pool, error) {
 	}
 	return connPool, nil
 }
+
+// PoolCheck fails when every pooled connection is in use, otherwise pings the database through the pool
+func PoolCheck(pool *pgxpool.Pool) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		stat := pool.Stat()
+		if stat.AcquiredConns() >= stat.MaxConns() {
+			return fmt.Errorf("connection pool exhausted: %d of %d connections in use", stat.AcquiredConns(), stat.MaxConns())
+		}
+		return pool.Ping(ctx)
+	}
+}
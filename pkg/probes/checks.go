@@ -0,0 +1,52 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"github.com/JECSand/identity-service/pkg/messaging"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// GrpcCheck asks the grpc.health.v1 service behind conn whether service is serving, "" meaning the whole server
+func GrpcCheck(conn grpc.ClientConnInterface, service string) func(ctx context.Context) error {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		res, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return errors.Wrap(err, "health.Check")
+		}
+		if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("grpc health status %s", res.GetStatus())
+		}
+		return nil
+	}
+}
+
+// LagCheck fails while the consumers are more than max messages behind; max <= 0 disables the threshold
+func LagCheck(lagger messaging.Lagger, max int64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if max <= 0 {
+			return nil
+		}
+		lag, err := lagger.Lag(ctx)
+		if err != nil {
+			return errors.Wrap(err, "Lag")
+		}
+		if lag > max {
+			return fmt.Errorf("consumer lag %d exceeds %d", lag, max)
+		}
+		return nil
+	}
+}
+
+// PingCheck checks the transport connection of pub when it supports pings
+func PingCheck(pub messaging.Publisher) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if pinger, ok := pub.(messaging.Pinger); ok {
+			return pinger.Ping(ctx)
+		}
+		return nil
+	}
+}
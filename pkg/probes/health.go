@@ -0,0 +1,172 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/heptiolabs/healthcheck"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Draining is the readiness check failing once shutdown has started
+	Draining = "draining"
+
+	defaultCheckInterval = 10 * time.Second
+	defaultCheckTimeout  = 5 * time.Second
+	readHeaderTimeout    = 5 * time.Second
+	shutdownTimeout      = 5 * time.Second
+)
+
+// Health serves liveness and readiness on the probes port and mirrors readiness into the grpc.health.v1 service.
+// Readiness checks run asynchronously every CheckIntervalSeconds so probes only read cached results.
+type Health struct {
+	log      logging.Logger
+	cfg      *Config
+	handler  healthcheck.Handler
+	grpc     *health.Server
+	mu       sync.Mutex
+	ready    []healthcheck.Check
+	draining atomic.Bool
+}
+
+// NewHealth ...
+func NewHealth(log logging.Logger, cfg *Config) *Health {
+	h := &Health{
+		log:     log,
+		cfg:     cfg,
+		handler: healthcheck.NewHandler(),
+		grpc:    health.NewServer(),
+	}
+	h.grpc.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	draining := func() error {
+		if h.draining.Load() {
+			return fmt.Errorf("shutting down")
+		}
+		return nil
+	}
+	h.handler.AddReadinessCheck(Draining, draining)
+	h.ready = append(h.ready, draining)
+	return h
+}
+
+func (h *Health) interval() time.Duration {
+	if h.cfg.CheckIntervalSeconds > 0 {
+		return time.Duration(h.cfg.CheckIntervalSeconds) * time.Second
+	}
+	return defaultCheckInterval
+}
+
+func (h *Health) timeout() time.Duration {
+	if h.cfg.CheckTimeoutSeconds > 0 {
+		return time.Duration(h.cfg.CheckTimeoutSeconds) * time.Second
+	}
+	return defaultCheckTimeout
+}
+
+func (h *Health) async(ctx context.Context, check func(ctx context.Context) error) healthcheck.Check {
+	return healthcheck.AsyncWithContext(ctx, func() error {
+		checkCtx, cancel := context.WithTimeout(ctx, h.timeout())
+		defer cancel()
+		return check(checkCtx)
+	}, h.interval())
+}
+
+// AddLivenessCheck runs check every interval until ctx is done; a failing liveness check restarts the process
+func (h *Health) AddLivenessCheck(ctx context.Context, name string, check func(ctx context.Context) error) {
+	c := h.async(ctx, check)
+	h.handler.AddLivenessCheck(name, c)
+	h.mu.Lock()
+	h.ready = append(h.ready, c)
+	h.mu.Unlock()
+}
+
+// AddReadinessCheck runs check every interval until ctx is done; a failing readiness check takes the process
+// out of rotation
+func (h *Health) AddReadinessCheck(ctx context.Context, name string, check func(ctx context.Context) error) {
+	c := h.async(ctx, check)
+	h.handler.AddReadinessCheck(name, c)
+	h.mu.Lock()
+	h.ready = append(h.ready, c)
+	h.mu.Unlock()
+}
+
+// Ready returns the first failing liveness or readiness check
+func (h *Health) Ready() error {
+	h.mu.Lock()
+	checks := append([]healthcheck.Check(nil), h.ready...)
+	h.mu.Unlock()
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterGrpc registers the grpc.health.v1 service on s and keeps its serving status in line with readiness
+// until ctx is done
+func (h *Health) RegisterGrpc(ctx context.Context, s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, h.grpc)
+	go h.syncGrpc(ctx)
+}
+
+// Serve serves the liveness and readiness endpoints on the probes port until the returned stop func is called
+func (h *Health) Serve() func() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(h.cfg.LivenessPath, h.handler.LiveEndpoint)
+	mux.HandleFunc(h.cfg.ReadinessPath, h.handler.ReadyEndpoint)
+	srv := &http.Server{Addr: h.cfg.Port, Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+	go func() {
+		h.log.Infof("Kubernetes probes listening on port: %s", h.cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			h.log.WarnMsg("ListenAndServe", err)
+		}
+	}()
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
+
+func (h *Health) syncGrpc(ctx context.Context) {
+	ticker := time.NewTicker(h.interval())
+	defer ticker.Stop()
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		if err := h.Ready(); err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		// ignored once Drain shut the health server down
+		h.grpc.SetServingStatus("", status)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain fails readiness and the gRPC health service, then waits DrainSeconds so load balancers stop routing new
+// requests before the servers shut down
+func (h *Health) Drain(ctx context.Context) {
+	h.draining.Store(true)
+	h.grpc.Shutdown()
+	if h.cfg.DrainSeconds <= 0 {
+		return
+	}
+	h.log.Infof("draining for %ds before shutdown", h.cfg.DrainSeconds)
+	timer := time.NewTimer(time.Duration(h.cfg.DrainSeconds) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,34 @@
+package probes
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"net/http"
+	"net/http/pprof"
+)
+
+// ServePprof serves the net/http/pprof handlers on the pprof port when one is configured, until the returned
+// stop func is called
+func ServePprof(log logging.Logger, cfg *Config) func() error {
+	if cfg.Pprof == "" {
+		return func() error { return nil }
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	srv := &http.Server{Addr: cfg.Pprof, Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+	go func() {
+		log.Infof("pprof listening on port: %s", cfg.Pprof)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WarnMsg("pprof.ListenAndServe", err)
+		}
+	}()
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
@@ -8,4 +8,7 @@ type Config struct {
 	PrometheusPath       string `mapstructure:"prometheusPath"`
 	PrometheusPort       string `mapstructure:"prometheusPort"`
 	CheckIntervalSeconds int    `mapstructure:"checkIntervalSeconds"`
+	CheckTimeoutSeconds  int    `mapstructure:"checkTimeoutSeconds"`
+	DrainSeconds         int    `mapstructure:"drainSeconds"`
+	MaxConsumerLag       int64  `mapstructure:"maxConsumerLag"`
 }
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type memoryLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryLimiter returns a Limiter keeping the state of its keys in process, for single instances and as a
+// fallback of the redis limiter
+func NewMemoryLimiter() *memoryLimiter {
+	return &memoryLimiter{tats: make(map[string]time.Time), lastSweep: time.Now(), now: time.Now}
+}
+
+func (m *memoryLimiter) Allow(_ context.Context, key string, limit Limit) (*Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.sweep(now)
+	res, tat := gcra(now, m.tats[key], limit)
+	if res.Allowed {
+		m.tats[key] = tat
+	}
+	return res, nil
+}
+
+func (m *memoryLimiter) Refund(_ context.Context, key string, limit Limit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tat, ok := m.tats[key]
+	if !ok {
+		return nil
+	}
+	if tat, ok = refund(m.now(), tat, limit); ok {
+		m.tats[key] = tat
+	} else {
+		delete(m.tats, key)
+	}
+	return nil
+}
+
+// sweep drops the keys whose bucket has refilled, at most once per sweepInterval
+func (m *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, tat := range m.tats {
+		if !tat.After(now) {
+			delete(m.tats, key)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when advanced
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(clock *fakeClock) *memoryLimiter {
+	m := NewMemoryLimiter()
+	m.now, m.lastSweep = clock.Now, clock.Now()
+	return m
+}
+
+// step is a request of a limiter test after advancing the clock, with its expected result
+type step struct {
+	advance    time.Duration
+	key        string
+	allowed    bool
+	remaining  int
+	resetAfter time.Duration
+	retryAfter time.Duration
+}
+
+func TestMemoryLimiterAllow(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit Limit
+		steps []step
+	}{
+		{
+			name:  "burst then reject",
+			limit: Limit{Rate: 2, Period: time.Second, Burst: 3},
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2, resetAfter: 500 * time.Millisecond},
+				{key: "a", allowed: true, remaining: 1, resetAfter: time.Second},
+				{key: "a", allowed: true, remaining: 0, resetAfter: 1500 * time.Millisecond},
+				{key: "a", allowed: false, remaining: 0, resetAfter: 1500 * time.Millisecond, retryAfter: 500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "refills one request per emission interval",
+			limit: Limit{Rate: 2, Period: time.Second, Burst: 3},
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2, resetAfter: 500 * time.Millisecond},
+				{key: "a", allowed: true, remaining: 1, resetAfter: time.Second},
+				{key: "a", allowed: true, remaining: 0, resetAfter: 1500 * time.Millisecond},
+				{advance: 250 * time.Millisecond, key: "a", allowed: false, remaining: 0, resetAfter: 1250 * time.Millisecond, retryAfter: 250 * time.Millisecond},
+				{advance: 250 * time.Millisecond, key: "a", allowed: true, remaining: 0, resetAfter: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "resets after the bucket refills",
+			limit: Limit{Rate: 2, Period: time.Second, Burst: 3},
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2, resetAfter: 500 * time.Millisecond},
+				{key: "a", allowed: true, remaining: 1, resetAfter: time.Second},
+				{advance: 5 * time.Second, key: "a", allowed: true, remaining: 2, resetAfter: 500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "burst defaults to rate",
+			limit: Limit{Rate: 1, Period: time.Minute},
+			steps: []step{
+				{key: "a", allowed: true, remaining: 0, resetAfter: time.Minute},
+				{advance: 20 * time.Second, key: "a", allowed: false, remaining: 0, resetAfter: 40 * time.Second, retryAfter: 40 * time.Second},
+				{advance: 40 * time.Second, key: "a", allowed: true, remaining: 0, resetAfter: time.Minute},
+			},
+		},
+		{
+			name:  "keys are limited separately",
+			limit: Limit{Rate: 1, Period: time.Minute},
+			steps: []step{
+				{key: "a", allowed: true, remaining: 0, resetAfter: time.Minute},
+				{key: "a", allowed: false, remaining: 0, resetAfter: time.Minute, retryAfter: time.Minute},
+				{key: "b", allowed: true, remaining: 0, resetAfter: time.Minute},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+			m := newTestLimiter(clock)
+			for i, s := range tt.steps {
+				clock.Advance(s.advance)
+				res, err := m.Allow(context.Background(), s.key, tt.limit)
+				if err != nil {
+					t.Fatalf("step %d: Allow: %v", i, err)
+				}
+				if res.Allowed != s.allowed || res.Remaining != s.remaining || res.ResetAfter != s.resetAfter || res.RetryAfter != s.retryAfter {
+					t.Errorf("step %d: got allowed=%v remaining=%d reset=%v retry=%v, want allowed=%v remaining=%d reset=%v retry=%v",
+						i, res.Allowed, res.Remaining, res.ResetAfter, res.RetryAfter, s.allowed, s.remaining, s.resetAfter, s.retryAfter)
+				}
+				if res.Limit != tt.limit.Quota() {
+					t.Errorf("step %d: got limit %d, want %d", i, res.Limit, tt.limit.Quota())
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryLimiterSweepsRefilledKeys(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m := newTestLimiter(clock)
+	limit := Limit{Rate: 1, Period: time.Second}
+	if _, err := m.Allow(context.Background(), "a", limit); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	clock.Advance(sweepInterval)
+	if _, err := m.Allow(context.Background(), "b", limit); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if _, ok := m.tats["a"]; ok {
+		t.Error("refilled key a was not swept")
+	}
+	if _, ok := m.tats["b"]; !ok {
+		t.Error("key b was swept")
+	}
+}
+
+func TestMemoryLimiterRefund(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m := newTestLimiter(clock)
+	limit := Limit{Rate: 2, Period: time.Second, Burst: 3}
+	for i := 0; i < 3; i++ {
+		if _, err := m.Allow(ctx, "a", limit); err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+	}
+	if err := m.Refund(ctx, "a", limit); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	res, err := m.Allow(ctx, "a", limit)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if !res.Allowed || res.Remaining != 0 {
+		t.Errorf("after a refund: got allowed=%v remaining=%d, want allowed=true remaining=0", res.Allowed, res.Remaining)
+	}
+	if err = m.Refund(ctx, "a", limit); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	clock.Advance(time.Second)
+	if err = m.Refund(ctx, "a", limit); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if _, ok := m.tats["a"]; ok {
+		t.Error("key a with a full bucket was kept")
+	}
+	if err = m.Refund(ctx, "unknown", limit); err != nil {
+		t.Errorf("Refund of an unknown key: %v", err)
+	}
+	if _, ok := m.tats["unknown"]; ok {
+		t.Error("Refund stored an unknown key")
+	}
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"strings"
+	"time"
+)
+
+// Limiter stores
+const (
+	StoreRedis  = "redis"
+	StoreMemory = "memory"
+)
+
+// Limit scopes
+const (
+	ScopeIP     = "ip"
+	ScopeUser   = "user"
+	ScopeClient = "client"
+)
+
+// Config structures the request rate limits of a service
+type Config struct {
+	Enabled           bool    `mapstructure:"enabled"`
+	Store             string  `mapstructure:"store"`
+	KeyPrefix         string  `mapstructure:"keyPrefix"`
+	TrustProxyHeaders bool    `mapstructure:"trustProxyHeaders"`
+	Groups            []Group `mapstructure:"groups"`
+}
+
+// Group limits the requests to a set of routes per client IP, per authenticated user and per API client.
+// Routes are "METHOD /path" or "/path" entries matched against route templates, a trailing * matches any suffix;
+// a group without routes matches every request.
+type Group struct {
+	Name   string   `mapstructure:"name"`
+	Routes []string `mapstructure:"routes"`
+	IP     Limit    `mapstructure:"ip"`
+	User   Limit    `mapstructure:"user"`
+	Client Limit    `mapstructure:"client"`
+}
+
+// Match returns the first group of the config matching the route, or nil
+func (c *Config) Match(method string, path string) *Group {
+	for i := range c.Groups {
+		if c.Groups[i].matches(method, path) {
+			return &c.Groups[i]
+		}
+	}
+	return nil
+}
+
+func (g *Group) matches(method string, path string) bool {
+	if len(g.Routes) == 0 {
+		return true
+	}
+	for _, route := range g.Routes {
+		routeMethod, routePath, ok := strings.Cut(route, " ")
+		if !ok {
+			routeMethod, routePath = "", route
+		}
+		if routeMethod != "" && !strings.EqualFold(routeMethod, method) {
+			continue
+		}
+		if prefix, wildcard := strings.CutSuffix(routePath, "*"); wildcard && strings.HasPrefix(path, prefix) || routePath == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit allows Rate requests per Period in bursts of up to Burst requests, Burst defaults to Rate.
+// A zero Rate leaves requests unlimited.
+type Limit struct {
+	Rate   int           `mapstructure:"rate"`
+	Period time.Duration `mapstructure:"period"`
+	Burst  int           `mapstructure:"burst"`
+}
+
+// Enabled reports whether the limit restricts requests
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Period > 0
+}
+
+// Quota returns the number of requests a full bucket allows at once
+func (l Limit) Quota() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// emissionInterval is the time a single request takes from the bucket to refill
+func (l Limit) emissionInterval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// burstOffset is how far ahead of now the theoretical arrival time of the next request may run
+func (l Limit) burstOffset() time.Duration {
+	return l.emissionInterval() * time.Duration(l.Quota())
+}
+
+// Result is a limiter decision in the terms of the RateLimit header fields
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter applies the generic cell rate algorithm (GCRA) to the requests identified by a key. Refund gives an allowed
+// request back to the bucket of key, for requests that another key rejects.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+	Refund(ctx context.Context, key string, limit Limit) error
+}
+
+// gcra decides a request arriving at now given the theoretical arrival time tat of the key,
+// returning the decision and the tat to store when the request is allowed
+func gcra(now time.Time, tat time.Time, limit Limit) (*Result, time.Time) {
+	if tat.Before(now) {
+		tat = now
+	}
+	interval := limit.emissionInterval()
+	offset := limit.burstOffset()
+	newTat := tat.Add(interval)
+	wait := newTat.Sub(now)
+	if wait > offset {
+		return &Result{Limit: limit.Quota(), ResetAfter: tat.Sub(now), RetryAfter: wait - offset}, tat
+	}
+	return &Result{
+		Allowed:    true,
+		Limit:      limit.Quota(),
+		Remaining:  int((offset - wait) / interval),
+		ResetAfter: wait,
+	}, newTat
+}
+
+// refund returns the tat of a key holding tat at now once a request is given back, and whether the bucket is still
+// partially drained
+func refund(now time.Time, tat time.Time, limit Limit) (time.Time, bool) {
+	tat = tat.Add(-limit.emissionInterval())
+	return tat, tat.After(now)
+}
+
+type fallbackLimiter struct {
+	log      logging.Logger
+	primary  Limiter
+	fallback Limiter
+}
+
+// NewFallbackLimiter returns a Limiter deciding with fallback whenever primary fails, e.g. while redis is unreachable
+func NewFallbackLimiter(log logging.Logger, primary Limiter, fallback Limiter) *fallbackLimiter {
+	return &fallbackLimiter{log: log, primary: primary, fallback: fallback}
+}
+
+func (f *fallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	res, err := f.primary.Allow(ctx, key, limit)
+	if err == nil {
+		return res, nil
+	}
+	f.log.WithContext(ctx).WarnMsg("ratelimit.primary.Allow", err)
+	return f.fallback.Allow(ctx, key, limit)
+}
+
+func (f *fallbackLimiter) Refund(ctx context.Context, key string, limit Limit) error {
+	err := f.primary.Refund(ctx, key, limit)
+	if err == nil {
+		return nil
+	}
+	f.log.WithContext(ctx).WarnMsg("ratelimit.primary.Refund", err)
+	return f.fallback.Refund(ctx, key, limit)
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// gcraScript applies the GCRA to KEYS[1] on the redis clock, so every gateway instance shares one view of a key.
+// ARGV holds the emission interval and burst offset in microseconds; the reply is allowed, remaining, reset after
+// and retry after, the durations in microseconds.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local interval = tonumber(ARGV[1])
+local offset = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local wait = new_tat - now
+if wait > offset then
+	return {0, 0, tat - now, wait - offset}
+end
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil(wait / 1000))
+return {1, math.floor((offset - wait) / interval), wait, 0}
+`)
+
+// refundScript gives one request of the emission interval in ARGV[1] microseconds back to KEYS[1], dropping the key
+// once its bucket is full again
+var refundScript = redis.NewScript(`
+redis.replicate_commands()
+local interval = tonumber(ARGV[1])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat then
+	return 0
+end
+tat = tat - interval
+if tat <= now then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+redis.call('SET', KEYS[1], tat, 'PX', math.ceil((tat - now) / 1000))
+return 1
+`)
+
+type redisLimiter struct {
+	redisClient redis.UniversalClient
+	keyPrefix   string
+}
+
+// NewRedisLimiter returns a Limiter keeping the state of its keys in redis under keyPrefix
+func NewRedisLimiter(redisClient redis.UniversalClient, keyPrefix string) *redisLimiter {
+	return &redisLimiter{redisClient: redisClient, keyPrefix: keyPrefix}
+}
+
+func (r *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	reply, err := gcraScript.Run(ctx, r.redisClient, []string{r.keyPrefix + key},
+		limit.emissionInterval().Microseconds(),
+		limit.burstOffset().Microseconds(),
+	).Int64Slice()
+	if err != nil {
+		return nil, errors.Wrap(err, "gcraScript.Run")
+	}
+	if len(reply) != 4 {
+		return nil, errors.Errorf("gcraScript.Run: unexpected reply %v", reply)
+	}
+	return &Result{
+		Allowed:    reply[0] == 1,
+		Limit:      limit.Quota(),
+		Remaining:  int(reply[1]),
+		ResetAfter: time.Duration(reply[2]) * time.Microsecond,
+		RetryAfter: time.Duration(reply[3]) * time.Microsecond,
+	}, nil
+}
+
+func (r *redisLimiter) Refund(ctx context.Context, key string, limit Limit) error {
+	if err := refundScript.Run(ctx, r.redisClient, []string{r.keyPrefix + key}, limit.emissionInterval().Microseconds()).Err(); err != nil {
+		return errors.Wrap(err, "refundScript.Run")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"github.com/go-redis/redis/extra/redisotel/v8"
 	"github.com/go-redis/redis/v8"
 	"time"
 )
@@ -25,9 +26,9 @@ const (
 	idleTimeout     = 12 * time.Second
 )
 
-// NewRedisClient returns a redis.UniversalClient
+// NewRedisClient returns a redis.UniversalClient tracing its commands as client spans
 func NewRedisClient(cfg *Config) redis.UniversalClient {
-	return redis.NewUniversalClient(&redis.UniversalOptions{
+	client := redis.NewUniversalClient(&redis.UniversalOptions{
 		Addrs:           []string{cfg.Addr},
 		Password:        cfg.Password, // no password set
 		DB:              cfg.DB,       // use default DB
@@ -42,4 +43,6 @@ func NewRedisClient(cfg *Config) redis.UniversalClient {
 		PoolTimeout:     poolTimeout,
 		IdleTimeout:     idleTimeout,
 	})
+	client.AddHook(redisotel.NewTracingHook())
+	return client
 }
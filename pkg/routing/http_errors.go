@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"net/http"
 	"strings"
 	"time"
@@ -21,6 +24,8 @@ const (
 	ErrInvalidEmail        = "Invalid email"
 	ErrInvalidPassword     = "Invalid password"
 	ErrInvalidField        = "Invalid field"
+	ErrForbidden           = "Forbidden"
+	ErrConflict            = "Conflict"
 	ErrInternalServerError = "Internal Server Error"
 )
 
@@ -168,8 +173,14 @@ func NewInternalServerError(ctx echo.Context, causes interface{}, debug bool) er
 
 // ParseErrors Parser of error string messages returns RestError
 func ParseErrors(err error, debug bool) RestErr {
+	var policyErr *authentication.PasswordPolicyError
+	var conflictErr *authentication.IdentifierConflictError
 	switch {
-	case errors.Is(err, sql.ErrNoRows):
+	case errors.As(err, &policyErr):
+		return NewRestErrorWithMessage(http.StatusBadRequest, ErrInvalidPassword, policyErr.Violations)
+	case errors.As(err, &conflictErr):
+		return NewRestErrorWithMessage(http.StatusConflict, ErrConflict, conflictErr)
+	case errors.Is(err, sql.ErrNoRows), status.Code(err) == codes.NotFound:
 		return NewRestError(http.StatusNotFound, ErrNotFound, err.Error(), debug)
 	case errors.Is(err, context.DeadlineExceeded):
 		return NewRestError(http.StatusRequestTimeout, ErrRequestTimeout, err.Error(), debug)
@@ -177,6 +188,30 @@ func ParseErrors(err error, debug bool) RestErr {
 		return NewRestError(http.StatusUnauthorized, ErrUnauthorized, err.Error(), debug)
 	case errors.Is(err, WrongCredentials):
 		return NewRestError(http.StatusUnauthorized, ErrUnauthorized, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "not found in organization"):
+		return NewRestError(http.StatusNotFound, ErrNotFound, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "organization admin rights required"),
+		strings.Contains(strings.ToLower(err.Error()), "root admin rights required"):
+		return NewRestError(http.StatusForbidden, ErrForbidden, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "organization is suspended"):
+		return NewRestError(http.StatusForbidden, ErrForbidden, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "user status transition"):
+		return NewRestError(http.StatusConflict, ErrConflict, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "group hierarchy cycle"),
+		strings.Contains(strings.ToLower(err.Error()), "parent group belongs to another organization"):
+		return NewRestError(http.StatusConflict, ErrConflict, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "invalid permission"),
+		strings.Contains(strings.ToLower(err.Error()), "invalid action"),
+		strings.Contains(strings.ToLower(err.Error()), "role name is reserved"),
+		strings.Contains(strings.ToLower(err.Error()), "invalid membership validity window"):
+		return NewRestError(http.StatusBadRequest, ErrBadRequest, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "built-in roles cannot be changed"):
+		return NewRestError(http.StatusForbidden, ErrForbidden, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "role belongs to another group or organization"),
+		strings.Contains(strings.ToLower(err.Error()), "role group belongs to another organization"):
+		return NewRestError(http.StatusConflict, ErrConflict, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "user status"):
+		return NewRestError(http.StatusForbidden, ErrForbidden, err.Error(), debug)
 	case strings.Contains(strings.ToLower(err.Error()), "sqlstate"):
 		return parseSqlErrors(err, debug)
 	case strings.Contains(strings.ToLower(err.Error()), "field validation"):
@@ -198,6 +233,8 @@ func ParseErrors(err error, debug bool) RestErr {
 		return NewRestError(http.StatusUnauthorized, ErrUnauthorized, err.Error(), debug)
 	case strings.Contains(strings.ToLower(err.Error()), "bcrypt"):
 		return NewRestError(http.StatusBadRequest, ErrBadRequest, err.Error(), debug)
+	case strings.Contains(strings.ToLower(err.Error()), "password hash"):
+		return NewRestError(http.StatusBadRequest, ErrBadRequest, err.Error(), debug)
 	case strings.Contains(strings.ToLower(err.Error()), "no documents in result"):
 		return NewRestError(http.StatusNotFound, ErrNotFound, err.Error(), debug)
 	default:
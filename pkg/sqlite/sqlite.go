@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/pkg/errors"
+	_ "modernc.org/sqlite" // registers the pure Go sqlite driver
+	"time"
+)
+
+// Config structures embedded sqlite settings for the command database
+type Config struct {
+	Path        string `mapstructure:"path"`
+	BusyTimeout int    `mapstructure:"busyTimeoutMillis"`
+}
+
+const (
+	driverName         = "sqlite"
+	defaultBusyTimeout = 5000
+	connectTimeout     = 10 * time.Second
+)
+
+// NewSqliteConn opens the database file at cfg.Path, enabling foreign keys and write-ahead logging
+func NewSqliteConn(cfg *Config) (*sql.DB, error) {
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = defaultBusyTimeout
+	}
+	dataSourceName := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)",
+		cfg.Path,
+		busyTimeout,
+	)
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "sql.Open")
+	}
+	// sqlite serializes writers, a single connection avoids SQLITE_BUSY between pooled connections
+	db.SetMaxOpenConns(1)
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		db.Close() // nolint: errCheck
+		return nil, errors.Wrap(err, "db.PingContext")
+	}
+	return db, nil
+}
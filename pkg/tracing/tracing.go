@@ -1,34 +1,98 @@
 package tracing
 
 import (
-	"github.com/opentracing/opentracing-go"
-	"github.com/uber/jaeger-client-go"
-	"github.com/uber/jaeger-client-go/config"
-	"io"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"time"
 )
 
-// Config structures jaeger settings for service tracing
+const shutdownTimeout = 5 * time.Second
+
+// Span exporters
+const (
+	ExporterOtlpGrpc = "otlpgrpc"
+	ExporterOtlpHttp = "otlphttp"
+	ExporterStdout   = "stdout"
+)
+
+// Config structures OpenTelemetry settings for service tracing
 type Config struct {
-	ServiceName string `mapstructure:"serviceName"`
-	HostPort    string `mapstructure:"hostPort"`
-	Enable      bool   `mapstructure:"enable"`
-	LogSpans    bool   `mapstructure:"logSpans"`
+	ServiceName string  `mapstructure:"serviceName"`
+	Enable      bool    `mapstructure:"enable"`
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sampleRatio"`
+}
+
+// NewTracerProvider installs the W3C trace context and baggage propagators and, when tracing is enabled, a global
+// tracer provider exporting spans with cfg.Exporter. The returned stop func flushes and stops the provider.
+// An empty Endpoint leaves the exporter to the standard OTEL_EXPORTER_OTLP_* environment variables.
+func NewTracerProvider(ctx context.Context, cfg *Config) (func() error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if cfg == nil || !cfg.Enable {
+		return func() error { return nil }, nil
+	}
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, errors.Wrap(err, "resource.Merge")
+	}
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(tp)
+	return func() error {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return tp.Shutdown(shutdownCtx)
+	}, nil
 }
 
-// NewJaegerTracer constructs a new tracer
-func NewJaegerTracer(jaegerConfig *Config) (opentracing.Tracer, io.Closer, error) {
-	cfg := &config.Configuration{
-		ServiceName: jaegerConfig.ServiceName,
-		// "const" sampler is a binary sampling strategy: 0=never sample, 1=always sample.
-		Sampler: &config.SamplerConfig{
-			Type:  "const",
-			Param: 1,
-		},
-		// Log the emitted spans to stdout.
-		Reporter: &config.ReporterConfig{
-			LogSpans:           jaegerConfig.LogSpans,
-			LocalAgentHostPort: jaegerConfig.HostPort,
-		},
+func newExporter(ctx context.Context, cfg *Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOtlpGrpc, "":
+		var opts []otlptracegrpc.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		return exporter, errors.Wrap(err, "otlptracegrpc.New")
+	case ExporterOtlpHttp:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		return exporter, errors.Wrap(err, "otlptracehttp.New")
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, errors.Wrap(err, "stdouttrace.New")
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q, want %s, %s or %s", cfg.Exporter, ExporterOtlpGrpc, ExporterOtlpHttp, ExporterStdout)
 	}
-	return cfg.NewTracer(config.Logger(jaeger.StdLogger))
 }
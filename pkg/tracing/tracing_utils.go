@@ -2,113 +2,112 @@ package tracing
 
 import (
 	"context"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/labstack/echo/v4"
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/grpc/metadata"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// StartHttpServerTracerSpan ...
-func StartHttpServerTracerSpan(c echo.Context, operationName string) (context.Context, opentracing.Span) {
-	spanCtx, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(c.Request().Header))
-	if err != nil {
-		serverSpan := opentracing.GlobalTracer().StartSpan(operationName)
-		ctx := opentracing.ContextWithSpan(c.Request().Context(), serverSpan)
-		return ctx, serverSpan
-	}
-	serverSpan := opentracing.GlobalTracer().StartSpan(operationName, ext.RPCServerOption(spanCtx))
-	ctx := opentracing.ContextWithSpan(c.Request().Context(), serverSpan)
-	return ctx, serverSpan
+// InstrumentationName names the tracer of the identity service code
+const InstrumentationName = "github.com/JECSand/identity-service"
+
+// Tracer returns the tracer of the identity service code from the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
 }
 
-// GetTextMapCarrierFromMetaData ...
-func GetTextMapCarrierFromMetaData(ctx context.Context) opentracing.TextMapCarrier {
-	metadataMap := make(opentracing.TextMapCarrier)
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		for key := range md.Copy() {
-			metadataMap.Set(key, md.Get(key)[0])
-		}
-	}
-	return metadataMap
+// StartSpan starts an internal span as a child of the span in ctx
+func StartSpan(ctx context.Context, operationName string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, operationName)
 }
 
-// StartGrpcServerTracerSpan ...
-func StartGrpcServerTracerSpan(ctx context.Context, operationName string) (context.Context, opentracing.Span) {
-	textMapCarrierFromMetaData := GetTextMapCarrierFromMetaData(ctx)
-	span, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, textMapCarrierFromMetaData)
-	if err != nil {
-		serverSpan := opentracing.GlobalTracer().StartSpan(operationName)
-		ctx = opentracing.ContextWithSpan(ctx, serverSpan)
-		return ctx, serverSpan
-	}
-	serverSpan := opentracing.GlobalTracer().StartSpan(operationName, ext.RPCServerOption(span))
-	ctx = opentracing.ContextWithSpan(ctx, serverSpan)
-	return ctx, serverSpan
+// StartHttpServerTracerSpan starts a handler span under the server span the otelecho middleware put in the request
+func StartHttpServerTracerSpan(c echo.Context, operationName string) (context.Context, trace.Span) {
+	return Tracer().Start(c.Request().Context(), operationName)
 }
 
-// StartKafkaConsumerTracerSpan ...
-func StartKafkaConsumerTracerSpan(ctx context.Context, headers []kafka.Header, operationName string) (context.Context, opentracing.Span) {
-	carrierFromKafkaHeaders := TextMapCarrierFromKafkaMessageHeaders(headers)
-	spanCtx, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, carrierFromKafkaHeaders)
-	if err != nil {
-		serverSpan := opentracing.GlobalTracer().StartSpan(operationName)
-		ctx = opentracing.ContextWithSpan(ctx, serverSpan)
-		return ctx, serverSpan
-	}
-	serverSpan := opentracing.GlobalTracer().StartSpan(operationName, ext.RPCServerOption(spanCtx))
-	ctx = opentracing.ContextWithSpan(ctx, serverSpan)
-	return ctx, serverSpan
+// StartGrpcServerTracerSpan starts a handler span under the server span the otelgrpc stats handler put in ctx
+func StartGrpcServerTracerSpan(ctx context.Context, operationName string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, operationName)
 }
 
-// TextMapCarrierToKafkaMessageHeaders ...
-func TextMapCarrierToKafkaMessageHeaders(textMap opentracing.TextMapCarrier) []kafka.Header {
-	headers := make([]kafka.Header, 0, len(textMap))
-	if err := textMap.ForeachKey(func(key, val string) error {
-		headers = append(headers, kafka.Header{
-			Key:   key,
-			Value: []byte(val),
-		})
-		return nil
-	}); err != nil {
-		return headers
-	}
+// StartDbSpan starts a client span for a database call, named after its operation and table as the database
+// semantic conventions ask
+func StartDbSpan(ctx context.Context, system attribute.KeyValue, operation string, table string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, operation+" "+table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(system, semconv.DBOperation(operation), semconv.DBSQLTable(table)),
+	)
+}
+
+// StartKafkaConsumerTracerSpan starts a consumer span processing m, continuing the trace propagated in its headers
+func StartKafkaConsumerTracerSpan(ctx context.Context, m messaging.Message, operationName string) (context.Context, trace.Span) {
+	return Tracer().Start(ContextFromKafkaHeaders(ctx, m.Headers), m.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("kafka"),
+			semconv.MessagingOperationProcess,
+			semconv.MessagingDestinationName(m.Topic),
+			semconv.MessagingKafkaDestinationPartition(m.Partition),
+			semconv.MessagingKafkaMessageOffset(int(m.Offset)),
+			semconv.MessagingKafkaMessageKey(string(m.Key)),
+			semconv.CodeFunction(operationName),
+		),
+	)
+}
+
+// ContextFromKafkaHeaders returns ctx carrying the remote span context propagated in headers
+func ContextFromKafkaHeaders(ctx context.Context, headers []messaging.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, KafkaHeadersCarrier{Headers: &headers})
+}
+
+// GetKafkaTracingHeaders returns the traceparent, tracestate and baggage headers propagating the span in ctx
+func GetKafkaTracingHeaders(ctx context.Context) []messaging.Header {
+	headers := make([]messaging.Header, 0, 3)
+	otel.GetTextMapPropagator().Inject(ctx, KafkaHeadersCarrier{Headers: &headers})
 	return headers
 }
 
-// TextMapCarrierFromKafkaMessageHeaders ...
-func TextMapCarrierFromKafkaMessageHeaders(headers []kafka.Header) opentracing.TextMapCarrier {
-	textMap := make(map[string]string, len(headers))
-	for _, header := range headers {
-		textMap[header.Key] = string(header.Value)
-	}
-	return opentracing.TextMapCarrier(textMap)
+// TraceErr marks span as failed with err
+func TraceErr(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
 }
 
-// InjectTextMapCarrier ...
-func InjectTextMapCarrier(spanCtx opentracing.SpanContext) (opentracing.TextMapCarrier, error) {
-	m := make(opentracing.TextMapCarrier)
-	if err := opentracing.GlobalTracer().Inject(spanCtx, opentracing.TextMap, m); err != nil {
-		return nil, err
+// KafkaHeadersCarrier adapts message headers to a propagation.TextMapCarrier
+type KafkaHeadersCarrier struct {
+	Headers *[]messaging.Header
+}
+
+// Get returns the value of the first header named key
+func (c KafkaHeadersCarrier) Get(key string) string {
+	for _, h := range *c.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
 	}
-	return m, nil
+	return ""
 }
 
-// InjectTextMapCarrierToGrpcMetaData ...
-func InjectTextMapCarrierToGrpcMetaData(ctx context.Context, spanCtx opentracing.SpanContext) context.Context {
-	if textMapCarrier, err := InjectTextMapCarrier(spanCtx); err == nil {
-		md := metadata.New(textMapCarrier)
-		ctx = metadata.NewOutgoingContext(ctx, md)
+// Set replaces the headers named key with a single header holding value
+func (c KafkaHeadersCarrier) Set(key string, value string) {
+	headers := make([]messaging.Header, 0, len(*c.Headers)+1)
+	for _, h := range *c.Headers {
+		if h.Key != key {
+			headers = append(headers, h)
+		}
 	}
-	return ctx
+	*c.Headers = append(headers, messaging.Header{Key: key, Value: []byte(value)})
 }
 
-// GetKafkaTracingHeadersFromSpanCtx ...
-func GetKafkaTracingHeadersFromSpanCtx(spanCtx opentracing.SpanContext) []kafka.Header {
-	textMapCarrier, err := InjectTextMapCarrier(spanCtx)
-	if err != nil {
-		return []kafka.Header{}
+// Keys returns the header names
+func (c KafkaHeadersCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
 	}
-	kafkaMessageHeaders := TextMapCarrierToKafkaMessageHeaders(textMapCarrier)
-	return kafkaMessageHeaders
+	return keys
 }
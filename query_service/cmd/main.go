@@ -8,9 +8,15 @@ import (
 	"log"
 )
 
+var configPath string
+
+func init() {
+	flag.StringVar(&configPath, "config", "", "Query service config path")
+}
+
 func main() {
 	flag.Parse()
-	cfg, err := config.InitConfig()
+	cfg, err := config.InitConfig(configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
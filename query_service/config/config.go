@@ -1,8 +1,9 @@
 package config
 
 import (
-	"flag"
 	"fmt"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/certs"
 	"github.com/JECSand/identity-service/pkg/constants"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
@@ -14,55 +15,66 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"os"
+	"time"
 )
 
-var configPath string
-
-func init() {
-	flag.StringVar(&configPath, "config", "", "Query service config path")
-}
-
 type Config struct {
-	ServiceName      string              `mapstructure:"serviceName"`
-	Logger           *logging.Config     `mapstructure:"logger"`
-	KafkaTopics      KafkaTopics         `mapstructure:"kafkaTopics"`
-	GRPC             GRPC                `mapstructure:"grpc"`
-	Postgresql       *postgres.Config    `mapstructure:"postgres"`
-	Kafka            *kafkaClient.Config `mapstructure:"kafka"`
-	Mongo            *mongodb.Config     `mapstructure:"mongo"`
-	Redis            *redis.Config       `mapstructure:"redis"`
-	MongoCollections MongoCollections    `mapstructure:"mongoCollections"`
-	Probes           probes.Config       `mapstructure:"probes"`
-	ServiceSettings  ServiceSettings     `mapstructure:"serviceSettings"`
-	Jaeger           *tracing.Config     `mapstructure:"jaeger"`
+	ServiceName      string                           `mapstructure:"serviceName"`
+	Logger           *logging.Config                  `mapstructure:"logger"`
+	KafkaTopics      KafkaTopics                      `mapstructure:"kafkaTopics"`
+	GRPC             GRPC                             `mapstructure:"grpc"`
+	Postgresql       *postgres.Config                 `mapstructure:"postgres"`
+	Kafka            *kafkaClient.Config              `mapstructure:"kafka"`
+	Mongo            *mongodb.Config                  `mapstructure:"mongo"`
+	Redis            *redis.Config                    `mapstructure:"redis"`
+	MongoCollections MongoCollections                 `mapstructure:"mongoCollections"`
+	Probes           probes.Config                    `mapstructure:"probes"`
+	ServiceSettings  ServiceSettings                  `mapstructure:"serviceSettings"`
+	Cache            Cache                            `mapstructure:"cache"`
+	Authz            Authz                            `mapstructure:"authz"`
+	Tracing          *tracing.Config                  `mapstructure:"tracing"`
+	ServiceAuth      authentication.ServiceAuthConfig `mapstructure:"serviceAuth"`
 }
 
 type GRPC struct {
-	Port        string `mapstructure:"port"`
-	Development bool   `mapstructure:"development"`
+	Port        string       `mapstructure:"port"`
+	Development bool         `mapstructure:"development"`
+	TLS         certs.Config `mapstructure:"tls"`
 }
 
 type MongoCollections struct {
-	Users            string `mapstructure:"users"`
-	Groups           string `mapstructure:"groups"`
-	Memberships      string `mapstructure:"memberships"`
-	UserMemberships  string `mapstructure:"userMemberships"`
-	GroupMemberships string `mapstructure:"groupMemberships"`
-	Blacklist        string `mapstructure:"blacklist"`
+	Users                string `mapstructure:"users"`
+	Groups               string `mapstructure:"groups"`
+	Memberships          string `mapstructure:"memberships"`
+	UserMemberships      string `mapstructure:"userMemberships"`
+	GroupMemberships     string `mapstructure:"groupMemberships"`
+	Blacklist            string `mapstructure:"blacklist"`
+	Organizations        string `mapstructure:"organizations"`
+	Roles                string `mapstructure:"roles"`
+	EffectiveMemberships string `mapstructure:"effectiveMemberships"`
 }
 
 type KafkaTopics struct {
-	UserCreated       kafkaClient.TopicConfig `mapstructure:"userCreated"`
-	UserUpdated       kafkaClient.TopicConfig `mapstructure:"userUpdated"`
-	UserDeleted       kafkaClient.TopicConfig `mapstructure:"userDeleted"`
-	GroupCreated      kafkaClient.TopicConfig `mapstructure:"groupCreated"`
-	GroupUpdated      kafkaClient.TopicConfig `mapstructure:"groupUpdated"`
-	GroupDeleted      kafkaClient.TopicConfig `mapstructure:"groupDeleted"`
-	MembershipCreated kafkaClient.TopicConfig `mapstructure:"membershipCreated"`
-	MembershipUpdated kafkaClient.TopicConfig `mapstructure:"membershipUpdated"`
-	MembershipDeleted kafkaClient.TopicConfig `mapstructure:"membershipDeleted"`
-	PasswordUpdated   kafkaClient.TopicConfig `mapstructure:"passwordUpdated"`
-	TokenBlacklisted  kafkaClient.TopicConfig `mapstructure:"tokenBlacklisted"`
+	UserCreated               kafkaClient.TopicConfig `mapstructure:"userCreated"`
+	UserUpdated               kafkaClient.TopicConfig `mapstructure:"userUpdated"`
+	UserDeleted               kafkaClient.TopicConfig `mapstructure:"userDeleted"`
+	UserStatusChanged         kafkaClient.TopicConfig `mapstructure:"userStatusChanged"`
+	GroupCreated              kafkaClient.TopicConfig `mapstructure:"groupCreated"`
+	GroupUpdated              kafkaClient.TopicConfig `mapstructure:"groupUpdated"`
+	GroupDeleted              kafkaClient.TopicConfig `mapstructure:"groupDeleted"`
+	GroupParentChanged        kafkaClient.TopicConfig `mapstructure:"groupParentChanged"`
+	MembershipCreated         kafkaClient.TopicConfig `mapstructure:"membershipCreated"`
+	MembershipUpdated         kafkaClient.TopicConfig `mapstructure:"membershipUpdated"`
+	MembershipDeleted         kafkaClient.TopicConfig `mapstructure:"membershipDeleted"`
+	PasswordUpdated           kafkaClient.TopicConfig `mapstructure:"passwordUpdated"`
+	TokenBlacklisted          kafkaClient.TopicConfig `mapstructure:"tokenBlacklisted"`
+	OrganizationCreated       kafkaClient.TopicConfig `mapstructure:"organizationCreated"`
+	OrganizationUpdated       kafkaClient.TopicConfig `mapstructure:"organizationUpdated"`
+	OrganizationStatusChanged kafkaClient.TopicConfig `mapstructure:"organizationStatusChanged"`
+	OrganizationDeleted       kafkaClient.TopicConfig `mapstructure:"organizationDeleted"`
+	RoleCreated               kafkaClient.TopicConfig `mapstructure:"roleCreated"`
+	RoleUpdated               kafkaClient.TopicConfig `mapstructure:"roleUpdated"`
+	RoleDeleted               kafkaClient.TopicConfig `mapstructure:"roleDeleted"`
 }
 
 type ServiceSettings struct {
@@ -75,7 +87,42 @@ type ServiceSettings struct {
 	JWTSalt                       string `mapstructure:"jwtSalt"`
 }
 
-func InitConfig() (*Config, error) {
+// Cache sets how long each entity type lives in the redis cache
+type Cache struct {
+	UserTTL             time.Duration `mapstructure:"userTTL"`
+	GroupTTL            time.Duration `mapstructure:"groupTTL"`
+	MembershipTTL       time.Duration `mapstructure:"membershipTTL"`
+	UserMembershipTTL   time.Duration `mapstructure:"userMembershipTTL"`
+	GroupMembershipTTL  time.Duration `mapstructure:"groupMembershipTTL"`
+	TokenTTL            time.Duration `mapstructure:"tokenTTL"`
+	InvalidationChannel string        `mapstructure:"invalidationChannel"`
+	Local               LocalCache    `mapstructure:"local"`
+}
+
+// LocalCache configures the in-process tier kept in front of redis for each entity type
+type LocalCache struct {
+	User            LocalCacheTier `mapstructure:"user"`
+	Group           LocalCacheTier `mapstructure:"group"`
+	Membership      LocalCacheTier `mapstructure:"membership"`
+	UserMembership  LocalCacheTier `mapstructure:"userMembership"`
+	GroupMembership LocalCacheTier `mapstructure:"groupMembership"`
+	Token           LocalCacheTier `mapstructure:"token"`
+}
+
+// LocalCacheTier bounds an in-process cache by entry count and entry age
+type LocalCacheTier struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Size    int           `mapstructure:"size"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// Authz configures the authorization check API; LogDecisions logs every decision it makes
+type Authz struct {
+	LogDecisions bool `mapstructure:"logDecisions"`
+}
+
+// InitConfig loads the config file at configPath, falling back to the CONFIG_PATH env and the working directory default
+func InitConfig(configPath string) (*Config, error) {
 	if configPath == "" {
 		configPathFromEnv := os.Getenv(constants.ConfigPath)
 		if configPathFromEnv != "" {
@@ -89,12 +136,13 @@ func InitConfig() (*Config, error) {
 		}
 	}
 	cfg := &Config{}
-	viper.SetConfigType(constants.Yaml)
-	viper.SetConfigFile(configPath)
-	if err := viper.ReadInConfig(); err != nil {
+	v := viper.New()
+	v.SetConfigType(constants.Yaml)
+	v.SetConfigFile(configPath)
+	if err := v.ReadInConfig(); err != nil {
 		return nil, errors.Wrap(err, "viper.ReadInConfig")
 	}
-	if err := viper.Unmarshal(cfg); err != nil {
+	if err := v.Unmarshal(cfg); err != nil {
 		return nil, errors.Wrap(err, "viper.Unmarshal")
 	}
 	grpcPort := os.Getenv(constants.GrpcPort)
@@ -118,21 +166,17 @@ func InitConfig() (*Config, error) {
 	if redisAddr != "" {
 		cfg.Redis.Addr = redisAddr
 	}
-	//jaegerAddr := os.Getenv("JAEGER_HOST")
-	//if jaegerAddr != "" {
-	//	cfg.Jaeger.HostPort = jaegerAddr
-	//}
 	//kafkaBrokers := os.Getenv("KAFKA_BROKERS")
 	//if kafkaBrokers != "" {
 	//	cfg.Kafka.Brokers = []string{"host.docker.internal:9092"}
 	//}
 	kafkaBrokers := os.Getenv(constants.KafkaBrokers)
 	if kafkaBrokers != "" {
-		cfg.Kafka.Brokers = []string{kafkaBrokers}
+		cfg.Kafka.Brokers = kafkaClient.ParseBrokers(kafkaBrokers)
 	}
-	jaegerAddr := os.Getenv(constants.JaegerHostPort)
-	if jaegerAddr != "" {
-		cfg.Jaeger.HostPort = jaegerAddr
+	otlpEndpoint := os.Getenv(constants.OtlpEndpoint)
+	if otlpEndpoint != "" {
+		cfg.Tracing.Endpoint = otlpEndpoint
 	}
 	return cfg, nil
 }
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// memoryEntry is a versioned cache entry; a nil payload is a tombstone left by a delete
+type memoryEntry struct {
+	version   int64
+	payload   []byte
+	expiresAt time.Time
+}
+
+// memoryCache is an in-process Cache with the same versioning and tombstone semantics as the redis cache
+type memoryCache struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// NewMemoryCache ...
+func NewMemoryCache(log logging.Logger, cfg *config.Config) *memoryCache {
+	return &memoryCache{
+		log:     log,
+		cfg:     cfg,
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+func (m *memoryCache) PutUserMembership(_ context.Context, key string, userMembership *entities.UserMembership) {
+	m.put(UserMembershipEntity, key, userMembership.UpdatedAt, userMembership)
+}
+
+func (m *memoryCache) GetUserMembership(_ context.Context, key string) (*entities.UserMembership, error) {
+	var userMembership entities.UserMembership
+	if err := m.get(UserMembershipEntity, key, &userMembership); err != nil {
+		return nil, err
+	}
+	return &userMembership, nil
+}
+
+func (m *memoryCache) DeleteUserMembership(_ context.Context, key string) {
+	m.delete(UserMembershipEntity, key)
+}
+
+func (m *memoryCache) PutGroupMembership(_ context.Context, key string, groupMembership *entities.GroupMembership) {
+	m.put(GroupMembershipEntity, key, groupMembership.UpdatedAt, groupMembership)
+}
+
+func (m *memoryCache) GetGroupMembership(_ context.Context, key string) (*entities.GroupMembership, error) {
+	var groupMembership entities.GroupMembership
+	if err := m.get(GroupMembershipEntity, key, &groupMembership); err != nil {
+		return nil, err
+	}
+	return &groupMembership, nil
+}
+
+func (m *memoryCache) DeleteGroupMembership(_ context.Context, key string) {
+	m.delete(GroupMembershipEntity, key)
+}
+
+func (m *memoryCache) PutMembership(_ context.Context, key string, membership *entities.Membership) {
+	m.put(MembershipEntity, key, membership.UpdatedAt, membership)
+}
+
+func (m *memoryCache) GetMembership(_ context.Context, key string) (*entities.Membership, error) {
+	var membership entities.Membership
+	if err := m.get(MembershipEntity, key, &membership); err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+func (m *memoryCache) DeleteMembership(_ context.Context, key string) {
+	m.delete(MembershipEntity, key)
+}
+
+func (m *memoryCache) PutGroup(_ context.Context, key string, group *entities.Group) {
+	m.put(GroupEntity, key, group.UpdatedAt, group)
+}
+
+func (m *memoryCache) GetGroup(_ context.Context, key string) (*entities.Group, error) {
+	var group entities.Group
+	if err := m.get(GroupEntity, key, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (m *memoryCache) DeleteGroup(_ context.Context, key string) {
+	m.delete(GroupEntity, key)
+}
+
+func (m *memoryCache) PutUser(_ context.Context, key string, user *entities.User) {
+	m.put(UserEntity, key, user.UpdatedAt, user)
+}
+
+func (m *memoryCache) GetUser(_ context.Context, key string) (*entities.User, error) {
+	var user entities.User
+	if err := m.get(UserEntity, key, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (m *memoryCache) DeleteUser(_ context.Context, key string) {
+	m.delete(UserEntity, key)
+}
+
+func (m *memoryCache) PutToken(_ context.Context, key string, blacklist *entities.Blacklist) {
+	m.put(TokenEntity, key, blacklist.UpdatedAt, blacklist)
+}
+
+func (m *memoryCache) GetToken(_ context.Context, key string) (*entities.Blacklist, error) {
+	var blacklist entities.Blacklist
+	if err := m.get(TokenEntity, key, &blacklist); err != nil {
+		return nil, err
+	}
+	return &blacklist, nil
+}
+
+func (m *memoryCache) DeleteToken(_ context.Context, key string) {
+	m.delete(TokenEntity, key)
+}
+
+// Invalidate is a no-op, a single process has no other replicas holding local copies
+func (m *memoryCache) Invalidate(context.Context, string, string) {}
+
+func (m *memoryCache) put(entity string, key string, updatedAt time.Time, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		m.log.WarnMsg("json.Marshal", err)
+		return
+	}
+	m.setIfNewer(entity, key, entryVersion(updatedAt), b)
+}
+
+func (m *memoryCache) get(entity string, key string, dst interface{}) error {
+	m.mu.Lock()
+	entry, ok := m.entries[entity+":"+key]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(m.entries, entity+":"+key)
+		ok = false
+	}
+	m.mu.Unlock()
+	if !ok || entry.payload == nil {
+		return ErrCacheMiss
+	}
+	if err := json.Unmarshal(entry.payload, dst); err != nil {
+		return errors.Wrap(err, "json.Unmarshal")
+	}
+	return nil
+}
+
+func (m *memoryCache) delete(entity string, key string) {
+	m.setIfNewer(entity, key, time.Now().UnixNano(), nil)
+}
+
+// setIfNewer stores a payload unless a newer, unexpired version of the entry is already cached
+func (m *memoryCache) setIfNewer(entity string, key string, version int64, payload []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if cur, ok := m.entries[entity+":"+key]; ok && now.Before(cur.expiresAt) && cur.version > version {
+		return
+	}
+	m.entries[entity+":"+key] = &memoryEntry{version: version, payload: payload, expiresAt: now.Add(entityTTL(m.cfg, entity))}
+}
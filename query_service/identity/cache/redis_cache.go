@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/JECSand/identity-service/query_service/identity/metrics"
 	"github.com/go-redis/redis/v8"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"time"
 )
 
 const (
@@ -20,366 +22,352 @@ const (
 	redisTokenPrefixKey           = "query:token"
 )
 
+// Entity types used to key the cache, its metrics and invalidation messages
+const (
+	UserMembershipEntity  = "userMembership"
+	GroupMembershipEntity = "groupMembership"
+	MembershipEntity      = "membership"
+	GroupEntity           = "group"
+	UserEntity            = "user"
+	TokenEntity           = "token"
+)
+
+const (
+	defaultTTL                 = 10 * time.Minute
+	defaultTokenTTL            = 24 * time.Hour
+	defaultInvalidationChannel = "query:invalidate"
+	versionField               = "v"
+	dataField                  = "d"
+)
+
+// Invalidation is the pub/sub message announcing that a cached entry changed
+type Invalidation struct {
+	Entity string `json:"entity"`
+	Key    string `json:"key"`
+}
+
+// ErrCacheMiss is returned when a key is absent, expired or deleted
+var ErrCacheMiss = errors.New("cache miss")
+
+// setIfNewerScript writes a versioned entry only when no newer version is stored, then refreshes its TTL.
+// An empty payload is stored as a tombstone so late writes of an older version cannot resurrect a deleted entry.
+var setIfNewerScript = redis.NewScript(`
+local cur = redis.call('HGET', KEYS[1], 'v')
+if cur and tonumber(cur) > tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'v', ARGV[1], 'd', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
 type redisCache struct {
 	log         logging.Logger
 	cfg         *config.Config
 	redisClient redis.UniversalClient
+	metrics     *metrics.QueryServiceMetrics
 }
 
-func NewRedisCache(log logging.Logger, cfg *config.Config, redisClient redis.UniversalClient) *redisCache {
+func NewRedisCache(log logging.Logger, cfg *config.Config, redisClient redis.UniversalClient, metrics *metrics.QueryServiceMetrics) *redisCache {
 	return &redisCache{
 		log:         log,
 		cfg:         cfg,
 		redisClient: redisClient,
+		metrics:     metrics,
 	}
 }
 
-func (r *redisCache) PutUserMembership(ctx context.Context, key string, user *entities.UserMembership) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.PutUserMembership")
-	defer span.Finish()
-	b, err := json.Marshal(user)
-	if err != nil {
-		r.log.WarnMsg("json.Marshal", err)
-		return
-	}
-	if err = r.redisClient.HSetNX(ctx, r.getRedisPrefixKey("userMembership"), key, b).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HSetNX", err)
-		return
-	}
-	r.log.Debugf("HSetNX prefix: %s, key: %s", r.getRedisPrefixKey("userMembership"), key)
+func (r *redisCache) PutUserMembership(ctx context.Context, key string, userMembership *entities.UserMembership) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.PutUserMembership")
+	defer span.End()
+	r.put(ctx, UserMembershipEntity, key, userMembership.UpdatedAt, userMembership)
 }
 
 func (r *redisCache) GetUserMembership(ctx context.Context, key string) (*entities.UserMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.GetUserMembership")
-	defer span.Finish()
-	b, err := r.redisClient.HGet(ctx, r.getRedisPrefixKey("userMembership"), key).Bytes()
-	if err != nil {
-		if err != redis.Nil {
-			r.log.WarnMsg("redisClient.HGet", err)
-		}
-		return nil, errors.Wrap(err, "redisClient.HGet")
-	}
-	var user entities.UserMembership
-	if err = json.Unmarshal(b, &user); err != nil {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.GetUserMembership")
+	defer span.End()
+	var userMembership entities.UserMembership
+	if err := r.get(ctx, UserMembershipEntity, key, &userMembership); err != nil {
 		return nil, err
 	}
-	r.log.Debugf("HGet prefix: %s, key: %s", r.getRedisPrefixKey("userMembership"), key)
-	return &user, nil
+	return &userMembership, nil
 }
 
 func (r *redisCache) DeleteUserMembership(ctx context.Context, key string) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteUserMembership")
-	defer span.Finish()
-	if err := r.redisClient.HDel(ctx, r.getRedisPrefixKey("userMembership"), key).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("HDel prefix: %s, key: %s", r.getRedisPrefixKey("userMembership"), key)
+	ctx, span := tracing.StartSpan(ctx, "redisCache.DeleteUserMembership")
+	defer span.End()
+	r.delete(ctx, UserMembershipEntity, key)
 }
 
-func (r *redisCache) DeleteAllUserMemberships(ctx context.Context) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteAllUserMemberships")
-	defer span.Finish()
-	if err := r.redisClient.Del(ctx, r.getRedisPrefixKey("userMembership")).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("Del key: %s", r.getRedisPrefixKey("userMembership"))
-}
-
-func (r *redisCache) PutGroupMembership(ctx context.Context, key string, user *entities.GroupMembership) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.PutGroupMembership")
-	defer span.Finish()
-	b, err := json.Marshal(user)
-	if err != nil {
-		r.log.WarnMsg("json.Marshal", err)
-		return
-	}
-	if err = r.redisClient.HSetNX(ctx, r.getRedisPrefixKey("groupMembership"), key, b).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HSetNX", err)
-		return
-	}
-	r.log.Debugf("HSetNX prefix: %s, key: %s", r.getRedisPrefixKey("groupMembership"), key)
+func (r *redisCache) PutGroupMembership(ctx context.Context, key string, groupMembership *entities.GroupMembership) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.PutGroupMembership")
+	defer span.End()
+	r.put(ctx, GroupMembershipEntity, key, groupMembership.UpdatedAt, groupMembership)
 }
 
 func (r *redisCache) GetGroupMembership(ctx context.Context, key string) (*entities.GroupMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.GetGroupMembership")
-	defer span.Finish()
-	b, err := r.redisClient.HGet(ctx, r.getRedisPrefixKey("groupMembership"), key).Bytes()
-	if err != nil {
-		if err != redis.Nil {
-			r.log.WarnMsg("redisClient.HGet", err)
-		}
-		return nil, errors.Wrap(err, "redisClient.HGet")
-	}
-	var user entities.GroupMembership
-	if err = json.Unmarshal(b, &user); err != nil {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.GetGroupMembership")
+	defer span.End()
+	var groupMembership entities.GroupMembership
+	if err := r.get(ctx, GroupMembershipEntity, key, &groupMembership); err != nil {
 		return nil, err
 	}
-	r.log.Debugf("HGet prefix: %s, key: %s", r.getRedisPrefixKey("groupMembership"), key)
-	return &user, nil
+	return &groupMembership, nil
 }
 
 func (r *redisCache) DeleteGroupMembership(ctx context.Context, key string) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteGroupMembership")
-	defer span.Finish()
-	if err := r.redisClient.HDel(ctx, r.getRedisPrefixKey("groupMembership"), key).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("HDel prefix: %s, key: %s", r.getRedisPrefixKey("groupMembership"), key)
+	ctx, span := tracing.StartSpan(ctx, "redisCache.DeleteGroupMembership")
+	defer span.End()
+	r.delete(ctx, GroupMembershipEntity, key)
 }
 
-func (r *redisCache) DeleteAllGroupMemberships(ctx context.Context) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteAllMemberships")
-	defer span.Finish()
-	if err := r.redisClient.Del(ctx, r.getRedisPrefixKey("groupMembership")).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("Del key: %s", r.getRedisPrefixKey("groupMembership"))
-}
-
-func (r *redisCache) PutMembership(ctx context.Context, key string, user *entities.Membership) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.PutMembership")
-	defer span.Finish()
-	b, err := json.Marshal(user)
-	if err != nil {
-		r.log.WarnMsg("json.Marshal", err)
-		return
-	}
-	if err = r.redisClient.HSetNX(ctx, r.getRedisPrefixKey("membership"), key, b).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HSetNX", err)
-		return
-	}
-	r.log.Debugf("HSetNX prefix: %s, key: %s", r.getRedisPrefixKey("membership"), key)
+func (r *redisCache) PutMembership(ctx context.Context, key string, membership *entities.Membership) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.PutMembership")
+	defer span.End()
+	r.put(ctx, MembershipEntity, key, membership.UpdatedAt, membership)
 }
 
 func (r *redisCache) GetMembership(ctx context.Context, key string) (*entities.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.GetMembership")
-	defer span.Finish()
-	b, err := r.redisClient.HGet(ctx, r.getRedisPrefixKey("membership"), key).Bytes()
-	if err != nil {
-		if err != redis.Nil {
-			r.log.WarnMsg("redisClient.HGet", err)
-		}
-		return nil, errors.Wrap(err, "redisClient.HGet")
-	}
-	var user entities.Membership
-	if err = json.Unmarshal(b, &user); err != nil {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.GetMembership")
+	defer span.End()
+	var membership entities.Membership
+	if err := r.get(ctx, MembershipEntity, key, &membership); err != nil {
 		return nil, err
 	}
-	r.log.Debugf("HGet prefix: %s, key: %s", r.getRedisPrefixKey("membership"), key)
-	return &user, nil
+	return &membership, nil
 }
 
 func (r *redisCache) DeleteMembership(ctx context.Context, key string) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteMembership")
-	defer span.Finish()
-	if err := r.redisClient.HDel(ctx, r.getRedisPrefixKey("membership"), key).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("HDel prefix: %s, key: %s", r.getRedisPrefixKey("membership"), key)
+	ctx, span := tracing.StartSpan(ctx, "redisCache.DeleteMembership")
+	defer span.End()
+	r.delete(ctx, MembershipEntity, key)
 }
 
-func (r *redisCache) DeleteAllMemberships(ctx context.Context) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteAllMemberships")
-	defer span.Finish()
-	if err := r.redisClient.Del(ctx, r.getRedisPrefixKey("membership")).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("Del key: %s", r.getRedisPrefixKey("membership"))
+func (r *redisCache) PutGroup(ctx context.Context, key string, group *entities.Group) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.PutGroup")
+	defer span.End()
+	r.put(ctx, GroupEntity, key, group.UpdatedAt, group)
 }
 
-func (r *redisCache) PutGroup(ctx context.Context, key string, user *entities.Group) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.PutGroup")
-	defer span.Finish()
-	b, err := json.Marshal(user)
-	if err != nil {
-		r.log.WarnMsg("json.Marshal", err)
-		return
-	}
-	if err = r.redisClient.HSetNX(ctx, r.getRedisPrefixKey("group"), key, b).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HSetNX", err)
-		return
+func (r *redisCache) GetGroup(ctx context.Context, key string) (*entities.Group, error) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.GetGroup")
+	defer span.End()
+	var group entities.Group
+	if err := r.get(ctx, GroupEntity, key, &group); err != nil {
+		return nil, err
 	}
-	r.log.Debugf("HSetNX prefix: %s, key: %s", r.getRedisPrefixKey("group"), key)
+	return &group, nil
 }
 
-func (r *redisCache) GetGroup(ctx context.Context, key string) (*entities.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.GetGroup")
-	defer span.Finish()
-	b, err := r.redisClient.HGet(ctx, r.getRedisPrefixKey("group"), key).Bytes()
-	if err != nil {
-		if err != redis.Nil {
-			r.log.WarnMsg("redisClient.HGet", err)
-		}
-		return nil, errors.Wrap(err, "redisClient.HGet")
-	}
-	var user entities.Group
-	if err = json.Unmarshal(b, &user); err != nil {
+func (r *redisCache) DeleteGroup(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.DeleteGroup")
+	defer span.End()
+	r.delete(ctx, GroupEntity, key)
+}
+
+func (r *redisCache) PutUser(ctx context.Context, key string, user *entities.User) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.PutUser")
+	defer span.End()
+	r.put(ctx, UserEntity, key, user.UpdatedAt, user)
+}
+
+func (r *redisCache) GetUser(ctx context.Context, key string) (*entities.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.GetUser")
+	defer span.End()
+	var user entities.User
+	if err := r.get(ctx, UserEntity, key, &user); err != nil {
 		return nil, err
 	}
-	r.log.Debugf("HGet prefix: %s, key: %s", r.getRedisPrefixKey("group"), key)
 	return &user, nil
 }
 
-func (r *redisCache) DeleteGroup(ctx context.Context, key string) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteGroup")
-	defer span.Finish()
-	if err := r.redisClient.HDel(ctx, r.getRedisPrefixKey("group"), key).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("HDel prefix: %s, key: %s", r.getRedisPrefixKey("group"), key)
+func (r *redisCache) DeleteUser(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.DeleteUser")
+	defer span.End()
+	r.delete(ctx, UserEntity, key)
 }
 
-func (r *redisCache) DeleteAllGroups(ctx context.Context) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteAllGroups")
-	defer span.Finish()
-	if err := r.redisClient.Del(ctx, r.getRedisPrefixKey("group")).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
+func (r *redisCache) PutToken(ctx context.Context, key string, blacklist *entities.Blacklist) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.PutToken")
+	defer span.End()
+	r.put(ctx, TokenEntity, key, blacklist.UpdatedAt, blacklist)
+}
+
+func (r *redisCache) GetToken(ctx context.Context, key string) (*entities.Blacklist, error) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.GetToken")
+	defer span.End()
+	var blacklist entities.Blacklist
+	if err := r.get(ctx, TokenEntity, key, &blacklist); err != nil {
+		return nil, err
 	}
-	r.log.Debugf("Del key: %s", r.getRedisPrefixKey("group"))
+	return &blacklist, nil
 }
 
-func (r *redisCache) PutUser(ctx context.Context, key string, user *entities.User) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.PutUser")
-	defer span.Finish()
-	b, err := json.Marshal(user)
+func (r *redisCache) DeleteToken(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.DeleteToken")
+	defer span.End()
+	r.delete(ctx, TokenEntity, key)
+}
+
+// Invalidate publishes an invalidation message telling every replica to drop its local copy of the entry
+func (r *redisCache) Invalidate(ctx context.Context, entity string, key string) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.Invalidate")
+	defer span.End()
+	b, err := json.Marshal(&Invalidation{Entity: entity, Key: key})
 	if err != nil {
 		r.log.WarnMsg("json.Marshal", err)
 		return
 	}
-	if err = r.redisClient.HSetNX(ctx, r.getRedisPrefixKey("user"), key, b).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HSetNX", err)
+	if err = r.redisClient.Publish(ctx, getInvalidationChannel(r.cfg), b).Err(); err != nil {
+		r.log.WarnMsg("redisClient.Publish", err)
 		return
 	}
-	r.log.Debugf("HSetNX prefix: %s, key: %s", r.getRedisPrefixKey("user"), key)
+	r.log.Debugf("Publish invalidation entity: %s, key: %s", entity, key)
 }
 
-func (r *redisCache) GetUser(ctx context.Context, key string) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.GetUser")
-	defer span.Finish()
-	b, err := r.redisClient.HGet(ctx, r.getRedisPrefixKey("user"), key).Bytes()
+// put stores value under key unless a newer version of the entry is already cached
+func (r *redisCache) put(ctx context.Context, entity string, key string, updatedAt time.Time, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		r.log.WarnMsg("json.Marshal", err)
+		return
+	}
+	r.setIfNewer(ctx, entity, key, entryVersion(updatedAt), b)
+}
+
+// get loads the entry stored under key into dst, returning ErrCacheMiss when absent or deleted
+func (r *redisCache) get(ctx context.Context, entity string, key string, dst interface{}) error {
+	b, err := r.redisClient.HGet(ctx, r.getRedisKey(entity, key), dataField).Bytes()
 	if err != nil {
+		r.metrics.CacheMisses.WithLabelValues(entity).Inc()
 		if err != redis.Nil {
 			r.log.WarnMsg("redisClient.HGet", err)
+			return errors.Wrap(err, "redisClient.HGet")
 		}
-		return nil, errors.Wrap(err, "redisClient.HGet")
+		return ErrCacheMiss
 	}
-	var user entities.User
-	if err = json.Unmarshal(b, &user); err != nil {
-		return nil, err
+	if len(b) == 0 {
+		r.metrics.CacheMisses.WithLabelValues(entity).Inc()
+		return ErrCacheMiss
 	}
-	r.log.Debugf("HGet prefix: %s, key: %s", r.getRedisPrefixKey("user"), key)
-	return &user, nil
+	if err = json.Unmarshal(b, dst); err != nil {
+		r.metrics.CacheMisses.WithLabelValues(entity).Inc()
+		return errors.Wrap(err, "json.Unmarshal")
+	}
+	r.metrics.CacheHits.WithLabelValues(entity).Inc()
+	r.log.Debugf("HGet key: %s", r.getRedisKey(entity, key))
+	return nil
 }
 
-func (r *redisCache) DeleteUser(ctx context.Context, key string) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteUser")
-	defer span.Finish()
-	if err := r.redisClient.HDel(ctx, r.getRedisPrefixKey("user"), key).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
+// PurgeUsers deletes every cached user entry, tombstones included, returning the number of keys deleted
+func (r *redisCache) PurgeUsers(ctx context.Context) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "redisCache.PurgeUsers")
+	defer span.End()
+	var purged int64
+	iter := r.redisClient.Scan(ctx, 0, r.getRedisPrefixKey(UserEntity)+":*", 100).Iterator()
+	for iter.Next(ctx) {
+		deleted, err := r.redisClient.Del(ctx, iter.Val()).Result()
+		if err != nil {
+			return purged, errors.Wrap(err, "redisClient.Del")
+		}
+		purged += deleted
 	}
-	r.log.Debugf("HDel prefix: %s, key: %s", r.getRedisPrefixKey("user"), key)
+	if err := iter.Err(); err != nil {
+		return purged, errors.Wrap(err, "redisClient.Scan")
+	}
+	return purged, nil
 }
 
-func (r *redisCache) DeleteAllUsers(ctx context.Context) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteAllUsers")
-	defer span.Finish()
-	if err := r.redisClient.Del(ctx, r.getRedisPrefixKey("user")).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
+// delete replaces the entry stored under key with a tombstone versioned at the current time
+func (r *redisCache) delete(ctx context.Context, entity string, key string) {
+	if r.setIfNewer(ctx, entity, key, time.Now().UnixNano(), nil) {
+		r.metrics.CacheEvictions.WithLabelValues(entity).Inc()
 	}
-	r.log.Debugf("Del key: %s", r.getRedisPrefixKey("user"))
 }
 
-func (r *redisCache) PutToken(ctx context.Context, key string, blacklist *entities.Blacklist) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.PutToken")
-	defer span.Finish()
-	b, err := json.Marshal(blacklist)
+func (r *redisCache) setIfNewer(ctx context.Context, entity string, key string, version int64, payload []byte) bool {
+	redisKey := r.getRedisKey(entity, key)
+	ttl := entityTTL(r.cfg, entity)
+	written, err := setIfNewerScript.Run(ctx, r.redisClient, []string{redisKey}, version, payload, ttl.Milliseconds()).Int()
 	if err != nil {
-		r.log.WarnMsg("json.Marshal", err)
-		return
+		r.log.WarnMsg("setIfNewerScript.Run", err)
+		return false
 	}
-	if err = r.redisClient.HSetNX(ctx, r.getRedisPrefixKey("token"), key, b).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HSetNX", err)
-		return
+	if written == 0 {
+		r.metrics.CacheStaleWrites.WithLabelValues(entity).Inc()
+		r.log.Debugf("stale write ignored key: %s, version: %d", redisKey, version)
+		return false
 	}
-	r.log.Debugf("HSetNX prefix: %s, key: %s", r.getRedisPrefixKey("token"), key)
+	r.log.Debugf("HSet key: %s, version: %d, ttl: %s", redisKey, version, ttl)
+	return true
 }
 
-func (r *redisCache) GetToken(ctx context.Context, key string) (*entities.Blacklist, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.GetToken")
-	defer span.Finish()
-	b, err := r.redisClient.HGet(ctx, r.getRedisPrefixKey("token"), key).Bytes()
-	if err != nil {
-		if err != redis.Nil {
-			r.log.WarnMsg("redisClient.HGet", err)
-		}
-		return nil, errors.Wrap(err, "redisClient.HGet")
-	}
-	var bl entities.Blacklist
-	if err = json.Unmarshal(b, &bl); err != nil {
-		return nil, err
+// entryVersion orders cache writes by the entity's last update, treating unversioned writes as current
+func entryVersion(updatedAt time.Time) int64 {
+	if updatedAt.IsZero() {
+		return time.Now().UnixNano()
 	}
-	r.log.Debugf("HGet prefix: %s, key: %s", r.getRedisPrefixKey("token"), key)
-	return &bl, nil
+	return updatedAt.UnixNano()
 }
 
-func (r *redisCache) DeleteToken(ctx context.Context, key string) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteToken")
-	defer span.Finish()
-	if err := r.redisClient.HDel(ctx, r.getRedisPrefixKey("token"), key).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
-	}
-	r.log.Debugf("HDel prefix: %s, key: %s", r.getRedisPrefixKey("token"), key)
+func (r *redisCache) getRedisKey(entity string, key string) string {
+	return r.getRedisPrefixKey(entity) + ":" + key
 }
 
-func (r *redisCache) DeleteAllTokens(ctx context.Context) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "redisCache.DeleteAllTokens")
-	defer span.Finish()
-	if err := r.redisClient.Del(ctx, r.getRedisPrefixKey("token")).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
-		return
+// entityTTL returns the configured lifetime of an entity's entries, falling back to the defaults
+func entityTTL(cfg *config.Config, entity string) time.Duration {
+	var ttl time.Duration
+	switch entity {
+	case UserMembershipEntity:
+		ttl = cfg.Cache.UserMembershipTTL
+	case GroupMembershipEntity:
+		ttl = cfg.Cache.GroupMembershipTTL
+	case MembershipEntity:
+		ttl = cfg.Cache.MembershipTTL
+	case GroupEntity:
+		ttl = cfg.Cache.GroupTTL
+	case UserEntity:
+		ttl = cfg.Cache.UserTTL
+	case TokenEntity:
+		ttl = cfg.Cache.TokenTTL
+		if ttl <= 0 {
+			return defaultTokenTTL
+		}
 	}
-	r.log.Debugf("Del key: %s", r.getRedisPrefixKey("token"))
+	if ttl <= 0 {
+		return defaultTTL
+	}
+	return ttl
 }
 
 func (r *redisCache) getRedisPrefixKey(prefixType string) string {
 	switch prefixType {
-	case "userMembership":
+	case UserMembershipEntity:
 		if r.cfg.ServiceSettings.RedisUserMembershipPrefixKey != "" {
 			return r.cfg.ServiceSettings.RedisUserMembershipPrefixKey
 		}
 		return redisUserMembershipPrefixKey
-	case "groupMembership":
+	case GroupMembershipEntity:
 		if r.cfg.ServiceSettings.RedisGroupMembershipPrefixKey != "" {
 			return r.cfg.ServiceSettings.RedisGroupMembershipPrefixKey
 		}
 		return redisGroupMembershipPrefixKey
-	case "membership":
+	case MembershipEntity:
 		if r.cfg.ServiceSettings.RedisMembershipPrefixKey != "" {
 			return r.cfg.ServiceSettings.RedisMembershipPrefixKey
 		}
 		return redisMembershipPrefixKey
-	case "group":
+	case GroupEntity:
 		if r.cfg.ServiceSettings.RedisGroupPrefixKey != "" {
 			return r.cfg.ServiceSettings.RedisGroupPrefixKey
 		}
 		return redisGroupPrefixKey
-	case "user":
+	case UserEntity:
 		if r.cfg.ServiceSettings.RedisUserPrefixKey != "" {
 			return r.cfg.ServiceSettings.RedisUserPrefixKey
 		}
 		return redisUserPrefixKey
-	case "token":
+	case TokenEntity:
 		if r.cfg.ServiceSettings.RedisTokenPrefixKey != "" {
 			return r.cfg.ServiceSettings.RedisTokenPrefixKey
 		}
@@ -387,3 +375,10 @@ func (r *redisCache) getRedisPrefixKey(prefixType string) string {
 	}
 	return "query"
 }
+
+func getInvalidationChannel(cfg *config.Config) string {
+	if cfg.Cache.InvalidationChannel != "" {
+		return cfg.Cache.InvalidationChannel
+	}
+	return defaultInvalidationChannel
+}
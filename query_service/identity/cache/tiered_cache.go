@@ -0,0 +1,292 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/JECSand/identity-service/query_service/identity/metrics"
+	"github.com/go-redis/redis/v8"
+)
+
+// tieredCache keeps an in-process LRU tier per enabled entity type in front of another Cache.
+// Local entries are dropped when an invalidation message for them arrives over redis pub/sub.
+type tieredCache struct {
+	log         logging.Logger
+	cfg         *config.Config
+	next        Cache
+	redisClient redis.UniversalClient
+	local       map[string]*lruCache
+	metrics     *metrics.QueryServiceMetrics
+}
+
+func NewTieredCache(log logging.Logger, cfg *config.Config, next Cache, redisClient redis.UniversalClient, metrics *metrics.QueryServiceMetrics) *tieredCache {
+	local := make(map[string]*lruCache)
+	tiers := map[string]config.LocalCacheTier{
+		UserMembershipEntity:  cfg.Cache.Local.UserMembership,
+		GroupMembershipEntity: cfg.Cache.Local.GroupMembership,
+		MembershipEntity:      cfg.Cache.Local.Membership,
+		GroupEntity:           cfg.Cache.Local.Group,
+		UserEntity:            cfg.Cache.Local.User,
+		TokenEntity:           cfg.Cache.Local.Token,
+	}
+	for entity, tier := range tiers {
+		if tier.Enabled && tier.Size > 0 && tier.TTL > 0 {
+			local[entity] = newLRUCache(tier.Size, tier.TTL)
+		}
+	}
+	return &tieredCache{
+		log:         log,
+		cfg:         cfg,
+		next:        next,
+		redisClient: redisClient,
+		local:       local,
+		metrics:     metrics,
+	}
+}
+
+// Subscribe drops local entries named by invalidation messages until ctx is done
+func (t *tieredCache) Subscribe(ctx context.Context) {
+	if len(t.local) == 0 {
+		return
+	}
+	pubSub := t.redisClient.Subscribe(ctx, getInvalidationChannel(t.cfg))
+	defer pubSub.Close() // nolint: errCheck
+	t.consume(ctx, pubSub.Channel())
+}
+
+// consume drops the local entries named by the invalidation messages of ch until ctx is done or ch is closed
+func (t *tieredCache) consume(ctx context.Context, ch <-chan *redis.Message) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var inv Invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				t.log.WarnMsg("json.Unmarshal", err)
+				continue
+			}
+			t.dropLocal(inv.Entity, inv.Key)
+		}
+	}
+}
+
+func (t *tieredCache) PutUserMembership(ctx context.Context, key string, userMembership *entities.UserMembership) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.PutUserMembership")
+	defer span.End()
+	t.next.PutUserMembership(ctx, key, userMembership)
+	t.dropLocal(UserMembershipEntity, key)
+}
+
+func (t *tieredCache) GetUserMembership(ctx context.Context, key string) (*entities.UserMembership, error) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.GetUserMembership")
+	defer span.End()
+	if cached, ok := t.getLocal(UserMembershipEntity, key); ok {
+		userMembership := *cached.(*entities.UserMembership)
+		return &userMembership, nil
+	}
+	userMembership, err := t.next.GetUserMembership(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	local := *userMembership
+	t.addLocal(UserMembershipEntity, key, &local)
+	return userMembership, nil
+}
+
+func (t *tieredCache) DeleteUserMembership(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.DeleteUserMembership")
+	defer span.End()
+	t.next.DeleteUserMembership(ctx, key)
+	t.dropLocal(UserMembershipEntity, key)
+}
+
+func (t *tieredCache) PutGroupMembership(ctx context.Context, key string, groupMembership *entities.GroupMembership) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.PutGroupMembership")
+	defer span.End()
+	t.next.PutGroupMembership(ctx, key, groupMembership)
+	t.dropLocal(GroupMembershipEntity, key)
+}
+
+func (t *tieredCache) GetGroupMembership(ctx context.Context, key string) (*entities.GroupMembership, error) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.GetGroupMembership")
+	defer span.End()
+	if cached, ok := t.getLocal(GroupMembershipEntity, key); ok {
+		groupMembership := *cached.(*entities.GroupMembership)
+		return &groupMembership, nil
+	}
+	groupMembership, err := t.next.GetGroupMembership(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	local := *groupMembership
+	t.addLocal(GroupMembershipEntity, key, &local)
+	return groupMembership, nil
+}
+
+func (t *tieredCache) DeleteGroupMembership(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.DeleteGroupMembership")
+	defer span.End()
+	t.next.DeleteGroupMembership(ctx, key)
+	t.dropLocal(GroupMembershipEntity, key)
+}
+
+func (t *tieredCache) PutMembership(ctx context.Context, key string, membership *entities.Membership) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.PutMembership")
+	defer span.End()
+	t.next.PutMembership(ctx, key, membership)
+	t.dropLocal(MembershipEntity, key)
+}
+
+func (t *tieredCache) GetMembership(ctx context.Context, key string) (*entities.Membership, error) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.GetMembership")
+	defer span.End()
+	if cached, ok := t.getLocal(MembershipEntity, key); ok {
+		membership := *cached.(*entities.Membership)
+		return &membership, nil
+	}
+	membership, err := t.next.GetMembership(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	local := *membership
+	t.addLocal(MembershipEntity, key, &local)
+	return membership, nil
+}
+
+func (t *tieredCache) DeleteMembership(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.DeleteMembership")
+	defer span.End()
+	t.next.DeleteMembership(ctx, key)
+	t.dropLocal(MembershipEntity, key)
+}
+
+func (t *tieredCache) PutGroup(ctx context.Context, key string, group *entities.Group) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.PutGroup")
+	defer span.End()
+	t.next.PutGroup(ctx, key, group)
+	t.dropLocal(GroupEntity, key)
+}
+
+func (t *tieredCache) GetGroup(ctx context.Context, key string) (*entities.Group, error) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.GetGroup")
+	defer span.End()
+	if cached, ok := t.getLocal(GroupEntity, key); ok {
+		group := *cached.(*entities.Group)
+		return &group, nil
+	}
+	group, err := t.next.GetGroup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	local := *group
+	t.addLocal(GroupEntity, key, &local)
+	return group, nil
+}
+
+func (t *tieredCache) DeleteGroup(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.DeleteGroup")
+	defer span.End()
+	t.next.DeleteGroup(ctx, key)
+	t.dropLocal(GroupEntity, key)
+}
+
+func (t *tieredCache) PutUser(ctx context.Context, key string, user *entities.User) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.PutUser")
+	defer span.End()
+	t.next.PutUser(ctx, key, user)
+	t.dropLocal(UserEntity, key)
+}
+
+func (t *tieredCache) GetUser(ctx context.Context, key string) (*entities.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.GetUser")
+	defer span.End()
+	if cached, ok := t.getLocal(UserEntity, key); ok {
+		user := *cached.(*entities.User)
+		return &user, nil
+	}
+	user, err := t.next.GetUser(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	local := *user
+	t.addLocal(UserEntity, key, &local)
+	return user, nil
+}
+
+func (t *tieredCache) DeleteUser(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.DeleteUser")
+	defer span.End()
+	t.next.DeleteUser(ctx, key)
+	t.dropLocal(UserEntity, key)
+}
+
+func (t *tieredCache) PutToken(ctx context.Context, key string, blacklist *entities.Blacklist) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.PutToken")
+	defer span.End()
+	t.next.PutToken(ctx, key, blacklist)
+	t.dropLocal(TokenEntity, key)
+}
+
+func (t *tieredCache) GetToken(ctx context.Context, key string) (*entities.Blacklist, error) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.GetToken")
+	defer span.End()
+	if cached, ok := t.getLocal(TokenEntity, key); ok {
+		blacklist := *cached.(*entities.Blacklist)
+		return &blacklist, nil
+	}
+	blacklist, err := t.next.GetToken(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	local := *blacklist
+	t.addLocal(TokenEntity, key, &local)
+	return blacklist, nil
+}
+
+func (t *tieredCache) DeleteToken(ctx context.Context, key string) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.DeleteToken")
+	defer span.End()
+	t.next.DeleteToken(ctx, key)
+	t.dropLocal(TokenEntity, key)
+}
+
+// Invalidate drops the local entry and announces the change to the other replicas
+func (t *tieredCache) Invalidate(ctx context.Context, entity string, key string) {
+	ctx, span := tracing.StartSpan(ctx, "tieredCache.Invalidate")
+	defer span.End()
+	t.dropLocal(entity, key)
+	t.next.Invalidate(ctx, entity, key)
+}
+
+func (t *tieredCache) getLocal(entity string, key string) (interface{}, bool) {
+	lru, ok := t.local[entity]
+	if !ok {
+		return nil, false
+	}
+	value, ok := lru.Get(key)
+	if !ok {
+		t.metrics.LocalCacheMisses.WithLabelValues(entity).Inc()
+		return nil, false
+	}
+	t.metrics.LocalCacheHits.WithLabelValues(entity).Inc()
+	return value, true
+}
+
+func (t *tieredCache) addLocal(entity string, key string, value interface{}) {
+	if lru, ok := t.local[entity]; ok && lru.Add(key, value) {
+		t.metrics.LocalCacheEvictions.WithLabelValues(entity).Inc()
+	}
+}
+
+func (t *tieredCache) dropLocal(entity string, key string) {
+	if lru, ok := t.local[entity]; ok && lru.Remove(key) {
+		t.metrics.LocalCacheInvalidated.WithLabelValues(entity).Inc()
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/JECSand/identity-service/query_service/identity/metrics"
+	"github.com/go-redis/redis/v8"
+	"testing"
+	"time"
+)
+
+// pubSubCache is a shared Cache whose invalidations are delivered to every subscriber, as redis pub/sub does
+type pubSubCache struct {
+	Cache
+	subscribers []chan *redis.Message
+}
+
+func (p *pubSubCache) subscribe() chan *redis.Message {
+	ch := make(chan *redis.Message, 16)
+	p.subscribers = append(p.subscribers, ch)
+	return ch
+}
+
+func (p *pubSubCache) Invalidate(_ context.Context, entity string, key string) {
+	b, _ := json.Marshal(&Invalidation{Entity: entity, Key: key})
+	for _, ch := range p.subscribers {
+		ch <- &redis.Message{Payload: string(b)}
+	}
+}
+
+// eventually polls cond until it holds or a second passes
+func eventually(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTieredCacheInvalidationEvictsOtherInstances(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := &config.Config{ServiceName: "tiered_cache_test"}
+	cfg.Cache.Local.User = config.LocalCacheTier{Enabled: true, Size: 10, TTL: time.Minute}
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	m := metrics.NewQueryServiceMetrics(cfg)
+	shared := &pubSubCache{Cache: NewMemoryCache(log, cfg)}
+	a := NewTieredCache(log, cfg, shared, nil, m)
+	b := NewTieredCache(log, cfg, shared, nil, m)
+	go a.consume(ctx, shared.subscribe())
+	busB := shared.subscribe()
+	go b.consume(ctx, busB)
+
+	now := time.Now()
+	shared.PutUser(ctx, "changed", &entities.User{ID: "changed", Username: "v1", UpdatedAt: now})
+	shared.PutUser(ctx, "untouched", &entities.User{ID: "untouched", Username: "v1", UpdatedAt: now})
+	for _, key := range []string{"changed", "untouched"} {
+		if user, err := b.GetUser(ctx, key); err != nil || user.Username != "v1" {
+			t.Fatalf("GetUser(%s): got %v, %v", key, user, err)
+		}
+	}
+
+	a.PutUser(ctx, "changed", &entities.User{ID: "changed", Username: "v2", UpdatedAt: now.Add(time.Second)})
+	shared.PutUser(ctx, "untouched", &entities.User{ID: "untouched", Username: "v2", UpdatedAt: now.Add(time.Second)})
+	if user, _ := b.GetUser(ctx, "changed"); user.Username != "v1" {
+		t.Fatalf("GetUser before the invalidation: got %q, want the local v1", user.Username)
+	}
+	busB <- &redis.Message{Payload: "not json"}
+	a.Invalidate(ctx, UserEntity, "changed")
+	if !eventually(t, func() bool {
+		user, err := b.GetUser(ctx, "changed")
+		return err == nil && user.Username == "v2"
+	}) {
+		t.Error("the invalidation did not evict the local entry of the other instance")
+	}
+	if user, _ := b.GetUser(ctx, "untouched"); user.Username != "v1" {
+		t.Errorf("GetUser of an entry that was not invalidated: got %q, want the local v1", user.Username)
+	}
+	if user, _ := a.GetUser(ctx, "changed"); user.Username != "v2" {
+		t.Errorf("GetUser on the invalidating instance: got %q, want v2", user.Username)
+	}
+}
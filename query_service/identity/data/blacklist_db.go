@@ -3,15 +3,16 @@ package data
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
@@ -76,8 +77,8 @@ func NewBlacklistRepository(log logging.Logger, cfg *config.Config, db *mongo.Cl
 }
 
 func (p *blacklistRepository) BlacklistToken(ctx context.Context, bList *entities.Blacklist) (*entities.Blacklist, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistRepository.BlacklistToken")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "blacklistRepository.BlacklistToken")
+	defer span.End()
 	p.log.Info(bList.ID)
 	ent, err := newBlacklistEntity(bList)
 	if err != nil {
@@ -86,6 +87,10 @@ func (p *blacklistRepository) BlacklistToken(ctx context.Context, bList *entitie
 	}
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Blacklist)
 	_, err = collection.InsertOne(ctx, ent, &options.InsertOneOptions{})
+	if mongo.IsDuplicateKeyError(err) {
+		// the token was already blacklisted through another path, e.g. the synchronous revoke RPC
+		return bList, nil
+	}
 	if err != nil {
 		p.traceErr(span, err)
 		return &entities.Blacklist{}, errors.Wrap(err, "InsertOne")
@@ -94,8 +99,8 @@ func (p *blacklistRepository) BlacklistToken(ctx context.Context, bList *entitie
 }
 
 func (p *blacklistRepository) CheckBlacklist(ctx context.Context, accessToken string) (*entities.Blacklist, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistRepository.CheckBlacklist")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "blacklistRepository.CheckBlacklist")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Blacklist)
 	var ent blacklistEntity
 	if err := collection.FindOne(ctx, bson.M{"access_token": accessToken}).Decode(&ent); err != nil {
@@ -105,7 +110,6 @@ func (p *blacklistRepository) CheckBlacklist(ctx context.Context, accessToken st
 	return ent.toRoot(), nil
 }
 
-func (p *blacklistRepository) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+func (p *blacklistRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 }
@@ -19,6 +19,9 @@ type database struct {
 	groupMemberships *groupMembershipRepository
 	userMemberships  *userMembershipRepository
 	blacklist        *blacklistRepository
+	organizations    *organizationRepository
+	effective        *effectiveMembershipRepository
+	roles            *roleRepository
 }
 
 // NewDatabase Initializes a new Database setup to MongoDB
@@ -29,6 +32,9 @@ func NewDatabase(log logging.Logger, cfg *config.Config, db *mongo.Client) *data
 	groupMembershipRepo := NewGroupMembershipRepository(log, cfg, db)
 	userMembershipRepo := NewUserMembershipRepository(log, cfg, db)
 	blRepo := NewBlacklistRepository(log, cfg, db)
+	organizationRepo := NewOrganizationRepository(log, cfg, db)
+	effectiveRepo := NewEffectiveMembershipRepository(log, cfg, db)
+	roleRepo := NewRoleRepository(log, cfg, db)
 	return &database{
 		userRepo,
 		groupRepo,
@@ -36,6 +42,9 @@ func NewDatabase(log logging.Logger, cfg *config.Config, db *mongo.Client) *data
 		groupMembershipRepo,
 		userMembershipRepo,
 		blRepo,
+		organizationRepo,
+		effectiveRepo,
+		roleRepo,
 	}
 }
 
@@ -47,6 +56,10 @@ func (d *database) UpdateUser(ctx context.Context, user *entities.User) (*entiti
 	return d.users.Update(ctx, user)
 }
 
+func (d *database) UpdateUserStatus(ctx context.Context, user *entities.User) (*entities.User, error) {
+	return d.users.UpdateStatus(ctx, user)
+}
+
 func (d *database) GetUserById(ctx context.Context, id uuid.UUID) (*entities.User, error) {
 	return d.users.GetById(ctx, id)
 }
@@ -55,16 +68,22 @@ func (d *database) GetUserByEmail(ctx context.Context, email string) (*entities.
 	return d.users.GetByEmail(ctx, email)
 }
 
-func (d *database) AuthenticateUser(ctx context.Context, email string, password string) (*entities.User, error) {
-	return d.users.Authenticate(ctx, email, password)
+// StripPasswordHashes migrates the users stored while password hashes were replicated into the read model
+func (d *database) StripPasswordHashes(ctx context.Context) (int64, error) {
+	return d.users.StripPasswordHashes(ctx)
 }
 
 func (d *database) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	return d.users.Delete(ctx, id)
 }
 
-func (d *database) SearchUsers(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.UsersList, error) {
-	return d.users.Search(ctx, search, pagination)
+func (d *database) SearchUsers(ctx context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.UsersList, error) {
+	return d.users.Search(ctx, search, tenantID, pagination)
+}
+
+// DeleteOrganizationUsers deletes the users of an organization, returning the deleted users
+func (d *database) DeleteOrganizationUsers(ctx context.Context, organizationID string) ([]*entities.User, error) {
+	return d.users.DeleteByOrganization(ctx, organizationID)
 }
 
 func (d *database) CreateGroup(ctx context.Context, model *entities.Group) (*entities.Group, error) {
@@ -79,12 +98,29 @@ func (d *database) GetGroupById(ctx context.Context, id uuid.UUID) (*entities.Gr
 	return d.groups.GetById(ctx, id)
 }
 
+func (d *database) SetGroupParent(ctx context.Context, id uuid.UUID, parentID string) (*entities.Group, error) {
+	return d.groups.SetParent(ctx, id, parentID)
+}
+
+func (d *database) GetChildGroups(ctx context.Context, parentID uuid.UUID) ([]*entities.Group, error) {
+	return d.groups.GetChildren(ctx, parentID)
+}
+
+func (d *database) DetachChildGroups(ctx context.Context, parentID uuid.UUID) ([]*entities.Group, error) {
+	return d.groups.DetachChildren(ctx, parentID)
+}
+
 func (d *database) DeleteGroup(ctx context.Context, id uuid.UUID) error {
 	return d.groups.Delete(ctx, id)
 }
 
-func (d *database) SearchGroups(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.GroupsList, error) {
-	return d.groups.Search(ctx, search, pagination)
+func (d *database) SearchGroups(ctx context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.GroupsList, error) {
+	return d.groups.Search(ctx, search, tenantID, pagination)
+}
+
+// DeleteOrganizationGroups deletes the groups of an organization and the groups its users created, returning them
+func (d *database) DeleteOrganizationGroups(ctx context.Context, organizationID string, creatorIDs []string) ([]*entities.Group, error) {
+	return d.groups.DeleteByOrganization(ctx, organizationID, creatorIDs)
 }
 
 func (d *database) CreateMembership(ctx context.Context, model *entities.Membership) (*entities.Membership, error) {
@@ -103,6 +139,14 @@ func (d *database) GetMembershipById(ctx context.Context, id uuid.UUID) (*entiti
 	return d.memberships.GetById(ctx, id)
 }
 
+func (d *database) GetMembershipsByUserId(ctx context.Context, userId uuid.UUID) ([]*entities.Membership, error) {
+	return d.memberships.ListByUserId(ctx, userId)
+}
+
+func (d *database) GetMembershipsByRoleId(ctx context.Context, roleId uuid.UUID) ([]*entities.Membership, error) {
+	return d.memberships.ListByRoleId(ctx, roleId)
+}
+
 func (d *database) DeleteMembership(ctx context.Context, id uuid.UUID) error {
 	return d.memberships.Delete(ctx, id)
 }
@@ -127,12 +171,12 @@ func (d *database) GetGroupMembershipById(ctx context.Context, id uuid.UUID, idT
 	return d.groupMemberships.GetById(ctx, id, idType)
 }
 
-func (d *database) GetGroupMembershipByUserId(ctx context.Context, userId uuid.UUID, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
-	return d.groupMemberships.GetByUserId(ctx, userId, pagination)
+func (d *database) GetGroupMembershipByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
+	return d.groupMemberships.GetByUserId(ctx, userId, tenantID, pagination)
 }
 
-func (d *database) GetGroupMembershipByGroupId(ctx context.Context, groupId uuid.UUID, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
-	return d.groupMemberships.GetByGroupId(ctx, groupId, pagination)
+func (d *database) GetGroupMembershipByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
+	return d.groupMemberships.GetByGroupId(ctx, groupId, tenantID, pagination)
 }
 
 func (d *database) DeleteGroupMembership(ctx context.Context, id uuid.UUID) error {
@@ -163,12 +207,12 @@ func (d *database) GetUserMembershipById(ctx context.Context, id uuid.UUID, idTy
 	return d.userMemberships.GetById(ctx, id, idType)
 }
 
-func (d *database) GetUserMembershipByUserId(ctx context.Context, userId uuid.UUID, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
-	return d.userMemberships.GetByUserId(ctx, userId, pagination)
+func (d *database) GetUserMembershipByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
+	return d.userMemberships.GetByUserId(ctx, userId, tenantID, pagination)
 }
 
-func (d *database) GetUserMembershipByGroupId(ctx context.Context, groupId uuid.UUID, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
-	return d.userMemberships.GetByGroupId(ctx, groupId, pagination)
+func (d *database) GetUserMembershipByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
+	return d.userMemberships.GetByGroupId(ctx, groupId, tenantID, pagination)
 }
 
 func (d *database) DeleteUserMembership(ctx context.Context, id uuid.UUID) error {
@@ -183,6 +227,75 @@ func (d *database) DeleteUserMembershipByMembershipId(ctx context.Context, id uu
 	return d.userMemberships.DeleteByMembershipId(ctx, id)
 }
 
+func (d *database) ReplaceEffectiveMemberships(ctx context.Context, userId uuid.UUID, memberships []*entities.EffectiveMembership) error {
+	return d.effective.Replace(ctx, userId, memberships)
+}
+
+func (d *database) GetEffectiveMembershipByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error) {
+	return d.effective.GetByUserId(ctx, userId, tenantID, pagination)
+}
+
+func (d *database) GetEffectiveMembershipByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error) {
+	return d.effective.GetByGroupId(ctx, groupId, tenantID, pagination)
+}
+
+func (d *database) GetEffectiveMemberIds(ctx context.Context, groupId uuid.UUID) ([]string, error) {
+	return d.effective.GetMemberIds(ctx, groupId)
+}
+
+func (d *database) DeleteEffectiveMemberships(ctx context.Context, filter *entities.EffectiveMembership) error {
+	return d.effective.DeleteMany(ctx, filter)
+}
+
+func (d *database) CreateOrganization(ctx context.Context, model *entities.Organization) (*entities.Organization, error) {
+	return d.organizations.Create(ctx, model)
+}
+
+func (d *database) UpdateOrganization(ctx context.Context, model *entities.Organization) (*entities.Organization, error) {
+	return d.organizations.Update(ctx, model)
+}
+
+func (d *database) UpdateOrganizationStatus(ctx context.Context, model *entities.Organization) (*entities.Organization, error) {
+	return d.organizations.UpdateStatus(ctx, model)
+}
+
+func (d *database) GetOrganizationById(ctx context.Context, id uuid.UUID) (*entities.Organization, error) {
+	return d.organizations.GetById(ctx, id)
+}
+
+func (d *database) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	return d.organizations.Delete(ctx, id)
+}
+
+func (d *database) SearchOrganizations(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.OrganizationsList, error) {
+	return d.organizations.Search(ctx, search, pagination)
+}
+
+func (d *database) CreateRole(ctx context.Context, model *entities.Role) (*entities.Role, error) {
+	return d.roles.Create(ctx, model)
+}
+
+func (d *database) UpdateRole(ctx context.Context, model *entities.Role) (*entities.Role, error) {
+	return d.roles.Update(ctx, model)
+}
+
+func (d *database) GetRoleById(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	return d.roles.GetById(ctx, id)
+}
+
+func (d *database) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	return d.roles.Delete(ctx, id)
+}
+
+// DeleteRoles deletes the custom roles of the group or the organization of filter
+func (d *database) DeleteRoles(ctx context.Context, filter *entities.Role) error {
+	return d.roles.DeleteMany(ctx, filter)
+}
+
+func (d *database) SearchRoles(ctx context.Context, search string, groupID string, tenantID string, pagination *utilities.Pagination) (*entities.RolesList, error) {
+	return d.roles.Search(ctx, search, groupID, tenantID, pagination)
+}
+
 func (d *database) BlacklistToken(ctx context.Context, bList *entities.Blacklist) (*entities.Blacklist, error) {
 	return d.blacklist.BlacklistToken(ctx, bList)
 }
@@ -194,28 +307,35 @@ func (d *database) CheckTokenBlacklist(ctx context.Context, accessToken string)
 type Database interface {
 	CreateUser(ctx context.Context, user *entities.User) (*entities.User, error)
 	UpdateUser(ctx context.Context, user *entities.User) (*entities.User, error)
+	UpdateUserStatus(ctx context.Context, user *entities.User) (*entities.User, error)
 	GetUserById(ctx context.Context, id uuid.UUID) (*entities.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entities.User, error)
-	AuthenticateUser(ctx context.Context, email string, password string) (*entities.User, error)
 	DeleteUser(ctx context.Context, id uuid.UUID) error
-	SearchUsers(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.UsersList, error)
+	DeleteOrganizationUsers(ctx context.Context, organizationID string) ([]*entities.User, error)
+	SearchUsers(ctx context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.UsersList, error)
 	CreateGroup(ctx context.Context, model *entities.Group) (*entities.Group, error)
 	UpdateGroup(ctx context.Context, model *entities.Group) (*entities.Group, error)
 	GetGroupById(ctx context.Context, id uuid.UUID) (*entities.Group, error)
+	SetGroupParent(ctx context.Context, id uuid.UUID, parentID string) (*entities.Group, error)
+	GetChildGroups(ctx context.Context, parentID uuid.UUID) ([]*entities.Group, error)
+	DetachChildGroups(ctx context.Context, parentID uuid.UUID) ([]*entities.Group, error)
 	DeleteGroup(ctx context.Context, id uuid.UUID) error
-	SearchGroups(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.GroupsList, error)
+	DeleteOrganizationGroups(ctx context.Context, organizationID string, creatorIDs []string) ([]*entities.Group, error)
+	SearchGroups(ctx context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.GroupsList, error)
 	CreateMembership(ctx context.Context, model *entities.Membership) (*entities.Membership, error)
 	UpdateMembership(ctx context.Context, model *entities.Membership) (*entities.Membership, error)
 	UpdateMemberships(ctx context.Context, filter *entities.Membership, update *entities.Membership) error
 	GetMembershipById(ctx context.Context, id uuid.UUID) (*entities.Membership, error)
+	GetMembershipsByUserId(ctx context.Context, userId uuid.UUID) ([]*entities.Membership, error)
+	GetMembershipsByRoleId(ctx context.Context, roleId uuid.UUID) ([]*entities.Membership, error)
 	DeleteMembership(ctx context.Context, id uuid.UUID) error
 	DeleteMemberships(ctx context.Context, filter *entities.Membership) error
 	CreateGroupMembership(ctx context.Context, model *entities.GroupMembership) (*entities.GroupMembership, error)
 	UpdateGroupMembership(ctx context.Context, model *entities.GroupMembership) (*entities.GroupMembership, error)
 	UpdateGroupMemberships(ctx context.Context, filter *entities.GroupMembership, update *entities.GroupMembership) error
 	GetGroupMembershipById(ctx context.Context, id uuid.UUID, idType enums.ReadTableIdType) (*entities.GroupMembership, error)
-	GetGroupMembershipByUserId(ctx context.Context, userId uuid.UUID, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error)
-	GetGroupMembershipByGroupId(ctx context.Context, groupId uuid.UUID, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error)
+	GetGroupMembershipByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error)
+	GetGroupMembershipByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error)
 	DeleteGroupMembership(ctx context.Context, id uuid.UUID) error
 	DeleteGroupMemberships(ctx context.Context, filter *entities.GroupMembership) error
 	DeleteGroupMembershipByMembershipId(ctx context.Context, id uuid.UUID) error
@@ -223,11 +343,28 @@ type Database interface {
 	UpdateUserMembership(ctx context.Context, model *entities.UserMembership) (*entities.UserMembership, error)
 	UpdateUserMemberships(ctx context.Context, filter *entities.UserMembership, update *entities.UserMembership) error
 	GetUserMembershipById(ctx context.Context, id uuid.UUID, idType enums.ReadTableIdType) (*entities.UserMembership, error)
-	GetUserMembershipByUserId(ctx context.Context, userId uuid.UUID, pagination *utilities.Pagination) (*entities.UserMembershipsList, error)
-	GetUserMembershipByGroupId(ctx context.Context, groupId uuid.UUID, pagination *utilities.Pagination) (*entities.UserMembershipsList, error)
+	GetUserMembershipByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error)
+	GetUserMembershipByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error)
 	DeleteUserMembership(ctx context.Context, id uuid.UUID) error
 	DeleteUserMemberships(ctx context.Context, filter *entities.UserMembership) error
 	DeleteUserMembershipByMembershipId(ctx context.Context, id uuid.UUID) error
+	ReplaceEffectiveMemberships(ctx context.Context, userId uuid.UUID, memberships []*entities.EffectiveMembership) error
+	GetEffectiveMembershipByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error)
+	GetEffectiveMembershipByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error)
+	GetEffectiveMemberIds(ctx context.Context, groupId uuid.UUID) ([]string, error)
+	DeleteEffectiveMemberships(ctx context.Context, filter *entities.EffectiveMembership) error
+	CreateOrganization(ctx context.Context, model *entities.Organization) (*entities.Organization, error)
+	UpdateOrganization(ctx context.Context, model *entities.Organization) (*entities.Organization, error)
+	UpdateOrganizationStatus(ctx context.Context, model *entities.Organization) (*entities.Organization, error)
+	GetOrganizationById(ctx context.Context, id uuid.UUID) (*entities.Organization, error)
+	DeleteOrganization(ctx context.Context, id uuid.UUID) error
+	SearchOrganizations(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.OrganizationsList, error)
+	CreateRole(ctx context.Context, model *entities.Role) (*entities.Role, error)
+	UpdateRole(ctx context.Context, model *entities.Role) (*entities.Role, error)
+	GetRoleById(ctx context.Context, id uuid.UUID) (*entities.Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	DeleteRoles(ctx context.Context, filter *entities.Role) error
+	SearchRoles(ctx context.Context, search string, groupID string, tenantID string, pagination *utilities.Pagination) (*entities.RolesList, error)
 	BlacklistToken(ctx context.Context, bList *entities.Blacklist) (*entities.Blacklist, error)
 	CheckTokenBlacklist(ctx context.Context, accessToken string) (*entities.Blacklist, error)
 }
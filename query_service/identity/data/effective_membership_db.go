@@ -0,0 +1,159 @@
+package data
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// effectiveMembershipFilter generates a bson filter for MongoDB queries from the EffectiveMembership data
+func effectiveMembershipFilter(u *entities.EffectiveMembership) (doc bson.D) {
+	if u.UserID != "" {
+		doc = append(doc, bson.E{Key: "user_id", Value: memoryKey(u.UserID)})
+	}
+	if u.GroupID != "" {
+		doc = append(doc, bson.E{Key: "group_id", Value: memoryKey(u.GroupID)})
+	}
+	if u.OrganizationID != "" {
+		doc = append(doc, bson.E{Key: "organization_id", Value: organizationKey(u.OrganizationID)})
+	}
+	return
+}
+
+type effectiveMembershipRepository struct {
+	log logging.Logger
+	cfg *config.Config
+	db  *mongo.Client
+}
+
+func NewEffectiveMembershipRepository(log logging.Logger, cfg *config.Config, db *mongo.Client) *effectiveMembershipRepository {
+	return &effectiveMembershipRepository{
+		log: log,
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+// Replace swaps the effective memberships of the user with userId for memberships
+func (p *effectiveMembershipRepository) Replace(ctx context.Context, userId uuid.UUID, memberships []*entities.EffectiveMembership) error {
+	ctx, span := tracing.StartSpan(ctx, "effectiveMembershipRepository.Replace")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.EffectiveMemberships)
+	if _, err := collection.DeleteMany(ctx, bson.M{"user_id": userId.String()}); err != nil {
+		p.traceErr(span, err)
+		return errors.Wrap(err, "DeleteMany")
+	}
+	if len(memberships) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(memberships))
+	for _, m := range memberships {
+		doc := *m
+		doc.UserID = memoryKey(m.UserID)
+		doc.GroupID = memoryKey(m.GroupID)
+		doc.OrganizationID = organizationKey(m.OrganizationID)
+		doc.ID = entities.NewEffectiveMembershipId(doc.UserID, doc.GroupID)
+		docs = append(docs, &doc)
+	}
+	if _, err := collection.InsertMany(ctx, docs); err != nil {
+		p.traceErr(span, err)
+		return errors.Wrap(err, "InsertMany")
+	}
+	return nil
+}
+
+func (p *effectiveMembershipRepository) GetByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "effectiveMembershipRepository.GetByUserId")
+	defer span.End()
+	return p.list(ctx, span, withTenant(bson.D{{Key: "user_id", Value: userId.String()}}, tenantID), pagination)
+}
+
+func (p *effectiveMembershipRepository) GetByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "effectiveMembershipRepository.GetByGroupId")
+	defer span.End()
+	return p.list(ctx, span, withTenant(bson.D{{Key: "group_id", Value: groupId.String()}}, tenantID), pagination)
+}
+
+func (p *effectiveMembershipRepository) list(ctx context.Context, span trace.Span, filter bson.D, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error) {
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.EffectiveMemberships)
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.EffectiveMembershipsList{}, errors.Wrap(err, "CountDocuments")
+	}
+	if count == 0 {
+		return &entities.EffectiveMembershipsList{EffectiveMemberships: make([]*entities.EffectiveMembership, 0)}, nil
+	}
+	limit := int64(pagination.GetLimit())
+	skip := int64(pagination.GetOffset())
+	cursor, err := collection.Find(ctx, filter, &options.FindOptions{
+		Limit: &limit,
+		Skip:  &skip,
+	})
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.EffectiveMembershipsList{}, errors.Wrap(err, "Find")
+	}
+	defer cursor.Close(ctx) // nolint: errCheck
+	memberships := make([]*entities.EffectiveMembership, 0, pagination.GetSize())
+	for cursor.Next(ctx) {
+		var u entities.EffectiveMembership
+		if err = cursor.Decode(&u); err != nil {
+			p.traceErr(span, err)
+			return &entities.EffectiveMembershipsList{}, errors.Wrap(err, "Find")
+		}
+		memberships = append(memberships, &u)
+	}
+	if err = cursor.Err(); err != nil {
+		p.traceErr(span, err)
+		return &entities.EffectiveMembershipsList{}, errors.Wrap(err, "cursor.Err")
+	}
+	return entities.NewEffectiveMembershipListWithPagination(memberships, count, pagination), nil
+}
+
+// GetMemberIds returns the ids of the users that belong to the group with groupId directly or through its subgroups
+func (p *effectiveMembershipRepository) GetMemberIds(ctx context.Context, groupId uuid.UUID) ([]string, error) {
+	ctx, span := tracing.StartSpan(ctx, "effectiveMembershipRepository.GetMemberIds")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.EffectiveMemberships)
+	ids, err := collection.Distinct(ctx, "user_id", bson.M{"group_id": groupId.String()})
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Distinct")
+	}
+	userIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if userID, ok := id.(string); ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (p *effectiveMembershipRepository) DeleteMany(ctx context.Context, filter *entities.EffectiveMembership) error {
+	ctx, span := tracing.StartSpan(ctx, "effectiveMembershipRepository.DeleteMany")
+	defer span.End()
+	bsonFilter := effectiveMembershipFilter(filter)
+	if len(bsonFilter) == 0 {
+		return errors.New("empty effective membership filter")
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.EffectiveMemberships)
+	if _, err := collection.DeleteMany(ctx, bsonFilter); err != nil {
+		p.traceErr(span, err)
+		return errors.Wrap(err, "DeleteMany")
+	}
+	return nil
+}
+
+func (p *effectiveMembershipRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
+}
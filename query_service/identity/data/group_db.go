@@ -3,28 +3,31 @@ package data
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
 	"github.com/gofrs/uuid"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
 // groupEntity structures a user BSON document to save in a groups collection
 type groupEntity struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty"`
-	Name        string             `bson:"name,omitempty" validate:"required,min=3,max=500"`
-	Description string             `bson:"description,omitempty" validate:"required"`
-	CreatorID   primitive.ObjectID `bson:"creator_id,omitempty" validate:"required,min=3,max=250"`
-	Active      bool               `bson:"active,omitempty"`
-	CreatedAt   time.Time          `bson:"created_at,omitempty"`
-	UpdatedAt   time.Time          `bson:"updated_at,omitempty"`
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Name           string             `bson:"name,omitempty" validate:"required,min=3,max=500"`
+	Description    string             `bson:"description,omitempty" validate:"required"`
+	CreatorID      primitive.ObjectID `bson:"creator_id,omitempty" validate:"required,min=3,max=250"`
+	Active         bool               `bson:"active,omitempty"`
+	OrganizationID string             `bson:"organization_id,omitempty"`
+	ParentID       string             `bson:"parent_id,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at,omitempty"`
+	UpdatedAt      time.Time          `bson:"updated_at,omitempty"`
 }
 
 // getID returns the unique identifier of the groupEntity
@@ -35,11 +38,13 @@ func (u *groupEntity) getID() primitive.ObjectID {
 // newGroupEntity initializes a new pointer to a groupEntity struct from a *models.Group struct
 func newGroupEntity(u *entities.Group) (um *groupEntity, err error) {
 	um = &groupEntity{
-		Name:        u.Name,
-		Description: u.Description,
-		Active:      u.Active,
-		CreatedAt:   u.CreatedAt,
-		UpdatedAt:   u.UpdatedAt,
+		Name:           u.Name,
+		Description:    u.Description,
+		Active:         u.Active,
+		OrganizationID: u.OrganizationID,
+		ParentID:       u.ParentID,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.CreatorID) == nil {
 		um.CreatorID, err = utilities.LoadObjectIDString(u.CreatorID)
@@ -53,11 +58,13 @@ func newGroupEntity(u *entities.Group) (um *groupEntity, err error) {
 // toRoot creates and return a new pointer to a models.Group JSON struct from a pointer to a BSON groupEntity
 func (u *groupEntity) toRoot() *entities.Group {
 	um := &entities.Group{
-		Name:        u.Name,
-		Description: u.Description,
-		Active:      u.Active,
-		CreatedAt:   u.CreatedAt,
-		UpdatedAt:   u.UpdatedAt,
+		Name:           u.Name,
+		Description:    u.Description,
+		Active:         u.Active,
+		OrganizationID: u.OrganizationID,
+		ParentID:       u.ParentID,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.CreatorID.Hex()) == nil {
 		um.CreatorID = utilities.LoadUUIDString(u.CreatorID)
@@ -83,8 +90,8 @@ func NewGroupRepository(log logging.Logger, cfg *config.Config, db *mongo.Client
 }
 
 func (p *groupRepository) Create(ctx context.Context, model *entities.Group) (*entities.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.CreateGroup")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.CreateGroup")
+	defer span.End()
 	p.log.Info(model.ID)
 	ent, err := newGroupEntity(model)
 	if err != nil {
@@ -101,8 +108,8 @@ func (p *groupRepository) Create(ctx context.Context, model *entities.Group) (*e
 }
 
 func (p *groupRepository) Update(ctx context.Context, model *entities.Group) (*entities.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.UpdateGroup")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.UpdateGroup")
+	defer span.End()
 	ent, err := newGroupEntity(model)
 	if err != nil {
 		p.traceErr(span, err)
@@ -121,8 +128,8 @@ func (p *groupRepository) Update(ctx context.Context, model *entities.Group) (*e
 }
 
 func (p *groupRepository) GetById(ctx context.Context, id uuid.UUID) (*entities.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.GetGroupById")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.GetGroupById")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Groups)
 	var ent groupEntity
 	oId, err := utilities.LoadObjectID(id)
@@ -137,9 +144,83 @@ func (p *groupRepository) GetById(ctx context.Context, id uuid.UUID) (*entities.
 	return ent.toRoot(), nil
 }
 
+// SetParent moves a group below the group with parentID, or to the top level when parentID is empty
+func (p *groupRepository) SetParent(ctx context.Context, id uuid.UUID, parentID string) (*entities.Group, error) {
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.SetParent")
+	defer span.End()
+	oId, err := utilities.LoadObjectID(id)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.Group{}, errors.Wrap(err, "LoadObjectIDString")
+	}
+	update := bson.M{"$unset": bson.M{"parent_id": ""}}
+	if parentID != "" {
+		update = bson.M{"$set": bson.M{"parent_id": parentID}}
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Groups)
+	ops := options.FindOneAndUpdate()
+	ops.SetReturnDocument(options.After)
+	var ent groupEntity
+	if err = collection.FindOneAndUpdate(ctx, bson.M{"_id": oId}, update, ops).Decode(&ent); err != nil {
+		p.traceErr(span, err)
+		return &entities.Group{}, errors.Wrap(err, "Decode")
+	}
+	return ent.toRoot(), nil
+}
+
+// GetChildren returns the child groups of the group with id
+func (p *groupRepository) GetChildren(ctx context.Context, id uuid.UUID) ([]*entities.Group, error) {
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.GetChildren")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Groups)
+	cursor, err := collection.Find(ctx, bson.M{"parent_id": id.String()})
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Find")
+	}
+	var ents []*groupEntity
+	if err = cursor.All(ctx, &ents); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+	groups := make([]*entities.Group, 0, len(ents))
+	for _, ent := range ents {
+		groups = append(groups, ent.toRoot())
+	}
+	return groups, nil
+}
+
+// DetachChildren moves the child groups of the group with id to the top level, returning them
+func (p *groupRepository) DetachChildren(ctx context.Context, id uuid.UUID) ([]*entities.Group, error) {
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.DetachChildren")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Groups)
+	filter := bson.M{"parent_id": id.String()}
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Find")
+	}
+	var ents []*groupEntity
+	if err = cursor.All(ctx, &ents); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+	if _, err = collection.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"parent_id": ""}}); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "UpdateMany")
+	}
+	groups := make([]*entities.Group, 0, len(ents))
+	for _, ent := range ents {
+		ent.ParentID = ""
+		groups = append(groups, ent.toRoot())
+	}
+	return groups, nil
+}
+
 func (p *groupRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.DeleteGroup")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.DeleteGroup")
+	defer span.End()
 	oId, err := utilities.LoadObjectID(id)
 	if err != nil {
 		p.traceErr(span, err)
@@ -149,16 +230,56 @@ func (p *groupRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return collection.FindOneAndDelete(ctx, bson.M{"_id": oId}).Err()
 }
 
-func (p *groupRepository) Search(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.GroupsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupRepository.Search")
-	defer span.Finish()
+// DeleteByOrganization deletes the groups of an organization together with the groups created by the given users,
+// returning the deleted groups
+func (p *groupRepository) DeleteByOrganization(ctx context.Context, organizationID string, creatorIDs []string) ([]*entities.Group, error) {
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.DeleteByOrganization")
+	defer span.End()
+	creators := make(bson.A, 0, len(creatorIDs))
+	for _, creatorID := range creatorIDs {
+		oId, err := utilities.LoadObjectIDString(creatorID)
+		if err != nil {
+			p.traceErr(span, err)
+			return nil, errors.Wrap(err, "LoadObjectIDString")
+		}
+		creators = append(creators, oId)
+	}
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Groups)
-	filter := bson.D{
+	filter := bson.M{"$or": bson.A{
+		bson.M{"organization_id": organizationKey(organizationID)},
+		bson.M{"creator_id": bson.M{"$in": creators}},
+	}}
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Find")
+	}
+	var ents []*groupEntity
+	if err = cursor.All(ctx, &ents); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+	if _, err = collection.DeleteMany(ctx, filter); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "DeleteMany")
+	}
+	groups := make([]*entities.Group, 0, len(ents))
+	for _, ent := range ents {
+		groups = append(groups, ent.toRoot())
+	}
+	return groups, nil
+}
+
+func (p *groupRepository) Search(ctx context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.GroupsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "groupRepository.Search")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Groups)
+	filter := withTenant(bson.D{
 		{Key: "$or", Value: bson.A{
 			bson.D{{Key: "name", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
 			bson.D{{Key: "description", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
 		}},
-	}
+	}, tenantID)
 	count, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -188,14 +309,12 @@ func (p *groupRepository) Search(ctx context.Context, search string, pagination
 		groups = append(groups, u.toRoot())
 	}
 	if err = cursor.Err(); err != nil {
-		span.SetTag("error", true)
-		span.LogKV("error_code", err.Error())
+		tracing.TraceErr(span, err)
 		return &entities.GroupsList{}, errors.Wrap(err, "cursor.Err")
 	}
 	return entities.NewGroupListWithPagination(groups, count, pagination), nil
 }
 
-func (p *groupRepository) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+func (p *groupRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 }
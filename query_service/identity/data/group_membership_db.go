@@ -4,32 +4,39 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
 	"github.com/gofrs/uuid"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
 // groupMembershipEntity structures a user BSON document to save in a groupMemberships aggregate collection
 type groupMembershipEntity struct {
-	ID           primitive.ObjectID     `bson:"_id,omitempty"`
-	UserID       primitive.ObjectID     `bson:"user_id,omitempty" validate:"required"`
-	GroupID      primitive.ObjectID     `bson:"group_id,omitempty" validate:"required"`
-	MembershipID primitive.ObjectID     `bson:"membership_id,omitempty" validate:"required"`
-	Name         string                 `bson:"name,omitempty" validate:"required,min=3,max=250"`
-	Description  string                 `bson:"description,omitempty" validate:"required,min=3,max=250"`
-	Status       enums.MembershipStatus `bson:"status,omitempty"`
-	Role         enums.Role             `bson:"role,omitempty"`
-	Creator      bool                   `bson:"creator,omitempty"`
-	CreatedAt    time.Time              `bson:"created_at,omitempty"`
-	UpdatedAt    time.Time              `bson:"updated_at,omitempty"`
+	ID             primitive.ObjectID     `bson:"_id,omitempty"`
+	UserID         primitive.ObjectID     `bson:"user_id,omitempty" validate:"required"`
+	GroupID        primitive.ObjectID     `bson:"group_id,omitempty" validate:"required"`
+	MembershipID   primitive.ObjectID     `bson:"membership_id,omitempty" validate:"required"`
+	Name           string                 `bson:"name,omitempty" validate:"required,min=3,max=250"`
+	Description    string                 `bson:"description,omitempty" validate:"required,min=3,max=250"`
+	Status         enums.MembershipStatus `bson:"status,omitempty"`
+	Role           enums.Role             `bson:"role,omitempty"`
+	RoleID         string                 `bson:"role_id,omitempty"`
+	RoleName       string                 `bson:"role_name,omitempty"`
+	Permissions    []string               `bson:"permissions,omitempty"`
+	Creator        bool                   `bson:"creator,omitempty"`
+	OrganizationID string                 `bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `bson:"updated_at,omitempty"`
 }
 
 // getID returns the unique identifier of the groupMembershipEntity
@@ -47,6 +54,10 @@ func (u *groupMembershipEntity) bsonFilter() (doc bson.D, err error) {
 		doc = bson.D{{"group_id", u.GroupID}}
 	} else if utilities.CheckObjectID(u.MembershipID) == nil {
 		doc = bson.D{{"membership_id", u.MembershipID}}
+	} else if u.RoleID != "" {
+		doc = bson.D{{Key: "role_id", Value: u.RoleID}}
+	} else if u.OrganizationID != "" {
+		doc = bson.D{{Key: "organization_id", Value: u.OrganizationID}}
 	}
 	return
 }
@@ -54,12 +65,17 @@ func (u *groupMembershipEntity) bsonFilter() (doc bson.D, err error) {
 // newGroupMembershipEntity initializes a new pointer to an groupMembershipEntity struct from a models.GroupMembership
 func newGroupMembershipEntity(u *entities.GroupMembership) (um *groupMembershipEntity, err error) {
 	um = &groupMembershipEntity{
-		Name:        u.Name,
-		Description: u.Description,
-		Status:      u.Status,
-		Role:        u.Role,
-		CreatedAt:   u.CreatedAt,
-		UpdatedAt:   u.UpdatedAt,
+		Name:           u.Name,
+		Description:    u.Description,
+		Status:         u.Status,
+		Role:           u.Role,
+		RoleName:       u.RoleName,
+		Permissions:    u.Permissions,
+		OrganizationID: u.OrganizationID,
+		ValidFrom:      u.ValidFrom,
+		ValidUntil:     u.ValidUntil,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.UserID) == nil {
 		um.UserID, err = utilities.LoadObjectIDString(u.UserID)
@@ -73,18 +89,27 @@ func newGroupMembershipEntity(u *entities.GroupMembership) (um *groupMembershipE
 	if utilities.CheckID(u.ID) == nil {
 		um.ID, err = utilities.LoadObjectIDString(u.ID)
 	}
+	if u.RoleID != "" {
+		um.RoleID = memoryKey(u.RoleID)
+	}
 	return
 }
 
 // toRoot creates and returns a new pointer to a models.GroupMembership JSON struct from a groupMembershipEntity
 func (u *groupMembershipEntity) toRoot() *entities.GroupMembership {
 	um := &entities.GroupMembership{
-		Name:        u.Name,
-		Description: u.Description,
-		Status:      u.Status,
-		Role:        u.Role,
-		CreatedAt:   u.CreatedAt,
-		UpdatedAt:   u.UpdatedAt,
+		Name:           u.Name,
+		Description:    u.Description,
+		Status:         u.Status,
+		Role:           u.Role,
+		RoleID:         u.RoleID,
+		RoleName:       u.RoleName,
+		Permissions:    u.Permissions,
+		OrganizationID: u.OrganizationID,
+		ValidFrom:      u.ValidFrom,
+		ValidUntil:     u.ValidUntil,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.UserID.Hex()) == nil {
 		um.UserID = utilities.LoadUUIDString(u.UserID)
@@ -116,8 +141,8 @@ func NewGroupMembershipRepository(log logging.Logger, cfg *config.Config, db *mo
 }
 
 func (p *groupMembershipRepository) Create(ctx context.Context, model *entities.GroupMembership) (*entities.GroupMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.CreateGroupMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.CreateGroupMembership")
+	defer span.End()
 	p.log.Info(model.ID)
 	ent, err := newGroupMembershipEntity(model)
 	if err != nil {
@@ -134,8 +159,8 @@ func (p *groupMembershipRepository) Create(ctx context.Context, model *entities.
 }
 
 func (p *groupMembershipRepository) UpdateMany(ctx context.Context, filter *entities.GroupMembership, update *entities.GroupMembership) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.UpdateGroupMemberships")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.UpdateGroupMemberships")
+	defer span.End()
 	up, err := newGroupMembershipEntity(update)
 	if err != nil {
 		p.traceErr(span, err)
@@ -161,8 +186,8 @@ func (p *groupMembershipRepository) UpdateMany(ctx context.Context, filter *enti
 }
 
 func (p *groupMembershipRepository) Update(ctx context.Context, model *entities.GroupMembership) (*entities.GroupMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.UpdateGroupMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.UpdateGroupMembership")
+	defer span.End()
 	ent, err := newGroupMembershipEntity(model)
 	if err != nil {
 		p.traceErr(span, err)
@@ -181,8 +206,8 @@ func (p *groupMembershipRepository) Update(ctx context.Context, model *entities.
 }
 
 func (p *groupMembershipRepository) GetById(ctx context.Context, id uuid.UUID, idType enums.ReadTableIdType) (*entities.GroupMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.GetGroupMembershipById")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.GetGroupMembershipById")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.GroupMemberships)
 	var ent groupMembershipEntity
 	oId, err := utilities.LoadObjectID(id)
@@ -197,11 +222,11 @@ func (p *groupMembershipRepository) GetById(ctx context.Context, id uuid.UUID, i
 	return ent.toRoot(), nil
 }
 
-func (p *groupMembershipRepository) GetByUserId(ctx context.Context, userId uuid.UUID, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.GetByUserId")
-	defer span.Finish()
+func (p *groupMembershipRepository) GetByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.GetByUserId")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.GroupMemberships)
-	filter := bson.M{"user_id": userId}
+	filter := withTenant(bson.D{{Key: "user_id", Value: userId}}, tenantID)
 	count, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -231,18 +256,17 @@ func (p *groupMembershipRepository) GetByUserId(ctx context.Context, userId uuid
 		groups = append(groups, u.toRoot())
 	}
 	if err = cursor.Err(); err != nil {
-		span.SetTag("error", true)
-		span.LogKV("error_code", err.Error())
+		tracing.TraceErr(span, err)
 		return &entities.GroupMembershipsList{}, errors.Wrap(err, "cursor.Err")
 	}
 	return entities.NewGroupMembershipListWithPagination(groups, count, pagination), nil
 }
 
-func (p *groupMembershipRepository) GetByGroupId(ctx context.Context, groupId uuid.UUID, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.GetByGroupId")
-	defer span.Finish()
+func (p *groupMembershipRepository) GetByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.GetByGroupId")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.GroupMemberships)
-	filter := bson.M{"group_id": groupId}
+	filter := withTenant(bson.D{{Key: "group_id", Value: groupId}}, tenantID)
 	count, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -272,16 +296,15 @@ func (p *groupMembershipRepository) GetByGroupId(ctx context.Context, groupId uu
 		groups = append(groups, u.toRoot())
 	}
 	if err = cursor.Err(); err != nil {
-		span.SetTag("error", true)
-		span.LogKV("error_code", err.Error())
+		tracing.TraceErr(span, err)
 		return &entities.GroupMembershipsList{}, errors.Wrap(err, "cursor.Err")
 	}
 	return entities.NewGroupMembershipListWithPagination(groups, count, pagination), nil
 }
 
 func (p *groupMembershipRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.DeleteGroupMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.DeleteGroupMembership")
+	defer span.End()
 	oId, err := utilities.LoadObjectID(id)
 	if err != nil {
 		p.traceErr(span, err)
@@ -292,8 +315,8 @@ func (p *groupMembershipRepository) Delete(ctx context.Context, id uuid.UUID) er
 }
 
 func (p *groupMembershipRepository) DeleteByMembershipId(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.DeleteGroupMembershipByMembershipId")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.DeleteGroupMembershipByMembershipId")
+	defer span.End()
 	oId, err := utilities.LoadObjectID(id)
 	if err != nil {
 		p.traceErr(span, err)
@@ -304,8 +327,8 @@ func (p *groupMembershipRepository) DeleteByMembershipId(ctx context.Context, id
 }
 
 func (p *groupMembershipRepository) DeleteMany(ctx context.Context, filter *entities.GroupMembership) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "groupMembershipRepository.DeleteGroupMemberships")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "groupMembershipRepository.DeleteGroupMemberships")
+	defer span.End()
 	f, err := newGroupMembershipEntity(filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -325,7 +348,6 @@ func (p *groupMembershipRepository) DeleteMany(ctx context.Context, filter *enti
 	return nil
 }
 
-func (p *groupMembershipRepository) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+func (p *groupMembershipRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 }
@@ -4,28 +4,35 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
 	"github.com/gofrs/uuid"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
 // membershipEntity structures a user BSON document to save in a memberships collection
 type membershipEntity struct {
-	ID        primitive.ObjectID     `bson:"_id,omitempty"`
-	UserID    primitive.ObjectID     `bson:"user_id,omitempty" validate:"required"`
-	GroupID   primitive.ObjectID     `bson:"group_id,omitempty" validate:"required"`
-	Status    enums.MembershipStatus `bson:"status,omitempty"`
-	Role      enums.Role             `bson:"role,omitempty"`
-	CreatedAt time.Time              `bson:"created_at,omitempty"`
-	UpdatedAt time.Time              `bson:"updated_at,omitempty"`
+	ID             primitive.ObjectID     `bson:"_id,omitempty"`
+	UserID         primitive.ObjectID     `bson:"user_id,omitempty" validate:"required"`
+	GroupID        primitive.ObjectID     `bson:"group_id,omitempty" validate:"required"`
+	Status         enums.MembershipStatus `bson:"status,omitempty"`
+	Role           enums.Role             `bson:"role,omitempty"`
+	RoleID         string                 `bson:"role_id,omitempty"`
+	RoleName       string                 `bson:"role_name,omitempty"`
+	Permissions    []string               `bson:"permissions,omitempty"`
+	OrganizationID string                 `bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `bson:"updated_at,omitempty"`
 }
 
 // getID returns the unique identifier of the membershipEntity
@@ -41,6 +48,10 @@ func (u *membershipEntity) bsonFilter() (doc bson.D, err error) {
 		doc = bson.D{{"user_id", u.UserID}}
 	} else if utilities.CheckObjectID(u.GroupID) == nil {
 		doc = bson.D{{"group_id", u.GroupID}}
+	} else if u.RoleID != "" {
+		doc = bson.D{{Key: "role_id", Value: u.RoleID}}
+	} else if u.OrganizationID != "" {
+		doc = bson.D{{Key: "organization_id", Value: u.OrganizationID}}
 	}
 	return
 }
@@ -48,10 +59,15 @@ func (u *membershipEntity) bsonFilter() (doc bson.D, err error) {
 // newMembershipEntity initializes a new pointer to a membershipEntity struct from a models.Membership
 func newMembershipEntity(u *entities.Membership) (um *membershipEntity, err error) {
 	um = &membershipEntity{
-		Status:    u.Status,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		Status:         u.Status,
+		Role:           u.Role,
+		RoleName:       u.RoleName,
+		Permissions:    u.Permissions,
+		OrganizationID: u.OrganizationID,
+		ValidFrom:      u.ValidFrom,
+		ValidUntil:     u.ValidUntil,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.UserID) == nil {
 		um.UserID, err = utilities.LoadObjectIDString(u.UserID)
@@ -62,22 +78,31 @@ func newMembershipEntity(u *entities.Membership) (um *membershipEntity, err erro
 	if utilities.CheckID(u.ID) == nil {
 		um.ID, err = utilities.LoadObjectIDString(u.ID)
 	}
+	if u.RoleID != "" {
+		um.RoleID = memoryKey(u.RoleID)
+	}
 	return
 }
 
 // toRoot creates and return a new pointer to a *models.Membership JSON struct from a pointer to a BSON membershipEntity
 func (u *membershipEntity) toRoot() *entities.Membership {
 	um := &entities.Membership{
-		Status:    u.Status,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		Status:         u.Status,
+		Role:           u.Role,
+		RoleID:         u.RoleID,
+		RoleName:       u.RoleName,
+		Permissions:    u.Permissions,
+		OrganizationID: u.OrganizationID,
+		ValidFrom:      u.ValidFrom,
+		ValidUntil:     u.ValidUntil,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.UserID.Hex()) == nil {
 		um.UserID = utilities.LoadUUIDString(u.UserID)
 	}
 	if utilities.CheckID(u.GroupID.Hex()) == nil {
-		um.UserID = utilities.LoadUUIDString(u.GroupID)
+		um.GroupID = utilities.LoadUUIDString(u.GroupID)
 	}
 	if utilities.CheckID(u.ID.Hex()) == nil {
 		um.ID = utilities.LoadUUIDString(u.ID)
@@ -100,8 +125,8 @@ func NewMembershipRepository(log logging.Logger, cfg *config.Config, db *mongo.C
 }
 
 func (p *membershipRepository) Create(ctx context.Context, model *entities.Membership) (*entities.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.CreateMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.CreateMembership")
+	defer span.End()
 	p.log.Info(model.ID)
 	ent, err := newMembershipEntity(model)
 	if err != nil {
@@ -118,8 +143,8 @@ func (p *membershipRepository) Create(ctx context.Context, model *entities.Membe
 }
 
 func (p *membershipRepository) UpdateMany(ctx context.Context, filter *entities.Membership, update *entities.Membership) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.UpdateMemberships")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.UpdateMemberships")
+	defer span.End()
 	up, err := newMembershipEntity(update)
 	if err != nil {
 		p.traceErr(span, err)
@@ -145,8 +170,8 @@ func (p *membershipRepository) UpdateMany(ctx context.Context, filter *entities.
 }
 
 func (p *membershipRepository) Update(ctx context.Context, model *entities.Membership) (*entities.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.UpdateMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.UpdateMembership")
+	defer span.End()
 	ent, err := newMembershipEntity(model)
 	if err != nil {
 		p.traceErr(span, err)
@@ -165,8 +190,8 @@ func (p *membershipRepository) Update(ctx context.Context, model *entities.Membe
 }
 
 func (p *membershipRepository) GetById(ctx context.Context, id uuid.UUID) (*entities.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.GetMembershipById")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.GetMembershipById")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Memberships)
 	var ent membershipEntity
 	oId, err := utilities.LoadObjectID(id)
@@ -181,9 +206,58 @@ func (p *membershipRepository) GetById(ctx context.Context, id uuid.UUID) (*enti
 	return ent.toRoot(), nil
 }
 
+// ListByUserId returns every membership of the user with userId
+func (p *membershipRepository) ListByUserId(ctx context.Context, userId uuid.UUID) ([]*entities.Membership, error) {
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.ListByUserId")
+	defer span.End()
+	oId, err := utilities.LoadObjectID(userId)
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "LoadObjectIDString")
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Memberships)
+	cursor, err := collection.Find(ctx, bson.M{"user_id": oId})
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Find")
+	}
+	var ents []*membershipEntity
+	if err = cursor.All(ctx, &ents); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+	memberships := make([]*entities.Membership, 0, len(ents))
+	for _, ent := range ents {
+		memberships = append(memberships, ent.toRoot())
+	}
+	return memberships, nil
+}
+
+// ListByRoleId returns every membership granted the role with roleId
+func (p *membershipRepository) ListByRoleId(ctx context.Context, roleId uuid.UUID) ([]*entities.Membership, error) {
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.ListByRoleId")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Memberships)
+	cursor, err := collection.Find(ctx, bson.M{"role_id": roleId.String()})
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Find")
+	}
+	var ents []*membershipEntity
+	if err = cursor.All(ctx, &ents); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+	memberships := make([]*entities.Membership, 0, len(ents))
+	for _, ent := range ents {
+		memberships = append(memberships, ent.toRoot())
+	}
+	return memberships, nil
+}
+
 func (p *membershipRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.DeleteMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.DeleteMembership")
+	defer span.End()
 	oId, err := utilities.LoadObjectID(id)
 	if err != nil {
 		p.traceErr(span, err)
@@ -194,8 +268,8 @@ func (p *membershipRepository) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 func (p *membershipRepository) DeleteMany(ctx context.Context, filter *entities.Membership) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "membershipRepository.DeleteMemberships")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "membershipRepository.DeleteMemberships")
+	defer span.End()
 	f, err := newMembershipEntity(filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -215,7 +289,6 @@ func (p *membershipRepository) DeleteMany(ctx context.Context, filter *entities.
 	return nil
 }
 
-func (p *membershipRepository) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+func (p *membershipRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 }
@@ -0,0 +1,1131 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// memoryDatabase is an in-process Database mirroring the mongo read model: updates are upserts of the non-zero
+// fields, lookups that miss surface as mongo.ErrNoDocuments and duplicate inserts as duplicate key errors
+type memoryDatabase struct {
+	log                  logging.Logger
+	cfg                  *config.Config
+	mu                   sync.RWMutex
+	users                map[string]*entities.User
+	groups               map[string]*entities.Group
+	memberships          map[string]*entities.Membership
+	groupMemberships     map[string]*entities.GroupMembership
+	userMemberships      map[string]*entities.UserMembership
+	blacklist            map[string]*entities.Blacklist
+	organizations        map[string]*entities.Organization
+	effectiveMemberships map[string]*entities.EffectiveMembership
+	roles                map[string]*entities.Role
+}
+
+// NewMemoryDatabase Initializes a new in-process Database
+func NewMemoryDatabase(log logging.Logger, cfg *config.Config) *memoryDatabase {
+	return &memoryDatabase{
+		log:                  log,
+		cfg:                  cfg,
+		users:                make(map[string]*entities.User),
+		groups:               make(map[string]*entities.Group),
+		memberships:          make(map[string]*entities.Membership),
+		groupMemberships:     make(map[string]*entities.GroupMembership),
+		userMemberships:      make(map[string]*entities.UserMembership),
+		blacklist:            make(map[string]*entities.Blacklist),
+		organizations:        make(map[string]*entities.Organization),
+		effectiveMemberships: make(map[string]*entities.EffectiveMembership),
+		roles:                make(map[string]*entities.Role),
+	}
+}
+
+// memoryKey normalizes the hyphenated and object id backed forms of an id string into one key
+func memoryKey(id string) string {
+	if parsed, err := uuid.FromString(id); err == nil {
+		return parsed.String()
+	}
+	return id
+}
+
+// sameID reports whether an optional filter id is unset or matches id
+func sameID(filter string, id string) bool {
+	return utilities.CheckID(filter) != nil || memoryKey(filter) == memoryKey(id)
+}
+
+func noDocuments() error {
+	return errors.Wrap(mongo.ErrNoDocuments, "Decode")
+}
+
+func duplicateDocument() error {
+	return errors.Wrap(mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}, "InsertOne")
+}
+
+// memoryCopy deep copies an entity so callers never share state with the store
+func memoryCopy[T any](src *T) *T {
+	dst := new(T)
+	if b, err := json.Marshal(src); err == nil {
+		_ = json.Unmarshal(b, dst)
+	}
+	return dst
+}
+
+// memoryPage sorts the matches by creation and returns the window selected by pagination
+func memoryPage[T any](items []*T, pagination *utilities.Pagination, less func(a, b *T) bool) []*T {
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+	offset := pagination.GetOffset()
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := len(items)
+	if limit := pagination.GetLimit(); limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	page := make([]*T, 0, end-offset)
+	for _, item := range items[offset:end] {
+		page = append(page, memoryCopy(item))
+	}
+	return page
+}
+
+// inOrganization reports whether a document of organizationID belongs to the organization of filter
+func inOrganization(filter string, organizationID string) bool {
+	return organizationKey(filter) == organizationKey(organizationID)
+}
+
+func containsFold(s string, search string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(search))
+}
+
+func (d *memoryDatabase) CreateUser(_ context.Context, user *entities.User) (*entities.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.users[memoryKey(user.ID)]; ok {
+		return &entities.User{}, duplicateDocument()
+	}
+	d.users[memoryKey(user.ID)] = memoryCopy(user)
+	return user, nil
+}
+
+func (d *memoryDatabase) UpdateUser(_ context.Context, user *entities.User) (*entities.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.users[memoryKey(user.ID)]
+	if !ok {
+		updated = &entities.User{ID: user.ID}
+		d.users[memoryKey(user.ID)] = updated
+	}
+	mergeUser(updated, user)
+	return memoryCopy(updated), nil
+}
+
+func (d *memoryDatabase) UpdateUserStatus(_ context.Context, user *entities.User) (*entities.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.users[memoryKey(user.ID)]
+	if !ok {
+		return &entities.User{}, noDocuments()
+	}
+	updated.Status = user.Status
+	updated.Active = user.Active
+	updated.StatusReason = user.StatusReason
+	updated.SuspendedUntil = user.SuspendedUntil
+	if !user.SessionsRevokedAt.IsZero() {
+		updated.SessionsRevokedAt = user.SessionsRevokedAt
+	}
+	updated.UpdatedAt = user.UpdatedAt
+	return memoryCopy(updated), nil
+}
+
+func mergeUser(dst *entities.User, src *entities.User) {
+	if src.Email != "" {
+		dst.Email = src.Email
+	}
+	if src.Username != "" {
+		dst.Username = src.Username
+	}
+	if src.Root {
+		dst.Root = true
+	}
+	if src.Active {
+		dst.Active = true
+	}
+	if src.Status != 0 {
+		dst.Status = src.Status
+	}
+	if src.OrganizationID != "" {
+		dst.OrganizationID = src.OrganizationID
+	}
+	if src.OrgAdmin {
+		dst.OrgAdmin = true
+	}
+	if !src.CreatedAt.IsZero() {
+		dst.CreatedAt = src.CreatedAt
+	}
+	if !src.UpdatedAt.IsZero() {
+		dst.UpdatedAt = src.UpdatedAt
+	}
+}
+
+func (d *memoryDatabase) GetUserById(_ context.Context, id uuid.UUID) (*entities.User, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.users[id.String()]
+	if !ok {
+		return &entities.User{}, noDocuments()
+	}
+	return memoryCopy(found), nil
+}
+
+func (d *memoryDatabase) GetUserByEmail(_ context.Context, email string) (*entities.User, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, u := range d.users {
+		if u.Email == email {
+			return memoryCopy(u), nil
+		}
+	}
+	return &entities.User{}, noDocuments()
+}
+
+func (d *memoryDatabase) DeleteUser(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.users[id.String()]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(d.users, id.String())
+	return nil
+}
+
+// DeleteOrganizationUsers deletes the users of an organization, returning the deleted users
+func (d *memoryDatabase) DeleteOrganizationUsers(_ context.Context, organizationID string) ([]*entities.User, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	deleted := make([]*entities.User, 0)
+	for k, u := range d.users {
+		if inOrganization(organizationID, u.OrganizationID) {
+			deleted = append(deleted, u)
+			delete(d.users, k)
+		}
+	}
+	return deleted, nil
+}
+
+func (d *memoryDatabase) SearchUsers(_ context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.UsersList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	matches := make([]*entities.User, 0)
+	for _, u := range d.users {
+		if (containsFold(u.Username, search) || containsFold(u.Email, search)) && authentication.InTenant(tenantID, u.OrganizationID) {
+			matches = append(matches, u)
+		}
+	}
+	if len(matches) == 0 {
+		return &entities.UsersList{Users: make([]*entities.User, 0)}, nil
+	}
+	users := memoryPage(matches, pagination, func(a, b *entities.User) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return entities.NewUserListWithPagination(users, int64(len(matches)), pagination), nil
+}
+
+func (d *memoryDatabase) CreateGroup(_ context.Context, model *entities.Group) (*entities.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.groups[memoryKey(model.ID)]; ok {
+		return &entities.Group{}, duplicateDocument()
+	}
+	d.groups[memoryKey(model.ID)] = memoryCopy(model)
+	return model, nil
+}
+
+func (d *memoryDatabase) UpdateGroup(_ context.Context, model *entities.Group) (*entities.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.groups[memoryKey(model.ID)]
+	if !ok {
+		updated = &entities.Group{ID: model.ID}
+		d.groups[memoryKey(model.ID)] = updated
+	}
+	if model.Name != "" {
+		updated.Name = model.Name
+	}
+	if model.Description != "" {
+		updated.Description = model.Description
+	}
+	if model.CreatorID != "" {
+		updated.CreatorID = model.CreatorID
+	}
+	if model.Active {
+		updated.Active = true
+	}
+	if model.OrganizationID != "" {
+		updated.OrganizationID = model.OrganizationID
+	}
+	if !model.CreatedAt.IsZero() {
+		updated.CreatedAt = model.CreatedAt
+	}
+	if !model.UpdatedAt.IsZero() {
+		updated.UpdatedAt = model.UpdatedAt
+	}
+	return memoryCopy(updated), nil
+}
+
+func (d *memoryDatabase) GetGroupById(_ context.Context, id uuid.UUID) (*entities.Group, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.groups[id.String()]
+	if !ok {
+		return &entities.Group{}, noDocuments()
+	}
+	return memoryCopy(found), nil
+}
+
+func (d *memoryDatabase) SetGroupParent(_ context.Context, id uuid.UUID, parentID string) (*entities.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	found, ok := d.groups[id.String()]
+	if !ok {
+		return &entities.Group{}, noDocuments()
+	}
+	found.ParentID = parentID
+	return memoryCopy(found), nil
+}
+
+func (d *memoryDatabase) GetChildGroups(_ context.Context, parentID uuid.UUID) ([]*entities.Group, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	children := make([]*entities.Group, 0)
+	for _, g := range d.groups {
+		if g.ParentID != "" && memoryKey(g.ParentID) == parentID.String() {
+			children = append(children, memoryCopy(g))
+		}
+	}
+	return children, nil
+}
+
+func (d *memoryDatabase) DetachChildGroups(_ context.Context, parentID uuid.UUID) ([]*entities.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	detached := make([]*entities.Group, 0)
+	for _, g := range d.groups {
+		if g.ParentID != "" && memoryKey(g.ParentID) == parentID.String() {
+			g.ParentID = ""
+			detached = append(detached, memoryCopy(g))
+		}
+	}
+	return detached, nil
+}
+
+func (d *memoryDatabase) DeleteGroup(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.groups[id.String()]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(d.groups, id.String())
+	return nil
+}
+
+// DeleteOrganizationGroups deletes the groups of an organization and the groups its users created, returning them
+func (d *memoryDatabase) DeleteOrganizationGroups(_ context.Context, organizationID string, creatorIDs []string) ([]*entities.Group, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	creators := make(map[string]bool, len(creatorIDs))
+	for _, creatorID := range creatorIDs {
+		creators[memoryKey(creatorID)] = true
+	}
+	deleted := make([]*entities.Group, 0)
+	for k, g := range d.groups {
+		if inOrganization(organizationID, g.OrganizationID) || creators[memoryKey(g.CreatorID)] {
+			deleted = append(deleted, g)
+			delete(d.groups, k)
+		}
+	}
+	return deleted, nil
+}
+
+func (d *memoryDatabase) SearchGroups(_ context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.GroupsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	matches := make([]*entities.Group, 0)
+	for _, g := range d.groups {
+		if (containsFold(g.Name, search) || containsFold(g.Description, search)) && authentication.InTenant(tenantID, g.OrganizationID) {
+			matches = append(matches, g)
+		}
+	}
+	if len(matches) == 0 {
+		return &entities.GroupsList{Groups: make([]*entities.Group, 0)}, nil
+	}
+	groups := memoryPage(matches, pagination, func(a, b *entities.Group) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return entities.NewGroupListWithPagination(groups, int64(len(matches)), pagination), nil
+}
+
+func (d *memoryDatabase) CreateMembership(_ context.Context, model *entities.Membership) (*entities.Membership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.memberships[memoryKey(model.ID)]; ok {
+		return &entities.Membership{}, duplicateDocument()
+	}
+	d.memberships[memoryKey(model.ID)] = memoryCopy(model)
+	return model, nil
+}
+
+func mergeMembership(dst *entities.Membership, src *entities.Membership) {
+	if src.UserID != "" {
+		dst.UserID = src.UserID
+	}
+	if src.GroupID != "" {
+		dst.GroupID = src.GroupID
+	}
+	if src.Status != 0 {
+		dst.Status = src.Status
+	}
+	if src.Role != 0 {
+		dst.Role = src.Role
+	}
+	if src.RoleID != "" {
+		dst.RoleID = src.RoleID
+	}
+	if src.RoleName != "" {
+		dst.RoleName = src.RoleName
+	}
+	if src.Permissions != nil {
+		dst.Permissions = src.Permissions
+	}
+	if src.OrganizationID != "" {
+		dst.OrganizationID = src.OrganizationID
+	}
+	if !src.ValidFrom.IsZero() {
+		dst.ValidFrom = src.ValidFrom
+	}
+	if !src.ValidUntil.IsZero() {
+		dst.ValidUntil = src.ValidUntil
+	}
+	if !src.CreatedAt.IsZero() {
+		dst.CreatedAt = src.CreatedAt
+	}
+	if !src.UpdatedAt.IsZero() {
+		dst.UpdatedAt = src.UpdatedAt
+	}
+}
+
+func (d *memoryDatabase) UpdateMembership(_ context.Context, model *entities.Membership) (*entities.Membership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.memberships[memoryKey(model.ID)]
+	if !ok {
+		updated = &entities.Membership{ID: model.ID}
+		d.memberships[memoryKey(model.ID)] = updated
+	}
+	mergeMembership(updated, model)
+	return memoryCopy(updated), nil
+}
+
+// membershipMatches applies the first set id of a filter, the same precedence as the mongo bsonFilter
+func membershipMatches(filter *entities.Membership, m *entities.Membership) bool {
+	switch {
+	case utilities.CheckID(filter.ID) == nil:
+		return sameID(filter.ID, m.ID)
+	case utilities.CheckID(filter.UserID) == nil:
+		return sameID(filter.UserID, m.UserID)
+	case utilities.CheckID(filter.GroupID) == nil:
+		return sameID(filter.GroupID, m.GroupID)
+	case filter.RoleID != "":
+		return sameID(filter.RoleID, m.RoleID)
+	case filter.OrganizationID != "":
+		return inOrganization(filter.OrganizationID, m.OrganizationID)
+	}
+	return true
+}
+
+func (d *memoryDatabase) UpdateMemberships(_ context.Context, filter *entities.Membership, update *entities.Membership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, m := range d.memberships {
+		if membershipMatches(filter, m) {
+			mergeMembership(m, update)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) GetMembershipById(_ context.Context, id uuid.UUID) (*entities.Membership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.memberships[id.String()]
+	if !ok {
+		return &entities.Membership{}, noDocuments()
+	}
+	return memoryCopy(found), nil
+}
+
+func (d *memoryDatabase) GetMembershipsByUserId(_ context.Context, userId uuid.UUID) ([]*entities.Membership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	memberships := make([]*entities.Membership, 0)
+	for _, m := range d.memberships {
+		if memoryKey(m.UserID) == userId.String() {
+			memberships = append(memberships, memoryCopy(m))
+		}
+	}
+	return memberships, nil
+}
+
+func (d *memoryDatabase) GetMembershipsByRoleId(_ context.Context, roleId uuid.UUID) ([]*entities.Membership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	memberships := make([]*entities.Membership, 0)
+	for _, m := range d.memberships {
+		if memoryKey(m.RoleID) == roleId.String() {
+			memberships = append(memberships, memoryCopy(m))
+		}
+	}
+	return memberships, nil
+}
+
+func (d *memoryDatabase) DeleteMembership(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.memberships[id.String()]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(d.memberships, id.String())
+	return nil
+}
+
+func (d *memoryDatabase) DeleteMemberships(_ context.Context, filter *entities.Membership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, m := range d.memberships {
+		if membershipMatches(filter, m) {
+			delete(d.memberships, k)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) CreateGroupMembership(_ context.Context, model *entities.GroupMembership) (*entities.GroupMembership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.groupMemberships[memoryKey(model.ID)]; ok {
+		return &entities.GroupMembership{}, duplicateDocument()
+	}
+	d.groupMemberships[memoryKey(model.ID)] = memoryCopy(model)
+	return model, nil
+}
+
+func mergeGroupMembership(dst *entities.GroupMembership, src *entities.GroupMembership) {
+	if src.UserID != "" {
+		dst.UserID = src.UserID
+	}
+	if src.GroupID != "" {
+		dst.GroupID = src.GroupID
+	}
+	if src.MembershipID != "" {
+		dst.MembershipID = src.MembershipID
+	}
+	if src.Name != "" {
+		dst.Name = src.Name
+	}
+	if src.Description != "" {
+		dst.Description = src.Description
+	}
+	if src.Status != 0 {
+		dst.Status = src.Status
+	}
+	if src.Role != 0 {
+		dst.Role = src.Role
+	}
+	if src.RoleID != "" {
+		dst.RoleID = src.RoleID
+	}
+	if src.RoleName != "" {
+		dst.RoleName = src.RoleName
+	}
+	if src.Permissions != nil {
+		dst.Permissions = src.Permissions
+	}
+	if src.Creator {
+		dst.Creator = true
+	}
+	if src.OrganizationID != "" {
+		dst.OrganizationID = src.OrganizationID
+	}
+	if !src.ValidFrom.IsZero() {
+		dst.ValidFrom = src.ValidFrom
+	}
+	if !src.ValidUntil.IsZero() {
+		dst.ValidUntil = src.ValidUntil
+	}
+	if !src.CreatedAt.IsZero() {
+		dst.CreatedAt = src.CreatedAt
+	}
+	if !src.UpdatedAt.IsZero() {
+		dst.UpdatedAt = src.UpdatedAt
+	}
+}
+
+func (d *memoryDatabase) UpdateGroupMembership(_ context.Context, model *entities.GroupMembership) (*entities.GroupMembership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.groupMemberships[memoryKey(model.ID)]
+	if !ok {
+		updated = &entities.GroupMembership{ID: model.ID}
+		d.groupMemberships[memoryKey(model.ID)] = updated
+	}
+	mergeGroupMembership(updated, model)
+	return memoryCopy(updated), nil
+}
+
+// groupMembershipMatches applies the first set id of a filter, the same precedence as the mongo bsonFilter
+func groupMembershipMatches(filter *entities.GroupMembership, m *entities.GroupMembership) bool {
+	switch {
+	case utilities.CheckID(filter.ID) == nil:
+		return sameID(filter.ID, m.ID)
+	case utilities.CheckID(filter.UserID) == nil:
+		return sameID(filter.UserID, m.UserID)
+	case utilities.CheckID(filter.GroupID) == nil:
+		return sameID(filter.GroupID, m.GroupID)
+	case utilities.CheckID(filter.MembershipID) == nil:
+		return sameID(filter.MembershipID, m.MembershipID)
+	case filter.RoleID != "":
+		return sameID(filter.RoleID, m.RoleID)
+	case filter.OrganizationID != "":
+		return inOrganization(filter.OrganizationID, m.OrganizationID)
+	}
+	return true
+}
+
+func (d *memoryDatabase) UpdateGroupMemberships(_ context.Context, filter *entities.GroupMembership, update *entities.GroupMembership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, m := range d.groupMemberships {
+		if groupMembershipMatches(filter, m) {
+			mergeGroupMembership(m, update)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) GetGroupMembershipById(_ context.Context, id uuid.UUID, idType enums.ReadTableIdType) (*entities.GroupMembership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for k, m := range d.groupMemberships {
+		if (idType == enums.PRIMARY && k == id.String()) || (idType != enums.PRIMARY && sameID(id.String(), m.MembershipID)) {
+			return memoryCopy(m), nil
+		}
+	}
+	return &entities.GroupMembership{}, noDocuments()
+}
+
+func (d *memoryDatabase) groupMembershipsWhere(match func(m *entities.GroupMembership) bool, pagination *utilities.Pagination) *entities.GroupMembershipsList {
+	matches := make([]*entities.GroupMembership, 0)
+	for _, m := range d.groupMemberships {
+		if match(m) {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		return &entities.GroupMembershipsList{GroupMemberships: make([]*entities.GroupMembership, 0)}
+	}
+	page := memoryPage(matches, pagination, func(a, b *entities.GroupMembership) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return entities.NewGroupMembershipListWithPagination(page, int64(len(matches)), pagination)
+}
+
+func (d *memoryDatabase) GetGroupMembershipByUserId(_ context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.groupMembershipsWhere(func(m *entities.GroupMembership) bool {
+		return sameID(userId.String(), m.UserID) && authentication.InTenant(tenantID, m.OrganizationID)
+	}, pagination), nil
+}
+
+func (d *memoryDatabase) GetGroupMembershipByGroupId(_ context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.GroupMembershipsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.groupMembershipsWhere(func(m *entities.GroupMembership) bool {
+		return sameID(groupId.String(), m.GroupID) && authentication.InTenant(tenantID, m.OrganizationID)
+	}, pagination), nil
+}
+
+func (d *memoryDatabase) DeleteGroupMembership(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.groupMemberships[id.String()]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(d.groupMemberships, id.String())
+	return nil
+}
+
+func (d *memoryDatabase) DeleteGroupMemberships(_ context.Context, filter *entities.GroupMembership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, m := range d.groupMemberships {
+		if groupMembershipMatches(filter, m) {
+			delete(d.groupMemberships, k)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) DeleteGroupMembershipByMembershipId(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, m := range d.groupMemberships {
+		if sameID(id.String(), m.MembershipID) {
+			delete(d.groupMemberships, k)
+			return nil
+		}
+	}
+	return mongo.ErrNoDocuments
+}
+
+func (d *memoryDatabase) CreateUserMembership(_ context.Context, model *entities.UserMembership) (*entities.UserMembership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.userMemberships[memoryKey(model.ID)]; ok {
+		return &entities.UserMembership{}, duplicateDocument()
+	}
+	d.userMemberships[memoryKey(model.ID)] = memoryCopy(model)
+	return model, nil
+}
+
+func mergeUserMembership(dst *entities.UserMembership, src *entities.UserMembership) {
+	if src.UserID != "" {
+		dst.UserID = src.UserID
+	}
+	if src.GroupID != "" {
+		dst.GroupID = src.GroupID
+	}
+	if src.MembershipID != "" {
+		dst.MembershipID = src.MembershipID
+	}
+	if src.Email != "" {
+		dst.Email = src.Email
+	}
+	if src.Username != "" {
+		dst.Username = src.Username
+	}
+	if src.Status != 0 {
+		dst.Status = src.Status
+	}
+	if src.Role != 0 {
+		dst.Role = src.Role
+	}
+	if src.RoleID != "" {
+		dst.RoleID = src.RoleID
+	}
+	if src.RoleName != "" {
+		dst.RoleName = src.RoleName
+	}
+	if src.Permissions != nil {
+		dst.Permissions = src.Permissions
+	}
+	if src.OrganizationID != "" {
+		dst.OrganizationID = src.OrganizationID
+	}
+	if !src.ValidFrom.IsZero() {
+		dst.ValidFrom = src.ValidFrom
+	}
+	if !src.ValidUntil.IsZero() {
+		dst.ValidUntil = src.ValidUntil
+	}
+	if !src.CreatedAt.IsZero() {
+		dst.CreatedAt = src.CreatedAt
+	}
+	if !src.UpdatedAt.IsZero() {
+		dst.UpdatedAt = src.UpdatedAt
+	}
+}
+
+func (d *memoryDatabase) UpdateUserMembership(_ context.Context, model *entities.UserMembership) (*entities.UserMembership, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.userMemberships[memoryKey(model.ID)]
+	if !ok {
+		updated = &entities.UserMembership{ID: model.ID}
+		d.userMemberships[memoryKey(model.ID)] = updated
+	}
+	mergeUserMembership(updated, model)
+	return memoryCopy(updated), nil
+}
+
+// userMembershipMatches applies the first set id of a filter, the same precedence as the mongo bsonFilter
+func userMembershipMatches(filter *entities.UserMembership, m *entities.UserMembership) bool {
+	switch {
+	case utilities.CheckID(filter.ID) == nil:
+		return sameID(filter.ID, m.ID)
+	case utilities.CheckID(filter.UserID) == nil:
+		return sameID(filter.UserID, m.UserID)
+	case utilities.CheckID(filter.GroupID) == nil:
+		return sameID(filter.GroupID, m.GroupID)
+	case utilities.CheckID(filter.MembershipID) == nil:
+		return sameID(filter.MembershipID, m.MembershipID)
+	case filter.RoleID != "":
+		return sameID(filter.RoleID, m.RoleID)
+	case filter.OrganizationID != "":
+		return inOrganization(filter.OrganizationID, m.OrganizationID)
+	}
+	return true
+}
+
+func (d *memoryDatabase) UpdateUserMemberships(_ context.Context, filter *entities.UserMembership, update *entities.UserMembership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, m := range d.userMemberships {
+		if userMembershipMatches(filter, m) {
+			mergeUserMembership(m, update)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) GetUserMembershipById(_ context.Context, id uuid.UUID, idType enums.ReadTableIdType) (*entities.UserMembership, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for k, m := range d.userMemberships {
+		if (idType == enums.PRIMARY && k == id.String()) || (idType != enums.PRIMARY && sameID(id.String(), m.MembershipID)) {
+			return memoryCopy(m), nil
+		}
+	}
+	return &entities.UserMembership{}, noDocuments()
+}
+
+func (d *memoryDatabase) userMembershipsWhere(match func(m *entities.UserMembership) bool, pagination *utilities.Pagination) *entities.UserMembershipsList {
+	matches := make([]*entities.UserMembership, 0)
+	for _, m := range d.userMemberships {
+		if match(m) {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		return &entities.UserMembershipsList{UserMemberships: make([]*entities.UserMembership, 0)}
+	}
+	page := memoryPage(matches, pagination, func(a, b *entities.UserMembership) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return entities.NewUserMembershipListWithPagination(page, int64(len(matches)), pagination)
+}
+
+func (d *memoryDatabase) GetUserMembershipByUserId(_ context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.userMembershipsWhere(func(m *entities.UserMembership) bool {
+		return sameID(userId.String(), m.UserID) && authentication.InTenant(tenantID, m.OrganizationID)
+	}, pagination), nil
+}
+
+func (d *memoryDatabase) GetUserMembershipByGroupId(_ context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.userMembershipsWhere(func(m *entities.UserMembership) bool {
+		return sameID(groupId.String(), m.GroupID) && authentication.InTenant(tenantID, m.OrganizationID)
+	}, pagination), nil
+}
+
+func (d *memoryDatabase) DeleteUserMembership(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.userMemberships[id.String()]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(d.userMemberships, id.String())
+	return nil
+}
+
+func (d *memoryDatabase) DeleteUserMemberships(_ context.Context, filter *entities.UserMembership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, m := range d.userMemberships {
+		if userMembershipMatches(filter, m) {
+			delete(d.userMemberships, k)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) DeleteUserMembershipByMembershipId(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, m := range d.userMemberships {
+		if sameID(id.String(), m.MembershipID) {
+			delete(d.userMemberships, k)
+			return nil
+		}
+	}
+	return mongo.ErrNoDocuments
+}
+
+func (d *memoryDatabase) ReplaceEffectiveMemberships(_ context.Context, userId uuid.UUID, memberships []*entities.EffectiveMembership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, m := range d.effectiveMemberships {
+		if memoryKey(m.UserID) == userId.String() {
+			delete(d.effectiveMemberships, k)
+		}
+	}
+	for _, m := range memberships {
+		doc := memoryCopy(m)
+		doc.ID = entities.NewEffectiveMembershipId(memoryKey(m.UserID), memoryKey(m.GroupID))
+		d.effectiveMemberships[doc.ID] = doc
+	}
+	return nil
+}
+
+func (d *memoryDatabase) effectiveMembershipsWhere(match func(m *entities.EffectiveMembership) bool, pagination *utilities.Pagination) *entities.EffectiveMembershipsList {
+	matches := make([]*entities.EffectiveMembership, 0)
+	for _, m := range d.effectiveMemberships {
+		if match(m) {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		return &entities.EffectiveMembershipsList{EffectiveMemberships: make([]*entities.EffectiveMembership, 0)}
+	}
+	page := memoryPage(matches, pagination, func(a, b *entities.EffectiveMembership) bool { return a.ID < b.ID })
+	return entities.NewEffectiveMembershipListWithPagination(page, int64(len(matches)), pagination)
+}
+
+func (d *memoryDatabase) GetEffectiveMembershipByUserId(_ context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.effectiveMembershipsWhere(func(m *entities.EffectiveMembership) bool {
+		return sameID(userId.String(), m.UserID) && authentication.InTenant(tenantID, m.OrganizationID)
+	}, pagination), nil
+}
+
+func (d *memoryDatabase) GetEffectiveMembershipByGroupId(_ context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.EffectiveMembershipsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.effectiveMembershipsWhere(func(m *entities.EffectiveMembership) bool {
+		return sameID(groupId.String(), m.GroupID) && authentication.InTenant(tenantID, m.OrganizationID)
+	}, pagination), nil
+}
+
+func (d *memoryDatabase) GetEffectiveMemberIds(_ context.Context, groupId uuid.UUID) ([]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	userIDs := make([]string, 0)
+	for _, m := range d.effectiveMemberships {
+		if memoryKey(m.GroupID) == groupId.String() {
+			userIDs = append(userIDs, m.UserID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (d *memoryDatabase) DeleteEffectiveMemberships(_ context.Context, filter *entities.EffectiveMembership) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, m := range d.effectiveMemberships {
+		if (filter.UserID == "" || sameID(filter.UserID, m.UserID)) &&
+			(filter.GroupID == "" || sameID(filter.GroupID, m.GroupID)) &&
+			(filter.OrganizationID == "" || inOrganization(filter.OrganizationID, m.OrganizationID)) {
+			delete(d.effectiveMemberships, k)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) CreateOrganization(_ context.Context, model *entities.Organization) (*entities.Organization, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.organizations[memoryKey(model.ID)]; ok {
+		return &entities.Organization{}, duplicateDocument()
+	}
+	d.organizations[memoryKey(model.ID)] = memoryCopy(model)
+	return model, nil
+}
+
+func (d *memoryDatabase) UpdateOrganization(_ context.Context, model *entities.Organization) (*entities.Organization, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.organizations[memoryKey(model.ID)]
+	if !ok {
+		return &entities.Organization{}, noDocuments()
+	}
+	if model.Name != "" {
+		updated.Name = model.Name
+	}
+	if model.Description != "" {
+		updated.Description = model.Description
+	}
+	updated.UpdatedAt = model.UpdatedAt
+	return memoryCopy(updated), nil
+}
+
+func (d *memoryDatabase) UpdateOrganizationStatus(_ context.Context, model *entities.Organization) (*entities.Organization, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.organizations[memoryKey(model.ID)]
+	if !ok {
+		return &entities.Organization{}, noDocuments()
+	}
+	updated.Active = model.Active
+	if !model.SessionsRevokedAt.IsZero() {
+		updated.SessionsRevokedAt = model.SessionsRevokedAt
+	}
+	updated.UpdatedAt = model.UpdatedAt
+	return memoryCopy(updated), nil
+}
+
+func (d *memoryDatabase) GetOrganizationById(_ context.Context, id uuid.UUID) (*entities.Organization, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.organizations[id.String()]
+	if !ok {
+		return &entities.Organization{}, noDocuments()
+	}
+	return memoryCopy(found), nil
+}
+
+func (d *memoryDatabase) DeleteOrganization(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.organizations[id.String()]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(d.organizations, id.String())
+	return nil
+}
+
+func (d *memoryDatabase) SearchOrganizations(_ context.Context, search string, pagination *utilities.Pagination) (*entities.OrganizationsList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	matches := make([]*entities.Organization, 0)
+	for _, o := range d.organizations {
+		if containsFold(o.Name, search) || containsFold(o.Description, search) {
+			matches = append(matches, o)
+		}
+	}
+	if len(matches) == 0 {
+		return &entities.OrganizationsList{Organizations: make([]*entities.Organization, 0)}, nil
+	}
+	organizations := memoryPage(matches, pagination, func(a, b *entities.Organization) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return entities.NewOrganizationListWithPagination(organizations, int64(len(matches)), pagination), nil
+}
+
+func (d *memoryDatabase) CreateRole(_ context.Context, model *entities.Role) (*entities.Role, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	role := memoryCopy(model)
+	role.ID = memoryKey(model.ID)
+	if model.GroupID != "" {
+		role.GroupID = memoryKey(model.GroupID)
+	}
+	role.OrganizationID = organizationKey(model.OrganizationID)
+	d.roles[role.ID] = role
+	return memoryCopy(role), nil
+}
+
+func (d *memoryDatabase) UpdateRole(_ context.Context, model *entities.Role) (*entities.Role, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated, ok := d.roles[memoryKey(model.ID)]
+	if !ok {
+		return &entities.Role{}, noDocuments()
+	}
+	if model.Name != "" {
+		updated.Name = model.Name
+	}
+	if model.Description != "" {
+		updated.Description = model.Description
+	}
+	updated.Permissions = model.Permissions
+	updated.UpdatedAt = model.UpdatedAt
+	return memoryCopy(updated), nil
+}
+
+func (d *memoryDatabase) GetRoleById(_ context.Context, id uuid.UUID) (*entities.Role, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.roles[id.String()]
+	if !ok {
+		return &entities.Role{}, noDocuments()
+	}
+	return memoryCopy(found), nil
+}
+
+func (d *memoryDatabase) DeleteRole(_ context.Context, id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.roles[id.String()]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(d.roles, id.String())
+	return nil
+}
+
+func (d *memoryDatabase) DeleteRoles(_ context.Context, filter *entities.Role) error {
+	if filter.GroupID == "" && filter.OrganizationID == "" {
+		return errors.New("empty role filter")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, r := range d.roles {
+		if r.BuiltIn || (filter.GroupID != "" && !sameID(filter.GroupID, r.GroupID)) {
+			continue
+		}
+		if filter.OrganizationID == "" || inOrganization(filter.OrganizationID, r.OrganizationID) {
+			delete(d.roles, k)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDatabase) SearchRoles(_ context.Context, search string, groupID string, tenantID string, pagination *utilities.Pagination) (*entities.RolesList, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	matches := make([]*entities.Role, 0)
+	for _, r := range d.roles {
+		if !containsFold(r.Name, search) && !containsFold(r.Description, search) {
+			continue
+		}
+		if groupID != "" && r.GroupID != "" && !sameID(groupID, r.GroupID) {
+			continue
+		}
+		if r.BuiltIn || authentication.InTenant(tenantID, r.OrganizationID) {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) == 0 {
+		return &entities.RolesList{Roles: make([]*entities.Role, 0)}, nil
+	}
+	roles := memoryPage(matches, pagination, func(a, b *entities.Role) bool {
+		if a.BuiltIn != b.BuiltIn {
+			return a.BuiltIn
+		}
+		return a.Name < b.Name
+	})
+	return entities.NewRoleListWithPagination(roles, int64(len(matches)), pagination), nil
+}
+
+func (d *memoryDatabase) BlacklistToken(_ context.Context, bList *entities.Blacklist) (*entities.Blacklist, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// the token may already be blacklisted through another path, e.g. the synchronous revoke RPC
+	if _, ok := d.blacklist[bList.AccessToken]; !ok {
+		d.blacklist[bList.AccessToken] = memoryCopy(bList)
+	}
+	return bList, nil
+}
+
+func (d *memoryDatabase) CheckTokenBlacklist(_ context.Context, accessToken string) (*entities.Blacklist, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	found, ok := d.blacklist[accessToken]
+	if !ok {
+		return &entities.Blacklist{}, noDocuments()
+	}
+	return memoryCopy(found), nil
+}
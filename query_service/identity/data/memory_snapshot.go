@@ -0,0 +1,103 @@
+package data
+
+import (
+	"encoding/json"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+)
+
+// memorySnapshot is the on-disk form of a memoryDatabase
+type memorySnapshot struct {
+	Users                map[string]*entities.User                `json:"users"`
+	Groups               map[string]*entities.Group               `json:"groups"`
+	Memberships          map[string]*entities.Membership          `json:"memberships"`
+	GroupMemberships     map[string]*entities.GroupMembership     `json:"groupMemberships"`
+	UserMemberships      map[string]*entities.UserMembership      `json:"userMemberships"`
+	Blacklist            map[string]*entities.Blacklist           `json:"blacklist"`
+	Organizations        map[string]*entities.Organization        `json:"organizations"`
+	EffectiveMemberships map[string]*entities.EffectiveMembership `json:"effectiveMemberships"`
+	Roles                map[string]*entities.Role                `json:"roles"`
+}
+
+// Save writes the documents to path, replacing the previous snapshot atomically
+func (d *memoryDatabase) Save(path string) error {
+	d.mu.RLock()
+	b, err := json.Marshal(&memorySnapshot{
+		Users:                d.users,
+		Groups:               d.groups,
+		Memberships:          d.memberships,
+		GroupMemberships:     d.groupMemberships,
+		UserMemberships:      d.userMemberships,
+		Blacklist:            d.blacklist,
+		Organizations:        d.organizations,
+		EffectiveMemberships: d.effectiveMemberships,
+		Roles:                d.roles,
+	})
+	d.mu.RUnlock()
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return errors.Wrap(err, "os.CreateTemp")
+	}
+	defer os.Remove(tmp.Name()) // nolint: errCheck
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close() // nolint: errCheck
+		return errors.Wrap(err, "Write")
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "Close")
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "os.Rename")
+	}
+	return nil
+}
+
+// Load replaces the documents with the snapshot at path; a missing snapshot leaves the database empty
+func (d *memoryDatabase) Load(path string) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "os.ReadFile")
+	}
+	var snapshot memorySnapshot
+	if err = json.Unmarshal(b, &snapshot); err != nil {
+		return errors.Wrap(err, "json.Unmarshal")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if snapshot.Users != nil {
+		d.users = snapshot.Users
+	}
+	if snapshot.Groups != nil {
+		d.groups = snapshot.Groups
+	}
+	if snapshot.Memberships != nil {
+		d.memberships = snapshot.Memberships
+	}
+	if snapshot.GroupMemberships != nil {
+		d.groupMemberships = snapshot.GroupMemberships
+	}
+	if snapshot.UserMemberships != nil {
+		d.userMemberships = snapshot.UserMemberships
+	}
+	if snapshot.Blacklist != nil {
+		d.blacklist = snapshot.Blacklist
+	}
+	if snapshot.Organizations != nil {
+		d.organizations = snapshot.Organizations
+	}
+	if snapshot.EffectiveMemberships != nil {
+		d.effectiveMemberships = snapshot.EffectiveMemberships
+	}
+	if snapshot.Roles != nil {
+		d.roles = snapshot.Roles
+	}
+	return nil
+}
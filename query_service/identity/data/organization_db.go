@@ -0,0 +1,239 @@
+package data
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// organizationKey normalizes an organization id into the form stored in the organization_id fields
+func organizationKey(organizationID string) string {
+	return uuid.FromStringOrNil(authentication.OrganizationOrDefault(organizationID)).String()
+}
+
+// withTenant scopes filter to the documents of tenantID; the documents projected before organizations were
+// introduced carry no organization_id and belong to the default tenant. The empty tenant of root sessions is unscoped.
+func withTenant(filter bson.D, tenantID string) bson.D {
+	if tenantID == "" {
+		return filter
+	}
+	key := organizationKey(tenantID)
+	if key == authentication.NoOrganization {
+		return append(filter, bson.E{Key: "organization_id", Value: bson.M{"$in": bson.A{key, nil}}})
+	}
+	return append(filter, bson.E{Key: "organization_id", Value: key})
+}
+
+// organizationEntity structures an organization BSON document to save in an organizations collection
+type organizationEntity struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	Name              string             `bson:"name,omitempty" validate:"required,min=3,max=250"`
+	Description       string             `bson:"description,omitempty"`
+	Active            bool               `bson:"active"`
+	SessionsRevokedAt time.Time          `bson:"sessions_revoked_at,omitempty"`
+	CreatedAt         time.Time          `bson:"created_at,omitempty"`
+	UpdatedAt         time.Time          `bson:"updated_at,omitempty"`
+}
+
+// newOrganizationEntity initializes a new pointer to an organizationEntity struct from an *entities.Organization
+func newOrganizationEntity(u *entities.Organization) (um *organizationEntity, err error) {
+	um = &organizationEntity{
+		Name:              u.Name,
+		Description:       u.Description,
+		Active:            u.Active,
+		SessionsRevokedAt: u.SessionsRevokedAt,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
+	}
+	if utilities.CheckID(u.ID) == nil {
+		um.ID, err = utilities.LoadObjectIDString(u.ID)
+	}
+	return
+}
+
+// toRoot creates and returns a new pointer to an entities.Organization from a pointer to a BSON organizationEntity
+func (u *organizationEntity) toRoot() *entities.Organization {
+	um := &entities.Organization{
+		Name:              u.Name,
+		Description:       u.Description,
+		Active:            u.Active,
+		SessionsRevokedAt: u.SessionsRevokedAt,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
+	}
+	if utilities.CheckID(u.ID.Hex()) == nil {
+		um.ID = utilities.LoadUUIDString(u.ID)
+	}
+	return um
+}
+
+type organizationRepository struct {
+	log logging.Logger
+	cfg *config.Config
+	db  *mongo.Client
+}
+
+func NewOrganizationRepository(log logging.Logger, cfg *config.Config, db *mongo.Client) *organizationRepository {
+	return &organizationRepository{
+		log: log,
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (p *organizationRepository) Create(ctx context.Context, model *entities.Organization) (*entities.Organization, error) {
+	ctx, span := tracing.StartSpan(ctx, "organizationRepository.CreateOrganization")
+	defer span.End()
+	ent, err := newOrganizationEntity(model)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "newOrganizationEntity")
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Organizations)
+	if _, err = collection.InsertOne(ctx, ent, &options.InsertOneOptions{}); err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "InsertOne")
+	}
+	return model, nil
+}
+
+// Update sets the name and description of an organization, leaving its status untouched
+func (p *organizationRepository) Update(ctx context.Context, model *entities.Organization) (*entities.Organization, error) {
+	ctx, span := tracing.StartSpan(ctx, "organizationRepository.UpdateOrganization")
+	defer span.End()
+	oId, err := utilities.LoadObjectIDString(model.ID)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "LoadObjectIDString")
+	}
+	set := bson.M{"updated_at": model.UpdatedAt}
+	if model.Name != "" {
+		set["name"] = model.Name
+	}
+	if model.Description != "" {
+		set["description"] = model.Description
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Organizations)
+	ops := options.FindOneAndUpdate()
+	ops.SetReturnDocument(options.After)
+	var ent organizationEntity
+	if err = collection.FindOneAndUpdate(ctx, bson.M{"_id": oId}, bson.M{"$set": set}, ops).Decode(&ent); err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "Decode")
+	}
+	return ent.toRoot(), nil
+}
+
+// UpdateStatus sets whether an organization is active, keeping the latest session revocation
+func (p *organizationRepository) UpdateStatus(ctx context.Context, model *entities.Organization) (*entities.Organization, error) {
+	ctx, span := tracing.StartSpan(ctx, "organizationRepository.UpdateStatus")
+	defer span.End()
+	oId, err := utilities.LoadObjectIDString(model.ID)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "LoadObjectIDString")
+	}
+	set := bson.M{"active": model.Active, "updated_at": model.UpdatedAt}
+	if !model.SessionsRevokedAt.IsZero() {
+		set["sessions_revoked_at"] = model.SessionsRevokedAt
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Organizations)
+	ops := options.FindOneAndUpdate()
+	ops.SetReturnDocument(options.After)
+	var ent organizationEntity
+	if err = collection.FindOneAndUpdate(ctx, bson.M{"_id": oId}, bson.M{"$set": set}, ops).Decode(&ent); err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "Decode")
+	}
+	return ent.toRoot(), nil
+}
+
+func (p *organizationRepository) GetById(ctx context.Context, id uuid.UUID) (*entities.Organization, error) {
+	ctx, span := tracing.StartSpan(ctx, "organizationRepository.GetOrganizationById")
+	defer span.End()
+	oId, err := utilities.LoadObjectID(id)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "LoadObjectIDString")
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Organizations)
+	var ent organizationEntity
+	if err = collection.FindOne(ctx, bson.M{"_id": oId}).Decode(&ent); err != nil {
+		p.traceErr(span, err)
+		return &entities.Organization{}, errors.Wrap(err, "Decode")
+	}
+	return ent.toRoot(), nil
+}
+
+func (p *organizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartSpan(ctx, "organizationRepository.DeleteOrganization")
+	defer span.End()
+	oId, err := utilities.LoadObjectID(id)
+	if err != nil {
+		p.traceErr(span, err)
+		return errors.Wrap(err, "LoadObjectIDString")
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Organizations)
+	return collection.FindOneAndDelete(ctx, bson.M{"_id": oId}).Err()
+}
+
+func (p *organizationRepository) Search(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.OrganizationsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "organizationRepository.Search")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Organizations)
+	filter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "name", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
+			bson.D{{Key: "description", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
+		}},
+	}
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.OrganizationsList{}, errors.Wrap(err, "CountDocuments")
+	}
+	if count == 0 {
+		return &entities.OrganizationsList{Organizations: make([]*entities.Organization, 0)}, nil
+	}
+	limit := int64(pagination.GetLimit())
+	skip := int64(pagination.GetOffset())
+	cursor, err := collection.Find(ctx, filter, &options.FindOptions{
+		Limit: &limit,
+		Skip:  &skip,
+	})
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.OrganizationsList{}, errors.Wrap(err, "Find")
+	}
+	defer cursor.Close(ctx) // nolint: errCheck
+	organizations := make([]*entities.Organization, 0, pagination.GetSize())
+	for cursor.Next(ctx) {
+		var u organizationEntity
+		if err = cursor.Decode(&u); err != nil {
+			p.traceErr(span, err)
+			return &entities.OrganizationsList{}, errors.Wrap(err, "Find")
+		}
+		organizations = append(organizations, u.toRoot())
+	}
+	if err = cursor.Err(); err != nil {
+		tracing.TraceErr(span, err)
+		return &entities.OrganizationsList{}, errors.Wrap(err, "cursor.Err")
+	}
+	return entities.NewOrganizationListWithPagination(organizations, count, pagination), nil
+}
+
+func (p *organizationRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
+}
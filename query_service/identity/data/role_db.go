@@ -0,0 +1,187 @@
+package data
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// roleFilter generates a bson filter for MongoDB queries from the Role data
+func roleFilter(u *entities.Role) (doc bson.D) {
+	if u.GroupID != "" {
+		doc = append(doc, bson.E{Key: "group_id", Value: memoryKey(u.GroupID)})
+	}
+	if u.OrganizationID != "" {
+		doc = append(doc, bson.E{Key: "organization_id", Value: organizationKey(u.OrganizationID)})
+	}
+	return
+}
+
+// roleSearchFilter matches the roles named or described like search that are assignable in the group with groupID,
+// or in any group when groupID is empty. Built-in roles are visible to every tenant
+func roleSearchFilter(search string, groupID string, tenantID string) bson.D {
+	filter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "name", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
+			bson.D{{Key: "description", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
+		}},
+	}
+	if groupID != "" {
+		filter = append(filter, bson.E{Key: "group_id", Value: bson.M{"$in": bson.A{memoryKey(groupID), nil}}})
+	}
+	if tenantID == "" {
+		return filter
+	}
+	return append(filter, bson.E{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "built_in", Value: true}},
+			withTenant(bson.D{}, tenantID),
+		}}},
+	}})
+}
+
+type roleRepository struct {
+	log logging.Logger
+	cfg *config.Config
+	db  *mongo.Client
+}
+
+func NewRoleRepository(log logging.Logger, cfg *config.Config, db *mongo.Client) *roleRepository {
+	return &roleRepository{
+		log: log,
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+// Create stores a role, replacing a role projected before with the same id so the built-in roles can be seeded on
+// every start
+func (p *roleRepository) Create(ctx context.Context, model *entities.Role) (*entities.Role, error) {
+	ctx, span := tracing.StartSpan(ctx, "roleRepository.CreateRole")
+	defer span.End()
+	doc := *model
+	doc.ID = memoryKey(model.ID)
+	if model.GroupID != "" {
+		doc.GroupID = memoryKey(model.GroupID)
+	}
+	doc.OrganizationID = organizationKey(model.OrganizationID)
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Roles)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"_id": doc.ID}, &doc, options.Replace().SetUpsert(true)); err != nil {
+		p.traceErr(span, err)
+		return &entities.Role{}, errors.Wrap(err, "ReplaceOne")
+	}
+	return &doc, nil
+}
+
+// Update sets the name, description and permissions of a role
+func (p *roleRepository) Update(ctx context.Context, model *entities.Role) (*entities.Role, error) {
+	ctx, span := tracing.StartSpan(ctx, "roleRepository.UpdateRole")
+	defer span.End()
+	set := bson.M{"permissions": model.Permissions, "updated_at": model.UpdatedAt}
+	if model.Name != "" {
+		set["name"] = model.Name
+	}
+	if model.Description != "" {
+		set["description"] = model.Description
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Roles)
+	ops := options.FindOneAndUpdate()
+	ops.SetReturnDocument(options.After)
+	var updated entities.Role
+	if err := collection.FindOneAndUpdate(ctx, bson.M{"_id": memoryKey(model.ID)}, bson.M{"$set": set}, ops).Decode(&updated); err != nil {
+		p.traceErr(span, err)
+		return &entities.Role{}, errors.Wrap(err, "Decode")
+	}
+	return &updated, nil
+}
+
+func (p *roleRepository) GetById(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	ctx, span := tracing.StartSpan(ctx, "roleRepository.GetRoleById")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Roles)
+	var role entities.Role
+	if err := collection.FindOne(ctx, bson.M{"_id": id.String()}).Decode(&role); err != nil {
+		p.traceErr(span, err)
+		return &entities.Role{}, errors.Wrap(err, "Decode")
+	}
+	return &role, nil
+}
+
+func (p *roleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.StartSpan(ctx, "roleRepository.DeleteRole")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Roles)
+	return collection.FindOneAndDelete(ctx, bson.M{"_id": id.String()}).Err()
+}
+
+// DeleteMany deletes the custom roles of a group or an organization
+func (p *roleRepository) DeleteMany(ctx context.Context, filter *entities.Role) error {
+	ctx, span := tracing.StartSpan(ctx, "roleRepository.DeleteRoles")
+	defer span.End()
+	bsonFilter := roleFilter(filter)
+	if len(bsonFilter) == 0 {
+		return errors.New("empty role filter")
+	}
+	bsonFilter = append(bsonFilter, bson.E{Key: "built_in", Value: bson.M{"$ne": true}})
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Roles)
+	if _, err := collection.DeleteMany(ctx, bsonFilter); err != nil {
+		p.traceErr(span, err)
+		return errors.Wrap(err, "DeleteMany")
+	}
+	return nil
+}
+
+func (p *roleRepository) Search(ctx context.Context, search string, groupID string, tenantID string, pagination *utilities.Pagination) (*entities.RolesList, error) {
+	ctx, span := tracing.StartSpan(ctx, "roleRepository.Search")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Roles)
+	filter := roleSearchFilter(search, groupID, tenantID)
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.RolesList{}, errors.Wrap(err, "CountDocuments")
+	}
+	if count == 0 {
+		return &entities.RolesList{Roles: make([]*entities.Role, 0)}, nil
+	}
+	limit := int64(pagination.GetLimit())
+	skip := int64(pagination.GetOffset())
+	cursor, err := collection.Find(ctx, filter, &options.FindOptions{
+		Limit: &limit,
+		Skip:  &skip,
+		Sort:  bson.D{{Key: "built_in", Value: -1}, {Key: "name", Value: 1}},
+	})
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.RolesList{}, errors.Wrap(err, "Find")
+	}
+	defer cursor.Close(ctx) // nolint: errCheck
+	roles := make([]*entities.Role, 0, pagination.GetSize())
+	for cursor.Next(ctx) {
+		var u entities.Role
+		if err = cursor.Decode(&u); err != nil {
+			p.traceErr(span, err)
+			return &entities.RolesList{}, errors.Wrap(err, "Find")
+		}
+		roles = append(roles, &u)
+	}
+	if err = cursor.Err(); err != nil {
+		p.traceErr(span, err)
+		return &entities.RolesList{}, errors.Wrap(err, "cursor.Err")
+	}
+	return entities.NewRoleListWithPagination(roles, count, pagination), nil
+}
+
+func (p *roleRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
+}
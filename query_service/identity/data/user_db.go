@@ -2,30 +2,37 @@ package data
 
 import (
 	"context"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
 	"github.com/gofrs/uuid"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
 // userEntity structures a user BSON document to save in a users collection
 type userEntity struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Username  string             `bson:"username,omitempty" validate:"required,min=3,max=500"`
-	Password  string             `bson:"password,omitempty" validate:"required"`
-	Email     string             `bson:"email,omitempty" validate:"required,min=3,max=250"`
-	Root      bool               `bson:"root,omitempty"`
-	Active    bool               `bson:"active,omitempty"`
-	CreatedAt time.Time          `bson:"created_at,omitempty"`
-	UpdatedAt time.Time          `bson:"updated_at,omitempty"`
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	Username          string             `bson:"username,omitempty" validate:"required,min=3,max=500"`
+	Email             string             `bson:"email,omitempty" validate:"required,min=3,max=250"`
+	Root              bool               `bson:"root,omitempty"`
+	Active            bool               `bson:"active,omitempty"`
+	Status            int                `bson:"status,omitempty"`
+	StatusReason      string             `bson:"status_reason,omitempty"`
+	SuspendedUntil    time.Time          `bson:"suspended_until,omitempty"`
+	SessionsRevokedAt time.Time          `bson:"sessions_revoked_at,omitempty"`
+	OrganizationID    string             `bson:"organization_id,omitempty"`
+	OrgAdmin          bool               `bson:"org_admin,omitempty"`
+	CreatedAt         time.Time          `bson:"created_at,omitempty"`
+	UpdatedAt         time.Time          `bson:"updated_at,omitempty"`
 }
 
 // getID returns the unique identifier of the userEntity
@@ -36,13 +43,18 @@ func (u *userEntity) getID() primitive.ObjectID {
 // newUserEntity initializes a new pointer to a userEntity struct from a pointer to a JSON models.User struct
 func newUserEntity(u *entities.User) (um *userEntity, err error) {
 	um = &userEntity{
-		Username:  u.Username,
-		Password:  u.Password,
-		Email:     u.Email,
-		Root:      u.Root,
-		Active:    u.Active,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		Username:          u.Username,
+		Email:             u.Email,
+		Root:              u.Root,
+		Active:            u.Active,
+		Status:            u.Status.EnumIndex(),
+		StatusReason:      u.StatusReason,
+		SuspendedUntil:    u.SuspendedUntil,
+		SessionsRevokedAt: u.SessionsRevokedAt,
+		OrganizationID:    u.OrganizationID,
+		OrgAdmin:          u.OrgAdmin,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
 	}
 	if utilities.CheckID(u.ID) == nil {
 		um.ID, err = utilities.LoadObjectIDString(u.ID)
@@ -53,13 +65,18 @@ func newUserEntity(u *entities.User) (um *userEntity, err error) {
 // toRoot creates and return a new pointer to a models.User JSON struct from a pointer to a BSON userEntity
 func (u *userEntity) toRoot() *entities.User {
 	um := &entities.User{
-		Email:     u.Email,
-		Username:  u.Username,
-		Password:  u.Password,
-		Root:      u.Root,
-		Active:    u.Active,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		Email:             u.Email,
+		Username:          u.Username,
+		Root:              u.Root,
+		Active:            u.Active,
+		Status:            enums.UserStatus(u.Status),
+		StatusReason:      u.StatusReason,
+		SuspendedUntil:    u.SuspendedUntil,
+		SessionsRevokedAt: u.SessionsRevokedAt,
+		OrganizationID:    u.OrganizationID,
+		OrgAdmin:          u.OrgAdmin,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
 	}
 	if utilities.CheckID(u.ID.Hex()) == nil {
 		um.ID = utilities.LoadUUIDString(u.ID)
@@ -89,8 +106,8 @@ func NewUserRepository(log logging.Logger, cfg *config.Config, db *mongo.Client)
 }
 
 func (p *userRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.CreateUser")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userRepository.CreateUser")
+	defer span.End()
 	p.log.Info(user.ID)
 	//tID := utilities.
 	ent, err := newUserEntity(user)
@@ -108,8 +125,8 @@ func (p *userRepository) Create(ctx context.Context, user *entities.User) (*enti
 }
 
 func (p *userRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.UpdateUser")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userRepository.UpdateUser")
+	defer span.End()
 	ent, err := newUserEntity(user)
 	if err != nil {
 		p.traceErr(span, err)
@@ -127,9 +144,49 @@ func (p *userRepository) Update(ctx context.Context, user *entities.User) (*enti
 	return &updated, nil
 }
 
+// UpdateStatus replaces the lifecycle fields of a user, clearing the reason and suspension end date it no longer has
+// and keeping the latest session revocation
+func (p *userRepository) UpdateStatus(ctx context.Context, user *entities.User) (*entities.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "userRepository.UpdateStatus")
+	defer span.End()
+	oId, err := utilities.LoadObjectIDString(user.ID)
+	if err != nil {
+		p.traceErr(span, err)
+		return &entities.User{}, errors.Wrap(err, "LoadObjectIDString")
+	}
+	set := bson.M{"status": user.Status.EnumIndex(), "active": user.Active, "updated_at": user.UpdatedAt}
+	unset := bson.M{}
+	if user.StatusReason != "" {
+		set["status_reason"] = user.StatusReason
+	} else {
+		unset["status_reason"] = ""
+	}
+	if !user.SuspendedUntil.IsZero() {
+		set["suspended_until"] = user.SuspendedUntil
+	} else {
+		unset["suspended_until"] = ""
+	}
+	if !user.SessionsRevokedAt.IsZero() {
+		set["sessions_revoked_at"] = user.SessionsRevokedAt
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Users)
+	ops := options.FindOneAndUpdate()
+	ops.SetReturnDocument(options.After)
+	var ent userEntity
+	if err = collection.FindOneAndUpdate(ctx, bson.M{"_id": oId}, update, ops).Decode(&ent); err != nil {
+		p.traceErr(span, err)
+		return &entities.User{}, errors.Wrap(err, "Decode")
+	}
+	return ent.toRoot(), nil
+}
+
 func (p *userRepository) GetById(ctx context.Context, id uuid.UUID) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.GetUserById")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userRepository.GetUserById")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Users)
 	var ent userEntity
 	oId, err := utilities.LoadObjectID(id)
@@ -145,8 +202,8 @@ func (p *userRepository) GetById(ctx context.Context, id uuid.UUID) (*entities.U
 }
 
 func (p *userRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.GetByEmail")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userRepository.GetByEmail")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Users)
 	var ent userEntity
 	if err := collection.FindOne(ctx, bson.M{"email": email}).Decode(&ent); err != nil {
@@ -157,8 +214,8 @@ func (p *userRepository) GetByEmail(ctx context.Context, email string) (*entitie
 }
 
 func (p *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.DeleteUser")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userRepository.DeleteUser")
+	defer span.End()
 	oId, err := utilities.LoadObjectID(id)
 	if err != nil {
 		p.traceErr(span, err)
@@ -168,16 +225,39 @@ func (p *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return collection.FindOneAndDelete(ctx, bson.M{"_id": oId}).Err()
 }
 
-func (p *userRepository) Search(ctx context.Context, search string, pagination *utilities.Pagination) (*entities.UsersList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.Search")
-	defer span.Finish()
+// DeleteByOrganization deletes the users of an organization, returning the deleted users
+func (p *userRepository) DeleteByOrganization(ctx context.Context, organizationID string) ([]*entities.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "userRepository.DeleteByOrganization")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Users)
+	filter := bson.M{"organization_id": organizationKey(organizationID)}
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Find")
+	}
+	var ents []*userEntity
+	if err = cursor.All(ctx, &ents); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+	if _, err = collection.DeleteMany(ctx, filter); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "DeleteMany")
+	}
+	return loadUserEntities(ents), nil
+}
+
+func (p *userRepository) Search(ctx context.Context, search string, tenantID string, pagination *utilities.Pagination) (*entities.UsersList, error) {
+	ctx, span := tracing.StartSpan(ctx, "userRepository.Search")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Users)
-	filter := bson.D{
+	filter := withTenant(bson.D{
 		{Key: "$or", Value: bson.A{
 			bson.D{{Key: "name", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
 			bson.D{{Key: "description", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
 		}},
-	}
+	}, tenantID)
 	count, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -207,29 +287,25 @@ func (p *userRepository) Search(ctx context.Context, search string, pagination *
 		users = append(users, u.toRoot())
 	}
 	if err = cursor.Err(); err != nil {
-		span.SetTag("error", true)
-		span.LogKV("error_code", err.Error())
+		tracing.TraceErr(span, err)
 		return &entities.UsersList{}, errors.Wrap(err, "cursor.Err")
 	}
 	return entities.NewUserListWithPagination(users, count, pagination), nil
 }
 
-func (p *userRepository) Authenticate(ctx context.Context, email string, password string) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userRepository.Authenticate")
-	defer span.Finish()
-	user, err := p.GetByEmail(ctx, email)
+// StripPasswordHashes unsets the password hashes of users projected while they were replicated into the read model
+func (p *userRepository) StripPasswordHashes(ctx context.Context) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "userRepository.StripPasswordHashes")
+	defer span.End()
+	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.Users)
+	res, err := collection.UpdateMany(ctx, bson.M{"password": bson.M{"$exists": true}}, bson.M{"$unset": bson.M{"password": ""}})
 	if err != nil {
 		p.traceErr(span, err)
-		return &entities.User{}, errors.Wrap(err, "userRepository.GetByEmail")
-	}
-	if err = user.Authenticate(password); err != nil {
-		p.traceErr(span, err)
-		return &entities.User{}, errors.Wrap(err, "user.Authenticate")
+		return 0, errors.Wrap(err, "UpdateMany")
 	}
-	return user, nil
+	return res.ModifiedCount, nil
 }
 
-func (p *userRepository) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+func (p *userRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 }
@@ -4,31 +4,38 @@ import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
 	"github.com/gofrs/uuid"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
 // userMembershipEntity structures a user BSON document to save in a userMemberships aggregate collection
 type userMembershipEntity struct {
-	ID           primitive.ObjectID     `bson:"_id,omitempty"`
-	GroupID      primitive.ObjectID     `bson:"group_id,omitempty"`
-	UserID       primitive.ObjectID     `bson:"user_id,omitempty"`
-	MembershipID primitive.ObjectID     `bson:"membership_id,omitempty"`
-	Email        string                 `bson:"email,omitempty" validate:"required,min=3,max=250"`
-	Username     string                 `bson:"username,omitempty" validate:"required,min=3,max=250"`
-	Status       enums.MembershipStatus `bson:"status,omitempty"`
-	Role         enums.Role             `bson:"role,omitempty"`
-	CreatedAt    time.Time              `bson:"created_at,omitempty"`
-	UpdatedAt    time.Time              `bson:"updated_at,omitempty"`
+	ID             primitive.ObjectID     `bson:"_id,omitempty"`
+	GroupID        primitive.ObjectID     `bson:"group_id,omitempty"`
+	UserID         primitive.ObjectID     `bson:"user_id,omitempty"`
+	MembershipID   primitive.ObjectID     `bson:"membership_id,omitempty"`
+	Email          string                 `bson:"email,omitempty" validate:"required,min=3,max=250"`
+	Username       string                 `bson:"username,omitempty" validate:"required,min=3,max=250"`
+	Status         enums.MembershipStatus `bson:"status,omitempty"`
+	Role           enums.Role             `bson:"role,omitempty"`
+	RoleID         string                 `bson:"role_id,omitempty"`
+	RoleName       string                 `bson:"role_name,omitempty"`
+	Permissions    []string               `bson:"permissions,omitempty"`
+	OrganizationID string                 `bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `bson:"updated_at,omitempty"`
 }
 
 // getID returns the unique identifier of the userMembershipEntity
@@ -46,6 +53,10 @@ func (u *userMembershipEntity) bsonFilter() (doc bson.D, err error) {
 		doc = bson.D{{"user_id", u.UserID}}
 	} else if utilities.CheckObjectID(u.MembershipID) == nil {
 		doc = bson.D{{"membership_id", u.MembershipID}}
+	} else if u.RoleID != "" {
+		doc = bson.D{{Key: "role_id", Value: u.RoleID}}
+	} else if u.OrganizationID != "" {
+		doc = bson.D{{Key: "organization_id", Value: u.OrganizationID}}
 	}
 	return
 }
@@ -53,12 +64,17 @@ func (u *userMembershipEntity) bsonFilter() (doc bson.D, err error) {
 // newUserMembershipEntity initializes a new pointer to an userMembershipEntity struct from a models.UserMembership
 func newUserMembershipEntity(u *entities.UserMembership) (um *userMembershipEntity, err error) {
 	um = &userMembershipEntity{
-		Email:     u.Email,
-		Username:  u.Username,
-		Status:    u.Status,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		Email:          u.Email,
+		Username:       u.Username,
+		Status:         u.Status,
+		Role:           u.Role,
+		RoleName:       u.RoleName,
+		Permissions:    u.Permissions,
+		OrganizationID: u.OrganizationID,
+		ValidFrom:      u.ValidFrom,
+		ValidUntil:     u.ValidUntil,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.GroupID) == nil {
 		um.GroupID, err = utilities.LoadObjectIDString(u.GroupID)
@@ -72,18 +88,27 @@ func newUserMembershipEntity(u *entities.UserMembership) (um *userMembershipEnti
 	if utilities.CheckID(u.ID) == nil {
 		um.ID, err = utilities.LoadObjectIDString(u.ID)
 	}
+	if u.RoleID != "" {
+		um.RoleID = memoryKey(u.RoleID)
+	}
 	return
 }
 
 // toRoot creates and returns a new pointer to a models.UserMembership JSON struct from a userMembershipEntity
 func (u *userMembershipEntity) toRoot() *entities.UserMembership {
 	um := &entities.UserMembership{
-		Email:     u.Email,
-		Username:  u.Username,
-		Status:    u.Status,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		Email:          u.Email,
+		Username:       u.Username,
+		Status:         u.Status,
+		Role:           u.Role,
+		RoleID:         u.RoleID,
+		RoleName:       u.RoleName,
+		Permissions:    u.Permissions,
+		OrganizationID: u.OrganizationID,
+		ValidFrom:      u.ValidFrom,
+		ValidUntil:     u.ValidUntil,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 	if utilities.CheckID(u.GroupID.Hex()) == nil {
 		um.UserID = utilities.LoadUUIDString(u.GroupID)
@@ -115,8 +140,8 @@ func NewUserMembershipRepository(log logging.Logger, cfg *config.Config, db *mon
 }
 
 func (p *userMembershipRepository) Create(ctx context.Context, model *entities.UserMembership) (*entities.UserMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.CreateUserMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.CreateUserMembership")
+	defer span.End()
 	p.log.Info(model.ID)
 	ent, err := newUserMembershipEntity(model)
 	if err != nil {
@@ -133,8 +158,8 @@ func (p *userMembershipRepository) Create(ctx context.Context, model *entities.U
 }
 
 func (p *userMembershipRepository) UpdateMany(ctx context.Context, filter *entities.UserMembership, update *entities.UserMembership) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.UpdateUserMemberships")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.UpdateUserMemberships")
+	defer span.End()
 	up, err := newUserMembershipEntity(update)
 	if err != nil {
 		p.traceErr(span, err)
@@ -160,8 +185,8 @@ func (p *userMembershipRepository) UpdateMany(ctx context.Context, filter *entit
 }
 
 func (p *userMembershipRepository) Update(ctx context.Context, model *entities.UserMembership) (*entities.UserMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.UpdateUserMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.UpdateUserMembership")
+	defer span.End()
 	ent, err := newUserMembershipEntity(model)
 	if err != nil {
 		p.traceErr(span, err)
@@ -180,8 +205,8 @@ func (p *userMembershipRepository) Update(ctx context.Context, model *entities.U
 }
 
 func (p *userMembershipRepository) GetById(ctx context.Context, id uuid.UUID, idType enums.ReadTableIdType) (*entities.UserMembership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.GetUserMembershipById")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.GetUserMembershipById")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.UserMemberships)
 	var ent userMembershipEntity
 	oId, err := utilities.LoadObjectID(id)
@@ -196,11 +221,11 @@ func (p *userMembershipRepository) GetById(ctx context.Context, id uuid.UUID, id
 	return ent.toRoot(), nil
 }
 
-func (p *userMembershipRepository) GetByUserId(ctx context.Context, userId uuid.UUID, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.GetByUserId")
-	defer span.Finish()
+func (p *userMembershipRepository) GetByUserId(ctx context.Context, userId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.GetByUserId")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.UserMemberships)
-	filter := bson.M{"user_id": userId}
+	filter := withTenant(bson.D{{Key: "user_id", Value: userId}}, tenantID)
 	count, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -230,18 +255,17 @@ func (p *userMembershipRepository) GetByUserId(ctx context.Context, userId uuid.
 		groups = append(groups, u.toRoot())
 	}
 	if err = cursor.Err(); err != nil {
-		span.SetTag("error", true)
-		span.LogKV("error_code", err.Error())
+		tracing.TraceErr(span, err)
 		return &entities.UserMembershipsList{}, errors.Wrap(err, "cursor.Err")
 	}
 	return entities.NewUserMembershipListWithPagination(groups, count, pagination), nil
 }
 
-func (p *userMembershipRepository) GetByGroupId(ctx context.Context, groupId uuid.UUID, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.GetByGroupId")
-	defer span.Finish()
+func (p *userMembershipRepository) GetByGroupId(ctx context.Context, groupId uuid.UUID, tenantID string, pagination *utilities.Pagination) (*entities.UserMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.GetByGroupId")
+	defer span.End()
 	collection := p.db.Database(p.cfg.Mongo.DB).Collection(p.cfg.MongoCollections.UserMemberships)
-	filter := bson.M{"group_id": groupId}
+	filter := withTenant(bson.D{{Key: "group_id", Value: groupId}}, tenantID)
 	count, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -271,16 +295,15 @@ func (p *userMembershipRepository) GetByGroupId(ctx context.Context, groupId uui
 		groups = append(groups, u.toRoot())
 	}
 	if err = cursor.Err(); err != nil {
-		span.SetTag("error", true)
-		span.LogKV("error_code", err.Error())
+		tracing.TraceErr(span, err)
 		return &entities.UserMembershipsList{}, errors.Wrap(err, "cursor.Err")
 	}
 	return entities.NewUserMembershipListWithPagination(groups, count, pagination), nil
 }
 
 func (p *userMembershipRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.DeleteUserMembership")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.DeleteUserMembership")
+	defer span.End()
 	oId, err := utilities.LoadObjectID(id)
 	if err != nil {
 		p.traceErr(span, err)
@@ -291,8 +314,8 @@ func (p *userMembershipRepository) Delete(ctx context.Context, id uuid.UUID) err
 }
 
 func (p *userMembershipRepository) DeleteByMembershipId(ctx context.Context, id uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.DeleteUserMembershipByMembershipId")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.DeleteUserMembershipByMembershipId")
+	defer span.End()
 	oId, err := utilities.LoadObjectID(id)
 	if err != nil {
 		p.traceErr(span, err)
@@ -303,8 +326,8 @@ func (p *userMembershipRepository) DeleteByMembershipId(ctx context.Context, id
 }
 
 func (p *userMembershipRepository) DeleteMany(ctx context.Context, filter *entities.UserMembership) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "userMembershipRepository.DeleteUserMemberships")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "userMembershipRepository.DeleteUserMemberships")
+	defer span.End()
 	f, err := newUserMembershipEntity(filter)
 	if err != nil {
 		p.traceErr(span, err)
@@ -324,7 +347,6 @@ func (p *userMembershipRepository) DeleteMany(ctx context.Context, filter *entit
 	return nil
 }
 
-func (p *userMembershipRepository) traceErr(span opentracing.Span, err error) {
-	span.SetTag("error", true)
-	span.LogKV("error_code", err.Error())
+func (p *userMembershipRepository) traceErr(span trace.Span, err error) {
+	tracing.TraceErr(span, err)
 }
@@ -0,0 +1,41 @@
+package grpc
+
+import "github.com/JECSand/identity-service/pkg/authentication"
+
+// ServiceAccessRules declares which services may call each query service RPC
+func ServiceAccessRules() authentication.ServiceAccessRules {
+	gateway := []string{authentication.ApiGatewayService}
+	projections := []string{authentication.CommandService}
+	return authentication.ServiceAccessRules{
+		"/authQueryService.authQueryService/BlacklistToken":                      gateway,
+		"/authQueryService.authQueryService/Validate":                            gateway,
+		"/authQueryService.authQueryService/UpdatePassword":                      projections,
+		"/queryService.queryService/CreateUser":                                  projections,
+		"/queryService.queryService/UpdateUser":                                  projections,
+		"/queryService.queryService/GetUserById":                                 gateway,
+		"/queryService.queryService/SearchUser":                                  gateway,
+		"/queryService.queryService/DeleteUserByID":                              projections,
+		"/groupQueryService.groupQueryService/CreateGroup":                       projections,
+		"/groupQueryService.groupQueryService/UpdateGroup":                       projections,
+		"/groupQueryService.groupQueryService/GetGroupById":                      gateway,
+		"/groupQueryService.groupQueryService/SearchGroup":                       gateway,
+		"/groupQueryService.groupQueryService/DeleteGroupByID":                   projections,
+		"/membershipQueryService.membershipQueryService/CreateMembership":        projections,
+		"/membershipQueryService.membershipQueryService/UpdateMembership":        projections,
+		"/membershipQueryService.membershipQueryService/GetMembershipById":       gateway,
+		"/membershipQueryService.membershipQueryService/DeleteMembershipByID":    projections,
+		"/membershipQueryService.membershipQueryService/GetGroupMembership":      gateway,
+		"/membershipQueryService.membershipQueryService/GetUserMembership":       gateway,
+		"/membershipQueryService.membershipQueryService/GetEffectiveGroups":      gateway,
+		"/membershipQueryService.membershipQueryService/GetEffectiveMembers":     gateway,
+		"/organizationQueryService.organizationQueryService/GetOrganizationById": gateway,
+		"/organizationQueryService.organizationQueryService/SearchOrganization":  gateway,
+		"/roleQueryService.roleQueryService/GetRoleById":                         gateway,
+		"/roleQueryService.roleQueryService/SearchRoles":                         gateway,
+		"/authzQueryService.authzQueryService/Check":                             gateway,
+		"/authzQueryService.authzQueryService/BatchCheck":                        gateway,
+		"/authzQueryService.authzQueryService/ListResources":                     gateway,
+		"/grpc.health.v1.Health/Check":                                           {authentication.AnyCaller},
+		"/grpc.health.v1.Health/Watch":                                           {authentication.AnyCaller},
+	}
+}
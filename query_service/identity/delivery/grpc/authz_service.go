@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/JECSand/identity-service/query_service/identity/metrics"
+	"github.com/JECSand/identity-service/query_service/identity/queries"
+	"github.com/JECSand/identity-service/query_service/identity/services"
+	authzQueryService "github.com/JECSand/identity-service/query_service/protos/authz_query"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type authzGrpcService struct {
+	log     logging.Logger
+	cfg     *config.Config
+	v       *validator.Validate
+	zs      *services.AuthzService
+	metrics *metrics.QueryServiceMetrics
+}
+
+func NewAuthzQueryGrpcService(
+	log logging.Logger,
+	cfg *config.Config,
+	v *validator.Validate,
+	zs *services.AuthzService,
+	metrics *metrics.QueryServiceMetrics,
+) *authzGrpcService {
+	return &authzGrpcService{
+		log:     log,
+		cfg:     cfg,
+		v:       v,
+		zs:      zs,
+		metrics: metrics,
+	}
+}
+
+func (s *authzGrpcService) Check(ctx context.Context, req *authzQueryService.CheckReq) (*authzQueryService.CheckRes, error) {
+	s.metrics.CheckGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authzGrpcService.Check")
+	defer span.End()
+	query, err := newCheckQuery(req, req.GetTenantID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	if err = s.v.StructCtx(ctx, query); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	decision, err := s.zs.Queries.Check.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("Check.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.countDecisions(decision)
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &authzQueryService.CheckRes{Decision: entities.DecisionToGrpcMessage(decision)}, nil
+}
+
+func (s *authzGrpcService) BatchCheck(ctx context.Context, req *authzQueryService.BatchCheckReq) (*authzQueryService.BatchCheckRes, error) {
+	s.metrics.BatchCheckGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authzGrpcService.BatchCheck")
+	defer span.End()
+	checks := make([]*queries.CheckQuery, 0, len(req.GetChecks()))
+	for _, check := range req.GetChecks() {
+		query, err := newCheckQuery(check, req.GetTenantID())
+		if err != nil {
+			s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+			return nil, s.errResponse(codes.InvalidArgument, err)
+		}
+		checks = append(checks, query)
+	}
+	query := queries.NewBatchCheckQuery(checks, req.GetTenantID())
+	if err := s.v.StructCtx(ctx, query); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	decisions, err := s.zs.Queries.BatchCheck.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("BatchCheck.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.countDecisions(decisions...)
+	s.metrics.SuccessGrpcRequests.Inc()
+	return entities.DecisionListToGrpc(decisions), nil
+}
+
+func (s *authzGrpcService) ListResources(ctx context.Context, req *authzQueryService.ListResourcesReq) (*authzQueryService.ListResourcesRes, error) {
+	s.metrics.ListResourcesGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "authzGrpcService.ListResources")
+	defer span.End()
+	subjectID, err := uuid.FromString(req.GetSubjectID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	query := queries.NewListResourcesQuery(subjectID, req.GetAction(), req.GetTenantID())
+	if err = s.v.StructCtx(ctx, query); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	resources, err := s.zs.Queries.ListResources.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("ListResources.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return entities.ResourceListToGrpc(resources), nil
+}
+
+// newCheckQuery parses a check of a request scoped to tenantID
+func newCheckQuery(req *authzQueryService.CheckReq, tenantID string) (*queries.CheckQuery, error) {
+	subjectID, err := uuid.FromString(req.GetSubjectID())
+	if err != nil {
+		return nil, err
+	}
+	resourceID, err := uuid.FromString(req.GetResourceID())
+	if err != nil {
+		return nil, err
+	}
+	return queries.NewCheckQuery(subjectID, req.GetAction(), resourceID, tenantID), nil
+}
+
+func (s *authzGrpcService) countDecisions(decisions ...*entities.Decision) {
+	for _, decision := range decisions {
+		if decision.Allowed {
+			s.metrics.AllowedDecisions.Inc()
+		} else {
+			s.metrics.DeniedDecisions.Inc()
+		}
+	}
+}
+
+func (s *authzGrpcService) errResponse(c codes.Code, err error) error {
+	s.metrics.ErrorGrpcRequests.Inc()
+	return status.Error(c, err.Error())
+}
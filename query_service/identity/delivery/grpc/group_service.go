@@ -46,15 +46,15 @@ func NewGroupQueryGrpcService(
 func (s *groupGrpcService) CreateGroup(ctx context.Context, req *groupQueryService.CreateGroupReq) (*groupQueryService.CreateGroupRes, error) {
 	s.metrics.CreateGroupGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.CreateGroup")
-	defer span.Finish()
+	defer span.End()
 	// TODO - ADD LOGIC FOR ROOT AND ACTIVE BELOW
-	event := events.NewCreateGroupEvent(req.GetID(), req.GetName(), req.GetDescription(), req.GetCreatorID(), false, time.Now(), time.Now())
+	event := events.NewCreateGroupEvent(req.GetID(), req.GetName(), req.GetDescription(), req.GetCreatorID(), false, "", "", time.Now(), time.Now())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err := s.gs.Events.CreateGroup.Handle(ctx, event); err != nil {
-		s.log.WarnMsg("CreateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateGroup.Handle", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -64,14 +64,14 @@ func (s *groupGrpcService) CreateGroup(ctx context.Context, req *groupQueryServi
 func (s *groupGrpcService) UpdateGroup(ctx context.Context, req *groupQueryService.UpdateGroupReq) (*groupQueryService.UpdateGroupRes, error) {
 	s.metrics.UpdateGroupGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.UpdateGroup")
-	defer span.Finish()
+	defer span.End()
 	command := events.NewUpdateGroupEvent(req.GetID(), req.GetName(), req.GetDescription(), time.Now())
 	if err := s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err := s.gs.Events.UpdateGroup.Handle(ctx, command); err != nil {
-		s.log.WarnMsg("UpdateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateGroup.Handle", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -81,20 +81,20 @@ func (s *groupGrpcService) UpdateGroup(ctx context.Context, req *groupQueryServi
 func (s *groupGrpcService) GetGroupById(ctx context.Context, req *groupQueryService.GetGroupByIdReq) (*groupQueryService.GetGroupByIdRes, error) {
 	s.metrics.GetGroupByIdGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.GetGroupById")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	query := queries.NewGetGroupByIdQuery(id)
+	query := queries.NewGetGroupByIdQuery(id, req.GetTenantID())
 	if err = s.v.StructCtx(ctx, query); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	group, err := s.gs.Queries.GetGroupById.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetGroupById.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetGroupById.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -104,12 +104,12 @@ func (s *groupGrpcService) GetGroupById(ctx context.Context, req *groupQueryServ
 func (s *groupGrpcService) SearchGroup(ctx context.Context, req *groupQueryService.SearchGroupReq) (*groupQueryService.SearchGroupRes, error) {
 	s.metrics.SearchGroupGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.SearchGroup")
-	defer span.Finish()
+	defer span.End()
 	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
-	query := queries.NewSearchGroupQuery(req.GetSearch(), pq)
+	query := queries.NewSearchGroupQuery(req.GetSearch(), req.GetTenantID(), pq)
 	groupsList, err := s.gs.Queries.SearchGroup.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("SearchGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("SearchGroup.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -119,14 +119,14 @@ func (s *groupGrpcService) SearchGroup(ctx context.Context, req *groupQueryServi
 func (s *groupGrpcService) DeleteGroupByID(ctx context.Context, req *groupQueryService.DeleteGroupByIdReq) (*groupQueryService.DeleteGroupByIdRes, error) {
 	s.metrics.DeleteMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "groupGrpcService.DeleteGroupByID")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err = s.gs.Events.DeleteGroup.Handle(ctx, events.NewDeleteGroupEvent(id)); err != nil {
-		s.log.WarnMsg("DeleteGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteGroup.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -47,7 +47,7 @@ func NewMembershipQueryGrpcService(
 func (s *membershipGrpcService) CreateMembership(ctx context.Context, req *membershipQueryService.CreateMembershipReq) (*membershipQueryService.CreateMembershipRes, error) {
 	s.metrics.CreateMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.CreateMembership")
-	defer span.Finish()
+	defer span.End()
 	datetime := time.Now()
 	createdMembership := events.NewCreatedMembership(
 		req.GetMembership().GetID(),
@@ -55,6 +55,12 @@ func (s *membershipGrpcService) CreateMembership(ctx context.Context, req *membe
 		req.GetMembership().GetGroupID(),
 		enums.MembershipStatus(req.GetMembership().GetStatus()),
 		enums.Role(req.GetMembership().GetRole()),
+		req.GetMembership().GetRoleID(),
+		req.GetMembership().GetRoleName(),
+		req.GetMembership().GetPermissions(),
+		req.GetMembership().GetOrganizationID(),
+		time.Time{},
+		time.Time{},
 		datetime,
 		datetime,
 	)
@@ -67,6 +73,12 @@ func (s *membershipGrpcService) CreateMembership(ctx context.Context, req *membe
 		req.GetUserMembership().GetUsername(),
 		enums.MembershipStatus(req.GetUserMembership().GetStatus()),
 		enums.Role(req.GetUserMembership().GetRole()),
+		req.GetUserMembership().GetRoleID(),
+		req.GetUserMembership().GetRoleName(),
+		req.GetUserMembership().GetPermissions(),
+		req.GetUserMembership().GetOrganizationID(),
+		time.Time{},
+		time.Time{},
 		datetime,
 		datetime,
 	)
@@ -79,17 +91,23 @@ func (s *membershipGrpcService) CreateMembership(ctx context.Context, req *membe
 		req.GetGroupMembership().GetDescription(),
 		enums.MembershipStatus(req.GetGroupMembership().GetStatus()),
 		enums.Role(req.GetGroupMembership().GetRole()),
+		req.GetGroupMembership().GetRoleID(),
+		req.GetGroupMembership().GetRoleName(),
+		req.GetGroupMembership().GetPermissions(),
 		req.GetGroupMembership().GetCreator(),
+		req.GetGroupMembership().GetOrganizationID(),
+		time.Time{},
+		time.Time{},
 		datetime,
 		datetime,
 	)
 	event := events.NewCreateMembershipEvent(createdMembership, createdUserMembership, createdGroupMembership)
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err := s.ms.Events.CreateMembership.Handle(ctx, event); err != nil {
-		s.log.WarnMsg("CreateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateMembership.Handle", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -99,14 +117,14 @@ func (s *membershipGrpcService) CreateMembership(ctx context.Context, req *membe
 func (s *membershipGrpcService) UpdateMembership(ctx context.Context, req *membershipQueryService.UpdateMembershipReq) (*membershipQueryService.UpdateMembershipRes, error) {
 	s.metrics.UpdateMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.UpdateMembership")
-	defer span.Finish()
-	command := events.NewUpdateMembershipEvent(req.GetID(), enums.MembershipStatus(req.GetStatus()), enums.Role(req.GetRole()), time.Now())
+	defer span.End()
+	command := events.NewUpdateMembershipEvent(req.GetID(), enums.MembershipStatus(req.GetStatus()), enums.Role(req.GetRole()), "", "", nil, time.Time{}, time.Time{}, time.Now())
 	if err := s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err := s.ms.Events.UpdateMembership.Handle(ctx, command); err != nil {
-		s.log.WarnMsg("UpdateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateMembership.Handle", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -116,20 +134,20 @@ func (s *membershipGrpcService) UpdateMembership(ctx context.Context, req *membe
 func (s *membershipGrpcService) GetMembershipById(ctx context.Context, req *membershipQueryService.GetMembershipByIdReq) (*membershipQueryService.GetMembershipByIdRes, error) {
 	s.metrics.GetMembershipByIdGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.GetMembershipById")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	query := queries.NewGetMembershipByIdQuery(id)
+	query := queries.NewGetMembershipByIdQuery(id, req.GetTenantID())
 	if err = s.v.StructCtx(ctx, query); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	membership, err := s.ms.Queries.GetMembershipById.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetMembershipById.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetMembershipById.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -139,17 +157,17 @@ func (s *membershipGrpcService) GetMembershipById(ctx context.Context, req *memb
 func (s *membershipGrpcService) GetGroupMembership(ctx context.Context, req *membershipQueryService.GetGroupMembershipReq) (*membershipQueryService.GetGroupMembershipRes, error) {
 	s.metrics.GetGroupMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.GetGroupMembership")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetUserID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
-	query := queries.NewGetGroupMembershipQuery(id, pq)
+	query := queries.NewGetGroupMembershipQuery(id, req.GetTenantID(), pq)
 	groupsList, err := s.ms.Queries.GetGroupMembership.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetGroupMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetGroupMembership.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -159,34 +177,74 @@ func (s *membershipGrpcService) GetGroupMembership(ctx context.Context, req *mem
 func (s *membershipGrpcService) GetUserMembership(ctx context.Context, req *membershipQueryService.GetUserMembershipReq) (*membershipQueryService.GetUserMembershipRes, error) {
 	s.metrics.GetUserMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.GetUserMembership")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetGroupID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
-	query := queries.NewGetUserMembershipQuery(id, pq)
+	query := queries.NewGetUserMembershipQuery(id, req.GetTenantID(), pq)
 	usersList, err := s.ms.Queries.GetUserMembership.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetUserMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetUserMembership.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
 	return entities.UserMembershipListToGrpc(usersList), nil
 }
 
+func (s *membershipGrpcService) GetEffectiveGroups(ctx context.Context, req *membershipQueryService.GetEffectiveGroupsReq) (*membershipQueryService.GetEffectiveMembershipsRes, error) {
+	s.metrics.GetEffectiveGroupsGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.GetEffectiveGroups")
+	defer span.End()
+	id, err := uuid.FromString(req.GetUserID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
+	query := queries.NewGetEffectiveGroupsQuery(id, req.GetTenantID(), pq)
+	effectiveList, err := s.ms.Queries.GetEffectiveGroups.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("GetEffectiveGroups.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return entities.EffectiveMembershipListToGrpc(effectiveList), nil
+}
+
+func (s *membershipGrpcService) GetEffectiveMembers(ctx context.Context, req *membershipQueryService.GetEffectiveMembersReq) (*membershipQueryService.GetEffectiveMembershipsRes, error) {
+	s.metrics.GetEffectiveMembersGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.GetEffectiveMembers")
+	defer span.End()
+	id, err := uuid.FromString(req.GetGroupID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
+	query := queries.NewGetEffectiveMembersQuery(id, req.GetTenantID(), pq)
+	effectiveList, err := s.ms.Queries.GetEffectiveMembers.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("GetEffectiveMembers.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return entities.EffectiveMembershipListToGrpc(effectiveList), nil
+}
+
 func (s *membershipGrpcService) DeleteMembershipByID(ctx context.Context, req *membershipQueryService.DeleteMembershipByIdReq) (*membershipQueryService.DeleteMembershipByIdRes, error) {
 	s.metrics.DeleteMembershipGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "membershipGrpcService.DeleteMembershipByID")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err = s.ms.Events.DeleteMembership.Handle(ctx, events.NewDeleteMembershipEvent(id)); err != nil {
-		s.log.WarnMsg("DeleteMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteMembership.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/JECSand/identity-service/query_service/identity/metrics"
+	"github.com/JECSand/identity-service/query_service/identity/queries"
+	"github.com/JECSand/identity-service/query_service/identity/services"
+	organizationQueryService "github.com/JECSand/identity-service/query_service/protos/organization_query"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type organizationGrpcService struct {
+	log     logging.Logger
+	cfg     *config.Config
+	v       *validator.Validate
+	os      *services.OrganizationService
+	metrics *metrics.QueryServiceMetrics
+}
+
+func NewOrganizationQueryGrpcService(
+	log logging.Logger,
+	cfg *config.Config,
+	v *validator.Validate,
+	os *services.OrganizationService,
+	metrics *metrics.QueryServiceMetrics,
+) *organizationGrpcService {
+	return &organizationGrpcService{
+		log:     log,
+		cfg:     cfg,
+		v:       v,
+		os:      os,
+		metrics: metrics,
+	}
+}
+
+func (s *organizationGrpcService) GetOrganizationById(ctx context.Context, req *organizationQueryService.GetOrganizationByIdReq) (*organizationQueryService.GetOrganizationByIdRes, error) {
+	s.metrics.GetOrganizationByIdGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "organizationGrpcService.GetOrganizationById")
+	defer span.End()
+	id, err := uuid.FromString(req.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	query := queries.NewGetOrganizationByIdQuery(id, req.GetTenantID())
+	if err = s.v.StructCtx(ctx, query); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	organization, err := s.os.Queries.GetOrganizationById.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("GetOrganizationById.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &organizationQueryService.GetOrganizationByIdRes{Organization: entities.OrganizationToGrpcMessage(organization)}, nil
+}
+
+func (s *organizationGrpcService) SearchOrganization(ctx context.Context, req *organizationQueryService.SearchOrganizationReq) (*organizationQueryService.SearchOrganizationRes, error) {
+	s.metrics.SearchOrganizationGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "organizationGrpcService.SearchOrganization")
+	defer span.End()
+	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
+	query := queries.NewSearchOrganizationQuery(req.GetSearch(), pq)
+	organizationsList, err := s.os.Queries.SearchOrganization.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("SearchOrganization.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return entities.OrganizationListToGrpc(organizationsList), nil
+}
+
+func (s *organizationGrpcService) errResponse(c codes.Code, err error) error {
+	s.metrics.ErrorGrpcRequests.Inc()
+	return status.Error(c, err.Error())
+}
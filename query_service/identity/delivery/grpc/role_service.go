@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/JECSand/identity-service/query_service/identity/metrics"
+	"github.com/JECSand/identity-service/query_service/identity/queries"
+	"github.com/JECSand/identity-service/query_service/identity/services"
+	roleQueryService "github.com/JECSand/identity-service/query_service/protos/role_query"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type roleGrpcService struct {
+	log     logging.Logger
+	cfg     *config.Config
+	v       *validator.Validate
+	rs      *services.RoleService
+	metrics *metrics.QueryServiceMetrics
+}
+
+func NewRoleQueryGrpcService(
+	log logging.Logger,
+	cfg *config.Config,
+	v *validator.Validate,
+	rs *services.RoleService,
+	metrics *metrics.QueryServiceMetrics,
+) *roleGrpcService {
+	return &roleGrpcService{
+		log:     log,
+		cfg:     cfg,
+		v:       v,
+		rs:      rs,
+		metrics: metrics,
+	}
+}
+
+func (s *roleGrpcService) GetRoleById(ctx context.Context, req *roleQueryService.GetRoleByIdReq) (*roleQueryService.GetRoleByIdRes, error) {
+	s.metrics.GetRoleByIdGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "roleGrpcService.GetRoleById")
+	defer span.End()
+	id, err := uuid.FromString(req.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	query := queries.NewGetRoleByIdQuery(id, req.GetTenantID())
+	if err = s.v.StructCtx(ctx, query); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+	role, err := s.rs.Queries.GetRoleById.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("GetRoleById.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &roleQueryService.GetRoleByIdRes{Role: entities.RoleToGrpcMessage(role)}, nil
+}
+
+func (s *roleGrpcService) SearchRoles(ctx context.Context, req *roleQueryService.SearchRolesReq) (*roleQueryService.SearchRolesRes, error) {
+	s.metrics.SearchRolesGrpcRequests.Inc()
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "roleGrpcService.SearchRoles")
+	defer span.End()
+	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
+	query := queries.NewSearchRolesQuery(req.GetSearch(), req.GetGroupID(), req.GetTenantID(), pq)
+	rolesList, err := s.rs.Queries.SearchRoles.Handle(ctx, query)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("SearchRoles.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+	s.metrics.SuccessGrpcRequests.Inc()
+	return entities.RoleListToGrpc(rolesList), nil
+}
+
+func (s *roleGrpcService) errResponse(c codes.Code, err error) error {
+	s.metrics.ErrorGrpcRequests.Inc()
+	return status.Error(c, err.Error())
+}
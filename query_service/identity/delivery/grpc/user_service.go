@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/pkg/utilities"
@@ -49,15 +50,15 @@ func NewQueryGrpcService(
 func (s *grpcService) CreateUser(ctx context.Context, req *queryService.CreateUserReq) (*queryService.CreateUserRes, error) {
 	s.metrics.CreateUserGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.CreateUser")
-	defer span.Finish()
+	defer span.End()
 	// TODO - ADD LOGIC FOR ROOT AND ACTIVE BELOW
-	event := events.NewCreateUserEvent(req.GetID(), req.GetEmail(), req.GetUsername(), req.GetPassword(), false, false, time.Now(), time.Now())
+	event := events.NewCreateUserEvent(req.GetID(), req.GetEmail(), req.GetUsername(), false, false, enums.UserStatusFromActive(false), "", false, time.Now(), time.Now())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err := s.us.Events.CreateUser.Handle(ctx, event); err != nil {
-		s.log.WarnMsg("CreateUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateUser.Handle", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -67,14 +68,14 @@ func (s *grpcService) CreateUser(ctx context.Context, req *queryService.CreateUs
 func (s *grpcService) UpdateUser(ctx context.Context, req *queryService.UpdateUserReq) (*queryService.UpdateUserRes, error) {
 	s.metrics.UpdateUserGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.UpdateUser")
-	defer span.Finish()
+	defer span.End()
 	command := events.NewUpdateUserEvent(req.GetID(), req.GetEmail(), req.GetUsername(), time.Now())
 	if err := s.v.StructCtx(ctx, command); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err := s.us.Events.UpdateUser.Handle(ctx, command); err != nil {
-		s.log.WarnMsg("UpdateUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateUser.Handle", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -84,20 +85,20 @@ func (s *grpcService) UpdateUser(ctx context.Context, req *queryService.UpdateUs
 func (s *grpcService) GetUserById(ctx context.Context, req *queryService.GetUserByIdReq) (*queryService.GetUserByIdRes, error) {
 	s.metrics.GetUserByIdGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.GetUserById")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
-	query := queries.NewGetUserByIdQuery(id)
+	query := queries.NewGetUserByIdQuery(id, req.GetTenantID())
 	if err = s.v.StructCtx(ctx, query); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	user, err := s.us.Queries.GetUserById.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("GetUserById.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("GetUserById.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -107,12 +108,12 @@ func (s *grpcService) GetUserById(ctx context.Context, req *queryService.GetUser
 func (s *grpcService) SearchUser(ctx context.Context, req *queryService.SearchReq) (*queryService.SearchRes, error) {
 	s.metrics.SearchUserGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.SearchUser")
-	defer span.Finish()
+	defer span.End()
 	pq := utilities.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
-	query := queries.NewSearchUserQuery(req.GetSearch(), pq)
+	query := queries.NewSearchUserQuery(req.GetSearch(), req.GetTenantID(), pq)
 	usersList, err := s.us.Queries.SearchUser.Handle(ctx, query)
 	if err != nil {
-		s.log.WarnMsg("SearchUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("SearchUser.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -122,14 +123,14 @@ func (s *grpcService) SearchUser(ctx context.Context, req *queryService.SearchRe
 func (s *grpcService) DeleteUserByID(ctx context.Context, req *queryService.DeleteUserByIdReq) (*queryService.DeleteUserByIdRes, error) {
 	s.metrics.DeleteUserGrpcRequests.Inc()
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.DeleteUserByID")
-	defer span.Finish()
+	defer span.End()
 	id, err := uuid.FromString(req.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 	if err = s.us.Events.DeleteUser.Handle(ctx, events.NewDeleteUserEvent(id)); err != nil {
-		s.log.WarnMsg("DeleteUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteUser.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -2,8 +2,11 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"github.com/JECSand/identity-service/pkg/enums"
+	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	kafkaMessages "github.com/JECSand/identity-service/protos/kafka"
 	"github.com/JECSand/identity-service/query_service/config"
@@ -13,8 +16,7 @@ import (
 	"github.com/avast/retry-go"
 	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"sync"
 	"time"
 )
@@ -33,14 +35,17 @@ var (
 )
 
 type queryMessageProcessor struct {
-	log     logging.Logger
-	cfg     *config.Config
-	v       *validator.Validate
-	us      *services.UserService
-	gs      *services.GroupService
-	ms      *services.MembershipService
-	as      *services.AuthService
-	metrics *metrics.QueryServiceMetrics
+	log      logging.Logger
+	cfg      *config.Config
+	v        *validator.Validate
+	us       *services.UserService
+	gs       *services.GroupService
+	ms       *services.MembershipService
+	as       *services.AuthService
+	os       *services.OrganizationService
+	rs       *services.RoleService
+	registry *kafkaClient.EventRegistry
+	metrics  *metrics.QueryServiceMetrics
 }
 
 func NewQueryMessageProcessor(
@@ -51,21 +56,26 @@ func NewQueryMessageProcessor(
 	gs *services.GroupService,
 	ms *services.MembershipService,
 	as *services.AuthService,
+	os *services.OrganizationService,
+	rs *services.RoleService,
 	metrics *metrics.QueryServiceMetrics,
 ) *queryMessageProcessor {
 	return &queryMessageProcessor{
-		log:     log,
-		cfg:     cfg,
-		v:       v,
-		us:      us,
-		gs:      gs,
-		ms:      ms,
-		as:      as,
-		metrics: metrics,
+		log:      log,
+		cfg:      cfg,
+		v:        v,
+		us:       us,
+		gs:       gs,
+		ms:       ms,
+		as:       as,
+		os:       os,
+		rs:       rs,
+		registry: kafkaClient.DefaultEventRegistry(),
+		metrics:  metrics,
 	}
 }
 
-func (s *queryMessageProcessor) ProcessMessages(ctx context.Context, r *kafka.Reader, wg *sync.WaitGroup, workerID int) {
+func (s *queryMessageProcessor) ProcessMessages(ctx context.Context, r messaging.Reader, wg *sync.WaitGroup, workerID int) {
 	defer wg.Done()
 	for {
 		select {
@@ -74,11 +84,14 @@ func (s *queryMessageProcessor) ProcessMessages(ctx context.Context, r *kafka.Re
 		default:
 		}
 		m, err := r.FetchMessage(ctx)
+		if errors.Is(err, messaging.ErrClosed) {
+			return
+		}
 		if err != nil {
 			s.log.Warnf("workerID: %v, err: %v", workerID, err)
 			continue
 		}
-		s.logProcessMessage(m, workerID)
+		s.logProcessMessage(ctx, m, workerID)
 		switch m.Topic {
 		case s.cfg.KafkaTopics.UserCreated.TopicName:
 			s.processUserCreated(ctx, r, m)
@@ -86,18 +99,36 @@ func (s *queryMessageProcessor) ProcessMessages(ctx context.Context, r *kafka.Re
 			s.processUserUpdated(ctx, r, m)
 		case s.cfg.KafkaTopics.UserDeleted.TopicName:
 			s.processUserDeleted(ctx, r, m)
+		case s.cfg.KafkaTopics.UserStatusChanged.TopicName:
+			s.processUserStatusChanged(ctx, r, m)
 		case s.cfg.KafkaTopics.GroupCreated.TopicName:
 			s.processGroupCreated(ctx, r, m)
 		case s.cfg.KafkaTopics.GroupUpdated.TopicName:
 			s.processGroupUpdated(ctx, r, m)
 		case s.cfg.KafkaTopics.GroupDeleted.TopicName:
 			s.processGroupDeleted(ctx, r, m)
+		case s.cfg.KafkaTopics.GroupParentChanged.TopicName:
+			s.processGroupParentChanged(ctx, r, m)
 		case s.cfg.KafkaTopics.MembershipCreated.TopicName:
 			s.processMembershipCreated(ctx, r, m)
 		case s.cfg.KafkaTopics.MembershipUpdated.TopicName:
 			s.processMembershipUpdated(ctx, r, m)
 		case s.cfg.KafkaTopics.MembershipDeleted.TopicName:
 			s.processMembershipDeleted(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationCreated.TopicName:
+			s.processOrganizationCreated(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationUpdated.TopicName:
+			s.processOrganizationUpdated(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationStatusChanged.TopicName:
+			s.processOrganizationStatusChanged(ctx, r, m)
+		case s.cfg.KafkaTopics.OrganizationDeleted.TopicName:
+			s.processOrganizationDeleted(ctx, r, m)
+		case s.cfg.KafkaTopics.RoleCreated.TopicName:
+			s.processRoleCreated(ctx, r, m)
+		case s.cfg.KafkaTopics.RoleUpdated.TopicName:
+			s.processRoleUpdated(ctx, r, m)
+		case s.cfg.KafkaTopics.RoleDeleted.TopicName:
+			s.processRoleDeleted(ctx, r, m)
 		case s.cfg.KafkaTopics.TokenBlacklisted.TopicName:
 			s.processBlacklistedToken(ctx, r, m)
 		case s.cfg.KafkaTopics.PasswordUpdated.TopicName:
@@ -106,22 +137,30 @@ func (s *queryMessageProcessor) ProcessMessages(ctx context.Context, r *kafka.Re
 	}
 }
 
-func (s *queryMessageProcessor) processMembershipCreated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processMembershipCreated(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.CreateMembershipKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processMembershipCreated")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processMembershipCreated")
+	defer span.End()
 	msg := &kafkaMessages.MembershipCreated{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.MembershipCreatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	createdMembership := events.NewCreatedMembership(
 		msg.GetMembership().GetID(),
 		msg.GetMembership().GetUserID(),
 		msg.GetMembership().GetGroupID(),
 		enums.MembershipStatus(msg.GetMembership().GetStatus()),
 		enums.Role(msg.GetMembership().GetRole()),
+		msg.GetMembership().GetRoleID(),
+		msg.GetMembership().GetRoleName(),
+		msg.GetMembership().GetPermissions(),
+		msg.GetMembership().GetOrganizationID(),
+		optionalTime(msg.GetMembership().GetValidFrom()),
+		optionalTime(msg.GetMembership().GetValidUntil()),
 		msg.GroupMembership.GetCreatedAt().AsTime(),
 		msg.GroupMembership.GetUpdatedAt().AsTime(),
 	)
@@ -134,6 +173,12 @@ func (s *queryMessageProcessor) processMembershipCreated(ctx context.Context, r
 		msg.GetUserMembership().GetUsername(),
 		enums.MembershipStatus(msg.GetUserMembership().GetStatus()),
 		enums.Role(msg.GetUserMembership().GetRole()),
+		msg.GetUserMembership().GetRoleID(),
+		msg.GetUserMembership().GetRoleName(),
+		msg.GetUserMembership().GetPermissions(),
+		msg.GetUserMembership().GetOrganizationID(),
+		optionalTime(msg.GetUserMembership().GetValidFrom()),
+		optionalTime(msg.GetUserMembership().GetValidUntil()),
 		msg.GroupMembership.GetCreatedAt().AsTime(),
 		msg.GroupMembership.GetUpdatedAt().AsTime(),
 	)
@@ -146,7 +191,13 @@ func (s *queryMessageProcessor) processMembershipCreated(ctx context.Context, r
 		msg.GetGroupMembership().GetDescription(),
 		enums.MembershipStatus(msg.GetGroupMembership().GetStatus()),
 		enums.Role(msg.GetGroupMembership().GetRole()),
+		msg.GetGroupMembership().GetRoleID(),
+		msg.GetGroupMembership().GetRoleName(),
+		msg.GetGroupMembership().GetPermissions(),
 		msg.GetGroupMembership().GetCreator(),
+		msg.GetGroupMembership().GetOrganizationID(),
+		optionalTime(msg.GetGroupMembership().GetValidFrom()),
+		optionalTime(msg.GetGroupMembership().GetValidUntil()),
 		msg.GroupMembership.GetCreatedAt().AsTime(),
 		msg.GroupMembership.GetUpdatedAt().AsTime(),
 	)
@@ -156,89 +207,95 @@ func (s *queryMessageProcessor) processMembershipCreated(ctx context.Context, r
 		createdGroupMembership,
 	)
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.ms.Events.CreateMembership.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("CreateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateMembership.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processMembershipUpdated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processMembershipUpdated(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.UpdateMembershipKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processMembershipUpdated")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processMembershipUpdated")
+	defer span.End()
 	msg := &kafkaMessages.MembershipUpdated{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.MembershipUpdatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	p := msg.GetMembership()
-	event := events.NewUpdateMembershipEvent(p.GetID(), enums.MembershipStatus(p.GetStatus()), enums.Role(p.GetRole()), p.GetUpdatedAt().AsTime())
+	event := events.NewUpdateMembershipEvent(p.GetID(), enums.MembershipStatus(p.GetStatus()), enums.Role(p.GetRole()), p.GetRoleID(), p.GetRoleName(), p.GetPermissions(), optionalTime(p.GetValidFrom()), optionalTime(p.GetValidUntil()), p.GetUpdatedAt().AsTime())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.ms.Events.UpdateMembership.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdateMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateMembership.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processMembershipDeleted(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processMembershipDeleted(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.DeleteGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processMembershipDeleted")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processMembershipDeleted")
+	defer span.End()
 	msg := &kafkaMessages.MembershipDeleted{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.MembershipDeletedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	event := events.NewDeleteMembershipEvent(id)
 	if err = s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.ms.Events.DeleteMembership.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("DeleteMembership.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteMembership.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processGroupCreated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processGroupCreated(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.CreateGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processGroupCreated")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processGroupCreated")
+	defer span.End()
 	msg := &kafkaMessages.GroupCreated{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.GroupCreatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	p := msg.GetGroup()
 	event := events.NewCreateGroupEvent(
 		p.GetID(),
@@ -246,239 +303,562 @@ func (s *queryMessageProcessor) processGroupCreated(ctx context.Context, r *kafk
 		p.GetDescription(),
 		p.GetCreatorID(),
 		p.GetActive(),
+		p.GetOrganizationID(),
+		p.GetParentID(),
 		p.GetCreatedAt().AsTime(),
 		p.GetUpdatedAt().AsTime(),
 	)
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.gs.Events.CreateGroup.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("CreateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateGroup.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processGroupUpdated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processGroupUpdated(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.UpdateGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processGroupUpdated")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processGroupUpdated")
+	defer span.End()
 	msg := &kafkaMessages.GroupUpdated{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.GroupUpdatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	p := msg.GetGroup()
 	event := events.NewUpdateGroupEvent(p.GetID(), p.GetName(), p.GetDescription(), p.GetUpdatedAt().AsTime())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.gs.Events.UpdateGroup.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdateGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateGroup.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processGroupParentChanged(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.ChangeGroupParentKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processGroupParentChanged")
+	defer span.End()
+	msg := &kafkaMessages.GroupParentChanged{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.GroupParentChangedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	p := msg.GetGroup()
+	id, err := uuid.FromString(p.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	event := events.NewChangeGroupParentEvent(id, p.GetParentID(), p.GetUpdatedAt().AsTime())
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.gs.Events.ChangeGroupParent.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("ChangeGroupParent.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processGroupDeleted(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processGroupDeleted(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.DeleteGroupKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processGroupDeleted")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processGroupDeleted")
+	defer span.End()
 	msg := &kafkaMessages.GroupDeleted{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.GroupDeletedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	event := events.NewDeleteGroupEvent(id)
 	if err = s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.gs.Events.DeleteGroup.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("DeleteGroup.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteGroup.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processBlacklistedToken(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processBlacklistedToken(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.BlacklistTokenKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processBlacklistedToken")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processBlacklistedToken")
+	defer span.End()
 	msg := &kafkaMessages.TokenBlacklisted{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.TokenBlacklistedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	p := msg.GetBlacklist()
 	event := events.NewBlacklistTokenEvent(p.GetID(), p.GetAccessToken(), p.GetCreatedAt().AsTime(), p.GetUpdatedAt().AsTime())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.as.Events.BlacklistToken.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("BlacklistToken.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("BlacklistToken.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processPasswordUpdated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processPasswordUpdated(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.UpdatePasswordKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processPasswordUpdated")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processPasswordUpdated")
+	defer span.End()
 	msg := &kafkaMessages.PasswordUpdated{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.PasswordUpdatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
-	event := events.NewUpdatePasswordEvent(msg.GetID(), msg.NewPassword, msg.GetUpdatedAt().AsTime())
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	event := events.NewUpdatePasswordEvent(msg.GetID(), msg.GetUpdatedAt().AsTime())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.as.Events.UpdatePassword.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdatePassword.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdatePassword.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processUserCreated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processUserCreated(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.CreateUserKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processUserCreated")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processUserCreated")
+	defer span.End()
 	msg := &kafkaMessages.UserCreated{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.UserCreatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	p := msg.GetUser()
 	// TODO: Write logic for Root and Active User fields below
-	event := events.NewCreateUserEvent(p.GetID(), p.GetEmail(), p.GetUsername(), p.GetPassword(), p.GetRoot(), p.GetActive(), p.GetCreatedAt().AsTime(), p.GetUpdatedAt().AsTime())
+	event := events.NewCreateUserEvent(p.GetID(), p.GetEmail(), p.GetUsername(), p.GetRoot(), p.GetActive(), enums.UserStatusFromString(p.GetStatus()), p.GetOrganizationID(), p.GetOrgAdmin(), p.GetCreatedAt().AsTime(), p.GetUpdatedAt().AsTime())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.us.Events.CreateUser.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("CreateUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("CreateUser.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processUserUpdated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processUserUpdated(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.UpdateUserKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processUserUpdated")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processUserUpdated")
+	defer span.End()
 	msg := &kafkaMessages.UserUpdated{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.UserUpdatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	p := msg.GetUser()
 	event := events.NewUpdateUserEvent(p.GetID(), p.GetEmail(), p.GetUsername(), p.GetUpdatedAt().AsTime())
 	if err := s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err := retry.Do(func() error {
 		return s.us.Events.UpdateUser.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("UpdateUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("UpdateUser.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) processUserDeleted(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) processUserDeleted(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.DeleteUserKafkaMessages.Inc()
-	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "queryMessageProcessor.processUserDeleted")
-	defer span.Finish()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processUserDeleted")
+	defer span.End()
 	msg := &kafkaMessages.UserDeleted{}
-	if err := proto.Unmarshal(m.Value, msg); err != nil {
-		s.log.WarnMsg("proto.Unmarshal", err)
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.UserDeletedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
 	id, err := uuid.FromString(msg.GetID())
 	if err != nil {
-		s.log.WarnMsg("uuid.FromString", err)
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	event := events.NewDeleteUserEvent(id)
 	if err = s.v.StructCtx(ctx, event); err != nil {
-		s.log.WarnMsg("validate", err)
+		s.log.WithContext(ctx).WarnMsg("validate", err)
 		s.commitErrMessage(ctx, r, m)
 		return
 	}
 	if err = retry.Do(func() error {
 		return s.us.Events.DeleteUser.Handle(ctx, event)
 	}, append(retryOptions, retry.Context(ctx))...); err != nil {
-		s.log.WarnMsg("DeleteUser.Handle", err)
+		s.log.WithContext(ctx).WarnMsg("DeleteUser.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processUserStatusChanged(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.ChangeUserStatusKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processUserStatusChanged")
+	defer span.End()
+	msg := &kafkaMessages.UserStatusChanged{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.UserStatusChangedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	p := msg.GetUser()
+	var suspendedUntil, sessionsRevokedAt time.Time
+	if p.GetSuspendedUntil() != nil {
+		suspendedUntil = p.GetSuspendedUntil().AsTime()
+	}
+	if p.GetSessionsRevokedAt() != nil {
+		sessionsRevokedAt = p.GetSessionsRevokedAt().AsTime()
+	}
+	event := events.NewChangeUserStatusEvent(
+		p.GetID(),
+		enums.UserStatusFromString(p.GetStatus()),
+		p.GetStatusReason(),
+		suspendedUntil,
+		sessionsRevokedAt,
+		p.GetUpdatedAt().AsTime(),
+	)
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.us.Events.ChangeUserStatus.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("ChangeUserStatus.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processOrganizationCreated(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.CreateOrganizationKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processOrganizationCreated")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationCreated{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationCreatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	p := msg.GetOrganization()
+	event := events.NewCreateOrganizationEvent(p.GetID(), p.GetName(), p.GetDescription(), p.GetActive(), p.GetCreatedAt().AsTime(), p.GetUpdatedAt().AsTime())
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.os.Events.CreateOrganization.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("CreateOrganization.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processOrganizationUpdated(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.UpdateOrganizationKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processOrganizationUpdated")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationUpdated{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationUpdatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	p := msg.GetOrganization()
+	event := events.NewUpdateOrganizationEvent(p.GetID(), p.GetName(), p.GetDescription(), p.GetUpdatedAt().AsTime())
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.os.Events.UpdateOrganization.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("UpdateOrganization.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processOrganizationStatusChanged(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.ChangeOrganizationStatusKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processOrganizationStatusChanged")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationStatusChanged{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationStatusChangedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	p := msg.GetOrganization()
+	var sessionsRevokedAt time.Time
+	if p.GetSessionsRevokedAt() != nil {
+		sessionsRevokedAt = p.GetSessionsRevokedAt().AsTime()
+	}
+	event := events.NewChangeOrganizationStatusEvent(p.GetID(), p.GetActive(), sessionsRevokedAt, p.GetUpdatedAt().AsTime())
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.os.Events.ChangeOrganizationStatus.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("ChangeOrganizationStatus.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processOrganizationDeleted(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.DeleteOrganizationKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processOrganizationDeleted")
+	defer span.End()
+	msg := &kafkaMessages.OrganizationDeleted{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.OrganizationDeletedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	event := events.NewDeleteOrganizationEvent(id)
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.os.Events.DeleteOrganization.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("DeleteOrganization.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processRoleCreated(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.CreateRoleKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processRoleCreated")
+	defer span.End()
+	msg := &kafkaMessages.RoleCreated{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.RoleCreatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	event := roleCreatedEvent(msg.GetRole())
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.rs.Events.CreateRole.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("CreateRole.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processRoleUpdated(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.UpdateRoleKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processRoleUpdated")
+	defer span.End()
+	msg := &kafkaMessages.RoleUpdated{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.RoleUpdatedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	p := msg.GetRole()
+	event := events.NewUpdateRoleEvent(p.GetID(), p.GetName(), p.GetDescription(), p.GetPermissions(), p.GetUpdatedAt().AsTime())
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.rs.Events.UpdateRole.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("UpdateRole.Handle", err)
+		s.metrics.ErrorKafkaMessages.Inc()
+		return
+	}
+	s.commitMessage(ctx, r, m)
+}
+
+func (s *queryMessageProcessor) processRoleDeleted(ctx context.Context, r messaging.Reader, m messaging.Message) {
+	s.metrics.DeleteRoleKafkaMessages.Inc()
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m, "queryMessageProcessor.processRoleDeleted")
+	defer span.End()
+	msg := &kafkaMessages.RoleDeleted{}
+	envelope, err := s.registry.Unmarshal(m, kafkaClient.RoleDeletedEvent, msg)
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("registry.Unmarshal", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	ctx = kafkaClient.ContextWithEnvelope(ctx, envelope)
+	id, err := uuid.FromString(msg.GetID())
+	if err != nil {
+		s.log.WithContext(ctx).WarnMsg("uuid.FromString", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	event := events.NewDeleteRoleEvent(id, roleCreatedEvent(msg.GetFallbackRole()))
+	if err = s.v.StructCtx(ctx, event); err != nil {
+		s.log.WithContext(ctx).WarnMsg("validate", err)
+		s.commitErrMessage(ctx, r, m)
+		return
+	}
+	if err = retry.Do(func() error {
+		return s.rs.Events.DeleteRole.Handle(ctx, event)
+	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WithContext(ctx).WarnMsg("DeleteRole.Handle", err)
 		s.metrics.ErrorKafkaMessages.Inc()
 		return
 	}
 	s.commitMessage(ctx, r, m)
 }
 
-func (s *queryMessageProcessor) commitMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+// roleCreatedEvent maps a projected role onto the event creating it
+func roleCreatedEvent(p *kafkaMessages.Role) *events.CreateRoleEvent {
+	return events.NewCreateRoleEvent(p.GetID(), p.GetName(), p.GetDescription(), p.GetPermissions(), p.GetGroupID(), p.GetOrganizationID(), p.GetBuiltIn(), p.GetCreatedAt().AsTime(), p.GetUpdatedAt().AsTime())
+}
+
+func (s *queryMessageProcessor) commitMessage(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.SuccessKafkaMessages.Inc()
-	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
+	s.log.KafkaLogCommittedMessage(ctx, m.Topic, m.Partition, m.Offset)
 	if err := r.CommitMessages(ctx, m); err != nil {
 		s.log.WarnMsg("commitMessage", err)
 	}
 }
 
-func (s *queryMessageProcessor) logProcessMessage(m kafka.Message, workerID int) {
-	s.log.KafkaProcessMessage(m.Topic, m.Partition, string(m.Value), workerID, m.Offset, m.Time)
+func (s *queryMessageProcessor) logProcessMessage(ctx context.Context, m messaging.Message, workerID int) {
+	s.log.KafkaProcessMessage(tracing.ContextFromKafkaHeaders(ctx, m.Headers), m.Topic, m.Partition, m.Key, len(m.Value), workerID, m.Offset, m.Time)
 }
 
-func (s *queryMessageProcessor) commitErrMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *queryMessageProcessor) commitErrMessage(ctx context.Context, r messaging.Reader, m messaging.Message) {
 	s.metrics.ErrorKafkaMessages.Inc()
-	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
+	s.log.KafkaLogCommittedMessage(ctx, m.Topic, m.Partition, m.Offset)
 	if err := r.CommitMessages(ctx, m); err != nil {
 		s.log.WarnMsg("commitMessage", err)
 	}
 }
+
+// optionalTime parses an optional timestamp of an event message, such as the ValidUntil of an unbounded membership
+func optionalTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
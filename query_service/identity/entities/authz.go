@@ -0,0 +1,51 @@
+package entities
+
+import queryService "github.com/JECSand/identity-service/query_service/protos/authz_query"
+
+// Decision answers whether a subject may take an action on a resource group; GrantedBy is the group whose membership
+// granted it, which is the resource itself or one of its ancestors
+type Decision struct {
+	SubjectID  string `json:"subjectID"`
+	Action     string `json:"action"`
+	ResourceID string `json:"resourceID"`
+	Allowed    bool   `json:"allowed"`
+	Reason     string `json:"reason,omitempty"`
+	GrantedBy  string `json:"grantedBy,omitempty"`
+}
+
+// ResourceList holds the resource groups a subject may take an action on; All is set for subjects that may act on
+// every resource of their tenant
+type ResourceList struct {
+	SubjectID   string   `json:"subjectID"`
+	Action      string   `json:"action"`
+	All         bool     `json:"all"`
+	ResourceIDs []string `json:"resourceIDs"`
+}
+
+func DecisionToGrpcMessage(decision *Decision) *queryService.Decision {
+	return &queryService.Decision{
+		SubjectID:  decision.SubjectID,
+		Action:     decision.Action,
+		ResourceID: decision.ResourceID,
+		Allowed:    decision.Allowed,
+		Reason:     decision.Reason,
+		GrantedBy:  decision.GrantedBy,
+	}
+}
+
+func DecisionListToGrpc(decisions []*Decision) *queryService.BatchCheckRes {
+	list := make([]*queryService.Decision, 0, len(decisions))
+	for _, decision := range decisions {
+		list = append(list, DecisionToGrpcMessage(decision))
+	}
+	return &queryService.BatchCheckRes{Decisions: list}
+}
+
+func ResourceListToGrpc(resources *ResourceList) *queryService.ListResourcesRes {
+	return &queryService.ListResourcesRes{
+		SubjectID:   resources.SubjectID,
+		Action:      resources.Action,
+		All:         resources.All,
+		ResourceIDs: resources.ResourceIDs,
+	}
+}
@@ -8,13 +8,15 @@ import (
 )
 
 type Group struct {
-	ID          string    `json:"id" bson:"_id,omitempty"`
-	Name        string    `json:"name,omitempty" bson:"email,omitempty" validate:"required,min=3,max=250"`
-	Description string    `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
-	CreatorID   string    `json:"creatorID,omitempty" bson:"creator_id,omitempty" validate:"required"`
-	Active      bool      `json:"active,omitempty" bson:"active,omitempty"`
-	CreatedAt   time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string    `json:"id" bson:"_id,omitempty"`
+	Name           string    `json:"name,omitempty" bson:"email,omitempty" validate:"required,min=3,max=250"`
+	Description    string    `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
+	CreatorID      string    `json:"creatorID,omitempty" bson:"creator_id,omitempty" validate:"required"`
+	Active         bool      `json:"active,omitempty" bson:"active,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ParentID       string    `json:"parentID,omitempty" bson:"parent_id,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 // GetID returns the unique identifier of the Group
@@ -45,13 +47,15 @@ func NewGroupListWithPagination(groups []*Group, count int64, pagination *utilit
 
 func GroupToGrpcMessage(group *Group) *queryService.Group {
 	return &queryService.Group{
-		ID:          group.ID,
-		Name:        group.Name,
-		Description: group.Description,
-		CreatorID:   group.CreatorID,
-		Active:      group.Active,
-		CreatedAt:   timestamppb.New(group.CreatedAt),
-		UpdatedAt:   timestamppb.New(group.UpdatedAt),
+		ID:             group.ID,
+		Name:           group.Name,
+		Description:    group.Description,
+		CreatorID:      group.CreatorID,
+		Active:         group.Active,
+		CreatedAt:      timestamppb.New(group.CreatedAt),
+		UpdatedAt:      timestamppb.New(group.UpdatedAt),
+		OrganizationID: group.OrganizationID,
+		ParentID:       group.ParentID,
 	}
 }
 
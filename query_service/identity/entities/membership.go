@@ -9,13 +9,19 @@ import (
 )
 
 type Membership struct {
-	ID        string                 `json:"id" bson:"_id,omitempty"`
-	UserID    string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
-	GroupID   string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
-	Status    enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
-	Role      enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
-	CreatedAt time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string                 `json:"id" bson:"_id,omitempty"`
+	UserID         string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
+	GroupID        string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
+	Status         enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
+	Role           enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
+	RoleID         string                 `json:"roleID,omitempty" bson:"role_id,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty" bson:"role_name,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty" bson:"permissions,omitempty"`
+	OrganizationID string                 `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 // GetID returns the unique identifier of the Membership
@@ -25,27 +31,39 @@ func (u *Membership) GetID() string {
 
 func MembershipToGrpcMessage(membership *Membership) *queryService.Membership {
 	return &queryService.Membership{
-		ID:        membership.ID,
-		UserID:    membership.UserID,
-		GroupID:   membership.GroupID,
-		Status:    int64(membership.Status.EnumIndex()),
-		Role:      int64(membership.Role.EnumIndex()),
-		CreatedAt: timestamppb.New(membership.CreatedAt),
-		UpdatedAt: timestamppb.New(membership.UpdatedAt),
+		ID:             membership.ID,
+		UserID:         membership.UserID,
+		GroupID:        membership.GroupID,
+		Status:         int64(membership.Status.EnumIndex()),
+		Role:           int64(membership.Role.EnumIndex()),
+		CreatedAt:      timestamppb.New(membership.CreatedAt),
+		UpdatedAt:      timestamppb.New(membership.UpdatedAt),
+		OrganizationID: membership.OrganizationID,
+		RoleID:         membership.RoleID,
+		RoleName:       membership.RoleName,
+		Permissions:    membership.Permissions,
+		ValidFrom:      optionalTimestamp(membership.ValidFrom),
+		ValidUntil:     optionalTimestamp(membership.ValidUntil),
 	}
 }
 
 type UserMembership struct {
-	ID           string                 `json:"id" bson:"_id,omitempty"`
-	GroupID      string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
-	UserID       string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
-	MembershipID string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
-	Email        string                 `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=500"`
-	Username     string                 `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
-	Status       enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
-	Role         enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
-	CreatedAt    time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt    time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string                 `json:"id" bson:"_id,omitempty"`
+	GroupID        string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
+	UserID         string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
+	MembershipID   string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
+	Email          string                 `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=500"`
+	Username       string                 `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
+	Status         enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
+	Role           enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
+	RoleID         string                 `json:"roleID,omitempty" bson:"role_id,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty" bson:"role_name,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty" bson:"permissions,omitempty"`
+	OrganizationID string                 `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 // GetID returns the unique identifier of the UserMembership
@@ -65,16 +83,22 @@ type UserMembershipsList struct {
 
 func UserMembershipToGrpcMessage(userMembership *UserMembership) *queryService.UserMembership {
 	return &queryService.UserMembership{
-		ID:           userMembership.ID,
-		GroupID:      userMembership.GroupID,
-		UserID:       userMembership.UserID,
-		MembershipID: userMembership.MembershipID,
-		Email:        userMembership.Email,
-		Username:     userMembership.Username,
-		Status:       int64(userMembership.Status.EnumIndex()),
-		Role:         int64(userMembership.Role.EnumIndex()),
-		CreatedAt:    timestamppb.New(userMembership.CreatedAt),
-		UpdatedAt:    timestamppb.New(userMembership.UpdatedAt),
+		ID:             userMembership.ID,
+		GroupID:        userMembership.GroupID,
+		UserID:         userMembership.UserID,
+		MembershipID:   userMembership.MembershipID,
+		Email:          userMembership.Email,
+		Username:       userMembership.Username,
+		Status:         int64(userMembership.Status.EnumIndex()),
+		Role:           int64(userMembership.Role.EnumIndex()),
+		CreatedAt:      timestamppb.New(userMembership.CreatedAt),
+		UpdatedAt:      timestamppb.New(userMembership.UpdatedAt),
+		OrganizationID: userMembership.OrganizationID,
+		RoleID:         userMembership.RoleID,
+		RoleName:       userMembership.RoleName,
+		Permissions:    userMembership.Permissions,
+		ValidFrom:      optionalTimestamp(userMembership.ValidFrom),
+		ValidUntil:     optionalTimestamp(userMembership.ValidUntil),
 	}
 }
 
@@ -105,17 +129,23 @@ func UserMembershipListToGrpc(userMemberships *UserMembershipsList) *queryServic
 }
 
 type GroupMembership struct {
-	ID           string                 `json:"id" bson:"_id,omitempty"`
-	UserID       string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
-	GroupID      string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
-	MembershipID string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
-	Name         string                 `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=500"`
-	Description  string                 `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
-	Status       enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
-	Role         enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
-	Creator      bool                   `json:"creator,omitempty" bson:"creator,omitempty"`
-	CreatedAt    time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt    time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string                 `json:"id" bson:"_id,omitempty"`
+	UserID         string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
+	GroupID        string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
+	MembershipID   string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
+	Name           string                 `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=500"`
+	Description    string                 `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
+	Status         enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
+	Role           enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
+	RoleID         string                 `json:"roleID,omitempty" bson:"role_id,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty" bson:"role_name,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty" bson:"permissions,omitempty"`
+	Creator        bool                   `json:"creator,omitempty" bson:"creator,omitempty"`
+	OrganizationID string                 `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 // GetID returns the unique identifier of the GroupMembership
@@ -135,16 +165,22 @@ type GroupMembershipsList struct {
 
 func GroupMembershipToGrpcMessage(groupMembership *GroupMembership) *queryService.GroupMembership {
 	return &queryService.GroupMembership{
-		ID:           groupMembership.ID,
-		UserID:       groupMembership.UserID,
-		GroupID:      groupMembership.GroupID,
-		MembershipID: groupMembership.MembershipID,
-		Name:         groupMembership.Name,
-		Description:  groupMembership.Description,
-		Status:       int64(groupMembership.Status.EnumIndex()),
-		Role:         int64(groupMembership.Role.EnumIndex()),
-		CreatedAt:    timestamppb.New(groupMembership.CreatedAt),
-		UpdatedAt:    timestamppb.New(groupMembership.UpdatedAt),
+		ID:             groupMembership.ID,
+		UserID:         groupMembership.UserID,
+		GroupID:        groupMembership.GroupID,
+		MembershipID:   groupMembership.MembershipID,
+		Name:           groupMembership.Name,
+		Description:    groupMembership.Description,
+		Status:         int64(groupMembership.Status.EnumIndex()),
+		Role:           int64(groupMembership.Role.EnumIndex()),
+		CreatedAt:      timestamppb.New(groupMembership.CreatedAt),
+		UpdatedAt:      timestamppb.New(groupMembership.UpdatedAt),
+		OrganizationID: groupMembership.OrganizationID,
+		RoleID:         groupMembership.RoleID,
+		RoleName:       groupMembership.RoleName,
+		Permissions:    groupMembership.Permissions,
+		ValidFrom:      optionalTimestamp(groupMembership.ValidFrom),
+		ValidUntil:     optionalTimestamp(groupMembership.ValidUntil),
 	}
 }
 
@@ -173,3 +209,70 @@ func GroupMembershipListToGrpc(groupMemberships *GroupMembershipsList) *querySer
 		GroupMemberships: list,
 	}
 }
+
+// EffectiveMembership is a group a user belongs to either directly or through the subgroups of the group. Path lists
+// the groups from the one the user is a direct member of up to GroupID
+type EffectiveMembership struct {
+	ID             string    `json:"id" bson:"_id,omitempty"`
+	UserID         string    `json:"userID,omitempty" bson:"user_id,omitempty"`
+	GroupID        string    `json:"groupID,omitempty" bson:"group_id,omitempty"`
+	Path           []string  `json:"path,omitempty" bson:"path,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+// GetID returns the unique identifier of the EffectiveMembership
+func (u *EffectiveMembership) GetID() string {
+	return u.ID
+}
+
+// NewEffectiveMembershipId keys the EffectiveMembership of a user in a group
+func NewEffectiveMembershipId(userID string, groupID string) string {
+	return userID + ":" + groupID
+}
+
+// EffectiveMembershipsList response with pagination
+type EffectiveMembershipsList struct {
+	TotalCount           int64                  `json:"totalCount" bson:"total_count"`
+	TotalPages           int64                  `json:"totalPages" bson:"total_pages"`
+	Page                 int64                  `json:"page" bson:"page"`
+	Size                 int64                  `json:"size" bson:"size"`
+	HasMore              bool                   `json:"hasMore" bson:"has_more"`
+	EffectiveMemberships []*EffectiveMembership `json:"effectiveMemberships" bson:"effective_memberships"`
+}
+
+func EffectiveMembershipToGrpcMessage(effectiveMembership *EffectiveMembership) *queryService.EffectiveMembership {
+	return &queryService.EffectiveMembership{
+		UserID:         effectiveMembership.UserID,
+		GroupID:        effectiveMembership.GroupID,
+		Path:           effectiveMembership.Path,
+		OrganizationID: effectiveMembership.OrganizationID,
+		UpdatedAt:      timestamppb.New(effectiveMembership.UpdatedAt),
+	}
+}
+
+func NewEffectiveMembershipListWithPagination(memberships []*EffectiveMembership, count int64, pagination *utilities.Pagination) *EffectiveMembershipsList {
+	return &EffectiveMembershipsList{
+		TotalCount:           count,
+		TotalPages:           int64(pagination.GetTotalPages(int(count))),
+		Page:                 int64(pagination.GetPage()),
+		Size:                 int64(pagination.GetSize()),
+		HasMore:              pagination.GetHasMore(int(count)),
+		EffectiveMemberships: memberships,
+	}
+}
+
+func EffectiveMembershipListToGrpc(effectiveMemberships *EffectiveMembershipsList) *queryService.GetEffectiveMembershipsRes {
+	list := make([]*queryService.EffectiveMembership, 0, len(effectiveMemberships.EffectiveMemberships))
+	for _, effectiveMembership := range effectiveMemberships.EffectiveMemberships {
+		list = append(list, EffectiveMembershipToGrpcMessage(effectiveMembership))
+	}
+	return &queryService.GetEffectiveMembershipsRes{
+		TotalCount:           effectiveMemberships.TotalCount,
+		TotalPages:           effectiveMemberships.TotalPages,
+		Page:                 effectiveMemberships.Page,
+		Size:                 effectiveMemberships.Size,
+		HasMore:              effectiveMemberships.HasMore,
+		EffectiveMemberships: list,
+	}
+}
@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"github.com/JECSand/identity-service/pkg/utilities"
+	queryService "github.com/JECSand/identity-service/query_service/protos/organization_query"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+type Organization struct {
+	ID                string    `json:"id" bson:"_id,omitempty"`
+	Name              string    `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=250"`
+	Description       string    `json:"description,omitempty" bson:"description,omitempty"`
+	Active            bool      `json:"active,omitempty" bson:"active,omitempty"`
+	SessionsRevokedAt time.Time `json:"sessionsRevokedAt,omitempty" bson:"sessions_revoked_at,omitempty"`
+	CreatedAt         time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt         time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+// GetID returns the unique identifier of the Organization
+func (u *Organization) GetID() string {
+	return u.ID
+}
+
+// OrganizationsList response with pagination
+type OrganizationsList struct {
+	TotalCount    int64           `json:"totalCount" bson:"total_count"`
+	TotalPages    int64           `json:"totalPages" bson:"total_pages"`
+	Page          int64           `json:"page" bson:"page"`
+	Size          int64           `json:"size" bson:"size"`
+	HasMore       bool            `json:"hasMore" bson:"has_more"`
+	Organizations []*Organization `json:"organizations" bson:"organizations"`
+}
+
+func NewOrganizationListWithPagination(organizations []*Organization, count int64, pagination *utilities.Pagination) *OrganizationsList {
+	return &OrganizationsList{
+		TotalCount:    count,
+		TotalPages:    int64(pagination.GetTotalPages(int(count))),
+		Page:          int64(pagination.GetPage()),
+		Size:          int64(pagination.GetSize()),
+		HasMore:       pagination.GetHasMore(int(count)),
+		Organizations: organizations,
+	}
+}
+
+func OrganizationToGrpcMessage(organization *Organization) *queryService.Organization {
+	return &queryService.Organization{
+		ID:          organization.ID,
+		Name:        organization.Name,
+		Description: organization.Description,
+		Active:      organization.Active,
+		CreatedAt:   timestamppb.New(organization.CreatedAt),
+		UpdatedAt:   timestamppb.New(organization.UpdatedAt),
+	}
+}
+
+func OrganizationListToGrpc(organizations *OrganizationsList) *queryService.SearchOrganizationRes {
+	list := make([]*queryService.Organization, 0, len(organizations.Organizations))
+	for _, organization := range organizations.Organizations {
+		list = append(list, OrganizationToGrpcMessage(organization))
+	}
+	return &queryService.SearchOrganizationRes{
+		TotalCount:    organizations.TotalCount,
+		TotalPages:    organizations.TotalPages,
+		Page:          organizations.Page,
+		Size:          organizations.Size,
+		HasMore:       organizations.HasMore,
+		Organizations: list,
+	}
+}
@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"github.com/JECSand/identity-service/pkg/utilities"
+	queryService "github.com/JECSand/identity-service/query_service/protos/role_query"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// Role is a named permission set granted through memberships. Built-in roles mirror the Role enum, custom roles are
+// scoped to an organization and optionally to one of its groups
+type Role struct {
+	ID             string    `json:"id" bson:"_id,omitempty"`
+	Name           string    `json:"name,omitempty" bson:"name,omitempty"`
+	Description    string    `json:"description,omitempty" bson:"description,omitempty"`
+	Permissions    []string  `json:"permissions" bson:"permissions"`
+	GroupID        string    `json:"groupID,omitempty" bson:"group_id,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	BuiltIn        bool      `json:"builtIn,omitempty" bson:"built_in,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+// GetID returns the unique identifier of the Role
+func (u *Role) GetID() string {
+	return u.ID
+}
+
+// RolesList response with pagination
+type RolesList struct {
+	TotalCount int64   `json:"totalCount" bson:"total_count"`
+	TotalPages int64   `json:"totalPages" bson:"total_pages"`
+	Page       int64   `json:"page" bson:"page"`
+	Size       int64   `json:"size" bson:"size"`
+	HasMore    bool    `json:"hasMore" bson:"has_more"`
+	Roles      []*Role `json:"roles" bson:"roles"`
+}
+
+func NewRoleListWithPagination(roles []*Role, count int64, pagination *utilities.Pagination) *RolesList {
+	return &RolesList{
+		TotalCount: count,
+		TotalPages: int64(pagination.GetTotalPages(int(count))),
+		Page:       int64(pagination.GetPage()),
+		Size:       int64(pagination.GetSize()),
+		HasMore:    pagination.GetHasMore(int(count)),
+		Roles:      roles,
+	}
+}
+
+func RoleToGrpcMessage(role *Role) *queryService.Role {
+	return &queryService.Role{
+		ID:             role.ID,
+		Name:           role.Name,
+		Description:    role.Description,
+		Permissions:    role.Permissions,
+		GroupID:        role.GroupID,
+		OrganizationID: role.OrganizationID,
+		BuiltIn:        role.BuiltIn,
+		CreatedAt:      timestamppb.New(role.CreatedAt),
+		UpdatedAt:      timestamppb.New(role.UpdatedAt),
+	}
+}
+
+func RoleListToGrpc(roles *RolesList) *queryService.SearchRolesRes {
+	list := make([]*queryService.Role, 0, len(roles.Roles))
+	for _, role := range roles.Roles {
+		list = append(list, RoleToGrpcMessage(role))
+	}
+	return &queryService.SearchRolesRes{
+		TotalCount: roles.TotalCount,
+		TotalPages: roles.TotalPages,
+		Page:       roles.Page,
+		Size:       roles.Size,
+		HasMore:    roles.HasMore,
+		Roles:      list,
+	}
+}
@@ -1,25 +1,29 @@
 package entities
 
 import (
-	"errors"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/utilities"
 	authQueryService "github.com/JECSand/identity-service/query_service/protos/auth_query"
 	queryService "github.com/JECSand/identity-service/query_service/protos/user_query"
 
-	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"time"
 )
 
 type User struct {
-	ID        string    `json:"id" bson:"_id,omitempty"`
-	Email     string    `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=250"`
-	Username  string    `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
-	Password  string    `json:"password,omitempty" bson:"password,omitempty" validate:"required"`
-	Root      bool      `json:"root,omitempty" bson:"root,omitempty"`
-	Active    bool      `json:"active,omitempty" bson:"active,omitempty"`
-	CreatedAt time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID                string           `json:"id" bson:"_id,omitempty"`
+	Email             string           `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=250"`
+	Username          string           `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
+	Root              bool             `json:"root,omitempty" bson:"root,omitempty"`
+	Active            bool             `json:"active,omitempty" bson:"active,omitempty"`
+	Status            enums.UserStatus `json:"status,omitempty" bson:"status,omitempty"`
+	StatusReason      string           `json:"statusReason,omitempty" bson:"status_reason,omitempty"`
+	SuspendedUntil    time.Time        `json:"suspendedUntil,omitempty" bson:"suspended_until,omitempty"`
+	SessionsRevokedAt time.Time        `json:"sessionsRevokedAt,omitempty" bson:"sessions_revoked_at,omitempty"`
+	OrganizationID    string           `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	OrgAdmin          bool             `json:"orgAdmin,omitempty" bson:"org_admin,omitempty"`
+	CreatedAt         time.Time        `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt         time.Time        `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 // GetID returns the unique identifier of the User
@@ -27,14 +31,20 @@ func (u *User) GetID() string {
 	return u.ID
 }
 
-// Authenticate compares an input password with the hashed password stored in the User model
-func (u *User) Authenticate(checkPassword string) error {
-	if len(u.Password) != 0 {
-		password := []byte(u.Password)
-		cPassword := []byte(checkPassword)
-		return bcrypt.CompareHashAndPassword(password, cPassword)
+// LifecycleStatus returns the User Status, derived from Active for users projected before lifecycle states
+func (u *User) LifecycleStatus() enums.UserStatus {
+	if u.Status == 0 {
+		return enums.UserStatusFromActive(u.Active)
 	}
-	return errors.New("user password is missing")
+	return u.Status
+}
+
+// optionalTimestamp maps the zero time to an unset timestamp
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
 }
 
 // UsersList response with pagination
@@ -60,27 +70,35 @@ func NewUserListWithPagination(users []*User, count int64, pagination *utilities
 
 func AuthUserToGrpcMessage(user *User) *authQueryService.User {
 	return &authQueryService.User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Password:  user.Password,
-		Root:      user.Root,
-		Active:    user.Active,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		ID:             user.ID,
+		Email:          user.Email,
+		Username:       user.Username,
+		Root:           user.Root,
+		Active:         user.Active,
+		CreatedAt:      timestamppb.New(user.CreatedAt),
+		UpdatedAt:      timestamppb.New(user.UpdatedAt),
+		Status:         user.LifecycleStatus().Stringify(),
+		StatusReason:   user.StatusReason,
+		SuspendedUntil: optionalTimestamp(user.SuspendedUntil),
+		OrganizationID: user.OrganizationID,
+		OrgAdmin:       user.OrgAdmin,
 	}
 }
 
 func UserToGrpcMessage(user *User) *queryService.User {
 	return &queryService.User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Password:  user.Password,
-		Root:      user.Root,
-		Active:    user.Active,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		ID:             user.ID,
+		Email:          user.Email,
+		Username:       user.Username,
+		Root:           user.Root,
+		Active:         user.Active,
+		CreatedAt:      timestamppb.New(user.CreatedAt),
+		UpdatedAt:      timestamppb.New(user.UpdatedAt),
+		Status:         user.LifecycleStatus().Stringify(),
+		StatusReason:   user.StatusReason,
+		SuspendedUntil: optionalTimestamp(user.SuspendedUntil),
+		OrganizationID: user.OrganizationID,
+		OrgAdmin:       user.OrgAdmin,
 	}
 }
 
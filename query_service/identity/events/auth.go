@@ -36,15 +36,13 @@ func NewBlacklistTokenEvent(id string, accessToken string, createdAt time.Time,
 }
 
 type UpdatePasswordEvent struct {
-	ID          string    `json:"id" bson:"_id,omitempty"`
-	NewPassword string    `json:"newPassword,omitempty" bson:"new_password,omitempty" validate:"required,min=3,max=250"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
-func NewUpdatePasswordEvent(id string, newPassword string, up time.Time) *UpdatePasswordEvent {
+func NewUpdatePasswordEvent(id string, up time.Time) *UpdatePasswordEvent {
 	return &UpdatePasswordEvent{
-		ID:          id,
-		NewPassword: newPassword,
-		UpdatedAt:   up,
+		ID:        id,
+		UpdatedAt: up,
 	}
 }
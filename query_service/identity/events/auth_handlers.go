@@ -3,11 +3,12 @@ package events
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
+	"time"
 )
 
 // BlacklistTokenEventHandler ...
@@ -37,8 +38,8 @@ func NewBlacklistTokenEventHandler(
 }
 
 func (c *blacklistTokenEventHandler) Handle(ctx context.Context, event *BlacklistTokenEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "blacklistTokenEventHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "blacklistTokenEventHandler.Handle")
+	defer span.End()
 	user := &entities.Blacklist{
 		ID:          event.ID,
 		AccessToken: event.AccessToken,
@@ -49,7 +50,10 @@ func (c *blacklistTokenEventHandler) Handle(ctx context.Context, event *Blacklis
 	if err != nil {
 		return err
 	}
-	c.redisCache.PutToken(ctx, created.ID, created)
+	// version the cache entry at processing time so it supersedes any cached "not blacklisted" result
+	created.UpdatedAt = time.Now()
+	c.redisCache.PutToken(ctx, created.AccessToken, created)
+	c.redisCache.Invalidate(ctx, cache.TokenEntity, created.AccessToken)
 	return nil
 }
 
@@ -80,11 +84,10 @@ func NewUpdatePasswordEventHandler(
 }
 
 func (c *updatePasswordEventHandler) Handle(ctx context.Context, event *UpdatePasswordEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updatePasswordEventHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "updatePasswordEventHandler.Handle")
+	defer span.End()
 	user := &entities.User{
 		ID:        event.ID,
-		Password:  event.NewPassword,
 		UpdatedAt: event.UpdatedAt,
 	}
 	updated, err := c.mongoDB.UpdateUser(ctx, user)
@@ -92,5 +95,6 @@ func (c *updatePasswordEventHandler) Handle(ctx context.Context, event *UpdatePa
 		return err
 	}
 	c.redisCache.PutUser(ctx, updated.ID, updated)
+	c.redisCache.Invalidate(ctx, cache.UserEntity, updated.ID)
 	return nil
 }
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// refreshEffectiveMemberships recomputes the groups each user belongs to directly or through the subgroups of a group,
+// keeping the shortest path granting each of them. Only ACTIVE memberships within their validity window are effective.
+func refreshEffectiveMemberships(ctx context.Context, mongoDB data.Database, userIDs ...string) error {
+	groups := make(map[string]*entities.Group)
+	refreshed := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		id, err := uuid.FromString(userID)
+		if err != nil {
+			return errors.Wrap(err, "uuid.FromString")
+		}
+		if refreshed[id.String()] {
+			continue
+		}
+		refreshed[id.String()] = true
+		memberships, err := mongoDB.GetMembershipsByUserId(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "GetMembershipsByUserId")
+		}
+		effective := make(map[string]*entities.EffectiveMembership)
+		for _, membership := range memberships {
+			if authentication.EffectiveMembershipStatus(membership.Status, membership.ValidFrom, membership.ValidUntil, time.Now()) != enums.ACTIVE {
+				continue
+			}
+			path := make([]string, 0)
+			for groupID := uuid.FromStringOrNil(membership.GroupID); groupID != uuid.Nil; {
+				if containsID(path, groupID.String()) {
+					break
+				}
+				group, ok := groups[groupID.String()]
+				if !ok {
+					group, err = mongoDB.GetGroupById(ctx, groupID)
+					if errors.Is(err, mongo.ErrNoDocuments) {
+						break
+					}
+					if err != nil {
+						return errors.Wrap(err, "GetGroupById")
+					}
+					groups[groupID.String()] = group
+				}
+				path = append(path, groupID.String())
+				if found, ok := effective[groupID.String()]; !ok || len(path) < len(found.Path) {
+					effective[groupID.String()] = &entities.EffectiveMembership{
+						UserID:         id.String(),
+						GroupID:        groupID.String(),
+						Path:           append([]string(nil), path...),
+						OrganizationID: group.OrganizationID,
+						UpdatedAt:      time.Now().UTC(),
+					}
+				}
+				groupID = uuid.FromStringOrNil(group.ParentID)
+			}
+		}
+		list := make([]*entities.EffectiveMembership, 0, len(effective))
+		for _, m := range effective {
+			list = append(list, m)
+		}
+		if err = mongoDB.ReplaceEffectiveMemberships(ctx, id, list); err != nil {
+			return errors.Wrap(err, "ReplaceEffectiveMemberships")
+		}
+	}
+	return nil
+}
+
+func containsID(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/cache"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// effectivePaths returns the paths of the effective memberships of userID keyed by group id
+func effectivePaths(t *testing.T, db data.Database, userID uuid.UUID) map[string][]string {
+	t.Helper()
+	list, err := db.GetEffectiveMembershipByUserId(context.Background(), userID, "", utilities.NewPaginationQuery(100, 1))
+	if err != nil {
+		t.Fatalf("GetEffectiveMembershipByUserId: %v", err)
+	}
+	paths := make(map[string][]string, len(list.EffectiveMemberships))
+	for _, m := range list.EffectiveMemberships {
+		paths[m.GroupID] = m.Path
+	}
+	return paths
+}
+
+func TestChangeGroupParentRebuildsEffectiveMemberships(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	db := data.NewMemoryDatabase(log, cfg)
+	id := func() string { return uuid.Must(uuid.NewV4()).String() }
+	org := id()
+	finance, payables, operations := id(), id(), id()
+	for _, group := range []*entities.Group{
+		{ID: finance, Name: "finance", OrganizationID: org, Active: true},
+		{ID: payables, Name: "payables", OrganizationID: org, Active: true, ParentID: finance},
+		{ID: operations, Name: "operations", OrganizationID: org, Active: true},
+	} {
+		if _, err := db.CreateGroup(ctx, group); err != nil {
+			t.Fatalf("CreateGroup: %v", err)
+		}
+	}
+	user, other := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	for _, membership := range []*entities.Membership{
+		{ID: id(), UserID: user.String(), GroupID: payables, Status: enums.ACTIVE, OrganizationID: org},
+		{ID: id(), UserID: other.String(), GroupID: operations, Status: enums.ACTIVE, OrganizationID: org},
+		{ID: id(), UserID: other.String(), GroupID: payables, Status: enums.ACTIVE, OrganizationID: org, ValidUntil: time.Now().Add(-time.Hour)},
+	} {
+		if _, err := db.CreateMembership(ctx, membership); err != nil {
+			t.Fatalf("CreateMembership: %v", err)
+		}
+	}
+	if err := refreshEffectiveMemberships(ctx, db, user.String(), other.String()); err != nil {
+		t.Fatalf("refreshEffectiveMemberships: %v", err)
+	}
+	h := NewChangeGroupParentEventHandler(log, cfg, db, cache.NewMemoryCache(log, cfg))
+	tests := []struct {
+		name      string
+		group     string
+		parent    string
+		wantUser  map[string][]string
+		wantOther map[string][]string
+	}{
+		{
+			name:      "before any move",
+			wantUser:  map[string][]string{payables: {payables}, finance: {payables, finance}},
+			wantOther: map[string][]string{operations: {operations}},
+		},
+		{
+			name:      "move below another root",
+			group:     payables,
+			parent:    operations,
+			wantUser:  map[string][]string{payables: {payables}, operations: {payables, operations}},
+			wantOther: map[string][]string{operations: {operations}},
+		},
+		{
+			name:      "move the new parent below a group",
+			group:     operations,
+			parent:    finance,
+			wantUser:  map[string][]string{payables: {payables}, operations: {payables, operations}, finance: {payables, operations, finance}},
+			wantOther: map[string][]string{operations: {operations}, finance: {operations, finance}},
+		},
+		{
+			name:      "move to the top level",
+			group:     payables,
+			wantUser:  map[string][]string{payables: {payables}},
+			wantOther: map[string][]string{operations: {operations}, finance: {operations, finance}},
+		},
+	}
+	for _, tt := range tests {
+		if tt.group != "" {
+			if err := h.Handle(ctx, NewChangeGroupParentEvent(uuid.FromStringOrNil(tt.group), tt.parent, time.Now())); err != nil {
+				t.Fatalf("%s: Handle: %v", tt.name, err)
+			}
+		}
+		if got := effectivePaths(t, db, user); !reflect.DeepEqual(got, tt.wantUser) {
+			t.Errorf("%s: got user paths %v, want %v", tt.name, got, tt.wantUser)
+		}
+		if got := effectivePaths(t, db, other); !reflect.DeepEqual(got, tt.wantOther) {
+			t.Errorf("%s: got other paths %v, want %v", tt.name, got, tt.wantOther)
+		}
+	}
+}
@@ -6,42 +6,49 @@ import (
 )
 
 type GroupEvents struct {
-	CreateGroup CreateGroupEventHandler
-	UpdateGroup UpdateGroupEventHandler
-	DeleteGroup DeleteGroupEventHandler
+	CreateGroup       CreateGroupEventHandler
+	UpdateGroup       UpdateGroupEventHandler
+	ChangeGroupParent ChangeGroupParentEventHandler
+	DeleteGroup       DeleteGroupEventHandler
 }
 
 func NewGroupEvents(
 	createGroup CreateGroupEventHandler,
 	updateGroup UpdateGroupEventHandler,
+	changeGroupParent ChangeGroupParentEventHandler,
 	deleteGroup DeleteGroupEventHandler,
 ) *GroupEvents {
 	return &GroupEvents{
-		CreateGroup: createGroup,
-		UpdateGroup: updateGroup,
-		DeleteGroup: deleteGroup,
+		CreateGroup:       createGroup,
+		UpdateGroup:       updateGroup,
+		ChangeGroupParent: changeGroupParent,
+		DeleteGroup:       deleteGroup,
 	}
 }
 
 type CreateGroupEvent struct {
-	ID          string    `json:"id" bson:"_id,omitempty"`
-	Name        string    `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=250"`
-	Description string    `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
-	CreatorID   string    `json:"creatorID,omitempty" bson:"creator_id,omitempty" validate:"required"`
-	Active      bool      `json:"active,omitempty" bson:"active,omitempty"`
-	CreatedAt   time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string    `json:"id" bson:"_id,omitempty"`
+	Name           string    `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=250"`
+	Description    string    `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
+	CreatorID      string    `json:"creatorID,omitempty" bson:"creator_id,omitempty" validate:"required"`
+	Active         bool      `json:"active,omitempty" bson:"active,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ParentID       string    `json:"parentID,omitempty" bson:"parent_id,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
-func NewCreateGroupEvent(id string, name string, description string, creatorID string, active bool, createdAt time.Time, updatedAt time.Time) *CreateGroupEvent {
+func NewCreateGroupEvent(id string, name string, description string, creatorID string, active bool, organizationID string, parentID string, createdAt time.Time, updatedAt time.Time) *CreateGroupEvent {
 	return &CreateGroupEvent{
-		ID:          id,
-		Name:        name,
-		Description: description,
-		CreatorID:   creatorID,
-		Active:      active,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
+		ID:             id,
+		Name:           name,
+		Description:    description,
+		CreatorID:      creatorID,
+		Active:         active,
+		OrganizationID: organizationID,
+		ParentID:       parentID,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
 	}
 }
 
@@ -61,6 +68,20 @@ func NewUpdateGroupEvent(id string, name string, description string, updatedAt t
 	}
 }
 
+type ChangeGroupParentEvent struct {
+	ID        uuid.UUID `json:"id" bson:"_id,omitempty"`
+	ParentID  string    `json:"parentID,omitempty" bson:"parent_id,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+func NewChangeGroupParentEvent(id uuid.UUID, parentID string, updatedAt time.Time) *ChangeGroupParentEvent {
+	return &ChangeGroupParentEvent{
+		ID:        id,
+		ParentID:  parentID,
+		UpdatedAt: updatedAt,
+	}
+}
+
 type DeleteGroupEvent struct {
 	ID uuid.UUID `json:"id" bson:"_id,omitempty"`
 }
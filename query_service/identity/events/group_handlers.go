@@ -3,11 +3,12 @@ package events
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 )
 
 // CreateGroupEventHandler ...
@@ -32,16 +33,18 @@ func NewCreateGroupEventHandler(log logging.Logger, cfg *config.Config, mongoDB
 }
 
 func (c *createGroupEventHandler) Handle(ctx context.Context, event *CreateGroupEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createGroupEventHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createGroupEventHandler.Handle")
+	defer span.End()
 	group := &entities.Group{
-		ID:          event.ID,
-		Name:        event.Name,
-		Description: event.Description,
-		CreatorID:   event.CreatorID,
-		Active:      event.Active,
-		CreatedAt:   event.CreatedAt,
-		UpdatedAt:   event.UpdatedAt,
+		ID:             event.ID,
+		Name:           event.Name,
+		Description:    event.Description,
+		CreatorID:      event.CreatorID,
+		Active:         event.Active,
+		OrganizationID: event.OrganizationID,
+		ParentID:       event.ParentID,
+		CreatedAt:      event.CreatedAt,
+		UpdatedAt:      event.UpdatedAt,
 	}
 	created, err := c.mongoDB.CreateGroup(ctx, group)
 	if err != nil {
@@ -73,11 +76,11 @@ func NewUpdateGroupEventHandler(log logging.Logger, cfg *config.Config, mongoDB
 }
 
 func (c *updateGroupEventHandler) Handle(ctx context.Context, event *UpdateGroupEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateGroupEventHandler.Handle")
+	ctx, span := tracing.StartSpan(ctx, "updateGroupEventHandler.Handle")
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 	}()
@@ -96,6 +99,7 @@ func (c *updateGroupEventHandler) Handle(ctx context.Context, event *UpdateGroup
 		}
 		if err == nil {
 			c.redisCache.PutGroup(ctx, updated.ID, updated)
+			c.redisCache.Invalidate(ctx, cache.GroupEntity, updated.ID)
 		}
 		errChan <- err
 	}()
@@ -128,6 +132,44 @@ func (c *updateGroupEventHandler) Handle(ctx context.Context, event *UpdateGroup
 	return err
 }
 
+// ChangeGroupParentEventHandler ...
+type ChangeGroupParentEventHandler interface {
+	Handle(ctx context.Context, event *ChangeGroupParentEvent) error
+}
+
+type changeGroupParentEventHandler struct {
+	log        logging.Logger
+	cfg        *config.Config
+	mongoDB    data.Database
+	redisCache cache.Cache
+}
+
+func NewChangeGroupParentEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *changeGroupParentEventHandler {
+	return &changeGroupParentEventHandler{
+		log:        log,
+		cfg:        cfg,
+		mongoDB:    mongoDB,
+		redisCache: redisCache,
+	}
+}
+
+// Handle moves the group and recomputes the effective memberships of every user below it
+func (c *changeGroupParentEventHandler) Handle(ctx context.Context, event *ChangeGroupParentEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "changeGroupParentEventHandler.Handle")
+	defer span.End()
+	userIDs, err := c.mongoDB.GetEffectiveMemberIds(ctx, event.ID)
+	if err != nil {
+		return errors.Wrap(err, "GetEffectiveMemberIds")
+	}
+	updated, err := c.mongoDB.SetGroupParent(ctx, event.ID, event.ParentID)
+	if err != nil {
+		return errors.Wrap(err, "SetGroupParent")
+	}
+	c.redisCache.PutGroup(ctx, updated.ID, updated)
+	c.redisCache.Invalidate(ctx, cache.GroupEntity, updated.ID)
+	return refreshEffectiveMemberships(ctx, c.mongoDB, userIDs...)
+}
+
 // DeleteGroupEventHandler ...
 type DeleteGroupEventHandler interface {
 	Handle(ctx context.Context, event *DeleteGroupEvent) error
@@ -150,14 +192,18 @@ func NewDeleteGroupEventHandler(log logging.Logger, cfg *config.Config, mongoDB
 }
 
 func (c *deleteGroupEventHandler) Handle(ctx context.Context, event *DeleteGroupEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteGroupEventHandler.Handle")
+	ctx, span := tracing.StartSpan(ctx, "deleteGroupEventHandler.Handle")
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 	}()
+	userIDs, err := c.mongoDB.GetEffectiveMemberIds(ctx, event.ID)
+	if err != nil {
+		return errors.Wrap(err, "GetEffectiveMemberIds")
+	}
 	go func() {
 		err := c.mongoDB.DeleteGroup(ctx, event.ID)
 		select {
@@ -167,6 +213,7 @@ func (c *deleteGroupEventHandler) Handle(ctx context.Context, event *DeleteGroup
 		}
 		if err == nil {
 			c.redisCache.DeleteGroup(ctx, event.ID.String())
+			c.redisCache.Invalidate(ctx, cache.GroupEntity, event.ID.String())
 		}
 		errChan <- err
 	}()
@@ -210,7 +257,6 @@ func (c *deleteGroupEventHandler) Handle(ctx context.Context, event *DeleteGroup
 		}
 		errChan <- err
 	}()
-	var err error
 	for i := 0; i < 4; i++ {
 		select {
 		case err = <-errChan:
@@ -219,5 +265,19 @@ func (c *deleteGroupEventHandler) Handle(ctx context.Context, event *DeleteGroup
 			}
 		}
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	if err = c.mongoDB.DeleteRoles(ctx, &entities.Role{GroupID: event.ID.String()}); err != nil {
+		return errors.Wrap(err, "DeleteRoles")
+	}
+	children, err := c.mongoDB.DetachChildGroups(ctx, event.ID)
+	if err != nil {
+		return errors.Wrap(err, "DetachChildGroups")
+	}
+	for _, child := range children {
+		c.redisCache.PutGroup(ctx, child.ID, child)
+		c.redisCache.Invalidate(ctx, cache.GroupEntity, child.ID)
+	}
+	return refreshEffectiveMemberships(ctx, c.mongoDB, userIDs...)
 }
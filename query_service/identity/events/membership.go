@@ -25,16 +25,22 @@ func NewMembershipEvents(
 }
 
 type CreatedUserMembership struct {
-	ID           string                 `json:"id" bson:"_id,omitempty" validate:"required"`
-	GroupID      string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=250"`
-	UserID       string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=500"`
-	MembershipID string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
-	Email        string                 `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=500"`
-	Username     string                 `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
-	Status       enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
-	Role         enums.Role             `json:"role,omitempty" bson:"role,omitempty" validate:"required"`
-	CreatedAt    time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt    time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string                 `json:"id" bson:"_id,omitempty" validate:"required"`
+	GroupID        string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=250"`
+	UserID         string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=500"`
+	MembershipID   string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
+	Email          string                 `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=500"`
+	Username       string                 `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
+	Status         enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
+	Role           enums.Role             `json:"role,omitempty" bson:"role,omitempty" validate:"required"`
+	RoleID         string                 `json:"roleID,omitempty" bson:"role_id,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty" bson:"role_name,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty" bson:"permissions,omitempty"`
+	OrganizationID string                 `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 func NewCreatedUserMembership(
@@ -46,35 +52,53 @@ func NewCreatedUserMembership(
 	username string,
 	status enums.MembershipStatus,
 	role enums.Role,
+	roleID string,
+	roleName string,
+	permissions []string,
+	organizationID string,
+	validFrom time.Time,
+	validUntil time.Time,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) *CreatedUserMembership {
 	return &CreatedUserMembership{
-		ID:           id,
-		GroupID:      groupID,
-		UserID:       userID,
-		MembershipID: membershipID,
-		Email:        email,
-		Username:     username,
-		Status:       status,
-		Role:         role,
-		CreatedAt:    createdAt,
-		UpdatedAt:    updatedAt,
+		ID:             id,
+		GroupID:        groupID,
+		UserID:         userID,
+		MembershipID:   membershipID,
+		Email:          email,
+		Username:       username,
+		Status:         status,
+		Role:           role,
+		RoleID:         roleID,
+		RoleName:       roleName,
+		Permissions:    permissions,
+		OrganizationID: organizationID,
+		ValidFrom:      validFrom,
+		ValidUntil:     validUntil,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
 	}
 }
 
 type CreatedGroupMembership struct {
-	ID           string                 `json:"id" bson:"_id,omitempty" validate:"required"`
-	UserID       string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
-	GroupID      string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
-	MembershipID string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
-	Name         string                 `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=500"`
-	Description  string                 `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
-	Status       enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
-	Role         enums.Role             `json:"role,omitempty" bson:"role,omitempty" validate:"required"`
-	Creator      bool                   `json:"creator,omitempty" bson:"creator,omitempty"`
-	CreatedAt    time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt    time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string                 `json:"id" bson:"_id,omitempty" validate:"required"`
+	UserID         string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
+	GroupID        string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
+	MembershipID   string                 `json:"membershipID,omitempty" bson:"membership_id,omitempty" validate:"required,min=3,max=500"`
+	Name           string                 `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=500"`
+	Description    string                 `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
+	Status         enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
+	Role           enums.Role             `json:"role,omitempty" bson:"role,omitempty" validate:"required"`
+	RoleID         string                 `json:"roleID,omitempty" bson:"role_id,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty" bson:"role_name,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty" bson:"permissions,omitempty"`
+	Creator        bool                   `json:"creator,omitempty" bson:"creator,omitempty"`
+	OrganizationID string                 `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 func NewCreatedGroupMembership(
@@ -86,33 +110,51 @@ func NewCreatedGroupMembership(
 	description string,
 	status enums.MembershipStatus,
 	role enums.Role,
+	roleID string,
+	roleName string,
+	permissions []string,
 	creator bool,
+	organizationID string,
+	validFrom time.Time,
+	validUntil time.Time,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) *CreatedGroupMembership {
 	return &CreatedGroupMembership{
-		ID:           id,
-		GroupID:      groupID,
-		UserID:       userID,
-		MembershipID: membershipID,
-		Name:         name,
-		Description:  description,
-		Status:       status,
-		Role:         role,
-		Creator:      creator,
-		CreatedAt:    createdAt,
-		UpdatedAt:    updatedAt,
+		ID:             id,
+		GroupID:        groupID,
+		UserID:         userID,
+		MembershipID:   membershipID,
+		Name:           name,
+		Description:    description,
+		Status:         status,
+		Role:           role,
+		RoleID:         roleID,
+		RoleName:       roleName,
+		Permissions:    permissions,
+		Creator:        creator,
+		OrganizationID: organizationID,
+		ValidFrom:      validFrom,
+		ValidUntil:     validUntil,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
 	}
 }
 
 type CreatedMembership struct {
-	ID        string                 `json:"id" bson:"_id,omitempty" validate:"required"`
-	UserID    string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
-	GroupID   string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
-	Status    enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
-	Role      enums.Role             `json:"role,omitempty" bson:"role,omitempty" validate:"required"`
-	CreatedAt time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string                 `json:"id" bson:"_id,omitempty" validate:"required"`
+	UserID         string                 `json:"userID,omitempty" bson:"user_id,omitempty" validate:"required,min=3,max=250"`
+	GroupID        string                 `json:"groupID,omitempty" bson:"group_id,omitempty" validate:"required,min=3,max=500"`
+	Status         enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty" validate:"required"`
+	Role           enums.Role             `json:"role,omitempty" bson:"role,omitempty" validate:"required"`
+	RoleID         string                 `json:"roleID,omitempty" bson:"role_id,omitempty"`
+	RoleName       string                 `json:"roleName,omitempty" bson:"role_name,omitempty"`
+	Permissions    []string               `json:"permissions,omitempty" bson:"permissions,omitempty"`
+	OrganizationID string                 `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	ValidFrom      time.Time              `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil     time.Time              `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
 func NewCreatedMembership(
@@ -121,17 +163,29 @@ func NewCreatedMembership(
 	groupID string,
 	status enums.MembershipStatus,
 	role enums.Role,
+	roleID string,
+	roleName string,
+	permissions []string,
+	organizationID string,
+	validFrom time.Time,
+	validUntil time.Time,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) *CreatedMembership {
 	return &CreatedMembership{
-		ID:        id,
-		UserID:    userID,
-		GroupID:   groupID,
-		Status:    status,
-		Role:      role,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:             id,
+		UserID:         userID,
+		GroupID:        groupID,
+		Status:         status,
+		Role:           role,
+		RoleID:         roleID,
+		RoleName:       roleName,
+		Permissions:    permissions,
+		OrganizationID: organizationID,
+		ValidFrom:      validFrom,
+		ValidUntil:     validUntil,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
 	}
 }
 
@@ -150,18 +204,28 @@ func NewCreateMembershipEvent(membership *CreatedMembership, userMembership *Cre
 }
 
 type UpdateMembershipEvent struct {
-	ID        string                 `json:"id" bson:"_id,omitempty" validate:"required"`
-	Status    enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty"`
-	Role      enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
-	UpdatedAt time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID          string                 `json:"id" bson:"_id,omitempty" validate:"required"`
+	Status      enums.MembershipStatus `json:"status,omitempty" bson:"status,omitempty"`
+	Role        enums.Role             `json:"role,omitempty" bson:"role,omitempty"`
+	RoleID      string                 `json:"roleID,omitempty" bson:"role_id,omitempty"`
+	RoleName    string                 `json:"roleName,omitempty" bson:"role_name,omitempty"`
+	Permissions []string               `json:"permissions,omitempty" bson:"permissions,omitempty"`
+	ValidFrom   time.Time              `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil  time.Time              `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+	UpdatedAt   time.Time              `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
-func NewUpdateMembershipEvent(id string, status enums.MembershipStatus, role enums.Role, updatedAt time.Time) *UpdateMembershipEvent {
+func NewUpdateMembershipEvent(id string, status enums.MembershipStatus, role enums.Role, roleID string, roleName string, permissions []string, validFrom time.Time, validUntil time.Time, updatedAt time.Time) *UpdateMembershipEvent {
 	return &UpdateMembershipEvent{
-		ID:        id,
-		Status:    status,
-		Role:      role,
-		UpdatedAt: updatedAt,
+		ID:          id,
+		Status:      status,
+		Role:        role,
+		RoleID:      roleID,
+		RoleName:    roleName,
+		Permissions: permissions,
+		ValidFrom:   validFrom,
+		ValidUntil:  validUntil,
+		UpdatedAt:   updatedAt,
 	}
 }
 
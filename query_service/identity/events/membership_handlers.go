@@ -3,11 +3,12 @@ package events
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
+	"github.com/gofrs/uuid"
 )
 
 // CreateMembershipEventHandler ...
@@ -32,23 +33,29 @@ func NewCreateMembershipEventHandler(log logging.Logger, cfg *config.Config, mon
 }
 
 func (c *createMembershipEventHandler) Handle(ctx context.Context, event *CreateMembershipEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createMembershipEventHandler.Handle")
+	ctx, span := tracing.StartSpan(ctx, "createMembershipEventHandler.Handle")
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 	}()
 	go func() {
 		membership := &entities.Membership{
-			ID:        event.Membership.ID,
-			UserID:    event.Membership.UserID,
-			GroupID:   event.Membership.GroupID,
-			Status:    event.Membership.Status,
-			Role:      event.Membership.Role,
-			CreatedAt: event.Membership.CreatedAt,
-			UpdatedAt: event.Membership.UpdatedAt,
+			ID:             event.Membership.ID,
+			UserID:         event.Membership.UserID,
+			GroupID:        event.Membership.GroupID,
+			Status:         event.Membership.Status,
+			Role:           event.Membership.Role,
+			RoleID:         event.Membership.RoleID,
+			RoleName:       event.Membership.RoleName,
+			Permissions:    event.Membership.Permissions,
+			OrganizationID: event.Membership.OrganizationID,
+			ValidFrom:      event.Membership.ValidFrom,
+			ValidUntil:     event.Membership.ValidUntil,
+			CreatedAt:      event.Membership.CreatedAt,
+			UpdatedAt:      event.Membership.UpdatedAt,
 		}
 		createdMembership, err := c.mongoDB.CreateMembership(ctx, membership)
 		select {
@@ -63,16 +70,22 @@ func (c *createMembershipEventHandler) Handle(ctx context.Context, event *Create
 	}()
 	go func() {
 		userMembership := &entities.UserMembership{
-			ID:           event.UserMembership.ID,
-			GroupID:      event.UserMembership.GroupID,
-			UserID:       event.UserMembership.UserID,
-			MembershipID: event.UserMembership.MembershipID,
-			Email:        event.UserMembership.Email,
-			Username:     event.UserMembership.Username,
-			Status:       event.UserMembership.Status,
-			Role:         event.UserMembership.Role,
-			CreatedAt:    event.UserMembership.CreatedAt,
-			UpdatedAt:    event.UserMembership.UpdatedAt,
+			ID:             event.UserMembership.ID,
+			GroupID:        event.UserMembership.GroupID,
+			UserID:         event.UserMembership.UserID,
+			MembershipID:   event.UserMembership.MembershipID,
+			Email:          event.UserMembership.Email,
+			Username:       event.UserMembership.Username,
+			Status:         event.UserMembership.Status,
+			Role:           event.UserMembership.Role,
+			RoleID:         event.UserMembership.RoleID,
+			RoleName:       event.UserMembership.RoleName,
+			Permissions:    event.UserMembership.Permissions,
+			OrganizationID: event.UserMembership.OrganizationID,
+			ValidFrom:      event.UserMembership.ValidFrom,
+			ValidUntil:     event.UserMembership.ValidUntil,
+			CreatedAt:      event.UserMembership.CreatedAt,
+			UpdatedAt:      event.UserMembership.UpdatedAt,
 		}
 		_, err := c.mongoDB.CreateUserMembership(ctx, userMembership)
 		select {
@@ -87,17 +100,23 @@ func (c *createMembershipEventHandler) Handle(ctx context.Context, event *Create
 	}()
 	go func() {
 		groupMembership := &entities.GroupMembership{
-			ID:           event.GroupMembership.ID,
-			UserID:       event.GroupMembership.UserID,
-			GroupID:      event.GroupMembership.GroupID,
-			MembershipID: event.GroupMembership.MembershipID,
-			Name:         event.GroupMembership.Name,
-			Description:  event.GroupMembership.Description,
-			Status:       event.GroupMembership.Status,
-			Role:         event.GroupMembership.Role,
-			Creator:      event.GroupMembership.Creator,
-			CreatedAt:    event.GroupMembership.CreatedAt,
-			UpdatedAt:    event.GroupMembership.UpdatedAt,
+			ID:             event.GroupMembership.ID,
+			UserID:         event.GroupMembership.UserID,
+			GroupID:        event.GroupMembership.GroupID,
+			MembershipID:   event.GroupMembership.MembershipID,
+			Name:           event.GroupMembership.Name,
+			Description:    event.GroupMembership.Description,
+			Status:         event.GroupMembership.Status,
+			Role:           event.GroupMembership.Role,
+			RoleID:         event.GroupMembership.RoleID,
+			RoleName:       event.GroupMembership.RoleName,
+			Permissions:    event.GroupMembership.Permissions,
+			Creator:        event.GroupMembership.Creator,
+			OrganizationID: event.GroupMembership.OrganizationID,
+			ValidFrom:      event.GroupMembership.ValidFrom,
+			ValidUntil:     event.GroupMembership.ValidUntil,
+			CreatedAt:      event.GroupMembership.CreatedAt,
+			UpdatedAt:      event.GroupMembership.UpdatedAt,
 		}
 		_, err := c.mongoDB.CreateGroupMembership(ctx, groupMembership)
 		select {
@@ -119,7 +138,10 @@ func (c *createMembershipEventHandler) Handle(ctx context.Context, event *Create
 			}
 		}
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return refreshEffectiveMemberships(ctx, c.mongoDB, event.Membership.UserID)
 }
 
 // UpdateMembershipEventHandler ...
@@ -144,20 +166,25 @@ func NewUpdateMembershipEventHandler(log logging.Logger, cfg *config.Config, mon
 }
 
 func (c *updateMembershipEventHandler) Handle(ctx context.Context, event *UpdateMembershipEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateMembershipEventHandler.Handle")
+	ctx, span := tracing.StartSpan(ctx, "updateMembershipEventHandler.Handle")
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 	}()
 	go func() {
 		membership := &entities.Membership{
-			ID:        event.ID,
-			Status:    event.Status,
-			Role:      event.Role,
-			UpdatedAt: event.UpdatedAt,
+			ID:          event.ID,
+			Status:      event.Status,
+			Role:        event.Role,
+			RoleID:      event.RoleID,
+			RoleName:    event.RoleName,
+			Permissions: event.Permissions,
+			ValidFrom:   event.ValidFrom,
+			ValidUntil:  event.ValidUntil,
+			UpdatedAt:   event.UpdatedAt,
 		}
 		updated, err := c.mongoDB.UpdateMembership(ctx, membership)
 		select {
@@ -167,6 +194,7 @@ func (c *updateMembershipEventHandler) Handle(ctx context.Context, event *Update
 		}
 		if err == nil {
 			c.redisCache.PutMembership(ctx, updated.ID, updated)
+			c.redisCache.Invalidate(ctx, cache.MembershipEntity, updated.ID)
 		}
 		errChan <- err
 	}()
@@ -175,9 +203,14 @@ func (c *updateMembershipEventHandler) Handle(ctx context.Context, event *Update
 			MembershipID: event.ID,
 		}
 		update := &entities.GroupMembership{
-			Status:    event.Status,
-			Role:      event.Role,
-			UpdatedAt: event.UpdatedAt,
+			Status:      event.Status,
+			Role:        event.Role,
+			RoleID:      event.RoleID,
+			RoleName:    event.RoleName,
+			Permissions: event.Permissions,
+			ValidFrom:   event.ValidFrom,
+			ValidUntil:  event.ValidUntil,
+			UpdatedAt:   event.UpdatedAt,
 		}
 		err := c.mongoDB.UpdateGroupMemberships(ctx, filter, update)
 		select {
@@ -192,9 +225,14 @@ func (c *updateMembershipEventHandler) Handle(ctx context.Context, event *Update
 			MembershipID: event.ID,
 		}
 		update := &entities.UserMembership{
-			Status:    event.Status,
-			Role:      event.Role,
-			UpdatedAt: event.UpdatedAt,
+			Status:      event.Status,
+			Role:        event.Role,
+			RoleID:      event.RoleID,
+			RoleName:    event.RoleName,
+			Permissions: event.Permissions,
+			ValidFrom:   event.ValidFrom,
+			ValidUntil:  event.ValidUntil,
+			UpdatedAt:   event.UpdatedAt,
 		}
 		err := c.mongoDB.UpdateUserMemberships(ctx, filter, update)
 		select {
@@ -213,7 +251,14 @@ func (c *updateMembershipEventHandler) Handle(ctx context.Context, event *Update
 			}
 		}
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	membership, err := c.mongoDB.GetMembershipById(ctx, uuid.FromStringOrNil(event.ID))
+	if err != nil {
+		return err
+	}
+	return refreshEffectiveMemberships(ctx, c.mongoDB, membership.UserID)
 }
 
 // DeleteMembershipEventHandler ...
@@ -238,14 +283,16 @@ func NewDeleteMembershipEventHandler(log logging.Logger, cfg *config.Config, mon
 }
 
 func (c *deleteMembershipEventHandler) Handle(ctx context.Context, event *DeleteMembershipEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteMembershipEventHandler.Handle")
+	ctx, span := tracing.StartSpan(ctx, "deleteMembershipEventHandler.Handle")
 	ctx, cancel := context.WithCancel(context.Background())
 	errChan := make(chan error)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 	}()
+	// the user whose effective memberships change is only known before the membership is gone; a miss leaves it empty
+	membership, _ := c.mongoDB.GetMembershipById(ctx, event.ID)
 	go func() {
 		err := c.mongoDB.DeleteMembership(ctx, event.ID)
 		select {
@@ -255,6 +302,7 @@ func (c *deleteMembershipEventHandler) Handle(ctx context.Context, event *Delete
 		}
 		if err == nil {
 			c.redisCache.DeleteMembership(ctx, event.ID.String())
+			c.redisCache.Invalidate(ctx, cache.MembershipEntity, event.ID.String())
 		}
 		errChan <- err
 	}()
@@ -285,5 +333,8 @@ func (c *deleteMembershipEventHandler) Handle(ctx context.Context, event *Delete
 			}
 		}
 	}
-	return err
+	if err != nil || membership.UserID == "" {
+		return err
+	}
+	return refreshEffectiveMemberships(ctx, c.mongoDB, membership.UserID)
 }
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+type OrganizationEvents struct {
+	CreateOrganization       CreateOrganizationEventHandler
+	UpdateOrganization       UpdateOrganizationEventHandler
+	ChangeOrganizationStatus ChangeOrganizationStatusEventHandler
+	DeleteOrganization       DeleteOrganizationEventHandler
+}
+
+func NewOrganizationEvents(
+	createOrganization CreateOrganizationEventHandler,
+	updateOrganization UpdateOrganizationEventHandler,
+	changeOrganizationStatus ChangeOrganizationStatusEventHandler,
+	deleteOrganization DeleteOrganizationEventHandler,
+) *OrganizationEvents {
+	return &OrganizationEvents{
+		CreateOrganization:       createOrganization,
+		UpdateOrganization:       updateOrganization,
+		ChangeOrganizationStatus: changeOrganizationStatus,
+		DeleteOrganization:       deleteOrganization,
+	}
+}
+
+type CreateOrganizationEvent struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	Name        string    `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=250"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	Active      bool      `json:"active,omitempty" bson:"active,omitempty"`
+	CreatedAt   time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+func NewCreateOrganizationEvent(id string, name string, description string, active bool, createdAt time.Time, updatedAt time.Time) *CreateOrganizationEvent {
+	return &CreateOrganizationEvent{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Active:      active,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+type UpdateOrganizationEvent struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	Name        string    `json:"name,omitempty" bson:"name,omitempty"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+func NewUpdateOrganizationEvent(id string, name string, description string, updatedAt time.Time) *UpdateOrganizationEvent {
+	return &UpdateOrganizationEvent{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+// ChangeOrganizationStatusEvent suspends or reactivates an organization; SessionsRevokedAt is set on suspension
+type ChangeOrganizationStatusEvent struct {
+	ID                string    `json:"id" bson:"_id,omitempty"`
+	Active            bool      `json:"active" bson:"active"`
+	SessionsRevokedAt time.Time `json:"sessionsRevokedAt,omitempty" bson:"sessions_revoked_at,omitempty"`
+	UpdatedAt         time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+func NewChangeOrganizationStatusEvent(id string, active bool, sessionsRevokedAt time.Time, updatedAt time.Time) *ChangeOrganizationStatusEvent {
+	return &ChangeOrganizationStatusEvent{
+		ID:                id,
+		Active:            active,
+		SessionsRevokedAt: sessionsRevokedAt,
+		UpdatedAt:         updatedAt,
+	}
+}
+
+type DeleteOrganizationEvent struct {
+	ID uuid.UUID `json:"id" bson:"_id,omitempty"`
+}
+
+func NewDeleteOrganizationEvent(id uuid.UUID) *DeleteOrganizationEvent {
+	return &DeleteOrganizationEvent{ID: id}
+}
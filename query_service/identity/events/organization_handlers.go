@@ -0,0 +1,222 @@
+package events
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/cache"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CreateOrganizationEventHandler ...
+type CreateOrganizationEventHandler interface {
+	Handle(ctx context.Context, event *CreateOrganizationEvent) error
+}
+
+type createOrganizationEventHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewCreateOrganizationEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *createOrganizationEventHandler {
+	return &createOrganizationEventHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (c *createOrganizationEventHandler) Handle(ctx context.Context, event *CreateOrganizationEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "createOrganizationEventHandler.Handle")
+	defer span.End()
+	organization := &entities.Organization{
+		ID:          event.ID,
+		Name:        event.Name,
+		Description: event.Description,
+		Active:      event.Active,
+		CreatedAt:   event.CreatedAt,
+		UpdatedAt:   event.UpdatedAt,
+	}
+	_, err := c.mongoDB.CreateOrganization(ctx, organization)
+	return err
+}
+
+// UpdateOrganizationEventHandler ...
+type UpdateOrganizationEventHandler interface {
+	Handle(ctx context.Context, event *UpdateOrganizationEvent) error
+}
+
+type updateOrganizationEventHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewUpdateOrganizationEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *updateOrganizationEventHandler {
+	return &updateOrganizationEventHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (c *updateOrganizationEventHandler) Handle(ctx context.Context, event *UpdateOrganizationEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "updateOrganizationEventHandler.Handle")
+	defer span.End()
+	organization := &entities.Organization{
+		ID:          event.ID,
+		Name:        event.Name,
+		Description: event.Description,
+		UpdatedAt:   event.UpdatedAt,
+	}
+	_, err := c.mongoDB.UpdateOrganization(ctx, organization)
+	return err
+}
+
+// ChangeOrganizationStatusEventHandler ...
+type ChangeOrganizationStatusEventHandler interface {
+	Handle(ctx context.Context, event *ChangeOrganizationStatusEvent) error
+}
+
+type changeOrganizationStatusEventHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewChangeOrganizationStatusEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *changeOrganizationStatusEventHandler {
+	return &changeOrganizationStatusEventHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (c *changeOrganizationStatusEventHandler) Handle(ctx context.Context, event *ChangeOrganizationStatusEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "changeOrganizationStatusEventHandler.Handle")
+	defer span.End()
+	organization := &entities.Organization{
+		ID:                event.ID,
+		Active:            event.Active,
+		SessionsRevokedAt: event.SessionsRevokedAt,
+		UpdatedAt:         event.UpdatedAt,
+	}
+	_, err := c.mongoDB.UpdateOrganizationStatus(ctx, organization)
+	return err
+}
+
+// DeleteOrganizationEventHandler ...
+type DeleteOrganizationEventHandler interface {
+	Handle(ctx context.Context, event *DeleteOrganizationEvent) error
+}
+
+type deleteOrganizationEventHandler struct {
+	log        logging.Logger
+	cfg        *config.Config
+	mongoDB    data.Database
+	redisCache cache.Cache
+}
+
+func NewDeleteOrganizationEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *deleteOrganizationEventHandler {
+	return &deleteOrganizationEventHandler{
+		log:        log,
+		cfg:        cfg,
+		mongoDB:    mongoDB,
+		redisCache: redisCache,
+	}
+}
+
+// Handle cascades the deletion of an organization to its users, its groups, its roles and the memberships of both
+func (c *deleteOrganizationEventHandler) Handle(ctx context.Context, event *DeleteOrganizationEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "deleteOrganizationEventHandler.Handle")
+	defer span.End()
+	organizationID := event.ID.String()
+	users, err := c.mongoDB.DeleteOrganizationUsers(ctx, organizationID)
+	if err != nil {
+		return errors.Wrap(err, "DeleteOrganizationUsers")
+	}
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+		c.redisCache.DeleteUser(ctx, user.ID)
+		c.redisCache.Invalidate(ctx, cache.UserEntity, user.ID)
+	}
+	groups, err := c.mongoDB.DeleteOrganizationGroups(ctx, organizationID, userIDs)
+	if err != nil {
+		return errors.Wrap(err, "DeleteOrganizationGroups")
+	}
+	// the users that reached a deleted group lose it, along with the ancestors it led to
+	affectedIDs := make([]string, 0)
+	for _, group := range groups {
+		c.redisCache.DeleteGroup(ctx, group.ID)
+		c.redisCache.Invalidate(ctx, cache.GroupEntity, group.ID)
+		groupID := uuid.FromStringOrNil(group.ID)
+		memberIDs, err := c.mongoDB.GetEffectiveMemberIds(ctx, groupID)
+		if err != nil {
+			return errors.Wrap(err, "GetEffectiveMemberIds")
+		}
+		affectedIDs = append(affectedIDs, memberIDs...)
+		children, err := c.mongoDB.DetachChildGroups(ctx, groupID)
+		if err != nil {
+			return errors.Wrap(err, "DetachChildGroups")
+		}
+		for _, child := range children {
+			c.redisCache.PutGroup(ctx, child.ID, child)
+			c.redisCache.Invalidate(ctx, cache.GroupEntity, child.ID)
+		}
+	}
+	if err = c.deleteMemberships(ctx, &entities.Membership{OrganizationID: organizationID}, &entities.UserMembership{OrganizationID: organizationID}, &entities.GroupMembership{OrganizationID: organizationID}); err != nil {
+		return err
+	}
+	// memberships reaching outside the organization are owned by another tenant and removed one by one
+	for _, id := range userIDs {
+		if err = c.deleteMemberships(ctx, &entities.Membership{UserID: id}, &entities.UserMembership{UserID: id}, &entities.GroupMembership{UserID: id}); err != nil {
+			return err
+		}
+	}
+	for _, group := range groups {
+		if group.OrganizationID == organizationID {
+			continue
+		}
+		if err = c.deleteMemberships(ctx, &entities.Membership{GroupID: group.ID}, &entities.UserMembership{GroupID: group.ID}, &entities.GroupMembership{GroupID: group.ID}); err != nil {
+			return err
+		}
+	}
+	if err = c.mongoDB.DeleteEffectiveMemberships(ctx, &entities.EffectiveMembership{OrganizationID: organizationID}); err != nil {
+		return errors.Wrap(err, "DeleteEffectiveMemberships")
+	}
+	for _, id := range userIDs {
+		if err = c.mongoDB.DeleteEffectiveMemberships(ctx, &entities.EffectiveMembership{UserID: id}); err != nil {
+			return errors.Wrap(err, "DeleteEffectiveMemberships")
+		}
+	}
+	if err = refreshEffectiveMemberships(ctx, c.mongoDB, affectedIDs...); err != nil {
+		return err
+	}
+	if err = c.mongoDB.DeleteRoles(ctx, &entities.Role{OrganizationID: organizationID}); err != nil {
+		return errors.Wrap(err, "DeleteRoles")
+	}
+	if err = c.mongoDB.DeleteOrganization(ctx, event.ID); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.Wrap(err, "DeleteOrganization")
+	}
+	return nil
+}
+
+func (c *deleteOrganizationEventHandler) deleteMemberships(ctx context.Context, membership *entities.Membership, userMembership *entities.UserMembership, groupMembership *entities.GroupMembership) error {
+	if err := c.mongoDB.DeleteMemberships(ctx, membership); err != nil {
+		return errors.Wrap(err, "DeleteMemberships")
+	}
+	if err := c.mongoDB.DeleteUserMemberships(ctx, userMembership); err != nil {
+		return errors.Wrap(err, "DeleteUserMemberships")
+	}
+	if err := c.mongoDB.DeleteGroupMemberships(ctx, groupMembership); err != nil {
+		return errors.Wrap(err, "DeleteGroupMemberships")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+type RoleEvents struct {
+	CreateRole CreateRoleEventHandler
+	UpdateRole UpdateRoleEventHandler
+	DeleteRole DeleteRoleEventHandler
+}
+
+func NewRoleEvents(
+	createRole CreateRoleEventHandler,
+	updateRole UpdateRoleEventHandler,
+	deleteRole DeleteRoleEventHandler,
+) *RoleEvents {
+	return &RoleEvents{
+		CreateRole: createRole,
+		UpdateRole: updateRole,
+		DeleteRole: deleteRole,
+	}
+}
+
+type CreateRoleEvent struct {
+	ID             string    `json:"id" bson:"_id,omitempty"`
+	Name           string    `json:"name,omitempty" bson:"name,omitempty" validate:"required,max=250"`
+	Description    string    `json:"description,omitempty" bson:"description,omitempty"`
+	Permissions    []string  `json:"permissions" bson:"permissions"`
+	GroupID        string    `json:"groupID,omitempty" bson:"group_id,omitempty"`
+	OrganizationID string    `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	BuiltIn        bool      `json:"builtIn,omitempty" bson:"built_in,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+func NewCreateRoleEvent(id string, name string, description string, permissions []string, groupID string, organizationID string, builtIn bool, createdAt time.Time, updatedAt time.Time) *CreateRoleEvent {
+	return &CreateRoleEvent{
+		ID:             id,
+		Name:           name,
+		Description:    description,
+		Permissions:    permissions,
+		GroupID:        groupID,
+		OrganizationID: organizationID,
+		BuiltIn:        builtIn,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+type UpdateRoleEvent struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	Name        string    `json:"name,omitempty" bson:"name,omitempty"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	Permissions []string  `json:"permissions" bson:"permissions"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+func NewUpdateRoleEvent(id string, name string, description string, permissions []string, updatedAt time.Time) *UpdateRoleEvent {
+	return &UpdateRoleEvent{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+// DeleteRoleEvent removes a custom role; the memberships granted it fall back to the built-in role of Fallback
+type DeleteRoleEvent struct {
+	ID       uuid.UUID        `json:"id" bson:"_id,omitempty"`
+	Fallback *CreateRoleEvent `json:"fallback,omitempty" bson:"fallback,omitempty"`
+}
+
+func NewDeleteRoleEvent(id uuid.UUID, fallback *CreateRoleEvent) *DeleteRoleEvent {
+	return &DeleteRoleEvent{ID: id, Fallback: fallback}
+}
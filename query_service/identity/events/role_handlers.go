@@ -0,0 +1,197 @@
+package events
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/cache"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// SeedBuiltInRoles projects the built-in roles, which are seeded by the command service rather than created by events
+func SeedBuiltInRoles(ctx context.Context, mongoDB data.Database) error {
+	now := time.Now().UTC()
+	for _, r := range enums.BuiltInRoles() {
+		role := &entities.Role{
+			ID:          r.RoleID(),
+			Name:        r.Stringify(),
+			Description: "Built-in " + r.Stringify() + " role",
+			Permissions: r.Permissions(),
+			BuiltIn:     true,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if _, err := mongoDB.CreateRole(ctx, role); err != nil {
+			return errors.Wrap(err, "CreateRole")
+		}
+	}
+	return nil
+}
+
+// reassignRole rewrites the role of the memberships granted the role with id across the membership projections,
+// dropping them from the cache
+func reassignRole(ctx context.Context, mongoDB data.Database, redisCache cache.Cache, id string, role *entities.Role) error {
+	memberships, err := mongoDB.GetMembershipsByRoleId(ctx, uuid.FromStringOrNil(id))
+	if err != nil {
+		return errors.Wrap(err, "GetMembershipsByRoleId")
+	}
+	if len(memberships) == 0 {
+		return nil
+	}
+	// memberships moved to another role take the Role enum value of the built-in role they fall back to
+	var enumRole enums.Role
+	if role.ID != id {
+		enumRole = enums.RoleFromID(role.ID)
+	}
+	if err = mongoDB.UpdateMemberships(ctx, &entities.Membership{RoleID: id}, &entities.Membership{
+		Role:        enumRole,
+		RoleID:      role.ID,
+		RoleName:    role.Name,
+		Permissions: role.Permissions,
+	}); err != nil {
+		return errors.Wrap(err, "UpdateMemberships")
+	}
+	if err = mongoDB.UpdateUserMemberships(ctx, &entities.UserMembership{RoleID: id}, &entities.UserMembership{
+		Role:        enumRole,
+		RoleID:      role.ID,
+		RoleName:    role.Name,
+		Permissions: role.Permissions,
+	}); err != nil {
+		return errors.Wrap(err, "UpdateUserMemberships")
+	}
+	if err = mongoDB.UpdateGroupMemberships(ctx, &entities.GroupMembership{RoleID: id}, &entities.GroupMembership{
+		Role:        enumRole,
+		RoleID:      role.ID,
+		RoleName:    role.Name,
+		Permissions: role.Permissions,
+	}); err != nil {
+		return errors.Wrap(err, "UpdateGroupMemberships")
+	}
+	for _, membership := range memberships {
+		redisCache.DeleteMembership(ctx, membership.ID)
+		redisCache.Invalidate(ctx, cache.MembershipEntity, membership.ID)
+	}
+	return nil
+}
+
+// CreateRoleEventHandler ...
+type CreateRoleEventHandler interface {
+	Handle(ctx context.Context, event *CreateRoleEvent) error
+}
+
+type createRoleEventHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewCreateRoleEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *createRoleEventHandler {
+	return &createRoleEventHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (c *createRoleEventHandler) Handle(ctx context.Context, event *CreateRoleEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "createRoleEventHandler.Handle")
+	defer span.End()
+	role := &entities.Role{
+		ID:             event.ID,
+		Name:           event.Name,
+		Description:    event.Description,
+		Permissions:    event.Permissions,
+		GroupID:        event.GroupID,
+		OrganizationID: event.OrganizationID,
+		BuiltIn:        event.BuiltIn,
+		CreatedAt:      event.CreatedAt,
+		UpdatedAt:      event.UpdatedAt,
+	}
+	_, err := c.mongoDB.CreateRole(ctx, role)
+	return err
+}
+
+// UpdateRoleEventHandler ...
+type UpdateRoleEventHandler interface {
+	Handle(ctx context.Context, event *UpdateRoleEvent) error
+}
+
+type updateRoleEventHandler struct {
+	log        logging.Logger
+	cfg        *config.Config
+	mongoDB    data.Database
+	redisCache cache.Cache
+}
+
+func NewUpdateRoleEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *updateRoleEventHandler {
+	return &updateRoleEventHandler{
+		log:        log,
+		cfg:        cfg,
+		mongoDB:    mongoDB,
+		redisCache: redisCache,
+	}
+}
+
+// Handle updates a role along with the role names and permissions projected into its memberships
+func (c *updateRoleEventHandler) Handle(ctx context.Context, event *UpdateRoleEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "updateRoleEventHandler.Handle")
+	defer span.End()
+	role := &entities.Role{
+		ID:          event.ID,
+		Name:        event.Name,
+		Description: event.Description,
+		Permissions: event.Permissions,
+		UpdatedAt:   event.UpdatedAt,
+	}
+	updated, err := c.mongoDB.UpdateRole(ctx, role)
+	if err != nil {
+		return err
+	}
+	return reassignRole(ctx, c.mongoDB, c.redisCache, updated.ID, updated)
+}
+
+// DeleteRoleEventHandler ...
+type DeleteRoleEventHandler interface {
+	Handle(ctx context.Context, event *DeleteRoleEvent) error
+}
+
+type deleteRoleEventHandler struct {
+	log        logging.Logger
+	cfg        *config.Config
+	mongoDB    data.Database
+	redisCache cache.Cache
+}
+
+func NewDeleteRoleEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *deleteRoleEventHandler {
+	return &deleteRoleEventHandler{
+		log:        log,
+		cfg:        cfg,
+		mongoDB:    mongoDB,
+		redisCache: redisCache,
+	}
+}
+
+// Handle deletes a role, moving the memberships granted it to the fallback role
+func (c *deleteRoleEventHandler) Handle(ctx context.Context, event *DeleteRoleEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "deleteRoleEventHandler.Handle")
+	defer span.End()
+	fallback := &entities.Role{
+		ID:          event.Fallback.ID,
+		Name:        event.Fallback.Name,
+		Permissions: event.Fallback.Permissions,
+	}
+	if err := reassignRole(ctx, c.mongoDB, c.redisCache, event.ID.String(), fallback); err != nil {
+		return err
+	}
+	if err := c.mongoDB.DeleteRole(ctx, event.ID); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.Wrap(err, "DeleteRole")
+	}
+	return nil
+}
@@ -1,49 +1,57 @@
 package events
 
 import (
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/gofrs/uuid"
 	"time"
 )
 
 type UserEvents struct {
-	CreateUser CreateUserEventHandler
-	UpdateUser UpdateUserEventHandler
-	DeleteUser DeleteUserEventHandler
+	CreateUser       CreateUserEventHandler
+	UpdateUser       UpdateUserEventHandler
+	DeleteUser       DeleteUserEventHandler
+	ChangeUserStatus ChangeUserStatusEventHandler
 }
 
 func NewUserEvents(
 	createUser CreateUserEventHandler,
 	updateUser UpdateUserEventHandler,
 	deleteUser DeleteUserEventHandler,
+	changeUserStatus ChangeUserStatusEventHandler,
 ) *UserEvents {
 	return &UserEvents{
-		CreateUser: createUser,
-		UpdateUser: updateUser,
-		DeleteUser: deleteUser,
+		CreateUser:       createUser,
+		UpdateUser:       updateUser,
+		DeleteUser:       deleteUser,
+		ChangeUserStatus: changeUserStatus,
 	}
 }
 
 type CreateUserEvent struct {
-	ID        string    `json:"id" bson:"_id,omitempty"`
-	Email     string    `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=250"`
-	Username  string    `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
-	Password  string    `json:"password,omitempty" bson:"password,omitempty" validate:"required"`
-	Root      bool      `json:"root,omitempty" bson:"root,omitempty" `
-	Active    bool      `json:"active,omitempty" bson:"active,omitempty"`
-	CreatedAt time.Time `json:"createdAt,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt time.Time `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID             string           `json:"id" bson:"_id,omitempty"`
+	Email          string           `json:"email,omitempty" bson:"email,omitempty" validate:"required,min=3,max=250"`
+	Username       string           `json:"username,omitempty" bson:"username,omitempty" validate:"required,min=3,max=500"`
+	Root           bool             `json:"root,omitempty" bson:"root,omitempty" `
+	Active         bool             `json:"active,omitempty" bson:"active,omitempty"`
+	Status         enums.UserStatus `json:"status,omitempty" bson:"status,omitempty"`
+	OrganizationID string           `json:"organizationID,omitempty" bson:"organization_id,omitempty"`
+	OrgAdmin       bool             `json:"orgAdmin,omitempty" bson:"org_admin,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt      time.Time        `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
 }
 
-func NewCreateUserEvent(id string, email string, username string, password string, root bool, active bool, createdAt time.Time, updatedAt time.Time) *CreateUserEvent {
+func NewCreateUserEvent(id string, email string, username string, root bool, active bool, status enums.UserStatus, organizationID string, orgAdmin bool, createdAt time.Time, updatedAt time.Time) *CreateUserEvent {
 	return &CreateUserEvent{
-		ID:        id,
-		Email:     email,
-		Username:  username,
-		Password:  password,
-		Root:      root,
-		Active:    active,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:             id,
+		Email:          email,
+		Username:       username,
+		Root:           root,
+		Active:         active,
+		Status:         status,
+		OrganizationID: organizationID,
+		OrgAdmin:       orgAdmin,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
 	}
 }
 
@@ -70,3 +78,23 @@ type DeleteUserEvent struct {
 func NewDeleteUserEvent(id uuid.UUID) *DeleteUserEvent {
 	return &DeleteUserEvent{ID: id}
 }
+
+type ChangeUserStatusEvent struct {
+	ID                string           `json:"id" bson:"_id,omitempty" validate:"required"`
+	Status            enums.UserStatus `json:"status" bson:"status" validate:"required,gte=1,lte=4"`
+	StatusReason      string           `json:"statusReason,omitempty" bson:"status_reason,omitempty"`
+	SuspendedUntil    time.Time        `json:"suspendedUntil,omitempty" bson:"suspended_until,omitempty"`
+	SessionsRevokedAt time.Time        `json:"sessionsRevokedAt,omitempty" bson:"sessions_revoked_at,omitempty"`
+	UpdatedAt         time.Time        `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+func NewChangeUserStatusEvent(id string, status enums.UserStatus, statusReason string, suspendedUntil time.Time, sessionsRevokedAt time.Time, updatedAt time.Time) *ChangeUserStatusEvent {
+	return &ChangeUserStatusEvent{
+		ID:                id,
+		Status:            status,
+		StatusReason:      statusReason,
+		SuspendedUntil:    suspendedUntil,
+		SessionsRevokedAt: sessionsRevokedAt,
+		UpdatedAt:         updatedAt,
+	}
+}
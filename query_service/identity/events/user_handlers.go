@@ -2,12 +2,13 @@ package events
 
 import (
 	"context"
+	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
 )
 
 // CreateUserEventHandler ...
@@ -32,17 +33,19 @@ func NewCreateUserEventHandler(log logging.Logger, cfg *config.Config, mongoDB d
 }
 
 func (c *createUserEventHandler) Handle(ctx context.Context, event *CreateUserEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "createUserEventHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "createUserEventHandler.Handle")
+	defer span.End()
 	user := &entities.User{
-		ID:        event.ID,
-		Email:     event.Email,
-		Username:  event.Username,
-		Password:  event.Password,
-		Root:      event.Root,
-		Active:    event.Active,
-		CreatedAt: event.CreatedAt,
-		UpdatedAt: event.UpdatedAt,
+		ID:             event.ID,
+		Email:          event.Email,
+		Username:       event.Username,
+		Root:           event.Root,
+		Active:         event.Active,
+		Status:         event.Status,
+		OrganizationID: event.OrganizationID,
+		OrgAdmin:       event.OrgAdmin,
+		CreatedAt:      event.CreatedAt,
+		UpdatedAt:      event.UpdatedAt,
 	}
 	created, err := c.mongoDB.CreateUser(ctx, user)
 	if err != nil {
@@ -74,11 +77,11 @@ func NewUpdateUserEventHandler(log logging.Logger, cfg *config.Config, mongoDB d
 }
 
 func (c *updateUserEventHandler) Handle(ctx context.Context, event *UpdateUserEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "updateUserEventHandler.Handle")
+	ctx, span := tracing.StartSpan(ctx, "updateUserEventHandler.Handle")
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 	}()
@@ -97,6 +100,7 @@ func (c *updateUserEventHandler) Handle(ctx context.Context, event *UpdateUserEv
 		}
 		if err == nil {
 			c.redisCache.PutUser(ctx, updated.ID, updated)
+			c.redisCache.Invalidate(ctx, cache.UserEntity, updated.ID)
 		}
 		errChan <- err
 	}()
@@ -151,11 +155,11 @@ func NewDeleteUserEventHandler(log logging.Logger, cfg *config.Config, mongoDB d
 }
 
 func (c *deleteUserEventHandler) Handle(ctx context.Context, event *DeleteUserEvent) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteUserEventHandler.Handle")
+	ctx, span := tracing.StartSpan(ctx, "deleteUserEventHandler.Handle")
 	ctx, cancel := context.WithCancel(ctx)
 	errChan := make(chan error)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 	}()
@@ -168,6 +172,7 @@ func (c *deleteUserEventHandler) Handle(ctx context.Context, event *DeleteUserEv
 		}
 		if err == nil {
 			c.redisCache.DeleteUser(ctx, event.ID.String())
+			c.redisCache.Invalidate(ctx, cache.UserEntity, event.ID.String())
 		}
 		errChan <- err
 	}()
@@ -211,8 +216,20 @@ func (c *deleteUserEventHandler) Handle(ctx context.Context, event *DeleteUserEv
 		}
 		errChan <- err
 	}()
+	go func() {
+		filter := &entities.EffectiveMembership{
+			UserID: event.ID.String(),
+		}
+		err := c.mongoDB.DeleteEffectiveMemberships(ctx, filter)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		errChan <- err
+	}()
 	var err error
-	for i := 0; i < 4; i++ {
+	for i := 0; i < 5; i++ {
 		select {
 		case err = <-errChan:
 			if err != nil {
@@ -222,3 +239,45 @@ func (c *deleteUserEventHandler) Handle(ctx context.Context, event *DeleteUserEv
 	}
 	return nil
 }
+
+// ChangeUserStatusEventHandler ...
+type ChangeUserStatusEventHandler interface {
+	Handle(ctx context.Context, event *ChangeUserStatusEvent) error
+}
+
+type changeUserStatusEventHandler struct {
+	log        logging.Logger
+	cfg        *config.Config
+	mongoDB    data.Database
+	redisCache cache.Cache
+}
+
+func NewChangeUserStatusEventHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *changeUserStatusEventHandler {
+	return &changeUserStatusEventHandler{
+		log:        log,
+		cfg:        cfg,
+		mongoDB:    mongoDB,
+		redisCache: redisCache,
+	}
+}
+
+func (c *changeUserStatusEventHandler) Handle(ctx context.Context, event *ChangeUserStatusEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "changeUserStatusEventHandler.Handle")
+	defer span.End()
+	user := &entities.User{
+		ID:                event.ID,
+		Active:            event.Status == enums.ACTIVATED,
+		Status:            event.Status,
+		StatusReason:      event.StatusReason,
+		SuspendedUntil:    event.SuspendedUntil,
+		SessionsRevokedAt: event.SessionsRevokedAt,
+		UpdatedAt:         event.UpdatedAt,
+	}
+	updated, err := c.mongoDB.UpdateUserStatus(ctx, user)
+	if err != nil {
+		return err
+	}
+	c.redisCache.PutUser(ctx, updated.ID, updated)
+	c.redisCache.Invalidate(ctx, cache.UserEntity, updated.ID)
+	return nil
+}
@@ -24,14 +24,27 @@ type QueryServiceMetrics struct {
 	GetGroupByIdGrpcRequests prometheus.Counter
 	SearchGroupGrpcRequests  prometheus.Counter
 	// gRPC Memberships
-	CreateMembershipGrpcRequests   prometheus.Counter
-	UpdateMembershipGrpcRequests   prometheus.Counter
-	DeleteMembershipGrpcRequests   prometheus.Counter
-	GetMembershipByIdGrpcRequests  prometheus.Counter
-	GetGroupMembershipGrpcRequests prometheus.Counter
-	GetUserMembershipGrpcRequests  prometheus.Counter
+	CreateMembershipGrpcRequests    prometheus.Counter
+	UpdateMembershipGrpcRequests    prometheus.Counter
+	DeleteMembershipGrpcRequests    prometheus.Counter
+	GetMembershipByIdGrpcRequests   prometheus.Counter
+	GetGroupMembershipGrpcRequests  prometheus.Counter
+	GetUserMembershipGrpcRequests   prometheus.Counter
+	GetEffectiveGroupsGrpcRequests  prometheus.Counter
+	GetEffectiveMembersGrpcRequests prometheus.Counter
+	// gRPC Organizations
+	GetOrganizationByIdGrpcRequests prometheus.Counter
+	SearchOrganizationGrpcRequests  prometheus.Counter
+	// gRPC Roles
+	GetRoleByIdGrpcRequests prometheus.Counter
+	SearchRolesGrpcRequests prometheus.Counter
+	// gRPC Authz
+	CheckGrpcRequests         prometheus.Counter
+	BatchCheckGrpcRequests    prometheus.Counter
+	ListResourcesGrpcRequests prometheus.Counter
+	AllowedDecisions          prometheus.Counter
+	DeniedDecisions           prometheus.Counter
 	// gRPC Auth
-	AuthenticateGrpcRequests   prometheus.Counter
 	ValidateGrpcRequests       prometheus.Counter
 	BlacklistTokenGrpcRequests prometheus.Counter
 	UpdatePasswordGrpcRequests prometheus.Counter
@@ -42,17 +55,39 @@ type QueryServiceMetrics struct {
 	CreateUserKafkaMessages prometheus.Counter
 	UpdateUserKafkaMessages prometheus.Counter
 	DeleteUserKafkaMessages prometheus.Counter
+	// Kafka User Status
+	ChangeUserStatusKafkaMessages prometheus.Counter
 	// Kafka Groups
-	CreateGroupKafkaMessages prometheus.Counter
-	UpdateGroupKafkaMessages prometheus.Counter
-	DeleteGroupKafkaMessages prometheus.Counter
+	CreateGroupKafkaMessages       prometheus.Counter
+	UpdateGroupKafkaMessages       prometheus.Counter
+	DeleteGroupKafkaMessages       prometheus.Counter
+	ChangeGroupParentKafkaMessages prometheus.Counter
 	// Kafka Memberships
 	CreateMembershipKafkaMessages prometheus.Counter
 	UpdateMembershipKafkaMessages prometheus.Counter
 	DeleteMembershipKafkaMessages prometheus.Counter
+	// Kafka Organizations
+	CreateOrganizationKafkaMessages       prometheus.Counter
+	UpdateOrganizationKafkaMessages       prometheus.Counter
+	ChangeOrganizationStatusKafkaMessages prometheus.Counter
+	DeleteOrganizationKafkaMessages       prometheus.Counter
+	// Kafka Roles
+	CreateRoleKafkaMessages prometheus.Counter
+	UpdateRoleKafkaMessages prometheus.Counter
+	DeleteRoleKafkaMessages prometheus.Counter
 	// Kafka Auth
 	BlacklistTokenKafkaMessages prometheus.Counter
 	UpdatePasswordKafkaMessages prometheus.Counter
+	// Cache
+	CacheHits        *prometheus.CounterVec
+	CacheMisses      *prometheus.CounterVec
+	CacheEvictions   *prometheus.CounterVec
+	CacheStaleWrites *prometheus.CounterVec
+	// Local Cache
+	LocalCacheHits        *prometheus.CounterVec
+	LocalCacheMisses      *prometheus.CounterVec
+	LocalCacheEvictions   *prometheus.CounterVec
+	LocalCacheInvalidated *prometheus.CounterVec
 }
 
 func NewQueryServiceMetrics(cfg *config.Config) *QueryServiceMetrics {
@@ -129,9 +164,13 @@ func NewQueryServiceMetrics(cfg *config.Config) *QueryServiceMetrics {
 			Name: fmt.Sprintf("%s_get_user_membership_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of get user membership grpc requests",
 		}),
-		AuthenticateGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
-			Name: fmt.Sprintf("%s_authenticate_grpc_requests_total", cfg.ServiceName),
-			Help: "The total number of authenticate grpc requests",
+		GetEffectiveGroupsGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_effective_groups_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of get effective groups grpc requests",
+		}),
+		GetEffectiveMembersGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_effective_members_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of get effective members grpc requests",
 		}),
 		ValidateGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_validate_grpc_requests_total", cfg.ServiceName),
@@ -157,6 +196,10 @@ func NewQueryServiceMetrics(cfg *config.Config) *QueryServiceMetrics {
 			Name: fmt.Sprintf("%s_delete_user_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of delete user kafka messages",
 		}),
+		ChangeUserStatusKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_change_user_status_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of change user status kafka messages",
+		}),
 		CreateGroupKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_create_group_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of create group kafka messages",
@@ -169,6 +212,10 @@ func NewQueryServiceMetrics(cfg *config.Config) *QueryServiceMetrics {
 			Name: fmt.Sprintf("%s_delete_group_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of delete group kafka messages",
 		}),
+		ChangeGroupParentKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_change_group_parent_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of change group parent kafka messages",
+		}),
 		CreateMembershipKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_create_membership_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of create membership kafka messages",
@@ -181,6 +228,70 @@ func NewQueryServiceMetrics(cfg *config.Config) *QueryServiceMetrics {
 			Name: fmt.Sprintf("%s_delete_membership_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of delete membership kafka messages",
 		}),
+		GetOrganizationByIdGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_organization_by_id_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of get organization by id grpc requests",
+		}),
+		SearchOrganizationGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_search_organization_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of search organization grpc requests",
+		}),
+		CreateOrganizationKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_create_organization_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of create organization kafka messages",
+		}),
+		UpdateOrganizationKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_update_organization_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of update organization kafka messages",
+		}),
+		ChangeOrganizationStatusKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_change_organization_status_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of change organization status kafka messages",
+		}),
+		DeleteOrganizationKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_delete_organization_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of delete organization kafka messages",
+		}),
+		GetRoleByIdGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_role_by_id_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of get role by id grpc requests",
+		}),
+		SearchRolesGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_search_roles_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of search roles grpc requests",
+		}),
+		CheckGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_check_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of authorization check grpc requests",
+		}),
+		BatchCheckGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_batch_check_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of batch authorization check grpc requests",
+		}),
+		ListResourcesGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_list_resources_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of list resources grpc requests",
+		}),
+		AllowedDecisions: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_allowed_decisions_total", cfg.ServiceName),
+			Help: "The total number of authorization checks that were allowed",
+		}),
+		DeniedDecisions: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_denied_decisions_total", cfg.ServiceName),
+			Help: "The total number of authorization checks that were denied",
+		}),
+		CreateRoleKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_create_role_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of create role kafka messages",
+		}),
+		UpdateRoleKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_update_role_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of update role kafka messages",
+		}),
+		DeleteRoleKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_delete_role_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of delete role kafka messages",
+		}),
 		BlacklistTokenKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_blacklist_token_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of blacklist token kafka messages",
@@ -197,5 +308,37 @@ func NewQueryServiceMetrics(cfg *config.Config) *QueryServiceMetrics {
 			Name: fmt.Sprintf("%s_error_kafka_processed_messages_total", cfg.ServiceName),
 			Help: "The total number of error kafka processed messages",
 		}),
+		CacheHits: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_cache_hits_total", cfg.ServiceName),
+			Help: "The total number of cache hits by entity type",
+		}, []string{"entity"}),
+		CacheMisses: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_cache_misses_total", cfg.ServiceName),
+			Help: "The total number of cache misses by entity type",
+		}, []string{"entity"}),
+		CacheEvictions: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_cache_evictions_total", cfg.ServiceName),
+			Help: "The total number of cache evictions by entity type",
+		}, []string{"entity"}),
+		CacheStaleWrites: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_cache_stale_writes_total", cfg.ServiceName),
+			Help: "The total number of out of order cache writes ignored by entity type",
+		}, []string{"entity"}),
+		LocalCacheHits: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_local_cache_hits_total", cfg.ServiceName),
+			Help: "The total number of in-process cache hits by entity type",
+		}, []string{"entity"}),
+		LocalCacheMisses: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_local_cache_misses_total", cfg.ServiceName),
+			Help: "The total number of in-process cache misses by entity type",
+		}, []string{"entity"}),
+		LocalCacheEvictions: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_local_cache_evictions_total", cfg.ServiceName),
+			Help: "The total number of in-process cache capacity evictions by entity type",
+		}, []string{"entity"}),
+		LocalCacheInvalidated: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_local_cache_invalidations_total", cfg.ServiceName),
+			Help: "The total number of in-process cache entries dropped by invalidation messages by entity type",
+		}, []string{"entity"}),
 	}
 }
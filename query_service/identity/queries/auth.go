@@ -6,26 +6,12 @@ import (
 )
 
 type AuthQueries struct {
-	Authenticate AuthenticateHandler
-	Validate     ValidateHandler
+	Validate ValidateHandler
 }
 
-func NewAuthQueries(authenticate AuthenticateHandler, validate ValidateHandler) *AuthQueries {
+func NewAuthQueries(validate ValidateHandler) *AuthQueries {
 	return &AuthQueries{
-		Authenticate: authenticate,
-		Validate:     validate,
-	}
-}
-
-type AuthenticateQuery struct {
-	Email    string `json:"email" bson:"email,omitempty"`
-	Password string `json:"password" bson:"password,omitempty"`
-}
-
-func NewAuthenticateQuery(email string, password string) *AuthenticateQuery {
-	return &AuthenticateQuery{
-		Email:    email,
-		Password: password,
+		Validate: validate,
 	}
 }
 
@@ -3,47 +3,18 @@ package queries
 import (
 	"context"
 	"errors"
+	"github.com/JECSand/identity-service/pkg/authentication"
 	"github.com/JECSand/identity-service/pkg/enums"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
+	"github.com/gofrs/uuid"
+	"time"
 )
 
-// AuthenticateHandler ...
-type AuthenticateHandler interface {
-	Handle(ctx context.Context, query *AuthenticateQuery) (*entities.User, error)
-}
-
-type authenticateHandler struct {
-	log        logging.Logger
-	cfg        *config.Config
-	mongoDB    data.Database
-	redisCache cache.Cache
-}
-
-func NewAuthenticateHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *authenticateHandler {
-	return &authenticateHandler{
-		log:        log,
-		cfg:        cfg,
-		mongoDB:    mongoDB,
-		redisCache: redisCache,
-	}
-}
-
-func (q *authenticateHandler) Handle(ctx context.Context, query *AuthenticateQuery) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "authenticateHandler.Handle")
-	defer span.Finish()
-	user, err := q.mongoDB.AuthenticateUser(ctx, query.Email, query.Password)
-	if err != nil {
-		q.log.WarnMsg("mongoDB.AuthenticateUser", err)
-		return nil, err
-	}
-	return user, nil
-}
-
 // ValidateHandler ...
 type ValidateHandler interface {
 	Handle(ctx context.Context, query *ValidateQuery) (*entities.User, error)
@@ -66,33 +37,27 @@ func NewValidateHandler(log logging.Logger, cfg *config.Config, mongoDB data.Dat
 }
 
 func (s *validateHandler) validateToken(ctx context.Context, query *ValidateQuery) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "validateHandler.validateToken")
+	ctx, span := tracing.StartSpan(ctx, "validateHandler.validateToken")
 	ctx, cancel := context.WithCancel(context.Background())
 	errChan := make(chan error)
 	userChan := make(chan *entities.User)
 	defer func() {
-		span.Finish()
+		span.End()
 		cancel()
 		close(errChan)
 		close(userChan)
 	}()
 	go func() { // Ensure token being validated is not in blacklist
-		_, err := s.mongoDB.CheckTokenBlacklist(ctx, query.AccessToken)
+		err := s.checkTokenBlacklist(ctx, query.AccessToken)
 		select {
 		case <-ctx.Done():
 			return
 		default:
 		}
-		if err == nil {
-			err = errors.New("token is blacklisted")
-			s.log.WarnMsg("mongoDB.CheckTokenBlacklist", err)
-		} else if err.Error() == "Decode: mongo: no documents in result" {
-			err = nil
-		}
 		errChan <- err
 	}()
 	go func() { // Ensure userId references a valid user
-		user, err := s.mongoDB.GetUserById(ctx, query.UserID)
+		user, err := s.getUser(ctx, query.UserID)
 		select {
 		case <-ctx.Done():
 			return
@@ -118,32 +83,95 @@ func (s *validateHandler) validateToken(ctx context.Context, query *ValidateQuer
 	if len(errs) > 0 {
 		return &entities.User{}, errs[0]
 	}
+	if err := s.checkUserSessions(ctx, user, query.AccessToken); err != nil {
+		return &entities.User{}, err
+	}
 	return user, nil
 }
 
-func (s *validateHandler) validatePassword(ctx context.Context, query *ValidateQuery) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "validateHandler.validatePassword")
-	defer span.Finish()
-	user, err := s.mongoDB.GetUserById(ctx, query.UserID)
+// checkUserSessions rejects tokens of users that are not active or belong to a suspended organization, or that were
+// issued before the sessions of the user or its organization were revoked; tokens without an iat claim predate every
+// revocation
+func (s *validateHandler) checkUserSessions(ctx context.Context, user *entities.User, accessToken string) error {
+	if err := authentication.CheckUserStatus(user.LifecycleStatus(), user.StatusReason, user.SuspendedUntil, time.Now()); err != nil {
+		return err
+	}
+	issuedAt, err := authentication.TokenIssuedAt(accessToken)
 	if err != nil {
-		s.log.WarnMsg("mongoDB.GetUserById", err)
-		return &entities.User{}, err
+		return err
 	}
-	if err = user.Authenticate(query.AccessToken); err != nil {
-		s.log.WarnMsg("mongoDB.GetUserById", err)
-		return &entities.User{}, err
+	if authentication.SessionRevoked(issuedAt, user.SessionsRevokedAt) {
+		return errors.New("token session was revoked")
+	}
+	organization, err := s.checkOrganization(ctx, user)
+	if err != nil {
+		return err
+	}
+	if organization != nil && authentication.SessionRevoked(issuedAt, organization.SessionsRevokedAt) {
+		return errors.New("token session was revoked")
+	}
+	return nil
+}
+
+// checkOrganization loads the organization of a tenant user, failing when it was suspended or deleted
+func (s *validateHandler) checkOrganization(ctx context.Context, user *entities.User) (*entities.Organization, error) {
+	organizationID := authentication.OrganizationOrDefault(user.OrganizationID)
+	if organizationID == authentication.NoOrganization {
+		return nil, nil
+	}
+	id, err := uuid.FromString(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	organization, err := s.mongoDB.GetOrganizationById(ctx, id)
+	if err != nil || !organization.Active {
+		return nil, authentication.ErrOrganizationSuspended
+	}
+	return organization, nil
+}
+
+// checkTokenBlacklist consults the cache before the blacklist collection, caching both outcomes
+func (s *validateHandler) checkTokenBlacklist(ctx context.Context, accessToken string) error {
+	if cached, err := s.redisCache.GetToken(ctx, accessToken); err == nil && cached != nil {
+		if cached.ID != "" {
+			return errors.New("token is blacklisted")
+		}
+		return nil
+	}
+	// a "not blacklisted" entry is versioned before the lookup so a concurrent blacklisting always supersedes it
+	checkedAt := time.Now()
+	blacklisted, err := s.mongoDB.CheckTokenBlacklist(ctx, accessToken)
+	if err == nil {
+		s.redisCache.PutToken(ctx, accessToken, blacklisted)
+		err = errors.New("token is blacklisted")
+		s.log.WarnMsg("mongoDB.CheckTokenBlacklist", err)
+		return err
+	}
+	if err.Error() == "Decode: mongo: no documents in result" {
+		s.redisCache.PutToken(ctx, accessToken, &entities.Blacklist{AccessToken: accessToken, UpdatedAt: checkedAt})
+		return nil
+	}
+	return err
+}
+
+func (s *validateHandler) getUser(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	if user, err := s.redisCache.GetUser(ctx, id.String()); err == nil && user != nil {
+		return user, nil
+	}
+	user, err := s.mongoDB.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
 	}
+	s.redisCache.PutUser(ctx, user.ID, user)
 	return user, nil
 }
 
 func (s *validateHandler) Handle(ctx context.Context, query *ValidateQuery) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "validateHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "validateHandler.Handle")
+	defer span.End()
 	switch query.ValidationType {
 	case enums.TOKEN:
 		return s.validateToken(ctx, query)
-	case enums.PASSWORD:
-		return s.validatePassword(ctx, query)
 	default:
 		return &entities.User{}, errors.New("invalid ValidationType")
 	}
@@ -0,0 +1,97 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofrs/uuid"
+	"testing"
+	"time"
+)
+
+func newTestLogger() logging.Logger {
+	log := logging.NewAppLogger(&logging.Config{LogLevel: "error"})
+	log.InitLogger()
+	return log
+}
+
+// tokenIssuedAt returns an access token whose iat claim is issuedAt; validation reads the claim unverified
+func tokenIssuedAt(t *testing.T, issuedAt time.Time) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iat": issuedAt.Unix()}).SignedString([]byte("test"))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return token
+}
+
+func TestValidateHandlerCheckUserSessions(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	log := newTestLogger()
+	db := data.NewMemoryDatabase(log, cfg)
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	activeOrg, suspendedOrg, revokedOrg := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	for _, org := range []*entities.Organization{
+		{ID: activeOrg.String(), Name: "active", Active: true},
+		{ID: suspendedOrg.String(), Name: "suspended"},
+		{ID: revokedOrg.String(), Name: "revoked", Active: true, SessionsRevokedAt: revokedAt},
+	} {
+		if _, err := db.CreateOrganization(ctx, org); err != nil {
+			t.Fatalf("CreateOrganization: %v", err)
+		}
+	}
+	h := NewValidateHandler(log, cfg, db, nil)
+	before, after := revokedAt.Add(-time.Minute), revokedAt.Add(time.Minute)
+	tests := []struct {
+		name       string
+		user       entities.User
+		issuedAt   time.Time
+		wantStatus enums.UserStatus
+		wantErr    error
+		wantOK     bool
+	}{
+		{name: "active user", user: entities.User{Status: enums.ACTIVATED}, issuedAt: before, wantOK: true},
+		{name: "suspended user", user: entities.User{Status: enums.SUSPENDED, SessionsRevokedAt: revokedAt}, issuedAt: after, wantStatus: enums.SUSPENDED},
+		{name: "deactivated user", user: entities.User{Status: enums.DEACTIVATED, SessionsRevokedAt: revokedAt}, issuedAt: after, wantStatus: enums.DEACTIVATED},
+		{name: "reactivated user, token issued before the suspension", user: entities.User{Status: enums.ACTIVATED, SessionsRevokedAt: revokedAt}, issuedAt: before},
+		{name: "reactivated user, token issued after the suspension", user: entities.User{Status: enums.ACTIVATED, SessionsRevokedAt: revokedAt}, issuedAt: after, wantOK: true},
+		{name: "ended suspension, token issued before it", user: entities.User{Status: enums.SUSPENDED, SuspendedUntil: time.Now().Add(-time.Minute), SessionsRevokedAt: revokedAt}, issuedAt: before},
+		{name: "ended suspension, token issued after it", user: entities.User{Status: enums.SUSPENDED, SuspendedUntil: time.Now().Add(-time.Minute), SessionsRevokedAt: revokedAt}, issuedAt: after, wantOK: true},
+		{name: "active organization", user: entities.User{Status: enums.ACTIVATED, OrganizationID: activeOrg.String()}, issuedAt: before, wantOK: true},
+		{name: "suspended organization", user: entities.User{Status: enums.ACTIVATED, OrganizationID: suspendedOrg.String()}, issuedAt: after, wantErr: authentication.ErrOrganizationSuspended},
+		{name: "unknown organization", user: entities.User{Status: enums.ACTIVATED, OrganizationID: uuid.Must(uuid.NewV4()).String()}, issuedAt: after, wantErr: authentication.ErrOrganizationSuspended},
+		{name: "organization sessions revoked, token issued before", user: entities.User{Status: enums.ACTIVATED, OrganizationID: revokedOrg.String()}, issuedAt: before},
+		{name: "organization sessions revoked, token issued after", user: entities.User{Status: enums.ACTIVATED, OrganizationID: revokedOrg.String()}, issuedAt: after, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.checkUserSessions(ctx, &tt.user, tokenIssuedAt(t, tt.issuedAt))
+			switch {
+			case tt.wantOK:
+				if err != nil {
+					t.Errorf("checkUserSessions: %v", err)
+				}
+			case tt.wantStatus != 0:
+				var statusErr *authentication.UserStatusError
+				if !errors.As(err, &statusErr) || statusErr.Status != tt.wantStatus {
+					t.Errorf("checkUserSessions: got %v, want status %s", err, tt.wantStatus.Stringify())
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("checkUserSessions: got %v, want %v", err, tt.wantErr)
+				}
+			default:
+				if err == nil {
+					t.Error("checkUserSessions accepted a revoked session")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package queries
+
+import "github.com/gofrs/uuid"
+
+type AuthzQueries struct {
+	Check         CheckHandler
+	BatchCheck    BatchCheckHandler
+	ListResources ListResourcesHandler
+}
+
+func NewAuthzQueries(check CheckHandler, batchCheck BatchCheckHandler, listResources ListResourcesHandler) *AuthzQueries {
+	return &AuthzQueries{
+		Check:         check,
+		BatchCheck:    batchCheck,
+		ListResources: listResources,
+	}
+}
+
+// CheckQuery asks whether the user SubjectID may take Action on the group ResourceID
+type CheckQuery struct {
+	SubjectID  uuid.UUID `json:"subjectID" validate:"required"`
+	Action     string    `json:"action" validate:"required,lte=250"`
+	ResourceID uuid.UUID `json:"resourceID" validate:"required"`
+	TenantID   string    `json:"tenantID"`
+}
+
+func NewCheckQuery(subjectID uuid.UUID, action string, resourceID uuid.UUID, tenantID string) *CheckQuery {
+	return &CheckQuery{
+		SubjectID:  subjectID,
+		Action:     action,
+		ResourceID: resourceID,
+		TenantID:   tenantID,
+	}
+}
+
+type BatchCheckQuery struct {
+	Checks   []*CheckQuery `json:"checks" validate:"required,min=1,max=100,dive"`
+	TenantID string        `json:"tenantID"`
+}
+
+func NewBatchCheckQuery(checks []*CheckQuery, tenantID string) *BatchCheckQuery {
+	return &BatchCheckQuery{Checks: checks, TenantID: tenantID}
+}
+
+// ListResourcesQuery asks for the groups the user SubjectID may take Action on
+type ListResourcesQuery struct {
+	SubjectID uuid.UUID `json:"subjectID" validate:"required"`
+	Action    string    `json:"action" validate:"required,lte=250"`
+	TenantID  string    `json:"tenantID"`
+}
+
+func NewListResourcesQuery(subjectID uuid.UUID, action string, tenantID string) *ListResourcesQuery {
+	return &ListResourcesQuery{SubjectID: subjectID, Action: action, TenantID: tenantID}
+}
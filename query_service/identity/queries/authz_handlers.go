@@ -0,0 +1,364 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	reasonRoot            = "subject is root"
+	reasonGranted         = "granted by membership"
+	reasonNotGranted      = "no membership grants the action"
+	reasonUnknownSubject  = "subject not found"
+	reasonInactiveSubject = "subject is not active"
+	reasonUnknownResource = "resource not found"
+)
+
+// authorizer decides checks from the read model, keeping what it loads so that the checks of a batch share it
+type authorizer struct {
+	mongoDB  data.Database
+	tenantID string
+	now      time.Time
+	users    map[uuid.UUID]*entities.User
+	groups   map[uuid.UUID]*entities.Group
+	roles    map[string][]string
+	grants   map[uuid.UUID]map[string][]string
+}
+
+func newAuthorizer(mongoDB data.Database, tenantID string) *authorizer {
+	return &authorizer{
+		mongoDB:  mongoDB,
+		tenantID: tenantID,
+		now:      time.Now(),
+		users:    make(map[uuid.UUID]*entities.User),
+		groups:   make(map[uuid.UUID]*entities.Group),
+		roles:    make(map[string][]string),
+		grants:   make(map[uuid.UUID]map[string][]string),
+	}
+}
+
+// subject returns the user with id, or nil when it does not exist in the tenant
+func (a *authorizer) subject(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	if user, ok := a.users[id]; ok {
+		return user, nil
+	}
+	user, err := a.mongoDB.GetUserById(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		user, err = nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "GetUserById")
+	}
+	if user != nil && !authentication.InTenant(a.tenantID, user.OrganizationID) {
+		user = nil
+	}
+	a.users[id] = user
+	return user, nil
+}
+
+// group returns the group with id, or nil when it does not exist in the tenant
+func (a *authorizer) group(ctx context.Context, id uuid.UUID) (*entities.Group, error) {
+	if group, ok := a.groups[id]; ok {
+		return group, nil
+	}
+	group, err := a.mongoDB.GetGroupById(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		group, err = nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "GetGroupById")
+	}
+	if group != nil && !authentication.InTenant(a.tenantID, group.OrganizationID) {
+		group = nil
+	}
+	a.groups[id] = group
+	return group, nil
+}
+
+// permissions returns the permission set a membership grants, falling back to its role for memberships projected
+// before roles carried permissions
+func (a *authorizer) permissions(ctx context.Context, membership *entities.Membership) ([]string, error) {
+	if len(membership.Permissions) > 0 {
+		return membership.Permissions, nil
+	}
+	if membership.RoleID == "" {
+		return membership.Role.Permissions(), nil
+	}
+	if permissions, ok := a.roles[membership.RoleID]; ok {
+		return permissions, nil
+	}
+	role, err := a.mongoDB.GetRoleById(ctx, uuid.FromStringOrNil(membership.RoleID))
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.Wrap(err, "GetRoleById")
+	}
+	var permissions []string
+	if err == nil {
+		permissions = role.Permissions
+	}
+	a.roles[membership.RoleID] = permissions
+	return permissions, nil
+}
+
+// grantsOf returns the permissions the memberships of a user grant by group id; only memberships that are ACTIVE
+// within their validity window grant anything
+func (a *authorizer) grantsOf(ctx context.Context, userID uuid.UUID) (map[string][]string, error) {
+	if grants, ok := a.grants[userID]; ok {
+		return grants, nil
+	}
+	memberships, err := a.mongoDB.GetMembershipsByUserId(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetMembershipsByUserId")
+	}
+	grants := make(map[string][]string)
+	for _, membership := range memberships {
+		if authentication.EffectiveMembershipStatus(membership.Status, membership.ValidFrom, membership.ValidUntil, a.now) != enums.ACTIVE {
+			continue
+		}
+		permissions, err := a.permissions(ctx, membership)
+		if err != nil {
+			return nil, err
+		}
+		groupID := uuid.FromStringOrNil(membership.GroupID).String()
+		grants[groupID] = append(grants[groupID], permissions...)
+	}
+	a.grants[userID] = grants
+	return grants, nil
+}
+
+// check allows root subjects everything and other active subjects the actions a membership in the resource group,
+// or in one of its ancestors, grants
+func (a *authorizer) check(ctx context.Context, query *CheckQuery) (*entities.Decision, error) {
+	decision := &entities.Decision{
+		SubjectID:  query.SubjectID.String(),
+		Action:     strings.ToLower(strings.TrimSpace(query.Action)),
+		ResourceID: query.ResourceID.String(),
+	}
+	if err := authentication.CheckAction(decision.Action); err != nil {
+		return nil, err
+	}
+	user, err := a.subject(ctx, query.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	if decision.Reason = subjectDenial(user); decision.Reason != "" {
+		return decision, nil
+	}
+	resource, err := a.group(ctx, query.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if resource == nil {
+		decision.Reason = reasonUnknownResource
+		return decision, nil
+	}
+	if user.Root {
+		decision.Allowed, decision.Reason = true, reasonRoot
+		return decision, nil
+	}
+	grants, err := a.grantsOf(ctx, query.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	visited := make(map[string]bool)
+	for group := resource; group != nil && !visited[group.ID]; {
+		visited[group.ID] = true
+		groupID := uuid.FromStringOrNil(group.ID).String()
+		if authentication.PermissionGranted(grants[groupID], decision.Action) {
+			decision.Allowed, decision.Reason, decision.GrantedBy = true, reasonGranted, groupID
+			return decision, nil
+		}
+		parentID := uuid.FromStringOrNil(group.ParentID)
+		if parentID == uuid.Nil {
+			break
+		}
+		if group, err = a.group(ctx, parentID); err != nil {
+			return nil, err
+		}
+	}
+	decision.Reason = reasonNotGranted
+	return decision, nil
+}
+
+// listResources returns the groups a membership grants the action in together with their subgroups
+func (a *authorizer) listResources(ctx context.Context, query *ListResourcesQuery) (*entities.ResourceList, error) {
+	resources := &entities.ResourceList{
+		SubjectID:   query.SubjectID.String(),
+		Action:      strings.ToLower(strings.TrimSpace(query.Action)),
+		ResourceIDs: make([]string, 0),
+	}
+	if err := authentication.CheckAction(resources.Action); err != nil {
+		return nil, err
+	}
+	user, err := a.subject(ctx, query.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	if subjectDenial(user) != "" {
+		return resources, nil
+	}
+	if user.Root {
+		resources.All = true
+		return resources, nil
+	}
+	grants, err := a.grantsOf(ctx, query.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]uuid.UUID, 0, len(grants))
+	for groupID, permissions := range grants {
+		if authentication.PermissionGranted(permissions, resources.Action) {
+			pending = append(pending, uuid.FromStringOrNil(groupID))
+		}
+	}
+	found := make(map[uuid.UUID]bool)
+	for len(pending) > 0 {
+		groupID := pending[0]
+		pending = pending[1:]
+		if found[groupID] {
+			continue
+		}
+		group, err := a.group(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if group == nil {
+			continue
+		}
+		found[groupID] = true
+		resources.ResourceIDs = append(resources.ResourceIDs, groupID.String())
+		children, err := a.mongoDB.GetChildGroups(ctx, groupID)
+		if err != nil {
+			return nil, errors.Wrap(err, "GetChildGroups")
+		}
+		for _, child := range children {
+			childID := uuid.FromStringOrNil(child.ID)
+			a.groups[childID] = child
+			pending = append(pending, childID)
+		}
+	}
+	sort.Strings(resources.ResourceIDs)
+	return resources, nil
+}
+
+// subjectDenial returns why a subject is denied every action, or an empty string when it may be granted some
+func subjectDenial(user *entities.User) string {
+	switch {
+	case user == nil:
+		return reasonUnknownSubject
+	case user.LifecycleStatus() != enums.ACTIVATED:
+		return reasonInactiveSubject
+	}
+	return ""
+}
+
+// logDecision logs an authorization decision when decision logging is enabled
+func logDecision(ctx context.Context, log logging.Logger, cfg *config.Config, decision *entities.Decision) {
+	if !cfg.Authz.LogDecisions {
+		return
+	}
+	log.WithContext(ctx).Infof("authz decision: subject=%s action=%s resource=%s allowed=%t reason=%q grantedBy=%s",
+		decision.SubjectID, decision.Action, decision.ResourceID, decision.Allowed, decision.Reason, decision.GrantedBy)
+}
+
+// CheckHandler ...
+type CheckHandler interface {
+	Handle(ctx context.Context, query *CheckQuery) (*entities.Decision, error)
+}
+
+type checkHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewCheckHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *checkHandler {
+	return &checkHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (q *checkHandler) Handle(ctx context.Context, query *CheckQuery) (*entities.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, "checkHandler.Handle")
+	defer span.End()
+	decision, err := newAuthorizer(q.mongoDB, query.TenantID).check(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	logDecision(ctx, q.log, q.cfg, decision)
+	return decision, nil
+}
+
+// BatchCheckHandler ...
+type BatchCheckHandler interface {
+	Handle(ctx context.Context, query *BatchCheckQuery) ([]*entities.Decision, error)
+}
+
+type batchCheckHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewBatchCheckHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *batchCheckHandler {
+	return &batchCheckHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+// Handle decides the checks of a batch in order; every check is scoped to the tenant of the batch
+func (q *batchCheckHandler) Handle(ctx context.Context, query *BatchCheckQuery) ([]*entities.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, "batchCheckHandler.Handle")
+	defer span.End()
+	a := newAuthorizer(q.mongoDB, query.TenantID)
+	decisions := make([]*entities.Decision, 0, len(query.Checks))
+	for _, check := range query.Checks {
+		decision, err := a.check(ctx, check)
+		if err != nil {
+			return nil, err
+		}
+		logDecision(ctx, q.log, q.cfg, decision)
+		decisions = append(decisions, decision)
+	}
+	return decisions, nil
+}
+
+// ListResourcesHandler ...
+type ListResourcesHandler interface {
+	Handle(ctx context.Context, query *ListResourcesQuery) (*entities.ResourceList, error)
+}
+
+type listResourcesHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewListResourcesHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *listResourcesHandler {
+	return &listResourcesHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (q *listResourcesHandler) Handle(ctx context.Context, query *ListResourcesQuery) (*entities.ResourceList, error) {
+	ctx, span := tracing.StartSpan(ctx, "listResourcesHandler.Handle")
+	defer span.End()
+	return newAuthorizer(q.mongoDB, query.TenantID).listResources(ctx, query)
+}
@@ -0,0 +1,179 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/enums"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+	"github.com/gofrs/uuid"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// authzFixture is a read model of two organizations with nested groups and members of various states
+type authzFixture struct {
+	db                                data.Database
+	org, otherOrg                     string
+	root, member, suspended, wildcard uuid.UUID
+	foreignUser                       uuid.UUID
+	finance, payables, invoices, hr   uuid.UUID
+	legal, audit, foreignGroup        uuid.UUID
+}
+
+func newAuthzFixture(t *testing.T) *authzFixture {
+	t.Helper()
+	ctx := context.Background()
+	newID := func() uuid.UUID { return uuid.Must(uuid.NewV4()) }
+	f := &authzFixture{
+		db:       data.NewMemoryDatabase(newTestLogger(), &config.Config{}),
+		org:      newID().String(),
+		otherOrg: newID().String(),
+		root:     newID(), member: newID(), suspended: newID(), wildcard: newID(), foreignUser: newID(),
+		finance: newID(), payables: newID(), invoices: newID(), hr: newID(),
+		legal: newID(), audit: newID(), foreignGroup: newID(),
+	}
+	for _, user := range []*entities.User{
+		{ID: f.root.String(), Root: true, Status: enums.ACTIVATED},
+		{ID: f.member.String(), Status: enums.ACTIVATED, OrganizationID: f.org},
+		{ID: f.suspended.String(), Status: enums.SUSPENDED, OrganizationID: f.org},
+		{ID: f.wildcard.String(), Status: enums.ACTIVATED, OrganizationID: f.org},
+		{ID: f.foreignUser.String(), Status: enums.ACTIVATED, OrganizationID: f.otherOrg},
+	} {
+		if _, err := f.db.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+	for _, group := range []*entities.Group{
+		{ID: f.finance.String(), Name: "finance", OrganizationID: f.org, Active: true},
+		{ID: f.payables.String(), Name: "payables", OrganizationID: f.org, Active: true, ParentID: f.finance.String()},
+		{ID: f.invoices.String(), Name: "invoices", OrganizationID: f.org, Active: true, ParentID: f.payables.String()},
+		{ID: f.hr.String(), Name: "hr", OrganizationID: f.org, Active: true},
+		{ID: f.legal.String(), Name: "legal", OrganizationID: f.org, Active: true},
+		{ID: f.audit.String(), Name: "audit", OrganizationID: f.org, Active: true},
+		{ID: f.foreignGroup.String(), Name: "foreign", OrganizationID: f.otherOrg, Active: true},
+	} {
+		if _, err := f.db.CreateGroup(ctx, group); err != nil {
+			t.Fatalf("CreateGroup: %v", err)
+		}
+	}
+	role := &entities.Role{ID: newID().String(), Name: "hr-reader", Permissions: []string{"hr.read"}, OrganizationID: f.org}
+	if _, err := f.db.CreateRole(ctx, role); err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	now := time.Now()
+	for _, membership := range []*entities.Membership{
+		{UserID: f.member.String(), GroupID: f.finance.String(), Status: enums.ACTIVE, Permissions: []string{"billing.*"}},
+		{UserID: f.member.String(), GroupID: f.hr.String(), Status: enums.ACTIVE, RoleID: role.ID},
+		{UserID: f.member.String(), GroupID: f.legal.String(), Status: enums.ACTIVE, Permissions: []string{"legal.read"}, ValidUntil: now.Add(-time.Hour)},
+		{UserID: f.member.String(), GroupID: f.audit.String(), Status: enums.ACTIVE, Permissions: []string{"audit.read"}, ValidFrom: now.Add(time.Hour)},
+		{UserID: f.member.String(), GroupID: f.foreignGroup.String(), Status: enums.ACTIVE, Permissions: []string{"billing.*"}},
+		{UserID: f.suspended.String(), GroupID: f.finance.String(), Status: enums.ACTIVE, Permissions: []string{"*"}},
+		{UserID: f.wildcard.String(), GroupID: f.hr.String(), Status: enums.ACTIVE, Permissions: []string{"*"}},
+	} {
+		membership.ID, membership.OrganizationID = newID().String(), f.org
+		if _, err := f.db.CreateMembership(ctx, membership); err != nil {
+			t.Fatalf("CreateMembership: %v", err)
+		}
+	}
+	return f
+}
+
+func TestAuthorizerCheck(t *testing.T) {
+	f := newAuthzFixture(t)
+	tests := []struct {
+		name        string
+		subject     uuid.UUID
+		action      string
+		resource    uuid.UUID
+		tenant      string
+		wantAllowed bool
+		wantReason  string
+		wantGrantBy uuid.UUID
+		wantErr     bool
+	}{
+		{name: "root", subject: f.root, action: "anything.at.all", resource: f.invoices, wantAllowed: true, wantReason: reasonRoot},
+		{name: "root on an unknown resource", subject: f.root, action: "group.read", resource: uuid.Must(uuid.NewV4()), wantReason: reasonUnknownResource},
+		{name: "unknown subject", subject: uuid.Must(uuid.NewV4()), action: "group.read", resource: f.finance, tenant: f.org, wantReason: reasonUnknownSubject},
+		{name: "inactive subject", subject: f.suspended, action: "group.read", resource: f.finance, tenant: f.org, wantReason: reasonInactiveSubject},
+		{name: "grant in the resource", subject: f.member, action: "billing.read", resource: f.finance, tenant: f.org, wantAllowed: true, wantReason: reasonGranted, wantGrantBy: f.finance},
+		{name: "grant inherited from the parent", subject: f.member, action: "billing.read", resource: f.payables, tenant: f.org, wantAllowed: true, wantReason: reasonGranted, wantGrantBy: f.finance},
+		{name: "grant inherited from an ancestor", subject: f.member, action: "billing.invoices.approve", resource: f.invoices, tenant: f.org, wantAllowed: true, wantReason: reasonGranted, wantGrantBy: f.finance},
+		{name: "wildcard covers its prefix", subject: f.member, action: "billing", resource: f.finance, tenant: f.org, wantAllowed: true, wantReason: reasonGranted, wantGrantBy: f.finance},
+		{name: "wildcard does not cover other names", subject: f.member, action: "billingx.read", resource: f.finance, tenant: f.org, wantReason: reasonNotGranted},
+		{name: "action normalized", subject: f.member, action: " Billing.Read ", resource: f.finance, tenant: f.org, wantAllowed: true, wantReason: reasonGranted, wantGrantBy: f.finance},
+		{name: "grant of a custom role", subject: f.member, action: "hr.read", resource: f.hr, tenant: f.org, wantAllowed: true, wantReason: reasonGranted, wantGrantBy: f.hr},
+		{name: "action outside the role", subject: f.member, action: "hr.write", resource: f.hr, tenant: f.org, wantReason: reasonNotGranted},
+		{name: "grant of another group", subject: f.member, action: "billing.read", resource: f.hr, tenant: f.org, wantReason: reasonNotGranted},
+		{name: "expired membership", subject: f.member, action: "legal.read", resource: f.legal, tenant: f.org, wantReason: reasonNotGranted},
+		{name: "not yet valid membership", subject: f.member, action: "audit.read", resource: f.audit, tenant: f.org, wantReason: reasonNotGranted},
+		{name: "cross-tenant resource", subject: f.member, action: "billing.read", resource: f.foreignGroup, tenant: f.org, wantReason: reasonUnknownResource},
+		{name: "cross-tenant subject", subject: f.foreignUser, action: "group.read", resource: f.finance, tenant: f.org, wantReason: reasonUnknownSubject},
+		{name: "wildcard permission", subject: f.wildcard, action: "anything.at.all", resource: f.hr, tenant: f.org, wantAllowed: true, wantReason: reasonGranted, wantGrantBy: f.hr},
+		{name: "invalid action", subject: f.member, action: "not an action", resource: f.finance, tenant: f.org, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := newAuthorizer(f.db, tt.tenant).check(context.Background(), NewCheckQuery(tt.subject, tt.action, tt.resource, tt.tenant))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("check: got %+v, want an error", decision)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("check: %v", err)
+			}
+			var wantGrantedBy string
+			if tt.wantGrantBy != uuid.Nil {
+				wantGrantedBy = tt.wantGrantBy.String()
+			}
+			if decision.Allowed != tt.wantAllowed || decision.Reason != tt.wantReason || decision.GrantedBy != wantGrantedBy {
+				t.Errorf("check: got allowed=%v reason=%q grantedBy=%q, want allowed=%v reason=%q grantedBy=%q",
+					decision.Allowed, decision.Reason, decision.GrantedBy, tt.wantAllowed, tt.wantReason, wantGrantedBy)
+			}
+		})
+	}
+}
+
+func TestAuthorizerListResources(t *testing.T) {
+	f := newAuthzFixture(t)
+	ids := func(ids ...uuid.UUID) []string {
+		list := make([]string, 0, len(ids))
+		for _, id := range ids {
+			list = append(list, id.String())
+		}
+		sort.Strings(list)
+		return list
+	}
+	tests := []struct {
+		name    string
+		subject uuid.UUID
+		action  string
+		tenant  string
+		wantAll bool
+		wantIDs []string
+	}{
+		{name: "root", subject: f.root, action: "billing.read", wantAll: true, wantIDs: ids()},
+		{name: "inactive subject", subject: f.suspended, action: "billing.read", tenant: f.org, wantIDs: ids()},
+		{name: "unknown subject", subject: uuid.Must(uuid.NewV4()), action: "billing.read", tenant: f.org, wantIDs: ids()},
+		{name: "grant including subgroups", subject: f.member, action: "billing.read", tenant: f.org, wantIDs: ids(f.finance, f.payables, f.invoices)},
+		{name: "grant including subgroups across tenants for root sessions", subject: f.member, action: "billing.read", wantIDs: ids(f.finance, f.payables, f.invoices, f.foreignGroup)},
+		{name: "custom role", subject: f.member, action: "hr.read", tenant: f.org, wantIDs: ids(f.hr)},
+		{name: "expired and not yet valid memberships", subject: f.member, action: "legal.read", tenant: f.org, wantIDs: ids()},
+		{name: "wildcard permission", subject: f.wildcard, action: "anything.at.all", tenant: f.org, wantIDs: ids(f.hr)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := newAuthorizer(f.db, tt.tenant).listResources(context.Background(), NewListResourcesQuery(tt.subject, tt.action, tt.tenant))
+			if err != nil {
+				t.Fatalf("listResources: %v", err)
+			}
+			if resources.All != tt.wantAll || !reflect.DeepEqual(resources.ResourceIDs, tt.wantIDs) {
+				t.Errorf("listResources: got all=%v ids=%v, want all=%v ids=%v", resources.All, resources.ResourceIDs, tt.wantAll, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -18,21 +18,24 @@ func NewGroupQueries(getById GetGroupByIdHandler, search SearchGroupHandler) *Gr
 }
 
 type GetGroupByIdQuery struct {
-	ID uuid.UUID `json:"id" bson:"_id,omitempty"`
+	ID       uuid.UUID `json:"id" bson:"_id,omitempty"`
+	TenantID string    `json:"tenantID"`
 }
 
-func NewGetGroupByIdQuery(id uuid.UUID) *GetGroupByIdQuery {
-	return &GetGroupByIdQuery{ID: id}
+func NewGetGroupByIdQuery(id uuid.UUID, tenantID string) *GetGroupByIdQuery {
+	return &GetGroupByIdQuery{ID: id, TenantID: tenantID}
 }
 
 type SearchGroupQuery struct {
 	Text       string                `json:"text"`
+	TenantID   string                `json:"tenantID"`
 	Pagination *utilities.Pagination `json:"pagination"`
 }
 
-func NewSearchGroupQuery(text string, pagination *utilities.Pagination) *SearchGroupQuery {
+func NewSearchGroupQuery(text string, tenantID string, pagination *utilities.Pagination) *SearchGroupQuery {
 	return &SearchGroupQuery{
 		Text:       text,
+		TenantID:   tenantID,
 		Pagination: pagination,
 	}
 }
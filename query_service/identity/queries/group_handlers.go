@@ -3,11 +3,12 @@ package queries
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
+	"golang.org/x/sync/singleflight"
 )
 
 // GetGroupByIdHandler ...
@@ -20,6 +21,7 @@ type getGroupByIdHandler struct {
 	cfg        *config.Config
 	mongoDB    data.Database
 	redisCache cache.Cache
+	loader     singleflight.Group
 }
 
 func NewGetGroupByIdHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *getGroupByIdHandler {
@@ -32,17 +34,25 @@ func NewGetGroupByIdHandler(log logging.Logger, cfg *config.Config, mongoDB data
 }
 
 func (q *getGroupByIdHandler) Handle(ctx context.Context, query *GetGroupByIdQuery) (*entities.Group, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getGroupByIdHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "getGroupByIdHandler.Handle")
+	defer span.End()
 	if group, err := q.redisCache.GetGroup(ctx, query.ID.String()); err == nil && group != nil {
-		return group, nil
+		return inTenant(group, query.TenantID, group.OrganizationID)
 	}
-	group, err := q.mongoDB.GetGroupById(ctx, query.ID)
+	// collapse concurrent misses for the same id into a single database load
+	loaded, err, _ := q.loader.Do(query.ID.String(), func() (interface{}, error) {
+		group, err := q.mongoDB.GetGroupById(ctx, query.ID)
+		if err != nil {
+			return nil, err
+		}
+		q.redisCache.PutGroup(ctx, group.ID, group)
+		return group, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	q.redisCache.PutGroup(ctx, group.ID, group)
-	return group, nil
+	group := loaded.(*entities.Group)
+	return inTenant(group, query.TenantID, group.OrganizationID)
 }
 
 // SearchGroupHandler ...
@@ -67,5 +77,5 @@ func NewSearchGroupHandler(log logging.Logger, cfg *config.Config, mongoDB data.
 }
 
 func (s *searchGroupHandler) Handle(ctx context.Context, query *SearchGroupQuery) (*entities.GroupsList, error) {
-	return s.mongoDB.SearchGroups(ctx, query.Text, query.Pagination)
+	return s.mongoDB.SearchGroups(ctx, query.Text, query.TenantID, query.Pagination)
 }
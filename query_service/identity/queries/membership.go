@@ -6,47 +6,90 @@ import (
 )
 
 type MembershipQueries struct {
-	GetMembershipById  GetMembershipByIdHandler
-	GetGroupMembership GetGroupMembershipHandler
-	GetUserMembership  GetUserMembershipHandler
+	GetMembershipById   GetMembershipByIdHandler
+	GetGroupMembership  GetGroupMembershipHandler
+	GetUserMembership   GetUserMembershipHandler
+	GetEffectiveGroups  GetEffectiveGroupsHandler
+	GetEffectiveMembers GetEffectiveMembersHandler
 }
 
-func NewMembershipQueries(getById GetMembershipByIdHandler, userGroups GetGroupMembershipHandler, groupUsers GetUserMembershipHandler) *MembershipQueries {
+func NewMembershipQueries(
+	getById GetMembershipByIdHandler,
+	userGroups GetGroupMembershipHandler,
+	groupUsers GetUserMembershipHandler,
+	effectiveGroups GetEffectiveGroupsHandler,
+	effectiveMembers GetEffectiveMembersHandler,
+) *MembershipQueries {
 	return &MembershipQueries{
-		GetMembershipById:  getById,
-		GetGroupMembership: userGroups,
-		GetUserMembership:  groupUsers,
+		GetMembershipById:   getById,
+		GetGroupMembership:  userGroups,
+		GetUserMembership:   groupUsers,
+		GetEffectiveGroups:  effectiveGroups,
+		GetEffectiveMembers: effectiveMembers,
 	}
 }
 
 type GetMembershipByIdQuery struct {
-	ID uuid.UUID `json:"id" bson:"_id,omitempty"`
+	ID       uuid.UUID `json:"id" bson:"_id,omitempty"`
+	TenantID string    `json:"tenantID"`
 }
 
-func NewGetMembershipByIdQuery(id uuid.UUID) *GetMembershipByIdQuery {
-	return &GetMembershipByIdQuery{ID: id}
+func NewGetMembershipByIdQuery(id uuid.UUID, tenantID string) *GetMembershipByIdQuery {
+	return &GetMembershipByIdQuery{ID: id, TenantID: tenantID}
 }
 
 type GetGroupMembershipQuery struct {
 	UserID     uuid.UUID             `json:"userID"`
+	TenantID   string                `json:"tenantID"`
 	Pagination *utilities.Pagination `json:"pagination"`
 }
 
-func NewGetGroupMembershipQuery(userID uuid.UUID, pagination *utilities.Pagination) *GetGroupMembershipQuery {
+func NewGetGroupMembershipQuery(userID uuid.UUID, tenantID string, pagination *utilities.Pagination) *GetGroupMembershipQuery {
 	return &GetGroupMembershipQuery{
 		UserID:     userID,
+		TenantID:   tenantID,
 		Pagination: pagination,
 	}
 }
 
 type GetUserMembershipQuery struct {
 	GroupID    uuid.UUID             `json:"groupID"`
+	TenantID   string                `json:"tenantID"`
 	Pagination *utilities.Pagination `json:"pagination"`
 }
 
-func NewGetUserMembershipQuery(groupID uuid.UUID, pagination *utilities.Pagination) *GetUserMembershipQuery {
+func NewGetUserMembershipQuery(groupID uuid.UUID, tenantID string, pagination *utilities.Pagination) *GetUserMembershipQuery {
 	return &GetUserMembershipQuery{
 		GroupID:    groupID,
+		TenantID:   tenantID,
+		Pagination: pagination,
+	}
+}
+
+type GetEffectiveGroupsQuery struct {
+	UserID     uuid.UUID             `json:"userID"`
+	TenantID   string                `json:"tenantID"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewGetEffectiveGroupsQuery(userID uuid.UUID, tenantID string, pagination *utilities.Pagination) *GetEffectiveGroupsQuery {
+	return &GetEffectiveGroupsQuery{
+		UserID:     userID,
+		TenantID:   tenantID,
+		Pagination: pagination,
+	}
+}
+
+type GetEffectiveMembersQuery struct {
+	GroupID    uuid.UUID             `json:"groupID"`
+	TenantID   string                `json:"tenantID"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewGetEffectiveMembersQuery(groupID uuid.UUID, tenantID string, pagination *utilities.Pagination) *GetEffectiveMembersQuery {
+	return &GetEffectiveMembersQuery{
+		GroupID:    groupID,
+		TenantID:   tenantID,
 		Pagination: pagination,
 	}
 }
@@ -3,11 +3,12 @@ package queries
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
+	"golang.org/x/sync/singleflight"
 )
 
 // GetMembershipByIdHandler ...
@@ -20,6 +21,7 @@ type getMembershipByIdHandler struct {
 	cfg        *config.Config
 	mongoDB    data.Database
 	redisCache cache.Cache
+	loader     singleflight.Group
 }
 
 func NewGetMembershipByIdHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *getMembershipByIdHandler {
@@ -32,17 +34,25 @@ func NewGetMembershipByIdHandler(log logging.Logger, cfg *config.Config, mongoDB
 }
 
 func (q *getMembershipByIdHandler) Handle(ctx context.Context, query *GetMembershipByIdQuery) (*entities.Membership, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getMembershipByIdHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "getMembershipByIdHandler.Handle")
+	defer span.End()
 	if membership, err := q.redisCache.GetMembership(ctx, query.ID.String()); err == nil && membership != nil {
-		return membership, nil
+		return inTenant(membership, query.TenantID, membership.OrganizationID)
 	}
-	membership, err := q.mongoDB.GetMembershipById(ctx, query.ID)
+	// collapse concurrent misses for the same id into a single database load
+	loaded, err, _ := q.loader.Do(query.ID.String(), func() (interface{}, error) {
+		membership, err := q.mongoDB.GetMembershipById(ctx, query.ID)
+		if err != nil {
+			return nil, err
+		}
+		q.redisCache.PutMembership(ctx, membership.ID, membership)
+		return membership, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	q.redisCache.PutMembership(ctx, membership.ID, membership)
-	return membership, nil
+	membership := loaded.(*entities.Membership)
+	return inTenant(membership, query.TenantID, membership.OrganizationID)
 }
 
 // GetGroupMembershipHandler ...
@@ -67,9 +77,9 @@ func NewGetGroupMembershipHandler(log logging.Logger, cfg *config.Config, mongoD
 }
 
 func (s *getGroupMembershipHandler) Handle(ctx context.Context, query *GetGroupMembershipQuery) (*entities.GroupMembershipsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getGroupMembershipHandler.Handle")
-	defer span.Finish()
-	return s.mongoDB.GetGroupMembershipByUserId(ctx, query.UserID, query.Pagination)
+	ctx, span := tracing.StartSpan(ctx, "getGroupMembershipHandler.Handle")
+	defer span.End()
+	return s.mongoDB.GetGroupMembershipByUserId(ctx, query.UserID, query.TenantID, query.Pagination)
 }
 
 // GetUserMembershipHandler ...
@@ -94,7 +104,61 @@ func NewGetUserMembershipHandler(log logging.Logger, cfg *config.Config, mongoDB
 }
 
 func (s *getUserMembershipHandler) Handle(ctx context.Context, query *GetUserMembershipQuery) (*entities.UserMembershipsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getUserMembershipHandler.Handle")
-	defer span.Finish()
-	return s.mongoDB.GetUserMembershipByGroupId(ctx, query.GroupID, query.Pagination)
+	ctx, span := tracing.StartSpan(ctx, "getUserMembershipHandler.Handle")
+	defer span.End()
+	return s.mongoDB.GetUserMembershipByGroupId(ctx, query.GroupID, query.TenantID, query.Pagination)
+}
+
+// GetEffectiveGroupsHandler ...
+type GetEffectiveGroupsHandler interface {
+	Handle(ctx context.Context, query *GetEffectiveGroupsQuery) (*entities.EffectiveMembershipsList, error)
+}
+
+type getEffectiveGroupsHandler struct {
+	log        logging.Logger
+	cfg        *config.Config
+	mongoDB    data.Database
+	redisCache cache.Cache
+}
+
+func NewGetEffectiveGroupsHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *getEffectiveGroupsHandler {
+	return &getEffectiveGroupsHandler{
+		log:        log,
+		cfg:        cfg,
+		mongoDB:    mongoDB,
+		redisCache: redisCache,
+	}
+}
+
+func (s *getEffectiveGroupsHandler) Handle(ctx context.Context, query *GetEffectiveGroupsQuery) (*entities.EffectiveMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "getEffectiveGroupsHandler.Handle")
+	defer span.End()
+	return s.mongoDB.GetEffectiveMembershipByUserId(ctx, query.UserID, query.TenantID, query.Pagination)
+}
+
+// GetEffectiveMembersHandler ...
+type GetEffectiveMembersHandler interface {
+	Handle(ctx context.Context, query *GetEffectiveMembersQuery) (*entities.EffectiveMembershipsList, error)
+}
+
+type getEffectiveMembersHandler struct {
+	log        logging.Logger
+	cfg        *config.Config
+	mongoDB    data.Database
+	redisCache cache.Cache
+}
+
+func NewGetEffectiveMembersHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *getEffectiveMembersHandler {
+	return &getEffectiveMembersHandler{
+		log:        log,
+		cfg:        cfg,
+		mongoDB:    mongoDB,
+		redisCache: redisCache,
+	}
+}
+
+func (s *getEffectiveMembersHandler) Handle(ctx context.Context, query *GetEffectiveMembersQuery) (*entities.EffectiveMembershipsList, error) {
+	ctx, span := tracing.StartSpan(ctx, "getEffectiveMembersHandler.Handle")
+	defer span.End()
+	return s.mongoDB.GetEffectiveMembershipByGroupId(ctx, query.GroupID, query.TenantID, query.Pagination)
 }
@@ -0,0 +1,39 @@
+package queries
+
+import (
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/gofrs/uuid"
+)
+
+type OrganizationQueries struct {
+	GetOrganizationById GetOrganizationByIdHandler
+	SearchOrganization  SearchOrganizationHandler
+}
+
+func NewOrganizationQueries(getById GetOrganizationByIdHandler, search SearchOrganizationHandler) *OrganizationQueries {
+	return &OrganizationQueries{
+		GetOrganizationById: getById,
+		SearchOrganization:  search,
+	}
+}
+
+type GetOrganizationByIdQuery struct {
+	ID       uuid.UUID `json:"id" bson:"_id,omitempty"`
+	TenantID string    `json:"tenantID"`
+}
+
+func NewGetOrganizationByIdQuery(id uuid.UUID, tenantID string) *GetOrganizationByIdQuery {
+	return &GetOrganizationByIdQuery{ID: id, TenantID: tenantID}
+}
+
+type SearchOrganizationQuery struct {
+	Text       string                `json:"text"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewSearchOrganizationQuery(text string, pagination *utilities.Pagination) *SearchOrganizationQuery {
+	return &SearchOrganizationQuery{
+		Text:       text,
+		Pagination: pagination,
+	}
+}
@@ -0,0 +1,62 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+)
+
+// GetOrganizationByIdHandler ...
+type GetOrganizationByIdHandler interface {
+	Handle(ctx context.Context, query *GetOrganizationByIdQuery) (*entities.Organization, error)
+}
+
+type getOrganizationByIdHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewGetOrganizationByIdHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *getOrganizationByIdHandler {
+	return &getOrganizationByIdHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (q *getOrganizationByIdHandler) Handle(ctx context.Context, query *GetOrganizationByIdQuery) (*entities.Organization, error) {
+	ctx, span := tracing.StartSpan(ctx, "getOrganizationByIdHandler.Handle")
+	defer span.End()
+	organization, err := q.mongoDB.GetOrganizationById(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+	return inTenant(organization, query.TenantID, organization.ID)
+}
+
+// SearchOrganizationHandler ...
+type SearchOrganizationHandler interface {
+	Handle(ctx context.Context, query *SearchOrganizationQuery) (*entities.OrganizationsList, error)
+}
+
+type searchOrganizationHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewSearchOrganizationHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *searchOrganizationHandler {
+	return &searchOrganizationHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (s *searchOrganizationHandler) Handle(ctx context.Context, query *SearchOrganizationQuery) (*entities.OrganizationsList, error) {
+	return s.mongoDB.SearchOrganizations(ctx, query.Text, query.Pagination)
+}
@@ -0,0 +1,43 @@
+package queries
+
+import (
+	"github.com/JECSand/identity-service/pkg/utilities"
+	"github.com/gofrs/uuid"
+)
+
+type RoleQueries struct {
+	GetRoleById GetRoleByIdHandler
+	SearchRoles SearchRolesHandler
+}
+
+func NewRoleQueries(getById GetRoleByIdHandler, search SearchRolesHandler) *RoleQueries {
+	return &RoleQueries{
+		GetRoleById: getById,
+		SearchRoles: search,
+	}
+}
+
+type GetRoleByIdQuery struct {
+	ID       uuid.UUID `json:"id" bson:"_id,omitempty"`
+	TenantID string    `json:"tenantID"`
+}
+
+func NewGetRoleByIdQuery(id uuid.UUID, tenantID string) *GetRoleByIdQuery {
+	return &GetRoleByIdQuery{ID: id, TenantID: tenantID}
+}
+
+type SearchRolesQuery struct {
+	Text       string                `json:"text"`
+	GroupID    string                `json:"groupID"`
+	TenantID   string                `json:"tenantID"`
+	Pagination *utilities.Pagination `json:"pagination"`
+}
+
+func NewSearchRolesQuery(text string, groupID string, tenantID string, pagination *utilities.Pagination) *SearchRolesQuery {
+	return &SearchRolesQuery{
+		Text:       text,
+		GroupID:    groupID,
+		TenantID:   tenantID,
+		Pagination: pagination,
+	}
+}
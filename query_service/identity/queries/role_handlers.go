@@ -0,0 +1,66 @@
+package queries
+
+import (
+	"context"
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/entities"
+)
+
+// GetRoleByIdHandler ...
+type GetRoleByIdHandler interface {
+	Handle(ctx context.Context, query *GetRoleByIdQuery) (*entities.Role, error)
+}
+
+type getRoleByIdHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewGetRoleByIdHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *getRoleByIdHandler {
+	return &getRoleByIdHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+// Handle returns a role of the tenant of the query; built-in roles are shared by every tenant
+func (q *getRoleByIdHandler) Handle(ctx context.Context, query *GetRoleByIdQuery) (*entities.Role, error) {
+	ctx, span := tracing.StartSpan(ctx, "getRoleByIdHandler.Handle")
+	defer span.End()
+	role, err := q.mongoDB.GetRoleById(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+	if role.BuiltIn {
+		return role, nil
+	}
+	return inTenant(role, query.TenantID, role.OrganizationID)
+}
+
+// SearchRolesHandler ...
+type SearchRolesHandler interface {
+	Handle(ctx context.Context, query *SearchRolesQuery) (*entities.RolesList, error)
+}
+
+type searchRolesHandler struct {
+	log     logging.Logger
+	cfg     *config.Config
+	mongoDB data.Database
+}
+
+func NewSearchRolesHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database) *searchRolesHandler {
+	return &searchRolesHandler{
+		log:     log,
+		cfg:     cfg,
+		mongoDB: mongoDB,
+	}
+}
+
+func (s *searchRolesHandler) Handle(ctx context.Context, query *SearchRolesQuery) (*entities.RolesList, error) {
+	return s.mongoDB.SearchRoles(ctx, query.Text, query.GroupID, query.TenantID, query.Pagination)
+}
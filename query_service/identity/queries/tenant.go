@@ -0,0 +1,11 @@
+package queries
+
+import "github.com/JECSand/identity-service/pkg/authentication"
+
+// inTenant returns found unless it belongs to another organization than the tenant a query is scoped to
+func inTenant[T any](found *T, tenantID string, organizationID string) (*T, error) {
+	if err := authentication.CheckTenant(tenantID, organizationID); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
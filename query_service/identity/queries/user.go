@@ -18,21 +18,24 @@ func NewUserQueries(getById GetUserByIdHandler, search SearchUserHandler) *UserQ
 }
 
 type GetUserByIdQuery struct {
-	ID uuid.UUID `json:"id" bson:"_id,omitempty"`
+	ID       uuid.UUID `json:"id" bson:"_id,omitempty"`
+	TenantID string    `json:"tenantID"`
 }
 
-func NewGetUserByIdQuery(id uuid.UUID) *GetUserByIdQuery {
-	return &GetUserByIdQuery{ID: id}
+func NewGetUserByIdQuery(id uuid.UUID, tenantID string) *GetUserByIdQuery {
+	return &GetUserByIdQuery{ID: id, TenantID: tenantID}
 }
 
 type SearchUserQuery struct {
 	Text       string                `json:"text"`
+	TenantID   string                `json:"tenantID"`
 	Pagination *utilities.Pagination `json:"pagination"`
 }
 
-func NewSearchUserQuery(text string, pagination *utilities.Pagination) *SearchUserQuery {
+func NewSearchUserQuery(text string, tenantID string, pagination *utilities.Pagination) *SearchUserQuery {
 	return &SearchUserQuery{
 		Text:       text,
+		TenantID:   tenantID,
 		Pagination: pagination,
 	}
 }
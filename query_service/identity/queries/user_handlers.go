@@ -3,11 +3,12 @@ package queries
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
 	"github.com/JECSand/identity-service/query_service/identity/cache"
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	"github.com/JECSand/identity-service/query_service/identity/entities"
-	"github.com/opentracing/opentracing-go"
+	"golang.org/x/sync/singleflight"
 )
 
 // GetUserByIdHandler ...
@@ -20,6 +21,7 @@ type getUserByIdHandler struct {
 	cfg        *config.Config
 	mongoDB    data.Database
 	redisCache cache.Cache
+	loader     singleflight.Group
 }
 
 func NewGetUserByIdHandler(log logging.Logger, cfg *config.Config, mongoDB data.Database, redisCache cache.Cache) *getUserByIdHandler {
@@ -32,17 +34,25 @@ func NewGetUserByIdHandler(log logging.Logger, cfg *config.Config, mongoDB data.
 }
 
 func (q *getUserByIdHandler) Handle(ctx context.Context, query *GetUserByIdQuery) (*entities.User, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "getUserByIdHandler.Handle")
-	defer span.Finish()
+	ctx, span := tracing.StartSpan(ctx, "getUserByIdHandler.Handle")
+	defer span.End()
 	if user, err := q.redisCache.GetUser(ctx, query.ID.String()); err == nil && user != nil {
-		return user, nil
+		return inTenant(user, query.TenantID, user.OrganizationID)
 	}
-	user, err := q.mongoDB.GetUserById(ctx, query.ID)
+	// collapse concurrent misses for the same id into a single database load
+	loaded, err, _ := q.loader.Do(query.ID.String(), func() (interface{}, error) {
+		user, err := q.mongoDB.GetUserById(ctx, query.ID)
+		if err != nil {
+			return nil, err
+		}
+		q.redisCache.PutUser(ctx, user.ID, user)
+		return user, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	q.redisCache.PutUser(ctx, user.ID, user)
-	return user, nil
+	user := loaded.(*entities.User)
+	return inTenant(user, query.TenantID, user.OrganizationID)
 }
 
 // SearchUserHandler ...
@@ -67,5 +77,5 @@ func NewSearchUserHandler(log logging.Logger, cfg *config.Config, mongoDB data.D
 }
 
 func (s *searchUserHandler) Handle(ctx context.Context, query *SearchUserQuery) (*entities.UsersList, error) {
-	return s.mongoDB.SearchUsers(ctx, query.Text, query.Pagination)
+	return s.mongoDB.SearchUsers(ctx, query.Text, query.TenantID, query.Pagination)
 }
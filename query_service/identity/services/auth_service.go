@@ -22,10 +22,9 @@ func NewAuthService(
 ) *AuthService {
 	blacklistTokenHandler := events.NewBlacklistTokenEventHandler(log, cfg, mongoDB, redisCache)
 	updatePasswordEventHandler := events.NewUpdatePasswordEventHandler(log, cfg, mongoDB, redisCache)
-	authenticateHandler := queries.NewAuthenticateHandler(log, cfg, mongoDB, redisCache)
 	validateHandler := queries.NewValidateHandler(log, cfg, mongoDB, redisCache)
 	userEvents := events.NewAuthEvents(blacklistTokenHandler, updatePasswordEventHandler)
-	userQueries := queries.NewAuthQueries(authenticateHandler, validateHandler)
+	userQueries := queries.NewAuthQueries(validateHandler)
 	return &AuthService{
 		Events:  userEvents,
 		Queries: userQueries,
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/queries"
+)
+
+type AuthzService struct {
+	Queries *queries.AuthzQueries
+}
+
+func NewAuthzService(log logging.Logger, cfg *config.Config, mongoDB data.Database) *AuthzService {
+	checkHandler := queries.NewCheckHandler(log, cfg, mongoDB)
+	batchCheckHandler := queries.NewBatchCheckHandler(log, cfg, mongoDB)
+	listResourcesHandler := queries.NewListResourcesHandler(log, cfg, mongoDB)
+	authzQueries := queries.NewAuthzQueries(checkHandler, batchCheckHandler, listResourcesHandler)
+	return &AuthzService{Queries: authzQueries}
+}
@@ -23,9 +23,10 @@ func NewGroupService(
 	createGroupHandler := events.NewCreateGroupEventHandler(log, cfg, mongoDB, redisCache)
 	deleteGroupEventHandler := events.NewDeleteGroupEventHandler(log, cfg, mongoDB, redisCache)
 	updateGroupEventHandler := events.NewUpdateGroupEventHandler(log, cfg, mongoDB, redisCache)
+	changeGroupParentEventHandler := events.NewChangeGroupParentEventHandler(log, cfg, mongoDB, redisCache)
 	getGroupByIdHandler := queries.NewGetGroupByIdHandler(log, cfg, mongoDB, redisCache)
 	searchGroupHandler := queries.NewSearchGroupHandler(log, cfg, mongoDB, redisCache)
-	groupEvents := events.NewGroupEvents(createGroupHandler, updateGroupEventHandler, deleteGroupEventHandler)
+	groupEvents := events.NewGroupEvents(createGroupHandler, updateGroupEventHandler, changeGroupParentEventHandler, deleteGroupEventHandler)
 	groupQueries := queries.NewGroupQueries(getGroupByIdHandler, searchGroupHandler)
 	return &GroupService{
 		Events:  groupEvents,
@@ -26,8 +26,16 @@ func NewMembershipService(
 	getMembershipByIdHandler := queries.NewGetMembershipByIdHandler(log, cfg, mongoDB, redisCache)
 	getGroupMembershipHandler := queries.NewGetGroupMembershipHandler(log, cfg, mongoDB, redisCache)
 	getUserMembershipHandler := queries.NewGetUserMembershipHandler(log, cfg, mongoDB, redisCache)
+	getEffectiveGroupsHandler := queries.NewGetEffectiveGroupsHandler(log, cfg, mongoDB, redisCache)
+	getEffectiveMembersHandler := queries.NewGetEffectiveMembersHandler(log, cfg, mongoDB, redisCache)
 	membershipEvents := events.NewMembershipEvents(createMembershipHandler, updateMembershipEventHandler, deleteMembershipEventHandler)
-	membershipQueries := queries.NewMembershipQueries(getMembershipByIdHandler, getGroupMembershipHandler, getUserMembershipHandler)
+	membershipQueries := queries.NewMembershipQueries(
+		getMembershipByIdHandler,
+		getGroupMembershipHandler,
+		getUserMembershipHandler,
+		getEffectiveGroupsHandler,
+		getEffectiveMembersHandler,
+	)
 	return &MembershipService{
 		Events:  membershipEvents,
 		Queries: membershipQueries,
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/cache"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/events"
+	"github.com/JECSand/identity-service/query_service/identity/queries"
+)
+
+type OrganizationService struct {
+	Events  *events.OrganizationEvents
+	Queries *queries.OrganizationQueries
+}
+
+func NewOrganizationService(
+	log logging.Logger,
+	cfg *config.Config,
+	mongoDB data.Database,
+	redisCache cache.Cache,
+) *OrganizationService {
+	createOrganizationHandler := events.NewCreateOrganizationEventHandler(log, cfg, mongoDB)
+	updateOrganizationHandler := events.NewUpdateOrganizationEventHandler(log, cfg, mongoDB)
+	changeOrganizationStatusHandler := events.NewChangeOrganizationStatusEventHandler(log, cfg, mongoDB)
+	deleteOrganizationHandler := events.NewDeleteOrganizationEventHandler(log, cfg, mongoDB, redisCache)
+	getOrganizationByIdHandler := queries.NewGetOrganizationByIdHandler(log, cfg, mongoDB)
+	searchOrganizationHandler := queries.NewSearchOrganizationHandler(log, cfg, mongoDB)
+	organizationEvents := events.NewOrganizationEvents(createOrganizationHandler, updateOrganizationHandler, changeOrganizationStatusHandler, deleteOrganizationHandler)
+	organizationQueries := queries.NewOrganizationQueries(getOrganizationByIdHandler, searchOrganizationHandler)
+	return &OrganizationService{
+		Events:  organizationEvents,
+		Queries: organizationQueries,
+	}
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/query_service/config"
+	"github.com/JECSand/identity-service/query_service/identity/cache"
+	"github.com/JECSand/identity-service/query_service/identity/data"
+	"github.com/JECSand/identity-service/query_service/identity/events"
+	"github.com/JECSand/identity-service/query_service/identity/queries"
+)
+
+type RoleService struct {
+	Events  *events.RoleEvents
+	Queries *queries.RoleQueries
+}
+
+func NewRoleService(
+	log logging.Logger,
+	cfg *config.Config,
+	mongoDB data.Database,
+	redisCache cache.Cache,
+) *RoleService {
+	createRoleHandler := events.NewCreateRoleEventHandler(log, cfg, mongoDB)
+	updateRoleHandler := events.NewUpdateRoleEventHandler(log, cfg, mongoDB, redisCache)
+	deleteRoleHandler := events.NewDeleteRoleEventHandler(log, cfg, mongoDB, redisCache)
+	getRoleByIdHandler := queries.NewGetRoleByIdHandler(log, cfg, mongoDB)
+	searchRolesHandler := queries.NewSearchRolesHandler(log, cfg, mongoDB)
+	roleEvents := events.NewRoleEvents(createRoleHandler, updateRoleHandler, deleteRoleHandler)
+	roleQueries := queries.NewRoleQueries(getRoleByIdHandler, searchRolesHandler)
+	return &RoleService{
+		Events:  roleEvents,
+		Queries: roleQueries,
+	}
+}
@@ -23,9 +23,10 @@ func NewUserService(
 	createUserHandler := events.NewCreateUserEventHandler(log, cfg, mongoDB, redisCache)
 	deleteUserEventHandler := events.NewDeleteUserEventHandler(log, cfg, mongoDB, redisCache)
 	updateUserEventHandler := events.NewUpdateUserEventHandler(log, cfg, mongoDB, redisCache)
+	changeUserStatusEventHandler := events.NewChangeUserStatusEventHandler(log, cfg, mongoDB, redisCache)
 	getUserByIdHandler := queries.NewGetUserByIdHandler(log, cfg, mongoDB, redisCache)
 	searchUserHandler := queries.NewSearchUserHandler(log, cfg, mongoDB, redisCache)
-	userEvents := events.NewUserEvents(createUserHandler, updateUserEventHandler, deleteUserEventHandler)
+	userEvents := events.NewUserEvents(createUserHandler, updateUserEventHandler, deleteUserEventHandler, changeUserStatusEventHandler)
 	userQueries := queries.NewUserQueries(getUserByIdHandler, searchUserHandler)
 	return &UserService{
 		Events:  userEvents,
@@ -26,54 +26,45 @@ var file_auth_query_proto_rawDesc = []byte{
 	0x74, 0x6f, 0x12, 0x10, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72,
 	0x76, 0x69, 0x63, 0x65, 0x1a, 0x19, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79,
 	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32,
-	0xea, 0x02, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x54, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x12, 0x21, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x21, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65,
-	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x5a, 0x0a, 0x0e, 0x42, 0x6c,
-	0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x23, 0x2e, 0x61,
+	0x94, 0x02, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x5a, 0x0a, 0x0e, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73,
+	0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c,
+	0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e, 0x61, 0x75,
+	0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42,
+	0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73,
+	0x12, 0x48, 0x0a, 0x08, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x61,
 	0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65,
+	0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x61, 0x75,
+	0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x56,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x5a, 0x0a, 0x0e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x23, 0x2e, 0x61,
+	0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
 	0x71, 0x1a, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f,
-	0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x12, 0x48, 0x0a, 0x08, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61,
-	0x74, 0x65, 0x12, 0x1d, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x71, 0x1a, 0x1d, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x12, 0x5a, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f,
-	0x72, 0x64, 0x12, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x42, 0x15, 0x5a, 0x13,
-	0x2e, 0x2f, 0x3b, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x42, 0x15, 0x5a, 0x13, 0x2e, 0x2f, 0x3b, 0x61, 0x75, 0x74,
+	0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_auth_query_proto_goTypes = []interface{}{
-	(*AuthenticateReq)(nil),   // 0: authQueryService.AuthenticateReq
-	(*BlacklistTokenReq)(nil), // 1: authQueryService.BlacklistTokenReq
-	(*ValidateReq)(nil),       // 2: authQueryService.ValidateReq
-	(*PasswordUpdateReq)(nil), // 3: authQueryService.PasswordUpdateReq
-	(*AuthenticateRes)(nil),   // 4: authQueryService.AuthenticateRes
-	(*BlacklistTokenRes)(nil), // 5: authQueryService.BlacklistTokenRes
-	(*ValidateRes)(nil),       // 6: authQueryService.ValidateRes
-	(*PasswordUpdateRes)(nil), // 7: authQueryService.PasswordUpdateRes
+	(*BlacklistTokenReq)(nil), // 0: authQueryService.BlacklistTokenReq
+	(*ValidateReq)(nil),       // 1: authQueryService.ValidateReq
+	(*PasswordUpdateReq)(nil), // 2: authQueryService.PasswordUpdateReq
+	(*BlacklistTokenRes)(nil), // 3: authQueryService.BlacklistTokenRes
+	(*ValidateRes)(nil),       // 4: authQueryService.ValidateRes
+	(*PasswordUpdateRes)(nil), // 5: authQueryService.PasswordUpdateRes
 }
 var file_auth_query_proto_depIdxs = []int32{
-	0, // 0: authQueryService.authQueryService.Authenticate:input_type -> authQueryService.AuthenticateReq
-	1, // 1: authQueryService.authQueryService.BlacklistToken:input_type -> authQueryService.BlacklistTokenReq
-	2, // 2: authQueryService.authQueryService.Validate:input_type -> authQueryService.ValidateReq
-	3, // 3: authQueryService.authQueryService.UpdatePassword:input_type -> authQueryService.PasswordUpdateReq
-	4, // 4: authQueryService.authQueryService.Authenticate:output_type -> authQueryService.AuthenticateRes
-	5, // 5: authQueryService.authQueryService.BlacklistToken:output_type -> authQueryService.BlacklistTokenRes
-	6, // 6: authQueryService.authQueryService.Validate:output_type -> authQueryService.ValidateRes
-	7, // 7: authQueryService.authQueryService.UpdatePassword:output_type -> authQueryService.PasswordUpdateRes
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
+	0, // 0: authQueryService.authQueryService.BlacklistToken:input_type -> authQueryService.BlacklistTokenReq
+	1, // 1: authQueryService.authQueryService.Validate:input_type -> authQueryService.ValidateReq
+	2, // 2: authQueryService.authQueryService.UpdatePassword:input_type -> authQueryService.PasswordUpdateReq
+	3, // 3: authQueryService.authQueryService.BlacklistToken:output_type -> authQueryService.BlacklistTokenRes
+	4, // 4: authQueryService.authQueryService.Validate:output_type -> authQueryService.ValidateRes
+	5, // 5: authQueryService.authQueryService.UpdatePassword:output_type -> authQueryService.PasswordUpdateRes
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
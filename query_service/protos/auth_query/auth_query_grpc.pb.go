@@ -22,7 +22,6 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AuthQueryServiceClient interface {
-	Authenticate(ctx context.Context, in *AuthenticateReq, opts ...grpc.CallOption) (*AuthenticateRes, error)
 	BlacklistToken(ctx context.Context, in *BlacklistTokenReq, opts ...grpc.CallOption) (*BlacklistTokenRes, error)
 	Validate(ctx context.Context, in *ValidateReq, opts ...grpc.CallOption) (*ValidateRes, error)
 	UpdatePassword(ctx context.Context, in *PasswordUpdateReq, opts ...grpc.CallOption) (*PasswordUpdateRes, error)
@@ -36,15 +35,6 @@ func NewAuthQueryServiceClient(cc grpc.ClientConnInterface) AuthQueryServiceClie
 	return &authQueryServiceClient{cc}
 }
 
-func (c *authQueryServiceClient) Authenticate(ctx context.Context, in *AuthenticateReq, opts ...grpc.CallOption) (*AuthenticateRes, error) {
-	out := new(AuthenticateRes)
-	err := c.cc.Invoke(ctx, "/authQueryService.authQueryService/Authenticate", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
 func (c *authQueryServiceClient) BlacklistToken(ctx context.Context, in *BlacklistTokenReq, opts ...grpc.CallOption) (*BlacklistTokenRes, error) {
 	out := new(BlacklistTokenRes)
 	err := c.cc.Invoke(ctx, "/authQueryService.authQueryService/BlacklistToken", in, out, opts...)
@@ -76,7 +66,6 @@ func (c *authQueryServiceClient) UpdatePassword(ctx context.Context, in *Passwor
 // All implementations should embed UnimplementedAuthQueryServiceServer
 // for forward compatibility
 type AuthQueryServiceServer interface {
-	Authenticate(context.Context, *AuthenticateReq) (*AuthenticateRes, error)
 	BlacklistToken(context.Context, *BlacklistTokenReq) (*BlacklistTokenRes, error)
 	Validate(context.Context, *ValidateReq) (*ValidateRes, error)
 	UpdatePassword(context.Context, *PasswordUpdateReq) (*PasswordUpdateRes, error)
@@ -86,9 +75,6 @@ type AuthQueryServiceServer interface {
 type UnimplementedAuthQueryServiceServer struct {
 }
 
-func (UnimplementedAuthQueryServiceServer) Authenticate(context.Context, *AuthenticateReq) (*AuthenticateRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
-}
 func (UnimplementedAuthQueryServiceServer) BlacklistToken(context.Context, *BlacklistTokenReq) (*BlacklistTokenRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlacklistToken not implemented")
 }
@@ -110,24 +96,6 @@ func RegisterAuthQueryServiceServer(s grpc.ServiceRegistrar, srv AuthQueryServic
 	s.RegisterService(&AuthQueryService_ServiceDesc, srv)
 }
 
-func _AuthQueryService_Authenticate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AuthenticateReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthQueryServiceServer).Authenticate(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/authQueryService.authQueryService/Authenticate",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthQueryServiceServer).Authenticate(ctx, req.(*AuthenticateReq))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
 func _AuthQueryService_BlacklistToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlacklistTokenReq)
 	if err := dec(in); err != nil {
@@ -189,10 +157,6 @@ var AuthQueryService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "authQueryService.authQueryService",
 	HandlerType: (*AuthQueryServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Authenticate",
-			Handler:    _AuthQueryService_Authenticate_Handler,
-		},
 		{
 			MethodName: "BlacklistToken",
 			Handler:    _AuthQueryService_BlacklistToken_Handler,
@@ -26,14 +26,18 @@ type User struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ID        string               `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Email     string               `protobuf:"bytes,2,opt,name=Email,proto3" json:"Email,omitempty"`
-	Username  string               `protobuf:"bytes,3,opt,name=Username,proto3" json:"Username,omitempty"`
-	Password  string               `protobuf:"bytes,4,opt,name=Password,proto3" json:"Password,omitempty"`
-	Root      bool                 `protobuf:"varint,5,opt,name=Root,proto3" json:"Root,omitempty"`
-	Active    bool                 `protobuf:"varint,6,opt,name=Active,proto3" json:"Active,omitempty"`
-	CreatedAt *timestamp.Timestamp `protobuf:"bytes,7,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
-	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,8,opt,name=UpdatedAt,proto3" json:"UpdatedAt,omitempty"`
+	ID             string               `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Email          string               `protobuf:"bytes,2,opt,name=Email,proto3" json:"Email,omitempty"`
+	Username       string               `protobuf:"bytes,3,opt,name=Username,proto3" json:"Username,omitempty"`
+	Root           bool                 `protobuf:"varint,5,opt,name=Root,proto3" json:"Root,omitempty"`
+	Active         bool                 `protobuf:"varint,6,opt,name=Active,proto3" json:"Active,omitempty"`
+	CreatedAt      *timestamp.Timestamp `protobuf:"bytes,7,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	UpdatedAt      *timestamp.Timestamp `protobuf:"bytes,8,opt,name=UpdatedAt,proto3" json:"UpdatedAt,omitempty"`
+	Status         string               `protobuf:"bytes,9,opt,name=Status,proto3" json:"Status,omitempty"`
+	StatusReason   string               `protobuf:"bytes,10,opt,name=StatusReason,proto3" json:"StatusReason,omitempty"`
+	SuspendedUntil *timestamp.Timestamp `protobuf:"bytes,11,opt,name=SuspendedUntil,proto3" json:"SuspendedUntil,omitempty"`
+	OrganizationID string               `protobuf:"bytes,12,opt,name=OrganizationID,proto3" json:"OrganizationID,omitempty"`
+	OrgAdmin       bool                 `protobuf:"varint,13,opt,name=OrgAdmin,proto3" json:"OrgAdmin,omitempty"`
 }
 
 func (x *User) Reset() {
@@ -89,13 +93,6 @@ func (x *User) GetUsername() string {
 	return ""
 }
 
-func (x *User) GetPassword() string {
-	if x != nil {
-		return x.Password
-	}
-	return ""
-}
-
 func (x *User) GetRoot() bool {
 	if x != nil {
 		return x.Root
@@ -124,6 +121,41 @@ func (x *User) GetUpdatedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *User) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *User) GetStatusReason() string {
+	if x != nil {
+		return x.StatusReason
+	}
+	return ""
+}
+
+func (x *User) GetSuspendedUntil() *timestamp.Timestamp {
+	if x != nil {
+		return x.SuspendedUntil
+	}
+	return nil
+}
+
+func (x *User) GetOrganizationID() string {
+	if x != nil {
+		return x.OrganizationID
+	}
+	return ""
+}
+
+func (x *User) GetOrgAdmin() bool {
+	if x != nil {
+		return x.OrgAdmin
+	}
+	return false
+}
+
 type Blacklist struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -195,116 +227,6 @@ func (x *Blacklist) GetUpdatedAt() *timestamp.Timestamp {
 	return nil
 }
 
-type AuthenticateReq struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Email    string `protobuf:"bytes,1,opt,name=Email,proto3" json:"Email,omitempty"`
-	Password string `protobuf:"bytes,2,opt,name=Password,proto3" json:"Password,omitempty"`
-}
-
-func (x *AuthenticateReq) Reset() {
-	*x = AuthenticateReq{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *AuthenticateReq) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*AuthenticateReq) ProtoMessage() {}
-
-func (x *AuthenticateReq) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use AuthenticateReq.ProtoReflect.Descriptor instead.
-func (*AuthenticateReq) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *AuthenticateReq) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
-}
-
-func (x *AuthenticateReq) GetPassword() string {
-	if x != nil {
-		return x.Password
-	}
-	return ""
-}
-
-type AuthenticateRes struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	User   *User `protobuf:"bytes,1,opt,name=User,proto3" json:"User,omitempty"`
-	Status int64 `protobuf:"varint,2,opt,name=Status,proto3" json:"Status,omitempty"`
-}
-
-func (x *AuthenticateRes) Reset() {
-	*x = AuthenticateRes{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *AuthenticateRes) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*AuthenticateRes) ProtoMessage() {}
-
-func (x *AuthenticateRes) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use AuthenticateRes.ProtoReflect.Descriptor instead.
-func (*AuthenticateRes) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *AuthenticateRes) GetUser() *User {
-	if x != nil {
-		return x.User
-	}
-	return nil
-}
-
-func (x *AuthenticateRes) GetStatus() int64 {
-	if x != nil {
-		return x.Status
-	}
-	return 0
-}
-
 type BlacklistTokenReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -317,7 +239,7 @@ type BlacklistTokenReq struct {
 func (x *BlacklistTokenReq) Reset() {
 	*x = BlacklistTokenReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[4]
+		mi := &file_auth_query_messages_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -330,7 +252,7 @@ func (x *BlacklistTokenReq) String() string {
 func (*BlacklistTokenReq) ProtoMessage() {}
 
 func (x *BlacklistTokenReq) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[4]
+	mi := &file_auth_query_messages_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +265,7 @@ func (x *BlacklistTokenReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlacklistTokenReq.ProtoReflect.Descriptor instead.
 func (*BlacklistTokenReq) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{4}
+	return file_auth_query_messages_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *BlacklistTokenReq) GetID() string {
@@ -372,7 +294,7 @@ type BlacklistTokenRes struct {
 func (x *BlacklistTokenRes) Reset() {
 	*x = BlacklistTokenRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[5]
+		mi := &file_auth_query_messages_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -385,7 +307,7 @@ func (x *BlacklistTokenRes) String() string {
 func (*BlacklistTokenRes) ProtoMessage() {}
 
 func (x *BlacklistTokenRes) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[5]
+	mi := &file_auth_query_messages_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -398,7 +320,7 @@ func (x *BlacklistTokenRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlacklistTokenRes.ProtoReflect.Descriptor instead.
 func (*BlacklistTokenRes) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{5}
+	return file_auth_query_messages_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *BlacklistTokenRes) GetID() string {
@@ -428,7 +350,7 @@ type ValidateReq struct {
 func (x *ValidateReq) Reset() {
 	*x = ValidateReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[6]
+		mi := &file_auth_query_messages_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -441,7 +363,7 @@ func (x *ValidateReq) String() string {
 func (*ValidateReq) ProtoMessage() {}
 
 func (x *ValidateReq) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[6]
+	mi := &file_auth_query_messages_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -454,7 +376,7 @@ func (x *ValidateReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidateReq.ProtoReflect.Descriptor instead.
 func (*ValidateReq) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{6}
+	return file_auth_query_messages_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ValidateReq) GetUserID() string {
@@ -490,7 +412,7 @@ type ValidateRes struct {
 func (x *ValidateRes) Reset() {
 	*x = ValidateRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[7]
+		mi := &file_auth_query_messages_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -503,7 +425,7 @@ func (x *ValidateRes) String() string {
 func (*ValidateRes) ProtoMessage() {}
 
 func (x *ValidateRes) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[7]
+	mi := &file_auth_query_messages_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,7 +438,7 @@ func (x *ValidateRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidateRes.ProtoReflect.Descriptor instead.
 func (*ValidateRes) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{7}
+	return file_auth_query_messages_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ValidateRes) GetUser() *User {
@@ -538,15 +460,13 @@ type PasswordUpdateReq struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ID              string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	CurrentPassword string `protobuf:"bytes,2,opt,name=CurrentPassword,proto3" json:"CurrentPassword,omitempty"`
-	NewPassword     string `protobuf:"bytes,3,opt,name=NewPassword,proto3" json:"NewPassword,omitempty"`
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 }
 
 func (x *PasswordUpdateReq) Reset() {
 	*x = PasswordUpdateReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[8]
+		mi := &file_auth_query_messages_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -559,7 +479,7 @@ func (x *PasswordUpdateReq) String() string {
 func (*PasswordUpdateReq) ProtoMessage() {}
 
 func (x *PasswordUpdateReq) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[8]
+	mi := &file_auth_query_messages_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -572,7 +492,7 @@ func (x *PasswordUpdateReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PasswordUpdateReq.ProtoReflect.Descriptor instead.
 func (*PasswordUpdateReq) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{8}
+	return file_auth_query_messages_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PasswordUpdateReq) GetID() string {
@@ -582,20 +502,6 @@ func (x *PasswordUpdateReq) GetID() string {
 	return ""
 }
 
-func (x *PasswordUpdateReq) GetCurrentPassword() string {
-	if x != nil {
-		return x.CurrentPassword
-	}
-	return ""
-}
-
-func (x *PasswordUpdateReq) GetNewPassword() string {
-	if x != nil {
-		return x.NewPassword
-	}
-	return ""
-}
-
 type PasswordUpdateRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -607,7 +513,7 @@ type PasswordUpdateRes struct {
 func (x *PasswordUpdateRes) Reset() {
 	*x = PasswordUpdateRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_auth_query_messages_proto_msgTypes[9]
+		mi := &file_auth_query_messages_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -620,7 +526,7 @@ func (x *PasswordUpdateRes) String() string {
 func (*PasswordUpdateRes) ProtoMessage() {}
 
 func (x *PasswordUpdateRes) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_query_messages_proto_msgTypes[9]
+	mi := &file_auth_query_messages_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -633,7 +539,7 @@ func (x *PasswordUpdateRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PasswordUpdateRes.ProtoReflect.Descriptor instead.
 func (*PasswordUpdateRes) Descriptor() ([]byte, []int) {
-	return file_auth_query_messages_proto_rawDescGZIP(), []int{9}
+	return file_auth_query_messages_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PasswordUpdateRes) GetStatus() int64 {
@@ -650,77 +556,77 @@ var file_auth_query_messages_proto_rawDesc = []byte{
 	0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x61, 0x75, 0x74,
 	0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1f, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84,
-	0x02, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbc,
+	0x03, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x6d, 0x61, 0x69, 0x6c,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x1a, 0x0a,
 	0x08, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x73,
-	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x50, 0x61, 0x73,
-	0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63, 0x74,
-	0x69, 0x76, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x76,
-	0x65, 0x12, 0x38, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x38, 0x0a, 0x09, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xb1, 0x01, 0x0a, 0x09, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c,
-	0x69, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b,
-	0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
-	0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x08, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6f,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x41,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
 	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
 	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12,
-	0x38, 0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x38, 0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x08, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x43, 0x0a, 0x0f, 0x41, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05,
-	0x45, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x45, 0x6d, 0x61,
-	0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x55,
-	0x0a, 0x0f, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x12, 0x2a, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x16, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x16, 0x0a,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x0e, 0x53, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x64,
+	0x65, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x53, 0x75, 0x73, 0x70, 0x65,
+	0x6e, 0x64, 0x65, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x26, 0x0a, 0x0e, 0x4f, 0x72, 0x67,
+	0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x4f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x44, 0x12, 0x1a, 0x0a, 0x08, 0x4f, 0x72, 0x67, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x4f, 0x72, 0x67, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x4a, 0x04, 0x08,
+	0x04, 0x10, 0x05, 0x52, 0x08, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0xb1, 0x01,
+	0x0a, 0x09, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x41,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x38, 0x0a,
+	0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x38, 0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x22, 0x45, 0x0a, 0x11, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x41, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x3b, 0x0a, 0x11, 0x42, 0x6c, 0x61, 0x63,
+	0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x12, 0x0e, 0x0a,
+	0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a,
 	0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x45, 0x0a, 0x11, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69,
-	0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x41, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x3b, 0x0a, 0x11,
-	0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65,
-	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49,
-	0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x6f, 0x0a, 0x0b, 0x56, 0x61, 0x6c,
-	0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72,
-	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44,
-	0x12, 0x20, 0x0a, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b,
-	0x65, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x56, 0x61, 0x6c, 0x69,
-	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22, 0x51, 0x0a, 0x0b, 0x56, 0x61,
-	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x04, 0x55, 0x73, 0x65,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52,
-	0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x6f, 0x0a,
-	0x11, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52,
-	0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x49, 0x44, 0x12, 0x28, 0x0a, 0x0f, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x73,
-	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x43, 0x75, 0x72,
-	0x72, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x20, 0x0a, 0x0b,
-	0x4e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0b, 0x4e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x2b,
-	0x0a, 0x11, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x15, 0x5a, 0x13, 0x2e,
-	0x2f, 0x3b, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x6f, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b,
+	0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x26,
+	0x0a, 0x0e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22, 0x51, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x55, 0x73, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x4d, 0x0a, 0x11, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x12, 0x0e,
+	0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x4a, 0x04,
+	0x08, 0x02, 0x10, 0x03, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x52, 0x0f, 0x43, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x0b, 0x4e, 0x65, 0x77,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x2b, 0x0a, 0x11, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x15, 0x5a, 0x13, 0x2e, 0x2f, 0x3b, 0x61, 0x75, 0x74, 0x68,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -735,32 +641,30 @@ func file_auth_query_messages_proto_rawDescGZIP() []byte {
 	return file_auth_query_messages_proto_rawDescData
 }
 
-var file_auth_query_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_auth_query_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_auth_query_messages_proto_goTypes = []interface{}{
 	(*User)(nil),                // 0: authQueryService.User
 	(*Blacklist)(nil),           // 1: authQueryService.Blacklist
-	(*AuthenticateReq)(nil),     // 2: authQueryService.AuthenticateReq
-	(*AuthenticateRes)(nil),     // 3: authQueryService.AuthenticateRes
-	(*BlacklistTokenReq)(nil),   // 4: authQueryService.BlacklistTokenReq
-	(*BlacklistTokenRes)(nil),   // 5: authQueryService.BlacklistTokenRes
-	(*ValidateReq)(nil),         // 6: authQueryService.ValidateReq
-	(*ValidateRes)(nil),         // 7: authQueryService.ValidateRes
-	(*PasswordUpdateReq)(nil),   // 8: authQueryService.PasswordUpdateReq
-	(*PasswordUpdateRes)(nil),   // 9: authQueryService.PasswordUpdateRes
-	(*timestamp.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(*BlacklistTokenReq)(nil),   // 2: authQueryService.BlacklistTokenReq
+	(*BlacklistTokenRes)(nil),   // 3: authQueryService.BlacklistTokenRes
+	(*ValidateReq)(nil),         // 4: authQueryService.ValidateReq
+	(*ValidateRes)(nil),         // 5: authQueryService.ValidateRes
+	(*PasswordUpdateReq)(nil),   // 6: authQueryService.PasswordUpdateReq
+	(*PasswordUpdateRes)(nil),   // 7: authQueryService.PasswordUpdateRes
+	(*timestamp.Timestamp)(nil), // 8: google.protobuf.Timestamp
 }
 var file_auth_query_messages_proto_depIdxs = []int32{
-	10, // 0: authQueryService.User.CreatedAt:type_name -> google.protobuf.Timestamp
-	10, // 1: authQueryService.User.UpdatedAt:type_name -> google.protobuf.Timestamp
-	10, // 2: authQueryService.Blacklist.CreatedAt:type_name -> google.protobuf.Timestamp
-	10, // 3: authQueryService.Blacklist.UpdatedAt:type_name -> google.protobuf.Timestamp
-	0,  // 4: authQueryService.AuthenticateRes.User:type_name -> authQueryService.User
-	0,  // 5: authQueryService.ValidateRes.User:type_name -> authQueryService.User
-	6,  // [6:6] is the sub-list for method output_type
-	6,  // [6:6] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	8, // 0: authQueryService.User.CreatedAt:type_name -> google.protobuf.Timestamp
+	8, // 1: authQueryService.User.UpdatedAt:type_name -> google.protobuf.Timestamp
+	8, // 2: authQueryService.User.SuspendedUntil:type_name -> google.protobuf.Timestamp
+	8, // 3: authQueryService.Blacklist.CreatedAt:type_name -> google.protobuf.Timestamp
+	8, // 4: authQueryService.Blacklist.UpdatedAt:type_name -> google.protobuf.Timestamp
+	0, // 5: authQueryService.ValidateRes.User:type_name -> authQueryService.User
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_auth_query_messages_proto_init() }
@@ -794,30 +698,6 @@ func file_auth_query_messages_proto_init() {
 			}
 		}
 		file_auth_query_messages_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticateReq); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_auth_query_messages_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticateRes); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_auth_query_messages_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BlacklistTokenReq); i {
 			case 0:
 				return &v.state
@@ -829,7 +709,7 @@ func file_auth_query_messages_proto_init() {
 				return nil
 			}
 		}
-		file_auth_query_messages_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_auth_query_messages_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BlacklistTokenRes); i {
 			case 0:
 				return &v.state
@@ -841,7 +721,7 @@ func file_auth_query_messages_proto_init() {
 				return nil
 			}
 		}
-		file_auth_query_messages_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_auth_query_messages_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ValidateReq); i {
 			case 0:
 				return &v.state
@@ -853,7 +733,7 @@ func file_auth_query_messages_proto_init() {
 				return nil
 			}
 		}
-		file_auth_query_messages_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_auth_query_messages_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ValidateRes); i {
 			case 0:
 				return &v.state
@@ -865,7 +745,7 @@ func file_auth_query_messages_proto_init() {
 				return nil
 			}
 		}
-		file_auth_query_messages_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_auth_query_messages_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PasswordUpdateReq); i {
 			case 0:
 				return &v.state
@@ -877,7 +757,7 @@ func file_auth_query_messages_proto_init() {
 				return nil
 			}
 		}
-		file_auth_query_messages_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_auth_query_messages_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PasswordUpdateRes); i {
 			case 0:
 				return &v.state
@@ -896,7 +776,7 @@ func file_auth_query_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_auth_query_messages_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,95 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: authz_query.proto
+
+package authzQueryService
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_authz_query_proto protoreflect.FileDescriptor
+
+var file_authz_query_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x11, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1a, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x71, 0x75,
+	0x65, 0x72, 0x79, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x32, 0x83, 0x02, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x05, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x12, 0x1b, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x1b,
+	0x2e, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x12, 0x50, 0x0a, 0x0a, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x20, 0x2e, 0x61, 0x75, 0x74, 0x68,
+	0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x20, 0x2e, 0x61, 0x75,
+	0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x12, 0x59, 0x0a,
+	0x0d, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x23,
+	0x2e, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x42, 0x16, 0x5a, 0x14, 0x2e, 0x2f, 0x3b, 0x61,
+	0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_authz_query_proto_goTypes = []interface{}{
+	(*CheckReq)(nil),         // 0: authzQueryService.CheckReq
+	(*BatchCheckReq)(nil),    // 1: authzQueryService.BatchCheckReq
+	(*ListResourcesReq)(nil), // 2: authzQueryService.ListResourcesReq
+	(*CheckRes)(nil),         // 3: authzQueryService.CheckRes
+	(*BatchCheckRes)(nil),    // 4: authzQueryService.BatchCheckRes
+	(*ListResourcesRes)(nil), // 5: authzQueryService.ListResourcesRes
+}
+var file_authz_query_proto_depIdxs = []int32{
+	0, // 0: authzQueryService.authzQueryService.Check:input_type -> authzQueryService.CheckReq
+	1, // 1: authzQueryService.authzQueryService.BatchCheck:input_type -> authzQueryService.BatchCheckReq
+	2, // 2: authzQueryService.authzQueryService.ListResources:input_type -> authzQueryService.ListResourcesReq
+	3, // 3: authzQueryService.authzQueryService.Check:output_type -> authzQueryService.CheckRes
+	4, // 4: authzQueryService.authzQueryService.BatchCheck:output_type -> authzQueryService.BatchCheckRes
+	5, // 5: authzQueryService.authzQueryService.ListResources:output_type -> authzQueryService.ListResourcesRes
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_authz_query_proto_init() }
+func file_authz_query_proto_init() {
+	if File_authz_query_proto != nil {
+		return
+	}
+	file_authz_query_messages_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_authz_query_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_authz_query_proto_goTypes,
+		DependencyIndexes: file_authz_query_proto_depIdxs,
+	}.Build()
+	File_authz_query_proto = out.File
+	file_authz_query_proto_rawDesc = nil
+	file_authz_query_proto_goTypes = nil
+	file_authz_query_proto_depIdxs = nil
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: authz_query.proto
+
+package authzQueryService
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AuthzQueryServiceClient is the client API for AuthzQueryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuthzQueryServiceClient interface {
+	Check(ctx context.Context, in *CheckReq, opts ...grpc.CallOption) (*CheckRes, error)
+	BatchCheck(ctx context.Context, in *BatchCheckReq, opts ...grpc.CallOption) (*BatchCheckRes, error)
+	ListResources(ctx context.Context, in *ListResourcesReq, opts ...grpc.CallOption) (*ListResourcesRes, error)
+}
+
+type authzQueryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthzQueryServiceClient(cc grpc.ClientConnInterface) AuthzQueryServiceClient {
+	return &authzQueryServiceClient{cc}
+}
+
+func (c *authzQueryServiceClient) Check(ctx context.Context, in *CheckReq, opts ...grpc.CallOption) (*CheckRes, error) {
+	out := new(CheckRes)
+	err := c.cc.Invoke(ctx, "/authzQueryService.authzQueryService/Check", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authzQueryServiceClient) BatchCheck(ctx context.Context, in *BatchCheckReq, opts ...grpc.CallOption) (*BatchCheckRes, error) {
+	out := new(BatchCheckRes)
+	err := c.cc.Invoke(ctx, "/authzQueryService.authzQueryService/BatchCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authzQueryServiceClient) ListResources(ctx context.Context, in *ListResourcesReq, opts ...grpc.CallOption) (*ListResourcesRes, error) {
+	out := new(ListResourcesRes)
+	err := c.cc.Invoke(ctx, "/authzQueryService.authzQueryService/ListResources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthzQueryServiceServer is the server API for AuthzQueryService service.
+// All implementations should embed UnimplementedAuthzQueryServiceServer
+// for forward compatibility
+type AuthzQueryServiceServer interface {
+	Check(context.Context, *CheckReq) (*CheckRes, error)
+	BatchCheck(context.Context, *BatchCheckReq) (*BatchCheckRes, error)
+	ListResources(context.Context, *ListResourcesReq) (*ListResourcesRes, error)
+}
+
+// UnimplementedAuthzQueryServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedAuthzQueryServiceServer struct {
+}
+
+func (UnimplementedAuthzQueryServiceServer) Check(context.Context, *CheckReq) (*CheckRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedAuthzQueryServiceServer) BatchCheck(context.Context, *BatchCheckReq) (*BatchCheckRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCheck not implemented")
+}
+func (UnimplementedAuthzQueryServiceServer) ListResources(context.Context, *ListResourcesReq) (*ListResourcesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListResources not implemented")
+}
+
+// UnsafeAuthzQueryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthzQueryServiceServer will
+// result in compilation errors.
+type UnsafeAuthzQueryServiceServer interface {
+	mustEmbedUnimplementedAuthzQueryServiceServer()
+}
+
+func RegisterAuthzQueryServiceServer(s grpc.ServiceRegistrar, srv AuthzQueryServiceServer) {
+	s.RegisterService(&AuthzQueryService_ServiceDesc, srv)
+}
+
+func _AuthzQueryService_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthzQueryServiceServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/authzQueryService.authzQueryService/Check",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthzQueryServiceServer).Check(ctx, req.(*CheckReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthzQueryService_BatchCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCheckReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthzQueryServiceServer).BatchCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/authzQueryService.authzQueryService/BatchCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthzQueryServiceServer).BatchCheck(ctx, req.(*BatchCheckReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthzQueryService_ListResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListResourcesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthzQueryServiceServer).ListResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/authzQueryService.authzQueryService/ListResources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthzQueryServiceServer).ListResources(ctx, req.(*ListResourcesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AuthzQueryService_ServiceDesc is the grpc.ServiceDesc for AuthzQueryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuthzQueryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "authzQueryService.authzQueryService",
+	HandlerType: (*AuthzQueryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Check",
+			Handler:    _AuthzQueryService_Check_Handler,
+		},
+		{
+			MethodName: "BatchCheck",
+			Handler:    _AuthzQueryService_BatchCheck_Handler,
+		},
+		{
+			MethodName: "ListResources",
+			Handler:    _AuthzQueryService_ListResources_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "authz_query.proto",
+}
@@ -0,0 +1,665 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: authz_query_messages.proto
+
+package authzQueryService
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Decision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubjectID  string `protobuf:"bytes,1,opt,name=SubjectID,proto3" json:"SubjectID,omitempty"`
+	Action     string `protobuf:"bytes,2,opt,name=Action,proto3" json:"Action,omitempty"`
+	ResourceID string `protobuf:"bytes,3,opt,name=ResourceID,proto3" json:"ResourceID,omitempty"`
+	Allowed    bool   `protobuf:"varint,4,opt,name=Allowed,proto3" json:"Allowed,omitempty"`
+	Reason     string `protobuf:"bytes,5,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	GrantedBy  string `protobuf:"bytes,6,opt,name=GrantedBy,proto3" json:"GrantedBy,omitempty"`
+}
+
+func (x *Decision) Reset() {
+	*x = Decision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_authz_query_messages_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Decision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Decision) ProtoMessage() {}
+
+func (x *Decision) ProtoReflect() protoreflect.Message {
+	mi := &file_authz_query_messages_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Decision.ProtoReflect.Descriptor instead.
+func (*Decision) Descriptor() ([]byte, []int) {
+	return file_authz_query_messages_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Decision) GetSubjectID() string {
+	if x != nil {
+		return x.SubjectID
+	}
+	return ""
+}
+
+func (x *Decision) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Decision) GetResourceID() string {
+	if x != nil {
+		return x.ResourceID
+	}
+	return ""
+}
+
+func (x *Decision) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *Decision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Decision) GetGrantedBy() string {
+	if x != nil {
+		return x.GrantedBy
+	}
+	return ""
+}
+
+type CheckReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubjectID  string `protobuf:"bytes,1,opt,name=SubjectID,proto3" json:"SubjectID,omitempty"`
+	Action     string `protobuf:"bytes,2,opt,name=Action,proto3" json:"Action,omitempty"`
+	ResourceID string `protobuf:"bytes,3,opt,name=ResourceID,proto3" json:"ResourceID,omitempty"`
+	TenantID   string `protobuf:"bytes,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+}
+
+func (x *CheckReq) Reset() {
+	*x = CheckReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_authz_query_messages_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckReq) ProtoMessage() {}
+
+func (x *CheckReq) ProtoReflect() protoreflect.Message {
+	mi := &file_authz_query_messages_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckReq.ProtoReflect.Descriptor instead.
+func (*CheckReq) Descriptor() ([]byte, []int) {
+	return file_authz_query_messages_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CheckReq) GetSubjectID() string {
+	if x != nil {
+		return x.SubjectID
+	}
+	return ""
+}
+
+func (x *CheckReq) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *CheckReq) GetResourceID() string {
+	if x != nil {
+		return x.ResourceID
+	}
+	return ""
+}
+
+func (x *CheckReq) GetTenantID() string {
+	if x != nil {
+		return x.TenantID
+	}
+	return ""
+}
+
+type CheckRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Decision *Decision `protobuf:"bytes,1,opt,name=Decision,proto3" json:"Decision,omitempty"`
+}
+
+func (x *CheckRes) Reset() {
+	*x = CheckRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_authz_query_messages_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRes) ProtoMessage() {}
+
+func (x *CheckRes) ProtoReflect() protoreflect.Message {
+	mi := &file_authz_query_messages_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRes.ProtoReflect.Descriptor instead.
+func (*CheckRes) Descriptor() ([]byte, []int) {
+	return file_authz_query_messages_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CheckRes) GetDecision() *Decision {
+	if x != nil {
+		return x.Decision
+	}
+	return nil
+}
+
+type BatchCheckReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Checks   []*CheckReq `protobuf:"bytes,1,rep,name=Checks,proto3" json:"Checks,omitempty"`
+	TenantID string      `protobuf:"bytes,2,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+}
+
+func (x *BatchCheckReq) Reset() {
+	*x = BatchCheckReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_authz_query_messages_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCheckReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCheckReq) ProtoMessage() {}
+
+func (x *BatchCheckReq) ProtoReflect() protoreflect.Message {
+	mi := &file_authz_query_messages_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCheckReq.ProtoReflect.Descriptor instead.
+func (*BatchCheckReq) Descriptor() ([]byte, []int) {
+	return file_authz_query_messages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchCheckReq) GetChecks() []*CheckReq {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *BatchCheckReq) GetTenantID() string {
+	if x != nil {
+		return x.TenantID
+	}
+	return ""
+}
+
+type BatchCheckRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Decisions []*Decision `protobuf:"bytes,1,rep,name=Decisions,proto3" json:"Decisions,omitempty"`
+}
+
+func (x *BatchCheckRes) Reset() {
+	*x = BatchCheckRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_authz_query_messages_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCheckRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCheckRes) ProtoMessage() {}
+
+func (x *BatchCheckRes) ProtoReflect() protoreflect.Message {
+	mi := &file_authz_query_messages_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCheckRes.ProtoReflect.Descriptor instead.
+func (*BatchCheckRes) Descriptor() ([]byte, []int) {
+	return file_authz_query_messages_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchCheckRes) GetDecisions() []*Decision {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+type ListResourcesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubjectID string `protobuf:"bytes,1,opt,name=SubjectID,proto3" json:"SubjectID,omitempty"`
+	Action    string `protobuf:"bytes,2,opt,name=Action,proto3" json:"Action,omitempty"`
+	TenantID  string `protobuf:"bytes,3,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+}
+
+func (x *ListResourcesReq) Reset() {
+	*x = ListResourcesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_authz_query_messages_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListResourcesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResourcesReq) ProtoMessage() {}
+
+func (x *ListResourcesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_authz_query_messages_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResourcesReq.ProtoReflect.Descriptor instead.
+func (*ListResourcesReq) Descriptor() ([]byte, []int) {
+	return file_authz_query_messages_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListResourcesReq) GetSubjectID() string {
+	if x != nil {
+		return x.SubjectID
+	}
+	return ""
+}
+
+func (x *ListResourcesReq) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListResourcesReq) GetTenantID() string {
+	if x != nil {
+		return x.TenantID
+	}
+	return ""
+}
+
+type ListResourcesRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubjectID   string   `protobuf:"bytes,1,opt,name=SubjectID,proto3" json:"SubjectID,omitempty"`
+	Action      string   `protobuf:"bytes,2,opt,name=Action,proto3" json:"Action,omitempty"`
+	All         bool     `protobuf:"varint,3,opt,name=All,proto3" json:"All,omitempty"`
+	ResourceIDs []string `protobuf:"bytes,4,rep,name=ResourceIDs,proto3" json:"ResourceIDs,omitempty"`
+}
+
+func (x *ListResourcesRes) Reset() {
+	*x = ListResourcesRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_authz_query_messages_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListResourcesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResourcesRes) ProtoMessage() {}
+
+func (x *ListResourcesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_authz_query_messages_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResourcesRes.ProtoReflect.Descriptor instead.
+func (*ListResourcesRes) Descriptor() ([]byte, []int) {
+	return file_authz_query_messages_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListResourcesRes) GetSubjectID() string {
+	if x != nil {
+		return x.SubjectID
+	}
+	return ""
+}
+
+func (x *ListResourcesRes) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListResourcesRes) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *ListResourcesRes) GetResourceIDs() []string {
+	if x != nil {
+		return x.ResourceIDs
+	}
+	return nil
+}
+
+var File_authz_query_messages_proto protoreflect.FileDescriptor
+
+var file_authz_query_messages_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x61, 0x75,
+	0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x22,
+	0xb0, 0x01, 0x0a, 0x08, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
+	0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x49, 0x44,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x65, 0x64, 0x42,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x65, 0x64,
+	0x42, 0x79, 0x22, 0x7c, 0x0a, 0x08, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x1c,
+	0x0a, 0x09, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x44,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x44,
+	0x22, 0x43, 0x0a, 0x08, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x08,
+	0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x44, 0x65, 0x63,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x60, 0x0a, 0x0d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x33, 0x0a, 0x06, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x52, 0x65, 0x71, 0x52, 0x06, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x54,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x54,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x22, 0x4a, 0x0a, 0x0d, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x09, 0x44, 0x65, 0x63, 0x69,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x61, 0x75,
+	0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x64, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x53, 0x75, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a,
+	0x08, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x22, 0x7c, 0x0a, 0x10, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x6c, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x03, 0x41, 0x6c, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x49, 0x44, 0x73, 0x42, 0x16, 0x5a, 0x14, 0x2e, 0x2f, 0x3b, 0x61, 0x75,
+	0x74, 0x68, 0x7a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_authz_query_messages_proto_rawDescOnce sync.Once
+	file_authz_query_messages_proto_rawDescData = file_authz_query_messages_proto_rawDesc
+)
+
+func file_authz_query_messages_proto_rawDescGZIP() []byte {
+	file_authz_query_messages_proto_rawDescOnce.Do(func() {
+		file_authz_query_messages_proto_rawDescData = protoimpl.X.CompressGZIP(file_authz_query_messages_proto_rawDescData)
+	})
+	return file_authz_query_messages_proto_rawDescData
+}
+
+var file_authz_query_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_authz_query_messages_proto_goTypes = []interface{}{
+	(*Decision)(nil),         // 0: authzQueryService.Decision
+	(*CheckReq)(nil),         // 1: authzQueryService.CheckReq
+	(*CheckRes)(nil),         // 2: authzQueryService.CheckRes
+	(*BatchCheckReq)(nil),    // 3: authzQueryService.BatchCheckReq
+	(*BatchCheckRes)(nil),    // 4: authzQueryService.BatchCheckRes
+	(*ListResourcesReq)(nil), // 5: authzQueryService.ListResourcesReq
+	(*ListResourcesRes)(nil), // 6: authzQueryService.ListResourcesRes
+}
+var file_authz_query_messages_proto_depIdxs = []int32{
+	0, // 0: authzQueryService.CheckRes.Decision:type_name -> authzQueryService.Decision
+	1, // 1: authzQueryService.BatchCheckReq.Checks:type_name -> authzQueryService.CheckReq
+	0, // 2: authzQueryService.BatchCheckRes.Decisions:type_name -> authzQueryService.Decision
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_authz_query_messages_proto_init() }
+func file_authz_query_messages_proto_init() {
+	if File_authz_query_messages_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_authz_query_messages_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Decision); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_authz_query_messages_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_authz_query_messages_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_authz_query_messages_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCheckReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_authz_query_messages_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCheckRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_authz_query_messages_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListResourcesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_authz_query_messages_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListResourcesRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_authz_query_messages_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_authz_query_messages_proto_goTypes,
+		DependencyIndexes: file_authz_query_messages_proto_depIdxs,
+		MessageInfos:      file_authz_query_messages_proto_msgTypes,
+	}.Build()
+	File_authz_query_messages_proto = out.File
+	file_authz_query_messages_proto_rawDesc = nil
+	file_authz_query_messages_proto_goTypes = nil
+	file_authz_query_messages_proto_depIdxs = nil
+}
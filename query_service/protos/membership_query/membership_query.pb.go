@@ -27,7 +27,7 @@ var file_membership_query_proto_rawDesc = []byte{
 	0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
 	0x1a, 0x1f, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x5f, 0x71, 0x75, 0x65,
 	0x72, 0x79, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x32, 0xc4, 0x05, 0x0a, 0x16, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70,
+	0x6f, 0x32, 0xb8, 0x07, 0x0a, 0x16, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70,
 	0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x6c, 0x0a, 0x10,
 	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70,
 	0x12, 0x2b, 0x2e, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65,
@@ -71,24 +71,43 @@ var file_membership_query_proto_rawDesc = []byte{
 	0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71, 0x1a, 0x2c, 0x2e, 0x6d, 0x65, 0x6d,
 	0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76,
 	0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x4d, 0x65, 0x6d, 0x62, 0x65,
-	0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x42, 0x1b, 0x5a, 0x19, 0x2e, 0x2f, 0x3b, 0x6d,
-	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x12, 0x77, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x45,
+	0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x2d,
+	0x2e, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x32, 0x2e,
+	0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x73, 0x52, 0x65,
+	0x73, 0x12, 0x79, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x2e, 0x2e, 0x6d, 0x65, 0x6d, 0x62, 0x65,
+	0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x32, 0x2e, 0x6d, 0x65, 0x6d, 0x62, 0x65,
+	0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x73, 0x52, 0x65, 0x73, 0x42, 0x1b, 0x5a, 0x19,
+	0x2e, 0x2f, 0x3b, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var file_membership_query_proto_goTypes = []interface{}{
-	(*CreateMembershipReq)(nil),     // 0: membershipQueryService.CreateMembershipReq
-	(*UpdateMembershipReq)(nil),     // 1: membershipQueryService.UpdateMembershipReq
-	(*GetMembershipByIdReq)(nil),    // 2: membershipQueryService.GetMembershipByIdReq
-	(*DeleteMembershipByIdReq)(nil), // 3: membershipQueryService.DeleteMembershipByIdReq
-	(*GetGroupMembershipReq)(nil),   // 4: membershipQueryService.GetGroupMembershipReq
-	(*GetUserMembershipReq)(nil),    // 5: membershipQueryService.GetUserMembershipReq
-	(*CreateMembershipRes)(nil),     // 6: membershipQueryService.CreateMembershipRes
-	(*UpdateMembershipRes)(nil),     // 7: membershipQueryService.UpdateMembershipRes
-	(*GetMembershipByIdRes)(nil),    // 8: membershipQueryService.GetMembershipByIdRes
-	(*DeleteMembershipByIdRes)(nil), // 9: membershipQueryService.DeleteMembershipByIdRes
-	(*GetGroupMembershipRes)(nil),   // 10: membershipQueryService.GetGroupMembershipRes
-	(*GetUserMembershipRes)(nil),    // 11: membershipQueryService.GetUserMembershipRes
+	(*CreateMembershipReq)(nil),        // 0: membershipQueryService.CreateMembershipReq
+	(*UpdateMembershipReq)(nil),        // 1: membershipQueryService.UpdateMembershipReq
+	(*GetMembershipByIdReq)(nil),       // 2: membershipQueryService.GetMembershipByIdReq
+	(*DeleteMembershipByIdReq)(nil),    // 3: membershipQueryService.DeleteMembershipByIdReq
+	(*GetGroupMembershipReq)(nil),      // 4: membershipQueryService.GetGroupMembershipReq
+	(*GetUserMembershipReq)(nil),       // 5: membershipQueryService.GetUserMembershipReq
+	(*GetEffectiveGroupsReq)(nil),      // 6: membershipQueryService.GetEffectiveGroupsReq
+	(*GetEffectiveMembersReq)(nil),     // 7: membershipQueryService.GetEffectiveMembersReq
+	(*CreateMembershipRes)(nil),        // 8: membershipQueryService.CreateMembershipRes
+	(*UpdateMembershipRes)(nil),        // 9: membershipQueryService.UpdateMembershipRes
+	(*GetMembershipByIdRes)(nil),       // 10: membershipQueryService.GetMembershipByIdRes
+	(*DeleteMembershipByIdRes)(nil),    // 11: membershipQueryService.DeleteMembershipByIdRes
+	(*GetGroupMembershipRes)(nil),      // 12: membershipQueryService.GetGroupMembershipRes
+	(*GetUserMembershipRes)(nil),       // 13: membershipQueryService.GetUserMembershipRes
+	(*GetEffectiveMembershipsRes)(nil), // 14: membershipQueryService.GetEffectiveMembershipsRes
 }
 var file_membership_query_proto_depIdxs = []int32{
 	0,  // 0: membershipQueryService.membershipQueryService.CreateMembership:input_type -> membershipQueryService.CreateMembershipReq
@@ -97,14 +116,18 @@ var file_membership_query_proto_depIdxs = []int32{
 	3,  // 3: membershipQueryService.membershipQueryService.DeleteMembershipByID:input_type -> membershipQueryService.DeleteMembershipByIdReq
 	4,  // 4: membershipQueryService.membershipQueryService.GetGroupMembership:input_type -> membershipQueryService.GetGroupMembershipReq
 	5,  // 5: membershipQueryService.membershipQueryService.GetUserMembership:input_type -> membershipQueryService.GetUserMembershipReq
-	6,  // 6: membershipQueryService.membershipQueryService.CreateMembership:output_type -> membershipQueryService.CreateMembershipRes
-	7,  // 7: membershipQueryService.membershipQueryService.UpdateMembership:output_type -> membershipQueryService.UpdateMembershipRes
-	8,  // 8: membershipQueryService.membershipQueryService.GetMembershipById:output_type -> membershipQueryService.GetMembershipByIdRes
-	9,  // 9: membershipQueryService.membershipQueryService.DeleteMembershipByID:output_type -> membershipQueryService.DeleteMembershipByIdRes
-	10, // 10: membershipQueryService.membershipQueryService.GetGroupMembership:output_type -> membershipQueryService.GetGroupMembershipRes
-	11, // 11: membershipQueryService.membershipQueryService.GetUserMembership:output_type -> membershipQueryService.GetUserMembershipRes
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
+	6,  // 6: membershipQueryService.membershipQueryService.GetEffectiveGroups:input_type -> membershipQueryService.GetEffectiveGroupsReq
+	7,  // 7: membershipQueryService.membershipQueryService.GetEffectiveMembers:input_type -> membershipQueryService.GetEffectiveMembersReq
+	8,  // 8: membershipQueryService.membershipQueryService.CreateMembership:output_type -> membershipQueryService.CreateMembershipRes
+	9,  // 9: membershipQueryService.membershipQueryService.UpdateMembership:output_type -> membershipQueryService.UpdateMembershipRes
+	10, // 10: membershipQueryService.membershipQueryService.GetMembershipById:output_type -> membershipQueryService.GetMembershipByIdRes
+	11, // 11: membershipQueryService.membershipQueryService.DeleteMembershipByID:output_type -> membershipQueryService.DeleteMembershipByIdRes
+	12, // 12: membershipQueryService.membershipQueryService.GetGroupMembership:output_type -> membershipQueryService.GetGroupMembershipRes
+	13, // 13: membershipQueryService.membershipQueryService.GetUserMembership:output_type -> membershipQueryService.GetUserMembershipRes
+	14, // 14: membershipQueryService.membershipQueryService.GetEffectiveGroups:output_type -> membershipQueryService.GetEffectiveMembershipsRes
+	14, // 15: membershipQueryService.membershipQueryService.GetEffectiveMembers:output_type -> membershipQueryService.GetEffectiveMembershipsRes
+	8,  // [8:16] is the sub-list for method output_type
+	0,  // [0:8] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -28,6 +28,8 @@ type MembershipQueryServiceClient interface {
 	DeleteMembershipByID(ctx context.Context, in *DeleteMembershipByIdReq, opts ...grpc.CallOption) (*DeleteMembershipByIdRes, error)
 	GetGroupMembership(ctx context.Context, in *GetGroupMembershipReq, opts ...grpc.CallOption) (*GetGroupMembershipRes, error)
 	GetUserMembership(ctx context.Context, in *GetUserMembershipReq, opts ...grpc.CallOption) (*GetUserMembershipRes, error)
+	GetEffectiveGroups(ctx context.Context, in *GetEffectiveGroupsReq, opts ...grpc.CallOption) (*GetEffectiveMembershipsRes, error)
+	GetEffectiveMembers(ctx context.Context, in *GetEffectiveMembersReq, opts ...grpc.CallOption) (*GetEffectiveMembershipsRes, error)
 }
 
 type membershipQueryServiceClient struct {
@@ -92,6 +94,24 @@ func (c *membershipQueryServiceClient) GetUserMembership(ctx context.Context, in
 	return out, nil
 }
 
+func (c *membershipQueryServiceClient) GetEffectiveGroups(ctx context.Context, in *GetEffectiveGroupsReq, opts ...grpc.CallOption) (*GetEffectiveMembershipsRes, error) {
+	out := new(GetEffectiveMembershipsRes)
+	err := c.cc.Invoke(ctx, "/membershipQueryService.membershipQueryService/GetEffectiveGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *membershipQueryServiceClient) GetEffectiveMembers(ctx context.Context, in *GetEffectiveMembersReq, opts ...grpc.CallOption) (*GetEffectiveMembershipsRes, error) {
+	out := new(GetEffectiveMembershipsRes)
+	err := c.cc.Invoke(ctx, "/membershipQueryService.membershipQueryService/GetEffectiveMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MembershipQueryServiceServer is the server API for MembershipQueryService service.
 // All implementations should embed UnimplementedMembershipQueryServiceServer
 // for forward compatibility
@@ -102,6 +122,8 @@ type MembershipQueryServiceServer interface {
 	DeleteMembershipByID(context.Context, *DeleteMembershipByIdReq) (*DeleteMembershipByIdRes, error)
 	GetGroupMembership(context.Context, *GetGroupMembershipReq) (*GetGroupMembershipRes, error)
 	GetUserMembership(context.Context, *GetUserMembershipReq) (*GetUserMembershipRes, error)
+	GetEffectiveGroups(context.Context, *GetEffectiveGroupsReq) (*GetEffectiveMembershipsRes, error)
+	GetEffectiveMembers(context.Context, *GetEffectiveMembersReq) (*GetEffectiveMembershipsRes, error)
 }
 
 // UnimplementedMembershipQueryServiceServer should be embedded to have forward compatible implementations.
@@ -126,6 +148,12 @@ func (UnimplementedMembershipQueryServiceServer) GetGroupMembership(context.Cont
 func (UnimplementedMembershipQueryServiceServer) GetUserMembership(context.Context, *GetUserMembershipReq) (*GetUserMembershipRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserMembership not implemented")
 }
+func (UnimplementedMembershipQueryServiceServer) GetEffectiveGroups(context.Context, *GetEffectiveGroupsReq) (*GetEffectiveMembershipsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEffectiveGroups not implemented")
+}
+func (UnimplementedMembershipQueryServiceServer) GetEffectiveMembers(context.Context, *GetEffectiveMembersReq) (*GetEffectiveMembershipsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEffectiveMembers not implemented")
+}
 
 // UnsafeMembershipQueryServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to MembershipQueryServiceServer will
@@ -246,6 +274,42 @@ func _MembershipQueryService_GetUserMembership_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MembershipQueryService_GetEffectiveGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEffectiveGroupsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MembershipQueryServiceServer).GetEffectiveGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/membershipQueryService.membershipQueryService/GetEffectiveGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MembershipQueryServiceServer).GetEffectiveGroups(ctx, req.(*GetEffectiveGroupsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MembershipQueryService_GetEffectiveMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEffectiveMembersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MembershipQueryServiceServer).GetEffectiveMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/membershipQueryService.membershipQueryService/GetEffectiveMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MembershipQueryServiceServer).GetEffectiveMembers(ctx, req.(*GetEffectiveMembersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MembershipQueryService_ServiceDesc is the grpc.ServiceDesc for MembershipQueryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -277,6 +341,14 @@ var MembershipQueryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserMembership",
 			Handler:    _MembershipQueryService_GetUserMembership_Handler,
 		},
+		{
+			MethodName: "GetEffectiveGroups",
+			Handler:    _MembershipQueryService_GetEffectiveGroups_Handler,
+		},
+		{
+			MethodName: "GetEffectiveMembers",
+			Handler:    _MembershipQueryService_GetEffectiveMembers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "membership_query.proto",
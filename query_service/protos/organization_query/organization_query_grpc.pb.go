@@ -0,0 +1,139 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: organization_query.proto
+
+package organizationQueryService
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// OrganizationQueryServiceClient is the client API for OrganizationQueryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrganizationQueryServiceClient interface {
+	GetOrganizationById(ctx context.Context, in *GetOrganizationByIdReq, opts ...grpc.CallOption) (*GetOrganizationByIdRes, error)
+	SearchOrganization(ctx context.Context, in *SearchOrganizationReq, opts ...grpc.CallOption) (*SearchOrganizationRes, error)
+}
+
+type organizationQueryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrganizationQueryServiceClient(cc grpc.ClientConnInterface) OrganizationQueryServiceClient {
+	return &organizationQueryServiceClient{cc}
+}
+
+func (c *organizationQueryServiceClient) GetOrganizationById(ctx context.Context, in *GetOrganizationByIdReq, opts ...grpc.CallOption) (*GetOrganizationByIdRes, error) {
+	out := new(GetOrganizationByIdRes)
+	err := c.cc.Invoke(ctx, "/organizationQueryService.organizationQueryService/GetOrganizationById", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *organizationQueryServiceClient) SearchOrganization(ctx context.Context, in *SearchOrganizationReq, opts ...grpc.CallOption) (*SearchOrganizationRes, error) {
+	out := new(SearchOrganizationRes)
+	err := c.cc.Invoke(ctx, "/organizationQueryService.organizationQueryService/SearchOrganization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrganizationQueryServiceServer is the server API for OrganizationQueryService service.
+// All implementations should embed UnimplementedOrganizationQueryServiceServer
+// for forward compatibility
+type OrganizationQueryServiceServer interface {
+	GetOrganizationById(context.Context, *GetOrganizationByIdReq) (*GetOrganizationByIdRes, error)
+	SearchOrganization(context.Context, *SearchOrganizationReq) (*SearchOrganizationRes, error)
+}
+
+// UnimplementedOrganizationQueryServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedOrganizationQueryServiceServer struct {
+}
+
+func (UnimplementedOrganizationQueryServiceServer) GetOrganizationById(context.Context, *GetOrganizationByIdReq) (*GetOrganizationByIdRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrganizationById not implemented")
+}
+func (UnimplementedOrganizationQueryServiceServer) SearchOrganization(context.Context, *SearchOrganizationReq) (*SearchOrganizationRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchOrganization not implemented")
+}
+
+// UnsafeOrganizationQueryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrganizationQueryServiceServer will
+// result in compilation errors.
+type UnsafeOrganizationQueryServiceServer interface {
+	mustEmbedUnimplementedOrganizationQueryServiceServer()
+}
+
+func RegisterOrganizationQueryServiceServer(s grpc.ServiceRegistrar, srv OrganizationQueryServiceServer) {
+	s.RegisterService(&OrganizationQueryService_ServiceDesc, srv)
+}
+
+func _OrganizationQueryService_GetOrganizationById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrganizationByIdReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrganizationQueryServiceServer).GetOrganizationById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/organizationQueryService.organizationQueryService/GetOrganizationById",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrganizationQueryServiceServer).GetOrganizationById(ctx, req.(*GetOrganizationByIdReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrganizationQueryService_SearchOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchOrganizationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrganizationQueryServiceServer).SearchOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/organizationQueryService.organizationQueryService/SearchOrganization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrganizationQueryServiceServer).SearchOrganization(ctx, req.(*SearchOrganizationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrganizationQueryService_ServiceDesc is the grpc.ServiceDesc for OrganizationQueryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrganizationQueryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "organizationQueryService.organizationQueryService",
+	HandlerType: (*OrganizationQueryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrganizationById",
+			Handler:    _OrganizationQueryService_GetOrganizationById_Handler,
+		},
+		{
+			MethodName: "SearchOrganization",
+			Handler:    _OrganizationQueryService_SearchOrganization_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "organization_query.proto",
+}
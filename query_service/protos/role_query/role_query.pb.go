@@ -0,0 +1,86 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: role_query.proto
+
+package roleQueryService
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_role_query_proto protoreflect.FileDescriptor
+
+var file_role_query_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x72, 0x6f, 0x6c, 0x65, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x10, 0x72, 0x6f, 0x6c, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x1a, 0x19, 0x72, 0x6f, 0x6c, 0x65, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79,
+	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32,
+	0xb8, 0x01, 0x0a, 0x10, 0x72, 0x6f, 0x6c, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x42,
+	0x79, 0x49, 0x64, 0x12, 0x20, 0x2e, 0x72, 0x6f, 0x6c, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x79,
+	0x49, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x20, 0x2e, 0x72, 0x6f, 0x6c, 0x65, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x6c, 0x65,
+	0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x73, 0x12, 0x51, 0x0a, 0x0b, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x20, 0x2e, 0x72, 0x6f, 0x6c, 0x65, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x20, 0x2e, 0x72, 0x6f, 0x6c, 0x65, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x42, 0x15, 0x5a, 0x13, 0x2e, 0x2f,
+	0x3b, 0x72, 0x6f, 0x6c, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_role_query_proto_goTypes = []interface{}{
+	(*GetRoleByIdReq)(nil), // 0: roleQueryService.GetRoleByIdReq
+	(*SearchRolesReq)(nil), // 1: roleQueryService.SearchRolesReq
+	(*GetRoleByIdRes)(nil), // 2: roleQueryService.GetRoleByIdRes
+	(*SearchRolesRes)(nil), // 3: roleQueryService.SearchRolesRes
+}
+var file_role_query_proto_depIdxs = []int32{
+	0, // 0: roleQueryService.roleQueryService.GetRoleById:input_type -> roleQueryService.GetRoleByIdReq
+	1, // 1: roleQueryService.roleQueryService.SearchRoles:input_type -> roleQueryService.SearchRolesReq
+	2, // 2: roleQueryService.roleQueryService.GetRoleById:output_type -> roleQueryService.GetRoleByIdRes
+	3, // 3: roleQueryService.roleQueryService.SearchRoles:output_type -> roleQueryService.SearchRolesRes
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_role_query_proto_init() }
+func file_role_query_proto_init() {
+	if File_role_query_proto != nil {
+		return
+	}
+	file_role_query_messages_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_role_query_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_role_query_proto_goTypes,
+		DependencyIndexes: file_role_query_proto_depIdxs,
+	}.Build()
+	File_role_query_proto = out.File
+	file_role_query_proto_rawDesc = nil
+	file_role_query_proto_goTypes = nil
+	file_role_query_proto_depIdxs = nil
+}
@@ -0,0 +1,139 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: role_query.proto
+
+package roleQueryService
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RoleQueryServiceClient is the client API for RoleQueryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RoleQueryServiceClient interface {
+	GetRoleById(ctx context.Context, in *GetRoleByIdReq, opts ...grpc.CallOption) (*GetRoleByIdRes, error)
+	SearchRoles(ctx context.Context, in *SearchRolesReq, opts ...grpc.CallOption) (*SearchRolesRes, error)
+}
+
+type roleQueryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRoleQueryServiceClient(cc grpc.ClientConnInterface) RoleQueryServiceClient {
+	return &roleQueryServiceClient{cc}
+}
+
+func (c *roleQueryServiceClient) GetRoleById(ctx context.Context, in *GetRoleByIdReq, opts ...grpc.CallOption) (*GetRoleByIdRes, error) {
+	out := new(GetRoleByIdRes)
+	err := c.cc.Invoke(ctx, "/roleQueryService.roleQueryService/GetRoleById", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleQueryServiceClient) SearchRoles(ctx context.Context, in *SearchRolesReq, opts ...grpc.CallOption) (*SearchRolesRes, error) {
+	out := new(SearchRolesRes)
+	err := c.cc.Invoke(ctx, "/roleQueryService.roleQueryService/SearchRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RoleQueryServiceServer is the server API for RoleQueryService service.
+// All implementations should embed UnimplementedRoleQueryServiceServer
+// for forward compatibility
+type RoleQueryServiceServer interface {
+	GetRoleById(context.Context, *GetRoleByIdReq) (*GetRoleByIdRes, error)
+	SearchRoles(context.Context, *SearchRolesReq) (*SearchRolesRes, error)
+}
+
+// UnimplementedRoleQueryServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedRoleQueryServiceServer struct {
+}
+
+func (UnimplementedRoleQueryServiceServer) GetRoleById(context.Context, *GetRoleByIdReq) (*GetRoleByIdRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoleById not implemented")
+}
+func (UnimplementedRoleQueryServiceServer) SearchRoles(context.Context, *SearchRolesReq) (*SearchRolesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchRoles not implemented")
+}
+
+// UnsafeRoleQueryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RoleQueryServiceServer will
+// result in compilation errors.
+type UnsafeRoleQueryServiceServer interface {
+	mustEmbedUnimplementedRoleQueryServiceServer()
+}
+
+func RegisterRoleQueryServiceServer(s grpc.ServiceRegistrar, srv RoleQueryServiceServer) {
+	s.RegisterService(&RoleQueryService_ServiceDesc, srv)
+}
+
+func _RoleQueryService_GetRoleById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoleByIdReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleQueryServiceServer).GetRoleById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roleQueryService.roleQueryService/GetRoleById",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleQueryServiceServer).GetRoleById(ctx, req.(*GetRoleByIdReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleQueryService_SearchRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRolesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleQueryServiceServer).SearchRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roleQueryService.roleQueryService/SearchRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleQueryServiceServer).SearchRoles(ctx, req.(*SearchRolesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RoleQueryService_ServiceDesc is the grpc.ServiceDesc for RoleQueryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RoleQueryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "roleQueryService.roleQueryService",
+	HandlerType: (*RoleQueryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRoleById",
+			Handler:    _RoleQueryService_GetRoleById_Handler,
+		},
+		{
+			MethodName: "SearchRoles",
+			Handler:    _RoleQueryService_SearchRoles_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "role_query.proto",
+}
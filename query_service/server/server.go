@@ -3,11 +3,14 @@ package server
 import (
 	"context"
 	"github.com/JECSand/identity-service/pkg/authentication"
+	"github.com/JECSand/identity-service/pkg/certs"
 	"github.com/JECSand/identity-service/pkg/constants"
 	"github.com/JECSand/identity-service/pkg/interceptors"
 	kafkaClient "github.com/JECSand/identity-service/pkg/kafka"
 	"github.com/JECSand/identity-service/pkg/logging"
+	"github.com/JECSand/identity-service/pkg/messaging"
 	"github.com/JECSand/identity-service/pkg/mongodb"
+	"github.com/JECSand/identity-service/pkg/probes"
 	redisClient "github.com/JECSand/identity-service/pkg/redis"
 	"github.com/JECSand/identity-service/pkg/tracing"
 	"github.com/JECSand/identity-service/query_service/config"
@@ -15,32 +18,33 @@ import (
 	"github.com/JECSand/identity-service/query_service/identity/data"
 	grpc2 "github.com/JECSand/identity-service/query_service/identity/delivery/grpc"
 	queryKafka "github.com/JECSand/identity-service/query_service/identity/delivery/kafka"
+	"github.com/JECSand/identity-service/query_service/identity/events"
 	"github.com/JECSand/identity-service/query_service/identity/metrics"
 	"github.com/JECSand/identity-service/query_service/identity/services"
 	authQueryService "github.com/JECSand/identity-service/query_service/protos/auth_query"
+	authzQueryService "github.com/JECSand/identity-service/query_service/protos/authz_query"
 	groupQueryService "github.com/JECSand/identity-service/query_service/protos/group_query"
 	membershipQueryService "github.com/JECSand/identity-service/query_service/protos/membership_query"
+	organizationQueryService "github.com/JECSand/identity-service/query_service/protos/organization_query"
+	roleQueryService "github.com/JECSand/identity-service/query_service/protos/role_query"
 	queryService "github.com/JECSand/identity-service/query_service/protos/user_query"
 	"github.com/go-playground/validator"
 	"github.com/go-redis/redis/v8"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
-	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
-	"github.com/heptiolabs/healthcheck"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/segmentio/kafka-go"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 	"net"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -68,23 +72,32 @@ type server struct {
 	as          *services.AuthService
 	gs          *services.GroupService
 	ms          *services.MembershipService
+	os          *services.OrganizationService
+	rs          *services.RoleService
+	zs          *services.AuthzService
 	metrics     *metrics.QueryServiceMetrics
+	health      *probes.Health
+	lag         *messaging.LagMonitor
 }
 
 func NewServer(log logging.Logger, cfg *config.Config) *server {
 	return &server{
-		log: log,
-		cfg: cfg,
-		v:   validator.New(),
+		log:     log,
+		cfg:     cfg,
+		v:       validator.New(),
+		metrics: metrics.NewQueryServiceMetrics(cfg),
+		health:  probes.NewHealth(log, &cfg.Probes),
 	}
 }
 
-func (s *server) newReaderGrpcServer() (func() error, *grpc.Server, error) {
-	l, err := net.Listen("tcp", s.cfg.GRPC.Port)
+func (s *server) newReaderGrpcServer(ctx context.Context, l net.Listener) (*grpc.Server, error) {
+	creds, err := certs.ServerOption(ctx, s.log, &s.cfg.GRPC.TLS)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "net.Listen")
+		return nil, errors.Wrap(err, "certs.ServerOption")
 	}
 	grpcServer := grpc.NewServer(
+		creds,
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.KeepaliveParams(keepalive.ServerParameters{
 			MaxConnectionIdle: maxConnectionIdle * time.Minute,
 			Timeout:           gRPCTimeout * time.Second,
@@ -93,12 +106,14 @@ func (s *server) newReaderGrpcServer() (func() error, *grpc.Server, error) {
 		}),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			grpc_ctxtags.UnaryServerInterceptor(),
-			grpc_opentracing.UnaryServerInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
 			grpc_recovery.UnaryServerInterceptor(),
 			s.im.Logger,
+			s.im.ServiceAuthUnaryInterceptor,
 		)),
+		grpc.StreamInterceptor(s.im.ServiceAuthStreamInterceptor),
 	)
+	s.health.RegisterGrpc(ctx, grpcServer)
 	queryGrpcService := grpc2.NewQueryGrpcService(s.log, s.cfg, s.v, s.us, s.as, s.metrics)
 	queryService.RegisterQueryServiceServer(grpcServer, queryGrpcService)
 	authQueryGrpcService := grpc2.NewAuthQueryGrpcService(s.log, s.cfg, s.v, s.us, s.as, s.metrics)
@@ -107,15 +122,23 @@ func (s *server) newReaderGrpcServer() (func() error, *grpc.Server, error) {
 	groupQueryService.RegisterGroupQueryServiceServer(grpcServer, groupQueryGrpcService)
 	membershipQueryGrpcService := grpc2.NewMembershipQueryGrpcService(s.log, s.cfg, s.v, s.ms, s.metrics)
 	membershipQueryService.RegisterMembershipQueryServiceServer(grpcServer, membershipQueryGrpcService)
+	organizationQueryGrpcService := grpc2.NewOrganizationQueryGrpcService(s.log, s.cfg, s.v, s.os, s.metrics)
+	organizationQueryService.RegisterOrganizationQueryServiceServer(grpcServer, organizationQueryGrpcService)
+	roleQueryGrpcService := grpc2.NewRoleQueryGrpcService(s.log, s.cfg, s.v, s.rs, s.metrics)
+	roleQueryService.RegisterRoleQueryServiceServer(grpcServer, roleQueryGrpcService)
+	authzQueryGrpcService := grpc2.NewAuthzQueryGrpcService(s.log, s.cfg, s.v, s.zs, s.metrics)
+	authzQueryService.RegisterAuthzQueryServiceServer(grpcServer, authzQueryGrpcService)
 	grpc_prometheus.Register(grpcServer)
 	if s.cfg.GRPC.Development {
 		reflection.Register(grpcServer)
 	}
 	go func() {
-		s.log.Infof("Query gRPC server is listening on port: %s", s.cfg.GRPC.Port)
-		s.log.Fatal(grpcServer.Serve(l))
+		s.log.Infof("Query gRPC server is listening on: %s", l.Addr())
+		if err := grpcServer.Serve(l); err != nil {
+			s.log.Fatal(err)
+		}
 	}()
-	return l.Close, grpcServer, nil
+	return grpcServer, nil
 }
 
 func (s *server) connectKafkaBrokers(ctx context.Context) error {
@@ -137,41 +160,42 @@ func (s *server) getConsumerGroupTopics() []string {
 		s.cfg.KafkaTopics.UserCreated.TopicName,
 		s.cfg.KafkaTopics.UserUpdated.TopicName,
 		s.cfg.KafkaTopics.UserDeleted.TopicName,
+		s.cfg.KafkaTopics.UserStatusChanged.TopicName,
 		s.cfg.KafkaTopics.GroupCreated.TopicName,
 		s.cfg.KafkaTopics.GroupUpdated.TopicName,
 		s.cfg.KafkaTopics.GroupDeleted.TopicName,
+		s.cfg.KafkaTopics.GroupParentChanged.TopicName,
 		s.cfg.KafkaTopics.MembershipCreated.TopicName,
 		s.cfg.KafkaTopics.MembershipUpdated.TopicName,
 		s.cfg.KafkaTopics.MembershipDeleted.TopicName,
+		s.cfg.KafkaTopics.OrganizationCreated.TopicName,
+		s.cfg.KafkaTopics.OrganizationUpdated.TopicName,
+		s.cfg.KafkaTopics.OrganizationStatusChanged.TopicName,
+		s.cfg.KafkaTopics.OrganizationDeleted.TopicName,
+		s.cfg.KafkaTopics.RoleCreated.TopicName,
+		s.cfg.KafkaTopics.RoleUpdated.TopicName,
+		s.cfg.KafkaTopics.RoleDeleted.TopicName,
 		s.cfg.KafkaTopics.TokenBlacklisted.TopicName,
 		s.cfg.KafkaTopics.PasswordUpdated.TopicName,
 	}
 }
 
-func (s *server) runHealthCheck(ctx context.Context) {
-	health := healthcheck.NewHandler()
-	health.AddLivenessCheck(s.cfg.ServiceName, healthcheck.AsyncWithContext(ctx, func() error {
+func (s *server) runHealthCheck(ctx context.Context) func() error {
+	s.health.AddLivenessCheck(ctx, s.cfg.ServiceName, func(context.Context) error {
 		return nil
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	health.AddReadinessCheck(constants.Redis, healthcheck.AsyncWithContext(ctx, func() error {
+	})
+	s.health.AddReadinessCheck(ctx, constants.Redis, func(ctx context.Context) error {
 		return s.redisClient.Ping(ctx).Err()
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	health.AddReadinessCheck(constants.MongoDB, healthcheck.AsyncWithContext(ctx, func() error {
+	})
+	s.health.AddReadinessCheck(ctx, constants.MongoDB, func(ctx context.Context) error {
 		return s.mongoClient.Ping(ctx, nil)
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	health.AddReadinessCheck(constants.Kafka, healthcheck.AsyncWithContext(ctx, func() error {
+	})
+	s.health.AddReadinessCheck(ctx, constants.Kafka, func(context.Context) error {
 		_, err := s.kafkaConn.Brokers()
-		if err != nil {
-			return err
-		}
-		return nil
-	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
-	go func() {
-		s.log.Infof("Query microservice Kubernetes probes listening on port: %s", s.cfg.Probes.Port)
-		if err := http.ListenAndServe(s.cfg.Probes.Port, health); err != nil {
-			s.log.WarnMsg("ListenAndServe", err)
-		}
-	}()
+		return err
+	})
+	s.health.AddReadinessCheck(ctx, constants.ConsumerLag, probes.LagCheck(s.lag, s.cfg.Probes.MaxConsumerLag))
+	return s.health.Serve()
 }
 
 func (s *server) runMetrics(cancel context.CancelFunc) {
@@ -191,11 +215,41 @@ func (s *server) runMetrics(cancel context.CancelFunc) {
 	}()
 }
 
+// Start wires the services over the given stores, consumes the event topics from sub and serves the query gRPC
+// API on l until the returned stop func is called or ctx is done
+func (s *server) Start(ctx context.Context, db data.Database, c cache.Cache, sub messaging.Subscriber, l net.Listener) (func() error, error) {
+	serviceAuth, err := authentication.NewServiceAuthenticator(s.log, &s.cfg.ServiceAuth, grpc2.ServiceAccessRules())
+	if err != nil {
+		return nil, errors.Wrap(err, "authentication.NewServiceAuthenticator")
+	}
+	s.im = interceptors.NewInterceptorManager(s.log, s.auth, serviceAuth)
+	s.us = services.NewUserService(s.log, s.cfg, db, c)
+	s.as = services.NewAuthService(s.log, s.cfg, db, c)
+	s.gs = services.NewGroupService(s.log, s.cfg, db, c)
+	s.ms = services.NewMembershipService(s.log, s.cfg, db, c)
+	s.os = services.NewOrganizationService(s.log, s.cfg, db, c)
+	s.rs = services.NewRoleService(s.log, s.cfg, db, c)
+	s.zs = services.NewAuthzService(s.log, s.cfg, db)
+	if err := events.SeedBuiltInRoles(ctx, db); err != nil {
+		return nil, errors.Wrap(err, "SeedBuiltInRoles")
+	}
+	readerMessageProcessor := queryKafka.NewQueryMessageProcessor(s.log, s.cfg, s.v, s.us, s.gs, s.ms, s.as, s.os, s.rs, s.metrics)
+	s.log.Info("Starting Reader consumers")
+	s.lag = messaging.NewLagMonitor(sub)
+	go messaging.ConsumeTopics(ctx, s.log, s.lag, s.cfg.Kafka.GroupID, s.getConsumerGroupTopics(), queryKafka.PoolSize, readerMessageProcessor.ProcessMessages)
+	grpcServer, err := s.newReaderGrpcServer(ctx, l)
+	if err != nil {
+		return nil, errors.Wrap(err, "newReaderGrpcServer")
+	}
+	return func() error {
+		grpcServer.GracefulStop()
+		return nil
+	}, nil
+}
+
 func (s *server) Run() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
-	s.im = interceptors.NewInterceptorManager(s.log, s.auth)
-	s.metrics = metrics.NewQueryServiceMetrics(s.cfg)
 	mongoDBConn, err := mongodb.NewMongoDBConn(ctx, s.cfg.Mongo)
 	if err != nil {
 		return errors.Wrap(err, "NewMongoDBConn")
@@ -207,35 +261,50 @@ func (s *server) Run() error {
 	defer s.redisClient.Close() // nolint: errCheck
 	s.log.Infof("Redis connected: %+v", s.redisClient.PoolStats())
 	dbRepo := data.NewDatabase(s.log, s.cfg, s.mongoClient)
-	redisRepo := cache.NewRedisCache(s.log, s.cfg, s.redisClient)
-	s.us = services.NewUserService(s.log, s.cfg, dbRepo, redisRepo)
-	s.as = services.NewAuthService(s.log, s.cfg, dbRepo, redisRepo)
-	s.gs = services.NewGroupService(s.log, s.cfg, dbRepo, redisRepo)
-	s.ms = services.NewMembershipService(s.log, s.cfg, dbRepo, redisRepo)
-	readerMessageProcessor := queryKafka.NewQueryMessageProcessor(s.log, s.cfg, s.v, s.us, s.gs, s.ms, s.as, s.metrics)
-	s.log.Info("Starting Reader Kafka consumers")
-	cg := kafkaClient.NewConsumerGroup(s.cfg.Kafka.Brokers, s.cfg.Kafka.GroupID, s.log)
-	go cg.ConsumeTopic(ctx, s.getConsumerGroupTopics(), queryKafka.PoolSize, readerMessageProcessor.ProcessMessages)
+	redisCache := cache.NewRedisCache(s.log, s.cfg, s.redisClient, s.metrics)
+	// users projected while password hashes were replicated into the read model still carry them. The cached users
+	// are purged on every start, as a start that stripped the hashes may have failed before purging the cache
+	stripped, err := dbRepo.StripPasswordHashes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "dbRepo.StripPasswordHashes")
+	}
+	purged, err := redisCache.PurgeUsers(ctx)
+	if err != nil {
+		return errors.Wrap(err, "redisCache.PurgeUsers")
+	}
+	if stripped > 0 || purged > 0 {
+		s.log.Infof("Stripped password hashes from %d users, purged %d cached users", stripped, purged)
+	}
+	redisRepo := cache.NewTieredCache(s.log, s.cfg, redisCache, s.redisClient, s.metrics)
+	go redisRepo.Subscribe(ctx)
+	subscriber, err := kafkaClient.NewSubscriber(s.log, s.cfg.Kafka)
+	if err != nil {
+		return errors.Wrap(err, "kafka.NewSubscriber")
+	}
 	if err = s.connectKafkaBrokers(ctx); err != nil {
 		return errors.Wrap(err, "s.connectKafkaBrokers")
 	}
 	defer s.kafkaConn.Close() // nolint: errCheck
-	s.runHealthCheck(ctx)
 	s.runMetrics(cancel)
-	if s.cfg.Jaeger.Enable {
-		tracer, closer, err := tracing.NewJaegerTracer(s.cfg.Jaeger)
-		if err != nil {
-			return err
-		}
-		defer closer.Close() // nolint: errCheck
-		opentracing.SetGlobalTracer(tracer)
+	stopPprof := probes.ServePprof(s.log, &s.cfg.Probes)
+	defer stopPprof() // nolint: errCheck
+	stopTracing, err := tracing.NewTracerProvider(ctx, s.cfg.Tracing)
+	if err != nil {
+		return errors.Wrap(err, "tracing.NewTracerProvider")
 	}
-	closeGrpcServer, grpcServer, err := s.newReaderGrpcServer()
+	defer stopTracing() // nolint: errCheck
+	l, err := net.Listen("tcp", s.cfg.GRPC.Port)
 	if err != nil {
-		return errors.Wrap(err, "NewScmGrpcServer")
+		return errors.Wrap(err, "net.Listen")
 	}
-	defer closeGrpcServer() // nolint: errCheck
+	stop, err := s.Start(ctx, dbRepo, redisRepo, subscriber, l)
+	if err != nil {
+		l.Close() // nolint: errCheck
+		return errors.Wrap(err, "Start")
+	}
+	stopProbes := s.runHealthCheck(ctx)
+	defer stopProbes() // nolint: errCheck
 	<-ctx.Done()
-	grpcServer.GracefulStop()
-	return nil
+	s.health.Drain(context.Background())
+	return stop()
 }
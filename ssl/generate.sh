@@ -30,4 +30,15 @@ openssl req -passin pass:1111 -new -key server.key -out server.csr -subj "/CN=${
 openssl x509 -req -passin pass:1111 -days 3650 -in server.csr -CA ca.crt -CAkey ca.key -set_serial 01 -out server.crt
 
 # Step 5: Convert the server certificate to .pem format (server.pem) - usable by grpc
-openssl pkcs8 -topk8 -nocrypt -passin pass:1111 -in server.key -out server.pem
\ No newline at end of file
+openssl pkcs8 -topk8 -nocrypt -passin pass:1111 -in server.key -out server.pem
+
+# Step 6: Generate a key pair per service, signed by the CA, with the service name as its SAN.
+# These are used for mutual TLS between the gateway, command_service and query_service.
+for SERVICE in api-gateway-service command-service query-service; do
+  openssl genrsa -out ${SERVICE}.key 4096
+  openssl req -new -key ${SERVICE}.key -out ${SERVICE}.csr -subj "/CN=${SERVICE}"
+  printf "subjectAltName=DNS:${SERVICE},DNS:localhost\nextendedKeyUsage=serverAuth,clientAuth\n" > ${SERVICE}.ext
+  openssl x509 -req -passin pass:1111 -days 365 -in ${SERVICE}.csr -CA ca.crt -CAkey ca.key -CAcreateserial -extfile ${SERVICE}.ext -out ${SERVICE}.crt
+  openssl pkcs8 -topk8 -nocrypt -in ${SERVICE}.key -out ${SERVICE}.pem
+  rm ${SERVICE}.csr ${SERVICE}.ext
+done